@@ -0,0 +1,88 @@
+// Package main provides the Diktator MCP server.
+//
+// It speaks the Model Context Protocol over stdio so AI assistants can manage a
+// single family's word sets and review progress. The family is selected by a
+// family API key created by a parent via POST /api/families/api-keys.
+//
+// Configuration is loaded like the API server's (see internal/config); the
+// MCP server uses the database and logging sections and requires
+// DIKTATOR_API_KEY (mcp.api_key) to be set.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/starefossen/diktator/backend/internal/config"
+	"github.com/starefossen/diktator/backend/internal/logging"
+	"github.com/starefossen/diktator/backend/internal/mcpserver"
+	"github.com/starefossen/diktator/backend/internal/services/db"
+)
+
+// version is overridden at build time with -ldflags "-X main.version=..."
+var version = "dev"
+
+func main() {
+	// stdout carries the MCP protocol, so all logging goes to stderr, which is
+	// both the slog default and the logging package's default writer
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		slog.Error("Failed to load configuration", "error", err)
+		os.Exit(1)
+	}
+
+	logger := logging.New(logging.Config{
+		Format: cfg.Logging.Format,
+		Level:  cfg.Logging.Level,
+		Levels: cfg.Logging.Levels,
+	})
+	slog.SetDefault(logger)
+	log := logging.Subsystem(logger, "mcp")
+
+	// run returns instead of exiting so its deferred cleanup, such as closing
+	// the database, always happens
+	if err := run(cfg, log); err != nil {
+		log.Error("MCP server stopped", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run(cfg *config.Config, log *slog.Logger) error {
+	if cfg.MCP.APIKey == "" {
+		return errors.New("DIKTATOR_API_KEY is required")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	dbConfig := db.DefaultConfig()
+	dbConfig.Driver = cfg.Database.Driver
+	dbConfig.DSN = cfg.Database.URL
+	// A single stdio client never needs a large pool
+	dbConfig.MaxOpenConns = 4
+	dbConfig.MaxIdleConns = 1
+
+	repository, err := db.NewRepository(ctx, dbConfig)
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	defer repository.Close()
+
+	key, err := mcpserver.Authenticate(ctx, repository, cfg.MCP.APIKey)
+	if err != nil {
+		return fmt.Errorf("authentication failed: %w", err)
+	}
+	log.Info("MCP server authenticated", "family_id", key.FamilyID, "key_prefix", key.KeyPrefix)
+
+	server := mcpserver.New(repository, key, version)
+	if err := server.Run(ctx, &mcp.StdioTransport{}); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("failed to serve MCP: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/starefossen/diktator/backend/internal/models"
+)
+
+const (
+	defaultAnalyticsRange = 30 * 24 * time.Hour
+	maxAnalyticsRange     = 366 * 24 * time.Hour
+	defaultAnalyticsLimit = 20
+	maxAnalyticsLimit     = 100
+	// regressionMasteryRuns is how many first-try correct answers make a word
+	// count as mastered before a miss
+	regressionMasteryRuns = 2
+)
+
+// analyticsQuery is the time range and limit shared by the analytics endpoints
+type analyticsQuery struct {
+	from  time.Time
+	to    time.Time
+	limit int
+}
+
+// parseAnalyticsQuery reads from, to and limit. Dates are RFC 3339 timestamps or
+// YYYY-MM-DD; the range defaults to the last 30 days and is at most a year.
+func parseAnalyticsQuery(c *gin.Context) (analyticsQuery, bool) {
+	q := analyticsQuery{to: time.Now(), limit: defaultAnalyticsLimit}
+
+	if raw := c.Query("to"); raw != "" {
+		to, ok := parseAnalyticsTime(raw)
+		if !ok {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Error: "to must be a date (YYYY-MM-DD) or RFC 3339 timestamp",
+			})
+			return q, false
+		}
+		q.to = to
+	}
+
+	q.from = q.to.Add(-defaultAnalyticsRange)
+	if raw := c.Query("from"); raw != "" {
+		from, ok := parseAnalyticsTime(raw)
+		if !ok {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Error: "from must be a date (YYYY-MM-DD) or RFC 3339 timestamp",
+			})
+			return q, false
+		}
+		q.from = from
+	}
+
+	if !q.from.Before(q.to) || q.to.Sub(q.from) > maxAnalyticsRange {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Error: "from must be before to and the range at most 366 days",
+		})
+		return q, false
+	}
+
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Error: "limit must be a positive integer",
+			})
+			return q, false
+		}
+		q.limit = min(parsed, maxAnalyticsLimit)
+	}
+
+	return q, true
+}
+
+func parseAnalyticsTime(raw string) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, true
+	}
+	if t, err := time.Parse(time.DateOnly, raw); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// respondAnalytics writes an analytics result or logs and reports the error
+func respondAnalytics(c *gin.Context, data any, err error, what, childID string) {
+	if err != nil {
+		logger().ErrorContext(c.Request.Context(), "Error getting "+what, "child_id", childID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Failed to retrieve analytics",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Data: data,
+	})
+}
+
+// @Summary		Get Child Missed Words
+// @Description	Get the words a child missed most in a time range, with the error types detected on the misses (parent only)
+// @Tags			children
+// @Produce		json
+// @Param			childId	path		string	true	"Child ID"
+// @Param			from	query		string	false	"Range start (YYYY-MM-DD or RFC 3339, default 30 days before to)"
+// @Param			to		query		string	false	"Range end, exclusive (YYYY-MM-DD or RFC 3339, default now)"
+// @Param			limit	query		int		false	"Maximum number of words (default 20, max 100)"
+// @Success		200		{object}	models.APIResponse{data=[]models.MissedWord}	"Most missed words first"
+// @Failure		400		{object}	models.APIResponse	"Invalid range or limit"
+// @Failure		401		{object}	models.APIResponse	"Parent access required"
+// @Failure		500		{object}	models.APIResponse	"Failed to retrieve analytics"
+// @Security		BearerAuth
+// @Router			/api/families/children/{childId}/analytics/missed-words [get]
+func GetChildMissedWords(c *gin.Context) {
+	serviceManager := GetServiceManager(c)
+	if serviceManager == nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Service unavailable",
+		})
+		return
+	}
+
+	q, ok := parseAnalyticsQuery(c)
+	if !ok {
+		return
+	}
+
+	childID := c.Param("childId")
+	words, err := serviceManager.DB.GetMissedWords(c.Request.Context(), childID, q.from, q.to, q.limit)
+	respondAnalytics(c, words, err, "missed words", childID)
+}
+
+// @Summary		Get Child Error Trends
+// @Description	Count a child's spelling error types per day, week or month in a time range (parent only)
+// @Tags			children
+// @Produce		json
+// @Param			childId		path		string	true	"Child ID"
+// @Param			from		query		string	false	"Range start (YYYY-MM-DD or RFC 3339, default 30 days before to)"
+// @Param			to			query		string	false	"Range end, exclusive (YYYY-MM-DD or RFC 3339, default now)"
+// @Param			bucket		query		string	false	"Bucket size: day, week (default) or month"
+// @Param			errorTypes	query		string	false	"Comma-separated error types to include (default all)"
+// @Success		200			{object}	models.APIResponse{data=[]models.ErrorTypeCount}	"Error counts by bucket, oldest first"
+// @Failure		400			{object}	models.APIResponse	"Invalid range or bucket"
+// @Failure		401			{object}	models.APIResponse	"Parent access required"
+// @Failure		500			{object}	models.APIResponse	"Failed to retrieve analytics"
+// @Security		BearerAuth
+// @Router			/api/families/children/{childId}/analytics/error-types [get]
+func GetChildErrorTrends(c *gin.Context) {
+	serviceManager := GetServiceManager(c)
+	if serviceManager == nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Service unavailable",
+		})
+		return
+	}
+
+	q, ok := parseAnalyticsQuery(c)
+	if !ok {
+		return
+	}
+
+	bucket := c.DefaultQuery("bucket", models.AnalyticsBucketWeek)
+	switch bucket {
+	case models.AnalyticsBucketDay, models.AnalyticsBucketWeek, models.AnalyticsBucketMonth:
+	default:
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Error: "bucket must be day, week or month",
+		})
+		return
+	}
+
+	var errorTypes []string
+	for _, et := range strings.Split(c.Query("errorTypes"), ",") {
+		if et = strings.TrimSpace(et); et != "" {
+			errorTypes = append(errorTypes, et)
+		}
+	}
+
+	childID := c.Param("childId")
+	counts, err := serviceManager.DB.GetErrorTypeCounts(c.Request.Context(), childID, q.from, q.to, bucket, errorTypes)
+	respondAnalytics(c, counts, err, "error type counts", childID)
+}
+
+// @Summary		Get Child Word Attempts
+// @Description	Get a child's average attempts per word in a time range, words needing the most attempts first (parent only)
+// @Tags			children
+// @Produce		json
+// @Param			childId	path		string	true	"Child ID"
+// @Param			from	query		string	false	"Range start (YYYY-MM-DD or RFC 3339, default 30 days before to)"
+// @Param			to		query		string	false	"Range end, exclusive (YYYY-MM-DD or RFC 3339, default now)"
+// @Param			limit	query		int		false	"Maximum number of words (default 20, max 100)"
+// @Success		200		{object}	models.APIResponse{data=[]models.WordAttemptStats}	"Attempt statistics per word"
+// @Failure		400		{object}	models.APIResponse	"Invalid range or limit"
+// @Failure		401		{object}	models.APIResponse	"Parent access required"
+// @Failure		500		{object}	models.APIResponse	"Failed to retrieve analytics"
+// @Security		BearerAuth
+// @Router			/api/families/children/{childId}/analytics/attempts [get]
+func GetChildWordAttempts(c *gin.Context) {
+	serviceManager := GetServiceManager(c)
+	if serviceManager == nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Service unavailable",
+		})
+		return
+	}
+
+	q, ok := parseAnalyticsQuery(c)
+	if !ok {
+		return
+	}
+
+	childID := c.Param("childId")
+	stats, err := serviceManager.DB.GetWordAttemptStats(c.Request.Context(), childID, q.from, q.to, q.limit)
+	respondAnalytics(c, stats, err, "word attempt stats", childID)
+}
+
+// @Summary		Get Child Regressions
+// @Description	Get words a child missed in a time range after answering them right on the first attempt in at
+// @Description	least two earlier tests (parent only)
+// @Tags			children
+// @Produce		json
+// @Param			childId	path		string	true	"Child ID"
+// @Param			from	query		string	false	"Range start (YYYY-MM-DD or RFC 3339, default 30 days before to)"
+// @Param			to		query		string	false	"Range end, exclusive (YYYY-MM-DD or RFC 3339, default now)"
+// @Param			limit	query		int		false	"Maximum number of words (default 20, max 100)"
+// @Success		200		{object}	models.APIResponse{data=[]models.RegressedWord}	"Regressed words, most recently missed first"
+// @Failure		400		{object}	models.APIResponse	"Invalid range or limit"
+// @Failure		401		{object}	models.APIResponse	"Parent access required"
+// @Failure		500		{object}	models.APIResponse	"Failed to retrieve analytics"
+// @Security		BearerAuth
+// @Router			/api/families/children/{childId}/analytics/regressions [get]
+func GetChildRegressions(c *gin.Context) {
+	serviceManager := GetServiceManager(c)
+	if serviceManager == nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Service unavailable",
+		})
+		return
+	}
+
+	q, ok := parseAnalyticsQuery(c)
+	if !ok {
+		return
+	}
+
+	childID := c.Param("childId")
+	words, err := serviceManager.DB.GetRegressedWords(c.Request.Context(), childID, q.from, q.to, regressionMasteryRuns, q.limit)
+	respondAnalytics(c, words, err, "regressed words", childID)
+}
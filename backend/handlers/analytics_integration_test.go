@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChildAnalytics_Integration(t *testing.T) {
+	env := SetupIntegrationTest(t)
+	defer env.Cleanup()
+
+	parent := env.CreateTestUser("", "parent")
+	familyID := env.CreateTestFamily(parent.ID)
+	parent.FamilyID = familyID
+	_, err := env.Pool.Exec(context.Background(), `UPDATE users SET family_id = $1 WHERE id = $2`, familyID, parent.ID)
+	require.NoError(t, err)
+	child := env.CreateTestUser(familyID, "child")
+	wordSet := env.CreateTestWordSet(familyID, parent.ID)
+
+	// "hus" is right first time twice, then missed twice in the last week.
+	// "skjære" is missed every time; "bil" is always right after two tries.
+	now := time.Now().UTC()
+	days := []int{40, 35, 5, 2}
+	for i, daysAgo := range days {
+		husCorrect := i < 2
+		words := []models.WordTestResult{
+			{Word: "hus", Correct: husCorrect, Attempts: 1, FinalAnswer: "hus"},
+			{Word: "skjære", Correct: false, Attempts: 3, FinalAnswer: "sjære", ErrorTypes: []string{"kjSkjSj"}},
+			{Word: "bil", Correct: true, Attempts: 2, FinalAnswer: "bil"},
+		}
+		if !husCorrect {
+			words[0] = models.WordTestResult{Word: "hus", Attempts: 3, FinalAnswer: "huss", ErrorTypes: []string{"doubleConsonant"}}
+		}
+		require.NoError(t, env.DB.SaveTestResult(t.Context(), &models.TestResult{
+			WordSetID:    wordSet.ID,
+			UserID:       child.ID,
+			Mode:         string(models.TestModeKeyboard),
+			Score:        33,
+			TotalWords:   3,
+			CorrectWords: 1,
+			CompletedAt:  now.AddDate(0, 0, -daysAgo),
+			Words:        words,
+		}))
+	}
+
+	env.SetupAuthMiddleware(parent)
+	base := "/api/families/children/:childId/analytics"
+	env.Router.GET(base+"/missed-words", GetChildMissedWords)
+	env.Router.GET(base+"/error-types", GetChildErrorTrends)
+	env.Router.GET(base+"/attempts", GetChildWordAttempts)
+	env.Router.GET(base+"/regressions", GetChildRegressions)
+	path := "/api/families/children/" + child.ID + "/analytics"
+
+	get := func(t *testing.T, url string, data any) {
+		t.Helper()
+		resp := makeRequest(env.Router, "GET", url, nil, nil)
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		apiResp := struct {
+			Data any `json:"data"`
+		}{Data: data}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &apiResp))
+	}
+
+	t.Run("MissedWords", func(t *testing.T) {
+		var words []models.MissedWord
+		get(t, path+"/missed-words", &words)
+
+		require.Len(t, words, 2, "only the last 30 days by default")
+		assert.Equal(t, "hus", words[0].Word)
+		assert.Equal(t, 2, words[0].Misses)
+		assert.Equal(t, 2, words[0].Tests)
+		assert.Equal(t, []string{"doubleConsonant"}, words[0].ErrorTypes)
+		assert.Equal(t, "skjære", words[1].Word)
+
+		get(t, path+"/missed-words?from="+now.AddDate(0, 0, -60).Format(time.DateOnly), &words)
+		assert.Equal(t, "skjære", words[0].Word)
+		assert.Equal(t, 4, words[0].Misses)
+	})
+
+	t.Run("ErrorTypes", func(t *testing.T) {
+		var counts []models.ErrorTypeCount
+		get(t, path+"/error-types?bucket=month&from="+now.AddDate(0, 0, -60).Format(time.DateOnly), &counts)
+
+		totals := map[string]int{}
+		for _, c := range counts {
+			totals[c.ErrorType] += c.Count
+			assert.Equal(t, 1, c.BucketStart.Day(), "month buckets start on the first")
+		}
+		assert.Equal(t, map[string]int{"kjSkjSj": 4, "doubleConsonant": 2}, totals)
+
+		get(t, path+"/error-types?errorTypes=doubleConsonant", &counts)
+		for _, c := range counts {
+			assert.Equal(t, "doubleConsonant", c.ErrorType)
+		}
+	})
+
+	t.Run("Attempts", func(t *testing.T) {
+		var stats []models.WordAttemptStats
+		get(t, path+"/attempts?limit=2", &stats)
+
+		require.Len(t, stats, 2)
+		assert.Equal(t, "hus", stats[0].Word)
+		assert.InDelta(t, 3.0, stats[0].AvgAttempts, 0.001)
+		assert.Equal(t, "skjære", stats[1].Word)
+		assert.Equal(t, 0, stats[1].FirstTryCorrect)
+	})
+
+	t.Run("Regressions", func(t *testing.T) {
+		var words []models.RegressedWord
+		get(t, path+"/regressions", &words)
+
+		require.Len(t, words, 1)
+		assert.Equal(t, "hus", words[0].Word)
+		assert.Equal(t, 2, words[0].Misses)
+		assert.Equal(t, 2, words[0].PriorCorrect)
+		assert.WithinDuration(t, now.AddDate(0, 0, -35), words[0].LastMasteredAt, time.Second)
+	})
+
+	t.Run("Error_InvalidRange", func(t *testing.T) {
+		resp := makeRequest(env.Router, "GET", path+"/missed-words?from=2026-02-01&to=2026-01-01", nil, nil)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+		resp = makeRequest(env.Router, "GET", path+"/error-types?bucket=year", nil, nil)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+}
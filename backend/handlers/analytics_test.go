@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAnalyticsQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	parse := func(query string) (analyticsQuery, int) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/analytics?"+query, nil)
+		q, ok := parseAnalyticsQuery(c)
+		if !ok {
+			return q, w.Code
+		}
+		return q, http.StatusOK
+	}
+
+	q, code := parse("")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, defaultAnalyticsRange, q.to.Sub(q.from))
+	assert.WithinDuration(t, time.Now(), q.to, time.Minute)
+	assert.Equal(t, defaultAnalyticsLimit, q.limit)
+
+	q, code = parse("from=2026-01-01&to=2026-02-01T12:00:00Z&limit=500")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), q.from)
+	assert.Equal(t, time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC), q.to)
+	assert.Equal(t, maxAnalyticsLimit, q.limit)
+
+	for _, bad := range []string{
+		"from=yesterday",
+		"to=2026-13-01",
+		"from=2026-02-01&to=2026-01-01",
+		"from=2024-01-01&to=2026-01-01",
+		"limit=0",
+	} {
+		_, code := parse(bad)
+		assert.Equal(t, http.StatusBadRequest, code, bad)
+	}
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services/auth"
+	"github.com/starefossen/diktator/backend/internal/services/db"
+)
+
+// @Summary		Create Family API Key
+// @Description	Create a family-scoped API key for non-interactive clients such as the MCP server (parent only). The plaintext key is only returned once.
+// @Tags			families
+// @Accept			json
+// @Produce		json
+// @Param			request	body		models.CreateFamilyAPIKeyRequest	true	"API key details"
+// @Success		201		{object}	models.APIResponse{data=models.CreateFamilyAPIKeyResponse}	"API key created"
+// @Failure		400		{object}	models.APIResponse	"Invalid request"
+// @Failure		500		{object}	models.APIResponse	"Failed to create API key"
+// @Security		BearerAuth
+// @Router			/api/families/api-keys [post]
+func CreateFamilyAPIKey(c *gin.Context) {
+	serviceManager := GetServiceManager(c)
+	if serviceManager == nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Service unavailable",
+		})
+		return
+	}
+
+	var req models.CreateFamilyAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Error: "Invalid request data",
+		})
+		return
+	}
+
+	familyID, err := getContextString(c, "validatedFamilyID")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid family ID"})
+		return
+	}
+	userID, err := getContextString(c, "userID")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	plaintext, prefix, hash, err := auth.GenerateAPIKey()
+	if err != nil {
+		logger().ErrorContext(c.Request.Context(), "Error generating API key", "error", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Failed to create API key",
+		})
+		return
+	}
+
+	apiKey := &models.FamilyAPIKey{
+		ID:        uuid.New().String(),
+		FamilyID:  familyID,
+		Name:      req.Name,
+		KeyPrefix: prefix,
+		KeyHash:   hash,
+		CreatedBy: userID,
+		CreatedAt: time.Now(),
+	}
+
+	if err := serviceManager.DB.CreateFamilyAPIKey(c.Request.Context(), apiKey); err != nil {
+		logger().ErrorContext(c.Request.Context(), "Error creating API key", "family_id", familyID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Failed to create API key",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Data: models.CreateFamilyAPIKeyResponse{
+			APIKey: apiKey,
+			Key:    plaintext,
+		},
+		Message: "API key created. Store it now - it will not be shown again.",
+	})
+}
+
+// @Summary		Get Family API Keys
+// @Description	List API keys for the family (parent only). Plaintext keys are never returned.
+// @Tags			families
+// @Accept			json
+// @Produce		json
+// @Success		200	{object}	models.APIResponse{data=[]models.FamilyAPIKey}	"API keys retrieved"
+// @Failure		500	{object}	models.APIResponse	"Failed to retrieve API keys"
+// @Security		BearerAuth
+// @Router			/api/families/api-keys [get]
+func GetFamilyAPIKeys(c *gin.Context) {
+	serviceManager := GetServiceManager(c)
+	if serviceManager == nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Service unavailable",
+		})
+		return
+	}
+
+	familyID, err := getContextString(c, "validatedFamilyID")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid family ID"})
+		return
+	}
+
+	keys, err := serviceManager.DB.GetFamilyAPIKeys(c.Request.Context(), familyID)
+	if err != nil {
+		logger().ErrorContext(c.Request.Context(), "Error getting API keys", "family_id", familyID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Failed to retrieve API keys",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Data: keys,
+	})
+}
+
+// @Summary		Delete Family API Key
+// @Description	Revoke a family API key (parent only)
+// @Tags			families
+// @Accept			json
+// @Produce		json
+// @Param			keyId	path		string				true	"API key ID"
+// @Success		200		{object}	models.APIResponse	"API key revoked"
+// @Failure		404		{object}	models.APIResponse	"API key not found"
+// @Failure		500		{object}	models.APIResponse	"Failed to revoke API key"
+// @Security		BearerAuth
+// @Router			/api/families/api-keys/{keyId} [delete]
+func DeleteFamilyAPIKey(c *gin.Context) {
+	serviceManager := GetServiceManager(c)
+	if serviceManager == nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Service unavailable",
+		})
+		return
+	}
+
+	familyID, err := getContextString(c, "validatedFamilyID")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid family ID"})
+		return
+	}
+
+	keyID := c.Param("keyId")
+	if keyID == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Error: "API key ID required",
+		})
+		return
+	}
+
+	if err := serviceManager.DB.DeleteFamilyAPIKey(c.Request.Context(), familyID, keyID); err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			c.JSON(http.StatusNotFound, models.APIResponse{
+				Error: "API key not found",
+			})
+			return
+		}
+		logger().ErrorContext(c.Request.Context(), "Error deleting API key", "id", keyID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Failed to revoke API key",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Message: "API key revoked successfully",
+	})
+}
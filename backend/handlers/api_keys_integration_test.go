@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/starefossen/diktator/backend/internal/mcpserver"
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFamilyAPIKeys_Integration(t *testing.T) {
+	env := SetupIntegrationTest(t)
+	defer env.Cleanup()
+
+	parent := env.CreateTestUser("", "parent")
+	familyID := env.CreateTestFamily(parent.ID)
+	parent.FamilyID = familyID
+
+	env.SetupAuthMiddleware(parent)
+	env.Router.POST("/api/families/api-keys", CreateFamilyAPIKey)
+	env.Router.GET("/api/families/api-keys", GetFamilyAPIKeys)
+	env.Router.DELETE("/api/families/api-keys/:keyId", DeleteFamilyAPIKey)
+
+	var created models.CreateFamilyAPIKeyResponse
+
+	t.Run("Create_ReturnsPlaintextOnce", func(t *testing.T) {
+		resp := makeRequest(env.Router, "POST", "/api/families/api-keys", map[string]string{"name": "Claude Desktop"}, nil)
+		require.Equal(t, http.StatusCreated, resp.Code)
+
+		var body struct {
+			Data models.CreateFamilyAPIKeyResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		created = body.Data
+
+		assert.NotEmpty(t, created.Key)
+		assert.Equal(t, familyID, created.APIKey.FamilyID)
+		assert.Equal(t, created.Key[:len(created.APIKey.KeyPrefix)], created.APIKey.KeyPrefix)
+		assert.NotContains(t, resp.Body.String(), "keyHash")
+		env.AssertRowCount("family_api_keys", 1)
+	})
+
+	t.Run("List_OmitsSecrets", func(t *testing.T) {
+		resp := makeRequest(env.Router, "GET", "/api/families/api-keys", nil, nil)
+		require.Equal(t, http.StatusOK, resp.Code)
+		assert.NotContains(t, resp.Body.String(), created.Key)
+		assert.Contains(t, resp.Body.String(), created.APIKey.ID)
+	})
+
+	t.Run("Authenticate_ResolvesFamily", func(t *testing.T) {
+		key, err := mcpserver.Authenticate(t.Context(), env.DB, created.Key)
+		require.NoError(t, err)
+		assert.Equal(t, familyID, key.FamilyID)
+		assert.Equal(t, parent.ID, key.CreatedBy)
+	})
+
+	t.Run("Delete_RevokesKey", func(t *testing.T) {
+		resp := makeRequest(env.Router, "DELETE", "/api/families/api-keys/"+created.APIKey.ID, nil, nil)
+		require.Equal(t, http.StatusOK, resp.Code)
+		env.AssertNoRowsInTable("family_api_keys")
+
+		_, err := mcpserver.Authenticate(t.Context(), env.DB, created.Key)
+		assert.ErrorIs(t, err, mcpserver.ErrUnauthorized)
+
+		resp = makeRequest(env.Router, "DELETE", "/api/families/api-keys/"+created.APIKey.ID, nil, nil)
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+}
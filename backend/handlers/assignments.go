@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services"
+	"github.com/starefossen/diktator/backend/internal/services/assignment"
+)
+
+// @Summary		Get Child Assignments
+// @Description	Get a child's word set assignments with due dates, goals and completion state (parent only)
+// @Tags			children
+// @Accept			json
+// @Produce		json
+// @Param			childId	path		string	true	"Child ID"
+// @Success		200		{object}	models.APIResponse{data=[]models.AssignmentProgress}	"Assignment completion state"
+// @Failure		401		{object}	models.APIResponse	"Parent access required"
+// @Failure		404		{object}	models.APIResponse	"Child not found"
+// @Failure		500		{object}	models.APIResponse	"Failed to retrieve assignments"
+// @Security		BearerAuth
+// @Router			/api/families/children/{childId}/assignments [get]
+func GetChildAssignments(c *gin.Context) {
+	serviceManager := GetServiceManager(c)
+	if serviceManager == nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Service unavailable",
+		})
+		return
+	}
+
+	respondAssignmentProgress(c, serviceManager, c.Param("childId"))
+}
+
+// @Summary		Get My Assignments
+// @Description	Get the current user's word set assignments with due dates, goals and completion state
+// @Tags			users
+// @Accept			json
+// @Produce		json
+// @Success		200	{object}	models.APIResponse{data=[]models.AssignmentProgress}	"Assignment completion state"
+// @Failure		500	{object}	models.APIResponse	"Failed to retrieve assignments"
+// @Security		BearerAuth
+// @Router			/api/users/assignments [get]
+func GetMyAssignments(c *gin.Context) {
+	serviceManager := GetServiceManager(c)
+	if serviceManager == nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Service unavailable",
+		})
+		return
+	}
+
+	userID, err := getContextString(c, "userID")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	respondAssignmentProgress(c, serviceManager, userID)
+}
+
+func respondAssignmentProgress(c *gin.Context, sm *services.Manager, userID string) {
+	assignments, err := sm.DB.GetUserAssignments(c.Request.Context(), userID)
+	if err != nil {
+		logger().ErrorContext(c.Request.Context(), "Error getting assignments", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Failed to retrieve assignments",
+		})
+		return
+	}
+
+	progress, err := assignment.ChildProgress(c.Request.Context(), sm.DB, userID, assignments, time.Now())
+	if err != nil {
+		logger().ErrorContext(c.Request.Context(), "Error evaluating assignment progress", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Failed to retrieve assignments",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Data: progress,
+	})
+}
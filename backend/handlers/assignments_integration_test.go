@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssignmentSchedule_Integration(t *testing.T) {
+	env := SetupIntegrationTest(t)
+	defer env.Cleanup()
+
+	parent := env.CreateTestUser("", "parent")
+	familyID := env.CreateTestFamily(parent.ID)
+	parent.FamilyID = familyID
+	child := env.CreateTestUser(familyID, "child")
+	wordSet := env.CreateTestWordSet(familyID, parent.ID)
+
+	env.SetupAuthMiddleware(parent)
+	env.Router.POST("/api/wordsets/:id/assignments/:userId", AssignWordSetToUser)
+	env.Router.GET("/api/families/children/:childId/assignments", GetChildAssignments)
+
+	getProgress := func(t *testing.T) []models.AssignmentProgress {
+		resp := makeRequest(env.Router, "GET", "/api/families/children/"+child.ID+"/assignments", nil, nil)
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var apiResp struct {
+			Data []models.AssignmentProgress `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &apiResp))
+		return apiResp.Data
+	}
+
+	t.Run("Error_InvalidGoalMode", func(t *testing.T) {
+		body := map[string]any{"goal": map[string]any{"mode": "telepathy", "runs": 2}}
+		resp := makeRequest(env.Router, "POST", "/api/wordsets/"+wordSet.ID+"/assignments/"+child.ID, body, nil)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		env.AssertNoRowsInTable("wordset_assignments")
+	})
+
+	t.Run("Success_AssignWithSchedule", func(t *testing.T) {
+		due := time.Now().Add(72 * time.Hour).UTC().Truncate(time.Second)
+		body := map[string]any{
+			"dueAt":             due,
+			"goal":              map[string]any{"mode": string(models.TestModeKeyboard), "runs": 2, "minScore": 80},
+			"reminderFrequency": models.ReminderDaily,
+			"reminderHour":      18,
+		}
+		resp := makeRequest(env.Router, "POST", "/api/wordsets/"+wordSet.ID+"/assignments/"+child.ID, body, nil)
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		progress := getProgress(t)
+		require.Len(t, progress, 1)
+		p := progress[0]
+		assert.Equal(t, models.AssignmentNotStarted, p.Status)
+		assert.Equal(t, 2, p.Goal.Runs)
+		require.NotNil(t, p.Assignment)
+		require.NotNil(t, p.Assignment.DueAt)
+		assert.True(t, due.Equal(*p.Assignment.DueAt))
+		assert.Equal(t, models.ReminderDaily, p.Assignment.ReminderFrequency)
+		assert.Equal(t, 18, p.Assignment.ReminderHour)
+
+		reminders, err := env.DB.GetReminderAssignments(t.Context())
+		require.NoError(t, err)
+		assert.Len(t, reminders, 1)
+	})
+
+	t.Run("Success_CompletionState", func(t *testing.T) {
+		for _, score := range []float64{70, 85, 95} {
+			require.NoError(t, env.DB.SaveTestResult(t.Context(), &models.TestResult{
+				ID:           uuid.New().String(),
+				WordSetID:    wordSet.ID,
+				UserID:       child.ID,
+				Mode:         string(models.TestModeKeyboard),
+				Score:        score,
+				TotalWords:   2,
+				CorrectWords: 2,
+				CompletedAt:  time.Now(),
+			}))
+		}
+
+		progress := getProgress(t)
+		require.Len(t, progress, 1)
+		assert.Equal(t, models.AssignmentCompleted, progress[0].Status)
+		assert.Equal(t, 3, progress[0].TotalRuns)
+		assert.Equal(t, 2, progress[0].QualifyingRuns)
+		assert.True(t, progress[0].Completed)
+	})
+
+	t.Run("Success_ClaimReminder", func(t *testing.T) {
+		slot := time.Now().Add(-time.Hour)
+		claimed, err := env.DB.ClaimAssignmentReminder(t.Context(), wordSet.ID, child.ID, slot, time.Now())
+		require.NoError(t, err)
+		assert.True(t, claimed)
+
+		claimed, err = env.DB.ClaimAssignmentReminder(t.Context(), wordSet.ID, child.ID, slot, time.Now())
+		require.NoError(t, err)
+		assert.False(t, claimed, "a slot is claimed once")
+
+		assignments, err := env.DB.GetUserAssignments(t.Context(), child.ID)
+		require.NoError(t, err)
+		require.Len(t, assignments, 1)
+		assert.NotNil(t, assignments[0].LastRemindedAt)
+	})
+}
@@ -3,7 +3,6 @@ package handlers
 
 import (
 	"fmt"
-	"log"
 	"net/http"
 	"strings"
 
@@ -76,9 +75,9 @@ func StreamWordAudio(c *gin.Context) {
 	sm := serviceManager
 
 	// Fetch word set to determine language and find word/translation
-	wordSet, err := sm.DB.GetWordSet(wordSetID)
+	wordSet, err := sm.DB.GetWordSet(c.Request.Context(), wordSetID)
 	if err != nil {
-		log.Printf("StreamWordAudio: Error getting word set '%s': %v", wordSetID, err)
+		logger().WarnContext(c.Request.Context(), "Error getting word set for audio", "wordset_id", wordSetID, "error", err)
 		c.JSON(http.StatusNotFound, models.APIResponse{
 			Error: "Word set not found",
 		})
@@ -151,21 +150,21 @@ func StreamWordAudio(c *gin.Context) {
 
 	isSentence := tts.IsSentence(textToSpeak)
 	if isTranslation {
-		log.Printf("StreamWordAudio: Generating translation audio for '%s' -> '%s' in language '%s' for %s (v%s)",
-			word, textToSpeak, language, ua.Name, ua.Version)
+		logger().DebugContext(c.Request.Context(), "Generating translation audio", "word", word, "text", textToSpeak,
+			"language", language, "browser", ua.Name, "browser_version", ua.Version)
 	} else if isSentence {
-		log.Printf("StreamWordAudio: Generating sentence audio (%d words) in language '%s' for %s (v%s)",
-			tts.GetWordCount(textToSpeak), language, ua.Name, ua.Version)
+		logger().DebugContext(c.Request.Context(), "Generating sentence audio", "words", tts.GetWordCount(textToSpeak),
+			"language", language, "browser", ua.Name, "browser_version", ua.Version)
 	} else {
-		log.Printf("StreamWordAudio: Generating word audio for '%s' in language '%s' for %s (v%s)",
-			textToSpeak, language, ua.Name, ua.Version)
+		logger().DebugContext(c.Request.Context(), "Generating word audio", "word", textToSpeak,
+			"language", language, "browser", ua.Name, "browser_version", ua.Version)
 	}
 
 	// Generate audio using TTS service - automatically detects sentence vs word
 	// Safari requires MP3 format (only partial OGG Opus support)
-	audioData, audioFile, contentType, err := sm.TTS.GenerateTextAudioWithFormat(textToSpeak, language, isSafari)
+	audioData, audioFile, contentType, err := sm.TTS.GenerateTextAudioWithFormat(c.Request.Context(), textToSpeak, language, isSafari)
 	if err != nil {
-		log.Printf("StreamWordAudio: Error generating audio: %v", err)
+		logger().ErrorContext(c.Request.Context(), "Error generating audio", "wordset_id", wordSetID, "error", err)
 
 		// Check if it's a permission error from Google Cloud
 		errStr := err.Error()
@@ -185,7 +184,7 @@ func StreamWordAudio(c *gin.Context) {
 		return
 	}
 
-	log.Printf("StreamWordAudio: Successfully generated audio, size: %d bytes, type: %s", len(audioData), contentType)
+	logger().DebugContext(c.Request.Context(), "Generated audio", "bytes", len(audioData), "content_type", contentType)
 
 	// Set aggressive caching headers - browser will cache this for the session
 	c.Header("Content-Type", contentType)
@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services"
+	"github.com/starefossen/diktator/backend/internal/services/challenge"
+	"github.com/starefossen/diktator/backend/internal/services/db"
+)
+
+// @Summary		Get Word Set Challenges
+// @Description	Generate letter tiles, word banks and missing-letter gaps for every word and sentence
+// @Description	in a word set. Distractors follow the word's spelling focus (kj/skj/sj, double
+// @Description	consonants, æ/e, ø/o, ...) and are deterministic for a seed. Without a seed a child
+// @Description	gets the seed a parent locked for them, or a fresh random seed. Parents can preview
+// @Description	a child's challenges with childId.
+// @Tags			wordsets
+// @Accept			json
+// @Produce		json
+// @Param			id		path		string	true	"Word set ID"
+// @Param			childId	query		string	false	"Child to generate challenges for (parents only, defaults to the caller)"
+// @Param			seed	query		int		false	"Seed to generate the challenges from (0 to 2^53-1)"
+// @Success		200		{object}	models.APIResponse{data=models.ChallengeSet}	"Generated challenges"
+// @Failure		400		{object}	models.APIResponse	"Invalid seed"
+// @Failure		403		{object}	models.APIResponse	"Only parents can preview challenges for a child"
+// @Failure		404		{object}	models.APIResponse	"Word set or child not found"
+// @Failure		500		{object}	models.APIResponse	"Failed to generate challenges"
+// @Security		BearerAuth
+// @Router			/api/wordsets/{id}/challenges [get]
+func GetWordSetChallenges(c *gin.Context) {
+	serviceManager := GetServiceManager(c)
+	if serviceManager == nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Service unavailable",
+		})
+		return
+	}
+
+	seed, hasSeed, ok := parseChallengeSeed(c)
+	if !ok {
+		return
+	}
+
+	childID, ok := challengeChild(c, serviceManager)
+	if !ok {
+		return
+	}
+
+	wordSetID := c.Param("id")
+	wordSet, err := serviceManager.DB.GetWordSet(c.Request.Context(), wordSetID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Error: "Word set not found",
+		})
+		return
+	}
+
+	set := models.ChallengeSet{
+		WordSetID: wordSetID,
+		ChildID:   childID,
+		Seed:      seed,
+	}
+	if !hasSeed {
+		lock, err := lockedChallenge(c.Request.Context(), serviceManager, wordSetID, childID)
+		if err != nil {
+			logger().ErrorContext(c.Request.Context(), "Error getting challenge lock", "child_id", childID, "wordset_id", wordSetID, "error", err)
+			c.JSON(http.StatusInternalServerError, models.APIResponse{
+				Error: "Failed to generate challenges",
+			})
+			return
+		}
+		if lock != nil {
+			set.Seed = lock.Seed
+			set.Locked = true
+			set.LockedAt = &lock.LockedAt
+		} else {
+			set.Seed = challenge.RandomSeed()
+		}
+	}
+	set.Words = challenge.Generate(wordSet, set.Seed)
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Data: set,
+	})
+}
+
+// parseChallengeSeed reads the optional seed query parameter. It writes a 400
+// response and returns ok=false when the seed is invalid.
+func parseChallengeSeed(c *gin.Context) (seed int64, hasSeed, ok bool) {
+	raw := c.Query("seed")
+	if raw == "" {
+		return 0, false, true
+	}
+	seed, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || seed < 0 || seed > models.MaxChallengeSeed {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Error: "seed must be an integer between 0 and 9007199254740991",
+		})
+		return 0, false, false
+	}
+	return seed, true, true
+}
+
+// challengeChild returns the child whose challenges are requested: the caller
+// if they are a child, or the childId query parameter for parents. Parents
+// without childId get an empty child ID and no lock lookup.
+func challengeChild(c *gin.Context, sm *services.Manager) (string, bool) {
+	userID, err := getContextString(c, "userID")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return "", false
+	}
+
+	role, _ := c.Get("userRole")
+	childID := c.Query("childId")
+	if role != "parent" {
+		if childID != "" && childID != userID {
+			c.JSON(http.StatusForbidden, models.APIResponse{
+				Error: "Only parents can preview challenges for a child",
+			})
+			return "", false
+		}
+		return userID, true
+	}
+	if childID == "" {
+		return "", true
+	}
+
+	return childID, familyChild(c, sm, childID)
+}
+
+// familyChild checks that childID is a child in the caller's family, writing
+// a 404 response if it isn't
+func familyChild(c *gin.Context, sm *services.Manager, childID string) bool {
+	familyID, err := getContextString(c, "validatedFamilyID")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid family ID"})
+		return false
+	}
+
+	child, err := sm.DB.GetUser(c.Request.Context(), childID)
+	if err != nil || child.FamilyID != familyID || child.Role != "child" {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Error: "Child not found",
+		})
+		return false
+	}
+	return true
+}
+
+// lockedChallenge returns the lock for a child and word set, or nil if there is none
+func lockedChallenge(ctx context.Context, sm *services.Manager, wordSetID, childID string) (*models.ChallengeLock, error) {
+	if childID == "" {
+		return nil, nil
+	}
+	lock, err := sm.DB.GetChallengeLock(ctx, wordSetID, childID)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, nil
+	}
+	return lock, err
+}
+
+// @Summary		Lock Child Challenges
+// @Description	Lock the challenge seed a child gets for a word set, typically a seed the parent
+// @Description	previewed with GET /api/wordsets/{id}/challenges. The child sees the same tiles, word
+// @Description	banks and gaps until the lock is removed; edited words get new challenges (parent only).
+// @Tags			wordsets
+// @Accept			json
+// @Produce		json
+// @Param			id		path		string						true	"Word set ID"
+// @Param			childId	path		string						true	"Child ID"
+// @Param			request	body		models.ChallengeLockRequest	true	"Seed to lock"
+// @Success		200		{object}	models.APIResponse{data=models.ChallengeLock}	"Challenges locked"
+// @Failure		400		{object}	models.APIResponse	"Invalid request data"
+// @Failure		401		{object}	models.APIResponse	"Parent access required"
+// @Failure		404		{object}	models.APIResponse	"Child not found"
+// @Failure		500		{object}	models.APIResponse	"Failed to lock challenges"
+// @Security		BearerAuth
+// @Router			/api/wordsets/{id}/challenges/lock/{childId} [put]
+func LockWordSetChallenges(c *gin.Context) {
+	serviceManager := GetServiceManager(c)
+	if serviceManager == nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Service unavailable",
+		})
+		return
+	}
+
+	userID, err := getContextString(c, "userID")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req models.ChallengeLockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Error: "Invalid request data",
+		})
+		return
+	}
+
+	childID := c.Param("childId")
+	if !familyChild(c, serviceManager, childID) {
+		return
+	}
+
+	lock := models.ChallengeLock{
+		WordSetID: c.Param("id"),
+		ChildID:   childID,
+		LockedBy:  userID,
+		Seed:      *req.Seed,
+	}
+	if err := serviceManager.DB.SaveChallengeLock(c.Request.Context(), &lock); err != nil {
+		logger().ErrorContext(c.Request.Context(), "Error locking challenges", "child_id", childID, "wordset_id", lock.WordSetID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Failed to lock challenges",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Data:    lock,
+		Message: "Challenges locked",
+	})
+}
+
+// @Summary		Unlock Child Challenges
+// @Description	Remove a child's challenge lock for a word set so they get fresh challenges again (parent only)
+// @Tags			wordsets
+// @Accept			json
+// @Produce		json
+// @Param			id		path		string	true	"Word set ID"
+// @Param			childId	path		string	true	"Child ID"
+// @Success		200		{object}	models.APIResponse	"Challenges unlocked"
+// @Failure		401		{object}	models.APIResponse	"Parent access required"
+// @Failure		404		{object}	models.APIResponse	"Child or challenge lock not found"
+// @Failure		500		{object}	models.APIResponse	"Failed to unlock challenges"
+// @Security		BearerAuth
+// @Router			/api/wordsets/{id}/challenges/lock/{childId} [delete]
+func UnlockWordSetChallenges(c *gin.Context) {
+	serviceManager := GetServiceManager(c)
+	if serviceManager == nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Service unavailable",
+		})
+		return
+	}
+
+	childID := c.Param("childId")
+	if !familyChild(c, serviceManager, childID) {
+		return
+	}
+
+	wordSetID := c.Param("id")
+	if err := serviceManager.DB.DeleteChallengeLock(c.Request.Context(), wordSetID, childID); err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			c.JSON(http.StatusNotFound, models.APIResponse{
+				Error: "Challenge lock not found",
+			})
+			return
+		}
+		logger().ErrorContext(c.Request.Context(), "Error unlocking challenges", "child_id", childID, "wordset_id", wordSetID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Failed to unlock challenges",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Message: "Challenges unlocked",
+	})
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWordSetChallenges_Integration(t *testing.T) {
+	env := SetupIntegrationTest(t)
+	defer env.Cleanup()
+
+	parent := env.CreateTestUser("", "parent")
+	familyID := env.CreateTestFamily(parent.ID)
+	child := env.CreateTestUser(familyID, "child")
+
+	wordSet := env.CreateTestWordSet(familyID, parent.ID)
+	for _, w := range []string{"skjønn", "hvit", "Han er her."} {
+		wordSet.Words = append(wordSet.Words, struct {
+			Word         string               `json:"word"`
+			Audio        models.WordAudio     `json:"audio,omitempty"`
+			Definition   string               `json:"definition,omitempty"`
+			Translations []models.Translation `json:"translations,omitempty"`
+		}{Word: w})
+	}
+	require.NoError(t, env.DB.UpdateWordSet(t.Context(), wordSet))
+
+	routerFor := func(user *models.User) *gin.Engine {
+		router := gin.New()
+		router.Use(func(c *gin.Context) {
+			c.Set("serviceManager", env.ServiceManager)
+			c.Set("userID", user.ID)
+			c.Set("userRole", user.Role)
+			c.Set("validatedFamilyID", familyID)
+			c.Next()
+		})
+		router.GET("/api/wordsets/:id/challenges", GetWordSetChallenges)
+		router.PUT("/api/wordsets/:id/challenges/lock/:childId", LockWordSetChallenges)
+		router.DELETE("/api/wordsets/:id/challenges/lock/:childId", UnlockWordSetChallenges)
+		return router
+	}
+	base := "/api/wordsets/" + wordSet.ID + "/challenges"
+
+	getSet := func(t *testing.T, user *models.User, query string) models.ChallengeSet {
+		t.Helper()
+		resp := makeRequest(routerFor(user), "GET", base+query, nil, nil)
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		var apiResp struct {
+			Data models.ChallengeSet `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &apiResp))
+		return apiResp.Data
+	}
+
+	var preview models.ChallengeSet
+	t.Run("Success_ParentPreview", func(t *testing.T) {
+		preview = getSet(t, parent, "?childId="+child.ID+"&seed=1234")
+		assert.Equal(t, int64(1234), preview.Seed)
+		assert.Equal(t, child.ID, preview.ChildID)
+		assert.False(t, preview.Locked)
+		require.Len(t, preview.Words, 3)
+		assert.Contains(t, preview.Words[0].Focus, models.SpellingFocusSkjSound)
+		assert.NotEmpty(t, preview.Words[0].LetterTiles)
+		assert.NotEmpty(t, preview.Words[2].WordBank)
+
+		assert.Equal(t, preview.Words, getSet(t, parent, "?childId="+child.ID+"&seed=1234").Words)
+	})
+
+	t.Run("Success_LockAndChildSeesPreview", func(t *testing.T) {
+		resp := makeRequest(routerFor(parent), "PUT", base+"/lock/"+child.ID, map[string]any{"seed": 1234}, nil)
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+		set := getSet(t, child, "")
+		assert.True(t, set.Locked)
+		assert.NotNil(t, set.LockedAt)
+		assert.Equal(t, preview.Words, set.Words)
+	})
+
+	t.Run("Success_Unlock", func(t *testing.T) {
+		resp := makeRequest(routerFor(parent), "DELETE", base+"/lock/"+child.ID, nil, nil)
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		assert.False(t, getSet(t, child, "").Locked)
+
+		resp = makeRequest(routerFor(parent), "DELETE", base+"/lock/"+child.ID, nil, nil)
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+
+	t.Run("Error_InvalidSeed", func(t *testing.T) {
+		resp := makeRequest(routerFor(parent), "GET", base+"?seed=-1", nil, nil)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
+	t.Run("Error_ChildPreviewsSibling", func(t *testing.T) {
+		sibling := env.CreateTestUser(familyID, "child")
+		resp := makeRequest(routerFor(child), "GET", base+"?childId="+sibling.ID, nil, nil)
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+	})
+
+	t.Run("Error_LockOtherFamilyChild", func(t *testing.T) {
+		otherParent := env.CreateTestUser("", "parent")
+		otherChild := env.CreateTestUser(env.CreateTestFamily(otherParent.ID), "child")
+		resp := makeRequest(routerFor(parent), "PUT", base+"/lock/"+otherChild.ID, map[string]any{"seed": 1}, nil)
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+}
@@ -82,7 +82,7 @@ func TestChildInvitationFlow_Integration(t *testing.T) {
 
 		// Now simulate the child logging in for the first time
 		// When they authenticate via OIDC, the system should check for pending invitations
-		invitations, err := env.DB.GetPendingInvitationsByEmail(childEmail)
+		invitations, err := env.DB.GetPendingInvitationsByEmail(t.Context(), childEmail)
 		require.NoError(t, err)
 		require.Len(t, invitations, 1, "Child should have exactly one pending invitation")
 		assert.Equal(t, childEmail, invitations[0].Email)
@@ -120,7 +120,7 @@ func TestChildInvitationFlow_Integration(t *testing.T) {
 		env.AssertRowCount("family_invitations", initialInvitations+3)
 
 		for _, email := range childEmails {
-			invitations, err := env.DB.GetPendingInvitationsByEmail(email)
+			invitations, err := env.DB.GetPendingInvitationsByEmail(t.Context(), email)
 			require.NoError(t, err)
 			require.Len(t, invitations, 1, "Child %s should have one invitation", email)
 		}
@@ -140,17 +140,17 @@ func TestChildInvitationFlow_Integration(t *testing.T) {
 		require.Equal(t, http.StatusCreated, resp.Code)
 
 		// Verify invitation was created
-		invitations, err := env.DB.GetPendingInvitationsByEmail(childEmail)
+		invitations, err := env.DB.GetPendingInvitationsByEmail(t.Context(), childEmail)
 		require.NoError(t, err)
 		require.Len(t, invitations, 1)
 		invitationID := invitations[0].ID
 
 		// Delete the invitation
-		err = env.DB.DeleteInvitation(invitationID)
+		err = env.DB.DeleteInvitation(t.Context(), invitationID)
 		require.NoError(t, err)
 
 		// Verify invitation was deleted
-		invitations, err = env.DB.GetPendingInvitationsByEmail(childEmail)
+		invitations, err = env.DB.GetPendingInvitationsByEmail(t.Context(), childEmail)
 		require.NoError(t, err)
 		assert.Len(t, invitations, 0, "Invitation should be deleted")
 	})
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services/digest"
+)
+
+// @Summary		Get Digest Preferences
+// @Description	Get the current parent's weekly email digest settings. Parents are opted out by default.
+// @Tags			families
+// @Accept			json
+// @Produce		json
+// @Success		200	{object}	models.APIResponse{data=models.DigestPreferences}	"Digest preferences"
+// @Failure		500	{object}	models.APIResponse	"Failed to retrieve digest preferences"
+// @Security		BearerAuth
+// @Router			/api/families/digest [get]
+func GetDigestPreferences(c *gin.Context) {
+	serviceManager := GetServiceManager(c)
+	if serviceManager == nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Service unavailable",
+		})
+		return
+	}
+
+	userID, err := getContextString(c, "userID")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	prefs, err := serviceManager.DB.GetDigestPreferences(c.Request.Context(), userID)
+	if err != nil {
+		logger().ErrorContext(c.Request.Context(), "Error getting digest preferences", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Failed to retrieve digest preferences",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Data: prefs,
+	})
+}
+
+// @Summary		Update Digest Preferences
+// @Description	Opt in or out of the weekly email digest and choose its language ("no" or "en")
+// @Tags			families
+// @Accept			json
+// @Produce		json
+// @Param			request	body		models.UpdateDigestPreferencesRequest	true	"Digest settings"
+// @Success		200		{object}	models.APIResponse{data=models.DigestPreferences}	"Digest preferences updated"
+// @Failure		400		{object}	models.APIResponse	"Invalid request"
+// @Failure		500		{object}	models.APIResponse	"Failed to update digest preferences"
+// @Security		BearerAuth
+// @Router			/api/families/digest [put]
+func UpdateDigestPreferences(c *gin.Context) {
+	serviceManager := GetServiceManager(c)
+	if serviceManager == nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Service unavailable",
+		})
+		return
+	}
+
+	var req models.UpdateDigestPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Error: "Invalid request data",
+		})
+		return
+	}
+
+	userID, err := getContextString(c, "userID")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	prefs, err := serviceManager.DB.GetDigestPreferences(c.Request.Context(), userID)
+	if err != nil {
+		logger().ErrorContext(c.Request.Context(), "Error getting digest preferences", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Failed to update digest preferences",
+		})
+		return
+	}
+
+	if req.Enabled != nil {
+		prefs.Enabled = *req.Enabled
+	}
+	if req.Language != nil {
+		prefs.Language = *req.Language
+	}
+
+	if err := serviceManager.DB.UpsertDigestPreferences(c.Request.Context(), prefs); err != nil {
+		logger().ErrorContext(c.Request.Context(), "Error updating digest preferences", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Failed to update digest preferences",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Data:    prefs,
+		Message: "Digest preferences updated",
+	})
+}
+
+// @Summary		Preview Digest
+// @Description	Render the weekly digest the current parent would receive now, without sending it
+// @Tags			families
+// @Accept			json
+// @Produce		json
+// @Param			language	query		string	false	"Language (no or en), defaults to the saved preference"
+// @Success		200			{object}	models.APIResponse{data=models.DigestPreview}	"Rendered digest"
+// @Failure		500			{object}	models.APIResponse	"Failed to render digest"
+// @Security		BearerAuth
+// @Router			/api/families/digest/preview [get]
+func PreviewDigest(c *gin.Context) {
+	serviceManager := GetServiceManager(c)
+	if serviceManager == nil || serviceManager.Digest == nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Service unavailable",
+		})
+		return
+	}
+
+	userID, err := getContextString(c, "userID")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	familyID, err := getContextString(c, "validatedFamilyID")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid family ID"})
+		return
+	}
+
+	user, err := serviceManager.DB.GetUser(c.Request.Context(), userID)
+	if err != nil {
+		logger().ErrorContext(c.Request.Context(), "Error getting user for digest preview", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Failed to render digest",
+		})
+		return
+	}
+
+	language := c.Query("language")
+	if language == "" {
+		prefs, err := serviceManager.DB.GetDigestPreferences(c.Request.Context(), userID)
+		if err != nil {
+			logger().ErrorContext(c.Request.Context(), "Error getting digest preferences", "user_id", userID, "error", err)
+			c.JSON(http.StatusInternalServerError, models.APIResponse{
+				Error: "Failed to render digest",
+			})
+			return
+		}
+		language = prefs.Language
+	}
+
+	d, err := serviceManager.Digest.Compose(c.Request.Context(), models.DigestRecipient{
+		UserID:      user.ID,
+		Email:       user.Email,
+		DisplayName: user.DisplayName,
+		FamilyID:    familyID,
+		Language:    language,
+	}, time.Now())
+	if err != nil {
+		logger().ErrorContext(c.Request.Context(), "Error composing digest preview", "family_id", familyID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Failed to render digest",
+		})
+		return
+	}
+
+	msg, err := digest.Render(d, language)
+	if err != nil {
+		logger().ErrorContext(c.Request.Context(), "Error rendering digest preview", "family_id", familyID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Failed to render digest",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Data: models.DigestPreview{
+			Subject: msg.Subject,
+			Text:    msg.Text,
+			HTML:    msg.HTML,
+		},
+	})
+}
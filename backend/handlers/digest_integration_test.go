@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services/digest"
+	"github.com/starefossen/diktator/backend/internal/services/mail"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type capturingMailer struct {
+	sent []*mail.Message
+}
+
+func (m *capturingMailer) Send(_ context.Context, msg *mail.Message) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func TestDigest_Integration(t *testing.T) {
+	env := SetupIntegrationTest(t)
+	defer env.Cleanup()
+
+	parent := env.CreateTestUser("", "parent")
+	familyID := env.CreateTestFamily(parent.ID)
+	parent.FamilyID = familyID
+	_, err := env.Pool.Exec(context.Background(), `UPDATE users SET family_id = $1 WHERE id = $2`, familyID, parent.ID)
+	require.NoError(t, err)
+
+	child := env.CreateTestUser(familyID, "child")
+	wordSet := env.CreateTestWordSet(familyID, parent.ID)
+	require.NoError(t, env.DB.SaveTestResult(t.Context(), &models.TestResult{
+		ID:           uuid.New().String(),
+		WordSetID:    wordSet.ID,
+		UserID:       child.ID,
+		Mode:         string(models.TestModeKeyboard),
+		Score:        75,
+		TotalWords:   2,
+		CorrectWords: 1,
+		TimeSpent:    90,
+		XPAwarded:    15,
+		CompletedAt:  time.Now().Add(-time.Hour),
+		Words: []models.WordTestResult{
+			{Word: "skjære", UserAnswers: []string{"sjære", "skjære"}, FinalAnswer: "skjære", Attempts: 2, Correct: true},
+			{Word: "hjem", UserAnswers: []string{"hjem"}, FinalAnswer: "hjem", Attempts: 1, Correct: true},
+		},
+	}))
+
+	mailer := &capturingMailer{}
+	env.ServiceManager.Digest = digest.NewService(env.DB, mailer, digest.DefaultConfig())
+
+	env.SetupAuthMiddleware(parent)
+	env.Router.GET("/api/families/digest", GetDigestPreferences)
+	env.Router.PUT("/api/families/digest", UpdateDigestPreferences)
+	env.Router.GET("/api/families/digest/preview", PreviewDigest)
+
+	// due claims the recipients due before sentBefore and releases them again,
+	// so checking who is due leaves the scheduler's state untouched
+	due := func(t *testing.T, sentBefore time.Time) []models.DigestRecipient {
+		t.Helper()
+		claimedAt := time.Now()
+		recipients, err := env.DB.ClaimDueDigestRecipients(t.Context(), sentBefore, claimedAt)
+		require.NoError(t, err)
+		for _, r := range recipients {
+			require.NoError(t, env.DB.ReleaseDigestClaim(t.Context(), r.UserID, claimedAt, r.LastSentAt))
+		}
+		return recipients
+	}
+
+	t.Run("Defaults_OptedOut", func(t *testing.T) {
+		resp := makeRequest(env.Router, "GET", "/api/families/digest", nil, nil)
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var body struct {
+			Data models.DigestPreferences `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		assert.False(t, body.Data.Enabled)
+		assert.Equal(t, "no", body.Data.Language)
+
+		assert.Empty(t, due(t, time.Now()))
+	})
+
+	t.Run("Update_RejectsUnknownLanguage", func(t *testing.T) {
+		resp := makeRequest(env.Router, "PUT", "/api/families/digest", map[string]any{"language": "de"}, nil)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
+	t.Run("OptIn_MakesParentDue", func(t *testing.T) {
+		resp := makeRequest(env.Router, "PUT", "/api/families/digest", map[string]any{"enabled": true, "language": "en"}, nil)
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		recipients := due(t, time.Now())
+		require.Len(t, recipients, 1)
+		assert.Equal(t, parent.Email, recipients[0].Email)
+		assert.Equal(t, "en", recipients[0].Language)
+	})
+
+	t.Run("Preview_RendersChildActivity", func(t *testing.T) {
+		resp := makeRequest(env.Router, "GET", "/api/families/digest/preview?language=no", nil, nil)
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var body struct {
+			Data models.DigestPreview `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		assert.Contains(t, body.Data.Text, "Prøver fullført: 1")
+		assert.Contains(t, body.Data.Text, "skjære (1x)")
+		assert.Contains(t, body.Data.Text, "XP opptjent: 15")
+	})
+
+	t.Run("SendDue_SendsOncePerWeek", func(t *testing.T) {
+		n, err := env.ServiceManager.Digest.SendDue(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+		require.Len(t, mailer.sent, 1)
+		assert.Equal(t, []string{parent.Email}, mailer.sent[0].To)
+		assert.Contains(t, mailer.sent[0].Subject, "weekly summary")
+
+		n, err = env.ServiceManager.Digest.SendDue(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 0, n)
+	})
+
+	t.Run("OptOut_StopsDigest", func(t *testing.T) {
+		resp := makeRequest(env.Router, "PUT", "/api/families/digest", map[string]any{"enabled": false}, nil)
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		assert.Empty(t, due(t, time.Now().Add(30*24*time.Hour)))
+	})
+}
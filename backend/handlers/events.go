@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services"
+	"github.com/starefossen/diktator/backend/internal/services/events"
+	"github.com/starefossen/diktator/backend/internal/services/realtime"
+)
+
+// publishEvent publishes a domain event if an event bus is configured.
+// Publishing never fails the request; subscribers log their own errors.
+func publishEvent(c *gin.Context, sm *services.Manager, event events.Event) {
+	if sm == nil || sm.Events == nil || event.FamilyID == "" {
+		return
+	}
+	sm.Events.Publish(c.Request.Context(), event)
+}
+
+// publishLiveEvent streams a live-only event to connected family dashboards.
+// Live events skip the event bus, so they never reach webhooks or the inbox.
+func publishLiveEvent(c *gin.Context, sm *services.Manager, event events.Event) {
+	if sm == nil || sm.Realtime == nil || event.FamilyID == "" {
+		return
+	}
+	if err := sm.Realtime.Publish(c.Request.Context(), event); err != nil {
+		logger().WarnContext(c.Request.Context(), "Failed to publish live event", "event_type", event.Type, "error", err)
+	}
+}
+
+// publishResultEvents emits test.completed and, when applicable, xp.level_up for a saved result,
+// plus a live xp.awarded event for dashboards
+func publishResultEvents(c *gin.Context, sm *services.Manager, familyID string, result *models.TestResult, xpInfo *models.XPInfo) {
+	publishEvent(c, sm, events.New(events.TypeTestCompleted, familyID, result.UserID, map[string]any{
+		"resultId":     result.ID,
+		"wordSetId":    result.WordSetID,
+		"mode":         result.Mode,
+		"score":        result.Score,
+		"totalWords":   result.TotalWords,
+		"correctWords": result.CorrectWords,
+		"timeSpent":    result.TimeSpent,
+		"xpAwarded":    result.XPAwarded,
+		"completedAt":  result.CompletedAt,
+	}))
+
+	if xpInfo != nil && xpInfo.Awarded > 0 {
+		publishLiveEvent(c, sm, events.New(realtime.TypeXPAwarded, familyID, result.UserID, map[string]any{
+			"resultId": result.ID,
+			"awarded":  xpInfo.Awarded,
+			"totalXp":  xpInfo.Total,
+			"level":    xpInfo.Level,
+		}))
+	}
+
+	if xpInfo != nil && xpInfo.LevelUp {
+		publishEvent(c, sm, events.New(events.TypeLevelUp, familyID, result.UserID, map[string]any{
+			"previousLevel": xpInfo.PreviousLevel,
+			"level":         xpInfo.Level,
+			"levelName":     xpInfo.LevelName,
+			"levelNameNo":   xpInfo.LevelNameNO,
+			"totalXp":       xpInfo.Total,
+		}))
+	}
+}
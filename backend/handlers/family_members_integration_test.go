@@ -65,7 +65,7 @@ func TestAddFamilyMember_Integration(t *testing.T) {
 		env.AssertUserRowCount(1)
 
 		// Verify the invitation exists in the database
-		invitations, err := env.DB.GetPendingInvitationsByEmail("child@example.com")
+		invitations, err := env.DB.GetPendingInvitationsByEmail(t.Context(), "child@example.com")
 		require.NoError(t, err)
 		require.Len(t, invitations, 1)
 		assert.Equal(t, "child@example.com", invitations[0].Email)
@@ -159,7 +159,7 @@ func TestAddFamilyMember_Integration(t *testing.T) {
 		env.AssertRowCount("family_invitations", currentCount+1)
 
 		// Verify the invitation exists in the database
-		invitations, err := env.DB.GetPendingInvitationsByEmail("parent3@example.com")
+		invitations, err := env.DB.GetPendingInvitationsByEmail(t.Context(), "parent3@example.com")
 		require.NoError(t, err)
 		require.Len(t, invitations, 1)
 		assert.Equal(t, "parent3@example.com", invitations[0].Email)
@@ -1,19 +1,23 @@
 package handlers
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/starefossen/diktator/backend/internal/logging"
+	"github.com/starefossen/diktator/backend/internal/metrics"
 	"github.com/starefossen/diktator/backend/internal/models"
 	"github.com/starefossen/diktator/backend/internal/services"
 	"github.com/starefossen/diktator/backend/internal/services/auth"
 	"github.com/starefossen/diktator/backend/internal/services/db"
+	"github.com/starefossen/diktator/backend/internal/services/events"
 	"github.com/starefossen/diktator/backend/internal/services/xp"
 )
 
@@ -25,6 +29,15 @@ func InitializeServices(manager *services.Manager) error {
 	return nil
 }
 
+// logger returns the API logger. Records logged with a request context carry
+// the request's ID.
+func logger() *slog.Logger {
+	if serviceManager == nil {
+		return logging.Subsystem(nil, "api")
+	}
+	return logging.Subsystem(serviceManager.Logger, "api")
+}
+
 // CloseServices closes all services
 func CloseServices() error {
 	if serviceManager != nil {
@@ -74,12 +87,19 @@ func GetServiceManager(c *gin.Context) *services.Manager {
 }
 
 // @Summary		Get Word Sets
-// @Description	Get word sets for the authenticated user's family
+// @Description	Get word sets for the authenticated user's family, newest first. Pass limit or cursor to page through them; nextCursor is set while more pages follow.
 // @Tags			wordsets
 // @Accept			json
 // @Produce		json
-// @Success		200	{object}	models.APIResponse	"Word sets for the family"
-// @Failure		401	{object}	models.APIResponse	"Family access validation required"
+// @Param			search		query		string	false	"Case-insensitive match on the word set name"
+// @Param			grade		query		string	false	"Target grade (1-2, 3-4 or 5-7)"
+// @Param			difficulty	query		string	false	"Difficulty (beginner, intermediate or advanced)"
+// @Param			language	query		string	false	"Language code"
+// @Param			limit		query		int		false	"Page size (max 200)"
+// @Param			cursor		query		string	false	"nextCursor from the previous page"
+// @Success		200			{object}	models.APIResponse	"Word sets for the family"
+// @Failure		400			{object}	models.APIResponse	"Invalid filter, limit or cursor"
+// @Failure		401			{object}	models.APIResponse	"Family access validation required"
 // @Failure		500	{object}	models.APIResponse	"Service unavailable or failed to retrieve word sets"
 // @Security		BearerAuth
 // @Router			/api/wordsets [get]
@@ -101,15 +121,12 @@ func GetWordSets(c *gin.Context) {
 		return
 	}
 
-	userID, exists := c.Get("userID")
-	if !exists {
+	if _, exists := c.Get("userID"); !exists {
 		c.JSON(http.StatusUnauthorized, models.APIResponse{
 			Error: "User authentication required",
 		})
 		return
 	}
-	log.Default().Printf("User ID: %s", userID)
-	log.Default().Printf("Family ID: %s", familyID)
 
 	familyIDStr, ok := familyID.(string)
 	if !ok {
@@ -119,17 +136,17 @@ func GetWordSets(c *gin.Context) {
 		return
 	}
 
-	wordSets, err := serviceManager.DB.GetWordSets(familyIDStr)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
-			Error: "Failed to retrieve word sets",
-		})
+	filter, ok := parseWordSetFilter(c)
+	if !ok {
+		return
+	}
+	page, ok := parsePageRequest(c)
+	if !ok {
 		return
 	}
 
-	c.JSON(http.StatusOK, models.APIResponse{
-		Data: wordSets,
-	})
+	wordSets, next, err := serviceManager.DB.ListWordSets(c.Request.Context(), familyIDStr, filter, page)
+	respondListing(c, wordSets, next, err, "word sets")
 }
 
 // @Summary		Get Curated Word Sets
@@ -150,7 +167,7 @@ func GetCuratedWordSets(c *gin.Context) {
 		return
 	}
 
-	wordSets, err := serviceManager.DB.GetGlobalWordSets()
+	wordSets, err := serviceManager.DB.GetGlobalWordSets(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Error: "Failed to retrieve curated word sets",
@@ -251,6 +268,8 @@ func CreateWordSet(c *gin.Context) {
 		CreatedBy:         userIDStr,
 		Language:          req.Language,
 		TestConfiguration: req.TestConfiguration,
+		TargetGrade:       req.TargetGrade,
+		Difficulty:        req.Difficulty,
 		CreatedAt:         time.Now(),
 		UpdatedAt:         time.Now(),
 	}
@@ -270,7 +289,7 @@ func CreateWordSet(c *gin.Context) {
 		})
 	}
 
-	err := serviceManager.DB.CreateWordSet(wordSet)
+	err := serviceManager.DB.CreateWordSet(c.Request.Context(), wordSet)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Error: "Failed to create word set",
@@ -343,7 +362,7 @@ func UpdateWordSet(c *gin.Context) {
 	}
 
 	// Get existing word set to check ownership and get current state
-	existingWordSet, err := serviceManager.DB.GetWordSet(id)
+	existingWordSet, err := serviceManager.DB.GetWordSet(c.Request.Context(), id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, models.APIResponse{
 			Error: "Word set not found",
@@ -419,6 +438,8 @@ func UpdateWordSet(c *gin.Context) {
 		CreatedBy:         existingWordSet.CreatedBy,
 		Language:          req.Language,
 		TestConfiguration: req.TestConfiguration,
+		TargetGrade:       req.TargetGrade,
+		Difficulty:        req.Difficulty,
 		CreatedAt:         existingWordSet.CreatedAt,
 		UpdatedAt:         time.Now(),
 	}
@@ -440,7 +461,7 @@ func UpdateWordSet(c *gin.Context) {
 
 	// Audio is now generated on-demand, no pre-generation status tracking needed
 
-	err = serviceManager.DB.UpdateWordSet(updatedWordSet)
+	err = serviceManager.DB.UpdateWordSet(c.Request.Context(), updatedWordSet)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Error: "Failed to update word set",
@@ -486,7 +507,7 @@ func DeleteWordSet(c *gin.Context) {
 	}
 
 	// Check if word set is global (curated) - prevent deletion
-	isGlobal, err := sm.DB.IsGlobalWordSet(id)
+	isGlobal, err := sm.DB.IsGlobalWordSet(c.Request.Context(), id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, models.APIResponse{
 			Error: "Word set not found",
@@ -500,7 +521,7 @@ func DeleteWordSet(c *gin.Context) {
 		return
 	}
 
-	err = sm.DB.DeleteWordSet(id)
+	err = sm.DB.DeleteWordSet(c.Request.Context(), id)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Error: "Failed to delete word set",
@@ -514,12 +535,13 @@ func DeleteWordSet(c *gin.Context) {
 }
 
 // @Summary		Assign Word Set to User
-// @Description	Assign a word set to a child user (parent only)
+// @Description	Assign a word set to a child user (parent only). An optional body sets a due date, practice goal and reminder schedule; posting again updates them.
 // @Tags			wordsets
 // @Accept			json
 // @Produce		json
-// @Param			id		path		string				true	"Word set ID"
-// @Param			userId	path		string				true	"Child user ID"
+// @Param			id		path		string						true	"Word set ID"
+// @Param			userId	path		string						true	"Child user ID"
+// @Param			request	body		models.AssignmentSchedule	false	"Due date, goal and reminders"
 // @Success		200		{object}	models.APIResponse	"Word set assigned successfully"
 // @Failure		400		{object}	models.APIResponse	"Invalid request"
 // @Failure		403		{object}	models.APIResponse	"Parent role required"
@@ -545,6 +567,25 @@ func AssignWordSetToUser(c *gin.Context) {
 		return
 	}
 
+	// The schedule body is optional; assignments without one have no due date or reminders
+	var schedule *models.AssignmentSchedule
+	if c.Request.ContentLength > 0 {
+		var req models.AssignmentSchedule
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Error: "Invalid assignment schedule",
+			})
+			return
+		}
+		if req.Goal != nil && req.Goal.Mode != "" && !models.IsValidTestMode(req.Goal.Mode) {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Error: "Invalid goal mode: " + req.Goal.Mode,
+			})
+			return
+		}
+		schedule = &req
+	}
+
 	// Get the authenticated user ID (parent making the assignment)
 	_, exists := c.Get("userID")
 	if !exists {
@@ -564,7 +605,7 @@ func AssignWordSetToUser(c *gin.Context) {
 	}
 
 	// Verify the child belongs to the family
-	childUser, err := sm.DB.GetUser(userID)
+	childUser, err := sm.DB.GetUser(c.Request.Context(), userID)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, models.APIResponse{
 			Error: "Child user not found",
@@ -589,7 +630,7 @@ func AssignWordSetToUser(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
 		return
 	}
-	err = sm.DB.AssignWordSetToUser(wordSetID, userID, assignedByStr)
+	err = sm.DB.AssignWordSetToUser(c.Request.Context(), wordSetID, userID, assignedByStr)
 	if err != nil {
 		// Check for specific error types to return appropriate status codes
 		errMsg := err.Error()
@@ -605,6 +646,28 @@ func AssignWordSetToUser(c *gin.Context) {
 		return
 	}
 
+	eventData := map[string]any{
+		"wordSetId":  wordSetID,
+		"assignedBy": assignedByStr,
+	}
+	if schedule != nil {
+		if err := sm.DB.SetWordSetAssignmentSchedule(c.Request.Context(), wordSetID, userID, schedule); err != nil {
+			logger().ErrorContext(c.Request.Context(), "Error setting assignment schedule", "wordset_id", wordSetID, "user_id", userID, "error", err)
+			c.JSON(http.StatusInternalServerError, models.APIResponse{
+				Error: "Failed to save assignment schedule",
+			})
+			return
+		}
+		if schedule.DueAt != nil {
+			eventData["dueAt"] = schedule.DueAt
+		}
+		if schedule.Goal != nil {
+			eventData["goal"] = schedule.Goal
+		}
+	}
+
+	publishEvent(c, sm, events.New(events.TypeWordSetAssigned, familyIDStr, userID, eventData))
+
 	c.JSON(http.StatusOK, models.APIResponse{
 		Message: "Word set assigned successfully",
 	})
@@ -651,7 +714,7 @@ func UnassignWordSetFromUser(c *gin.Context) {
 		return
 	}
 
-	childUser, err := sm.DB.GetUser(userID)
+	childUser, err := sm.DB.GetUser(c.Request.Context(), userID)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, models.APIResponse{
 			Error: "Child user not found",
@@ -671,7 +734,7 @@ func UnassignWordSetFromUser(c *gin.Context) {
 		return
 	}
 
-	err = sm.DB.UnassignWordSetFromUser(wordSetID, userID)
+	err = sm.DB.UnassignWordSetFromUser(c.Request.Context(), wordSetID, userID)
 	if err != nil {
 		if err.Error() == "assignment not found" {
 			c.JSON(http.StatusNotFound, models.APIResponse{
@@ -691,11 +754,14 @@ func UnassignWordSetFromUser(c *gin.Context) {
 }
 
 // @Summary		Save Test Result
-// @Description	Save a test result for the authenticated user
+// @Description	Save a test result for the authenticated user. Send an idempotency key (in the body or the
+// @Description	Idempotency-Key header) to make retries safe: a replayed key returns the saved result with 200.
 // @Tags			users
 // @Accept			json
 // @Produce		json
-// @Param			request	body		models.SaveResultRequest	true	"Test result data"
+// @Param			request			body		models.SaveResultRequest	true	"Test result data"
+// @Param			Idempotency-Key	header		string						false	"Client-generated key for replay-safe saves"
+// @Success		200		{object}	models.APIResponse			"Test result already saved"
 // @Success		201		{object}	models.APIResponse			"Test result saved successfully"
 // @Failure		400		{object}	models.APIResponse			"Invalid request data"
 // @Failure		401		{object}	models.APIResponse			"User authentication required"
@@ -728,92 +794,37 @@ func SaveResult(c *gin.Context) {
 		return
 	}
 
-	// Validate translation mode requirements
-	if req.Mode == "translation" {
-		// Get the wordset to check if translations exist
-		wordSet, err := serviceManager.DB.GetWordSet(req.WordSetID)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, models.APIResponse{
-				Error: "Failed to validate wordset for translation mode",
-			})
-			return
-		}
-
-		// Check if any word has translations
-		hasTranslations := false
-		for _, word := range wordSet.Words {
-			if len(word.Translations) > 0 {
-				hasTranslations = true
-				break
-			}
-		}
-
-		if !hasTranslations {
-			c.JSON(http.StatusBadRequest, models.APIResponse{
-				Error: "Cannot save translation mode result: wordset has no translations",
-			})
-			return
-		}
-	}
-
-	// Create test result
 	userIDStr, err := getContextString(c, "userID")
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
 		return
 	}
-	result := &models.TestResult{
-		ID:             uuid.New().String(),
-		WordSetID:      req.WordSetID,
-		UserID:         userIDStr,
-		Score:          req.Score,
-		TotalWords:     req.TotalWords,
-		CorrectWords:   req.CorrectWords,
-		Mode:           req.Mode,
-		IncorrectWords: req.IncorrectWords, // Keep for backward compatibility
-		Words:          req.Words,          // New detailed word information
-		TimeSpent:      req.TimeSpent,
-		CompletedAt:    time.Now(),
-		CreatedAt:      time.Now(),
-	}
 
-	// Calculate and award XP before saving the result
-	var xpInfo *models.XPInfo
-	if serviceManager.XP != nil {
-		xpResult, err := serviceManager.XP.AwardXP(userIDStr, result)
-		if err != nil {
-			log.Printf("[SaveResult] Warning: failed to award XP for user %s: %v", userIDStr, err)
-			// Continue without XP - don't fail the whole request
-		} else {
-			result.XPAwarded = xpResult.Awarded
-			xpInfo = &models.XPInfo{
-				Awarded:        xpResult.Awarded,
-				Total:          xpResult.Total,
-				Level:          xpResult.Level,
-				LevelName:      xpResult.LevelName,
-				LevelNameNO:    xpResult.LevelNameNO,
-				LevelIconPath:  xpResult.LevelIconPath,
-				LevelUp:        xpResult.LevelUp,
-				PreviousLevel:  xpResult.PreviousLevel,
-				NextLevelXP:    xpResult.NextLevelXP,
-				CurrentLevelXP: xpResult.CurrentLevelXP,
-			}
-		}
+	if req.IdempotencyKey == "" {
+		req.IdempotencyKey = c.GetHeader("Idempotency-Key")
 	}
 
-	err = serviceManager.DB.SaveTestResult(result)
+	response, created, err := saveResultRequest(c, serviceManager, userIDStr, req, time.Now())
 	if err != nil {
-		log.Printf("[SaveResult] Error saving test result for user %s, wordset %s: %v", userIDStr, req.WordSetID, err)
+		if msg, ok := resultValidationError(err); ok {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Error: msg,
+			})
+			return
+		}
+		logger().ErrorContext(c.Request.Context(), "Error saving test result", "user_id", userIDStr, "wordset_id", req.WordSetID, "error", err)
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Error: "Failed to save test result",
 		})
 		return
 	}
 
-	// Return response with XP info
-	response := models.SaveResultResponse{
-		TestResult: result,
-		XP:         xpInfo,
+	if !created {
+		c.JSON(http.StatusOK, models.APIResponse{
+			Data:    response,
+			Message: "Test result already saved",
+		})
+		return
 	}
 
 	c.JSON(http.StatusCreated, models.APIResponse{
@@ -822,14 +833,80 @@ func SaveResult(c *gin.Context) {
 	})
 }
 
+// recordTestResult grades sentences, awards XP, saves a finished test and publishes
+// its events. XP and the result are committed together, so a failed save grants no
+// XP. XP failures are logged and the result is saved without XP.
+func recordTestResult(c *gin.Context, sm *services.Manager, result *models.TestResult) (*models.SaveResultResponse, error) {
+	ctx := c.Request.Context()
+	gradeSentences(ctx, sm, result)
+
+	var xpInfo *models.XPInfo
+	err := sm.DB.WithTx(ctx, func(tx db.Repository) error {
+		if sm.XP != nil {
+			xpInfo = awardXP(ctx, sm.XP, tx, result)
+		}
+		return tx.SaveTestResult(ctx, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	metrics.RecordTestSaved(result.Mode, result.XPAwarded)
+
+	if familyID, err := getContextString(c, "validatedFamilyID"); err == nil {
+		publishResultEvents(c, sm, familyID, result, xpInfo)
+	}
+
+	return &models.SaveResultResponse{
+		TestResult: result,
+		XP:         xpInfo,
+	}, nil
+}
+
+// awardXP awards XP for a result inside tx. It runs in a nested transaction so a
+// failure rolls back only the XP update and the result can still be saved.
+func awardXP(ctx context.Context, svc *xp.Service, tx db.Repository, result *models.TestResult) *models.XPInfo {
+	var xpResult *xp.XPResult
+	err := tx.WithTx(ctx, func(xtx db.Repository) error {
+		var err error
+		xpResult, err = svc.WithRepository(xtx).AwardXP(ctx, result.UserID, result)
+		return err
+	})
+	if err != nil {
+		logger().WarnContext(ctx, "Failed to award XP", "user_id", result.UserID, "error", err)
+		return nil
+	}
+
+	result.XPAwarded = xpResult.Awarded
+	return &models.XPInfo{
+		Awarded:        xpResult.Awarded,
+		Total:          xpResult.Total,
+		Level:          xpResult.Level,
+		LevelName:      xpResult.LevelName,
+		LevelNameNO:    xpResult.LevelNameNO,
+		LevelIconPath:  xpResult.LevelIconPath,
+		LevelUp:        xpResult.LevelUp,
+		PreviousLevel:  xpResult.PreviousLevel,
+		NextLevelXP:    xpResult.NextLevelXP,
+		CurrentLevelXP: xpResult.CurrentLevelXP,
+	}
+}
+
 // @Summary		Get Test Results
-// @Description	Get test results for the authenticated user
+// @Description	Get test results for the authenticated user, newest first. Pass limit or cursor to page through them; nextCursor is set while more pages follow.
 // @Tags			users
 // @Accept			json
 // @Produce		json
-// @Success		200	{object}	models.APIResponse	"Test results"
-// @Failure		401	{object}	models.APIResponse	"User authentication required"
-// @Failure		500	{object}	models.APIResponse	"Failed to retrieve test results"
+// @Param			from		query		string	false	"Completed at or after (YYYY-MM-DD or RFC 3339)"
+// @Param			to			query		string	false	"Completed before (YYYY-MM-DD or RFC 3339)"
+// @Param			mode		query		string	false	"Test mode"
+// @Param			wordSetId	query		string	false	"Word set ID"
+// @Param			minScore	query		number	false	"Minimum score (0-100)"
+// @Param			limit		query		int		false	"Page size (max 200)"
+// @Param			cursor		query		string	false	"nextCursor from the previous page"
+// @Success		200			{object}	models.APIResponse	"Test results"
+// @Failure		400			{object}	models.APIResponse	"Invalid filter, limit or cursor"
+// @Failure		401			{object}	models.APIResponse	"User authentication required"
+// @Failure		500			{object}	models.APIResponse	"Failed to retrieve test results"
 // @Security		BearerAuth
 // @Router			/api/users/results [get]
 func GetResults(c *gin.Context) {
@@ -855,17 +932,18 @@ func GetResults(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
 		return
 	}
-	results, err := serviceManager.DB.GetTestResults(userIDStr)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
-			Error: "Failed to retrieve test results",
-		})
+	filter, ok := parseResultFilter(c)
+	if !ok {
 		return
 	}
+	page, ok := parsePageRequest(c)
+	if !ok {
+		return
+	}
+	filter.UserID = userIDStr
 
-	c.JSON(http.StatusOK, models.APIResponse{
-		Data: results,
-	})
+	results, next, err := serviceManager.DB.ListTestResults(c.Request.Context(), filter, page)
+	respondListing(c, results, next, err, "test results")
 }
 
 // Invitation handlers
@@ -909,16 +987,16 @@ func GetPendingInvitations(c *gin.Context) {
 	if email == "" {
 		// Email not in token - return empty array instead of error
 		// This allows the flow to continue to registration
-		log.Printf("[GetPendingInvitations] Email not in token for auth_id=%s, returning empty invitations list", authIdentity.ID)
+		logger().InfoContext(c.Request.Context(), "Email not in token, returning empty invitations list", "auth_id", authIdentity.ID)
 		c.JSON(http.StatusOK, models.APIResponse{
 			Data: []models.FamilyInvitation{},
 		})
 		return
 	}
 
-	invitations, err := serviceManager.DB.GetPendingInvitationsByEmail(email)
+	invitations, err := serviceManager.DB.GetPendingInvitationsByEmail(c.Request.Context(), email)
 	if err != nil {
-		log.Printf("ERROR getting pending invitations: %v (email=%s)", err, email)
+		logger().ErrorContext(c.Request.Context(), "Error getting pending invitations", "email", email, "error", err)
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Error: "Failed to retrieve invitations",
 		})
@@ -990,7 +1068,7 @@ func AcceptInvitation(c *gin.Context) {
 		}
 
 		// Get the invitation to find the existing child account
-		invitations, err := serviceManager.DB.GetPendingInvitationsByEmail(authIdentity.Email)
+		invitations, err := serviceManager.DB.GetPendingInvitationsByEmail(c.Request.Context(), authIdentity.Email)
 		if err != nil || len(invitations) == 0 {
 			c.JSON(http.StatusNotFound, models.APIResponse{
 				Error: "No pending invitations found for your email",
@@ -1015,10 +1093,11 @@ func AcceptInvitation(c *gin.Context) {
 		}
 
 		// Check if user already exists (in case they were created before the code fix)
-		existingUser, err := serviceManager.DB.GetUserByEmail(authIdentity.Email)
+		existingUser, err := serviceManager.DB.GetUserByEmail(c.Request.Context(), authIdentity.Email)
 		if err != nil && err != db.ErrUserNotFound {
+			logger().ErrorContext(c.Request.Context(), "Error checking invited user", "id", invitationID, "error", err)
 			c.JSON(http.StatusInternalServerError, models.APIResponse{
-				Error: "Failed to check user: " + err.Error(),
+				Error: "Failed to check user",
 			})
 			return
 		}
@@ -1029,26 +1108,17 @@ func AcceptInvitation(c *gin.Context) {
 			return
 		}
 
-		var newUserID string
-
+		var newUser *models.User
+		newUserID := authIDStr
 		if existingUser != nil {
 			// User already exists (legacy flow) - just link the auth ID
-			if err := serviceManager.DB.LinkUserToAuthID(existingUser.ID, authIDStr); err != nil {
-				log.Printf("ERROR linking user to auth ID: %v (userID=%s, authID=%s)", err, existingUser.ID, authIDStr)
-				c.JSON(http.StatusInternalServerError, models.APIResponse{
-					Error: "Failed to link account: " + err.Error(),
-				})
-				return
-			}
 			newUserID = existingUser.ID
 		} else {
 			// Create new user with their auth ID as the user ID
-			newUserID = authIDStr
-
 			// Use email prefix as display name (user can update later)
 			displayName := strings.Split(authIdentity.Email, "@")[0]
 
-			newUser := &models.User{
+			newUser = &models.User{
 				ID:           newUserID,
 				AuthID:       newUserID,
 				Email:        authIdentity.Email,
@@ -1065,31 +1135,21 @@ func AcceptInvitation(c *gin.Context) {
 				parentID := targetInvitation.InvitedBy
 				newUser.ParentID = &parentID
 			}
-
-			if err := serviceManager.DB.CreateUser(newUser); err != nil {
-				log.Printf("ERROR creating user: %v (authID=%s, email=%s)", err, newUserID, authIdentity.Email)
-				c.JSON(http.StatusInternalServerError, models.APIResponse{
-					Error: "Failed to create account: " + err.Error(),
-				})
-				return
-			}
 		}
 
-		// Now accept the invitation
-		if err := serviceManager.DB.AcceptInvitation(invitationID, newUserID); err != nil {
-			log.Printf("ERROR accepting invitation: %v (id=%s, user=%s)", err, invitationID, newUserID)
-			// Clean up the user if we just created them
-			if existingUser == nil {
-				if delErr := serviceManager.DB.DeleteUser(newUserID); delErr != nil {
-					log.Printf("Warning: failed to delete temporary user %s: %v", newUserID, delErr)
-				}
-			}
+		if failure, err := activateInvitedUser(c.Request.Context(), serviceManager.DB, invitationID, authIDStr, existingUser, newUser); err != nil {
+			logger().ErrorContext(c.Request.Context(), "Error accepting invitation", "id", invitationID, "user_id", newUserID, "error", err)
 			c.JSON(http.StatusInternalServerError, models.APIResponse{
-				Error: "Failed to accept invitation: " + err.Error(),
+				Error: failure,
 			})
 			return
 		}
 
+		publishEvent(c, serviceManager, events.New(events.TypeInvitationAccepted, targetInvitation.FamilyID, newUserID, map[string]any{
+			"invitationId": invitationID,
+			"role":         targetInvitation.Role,
+		}))
+
 		c.JSON(http.StatusOK, models.APIResponse{
 			Message: "Invitation accepted successfully. Your account has been activated.",
 			Data: map[string]interface{}{
@@ -1106,20 +1166,54 @@ func AcceptInvitation(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
 		return
 	}
-	err = serviceManager.DB.AcceptInvitation(invitationID, userIDStr)
+	err = serviceManager.DB.AcceptInvitation(c.Request.Context(), invitationID, userIDStr)
 	if err != nil {
-		log.Printf("ERROR accepting invitation: %v (id=%s, user=%s)", err, invitationID, userIDStr)
+		logger().ErrorContext(c.Request.Context(), "Error accepting invitation", "id", invitationID, "user_id", userIDStr, "error", err)
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
-			Error: "Failed to accept invitation: " + err.Error(),
+			Error: "Failed to accept invitation",
 		})
 		return
 	}
 
+	// The user's family is only known once the invitation has been applied
+	if member, err := serviceManager.DB.GetUser(c.Request.Context(), userIDStr); err == nil {
+		publishEvent(c, serviceManager, events.New(events.TypeInvitationAccepted, member.FamilyID, member.ID, map[string]any{
+			"invitationId": invitationID,
+			"role":         member.Role,
+		}))
+	}
+
 	c.JSON(http.StatusOK, models.APIResponse{
 		Message: "Invitation accepted successfully",
 	})
 }
 
+// activateInvitedUser links an existing account to the auth identity, or creates
+// newUser, and accepts the invitation in one transaction, so a failed acceptance
+// leaves no half-activated account behind. failure is the client-facing message
+// for the step that failed; err carries the details for the log.
+func activateInvitedUser(ctx context.Context, repo db.Repository, invitationID, authID string, existingUser, newUser *models.User) (failure string, err error) {
+	failure = "Failed to accept invitation"
+	err = repo.WithTx(ctx, func(tx db.Repository) error {
+		userID := authID
+		if existingUser != nil {
+			if err := tx.LinkUserToAuthID(ctx, existingUser.ID, authID); err != nil {
+				failure = "Failed to link account"
+				return fmt.Errorf("failed to link account: %w", err)
+			}
+			userID = existingUser.ID
+		} else if err := tx.CreateUser(ctx, newUser); err != nil {
+			failure = "Failed to create account"
+			return fmt.Errorf("failed to create account: %w", err)
+		}
+		if err := tx.AcceptInvitation(ctx, invitationID, userID); err != nil {
+			return fmt.Errorf("failed to accept invitation: %w", err)
+		}
+		return nil
+	})
+	return failure, err
+}
+
 // Family management handlers
 
 // @Summary		Get Family Information
@@ -1154,7 +1248,7 @@ func GetFamily(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid family ID"})
 		return
 	}
-	family, err := serviceManager.DB.GetFamily(familyIDStr)
+	family, err := serviceManager.DB.GetFamily(c.Request.Context(), familyIDStr)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Error: "Failed to retrieve family",
@@ -1199,7 +1293,7 @@ func GetFamilyStats(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid family ID"})
 		return
 	}
-	stats, err := serviceManager.DB.GetFamilyStats(familyIDStr)
+	stats, err := serviceManager.DB.GetFamilyStats(c.Request.Context(), familyIDStr)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Error: "Failed to retrieve family stats",
@@ -1213,13 +1307,22 @@ func GetFamilyStats(c *gin.Context) {
 }
 
 // @Summary		Get Family Results
-// @Description	Get test results for all members of the authenticated user's family
+// @Description	Get test results for all members of the authenticated user's family, newest first. Pass limit or cursor to page through them; nextCursor is set while more pages follow.
 // @Tags			families
 // @Accept			json
 // @Produce		json
-// @Success		200	{object}	models.APIResponse	"Family test results"
-// @Failure		401	{object}	models.APIResponse	"Family access validation required"
-// @Failure		500	{object}	models.APIResponse	"Failed to retrieve family results"
+// @Param			childId		query		string	false	"Only this family member's results"
+// @Param			from		query		string	false	"Completed at or after (YYYY-MM-DD or RFC 3339)"
+// @Param			to			query		string	false	"Completed before (YYYY-MM-DD or RFC 3339)"
+// @Param			mode		query		string	false	"Test mode"
+// @Param			wordSetId	query		string	false	"Word set ID"
+// @Param			minScore	query		number	false	"Minimum score (0-100)"
+// @Param			limit		query		int		false	"Page size (max 200)"
+// @Param			cursor		query		string	false	"nextCursor from the previous page"
+// @Success		200			{object}	models.APIResponse	"Family test results"
+// @Failure		400			{object}	models.APIResponse	"Invalid filter, limit or cursor"
+// @Failure		401			{object}	models.APIResponse	"Family access validation required"
+// @Failure		500			{object}	models.APIResponse	"Failed to retrieve family results"
 // @Security		BearerAuth
 // @Router			/api/families/results [get]
 func GetFamilyResults(c *gin.Context) {
@@ -1244,17 +1347,20 @@ func GetFamilyResults(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid family ID"})
 		return
 	}
-	familyResults, err := serviceManager.DB.GetFamilyResults(familyIDStr)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
-			Error: "Failed to retrieve family results",
-		})
+	filter, ok := parseResultFilter(c)
+	if !ok {
+		return
+	}
+	page, ok := parsePageRequest(c)
+	if !ok {
 		return
 	}
+	// The family condition still applies, so childId cannot reach another family
+	filter.FamilyID = familyIDStr
+	filter.UserID = c.Query("childId")
 
-	c.JSON(http.StatusOK, models.APIResponse{
-		Data: familyResults,
-	})
+	familyResults, next, err := serviceManager.DB.ListTestResults(c.Request.Context(), filter, page)
+	respondListing(c, familyResults, next, err, "family results")
 }
 
 // @Summary		Get Family Children
@@ -1289,7 +1395,7 @@ func GetFamilyChildren(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid family ID"})
 		return
 	}
-	children, err := serviceManager.DB.GetFamilyChildren(familyIDStr)
+	children, err := serviceManager.DB.GetFamilyChildren(c.Request.Context(), familyIDStr)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Error: "Failed to retrieve children",
@@ -1334,7 +1440,7 @@ func GetFamilyProgress(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid family ID"})
 		return
 	}
-	progress, err := serviceManager.DB.GetFamilyProgress(familyIDStr)
+	progress, err := serviceManager.DB.GetFamilyProgress(c.Request.Context(), familyIDStr)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Error: "Failed to retrieve family progress",
@@ -1371,19 +1477,18 @@ func AddFamilyMember(c *gin.Context) {
 
 	var req models.AddFamilyMemberRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		log.Printf("ERROR AddFamilyMember: Invalid request data - %v. Request body: %+v", err, req)
+		logger().InfoContext(c.Request.Context(), "Invalid add family member request", "error", err)
 		c.JSON(http.StatusBadRequest, models.APIResponse{
 			Error: "Invalid request data: " + err.Error(),
 		})
 		return
 	}
 
-	log.Printf("INFO AddFamilyMember: Processing request - email=%s, role=%s, displayName=%s, familyId=%s",
-		req.Email, req.Role, req.DisplayName, req.FamilyID)
+	logger().InfoContext(c.Request.Context(), "Adding family member", "role", req.Role, "family_id", req.FamilyID)
 
 	// Validate displayName is provided for child role
 	if req.Role == "child" && strings.TrimSpace(req.DisplayName) == "" {
-		log.Printf("ERROR AddFamilyMember: DisplayName is required for child role")
+		logger().InfoContext(c.Request.Context(), "Display name missing for child family member")
 		c.JSON(http.StatusBadRequest, models.APIResponse{
 			Error: "DisplayName is required for child accounts",
 		})
@@ -1415,8 +1520,8 @@ func AddFamilyMember(c *gin.Context) {
 			ExpiresAt: &expiresAt,
 		}
 
-		if err := serviceManager.DB.CreateFamilyInvitation(invitation); err != nil {
-			log.Printf("ERROR creating parent invitation: %v (email=%s, family=%s)", err, req.Email, familyIDStr)
+		if err := serviceManager.DB.CreateFamilyInvitation(c.Request.Context(), invitation); err != nil {
+			logger().ErrorContext(c.Request.Context(), "Error creating parent invitation", "email", req.Email, "family_id", familyIDStr, "error", err)
 			c.JSON(http.StatusInternalServerError, models.APIResponse{
 				Error: "Failed to invite parent: " + err.Error(),
 			})
@@ -1471,7 +1576,7 @@ func AddFamilyMember(c *gin.Context) {
 	// or Option 2 (Invitation) for better UX and security.
 
 	// Check if user already exists with this email
-	existingUser, err := serviceManager.DB.GetUserByEmail(req.Email)
+	existingUser, err := serviceManager.DB.GetUserByEmail(c.Request.Context(), req.Email)
 	if err == nil && existingUser != nil {
 		c.JSON(http.StatusConflict, models.APIResponse{
 			Error: "A user with this email already exists",
@@ -1502,8 +1607,8 @@ func AddFamilyMember(c *gin.Context) {
 		ExpiresAt: &expiresAt,
 	}
 
-	if err := serviceManager.DB.CreateFamilyInvitation(invitation); err != nil {
-		log.Printf("ERROR creating child invitation: %v (email=%s, family=%s)", err, req.Email, familyIDStr)
+	if err := serviceManager.DB.CreateFamilyInvitation(c.Request.Context(), invitation); err != nil {
+		logger().ErrorContext(c.Request.Context(), "Error creating child invitation", "email", req.Email, "family_id", familyIDStr, "error", err)
 		// Check if error is due to duplicate invitation
 		if strings.Contains(err.Error(), "invitation already exists") {
 			c.JSON(http.StatusConflict, models.APIResponse{
@@ -1550,9 +1655,9 @@ func GetFamilyInvitations(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid family ID"})
 		return
 	}
-	invitations, err := serviceManager.DB.GetFamilyInvitations(familyIDStr)
+	invitations, err := serviceManager.DB.GetFamilyInvitations(c.Request.Context(), familyIDStr)
 	if err != nil {
-		log.Printf("ERROR getting family invitations: %v (family=%s)", err, familyIDStr)
+		logger().ErrorContext(c.Request.Context(), "Error getting family invitations", "family_id", familyIDStr, "error", err)
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Error: "Failed to retrieve invitations",
 		})
@@ -1592,8 +1697,8 @@ func DeleteFamilyInvitation(c *gin.Context) {
 		return
 	}
 
-	if err := serviceManager.DB.DeleteInvitation(invitationID); err != nil {
-		log.Printf("ERROR deleting invitation: %v (id=%s)", err, invitationID)
+	if err := serviceManager.DB.DeleteInvitation(c.Request.Context(), invitationID); err != nil {
+		logger().ErrorContext(c.Request.Context(), "Error deleting invitation", "id", invitationID, "error", err)
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Error: "Failed to delete invitation",
 		})
@@ -1640,9 +1745,9 @@ func RemoveFamilyMember(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid family ID"})
 		return
 	}
-	family, err := serviceManager.DB.GetFamily(familyIDStr)
+	family, err := serviceManager.DB.GetFamily(c.Request.Context(), familyIDStr)
 	if err != nil {
-		log.Printf("ERROR getting family: %v (family=%s)", err, familyIDStr)
+		logger().ErrorContext(c.Request.Context(), "Error getting family", "family_id", familyIDStr, "error", err)
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Error: "Failed to retrieve family",
 		})
@@ -1712,7 +1817,7 @@ func UpdateChildAccount(c *gin.Context) {
 		return
 	}
 
-	if err := serviceManager.DB.UpdateChildDisplayName(childID, displayName); err != nil {
+	if err := serviceManager.DB.UpdateChildDisplayName(c.Request.Context(), childID, displayName); err != nil {
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Error: "Failed to update child account",
 		})
@@ -1720,7 +1825,7 @@ func UpdateChildAccount(c *gin.Context) {
 	}
 
 	// Get updated child data
-	child, err := serviceManager.DB.GetChild(childID)
+	child, err := serviceManager.DB.GetChild(c.Request.Context(), childID)
 	if err != nil {
 		// Still return success even if we can't fetch updated data
 		c.JSON(http.StatusOK, models.APIResponse{
@@ -1773,7 +1878,7 @@ func UpdateChildBirthYear(c *gin.Context) {
 	childID := c.Param("childId")
 
 	// First, verify the child belongs to the same family
-	child, err := serviceManager.DB.GetChild(childID)
+	child, err := serviceManager.DB.GetChild(c.Request.Context(), childID)
 	if err != nil {
 		if errors.Is(err, db.ErrChildNotFound) {
 			c.JSON(http.StatusNotFound, models.APIResponse{
@@ -1819,7 +1924,7 @@ func UpdateChildBirthYear(c *gin.Context) {
 		}
 	}
 
-	if err := serviceManager.DB.UpdateChildBirthYear(childID, req.BirthYear); err != nil {
+	if err := serviceManager.DB.UpdateChildBirthYear(c.Request.Context(), childID, req.BirthYear); err != nil {
 		if errors.Is(err, db.ErrChildNotFound) {
 			c.JSON(http.StatusNotFound, models.APIResponse{
 				Error: "Child not found",
@@ -1833,7 +1938,7 @@ func UpdateChildBirthYear(c *gin.Context) {
 	}
 
 	// Get updated child data
-	updatedChild, err := serviceManager.DB.GetChild(childID)
+	updatedChild, err := serviceManager.DB.GetChild(c.Request.Context(), childID)
 	if err != nil {
 		c.JSON(http.StatusOK, models.APIResponse{
 			Message: "Child birth year updated successfully",
@@ -1877,26 +1982,22 @@ func DeleteChildAccount(c *gin.Context) {
 	// For now, we only delete from our database
 	// The child's OIDC account (if it exists) can be cleaned up separately via Zitadel admin
 
-	// First delete from users table
-
-	// Delete child record from our database
-	if err := serviceManager.DB.DeleteChild(childID); err != nil {
-		log.Printf("Warning: Failed to delete child record from database: %v", err)
+	// Children are user rows, so one delete removes the account along with its
+	// memberships, results and assignments through cascading foreign keys
+	if err := serviceManager.DB.DeleteChild(c.Request.Context(), childID); err != nil {
+		if errors.Is(err, db.ErrChildNotFound) {
+			c.JSON(http.StatusNotFound, models.APIResponse{
+				Error: "Child not found",
+			})
+			return
+		}
+		logger().ErrorContext(c.Request.Context(), "Failed to delete child record", "error", err)
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Error: "Failed to delete child from database",
 		})
 		return
 	}
 
-	// Delete user record from our database
-	if err := serviceManager.DB.DeleteUser(childID); err != nil {
-		log.Printf("Warning: Failed to delete user record from database: %v", err)
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
-			Error: "Child account partially deleted - child record removed but user record cleanup failed",
-		})
-		return
-	}
-
 	c.JSON(http.StatusOK, models.APIResponse{
 		Message: "Child account deleted successfully",
 	})
@@ -1925,7 +2026,7 @@ func GetChildProgress(c *gin.Context) {
 
 	childID := c.Param("childId")
 
-	progress, err := serviceManager.DB.GetUserProgress(childID)
+	progress, err := serviceManager.DB.GetUserProgress(c.Request.Context(), childID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Error: "Failed to retrieve child progress",
@@ -1943,11 +2044,19 @@ func GetChildProgress(c *gin.Context) {
 // @Tags			children
 // @Accept			json
 // @Produce		json
-// @Param			childId	path		string	true	"Child ID"
-// @Success		200		{object}	models.APIResponse	"Child test results"
-// @Failure		401		{object}	models.APIResponse	"Parent access required"
-// @Failure		404		{object}	models.APIResponse	"Child not found"
-// @Failure		500		{object}	models.APIResponse	"Failed to retrieve child results"
+// @Param			childId		path		string	true	"Child ID"
+// @Param			from		query		string	false	"Completed at or after (YYYY-MM-DD or RFC 3339)"
+// @Param			to			query		string	false	"Completed before (YYYY-MM-DD or RFC 3339)"
+// @Param			mode		query		string	false	"Test mode"
+// @Param			wordSetId	query		string	false	"Word set ID"
+// @Param			minScore	query		number	false	"Minimum score (0-100)"
+// @Param			limit		query		int		false	"Page size (max 200)"
+// @Param			cursor		query		string	false	"nextCursor from the previous page"
+// @Success		200			{object}	models.APIResponse	"Child test results"
+// @Failure		400			{object}	models.APIResponse	"Invalid filter, limit or cursor"
+// @Failure		401			{object}	models.APIResponse	"Parent access required"
+// @Failure		404			{object}	models.APIResponse	"Child not found"
+// @Failure		500			{object}	models.APIResponse	"Failed to retrieve child results"
 // @Security		BearerAuth
 // @Router			/api/families/children/{childId}/results [get]
 func GetChildResults(c *gin.Context) {
@@ -1959,19 +2068,18 @@ func GetChildResults(c *gin.Context) {
 		return
 	}
 
-	childID := c.Param("childId")
-
-	results, err := serviceManager.DB.GetTestResults(childID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
-			Error: "Failed to retrieve child results",
-		})
+	filter, ok := parseResultFilter(c)
+	if !ok {
 		return
 	}
+	page, ok := parsePageRequest(c)
+	if !ok {
+		return
+	}
+	filter.UserID = c.Param("childId")
 
-	c.JSON(http.StatusOK, models.APIResponse{
-		Data: results,
-	})
+	results, next, err := serviceManager.DB.ListTestResults(c.Request.Context(), filter, page)
+	respondListing(c, results, next, err, "child results")
 }
 
 // User management handlers
@@ -2056,9 +2164,9 @@ func CreateUser(c *gin.Context) {
 	}
 
 	// Check for pending invitations before creating new family
-	pendingInvitations, err := serviceManager.DB.GetPendingInvitationsByEmail(email)
+	pendingInvitations, err := serviceManager.DB.GetPendingInvitationsByEmail(c.Request.Context(), email)
 	if err != nil {
-		log.Printf("ERROR checking pending invitations: %v", err)
+		logger().ErrorContext(c.Request.Context(), "Error checking pending invitations", "error", err)
 		// Continue with registration even if invitation check fails
 		pendingInvitations = nil
 	}
@@ -2088,16 +2196,6 @@ func CreateUser(c *gin.Context) {
 		LastActiveAt: time.Now(),
 	}
 
-	if err := serviceManager.DB.CreateUser(newUser); err != nil {
-		log.Printf("ERROR creating user: %v", err)
-		status := http.StatusInternalServerError
-		if strings.Contains(strings.ToLower(err.Error()), "duplicate") {
-			status = http.StatusConflict
-		}
-		c.JSON(status, models.APIResponse{Error: "Failed to create user"})
-		return
-	}
-
 	if familyName == "" {
 		familyName = displayName + "'s Family"
 	}
@@ -2111,23 +2209,9 @@ func CreateUser(c *gin.Context) {
 		CreatedAt: time.Now(),
 	}
 
-	if err := serviceManager.DB.CreateFamily(family); err != nil {
-		log.Printf("ERROR creating family: %v", err)
-		if delErr := serviceManager.DB.DeleteUser(newUser.ID); delErr != nil {
-			log.Printf("Warning: failed to delete user during cleanup: %v", delErr)
-		}
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
-			Error: "Failed to create family",
-		})
-		return
-	}
-
-	newUser.FamilyID = family.ID
-	if err := serviceManager.DB.UpdateUser(newUser); err != nil {
-		log.Printf("ERROR updating user with family ID: %v", err)
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
-			Error: "Failed to update user with family",
-		})
+	if status, failure, err := createParentAccount(c.Request.Context(), serviceManager.DB, newUser, family); err != nil {
+		logger().ErrorContext(c.Request.Context(), "Error creating parent account", "error", err)
+		c.JSON(status, models.APIResponse{Error: failure})
 		return
 	}
 
@@ -2146,6 +2230,36 @@ func CreateUser(c *gin.Context) {
 	})
 }
 
+// createParentAccount creates a parent, their family and the link between them in
+// one transaction, so a failed step leaves no user without a family. It returns
+// the response status and message for the step that failed.
+func createParentAccount(ctx context.Context, repo db.Repository, user *models.User, family *models.Family) (int, string, error) {
+	status, failure := http.StatusInternalServerError, ""
+	err := repo.WithTx(ctx, func(tx db.Repository) error {
+		if err := tx.CreateUser(ctx, user); err != nil {
+			if strings.Contains(strings.ToLower(err.Error()), "duplicate") {
+				status = http.StatusConflict
+			}
+			failure = "Failed to create user"
+			return err
+		}
+		if err := tx.CreateFamily(ctx, family); err != nil {
+			failure = "Failed to create family"
+			return err
+		}
+		user.FamilyID = family.ID
+		if err := tx.UpdateUser(ctx, user); err != nil {
+			failure = "Failed to update user with family"
+			return err
+		}
+		return nil
+	})
+	if err != nil && failure == "" {
+		failure = "Failed to create user"
+	}
+	return status, failure, err
+}
+
 // @Summary		Get User Profile
 // @Description	Get the current user's profile information
 // @Tags			users
@@ -2173,12 +2287,12 @@ func GetUserProfile(c *gin.Context) {
 		// Get service manager and try to lookup user
 		serviceManager := GetServiceManager(c)
 		if serviceManager != nil {
-			userData, err := serviceManager.DB.GetUserByAuthID(authID)
+			userData, err := serviceManager.DB.GetUserByAuthID(c.Request.Context(), authID)
 			if err == nil && userData != nil {
 				// Get family name if user has a family
 				var familyName string
 				if userData.FamilyID != "" {
-					family, err := serviceManager.DB.GetFamily(userData.FamilyID)
+					family, err := serviceManager.DB.GetFamily(c.Request.Context(), userData.FamilyID)
 					if err == nil && family != nil {
 						familyName = family.Name
 					}
@@ -2216,7 +2330,7 @@ func GetUserProfile(c *gin.Context) {
 					email := authIdentity.Email
 					if email != "" {
 						// Check for pending invitations
-						invitations, err := serviceManager.DB.GetPendingInvitationsByEmail(email)
+						invitations, err := serviceManager.DB.GetPendingInvitationsByEmail(c.Request.Context(), email)
 						if err == nil && len(invitations) > 0 {
 							// User has pending invitations
 							c.JSON(http.StatusOK, models.APIResponse{
@@ -2258,7 +2372,7 @@ func GetUserProfile(c *gin.Context) {
 	if userData.FamilyID != "" {
 		serviceManager := GetServiceManager(c)
 		if serviceManager != nil {
-			family, err := serviceManager.DB.GetFamily(userData.FamilyID)
+			family, err := serviceManager.DB.GetFamily(c.Request.Context(), userData.FamilyID)
 			if err == nil && family != nil {
 				familyName = family.Name
 			}
@@ -2336,7 +2450,7 @@ func UpdateUserDisplayName(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
 		return
 	}
-	if err := serviceManager.DB.UpdateUserDisplayName(userIDStr, displayName); err != nil {
+	if err := serviceManager.DB.UpdateUserDisplayName(c.Request.Context(), userIDStr, displayName); err != nil {
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Error: "Failed to update display name",
 		})
@@ -2369,7 +2483,7 @@ func ListVoices(c *gin.Context) {
 	}
 
 	// Get available voices for the language
-	voices, err := serviceManager.TTS.GetChildFriendlyVoices(language)
+	voices, err := serviceManager.TTS.GetChildFriendlyVoices(c.Request.Context(), language)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Error: fmt.Sprintf("Failed to retrieve voices: %v", err),
@@ -2473,7 +2587,7 @@ func GetWordSetMastery(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
 		return
 	}
-	mastery, err := serviceManager.DB.GetWordSetMastery(userIDStr, wordSetID)
+	mastery, err := serviceManager.DB.GetWordSetMastery(c.Request.Context(), userIDStr, wordSetID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Error: "Failed to retrieve mastery data",
@@ -2536,7 +2650,7 @@ func GetWordMastery(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
 		return
 	}
-	mastery, err := serviceManager.DB.GetWordMastery(userIDStr, wordSetID, word)
+	mastery, err := serviceManager.DB.GetWordMastery(c.Request.Context(), userIDStr, wordSetID, word)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Error: "Failed to retrieve mastery data",
@@ -2636,9 +2750,9 @@ func IncrementMastery(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
 		return
 	}
-	mastery, err := serviceManager.DB.IncrementMastery(userIDStr, wordSetID, req.Word, inputMode)
+	mastery, err := serviceManager.DB.IncrementMastery(c.Request.Context(), userIDStr, wordSetID, req.Word, inputMode)
 	if err != nil {
-		log.Printf("[IncrementMastery] Error: %v", err)
+		logger().ErrorContext(c.Request.Context(), "Error incrementing mastery", "user_id", userIDStr, "wordset_id", wordSetID, "error", err)
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Error: "Failed to increment mastery",
 		})
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/starefossen/diktator/backend/internal/health"
+)
+
+// Livez reports that the process is running and serving requests. It checks
+// no dependencies, so a database outage never gets the pod restarted.
+//
+// @Summary		Liveness probe
+// @Description	Returns 200 while the server is running
+// @Tags			health
+// @Produce		json
+// @Success		200	{object}	map[string]string	"Server is alive"
+// @Router			/livez [get]
+func Livez(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": health.StatusOK})
+}
+
+// Readyz runs the dependency checks and reports whether the server should
+// receive traffic. It returns 503 when a critical dependency fails; failing
+// non-critical dependencies are reported as degraded with 200. The endpoint is
+// public, so only statuses are returned and check errors are logged.
+//
+// @Summary		Readiness probe
+// @Description	Checks the database, schema version, TTS, dictionary and OIDC provider
+// @Tags			health
+// @Produce		json
+// @Success		200	{object}	health.PublicReport	"Ready, possibly degraded"
+// @Failure		503	{object}	health.PublicReport	"A critical dependency is unavailable"
+// @Router			/readyz [get]
+func Readyz(c *gin.Context) {
+	serviceManager := GetServiceManager(c)
+	if serviceManager == nil {
+		c.JSON(http.StatusServiceUnavailable, health.PublicReport{Status: health.StatusUnavailable})
+		return
+	}
+
+	ctx := c.Request.Context()
+	report := health.Run(ctx, health.DefaultTimeout, serviceManager.HealthChecks()...)
+	for name, result := range report.Checks {
+		switch result.Status {
+		case health.StatusUnavailable:
+			logger().ErrorContext(ctx, "Readiness check failed", "check", name, "error", result.Error)
+		case health.StatusDegraded:
+			logger().WarnContext(ctx, "Readiness check degraded", "check", name, "error", result.Error)
+		}
+	}
+
+	status := http.StatusOK
+	if !report.Ready() {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report.Public())
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/starefossen/diktator/backend/internal/services"
+	"github.com/starefossen/diktator/backend/internal/services/db"
+	"github.com/stretchr/testify/assert"
+)
+
+// unhealthyRepo fails its health check with an error naming internal details
+type unhealthyRepo struct {
+	db.Repository
+}
+
+func (unhealthyRepo) CheckHealth(context.Context) error {
+	return errors.New("dial tcp 10.0.0.5:5432: connection refused")
+}
+
+func TestReadyz_HidesCheckErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	sm := &services.Manager{DB: unhealthyRepo{Repository: db.NewMemory()}}
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("serviceManager", sm)
+		c.Next()
+	})
+	router.GET("/readyz", Readyz)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.JSONEq(t, `{"status": "unavailable", "checks": {"database": "unavailable"}}`, w.Body.String())
+}
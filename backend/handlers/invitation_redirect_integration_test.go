@@ -92,7 +92,7 @@ func TestInvitationRedirectFlow_Integration(t *testing.T) {
 		require.Equal(t, http.StatusOK, w.Code, "Response body: %s", w.Body.String())
 
 		// Get the newly created user
-		newParent, err := env.DB.GetUserByAuthID("auth-newparent-123")
+		newParent, err := env.DB.GetUserByAuthID(t.Context(), "auth-newparent-123")
 		require.NoError(t, err)
 		require.NotNil(t, newParent)
 
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services/db"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 200
+)
+
+// parsePageRequest reads cursor and limit. Listings are paginated only when one
+// of them is given, so clients that expect the full list keep getting it.
+func parsePageRequest(c *gin.Context) (models.PageRequest, bool) {
+	page := models.PageRequest{Cursor: c.Query("cursor")}
+
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Error: "limit must be a positive integer",
+			})
+			return page, false
+		}
+		page.Limit = min(parsed, maxPageLimit)
+	} else if page.Cursor != "" {
+		page.Limit = defaultPageLimit
+	}
+
+	return page, true
+}
+
+// parseResultFilter reads the from, to, mode, wordSetId and minScore filters of
+// the result listings. Dates are RFC 3339 timestamps or YYYY-MM-DD; to is exclusive.
+func parseResultFilter(c *gin.Context) (models.ResultFilter, bool) {
+	filter := models.ResultFilter{
+		Mode:      c.Query("mode"),
+		WordSetID: c.Query("wordSetId"),
+	}
+
+	for _, bound := range []struct {
+		param string
+		dst   **time.Time
+	}{{"from", &filter.From}, {"to", &filter.To}} {
+		raw := c.Query(bound.param)
+		if raw == "" {
+			continue
+		}
+		t, ok := parseAnalyticsTime(raw)
+		if !ok {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Error: bound.param + " must be a date (YYYY-MM-DD) or RFC 3339 timestamp",
+			})
+			return filter, false
+		}
+		*bound.dst = &t
+	}
+
+	if filter.Mode != "" && !models.IsValidTestMode(filter.Mode) {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Error: "Invalid mode",
+		})
+		return filter, false
+	}
+
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Error: "from must be before to",
+		})
+		return filter, false
+	}
+
+	if raw := c.Query("minScore"); raw != "" {
+		score, err := strconv.ParseFloat(raw, 64)
+		if err != nil || score < 0 || score > 100 {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Error: "minScore must be a number between 0 and 100",
+			})
+			return filter, false
+		}
+		filter.MinScore = &score
+	}
+
+	return filter, true
+}
+
+// parseWordSetFilter reads the search, grade, difficulty and language filters of
+// the word set listing
+func parseWordSetFilter(c *gin.Context) (models.WordSetFilter, bool) {
+	filter := models.WordSetFilter{
+		Search:     c.Query("search"),
+		Language:   c.Query("language"),
+		Grade:      models.GradeLevel(c.Query("grade")),
+		Difficulty: models.DifficultyLevel(c.Query("difficulty")),
+	}
+
+	if filter.Grade != "" && !models.IsValidGradeLevel(filter.Grade) {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Error: "Invalid grade",
+		})
+		return filter, false
+	}
+	if filter.Difficulty != "" && !models.IsValidDifficultyLevel(filter.Difficulty) {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Error: "Invalid difficulty",
+		})
+		return filter, false
+	}
+
+	return filter, true
+}
+
+// respondListing writes one page of a listing with the cursor of the next page,
+// or reports a stale cursor or a failed query
+func respondListing[T any](c *gin.Context, items []T, next string, err error, what string) {
+	if errors.Is(err, db.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Error: "Invalid cursor",
+		})
+		return
+	}
+	if err != nil {
+		logger().ErrorContext(c.Request.Context(), "Error listing "+what, "error", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Failed to retrieve " + what,
+		})
+		return
+	}
+
+	if items == nil {
+		items = []T{}
+	}
+	c.JSON(http.StatusOK, models.APIResponse{
+		Data:       items,
+		NextCursor: next,
+	})
+}
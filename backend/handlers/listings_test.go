@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services"
+	"github.com/starefossen/diktator/backend/internal/services/xp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listingPage is a decoded listing response
+type listingPage struct {
+	Data       []map[string]any `json:"data"`
+	NextCursor string           `json:"nextCursor"`
+	Error      string           `json:"error"`
+}
+
+func getListing(t *testing.T, f *txFamily, handler gin.HandlerFunc, query string) (int, listingPage) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	sm := &services.Manager{DB: f.repo, XP: xp.NewService(f.repo)}
+
+	router := gin.New()
+	router.GET("/list", func(c *gin.Context) {
+		c.Set("serviceManager", sm)
+		c.Set("userID", f.parentID)
+		c.Set("validatedFamilyID", f.familyID)
+		handler(c)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/list?"+query, nil))
+
+	var page listingPage
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	return w.Code, page
+}
+
+func TestGetFamilyResults_Pagination(t *testing.T) {
+	f := newTxFamily(t)
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	for i := range 3 {
+		result := &models.TestResult{WordSetID: f.wordSet, UserID: f.childID, Mode: "keyboard", Score: float64(50 * i), TotalWords: 1, CompletedAt: base.Add(time.Duration(i) * time.Minute)}
+		require.NoError(t, f.repo.SaveTestResult(t.Context(), result))
+	}
+
+	code, all := getListing(t, f, GetFamilyResults, "")
+	require.Equal(t, http.StatusOK, code)
+	assert.Len(t, all.Data, 3, "without limit or cursor the whole listing is returned")
+	assert.Empty(t, all.NextCursor)
+
+	code, first := getListing(t, f, GetFamilyResults, "limit=2")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, first.Data, 2)
+	require.NotEmpty(t, first.NextCursor)
+
+	code, second := getListing(t, f, GetFamilyResults, "cursor="+first.NextCursor)
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, second.Data, 1)
+	assert.Empty(t, second.NextCursor)
+	assert.Equal(t, all.Data[2]["id"], second.Data[0]["id"])
+
+	code, filtered := getListing(t, f, GetFamilyResults, "minScore=50&childId="+f.childID)
+	require.Equal(t, http.StatusOK, code)
+	assert.Len(t, filtered.Data, 2)
+
+	code, none := getListing(t, f, GetFamilyResults, "childId=someone-else")
+	require.Equal(t, http.StatusOK, code)
+	assert.Empty(t, none.Data)
+}
+
+func TestListings_InvalidParameters(t *testing.T) {
+	f := newTxFamily(t)
+
+	tests := []struct {
+		name    string
+		handler gin.HandlerFunc
+		query   string
+	}{
+		{"zero limit", GetFamilyResults, "limit=0"},
+		{"malformed cursor", GetFamilyResults, "cursor=not-a-cursor"},
+		{"unknown mode", GetFamilyResults, "mode=shouting"},
+		{"malformed date", GetFamilyResults, "from=yesterday"},
+		{"empty range", GetFamilyResults, "from=2026-02-01&to=2026-01-01"},
+		{"score out of range", GetFamilyResults, "minScore=101"},
+		{"unknown grade", GetWordSets, "grade=8-10"},
+		{"unknown difficulty", GetWordSets, "difficulty=extreme"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, page := getListing(t, f, tt.handler, tt.query)
+			assert.Equal(t, http.StatusBadRequest, code)
+			assert.NotEmpty(t, page.Error)
+		})
+	}
+}
+
+func TestGetWordSets_Filters(t *testing.T) {
+	f := newTxFamily(t)
+	grade := models.GradeLevel34
+	ws := &models.WordSet{Name: "Dobbel konsonant", FamilyID: &f.familyID, CreatedBy: f.parentID, Language: "no", TargetGrade: &grade, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	require.NoError(t, f.repo.CreateWordSet(t.Context(), ws))
+
+	code, page := getListing(t, f, GetWordSets, "search=dobbel&grade=3-4")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, page.Data, 1)
+	assert.Equal(t, ws.ID, page.Data[0]["id"])
+
+	code, page = getListing(t, f, GetWordSets, "language=en")
+	require.Equal(t, http.StatusOK, code)
+	assert.Empty(t, page.Data)
+	assert.NotNil(t, page.Data, "an empty listing is an empty array")
+}
@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/starefossen/diktator/backend/internal/models"
+)
+
+// Inbox page size bounds
+const (
+	defaultNotificationLimit = 50
+	maxNotificationLimit     = 200
+)
+
+// @Summary		Get Notifications
+// @Description	Get the current user's in-app notifications, newest first, with the unread count
+// @Tags			notifications
+// @Accept			json
+// @Produce		json
+// @Param			unread	query		bool	false	"Only unread notifications"
+// @Param			limit	query		int		false	"Maximum number of notifications (default 50, max 200)"
+// @Success		200		{object}	models.APIResponse{data=models.NotificationInbox}	"Notification inbox"
+// @Failure		400		{object}	models.APIResponse	"Invalid query"
+// @Failure		500		{object}	models.APIResponse	"Failed to retrieve notifications"
+// @Security		BearerAuth
+// @Router			/api/notifications [get]
+func GetNotifications(c *gin.Context) {
+	serviceManager := GetServiceManager(c)
+	if serviceManager == nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Service unavailable",
+		})
+		return
+	}
+
+	userID, err := getContextString(c, "userID")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	limit := defaultNotificationLimit
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Error: "limit must be a positive integer",
+			})
+			return
+		}
+		limit = min(parsed, maxNotificationLimit)
+	}
+
+	unreadOnly := false
+	if raw := c.Query("unread"); raw != "" {
+		unreadOnly, err = strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Error: "unread must be true or false",
+			})
+			return
+		}
+	}
+
+	notifications, err := serviceManager.DB.GetNotifications(c.Request.Context(), userID, unreadOnly, limit)
+	if err != nil {
+		logger().ErrorContext(c.Request.Context(), "Error getting notifications", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Failed to retrieve notifications",
+		})
+		return
+	}
+
+	unread, err := serviceManager.DB.CountUnreadNotifications(c.Request.Context(), userID)
+	if err != nil {
+		logger().ErrorContext(c.Request.Context(), "Error counting unread notifications", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Failed to retrieve notifications",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Data: models.NotificationInbox{
+			Notifications: notifications,
+			UnreadCount:   unread,
+		},
+	})
+}
+
+// @Summary		Mark Notifications Read
+// @Description	Mark the given notifications, or all of them, as read
+// @Tags			notifications
+// @Accept			json
+// @Produce		json
+// @Param			request	body		models.NotificationActionRequest	true	"Notifications to mark as read"
+// @Success		200		{object}	models.APIResponse{data=map[string]int}	"Number of notifications updated"
+// @Failure		400		{object}	models.APIResponse	"Invalid request"
+// @Failure		500		{object}	models.APIResponse	"Failed to update notifications"
+// @Security		BearerAuth
+// @Router			/api/notifications/read [post]
+func MarkNotificationsRead(c *gin.Context) {
+	serviceManager := GetServiceManager(c)
+	if serviceManager == nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Service unavailable",
+		})
+		return
+	}
+
+	userID, ids, ok := bindNotificationAction(c)
+	if !ok {
+		return
+	}
+
+	updated, err := serviceManager.DB.MarkNotificationsRead(c.Request.Context(), userID, ids, time.Now())
+	if err != nil {
+		logger().ErrorContext(c.Request.Context(), "Error marking notifications read", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Failed to update notifications",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Data:    map[string]int{"updated": updated},
+		Message: "Notifications marked as read",
+	})
+}
+
+// @Summary		Dismiss Notifications
+// @Description	Remove the given notifications, or all of them, from the inbox
+// @Tags			notifications
+// @Accept			json
+// @Produce		json
+// @Param			request	body		models.NotificationActionRequest	true	"Notifications to dismiss"
+// @Success		200		{object}	models.APIResponse{data=map[string]int}	"Number of notifications dismissed"
+// @Failure		400		{object}	models.APIResponse	"Invalid request"
+// @Failure		500		{object}	models.APIResponse	"Failed to dismiss notifications"
+// @Security		BearerAuth
+// @Router			/api/notifications/dismiss [post]
+func DismissNotifications(c *gin.Context) {
+	serviceManager := GetServiceManager(c)
+	if serviceManager == nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Service unavailable",
+		})
+		return
+	}
+
+	userID, ids, ok := bindNotificationAction(c)
+	if !ok {
+		return
+	}
+
+	dismissed, err := serviceManager.DB.DismissNotifications(c.Request.Context(), userID, ids)
+	if err != nil {
+		logger().ErrorContext(c.Request.Context(), "Error dismissing notifications", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Failed to dismiss notifications",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Data:    map[string]int{"dismissed": dismissed},
+		Message: "Notifications dismissed",
+	})
+}
+
+// bindNotificationAction reads the caller and the selected notification IDs.
+// A nil ID slice means all notifications. It writes the error response itself.
+func bindNotificationAction(c *gin.Context) (string, []string, bool) {
+	userID, err := getContextString(c, "userID")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return "", nil, false
+	}
+
+	var req models.NotificationActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Error: "Invalid request data",
+		})
+		return "", nil, false
+	}
+
+	if req.All == (len(req.IDs) > 0) {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Error: "Specify either ids or all",
+		})
+		return "", nil, false
+	}
+	if req.All {
+		return userID, nil, true
+	}
+
+	return userID, req.IDs, true
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services/events"
+	"github.com/starefossen/diktator/backend/internal/services/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifications_Integration(t *testing.T) {
+	env := SetupIntegrationTest(t)
+	defer env.Cleanup()
+
+	parent := env.CreateTestUser("", "parent")
+	familyID := env.CreateTestFamily(parent.ID)
+	parent.FamilyID = familyID
+	_, err := env.Pool.Exec(context.Background(), `UPDATE users SET family_id = $1 WHERE id = $2`, familyID, parent.ID)
+	require.NoError(t, err)
+
+	child := env.CreateTestUser(familyID, "child")
+	wordSet := env.CreateTestWordSet(familyID, parent.ID)
+
+	env.ServiceManager.Events = events.NewBus(nil)
+	env.ServiceManager.Events.Subscribe("inbox", notify.NewInbox(env.DB))
+
+	env.SetupAuthMiddleware(parent)
+	env.Router.POST("/api/wordsets/:id/assignments/:userId", AssignWordSetToUser)
+	env.Router.GET("/api/notifications", GetNotifications)
+
+	// The child reads their own inbox through a separate router
+	childRouter := gin.New()
+	childRouter.Use(func(c *gin.Context) {
+		c.Set("serviceManager", env.ServiceManager)
+		c.Set("userID", child.ID)
+		c.Set("userRole", child.Role)
+		c.Set("validatedFamilyID", familyID)
+		c.Next()
+	})
+	childRouter.GET("/api/notifications", GetNotifications)
+	childRouter.POST("/api/notifications/read", MarkNotificationsRead)
+	childRouter.POST("/api/notifications/dismiss", DismissNotifications)
+
+	getInbox := func(t *testing.T, router *gin.Engine, query string) models.NotificationInbox {
+		resp := makeRequest(router, "GET", "/api/notifications"+query, nil, nil)
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var apiResp struct {
+			Data models.NotificationInbox `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &apiResp))
+		return apiResp.Data
+	}
+
+	t.Run("AssignmentNotifiesChild", func(t *testing.T) {
+		resp := makeRequest(env.Router, "POST", "/api/wordsets/"+wordSet.ID+"/assignments/"+child.ID, nil, nil)
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		inbox := getInbox(t, childRouter, "")
+		assert.Equal(t, 1, inbox.UnreadCount)
+		require.Len(t, inbox.Notifications, 1)
+		n := inbox.Notifications[0]
+		assert.Equal(t, notify.MessageWordSetAssigned, n.MessageKey)
+		assert.Equal(t, string(events.TypeWordSetAssigned), n.Kind)
+		assert.Equal(t, child.ID, n.SubjectUserID)
+		assert.Equal(t, wordSet.Name, n.Params["wordSetName"])
+		assert.Nil(t, n.ReadAt)
+
+		// The assigning parent is not notified
+		assert.Empty(t, getInbox(t, env.Router, "").Notifications)
+	})
+
+	t.Run("TestCompletedNotifiesParents", func(t *testing.T) {
+		env.ServiceManager.Events.Publish(context.Background(), events.New(events.TypeTestCompleted, familyID, child.ID, map[string]any{
+			"wordSetId": wordSet.ID,
+			"score":     87.5,
+		}))
+
+		inbox := getInbox(t, env.Router, "?unread=true")
+		require.Len(t, inbox.Notifications, 1)
+		assert.Equal(t, notify.MessageTestCompleted, inbox.Notifications[0].MessageKey)
+		assert.Equal(t, child.DisplayName, inbox.Notifications[0].Params["childName"])
+		assert.Equal(t, 87.5, inbox.Notifications[0].Params["score"])
+	})
+
+	t.Run("MarkRead", func(t *testing.T) {
+		id := getInbox(t, childRouter, "").Notifications[0].ID
+
+		resp := makeRequest(childRouter, "POST", "/api/notifications/read", map[string]any{"ids": []string{id}}, nil)
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		inbox := getInbox(t, childRouter, "")
+		assert.Equal(t, 0, inbox.UnreadCount)
+		require.Len(t, inbox.Notifications, 1)
+		assert.NotNil(t, inbox.Notifications[0].ReadAt)
+		assert.Empty(t, getInbox(t, childRouter, "?unread=true").Notifications)
+
+		// Another user's notifications are untouched
+		assert.Equal(t, 1, getInbox(t, env.Router, "").UnreadCount)
+	})
+
+	t.Run("Error_DismissWithoutSelection", func(t *testing.T) {
+		resp := makeRequest(childRouter, "POST", "/api/notifications/dismiss", map[string]any{}, nil)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+		resp = makeRequest(childRouter, "POST", "/api/notifications/dismiss", map[string]any{"ids": []string{"x"}, "all": true}, nil)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
+	t.Run("DismissAll", func(t *testing.T) {
+		resp := makeRequest(childRouter, "POST", "/api/notifications/dismiss", map[string]any{"all": true}, nil)
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		assert.Empty(t, getInbox(t, childRouter, "").Notifications)
+		assert.Len(t, getInbox(t, env.Router, "").Notifications, 1)
+	})
+}
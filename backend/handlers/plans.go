@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services/planner"
+)
+
+// @Summary		Plan Test
+// @Description	Build an adaptive test plan for a word set: an ordered list of words with the mode to practice
+// @Description	each in. The plan combines the child's word mastery, age, recent spelling errors and the word
+// @Description	set's test configuration. Parents may pass childId to plan for one of their children.
+// @Tags			tests
+// @Accept			json
+// @Produce		json
+// @Param			request	body		models.TestPlanRequest	true	"Word set and plan options"
+// @Success		200		{object}	models.APIResponse{data=models.TestPlan}	"Test plan"
+// @Failure		400		{object}	models.APIResponse	"Invalid request data"
+// @Failure		403		{object}	models.APIResponse	"Access denied"
+// @Failure		404		{object}	models.APIResponse	"Child not found"
+// @Failure		500		{object}	models.APIResponse	"Failed to build test plan"
+// @Security		BearerAuth
+// @Router			/api/tests/plan [post]
+func PlanTest(c *gin.Context) {
+	serviceManager := GetServiceManager(c)
+	if serviceManager == nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Service unavailable",
+		})
+		return
+	}
+
+	userID, err := getContextString(c, "userID")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	familyID, err := getContextString(c, "validatedFamilyID")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid family ID"})
+		return
+	}
+
+	var req models.TestPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Error: "Invalid request data",
+		})
+		return
+	}
+
+	targetID := userID
+	if req.ChildID != "" && req.ChildID != userID {
+		if role, _ := c.Get("userRole"); role != "parent" {
+			c.JSON(http.StatusForbidden, models.APIResponse{
+				Error: "Only parents can plan tests for a child",
+			})
+			return
+		}
+		targetID = req.ChildID
+	}
+
+	user, err := serviceManager.DB.GetUser(c.Request.Context(), targetID)
+	if err != nil || user.FamilyID != familyID {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Error: "Child not found",
+		})
+		return
+	}
+
+	if err := serviceManager.DB.VerifyWordSetAccess(c.Request.Context(), familyID, req.WordSetID); err != nil {
+		c.JSON(http.StatusForbidden, models.APIResponse{
+			Error: "Access denied",
+		})
+		return
+	}
+
+	wordSet, err := serviceManager.DB.GetWordSet(c.Request.Context(), req.WordSetID)
+	if err != nil {
+		logger().ErrorContext(c.Request.Context(), "Error loading word set for test plan", "user_id", targetID, "wordset_id", req.WordSetID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Failed to build test plan",
+		})
+		return
+	}
+
+	mastery, err := serviceManager.DB.GetWordSetMastery(c.Request.Context(), targetID, req.WordSetID)
+	if err != nil {
+		logger().ErrorContext(c.Request.Context(), "Error loading mastery for test plan", "user_id", targetID, "wordset_id", req.WordSetID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Failed to build test plan",
+		})
+		return
+	}
+
+	recent, err := serviceManager.DB.GetRecentWordSetResults(c.Request.Context(), targetID, req.WordSetID, planner.RecentResultsLimit)
+	if err != nil {
+		logger().ErrorContext(c.Request.Context(), "Error loading results for test plan", "user_id", targetID, "wordset_id", req.WordSetID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Failed to build test plan",
+		})
+		return
+	}
+
+	plan := planner.Build(planner.Input{
+		WordSet:   wordSet,
+		BirthYear: user.BirthYear,
+		Mastery:   mastery,
+		Recent:    recent,
+		UserID:    targetID,
+		MaxWords:  req.MaxWords,
+	}, time.Now())
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Data: plan,
+	})
+}
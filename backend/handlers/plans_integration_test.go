@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanTest_Integration(t *testing.T) {
+	env := SetupIntegrationTest(t)
+	defer env.Cleanup()
+
+	parent := env.CreateTestUser("", "parent")
+	familyID := env.CreateTestFamily(parent.ID)
+	parent.FamilyID = familyID
+	_, err := env.Pool.Exec(context.Background(), `UPDATE users SET family_id = $1 WHERE id = $2`, familyID, parent.ID)
+	require.NoError(t, err)
+	child := env.CreateTestUser(familyID, "child")
+	birthYear := time.Now().Year() - 6
+	require.NoError(t, env.DB.UpdateChildBirthYear(t.Context(), child.ID, &birthYear))
+
+	wordSet := env.CreateTestWordSet(familyID, parent.ID)
+	for _, w := range []string{"hus", "bil"} {
+		wordSet.Words = append(wordSet.Words, struct {
+			Word         string               `json:"word"`
+			Audio        models.WordAudio     `json:"audio,omitempty"`
+			Definition   string               `json:"definition,omitempty"`
+			Translations []models.Translation `json:"translations,omitempty"`
+		}{Word: w})
+	}
+	require.NoError(t, env.DB.UpdateWordSet(t.Context(), wordSet))
+
+	for range 2 {
+		_, err := env.DB.IncrementMastery(t.Context(), child.ID, wordSet.ID, "bil", models.TestModeLetterTiles)
+		require.NoError(t, err)
+	}
+
+	routerFor := func(user *models.User) *gin.Engine {
+		router := gin.New()
+		router.Use(func(c *gin.Context) {
+			c.Set("serviceManager", env.ServiceManager)
+			c.Set("userID", user.ID)
+			c.Set("userRole", user.Role)
+			c.Set("validatedFamilyID", familyID)
+			c.Next()
+		})
+		router.POST("/api/tests/plan", PlanTest)
+		return router
+	}
+
+	t.Run("Success_ParentPlansForChild", func(t *testing.T) {
+		resp := makeRequest(routerFor(parent), "POST", "/api/tests/plan", map[string]any{"wordSetId": wordSet.ID, "childId": child.ID}, nil)
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var apiResp struct {
+			Data models.TestPlan `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &apiResp))
+		plan := apiResp.Data
+		assert.Equal(t, child.ID, plan.UserID)
+		require.NotNil(t, plan.Age)
+		assert.Equal(t, 6, *plan.Age)
+		require.Len(t, plan.Words, 2)
+		assert.Equal(t, "hus", plan.Words[0].Word)
+		assert.Equal(t, string(models.TestModeLetterTiles), plan.Words[0].Mode)
+		assert.Equal(t, string(models.TestModeWordBank), plan.Words[1].Mode)
+	})
+
+	t.Run("Success_ChildPlansOwnTest", func(t *testing.T) {
+		resp := makeRequest(routerFor(child), "POST", "/api/tests/plan", map[string]any{"wordSetId": wordSet.ID, "maxWords": 1}, nil)
+		require.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"userId":"`+child.ID+`"`)
+	})
+
+	t.Run("Error_ChildPlansForSibling", func(t *testing.T) {
+		sibling := env.CreateTestUser(familyID, "child")
+		resp := makeRequest(routerFor(child), "POST", "/api/tests/plan", map[string]any{"wordSetId": wordSet.ID, "childId": sibling.ID}, nil)
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+	})
+
+	t.Run("Error_OtherFamilyChild", func(t *testing.T) {
+		otherParent := env.CreateTestUser("", "parent")
+		otherFamilyID := env.CreateTestFamily(otherParent.ID)
+		otherChild := env.CreateTestUser(otherFamilyID, "child")
+
+		resp := makeRequest(routerFor(parent), "POST", "/api/tests/plan", map[string]any{"wordSetId": wordSet.ID, "childId": otherChild.ID}, nil)
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+}
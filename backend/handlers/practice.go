@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services/practice"
+)
+
+// @Summary		Generate Child Practice List
+// @Description	Build a personal practice list ("øveliste") from the words a child missed in the last
+// @Description	windowDays days, optionally padded with curated words sharing the same spelling focus.
+// @Description	The list is a family word set assigned to the child. Calling this again rebuilds the
+// @Description	list with the new settings; after each test it is refreshed so mastered words drop off.
+// @Tags			children
+// @Accept			json
+// @Produce		json
+// @Param			childId	path		string						true	"Child ID"
+// @Param			request	body		models.PracticeListRequest	false	"Practice list settings"
+// @Success		200		{object}	models.APIResponse{data=models.PracticeListResponse}	"Practice list"
+// @Failure		400		{object}	models.APIResponse	"Invalid request data or no missed words"
+// @Failure		401		{object}	models.APIResponse	"Parent access required"
+// @Failure		404		{object}	models.APIResponse	"Child not found"
+// @Failure		500		{object}	models.APIResponse	"Failed to build practice list"
+// @Security		BearerAuth
+// @Router			/api/families/children/{childId}/practice-list [post]
+func GenerateChildPracticeList(c *gin.Context) {
+	serviceManager := GetServiceManager(c)
+	if serviceManager == nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Service unavailable",
+		})
+		return
+	}
+
+	userID, err := getContextString(c, "userID")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req models.PracticeListRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Error: "Invalid request data",
+			})
+			return
+		}
+	}
+
+	childID := c.Param("childId")
+	resp, err := serviceManager.Practice.Generate(c.Request.Context(), childID, userID, req)
+	if errors.Is(err, practice.ErrNothingToPractice) {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Error: "No missed words in the selected period",
+		})
+		return
+	}
+	if err != nil {
+		logger().ErrorContext(c.Request.Context(), "Error building practice list", "child_id", childID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Failed to build practice list",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Data:    resp,
+		Message: "Practice list updated",
+	})
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services/practice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateChildPracticeList_Integration(t *testing.T) {
+	env := SetupIntegrationTest(t)
+	defer env.Cleanup()
+
+	parent := env.CreateTestUser("", "parent")
+	familyID := env.CreateTestFamily(parent.ID)
+	parent.FamilyID = familyID
+	_, err := env.Pool.Exec(context.Background(), `UPDATE users SET family_id = $1 WHERE id = $2`, familyID, parent.ID)
+	require.NoError(t, err)
+	child := env.CreateTestUser(familyID, "child")
+	wordSet := env.CreateTestWordSet(familyID, parent.ID)
+
+	saveResult := func(daysAgo int, words ...models.WordTestResult) {
+		require.NoError(t, env.DB.SaveTestResult(t.Context(), &models.TestResult{
+			WordSetID:   wordSet.ID,
+			UserID:      child.ID,
+			Mode:        string(models.TestModeKeyboard),
+			TotalWords:  len(words),
+			CompletedAt: time.Now().AddDate(0, 0, -daysAgo),
+			Words:       words,
+		}))
+	}
+
+	practiceService := practice.NewService(env.DB, nil)
+	env.ServiceManager.Practice = practiceService
+	env.SetupAuthMiddleware(parent)
+	env.Router.POST("/api/families/children/:childId/practice-list", GenerateChildPracticeList)
+	path := "/api/families/children/" + child.ID + "/practice-list"
+
+	t.Run("Error_NothingToPractice", func(t *testing.T) {
+		resp := makeRequest(env.Router, "POST", path, nil, nil)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		env.AssertNoRowsInTable("practice_lists")
+	})
+
+	saveResult(3,
+		models.WordTestResult{Word: "skjære", Attempts: 3, FinalAnswer: "sjære", ErrorTypes: []string{"kjSkjSj"}},
+		models.WordTestResult{Word: "hvit", Attempts: 2, FinalAnswer: "vit", ErrorTypes: []string{"silentH"}},
+		models.WordTestResult{Word: "hus", Attempts: 1, Correct: true, FinalAnswer: "hus"},
+	)
+
+	var listID string
+	t.Run("Success", func(t *testing.T) {
+		resp := makeRequest(env.Router, "POST", path, map[string]any{"windowDays": 7, "maxWords": 10}, nil)
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+		var apiResp struct {
+			Data models.PracticeListResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &apiResp))
+		list := apiResp.Data
+		require.NotNil(t, list.WordSet)
+		listID = list.WordSet.ID
+		assert.Equal(t, 7, list.PracticeList.WindowDays)
+		assert.Len(t, list.Words, 2)
+
+		stored, err := env.DB.GetWordSet(t.Context(), listID)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"hvit", "skjære"}, []string{stored.Words[0].Word, stored.Words[1].Word})
+
+		assigned, err := env.DB.GetWordSetAssignments(t.Context(), listID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{child.ID}, assigned)
+	})
+
+	t.Run("RefreshDropsMasteredWords", func(t *testing.T) {
+		saveResult(1, models.WordTestResult{Word: "hvit", Attempts: 1, Correct: true, FinalAnswer: "hvit"})
+		saveResult(0, models.WordTestResult{Word: "hvit", Attempts: 1, Correct: true, FinalAnswer: "hvit"})
+
+		_, err := practiceService.Refresh(t.Context(), child.ID)
+		require.NoError(t, err)
+
+		stored, err := env.DB.GetWordSet(t.Context(), listID)
+		require.NoError(t, err)
+		require.Len(t, stored.Words, 1)
+		assert.Equal(t, "skjære", stored.Words[0].Word)
+
+		// Rebuilding keeps the same word set
+		resp := makeRequest(env.Router, "POST", path, nil, nil)
+		require.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), listID)
+		env.AssertRowCount("practice_lists", 1)
+	})
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services/printable"
+)
+
+// reportMissedWords is the number of missed words listed on a progress report
+const reportMissedWords = 10
+
+// @Summary		Get Word Set Worksheet
+// @Description	Render a word set as a printable PDF worksheet for dictation on paper.
+// @Description	Modes: dictation (numbered blank lines), missingLetters (tricky letters blanked out)
+// @Description	and answerKey (every word spelled out). Labels are Norwegian unless lang=en.
+// @Tags			wordsets
+// @Produce		application/pdf
+// @Param			id		path		string	true	"Word set ID"
+// @Param			mode	query		string	false	"Worksheet mode"	Enums(dictation, missingLetters, answerKey)	default(dictation)
+// @Param			lang	query		string	false	"Label language"	Enums(no, en)	default(no)
+// @Success		200		{file}		binary				"PDF worksheet"
+// @Failure		400		{object}	models.APIResponse	"Invalid mode"
+// @Failure		403		{object}	models.APIResponse	"Word set not accessible"
+// @Failure		404		{object}	models.APIResponse	"Word set not found"
+// @Failure		500		{object}	models.APIResponse	"Failed to render worksheet"
+// @Security		BearerAuth
+// @Router			/api/wordsets/{id}/worksheet.pdf [get]
+func GetWordSetWorksheet(c *gin.Context) {
+	serviceManager := GetServiceManager(c)
+	if serviceManager == nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Service unavailable",
+		})
+		return
+	}
+
+	mode := printable.WorksheetMode(c.Query("mode"))
+	if mode == "" {
+		mode = printable.WorksheetDictation
+	}
+	if !mode.IsValid() {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Error: "mode must be one of dictation, missingLetters, answerKey",
+		})
+		return
+	}
+
+	id := c.Param("id")
+	wordSet, err := serviceManager.DB.GetWordSet(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Error: "Word set not found",
+		})
+		return
+	}
+
+	pdf, err := printable.Worksheet(wordSet, mode, c.Query("lang"), time.Now())
+	if err != nil {
+		logger().ErrorContext(c.Request.Context(), "Error rendering worksheet", "wordset_id", id, "error", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Failed to render worksheet",
+		})
+		return
+	}
+
+	respondPDF(c, fmt.Sprintf("worksheet-%s-%s.pdf", id, mode), pdf)
+}
+
+// @Summary		Get Child Progress Report
+// @Description	Render a child's progress as a printable PDF report for sharing with teachers:
+// @Description	overall stats, the most recent tests and the words missed in the last 30 days.
+// @Description	Labels are Norwegian unless lang=en.
+// @Tags			children
+// @Produce		application/pdf
+// @Param			childId	path		string	true	"Child ID"
+// @Param			lang	query		string	false	"Label language"	Enums(no, en)	default(no)
+// @Success		200		{file}		binary				"PDF report"
+// @Failure		401		{object}	models.APIResponse	"Parent access required"
+// @Failure		404		{object}	models.APIResponse	"Child not found"
+// @Failure		500		{object}	models.APIResponse	"Failed to render report"
+// @Security		BearerAuth
+// @Router			/api/families/children/{childId}/report.pdf [get]
+func GetChildReport(c *gin.Context) {
+	serviceManager := GetServiceManager(c)
+	if serviceManager == nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Service unavailable",
+		})
+		return
+	}
+
+	childID := c.Param("childId")
+	now := time.Now()
+	data, err := reportData(c.Request.Context(), serviceManager.DB, childID, now)
+	if err != nil {
+		logger().ErrorContext(c.Request.Context(), "Error loading report data", "child_id", childID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Failed to retrieve child progress",
+		})
+		return
+	}
+
+	pdf, err := printable.Report(data, c.Query("lang"), now)
+	if err != nil {
+		logger().ErrorContext(c.Request.Context(), "Error rendering report", "child_id", childID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Failed to render report",
+		})
+		return
+	}
+
+	respondPDF(c, fmt.Sprintf("report-%s-%s.pdf", childID, now.Format(time.DateOnly)), pdf)
+}
+
+// reportDataSource is the part of the repository a progress report reads from
+type reportDataSource interface {
+	GetUserProgress(ctx context.Context, userID string) (*models.FamilyProgress, error)
+	GetTestResults(ctx context.Context, userID string) ([]models.TestResult, error)
+	GetWordSet(ctx context.Context, id string) (*models.WordSet, error)
+	GetMissedWords(ctx context.Context, userID string, from, to time.Time, limit int) ([]models.MissedWord, error)
+}
+
+func reportData(ctx context.Context, repo reportDataSource, childID string, now time.Time) (printable.ReportData, error) {
+	progress, err := repo.GetUserProgress(ctx, childID)
+	if err != nil {
+		return printable.ReportData{}, err
+	}
+
+	results, err := repo.GetTestResults(ctx, childID)
+	if err != nil {
+		return printable.ReportData{}, err
+	}
+	if len(results) > printable.ReportRecentTests {
+		results = results[:printable.ReportRecentTests]
+	}
+	progress.RecentResults = results
+
+	// Word sets may have been deleted since the test; those rows show no name
+	names := make(map[string]string)
+	for _, r := range results {
+		if _, seen := names[r.WordSetID]; seen {
+			continue
+		}
+		names[r.WordSetID] = ""
+		if ws, err := repo.GetWordSet(ctx, r.WordSetID); err == nil {
+			names[r.WordSetID] = ws.Name
+		}
+	}
+
+	missed, err := repo.GetMissedWords(ctx, childID, now.Add(-defaultAnalyticsRange), now, reportMissedWords)
+	if err != nil {
+		return printable.ReportData{}, err
+	}
+
+	return printable.ReportData{
+		Progress:    progress,
+		WordSets:    names,
+		MissedWords: missed,
+	}, nil
+}
+
+// respondPDF sends a PDF to be shown inline in the browser
+func respondPDF(c *gin.Context, filename string, pdf []byte) {
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=\"%s\"", filename))
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintables_Integration(t *testing.T) {
+	env := SetupIntegrationTest(t)
+	defer env.Cleanup()
+
+	parent := env.CreateTestUser("", "parent")
+	familyID := env.CreateTestFamily(parent.ID)
+	parent.FamilyID = familyID
+	_, err := env.Pool.Exec(context.Background(), `UPDATE users SET family_id = $1 WHERE id = $2`, familyID, parent.ID)
+	require.NoError(t, err)
+	child := env.CreateTestUser(familyID, "child")
+	wordSet := env.CreateTestWordSet(familyID, parent.ID)
+
+	require.NoError(t, env.DB.SaveTestResult(t.Context(), &models.TestResult{
+		WordSetID:    wordSet.ID,
+		UserID:       child.ID,
+		Mode:         string(models.TestModeKeyboard),
+		Score:        50,
+		TotalWords:   2,
+		CorrectWords: 1,
+		CompletedAt:  time.Now().UTC().AddDate(0, 0, -1),
+		Words: []models.WordTestResult{
+			{Word: "skjære", Attempts: 3, FinalAnswer: "sjære", ErrorTypes: []string{"kjSkjSj"}},
+			{Word: "båt", Correct: true, Attempts: 1, FinalAnswer: "båt"},
+		},
+	}))
+
+	env.SetupAuthMiddleware(parent)
+	env.Router.GET("/api/wordsets/:id/worksheet.pdf", GetWordSetWorksheet)
+	env.Router.GET("/api/families/children/:childId/report.pdf", GetChildReport)
+
+	assertPDF := func(t *testing.T, url string) {
+		t.Helper()
+		resp := makeRequest(env.Router, "GET", url, nil, nil)
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		assert.Equal(t, "application/pdf", resp.Header().Get("Content-Type"))
+		assert.Contains(t, resp.Header().Get("Content-Disposition"), ".pdf")
+		assert.True(t, bytes.HasPrefix(resp.Body.Bytes(), []byte("%PDF-")))
+	}
+
+	t.Run("Worksheet", func(t *testing.T) {
+		for _, mode := range []string{"", "dictation", "missingLetters", "answerKey"} {
+			assertPDF(t, "/api/wordsets/"+wordSet.ID+"/worksheet.pdf?lang=en&mode="+mode)
+		}
+	})
+
+	t.Run("Report", func(t *testing.T) {
+		assertPDF(t, "/api/families/children/"+child.ID+"/report.pdf")
+	})
+
+	t.Run("Error_InvalidMode", func(t *testing.T) {
+		resp := makeRequest(env.Router, "GET", "/api/wordsets/"+wordSet.ID+"/worksheet.pdf?mode=crossword", nil, nil)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
+	t.Run("Error_WordSetNotFound", func(t *testing.T) {
+		resp := makeRequest(env.Router, "GET", "/api/wordsets/00000000-0000-0000-0000-000000000000/worksheet.pdf", nil, nil)
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+}
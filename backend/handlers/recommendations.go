@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services"
+	"github.com/starefossen/diktator/backend/internal/services/recommendations"
+)
+
+// @Summary		Get Child Recommendations
+// @Description	Rank family and curated word sets for a child by grade, difficulty, spelling weaknesses and
+// @Description	practice recency. Each suggestion lists the reasons behind its score. Mastered sets are left out.
+// @Tags			children
+// @Produce		json
+// @Param			childId	path		string	true	"Child ID"
+// @Param			limit	query		int		false	"Maximum number of suggestions (default 10, max 50)"
+// @Success		200		{object}	models.APIResponse{data=[]models.WordSetRecommendation}	"Recommended word sets, best first"
+// @Failure		401		{object}	models.APIResponse	"Parent access required"
+// @Failure		404		{object}	models.APIResponse	"Child not found"
+// @Failure		500		{object}	models.APIResponse	"Failed to build recommendations"
+// @Security		BearerAuth
+// @Router			/api/families/children/{childId}/recommendations [get]
+func GetChildRecommendations(c *gin.Context) {
+	serviceManager := GetServiceManager(c)
+	if serviceManager == nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Service unavailable",
+		})
+		return
+	}
+
+	respondRecommendations(c, serviceManager, c.Param("childId"))
+}
+
+// @Summary		Get My Recommendations
+// @Description	Rank family and curated word sets for the current user, with the reasons behind each suggestion
+// @Tags			users
+// @Produce		json
+// @Param			limit	query		int	false	"Maximum number of suggestions (default 10, max 50)"
+// @Success		200		{object}	models.APIResponse{data=[]models.WordSetRecommendation}	"Recommended word sets, best first"
+// @Failure		500		{object}	models.APIResponse	"Failed to build recommendations"
+// @Security		BearerAuth
+// @Router			/api/users/recommendations [get]
+func GetMyRecommendations(c *gin.Context) {
+	serviceManager := GetServiceManager(c)
+	if serviceManager == nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Service unavailable",
+		})
+		return
+	}
+
+	userID, err := getContextString(c, "userID")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	respondRecommendations(c, serviceManager, userID)
+}
+
+func respondRecommendations(c *gin.Context, sm *services.Manager, userID string) {
+	user, err := sm.DB.GetUser(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Error: "Child not found",
+		})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	ranked, err := sm.Recommendations.ForChild(c.Request.Context(), recommendations.Child{
+		BirthYear: user.BirthYear,
+		ID:        user.ID,
+		FamilyID:  user.FamilyID,
+		Level:     user.Level,
+	}, limit)
+	if err != nil {
+		logger().ErrorContext(c.Request.Context(), "Error building recommendations", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Failed to build recommendations",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Data: ranked,
+	})
+}
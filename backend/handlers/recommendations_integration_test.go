@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services/recommendations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetChildRecommendations_Integration(t *testing.T) {
+	env := SetupIntegrationTest(t)
+	defer env.Cleanup()
+
+	parent := env.CreateTestUser("", "parent")
+	familyID := env.CreateTestFamily(parent.ID)
+	parent.FamilyID = familyID
+	_, err := env.Pool.Exec(context.Background(), `UPDATE users SET family_id = $1 WHERE id = $2`, familyID, parent.ID)
+	require.NoError(t, err)
+	child := env.CreateTestUser(familyID, "child")
+
+	practiced := env.CreateTestWordSet(familyID, parent.ID)
+	fresh := env.CreateTestWordSet(familyID, parent.ID)
+	mastered := env.CreateTestWordSet(familyID, parent.ID)
+
+	for _, r := range []struct {
+		wordSetID string
+		score     float64
+	}{{practiced.ID, 60}, {mastered.ID, 100}} {
+		require.NoError(t, env.DB.SaveTestResult(t.Context(), &models.TestResult{
+			UserID:      child.ID,
+			WordSetID:   r.wordSetID,
+			Mode:        string(models.TestModeKeyboard),
+			Score:       r.score,
+			TotalWords:  1,
+			CompletedAt: time.Now().Add(-10 * 24 * time.Hour),
+		}))
+	}
+
+	env.ServiceManager.Recommendations = recommendations.NewService(env.DB)
+	env.SetupAuthMiddleware(parent)
+	env.Router.GET("/api/families/children/:childId/recommendations", GetChildRecommendations)
+
+	t.Run("Success", func(t *testing.T) {
+		resp := makeRequest(env.Router, "GET", "/api/families/children/"+child.ID+"/recommendations", nil, nil)
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var apiResp struct {
+			Data []models.WordSetRecommendation `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &apiResp))
+
+		ids := []string{}
+		for _, rec := range apiResp.Data {
+			ids = append(ids, rec.WordSetID)
+			assert.NotEmpty(t, rec.Reasons)
+		}
+		assert.NotContains(t, ids, mastered.ID)
+		require.GreaterOrEqual(t, len(ids), 2)
+		assert.Equal(t, practiced.ID, ids[0], "unfinished sets due for review rank first")
+		assert.Contains(t, ids, fresh.ID)
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		resp := makeRequest(env.Router, "GET", "/api/families/children/"+child.ID+"/recommendations?limit=1", nil, nil)
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var apiResp struct {
+			Data []models.WordSetRecommendation `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &apiResp))
+		assert.Len(t, apiResp.Data, 1)
+	})
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services"
+	"github.com/starefossen/diktator/backend/internal/services/spelling"
+)
+
+// gradeSentences adds word-level scores to the sentence items of a test result.
+// The word set is only loaded when the test has sentences; if it can't be loaded
+// the sentences are graded without focus words.
+func gradeSentences(ctx context.Context, sm *services.Manager, result *models.TestResult) {
+	hasSentences := false
+	for _, w := range result.Words {
+		if spelling.IsSentence(w.Word) {
+			hasSentences = true
+			break
+		}
+	}
+	if !hasSentences {
+		return
+	}
+
+	wordSet, err := sm.DB.GetWordSet(ctx, result.WordSetID)
+	if err != nil {
+		logger().WarnContext(ctx, "Failed to load word set for sentence scoring", "wordset_id", result.WordSetID, "error", err)
+		wordSet = nil
+	}
+	spelling.GradeSentences(wordSet, result.Words)
+}
+
+// @Summary		Score Sentence
+// @Description	Align a sentence dictation answer with a sentence from the word set, word by word.
+// @Description	Returns per-word correctness, partial credit (full for correct words, half for
+// @Description	misspelled or out-of-order words) and the error types found on the sentence's focus
+// @Description	words. Case and punctuation follow the word set's ignoreCase and ignorePunctuation
+// @Description	test configuration, and are both ignored by default.
+// @Tags			wordsets
+// @Accept			json
+// @Produce		json
+// @Param			id		path		string						true	"Word set ID"
+// @Param			request	body		models.SentenceScoreRequest	true	"Sentence and answer"
+// @Success		200		{object}	models.APIResponse{data=models.SentenceScore}	"Sentence score"
+// @Failure		400		{object}	models.APIResponse	"Invalid request data"
+// @Failure		403		{object}	models.APIResponse	"Word set not accessible"
+// @Failure		404		{object}	models.APIResponse	"Word set not found"
+// @Security		BearerAuth
+// @Router			/api/wordsets/{id}/sentences/score [post]
+func ScoreWordSetSentence(c *gin.Context) {
+	serviceManager := GetServiceManager(c)
+	if serviceManager == nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Service unavailable",
+		})
+		return
+	}
+
+	var req models.SentenceScoreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Error: "Invalid request data",
+		})
+		return
+	}
+
+	wordSet, err := serviceManager.DB.GetWordSet(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Error: "Word set not found",
+		})
+		return
+	}
+
+	score := spelling.ScoreSentence(req.Sentence, req.Answer,
+		spelling.FocusWordsFor(wordSet, req.Sentence), spelling.SentenceOptionsFor(wordSet))
+	c.JSON(http.StatusOK, models.APIResponse{
+		Data: score,
+	})
+}
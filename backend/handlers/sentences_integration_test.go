@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services/xp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSentenceScoring_Integration(t *testing.T) {
+	env := SetupIntegrationTest(t)
+	defer env.Cleanup()
+
+	parent := env.CreateTestUser("", "parent")
+	familyID := env.CreateTestFamily(parent.ID)
+	child := env.CreateTestUser(familyID, "child")
+	wordSet := env.CreateTestWordSet(familyID, parent.ID)
+
+	const sentence = "Jeg ser en hvit båt."
+	sentences, err := json.Marshal([]models.SentenceItem{{Sentence: sentence, FocusWords: []string{"hvit", "båt"}}})
+	require.NoError(t, err)
+	_, err = env.Pool.Exec(context.Background(), `UPDATE word_sets SET sentences = $1 WHERE id = $2`, sentences, wordSet.ID)
+	require.NoError(t, err)
+
+	env.ServiceManager.XP = xp.NewService(env.DB)
+	env.SetupAuthMiddleware(child)
+	env.Router.POST("/api/users/results", SaveResult)
+	env.Router.POST("/api/wordsets/:id/sentences/score", ScoreWordSetSentence)
+
+	t.Run("Success_ScoreEndpoint", func(t *testing.T) {
+		resp := makeRequest(env.Router, "POST", "/api/wordsets/"+wordSet.ID+"/sentences/score",
+			map[string]any{"sentence": sentence, "answer": "jeg ser en vit båt"}, nil)
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+		var apiResp struct {
+			Data models.SentenceScore `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &apiResp))
+		score := apiResp.Data
+		assert.Equal(t, 90.0, score.Score)
+		assert.Equal(t, 2, score.FocusTotal)
+		assert.Equal(t, 1, score.FocusCorrect)
+		assert.Contains(t, score.FocusErrorTypes, "silentH")
+		assert.Equal(t, models.SentenceWordMisspelled, score.Words[3].Status)
+		assert.Equal(t, "vit", score.Words[3].UserWord)
+	})
+
+	t.Run("Success_StoredWithResult", func(t *testing.T) {
+		resp := makeRequest(env.Router, "POST", "/api/users/results", map[string]any{
+			"wordSetId":    wordSet.ID,
+			"mode":         string(models.TestModeKeyboard),
+			"score":        0,
+			"totalWords":   1,
+			"correctWords": 0,
+			"words": []map[string]any{{
+				"word":        sentence,
+				"finalAnswer": "jeg ser en vit båt",
+				"userAnswers": []string{"jeg ser en vit båt"},
+				"attempts":    1,
+			}},
+		}, nil)
+		require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+
+		results, err := env.DB.GetTestResults(t.Context(), child.ID)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Len(t, results[0].Words, 1)
+		word := results[0].Words[0]
+		require.NotNil(t, word.Sentence)
+		assert.Equal(t, 90.0, word.Sentence.Score)
+		assert.Len(t, word.Sentence.Words, 5)
+		assert.Contains(t, word.ErrorTypes, "silentH", "focus word errors feed the error analytics")
+	})
+
+	t.Run("Error_MissingSentence", func(t *testing.T) {
+		resp := makeRequest(env.Router, "POST", "/api/wordsets/"+wordSet.ID+"/sentences/score",
+			map[string]any{"answer": "hei"}, nil)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+}
@@ -0,0 +1,382 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services"
+	"github.com/starefossen/diktator/backend/internal/services/db"
+	"github.com/starefossen/diktator/backend/internal/services/events"
+	"github.com/starefossen/diktator/backend/internal/services/realtime"
+	"github.com/starefossen/diktator/backend/internal/services/session"
+)
+
+// @Summary		Start Test Session
+// @Description	Start a server-side test session. The server picks word order, mode and translation
+// @Description	directions from the word set's test configuration and judges each answer.
+// @Tags			sessions
+// @Accept			json
+// @Produce		json
+// @Param			request	body		models.StartTestSessionRequest	true	"Word set and optional mode"
+// @Success		201		{object}	models.APIResponse{data=models.TestSession}	"Session started"
+// @Failure		400		{object}	models.APIResponse	"Invalid request data"
+// @Failure		403		{object}	models.APIResponse	"Access denied"
+// @Failure		500		{object}	models.APIResponse	"Failed to start session"
+// @Security		BearerAuth
+// @Router			/api/users/sessions [post]
+func StartTestSession(c *gin.Context) {
+	serviceManager, userID, ok := sessionContext(c)
+	if !ok {
+		return
+	}
+
+	familyID, err := getContextString(c, "validatedFamilyID")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid family ID"})
+		return
+	}
+
+	var req models.StartTestSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Error: "Invalid request data",
+		})
+		return
+	}
+
+	if err := serviceManager.DB.VerifyWordSetAccess(c.Request.Context(), familyID, req.WordSetID); err != nil {
+		c.JSON(http.StatusForbidden, models.APIResponse{
+			Error: "Access denied",
+		})
+		return
+	}
+
+	wordSet, err := serviceManager.DB.GetWordSet(c.Request.Context(), req.WordSetID)
+	if err != nil {
+		logger().ErrorContext(c.Request.Context(), "Error loading word set for session", "user_id", userID, "wordset_id", req.WordSetID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Failed to start session",
+		})
+		return
+	}
+
+	now := time.Now()
+	testSession, err := serviceManager.Sessions.Start(wordSet, userID, familyID, req.Mode, now)
+	if err != nil {
+		respondSessionError(c, err, "Failed to start session")
+		return
+	}
+
+	if err := serviceManager.DB.CreateTestSession(c.Request.Context(), testSession); err != nil {
+		logger().ErrorContext(c.Request.Context(), "Error creating test session", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Failed to start session",
+		})
+		return
+	}
+
+	publishLiveEvent(c, serviceManager, events.New(realtime.TypeTestStarted, familyID, userID, map[string]any{
+		"sessionId":  testSession.ID,
+		"wordSetId":  testSession.WordSetID,
+		"mode":       testSession.Mode,
+		"totalWords": len(testSession.Words),
+	}))
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Data:    publicSession(testSession),
+		Message: "Session started",
+	})
+}
+
+// @Summary		Get Active Test Sessions
+// @Description	Get the current user's unfinished, unexpired test sessions, most recent first
+// @Tags			sessions
+// @Produce		json
+// @Success		200	{object}	models.APIResponse{data=[]models.TestSession}	"Active sessions"
+// @Failure		500	{object}	models.APIResponse	"Failed to retrieve sessions"
+// @Security		BearerAuth
+// @Router			/api/users/sessions [get]
+func GetActiveTestSessions(c *gin.Context) {
+	serviceManager, userID, ok := sessionContext(c)
+	if !ok {
+		return
+	}
+
+	sessions, err := serviceManager.DB.GetActiveTestSessions(c.Request.Context(), userID, time.Now())
+	if err != nil {
+		logger().ErrorContext(c.Request.Context(), "Error listing test sessions", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Failed to retrieve sessions",
+		})
+		return
+	}
+
+	public := make([]*models.TestSession, len(sessions))
+	for i := range sessions {
+		public[i] = publicSession(&sessions[i])
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Data: public,
+	})
+}
+
+// @Summary		Get Test Session
+// @Description	Get one of the current user's test sessions
+// @Tags			sessions
+// @Produce		json
+// @Param			sessionId	path		string	true	"Session ID"
+// @Success		200			{object}	models.APIResponse{data=models.TestSession}	"Session"
+// @Failure		404			{object}	models.APIResponse	"Session not found"
+// @Security		BearerAuth
+// @Router			/api/users/sessions/{sessionId} [get]
+func GetTestSession(c *gin.Context) {
+	serviceManager, userID, ok := sessionContext(c)
+	if !ok {
+		return
+	}
+
+	testSession, ok := loadTestSession(c, serviceManager, userID)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Data: publicSession(testSession),
+	})
+}
+
+// @Summary		Resume Test Session
+// @Description	Resume a session after a reload. Timing of the current word restarts so idle time is not counted.
+// @Tags			sessions
+// @Produce		json
+// @Param			sessionId	path		string	true	"Session ID"
+// @Success		200			{object}	models.APIResponse{data=models.TestSession}	"Session resumed"
+// @Failure		404			{object}	models.APIResponse	"Session not found"
+// @Failure		409			{object}	models.APIResponse	"Session already completed"
+// @Failure		410			{object}	models.APIResponse	"Session expired"
+// @Security		BearerAuth
+// @Router			/api/users/sessions/{sessionId}/resume [post]
+func ResumeTestSession(c *gin.Context) {
+	serviceManager, userID, ok := sessionContext(c)
+	if !ok {
+		return
+	}
+
+	testSession, ok := loadTestSession(c, serviceManager, userID)
+	if !ok {
+		return
+	}
+
+	if err := serviceManager.Sessions.Resume(testSession, time.Now()); err != nil {
+		respondSessionError(c, err, "Failed to resume session")
+		return
+	}
+
+	if err := serviceManager.DB.UpdateTestSession(c.Request.Context(), testSession); err != nil {
+		respondSessionError(c, err, "Failed to resume session")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Data:    publicSession(testSession),
+		Message: "Session resumed",
+	})
+}
+
+// @Summary		Record Answer
+// @Description	Submit an attempt at the session's current word. The server judges the answer, records
+// @Description	the time spent and moves to the next word when the word is right or out of attempts.
+// @Description	The expected answer is only revealed once the word is complete.
+// @Tags			sessions
+// @Accept			json
+// @Produce		json
+// @Param			sessionId	path		string						true	"Session ID"
+// @Param			request		body		models.RecordAnswerRequest	true	"Answer"
+// @Success		200			{object}	models.APIResponse{data=models.RecordAnswerResponse}	"Answer recorded"
+// @Failure		400			{object}	models.APIResponse	"Invalid request data"
+// @Failure		404			{object}	models.APIResponse	"Session not found"
+// @Failure		409			{object}	models.APIResponse	"Session finished or modified concurrently"
+// @Failure		410			{object}	models.APIResponse	"Session expired"
+// @Security		BearerAuth
+// @Router			/api/users/sessions/{sessionId}/answers [post]
+func RecordSessionAnswer(c *gin.Context) {
+	serviceManager, userID, ok := sessionContext(c)
+	if !ok {
+		return
+	}
+
+	var req models.RecordAnswerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Error: "Invalid request data",
+		})
+		return
+	}
+
+	testSession, ok := loadTestSession(c, serviceManager, userID)
+	if !ok {
+		return
+	}
+
+	wordIndex := testSession.CurrentIndex
+	resp, err := serviceManager.Sessions.Answer(testSession, req, time.Now())
+	if err != nil {
+		respondSessionError(c, err, "Failed to record answer")
+		return
+	}
+
+	if err := serviceManager.DB.UpdateTestSession(c.Request.Context(), testSession); err != nil {
+		respondSessionError(c, err, "Failed to record answer")
+		return
+	}
+
+	publishLiveEvent(c, serviceManager, events.New(realtime.TypeWordAnswered, testSession.FamilyID, userID, map[string]any{
+		"sessionId":  testSession.ID,
+		"wordSetId":  testSession.WordSetID,
+		"mode":       testSession.Mode,
+		"word":       testSession.Words[wordIndex].Word,
+		"wordIndex":  wordIndex,
+		"totalWords": len(testSession.Words),
+		"attempt":    resp.Attempts,
+		"correct":    resp.Correct,
+	}))
+
+	resp.Session = publicSession(testSession)
+	c.JSON(http.StatusOK, models.APIResponse{
+		Data: resp,
+	})
+}
+
+// @Summary		Complete Test Session
+// @Description	Finalize a session whose words are all answered into a test result. XP is awarded and
+// @Description	the result is saved exactly as with POST /api/users/results.
+// @Tags			sessions
+// @Produce		json
+// @Param			sessionId	path		string	true	"Session ID"
+// @Success		201			{object}	models.APIResponse{data=models.SaveResultResponse}	"Test result saved"
+// @Failure		404			{object}	models.APIResponse	"Session not found"
+// @Failure		409			{object}	models.APIResponse	"Session incomplete or already completed"
+// @Failure		410			{object}	models.APIResponse	"Session expired"
+// @Failure		500			{object}	models.APIResponse	"Failed to save test result"
+// @Security		BearerAuth
+// @Router			/api/users/sessions/{sessionId}/complete [post]
+func CompleteTestSession(c *gin.Context) {
+	serviceManager, userID, ok := sessionContext(c)
+	if !ok {
+		return
+	}
+
+	testSession, ok := loadTestSession(c, serviceManager, userID)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	result, err := serviceManager.Sessions.Result(testSession, now)
+	if err != nil {
+		respondSessionError(c, err, "Failed to complete session")
+		return
+	}
+
+	// Claim the session before saving so a double submit cannot save twice.
+	// The result link is written once the result row exists.
+	serviceManager.Sessions.Complete(testSession, result.ID, now)
+	testSession.ResultID = nil
+	if err := serviceManager.DB.UpdateTestSession(c.Request.Context(), testSession); err != nil {
+		respondSessionError(c, err, "Failed to complete session")
+		return
+	}
+
+	response, err := recordTestResult(c, serviceManager, result)
+	if err != nil {
+		logger().ErrorContext(c.Request.Context(), "Error saving result for session", "session_id", testSession.ID, "user_id", userID, "error", err)
+		// Reopen the session so the child can retry instead of losing the test
+		testSession.Status = models.TestSessionActive
+		testSession.CompletedAt = nil
+		if err := serviceManager.DB.UpdateTestSession(c.Request.Context(), testSession); err != nil {
+			logger().WarnContext(c.Request.Context(), "Failed to reopen session", "session_id", testSession.ID, "error", err)
+		}
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Failed to save test result",
+		})
+		return
+	}
+
+	testSession.ResultID = &result.ID
+	if err := serviceManager.DB.UpdateTestSession(c.Request.Context(), testSession); err != nil {
+		logger().WarnContext(c.Request.Context(), "Failed to link session to result", "session_id", testSession.ID, "result_id", result.ID, "error", err)
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Data:    response,
+		Message: "Test result saved successfully",
+	})
+}
+
+// sessionContext resolves the service manager and user for a session request,
+// writing the error response when either is missing
+func sessionContext(c *gin.Context) (*services.Manager, string, bool) {
+	serviceManager := GetServiceManager(c)
+	if serviceManager == nil || serviceManager.Sessions == nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Service unavailable",
+		})
+		return nil, "", false
+	}
+
+	userID, err := getContextString(c, "userID")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return nil, "", false
+	}
+
+	return serviceManager, userID, true
+}
+
+func loadTestSession(c *gin.Context, sm *services.Manager, userID string) (*models.TestSession, bool) {
+	testSession, err := sm.DB.GetTestSession(c.Request.Context(), userID, c.Param("sessionId"))
+	if err != nil {
+		respondSessionError(c, err, "Failed to retrieve session")
+		return nil, false
+	}
+	return testSession, true
+}
+
+// publicSession returns a copy of the session safe to send to the client, with
+// the expected answer removed from words that are not complete yet
+func publicSession(s *models.TestSession) *models.TestSession {
+	public := *s
+	public.Words = make([]models.SessionWord, len(s.Words))
+	for i, w := range s.Words {
+		if w.CompletedAt == nil {
+			w.ExpectedAnswer = ""
+		}
+		public.Words[i] = w
+	}
+	return &public
+}
+
+// respondSessionError maps session and storage errors to HTTP responses
+func respondSessionError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, db.ErrNotFound):
+		c.JSON(http.StatusNotFound, models.APIResponse{Error: "Session not found"})
+	case errors.Is(err, session.ErrSessionExpired):
+		c.JSON(http.StatusGone, models.APIResponse{Error: "Session expired"})
+	case errors.Is(err, session.ErrSessionCompleted):
+		c.JSON(http.StatusConflict, models.APIResponse{Error: "Session already completed"})
+	case errors.Is(err, session.ErrSessionFinished), errors.Is(err, session.ErrIncomplete):
+		c.JSON(http.StatusConflict, models.APIResponse{Error: err.Error()})
+	case errors.Is(err, db.ErrConflict):
+		c.JSON(http.StatusConflict, models.APIResponse{Error: "Session was modified concurrently, reload and retry"})
+	case errors.Is(err, session.ErrNoWords), errors.Is(err, session.ErrInvalidMode), errors.Is(err, session.ErrNoTranslations):
+		c.JSON(http.StatusBadRequest, models.APIResponse{Error: err.Error()})
+	default:
+		logger().ErrorContext(c.Request.Context(), fallback, "error", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{Error: fallback})
+	}
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestSessions_Integration(t *testing.T) {
+	env := SetupIntegrationTest(t)
+	defer env.Cleanup()
+
+	parent := env.CreateTestUser("", "parent")
+	familyID := env.CreateTestFamily(parent.ID)
+	child := env.CreateTestUser(familyID, "child")
+
+	wordSet := env.CreateTestWordSet(familyID, parent.ID)
+	for _, w := range []string{"hus", "bil"} {
+		wordSet.Words = append(wordSet.Words, struct {
+			Word         string               `json:"word"`
+			Audio        models.WordAudio     `json:"audio,omitempty"`
+			Definition   string               `json:"definition,omitempty"`
+			Translations []models.Translation `json:"translations,omitempty"`
+		}{Word: w})
+	}
+	require.NoError(t, env.DB.UpdateWordSet(t.Context(), wordSet))
+
+	env.ServiceManager.Sessions = session.NewEngine(session.DefaultConfig(), nil)
+	env.SetupAuthMiddleware(child)
+	env.Router.POST("/api/users/sessions", StartTestSession)
+	env.Router.GET("/api/users/sessions", GetActiveTestSessions)
+	env.Router.GET("/api/users/sessions/:sessionId", GetTestSession)
+	env.Router.POST("/api/users/sessions/:sessionId/resume", ResumeTestSession)
+	env.Router.POST("/api/users/sessions/:sessionId/answers", RecordSessionAnswer)
+	env.Router.POST("/api/users/sessions/:sessionId/complete", CompleteTestSession)
+
+	var sessionID string
+
+	t.Run("Error_OtherFamilyWordSet", func(t *testing.T) {
+		otherParent := env.CreateTestUser("", "parent")
+		otherFamilyID := env.CreateTestFamily(otherParent.ID)
+		otherWordSet := env.CreateTestWordSet(otherFamilyID, otherParent.ID)
+
+		resp := makeRequest(env.Router, "POST", "/api/users/sessions", map[string]any{"wordSetId": otherWordSet.ID}, nil)
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+		env.AssertNoRowsInTable("test_sessions")
+	})
+
+	t.Run("Success_Start", func(t *testing.T) {
+		resp := makeRequest(env.Router, "POST", "/api/users/sessions", map[string]any{"wordSetId": wordSet.ID}, nil)
+		require.Equal(t, http.StatusCreated, resp.Code)
+
+		var apiResp struct {
+			Data models.TestSession `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &apiResp))
+		sessionID = apiResp.Data.ID
+		assert.Equal(t, models.TestSessionActive, apiResp.Data.Status)
+		assert.Len(t, apiResp.Data.Words, 2)
+		env.AssertRowCount("test_sessions", 1)
+	})
+
+	t.Run("Success_ResumeAfterReload", func(t *testing.T) {
+		resp := makeRequest(env.Router, "GET", "/api/users/sessions", nil, nil)
+		require.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), sessionID)
+
+		resp = makeRequest(env.Router, "POST", "/api/users/sessions/"+sessionID+"/resume", nil, nil)
+		assert.Equal(t, http.StatusOK, resp.Code)
+	})
+
+	t.Run("Error_CompleteTooEarly", func(t *testing.T) {
+		resp := makeRequest(env.Router, "POST", "/api/users/sessions/"+sessionID+"/complete", nil, nil)
+		assert.Equal(t, http.StatusConflict, resp.Code)
+		env.AssertNoRowsInTable("test_results")
+	})
+
+	t.Run("Success_Answers", func(t *testing.T) {
+		answer := func(text string) models.RecordAnswerResponse {
+			resp := makeRequest(env.Router, "POST", "/api/users/sessions/"+sessionID+"/answers", map[string]any{"answer": text}, nil)
+			require.Equal(t, http.StatusOK, resp.Code)
+
+			var apiResp struct {
+				Data models.RecordAnswerResponse `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &apiResp))
+			return apiResp.Data
+		}
+
+		first := answer("Hus")
+		assert.True(t, first.Correct)
+		assert.True(t, first.WordComplete)
+
+		assert.False(t, answer("bill").WordComplete)
+		last := answer("bil")
+		assert.True(t, last.Finished)
+		assert.Equal(t, 2, last.Attempts)
+	})
+
+	t.Run("Success_Complete", func(t *testing.T) {
+		resp := makeRequest(env.Router, "POST", "/api/users/sessions/"+sessionID+"/complete", nil, nil)
+		require.Equal(t, http.StatusCreated, resp.Code)
+
+		var apiResp struct {
+			Data models.SaveResultResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &apiResp))
+		assert.Equal(t, 85.0, apiResp.Data.TestResult.Score)
+		assert.Equal(t, 2, apiResp.Data.TestResult.CorrectWords)
+		env.AssertRowCount("test_results", 1)
+
+		stored, err := env.DB.GetTestSession(t.Context(), child.ID, sessionID)
+		require.NoError(t, err)
+		assert.Equal(t, models.TestSessionCompleted, stored.Status)
+		require.NotNil(t, stored.ResultID)
+		assert.Equal(t, apiResp.Data.TestResult.ID, *stored.ResultID)
+	})
+
+	t.Run("Error_CompleteTwice", func(t *testing.T) {
+		resp := makeRequest(env.Router, "POST", "/api/users/sessions/"+sessionID+"/complete", nil, nil)
+		assert.Equal(t, http.StatusConflict, resp.Code)
+		env.AssertRowCount("test_results", 1)
+	})
+
+	t.Run("Error_OtherUsersSession", func(t *testing.T) {
+		sibling := env.CreateTestUser(familyID, "child")
+		router := gin.New()
+		router.Use(func(c *gin.Context) {
+			c.Set("serviceManager", env.ServiceManager)
+			c.Set("userID", sibling.ID)
+			c.Set("validatedFamilyID", familyID)
+			c.Next()
+		})
+		router.GET("/api/users/sessions/:sessionId", GetTestSession)
+
+		resp := makeRequest(router, "GET", "/api/users/sessions/"+sessionID, nil, nil)
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+}
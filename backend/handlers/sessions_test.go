@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/starefossen/diktator/backend/internal/services"
+	"github.com/starefossen/diktator/backend/internal/services/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestSessions_ExpectedAnswerHiddenUntilComplete(t *testing.T) {
+	f := newTxFamily(t)
+	ws, err := f.repo.GetWordSet(t.Context(), f.wordSet)
+	require.NoError(t, err)
+	ws.Words = append(ws.Words, ws.Words[0])
+	ws.Words[1].Word = "bil"
+	require.NoError(t, f.repo.UpdateWordSet(t.Context(), ws))
+
+	gin.SetMode(gin.TestMode)
+	sm := &services.Manager{DB: f.repo, Sessions: session.NewEngine(session.DefaultConfig(), nil)}
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("serviceManager", sm)
+		c.Set("userID", f.childID)
+		c.Set("validatedFamilyID", f.familyID)
+		c.Next()
+	})
+	router.POST("/api/users/sessions", StartTestSession)
+	router.GET("/api/users/sessions", GetActiveTestSessions)
+	router.GET("/api/users/sessions/:sessionId", GetTestSession)
+	router.POST("/api/users/sessions/:sessionId/resume", ResumeTestSession)
+	router.POST("/api/users/sessions/:sessionId/answers", RecordSessionAnswer)
+
+	serve := func(method, path string, body any) *httptest.ResponseRecorder {
+		data, _ := json.Marshal(body)
+		req := httptest.NewRequest(method, path, bytes.NewReader(data))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// words decodes the session words from a response as raw JSON objects
+	words := func(w *httptest.ResponseRecorder) []map[string]any {
+		t.Helper()
+		var body struct {
+			Data struct {
+				Words []map[string]any `json:"words"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return body.Data.Words
+	}
+
+	w := serve(http.MethodPost, "/api/users/sessions", map[string]any{"wordSetId": f.wordSet})
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.NotContains(t, w.Body.String(), "expectedAnswer")
+	var started struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &started))
+	path := "/api/users/sessions/" + started.Data.ID
+
+	for _, p := range []string{"/api/users/sessions", path} {
+		w = serve(http.MethodGet, p, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "expectedAnswer", p)
+	}
+	w = serve(http.MethodPost, path+"/resume", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "expectedAnswer")
+
+	w = serve(http.MethodPost, path+"/answers", map[string]any{"answer": "hus"})
+	require.Equal(t, http.StatusOK, w.Code)
+	var answered struct {
+		Data struct {
+			Session struct {
+				Words []map[string]any `json:"words"`
+			} `json:"session"`
+			ExpectedAnswer string `json:"expectedAnswer"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &answered))
+	assert.Equal(t, "hus", answered.Data.ExpectedAnswer)
+	require.Len(t, answered.Data.Session.Words, 2)
+	assert.NotContains(t, answered.Data.Session.Words[1], "expectedAnswer", "the next word stays hidden")
+
+	w = serve(http.MethodGet, path, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	got := words(w)
+	require.Len(t, got, 2)
+	assert.Equal(t, "hus", got[0]["expectedAnswer"], "revealed once the word is complete")
+	assert.NotContains(t, got[1], "expectedAnswer", "hidden while the word is pending")
+}
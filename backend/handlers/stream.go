@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services/events"
+	"github.com/starefossen/diktator/backend/internal/services/realtime"
+)
+
+// Live event stream timing
+const (
+	streamHeartbeatInterval = 25 * time.Second // Keeps proxies from closing idle streams
+	streamRetryDelay        = 5 * time.Second  // Client reconnect delay sent to EventSource
+)
+
+// @Summary		Stream Family Events
+// @Description	Server-sent event stream of the family's live events (test.started, test.word_answered,
+// @Description	test.completed, xp.awarded, xp.level_up, ...). Each message has the event type as its
+// @Description	SSE event name and the JSON event as data. Authenticate with the Authorization header,
+// @Description	so use a fetch-based SSE client rather than the browser EventSource (parent only).
+// @Tags			families
+// @Produce		text/event-stream
+// @Success		200	{string}	string	"Event stream"
+// @Failure		401	{object}	models.APIResponse	"Parent access required"
+// @Failure		503	{object}	models.APIResponse	"Live events unavailable"
+// @Security		BearerAuth
+// @Router			/api/families/events/stream [get]
+func StreamFamilyEvents(c *gin.Context) {
+	serviceManager := GetServiceManager(c)
+	if serviceManager == nil || serviceManager.Realtime == nil {
+		c.JSON(http.StatusServiceUnavailable, models.APIResponse{
+			Error: "Live events unavailable",
+		})
+		return
+	}
+
+	familyID, err := getContextString(c, "validatedFamilyID")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid family ID"})
+		return
+	}
+
+	// The server's WriteTimeout would cut the stream short; lift it for this response
+	rc := http.NewResponseController(c.Writer)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		logger().WarnContext(c.Request.Context(), "Failed to clear write deadline for event stream", "error", err)
+	}
+
+	sub := serviceManager.Realtime.Subscribe(familyID)
+	defer sub.Close()
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	if _, err := fmt.Fprintf(c.Writer, "retry: %d\n\n", streamRetryDelay.Milliseconds()); err != nil {
+		return
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	ctx := c.Request.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(c.Writer, ": ping\n\n"); err != nil {
+				return
+			}
+		case event, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			if err := writeSSE(c.Writer, event); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+	}
+}
+
+func writeSSE(w gin.ResponseWriter, event events.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		logger().Error("Error encoding event for stream", "event_type", event.Type, "event_id", event.ID, "error", err)
+		return nil
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
+
+// @Summary		Report Live Activity
+// @Description	Report test progress so parents watching the family's event stream see it live.
+// @Description	Activity is streamed only; it is not stored and not sent to webhooks.
+// @Tags			users
+// @Accept			json
+// @Produce		json
+// @Param			request	body		models.LiveActivityRequest	true	"Test progress"
+// @Success		202		{object}	models.APIResponse	"Activity published"
+// @Failure		400		{object}	models.APIResponse	"Invalid request data"
+// @Security		BearerAuth
+// @Router			/api/users/activity [post]
+func ReportLiveActivity(c *gin.Context) {
+	serviceManager := GetServiceManager(c)
+	if serviceManager == nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Service unavailable",
+		})
+		return
+	}
+
+	userID, err := getContextString(c, "userID")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	familyID, err := getContextString(c, "validatedFamilyID")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid family ID"})
+		return
+	}
+
+	var req models.LiveActivityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Error: "Invalid request data",
+		})
+		return
+	}
+
+	data := map[string]any{
+		"wordSetId":  req.WordSetID,
+		"mode":       req.Mode,
+		"wordIndex":  req.WordIndex,
+		"totalWords": req.TotalWords,
+	}
+	eventType := events.Type(req.Type)
+	if eventType == realtime.TypeWordAnswered {
+		data["word"] = req.Word
+		data["attempt"] = req.Attempt
+		data["correct"] = req.Correct
+	}
+
+	publishLiveEvent(c, serviceManager, events.New(eventType, familyID, userID, data))
+
+	c.JSON(http.StatusAccepted, models.APIResponse{
+		Message: "Activity published",
+	})
+}
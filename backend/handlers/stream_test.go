@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/starefossen/diktator/backend/internal/services"
+	"github.com/starefossen/diktator/backend/internal/services/events"
+	"github.com/starefossen/diktator/backend/internal/services/realtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupStreamRouter(hub *realtime.MemoryHub, familyID string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	sm := &services.Manager{Realtime: hub}
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("serviceManager", sm)
+		c.Set("userID", "child-1")
+		c.Set("validatedFamilyID", familyID)
+		c.Next()
+	})
+	router.GET("/api/families/events/stream", StreamFamilyEvents)
+	router.POST("/api/users/activity", ReportLiveActivity)
+	return router
+}
+
+// readSSEMessage reads lines until a blank line ends the next SSE message
+func readSSEMessage(t *testing.T, r *bufio.Reader) map[string]string {
+	t.Helper()
+	done := make(chan map[string]string, 1)
+	go func() {
+		msg := map[string]string{}
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				done <- msg
+				return
+			}
+			line = strings.TrimRight(line, "\n")
+			if line == "" {
+				done <- msg
+				return
+			}
+			if field, value, ok := strings.Cut(line, ": "); ok {
+				msg[field] = value
+			}
+		}
+	}()
+
+	select {
+	case msg := <-done:
+		return msg
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for SSE message")
+		return nil
+	}
+}
+
+func TestStreamFamilyEvents(t *testing.T) {
+	hub := realtime.NewMemoryHub(realtime.DefaultBufferSize, nil)
+	server := httptest.NewServer(setupStreamRouter(hub, "family-1"))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", server.URL+"/api/families/events/stream", nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(resp.Body)
+	readSSEMessage(t, reader) // retry hint
+	require.Equal(t, 1, hub.Subscribers("family-1"))
+
+	t.Run("LiveActivity", func(t *testing.T) {
+		body, _ := json.Marshal(map[string]any{
+			"type":       "test.word_answered",
+			"wordSetId":  "ws-1",
+			"word":       "skjære",
+			"wordIndex":  2,
+			"totalWords": 10,
+			"attempt":    1,
+			"correct":    true,
+		})
+		post, err := http.Post(server.URL+"/api/users/activity", "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		post.Body.Close()
+		require.Equal(t, http.StatusAccepted, post.StatusCode)
+
+		msg := readSSEMessage(t, reader)
+		assert.Equal(t, string(realtime.TypeWordAnswered), msg["event"])
+
+		var event events.Event
+		require.NoError(t, json.Unmarshal([]byte(msg["data"]), &event))
+		assert.Equal(t, msg["id"], event.ID)
+		assert.Equal(t, "family-1", event.FamilyID)
+		assert.Equal(t, "child-1", event.UserID)
+		assert.Equal(t, "skjære", event.Data["word"])
+		assert.Equal(t, true, event.Data["correct"])
+	})
+
+	t.Run("OtherFamiliesAreNotStreamed", func(t *testing.T) {
+		require.NoError(t, hub.Publish(context.Background(), events.New(events.TypeTestCompleted, "family-2", "child-9", nil)))
+		require.NoError(t, hub.Publish(context.Background(), events.New(events.TypeTestCompleted, "family-1", "child-1", nil)))
+
+		msg := readSSEMessage(t, reader)
+		assert.Equal(t, string(events.TypeTestCompleted), msg["event"])
+		assert.Contains(t, msg["data"], `"familyId":"family-1"`)
+	})
+
+	t.Run("DisconnectUnsubscribes", func(t *testing.T) {
+		cancel()
+		assert.Eventually(t, func() bool { return hub.Subscribers("family-1") == 0 }, 2*time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestReportLiveActivity_InvalidType(t *testing.T) {
+	router := setupStreamRouter(realtime.NewMemoryHub(1, nil), "family-1")
+
+	resp := makeRequest(router, "POST", "/api/users/activity", map[string]any{"type": "test.completed", "wordSetId": "ws-1"}, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
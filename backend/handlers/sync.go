@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services"
+	"github.com/starefossen/diktator/backend/internal/services/db"
+)
+
+// maxOfflineResultAge is how long a client may hold a result before syncing it
+const maxOfflineResultAge = 30 * 24 * time.Hour
+
+// Result validation errors
+var (
+	errResultTooOld             = errors.New("test result is too old to sync")
+	errMissingIdempotencyKey    = errors.New("synced result has no idempotency key")
+	errTranslationWordSet       = errors.New("failed to validate word set for translation mode")
+	errTranslationsNotAvailable = errors.New("word set has no translations")
+)
+
+// resultValidationError returns the client message for errors caused by the request itself
+func resultValidationError(err error) (string, bool) {
+	switch {
+	case errors.Is(err, errResultTooOld):
+		return "Test result is too old to sync", true
+	case errors.Is(err, errMissingIdempotencyKey):
+		return "Each synced result needs an idempotency key", true
+	case errors.Is(err, errTranslationWordSet):
+		return "Failed to validate wordset for translation mode", true
+	case errors.Is(err, errTranslationsNotAvailable):
+		return "Cannot save translation mode result: wordset has no translations", true
+	default:
+		return "", false
+	}
+}
+
+// saveResultRequest saves a submitted test result. When the request carries an
+// idempotency key that was already used, the stored result is returned instead
+// and created is false; no XP is awarded twice.
+func saveResultRequest(c *gin.Context, sm *services.Manager, userID string, req models.SaveResultRequest, now time.Time) (*models.SaveResultResponse, bool, error) {
+	if req.IdempotencyKey != "" {
+		existing, err := sm.DB.GetTestResultByIdempotencyKey(c.Request.Context(), userID, req.IdempotencyKey)
+		if err == nil {
+			return &models.SaveResultResponse{TestResult: existing}, false, nil
+		}
+		if !errors.Is(err, db.ErrNotFound) {
+			return nil, false, err
+		}
+	}
+
+	completedAt := now
+	if req.CompletedAt != nil {
+		if now.Sub(*req.CompletedAt) > maxOfflineResultAge {
+			return nil, false, errResultTooOld
+		}
+		// Clock skew can put a queued result slightly in the future
+		if req.CompletedAt.Before(now) {
+			completedAt = *req.CompletedAt
+		}
+	}
+
+	if req.Mode == string(models.TestModeTranslation) {
+		wordSet, err := sm.DB.GetWordSet(c.Request.Context(), req.WordSetID)
+		if err != nil {
+			return nil, false, errTranslationWordSet
+		}
+		hasTranslations := false
+		for _, word := range wordSet.Words {
+			if len(word.Translations) > 0 {
+				hasTranslations = true
+				break
+			}
+		}
+		if !hasTranslations {
+			return nil, false, errTranslationsNotAvailable
+		}
+	}
+
+	result := &models.TestResult{
+		ID:             uuid.New().String(),
+		WordSetID:      req.WordSetID,
+		UserID:         userID,
+		Score:          req.Score,
+		TotalWords:     req.TotalWords,
+		CorrectWords:   req.CorrectWords,
+		Mode:           req.Mode,
+		IdempotencyKey: req.IdempotencyKey,
+		IncorrectWords: req.IncorrectWords, // Keep for backward compatibility
+		Words:          req.Words,          // New detailed word information
+		TimeSpent:      req.TimeSpent,
+		CompletedAt:    completedAt,
+		CreatedAt:      now,
+	}
+
+	response, err := recordTestResult(c, sm, result)
+	if errors.Is(err, db.ErrDuplicate) && req.IdempotencyKey != "" {
+		// A concurrent request with the same key won the insert
+		existing, lookupErr := sm.DB.GetTestResultByIdempotencyKey(c.Request.Context(), userID, req.IdempotencyKey)
+		if lookupErr != nil {
+			return nil, false, fmt.Errorf("failed to load duplicate result: %w", lookupErr)
+		}
+		return &models.SaveResultResponse{TestResult: existing}, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return response, true, nil
+}
+
+// @Summary		Sync Offline Results
+// @Description	Upload test results queued while the client was offline. Every result needs an idempotency
+// @Description	key and should carry its original completedAt. Results are saved oldest first so XP repetition
+// @Description	decay is applied as if each test had been synced when it was taken. Results whose key was already
+// @Description	saved are reported as duplicates; one failing result does not stop the others.
+// @Tags			users
+// @Accept			json
+// @Produce		json
+// @Param			request	body		models.SyncResultsRequest	true	"Queued test results"
+// @Success		200		{object}	models.APIResponse{data=models.SyncResultsResponse}	"Per-result sync outcome"
+// @Failure		400		{object}	models.APIResponse	"Invalid request data"
+// @Security		BearerAuth
+// @Router			/api/users/results/sync [post]
+func SyncResults(c *gin.Context) {
+	serviceManager := GetServiceManager(c)
+	if serviceManager == nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Service unavailable",
+		})
+		return
+	}
+
+	userID, err := getContextString(c, "userID")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req models.SyncResultsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Error: "Invalid request data",
+		})
+		return
+	}
+
+	now := time.Now()
+	queued := req.Results
+	sort.SliceStable(queued, func(i, j int) bool {
+		return syncTime(queued[i], now).Before(syncTime(queued[j], now))
+	})
+
+	response := models.SyncResultsResponse{Results: make([]models.SyncResultItem, 0, len(queued))}
+	for _, r := range queued {
+		item := syncResult(c, serviceManager, userID, r, now)
+		switch item.Status {
+		case models.SyncStatusCreated:
+			response.Created++
+		case models.SyncStatusDuplicate:
+			response.Duplicate++
+		default:
+			response.Failed++
+		}
+		response.Results = append(response.Results, item)
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Data: response,
+	})
+}
+
+// syncResult saves one queued result and reports its outcome
+func syncResult(c *gin.Context, sm *services.Manager, userID string, r models.SaveResultRequest, now time.Time) models.SyncResultItem {
+	item := models.SyncResultItem{IdempotencyKey: r.IdempotencyKey}
+
+	var saved *models.SaveResultResponse
+	var created bool
+	err := errMissingIdempotencyKey
+	if r.IdempotencyKey != "" {
+		saved, created, err = saveResultRequest(c, sm, userID, r, now)
+	}
+	if err != nil {
+		item.Status = models.SyncStatusFailed
+		item.Error = "Failed to save test result"
+		if msg, ok := resultValidationError(err); ok {
+			item.Error = msg
+		} else {
+			logger().ErrorContext(c.Request.Context(), "Error syncing test result", "user_id", userID, "idempotency_key", r.IdempotencyKey, "error", err)
+		}
+		return item
+	}
+
+	item.Result = saved.TestResult
+	item.XP = saved.XP
+	item.Status = models.SyncStatusDuplicate
+	if created {
+		item.Status = models.SyncStatusCreated
+	}
+	return item
+}
+
+// syncTime orders queued results; results without a completion time sort last
+func syncTime(r models.SaveResultRequest, now time.Time) time.Time {
+	if r.CompletedAt == nil {
+		return now
+	}
+	return *r.CompletedAt
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services/xp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResultSync_Integration(t *testing.T) {
+	env := SetupIntegrationTest(t)
+	defer env.Cleanup()
+
+	parent := env.CreateTestUser("", "parent")
+	familyID := env.CreateTestFamily(parent.ID)
+	child := env.CreateTestUser(familyID, "child")
+	wordSet := env.CreateTestWordSet(familyID, parent.ID)
+
+	env.ServiceManager.XP = xp.NewService(env.DB)
+	env.SetupAuthMiddleware(child)
+	env.Router.POST("/api/users/results", SaveResult)
+	env.Router.POST("/api/users/results/sync", SyncResults)
+
+	result := func(key string, completedAt time.Time) map[string]any {
+		return map[string]any{
+			"wordSetId":      wordSet.ID,
+			"mode":           string(models.TestModeKeyboard),
+			"score":          100,
+			"totalWords":     2,
+			"correctWords":   2,
+			"idempotencyKey": key,
+			"completedAt":    completedAt,
+		}
+	}
+
+	t.Run("Success_SaveResultReplay", func(t *testing.T) {
+		body := result("single-1", time.Now())
+		resp := makeRequest(env.Router, "POST", "/api/users/results", body, nil)
+		require.Equal(t, http.StatusCreated, resp.Code)
+
+		resp = makeRequest(env.Router, "POST", "/api/users/results", body, nil)
+		assert.Equal(t, http.StatusOK, resp.Code)
+		env.AssertRowCount("test_results", 1)
+	})
+
+	t.Run("Success_SaveResultHeaderKey", func(t *testing.T) {
+		body := result("", time.Now())
+		headers := map[string]string{"Idempotency-Key": "header-1"}
+		require.Equal(t, http.StatusCreated, makeRequest(env.Router, "POST", "/api/users/results", body, headers).Code)
+		assert.Equal(t, http.StatusOK, makeRequest(env.Router, "POST", "/api/users/results", body, headers).Code)
+		env.AssertRowCount("test_results", 2)
+	})
+
+	t.Run("Success_BatchSync", func(t *testing.T) {
+		xpBefore, _, err := env.DB.GetUserXP(t.Context(), child.ID)
+		require.NoError(t, err)
+
+		now := time.Now()
+		body := map[string]any{"results": []map[string]any{
+			result("offline-2", now),
+			result("offline-1", now.AddDate(0, 0, -9)),
+			result("single-1", now),                    // already saved online
+			result("offline-1", now.AddDate(0, 0, -9)), // queued twice
+			result("", now),
+			result("too-old", now.AddDate(0, 0, -60)),
+		}}
+		resp := makeRequest(env.Router, "POST", "/api/users/results/sync", body, nil)
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var apiResp struct {
+			Data models.SyncResultsResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &apiResp))
+		sync := apiResp.Data
+		assert.Equal(t, 2, sync.Created)
+		assert.Equal(t, 2, sync.Duplicate)
+		assert.Equal(t, 2, sync.Failed)
+		env.AssertRowCount("test_results", 4)
+
+		// Saved oldest first: the nine-day-old run is outside the decay window of today's runs
+		require.Len(t, sync.Results, 6)
+		assert.Equal(t, "too-old", sync.Results[0].IdempotencyKey)
+		assert.Equal(t, models.SyncStatusFailed, sync.Results[0].Status)
+		assert.Equal(t, "offline-1", sync.Results[1].IdempotencyKey)
+		assert.Equal(t, models.SyncStatusCreated, sync.Results[1].Status)
+		assert.Equal(t, models.SyncStatusDuplicate, sync.Results[2].Status)
+		require.NotNil(t, sync.Results[1].XP)
+		require.NotNil(t, sync.Results[3].XP)
+		assert.Greater(t, sync.Results[1].XP.Awarded, sync.Results[3].XP.Awarded)
+
+		saved, err := env.DB.GetTestResultByIdempotencyKey(t.Context(), child.ID, "offline-1")
+		require.NoError(t, err)
+		assert.WithinDuration(t, now.AddDate(0, 0, -9), saved.CompletedAt, time.Second)
+
+		xpAfter, _, err := env.DB.GetUserXP(t.Context(), child.ID)
+		require.NoError(t, err)
+		assert.Equal(t, xpBefore+sync.Results[1].XP.Awarded+sync.Results[3].XP.Awarded, xpAfter)
+	})
+}
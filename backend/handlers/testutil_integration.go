@@ -68,7 +68,7 @@ func SetupIntegrationTest(t *testing.T) *IntegrationTestEnv {
 	require.NoError(t, err, "Failed to connect to test database")
 
 	// Run migrations using the existing migrate package
-	err = migrate.Run(testDBURL)
+	err = migrate.Run(testDBURL, nil)
 	require.NoError(t, err, "Failed to run migrations")
 
 	// Create DB service with context and minimal config
@@ -130,7 +130,7 @@ func (env *IntegrationTestEnv) CreateTestUser(familyID, role string) *models.Use
 		IsActive:    true,
 	}
 
-	err := env.DB.CreateUser(user)
+	err := env.DB.CreateUser(env.T.Context(), user)
 	require.NoError(env.T, err, "Failed to create test user")
 
 	return user
@@ -158,7 +158,7 @@ func (env *IntegrationTestEnv) CreateTestWordSet(familyID, createdBy string) *mo
 		CreatedBy: createdBy,
 	}
 
-	err := env.DB.CreateWordSet(wordSet)
+	err := env.DB.CreateWordSet(env.T.Context(), wordSet)
 	require.NoError(env.T, err, "Failed to create test word set")
 
 	return wordSet
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services"
+	"github.com/starefossen/diktator/backend/internal/services/auth"
+	"github.com/starefossen/diktator/backend/internal/services/db"
+	"github.com/starefossen/diktator/backend/internal/services/xp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errInjected = errors.New("injected failure")
+
+// failingRepo fails one repository operation, also inside transactions
+type failingRepo struct {
+	db.Repository
+	failOn string
+}
+
+func (r *failingRepo) WithTx(ctx context.Context, fn func(tx db.Repository) error) error {
+	return r.Repository.WithTx(ctx, func(tx db.Repository) error {
+		return fn(&failingRepo{Repository: tx, failOn: r.failOn})
+	})
+}
+
+func (r *failingRepo) SaveTestResult(ctx context.Context, result *models.TestResult) error {
+	if r.failOn == "SaveTestResult" {
+		return errInjected
+	}
+	return r.Repository.SaveTestResult(ctx, result)
+}
+
+func (r *failingRepo) AcceptInvitation(ctx context.Context, invitationID, userID string) error {
+	if r.failOn == "AcceptInvitation" {
+		return errInjected
+	}
+	return r.Repository.AcceptInvitation(ctx, invitationID, userID)
+}
+
+func (r *failingRepo) UpdateUser(ctx context.Context, user *models.User) error {
+	if r.failOn == "UpdateUser" {
+		return errInjected
+	}
+	return r.Repository.UpdateUser(ctx, user)
+}
+
+// txFamily is a family with a parent, one child and a word set in an in-memory store
+type txFamily struct {
+	repo     *db.Memory
+	parentID string
+	childID  string
+	familyID string
+	wordSet  string
+}
+
+func newTxFamily(t *testing.T) *txFamily {
+	t.Helper()
+	ctx := t.Context()
+	repo := db.NewMemory()
+	now := time.Now()
+
+	parent := &models.User{AuthID: "auth-parent", Email: "parent@example.com", DisplayName: "Parent", Role: "parent", IsActive: true, CreatedAt: now, LastActiveAt: now}
+	require.NoError(t, repo.CreateUser(ctx, parent))
+	family := &models.Family{Name: "Test Family", CreatedBy: parent.ID, Members: []string{parent.ID}, CreatedAt: now, UpdatedAt: now}
+	require.NoError(t, repo.CreateFamily(ctx, family))
+	parent.FamilyID = family.ID
+	require.NoError(t, repo.UpdateUser(ctx, parent))
+
+	child := &models.ChildAccount{Email: "child@example.com", DisplayName: "Child", FamilyID: family.ID, ParentID: &parent.ID, IsActive: true, CreatedAt: now, LastActiveAt: now}
+	require.NoError(t, repo.CreateChild(ctx, child))
+	require.NoError(t, repo.AddFamilyMember(ctx, family.ID, child.ID, "child"))
+
+	ws := &models.WordSet{Name: "Uke 1", FamilyID: &family.ID, CreatedBy: parent.ID, Language: "no", CreatedAt: now, UpdatedAt: now}
+	ws.Words = append(ws.Words, struct {
+		Word         string               `json:"word"`
+		Audio        models.WordAudio     `json:"audio,omitempty"`
+		Definition   string               `json:"definition,omitempty"`
+		Translations []models.Translation `json:"translations,omitempty"`
+	}{Word: "hus"})
+	require.NoError(t, repo.CreateWordSet(ctx, ws))
+
+	return &txFamily{repo: repo, parentID: parent.ID, childID: child.ID, familyID: family.ID, wordSet: ws.ID}
+}
+
+func serveTx(repo db.Repository, values map[string]any, method, path string, handler gin.HandlerFunc, body any) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	sm := &services.Manager{DB: repo, XP: xp.NewService(repo)}
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("serviceManager", sm)
+		for k, v := range values {
+			c.Set(k, v)
+		}
+		c.Next()
+	})
+	router.Handle(method, path, handler)
+
+	data, _ := json.Marshal(body)
+	req := httptest.NewRequest(method, path, bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestSaveResult_FailedSaveGrantsNoXP(t *testing.T) {
+	f := newTxFamily(t)
+	values := map[string]any{"userID": f.childID, "validatedFamilyID": f.familyID}
+	body := models.SaveResultRequest{WordSetID: f.wordSet, Mode: "keyboard", Score: 100, TotalWords: 1, CorrectWords: 1}
+
+	w := serveTx(&failingRepo{Repository: f.repo, failOn: "SaveTestResult"}, values, http.MethodPost, "/api/users/results", SaveResult, body)
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+
+	total, _, err := f.repo.GetUserXP(t.Context(), f.childID)
+	require.NoError(t, err)
+	assert.Zero(t, total, "XP is rolled back with the failed save")
+	results, err := f.repo.GetTestResults(t.Context(), f.childID)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	w = serveTx(f.repo, values, http.MethodPost, "/api/users/results", SaveResult, body)
+	require.Equal(t, http.StatusCreated, w.Code)
+	total, _, err = f.repo.GetUserXP(t.Context(), f.childID)
+	require.NoError(t, err)
+	assert.Positive(t, total)
+}
+
+func TestAcceptInvitation_FailedAcceptCreatesNoUser(t *testing.T) {
+	f := newTxFamily(t)
+	invitation := &models.FamilyInvitation{ID: "invite-1", FamilyID: f.familyID, Email: "new@example.com", Role: "parent", InvitedBy: f.parentID, Status: "pending", CreatedAt: time.Now()}
+	require.NoError(t, f.repo.CreateFamilyInvitation(t.Context(), invitation))
+
+	values := map[string]any{
+		"authIdentityID": "auth-new",
+		"identity":       &auth.Identity{ID: "auth-new", Email: "new@example.com"},
+	}
+	path := "/api/invitations/" + invitation.ID + "/accept"
+	handle := func(c *gin.Context) {
+		c.Params = gin.Params{{Key: "invitationId", Value: invitation.ID}}
+		AcceptInvitation(c)
+	}
+
+	w := serveTx(&failingRepo{Repository: f.repo, failOn: "AcceptInvitation"}, values, http.MethodPost, path, handle, nil)
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"error": "Failed to accept invitation"}`, w.Body.String(), "internal errors are logged, not returned")
+	_, err := f.repo.GetUserByEmail(t.Context(), "new@example.com")
+	assert.ErrorIs(t, err, db.ErrUserNotFound, "the account is rolled back with the failed acceptance")
+
+	w = serveTx(f.repo, values, http.MethodPost, path, handle, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	user, err := f.repo.GetUserByEmail(t.Context(), "new@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, f.familyID, user.FamilyID)
+}
+
+func TestCreateUser_FailedFamilyLinkLeavesNoAccount(t *testing.T) {
+	repo := db.NewMemory()
+	values := map[string]any{"authIdentityID": "auth-signup"}
+	body := map[string]string{"displayName": "Signup", "email": "signup@example.com"}
+
+	w := serveTx(&failingRepo{Repository: repo, failOn: "UpdateUser"}, values, http.MethodPost, "/api/users", CreateUser, body)
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	_, err := repo.GetUserByAuthID(t.Context(), "auth-signup")
+	assert.ErrorIs(t, err, db.ErrUserNotFound)
+	_, err = repo.GetFamily(t.Context(), "family-auth-signup")
+	assert.ErrorIs(t, err, db.ErrFamilyNotFound)
+
+	w = serveTx(repo, values, http.MethodPost, "/api/users", CreateUser, body)
+	require.Equal(t, http.StatusCreated, w.Code)
+	user, err := repo.GetUserByAuthID(t.Context(), "auth-signup")
+	require.NoError(t, err)
+	assert.Equal(t, "family-auth-signup", user.FamilyID)
+}
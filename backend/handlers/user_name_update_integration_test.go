@@ -21,7 +21,7 @@ func TestUpdateUserDisplayName_Integration(t *testing.T) {
 	familyID := env.CreateTestFamily(parent.ID)
 	parent.FamilyID = familyID
 	// Update user in database with family ID
-	err := env.DB.UpdateUser(parent)
+	err := env.DB.UpdateUser(t.Context(), parent)
 	assert.NoError(t, err)
 
 	// Setup auth middleware and routes
@@ -102,7 +102,7 @@ func TestUpdateUserDisplayName_Integration(t *testing.T) {
 				assert.NotNil(t, resp.Data)
 
 				// Verify the name was actually updated in the database
-				updatedUser, err := env.DB.GetUser(parent.ID)
+				updatedUser, err := env.DB.GetUser(t.Context(), parent.ID)
 				assert.NoError(t, err)
 				assert.NotNil(t, updatedUser)
 				// Handle whitespace trimming
@@ -125,7 +125,7 @@ func TestUpdateChildDisplayName_Integration(t *testing.T) {
 	familyID := env.CreateTestFamily(parent.ID)
 	parent.FamilyID = familyID
 	// Update user in database with family ID
-	err := env.DB.UpdateUser(parent)
+	err := env.DB.UpdateUser(t.Context(), parent)
 	assert.NoError(t, err)
 
 	otherParent := env.CreateTestUser(familyID, "parent")
@@ -135,7 +135,7 @@ func TestUpdateChildDisplayName_Integration(t *testing.T) {
 		DisplayName: "Original Child Name",
 		ParentID:    &parent.ID,
 	}
-	err = env.DB.CreateChild(child)
+	err = env.DB.CreateChild(t.Context(), child)
 	assert.NoError(t, err)
 
 	// Setup auth middleware and routes
@@ -219,7 +219,7 @@ func TestUpdateChildDisplayName_Integration(t *testing.T) {
 				assert.Empty(t, resp.Error)
 
 				// Verify the name was actually updated in the database
-				updatedChild, err := env.DB.GetChild(tt.childID)
+				updatedChild, err := env.DB.GetChild(t.Context(), tt.childID)
 				assert.NoError(t, err)
 				assert.NotNil(t, updatedChild)
 				expectedName := strings.TrimSpace(tt.displayName)
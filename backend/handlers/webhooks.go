@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services/db"
+	"github.com/starefossen/diktator/backend/internal/services/events"
+	"github.com/starefossen/diktator/backend/internal/services/webhook"
+)
+
+// Delivery log page size bounds
+const (
+	defaultWebhookDeliveryLimit = 50
+	maxWebhookDeliveryLimit     = 200
+)
+
+// @Summary		Create Webhook
+// @Description	Register a URL that receives HMAC-signed JSON payloads for family events (parent only). The signing secret is only returned once.
+// @Tags			families
+// @Accept			json
+// @Produce		json
+// @Param			request	body		models.CreateWebhookRequest	true	"Webhook details"
+// @Success		201		{object}	models.APIResponse{data=models.CreateWebhookResponse}	"Webhook created"
+// @Failure		400		{object}	models.APIResponse	"Invalid request"
+// @Failure		500		{object}	models.APIResponse	"Failed to create webhook"
+// @Security		BearerAuth
+// @Router			/api/families/webhooks [post]
+func CreateWebhook(c *gin.Context) {
+	serviceManager := GetServiceManager(c)
+	if serviceManager == nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Service unavailable",
+		})
+		return
+	}
+
+	var req models.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Error: "Invalid request data",
+		})
+		return
+	}
+
+	if err := serviceManager.Webhooks.ValidateURL(req.URL); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	for _, t := range req.EventTypes {
+		if !events.IsValidType(t) {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Error: "Unknown event type: " + t,
+			})
+			return
+		}
+	}
+
+	familyID, err := getContextString(c, "validatedFamilyID")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid family ID"})
+		return
+	}
+	userID, err := getContextString(c, "userID")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	secret, err := webhook.GenerateSecret()
+	if err != nil {
+		logger().ErrorContext(c.Request.Context(), "Error generating webhook secret", "error", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Failed to create webhook",
+		})
+		return
+	}
+
+	hook := &models.Webhook{
+		ID:          uuid.New().String(),
+		FamilyID:    familyID,
+		URL:         req.URL,
+		Secret:      secret,
+		Description: req.Description,
+		EventTypes:  req.EventTypes,
+		IsActive:    true,
+		CreatedBy:   userID,
+		CreatedAt:   time.Now(),
+	}
+
+	if err := serviceManager.DB.CreateWebhook(c.Request.Context(), hook); err != nil {
+		logger().ErrorContext(c.Request.Context(), "Error creating webhook", "family_id", familyID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Failed to create webhook",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Data: models.CreateWebhookResponse{
+			Webhook: hook,
+			Secret:  secret,
+		},
+		Message: "Webhook created. Store the signing secret now - it will not be shown again.",
+	})
+}
+
+// @Summary		Get Webhooks
+// @Description	List the family's webhooks (parent only). Signing secrets are never returned.
+// @Tags			families
+// @Accept			json
+// @Produce		json
+// @Success		200	{object}	models.APIResponse{data=[]models.Webhook}	"Webhooks retrieved"
+// @Failure		500	{object}	models.APIResponse	"Failed to retrieve webhooks"
+// @Security		BearerAuth
+// @Router			/api/families/webhooks [get]
+func GetWebhooks(c *gin.Context) {
+	serviceManager := GetServiceManager(c)
+	if serviceManager == nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Service unavailable",
+		})
+		return
+	}
+
+	familyID, err := getContextString(c, "validatedFamilyID")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid family ID"})
+		return
+	}
+
+	hooks, err := serviceManager.DB.GetFamilyWebhooks(c.Request.Context(), familyID)
+	if err != nil {
+		logger().ErrorContext(c.Request.Context(), "Error getting webhooks", "family_id", familyID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Failed to retrieve webhooks",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Data: hooks,
+	})
+}
+
+// @Summary		Delete Webhook
+// @Description	Delete a webhook and its delivery log (parent only)
+// @Tags			families
+// @Accept			json
+// @Produce		json
+// @Param			webhookId	path		string				true	"Webhook ID"
+// @Success		200			{object}	models.APIResponse	"Webhook deleted"
+// @Failure		404			{object}	models.APIResponse	"Webhook not found"
+// @Failure		500			{object}	models.APIResponse	"Failed to delete webhook"
+// @Security		BearerAuth
+// @Router			/api/families/webhooks/{webhookId} [delete]
+func DeleteWebhook(c *gin.Context) {
+	serviceManager := GetServiceManager(c)
+	if serviceManager == nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Service unavailable",
+		})
+		return
+	}
+
+	familyID, err := getContextString(c, "validatedFamilyID")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid family ID"})
+		return
+	}
+
+	webhookID := c.Param("webhookId")
+	if err := serviceManager.DB.DeleteWebhook(c.Request.Context(), familyID, webhookID); err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			c.JSON(http.StatusNotFound, models.APIResponse{
+				Error: "Webhook not found",
+			})
+			return
+		}
+		logger().ErrorContext(c.Request.Context(), "Error deleting webhook", "id", webhookID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Failed to delete webhook",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Message: "Webhook deleted successfully",
+	})
+}
+
+// @Summary		Get Webhook Deliveries
+// @Description	Get the delivery log for a webhook, newest first (parent only)
+// @Tags			families
+// @Accept			json
+// @Produce		json
+// @Param			webhookId	path		string	true	"Webhook ID"
+// @Param			limit		query		int		false	"Maximum number of deliveries (default 50, max 200)"
+// @Success		200			{object}	models.APIResponse{data=[]models.WebhookDelivery}	"Delivery log"
+// @Failure		404			{object}	models.APIResponse	"Webhook not found"
+// @Failure		500			{object}	models.APIResponse	"Failed to retrieve deliveries"
+// @Security		BearerAuth
+// @Router			/api/families/webhooks/{webhookId}/deliveries [get]
+func GetWebhookDeliveries(c *gin.Context) {
+	serviceManager := GetServiceManager(c)
+	if serviceManager == nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Service unavailable",
+		})
+		return
+	}
+
+	familyID, err := getContextString(c, "validatedFamilyID")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid family ID"})
+		return
+	}
+
+	limit := defaultWebhookDeliveryLimit
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Error: "limit must be a positive integer",
+			})
+			return
+		}
+		limit = min(parsed, maxWebhookDeliveryLimit)
+	}
+
+	webhookID := c.Param("webhookId")
+	deliveries, err := serviceManager.DB.GetWebhookDeliveries(c.Request.Context(), familyID, webhookID, limit)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			c.JSON(http.StatusNotFound, models.APIResponse{
+				Error: "Webhook not found",
+			})
+			return
+		}
+		logger().ErrorContext(c.Request.Context(), "Error getting webhook deliveries", "id", webhookID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Error: "Failed to retrieve deliveries",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Data: deliveries,
+	})
+}
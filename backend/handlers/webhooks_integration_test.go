@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services/events"
+	"github.com/starefossen/diktator/backend/internal/services/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhooks_Integration(t *testing.T) {
+	env := SetupIntegrationTest(t)
+	defer env.Cleanup()
+
+	parent := env.CreateTestUser("", "parent")
+	familyID := env.CreateTestFamily(parent.ID)
+	parent.FamilyID = familyID
+	child := env.CreateTestUser(familyID, "child")
+	wordSet := env.CreateTestWordSet(familyID, parent.ID)
+
+	// Wire the event bus to the webhook outbox the same way the service manager does
+	webhookConfig := webhook.DefaultConfig()
+	webhookConfig.AllowPrivateNetworks = true // The receiver listens on loopback
+	dispatcher := webhook.NewDispatcher(env.DB, webhookConfig)
+	env.ServiceManager.Webhooks = dispatcher
+	env.ServiceManager.Events = events.NewBus(nil)
+	env.ServiceManager.Events.Subscribe("webhooks", dispatcher)
+
+	received := make(chan *http.Request, 10)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	env.SetupAuthMiddleware(parent)
+	env.Router.POST("/api/families/webhooks", CreateWebhook)
+	env.Router.GET("/api/families/webhooks", GetWebhooks)
+	env.Router.DELETE("/api/families/webhooks/:webhookId", DeleteWebhook)
+	env.Router.GET("/api/families/webhooks/:webhookId/deliveries", GetWebhookDeliveries)
+	env.Router.POST("/api/wordsets/:id/assignments/:userId", AssignWordSetToUser)
+
+	var created models.CreateWebhookResponse
+
+	t.Run("Create_RejectsUnknownEventType", func(t *testing.T) {
+		resp := makeRequest(env.Router, "POST", "/api/families/webhooks", map[string]any{
+			"url":        receiver.URL,
+			"eventTypes": []string{"nope"},
+		}, nil)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
+	t.Run("Create_ReturnsSecretOnce", func(t *testing.T) {
+		resp := makeRequest(env.Router, "POST", "/api/families/webhooks", map[string]any{
+			"url":        receiver.URL,
+			"eventTypes": []string{string(events.TypeWordSetAssigned)},
+		}, nil)
+		require.Equal(t, http.StatusCreated, resp.Code)
+
+		var body struct {
+			Data models.CreateWebhookResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		created = body.Data
+		assert.NotEmpty(t, created.Secret)
+
+		list := makeRequest(env.Router, "GET", "/api/families/webhooks", nil, nil)
+		require.Equal(t, http.StatusOK, list.Code)
+		assert.NotContains(t, list.Body.String(), created.Secret)
+	})
+
+	t.Run("Assignment_EnqueuesAndDeliversSignedEvent", func(t *testing.T) {
+		resp := makeRequest(env.Router, "POST", "/api/wordsets/"+wordSet.ID+"/assignments/"+child.ID, nil, nil)
+		require.Equal(t, http.StatusOK, resp.Code)
+		env.AssertRowCount("webhook_deliveries", 1)
+
+		n, err := dispatcher.ProcessDue(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+
+		select {
+		case r := <-received:
+			assert.Equal(t, string(events.TypeWordSetAssigned), r.Header.Get(webhook.HeaderEvent))
+			assert.NotEmpty(t, r.Header.Get(webhook.HeaderSignature))
+		case <-time.After(5 * time.Second):
+			t.Fatal("webhook was not delivered")
+		}
+
+		log := makeRequest(env.Router, "GET", "/api/families/webhooks/"+created.Webhook.ID+"/deliveries", nil, nil)
+		require.Equal(t, http.StatusOK, log.Code)
+
+		var body struct {
+			Data []models.WebhookDelivery `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(log.Body.Bytes(), &body))
+		require.Len(t, body.Data, 1)
+		assert.Equal(t, models.WebhookDeliveryDelivered, body.Data[0].Status)
+		assert.Equal(t, 1, body.Data[0].Attempts)
+	})
+
+	t.Run("Delete_RemovesWebhook", func(t *testing.T) {
+		resp := makeRequest(env.Router, "DELETE", "/api/families/webhooks/"+created.Webhook.ID, nil, nil)
+		require.Equal(t, http.StatusOK, resp.Code)
+		env.AssertNoRowsInTable("webhook_deliveries")
+
+		resp = makeRequest(env.Router, "GET", "/api/families/webhooks/"+created.Webhook.ID+"/deliveries", nil, nil)
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+}
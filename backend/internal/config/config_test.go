@@ -0,0 +1,202 @@
+package config
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clearEnv unsets every variable Load reads so the host environment cannot
+// leak into a test
+func clearEnv(t *testing.T) {
+	t.Helper()
+	t.Setenv(FileEnv, "")
+	for _, f := range fields(Default()) {
+		if f.env != "" {
+			t.Setenv(f.env, "")
+		}
+	}
+}
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	clearEnv(t)
+
+	cfg, err := Load(nil)
+	require.NoError(t, err)
+	assert.Equal(t, Default(), cfg)
+}
+
+func TestLoad_Env(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("PORT", "9090")
+	t.Setenv("DB_DRIVER", "memory")
+	t.Setenv("DISABLE_TTS", "true")
+	t.Setenv("TTS_CACHE_SIZE_MB", "32")
+	t.Setenv("LOG_LEVEL", "debug")
+	t.Setenv("LOG_LEVELS", "tts=debug, dictionary=WARN")
+	t.Setenv("OTEL_TRACES_EXPORTER", "console")
+	t.Setenv("TEST_MODE", "1")
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8, 192.0.2.1")
+	t.Setenv("RATE_LIMIT_AUDIO_PER_MINUTE", "120")
+
+	cfg, err := Load(nil)
+	require.NoError(t, err)
+	assert.Equal(t, 9090, cfg.Server.Port)
+	assert.True(t, cfg.Server.TestMode)
+	assert.Equal(t, []string{"10.0.0.0/8", "192.0.2.1"}, cfg.Server.TrustedProxies)
+	assert.Equal(t, 120, cfg.RateLimit.AudioPerMinute)
+	assert.Equal(t, "memory", cfg.Database.Driver)
+	assert.True(t, cfg.TTS.Disabled)
+	assert.Equal(t, 32, cfg.TTS.CacheSizeMB)
+	assert.Equal(t, slog.LevelDebug, cfg.Logging.Level)
+	assert.Equal(t, LevelMap{"tts": slog.LevelDebug, "dictionary": slog.LevelWarn}, cfg.Logging.Levels)
+	assert.Equal(t, "stdout", cfg.Tracing.Exporter, "console is an alias for stdout")
+}
+
+func TestLoad_File(t *testing.T) {
+	clearEnv(t)
+	path := writeFile(t, `
+server:
+  port: 7070
+auth:
+  mode: oidc
+  oidc_issuer_url: https://auth.example.com
+logging:
+  levels:
+    tts: debug
+`)
+
+	t.Run("from flag", func(t *testing.T) {
+		cfg, err := Load([]string{"-config", path})
+		require.NoError(t, err)
+		assert.Equal(t, 7070, cfg.Server.Port)
+		assert.Equal(t, "oidc", cfg.Auth.Mode)
+		assert.Equal(t, LevelMap{"tts": slog.LevelDebug}, cfg.Logging.Levels)
+		assert.Equal(t, 15, cfg.TTS.CacheSizeMB, "unset keys keep their defaults")
+	})
+
+	t.Run("from environment", func(t *testing.T) {
+		t.Setenv(FileEnv, path)
+		cfg, err := Load(nil)
+		require.NoError(t, err)
+		assert.Equal(t, 7070, cfg.Server.Port)
+	})
+
+	t.Run("environment overrides file", func(t *testing.T) {
+		t.Setenv("PORT", "6060")
+		cfg, err := Load([]string{"-config", path})
+		require.NoError(t, err)
+		assert.Equal(t, 6060, cfg.Server.Port)
+	})
+
+	t.Run("rejects unknown keys", func(t *testing.T) {
+		_, err := Load([]string{"-config", writeFile(t, "server:\n  prot: 7070\n")})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "prot")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := Load([]string{"-config", filepath.Join(t.TempDir(), "missing.yaml")})
+		assert.ErrorContains(t, err, "failed to read config file")
+	})
+}
+
+func TestLoad_FlagsOverrideEnv(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("PORT", "9090")
+	t.Setenv("LOG_FORMAT", "json")
+
+	cfg, err := Load([]string{"-port", "3000", "-log-format", "text"})
+	require.NoError(t, err)
+	assert.Equal(t, 3000, cfg.Server.Port)
+	assert.Equal(t, "text", cfg.Logging.Format)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	clearEnv(t)
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		want []string
+	}{
+		{
+			name: "unparsable values",
+			env:  map[string]string{"PORT": "http", "DISABLE_TTS": "maybe", "LOG_LEVELS": "tts"},
+			want: []string{`PORT: invalid integer "http"`, `DISABLE_TTS: invalid boolean "maybe"`, "LOG_LEVELS"},
+		},
+		{
+			name: "out of range values",
+			env:  map[string]string{"PORT": "70000", "TTS_CACHE_SIZE_MB": "0", "DB_DRIVER": "mysql"},
+			want: []string{"server.port (PORT): must be between 1 and 65535", "tts.cache_size_mb (TTS_CACHE_SIZE_MB)", "database.driver (DB_DRIVER): must be one of postgres, memory"},
+		},
+		{
+			name: "rate limits and proxies",
+			env:  map[string]string{"RATE_LIMIT_API_BURST": "0", "TRUSTED_PROXIES": "10.0.0.0/8,proxy.local"},
+			want: []string{"rate_limit.api_burst (RATE_LIMIT_API_BURST): must be at least 1", `server.trusted_proxies (TRUSTED_PROXIES): invalid IP or CIDR "proxy.local"`},
+		},
+		{
+			name: "oidc without issuer",
+			env:  map[string]string{"AUTH_MODE": "oidc"},
+			want: []string{"auth.oidc_issuer_url (OIDC_ISSUER_URL): required when auth.mode is oidc"},
+		},
+		{
+			name: "reminder emails without mail host",
+			env:  map[string]string{"REMINDER_EMAILS": "true"},
+			want: []string{"mail.reminder_emails (REMINDER_EMAILS): requires mail.host"},
+		},
+		{
+			name: "invalid database URL is masked",
+			env:  map[string]string{"DATABASE_URL": "mysql://app:hunter2@db/diktator"},
+			want: []string{"database.url (DATABASE_URL): scheme must be one of postgres, postgresql"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			_, err := Load(nil)
+			require.Error(t, err)
+			for _, want := range tt.want {
+				assert.Contains(t, err.Error(), want)
+			}
+			assert.NotContains(t, err.Error(), "hunter2")
+		})
+	}
+}
+
+func TestConfig_LogValue(t *testing.T) {
+	cfg := Default()
+	cfg.Database.URL = "postgresql://app:hunter2@db:5432/diktator"
+	cfg.Mail.Host = "smtp.example.com"
+	cfg.Mail.Password = "s3cret"
+	cfg.MCP.APIKey = "dkt_secretkey"
+	cfg.Logging.Levels = LevelMap{"tts": slog.LevelDebug}
+
+	var buf bytes.Buffer
+	slog.New(slog.NewTextHandler(&buf, nil)).Info("config", "config", cfg)
+	out := buf.String()
+
+	assert.Contains(t, out, "config.database.url=postgresql://app:xxxxx@db:5432/diktator")
+	assert.Contains(t, out, "config.mail.password=***")
+	assert.Contains(t, out, "config.mcp.api_key=***")
+	assert.Contains(t, out, "config.server.port=8080")
+	assert.Contains(t, out, `config.logging.levels="tts=debug"`)
+	assert.NotContains(t, out, "hunter2")
+	assert.NotContains(t, out, "s3cret")
+	assert.NotContains(t, out, "dkt_secretkey")
+}
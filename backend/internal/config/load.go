@@ -0,0 +1,214 @@
+package config
+
+import (
+	"bytes"
+	"encoding"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv names the environment variable holding the config file path,
+// used when the -config flag is not given
+const FileEnv = "CONFIG_FILE"
+
+// Load builds the configuration from defaults, the YAML file named by -config
+// or CONFIG_FILE, environment variables and the flags in args, then validates it
+func Load(args []string) (*Config, error) {
+	cfg := Default()
+	all := fields(cfg)
+
+	fs := flag.NewFlagSet("diktator-api", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	file := fs.String("config", os.Getenv(FileEnv), "path to a YAML config file")
+	flagValues := map[string]string{}
+	for _, f := range all {
+		if f.flag == "" {
+			continue
+		}
+		fs.Func(f.flag, "sets "+f.path, func(s string) error {
+			flagValues[f.flag] = s
+			return nil
+		})
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if *file != "" {
+		if err := loadFile(cfg, *file); err != nil {
+			return nil, err
+		}
+	}
+
+	var errs []error
+	for _, f := range all {
+		if f.env == "" {
+			continue
+		}
+		if s, ok := os.LookupEnv(f.env); ok && s != "" {
+			if err := set(f.value, s); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", f.env, err))
+			}
+		}
+	}
+	for _, f := range all {
+		if s, ok := flagValues[f.flag]; ok && f.flag != "" {
+			if err := set(f.value, s); err != nil {
+				errs = append(errs, fmt.Errorf("-%s: %w", f.flag, err))
+			}
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+
+	cfg.normalize()
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	return cfg, nil
+}
+
+func loadFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path) // #nosec G304 -- path is chosen by the operator
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true) // Catch misspelled keys instead of silently ignoring them
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// field is a settable leaf of Config
+type field struct {
+	value  reflect.Value
+	path   string // YAML path, e.g. "server.port"
+	env    string
+	flag   string
+	secret string // "true" to mask the value, "url" to mask a URL's password
+}
+
+// fields lists the leaves of cfg in declaration order
+func fields(cfg *Config) []field {
+	var out []field
+	sections := reflect.ValueOf(cfg).Elem()
+	for i := range sections.NumField() {
+		section := sections.Field(i)
+		sectionName := yamlName(sections.Type().Field(i))
+		for j := range section.NumField() {
+			sf := section.Type().Field(j)
+			out = append(out, field{
+				value:  section.Field(j),
+				path:   sectionName + "." + yamlName(sf),
+				env:    sf.Tag.Get("env"),
+				flag:   sf.Tag.Get("flag"),
+				secret: sf.Tag.Get("secret"),
+			})
+		}
+	}
+	return out
+}
+
+func yamlName(sf reflect.StructField) string {
+	name, _, _ := strings.Cut(sf.Tag.Get("yaml"), ",")
+	return name
+}
+
+var durationType = reflect.TypeFor[time.Duration]()
+
+// set parses s into v according to v's type
+func set(v reflect.Value, s string) error {
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(s))
+	}
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", s)
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported config type %s", v.Type())
+		}
+		var items []string
+		for item := range strings.SplitSeq(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", s)
+		}
+		v.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", s)
+		}
+		v.SetInt(int64(n))
+	default:
+		return fmt.Errorf("unsupported config type %s", v.Type())
+	}
+	return nil
+}
+
+// LevelMap holds per-subsystem log levels. In the environment it is written
+// as "tts=debug,dictionary=warn"; in YAML as a mapping.
+type LevelMap map[string]slog.Level
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (m *LevelMap) UnmarshalText(text []byte) error {
+	levels := LevelMap{}
+	for entry := range strings.SplitSeq(string(text), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, level, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return fmt.Errorf("invalid entry %q, expected subsystem=level", entry)
+		}
+		var l slog.Level
+		if err := l.UnmarshalText([]byte(strings.TrimSpace(level))); err != nil {
+			return fmt.Errorf("invalid entry %q: %w", entry, err)
+		}
+		levels[name] = l
+	}
+	*m = levels
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (m LevelMap) MarshalText() ([]byte, error) {
+	entries := make([]string, 0, len(m))
+	for name, level := range m {
+		entries = append(entries, name+"="+strings.ToLower(level.String()))
+	}
+	slices.Sort(entries)
+	return []byte(strings.Join(entries, ",")), nil
+}
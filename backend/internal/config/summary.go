@@ -0,0 +1,71 @@
+package config
+
+import (
+	"encoding"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+)
+
+// masked replaces secret values in the effective-config summary
+const masked = "***"
+
+// LogValue implements slog.LogValuer, logging the effective configuration
+// grouped by section with secrets masked
+func (c *Config) LogValue() slog.Value {
+	var sections []slog.Attr
+	var current string
+	var attrs []slog.Attr
+	flush := func() {
+		if current != "" {
+			sections = append(sections, slog.Attr{Key: current, Value: slog.GroupValue(attrs...)})
+		}
+	}
+	for _, f := range fields(c) {
+		section, key, _ := strings.Cut(f.path, ".")
+		if section != current {
+			flush()
+			current, attrs = section, nil
+		}
+		attrs = append(attrs, slog.Attr{Key: key, Value: displayValue(f)})
+	}
+	flush()
+	return slog.GroupValue(sections...)
+}
+
+func displayValue(f field) slog.Value {
+	v := f.value.Interface()
+	m, isText := v.(encoding.TextMarshaler)
+	if !isText && f.secret == "" {
+		return slog.AnyValue(v)
+	}
+
+	var s string
+	if isText {
+		text, err := m.MarshalText()
+		if err != nil {
+			return slog.StringValue(fmt.Sprintf("!%v", err))
+		}
+		s = string(text)
+	} else {
+		s = fmt.Sprint(v)
+	}
+
+	switch {
+	case f.secret == "url":
+		s = maskURL(s)
+	case f.secret != "" && s != "":
+		s = masked
+	}
+	return slog.StringValue(s)
+}
+
+// maskURL hides the password in a URL such as a database DSN
+func maskURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return masked
+	}
+	return u.Redacted()
+}
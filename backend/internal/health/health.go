@@ -0,0 +1,129 @@
+// Package health runs the dependency checks behind the readiness endpoint.
+// A failing critical check (such as the database) makes the server unready so
+// traffic is routed elsewhere; a failing non-critical check (such as TTS)
+// only marks it degraded, since the rest of the API still works.
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Status is the outcome of a check or of all checks together
+type Status string
+
+const (
+	StatusOK          Status = "ok"
+	StatusDegraded    Status = "degraded"    // A non-critical check failed
+	StatusUnavailable Status = "unavailable" // A critical check failed
+)
+
+// DefaultTimeout bounds each check so a hanging dependency cannot stall probes
+const DefaultTimeout = 2 * time.Second
+
+// Checker is implemented by dependencies that can report their own health
+type Checker interface {
+	CheckHealth(ctx context.Context) error
+}
+
+// Check is one named dependency check
+type Check struct {
+	Run      func(ctx context.Context) (detail string, err error)
+	Name     string
+	Critical bool // A failure makes the server unready instead of degraded
+}
+
+// FromChecker adapts a Checker to a Check
+func FromChecker(name string, critical bool, c Checker) Check {
+	return Check{
+		Name:     name,
+		Critical: critical,
+		Run: func(ctx context.Context) (string, error) {
+			return "", c.CheckHealth(ctx)
+		},
+	}
+}
+
+// Result is the outcome of one check
+type Result struct {
+	Status     Status  `json:"status"`
+	Critical   bool    `json:"critical"`
+	Detail     string  `json:"detail,omitempty"`
+	Error      string  `json:"error,omitempty"`
+	DurationMS float64 `json:"durationMs"`
+}
+
+// Report is the outcome of all checks
+type Report struct {
+	Status Status            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Ready reports whether every critical check passed
+func (r Report) Ready() bool {
+	return r.Status != StatusUnavailable
+}
+
+// PublicReport is the part of a report safe to show unauthenticated callers:
+// each check's status, without details or errors
+type PublicReport struct {
+	Status Status            `json:"status"`
+	Checks map[string]Status `json:"checks"`
+}
+
+// Public strips details and errors from the report
+func (r Report) Public() PublicReport {
+	checks := make(map[string]Status, len(r.Checks))
+	for name, result := range r.Checks {
+		checks[name] = result.Status
+	}
+	return PublicReport{Status: r.Status, Checks: checks}
+}
+
+// Run runs the checks concurrently, each bounded by timeout
+func Run(ctx context.Context, timeout time.Duration, checks ...Check) Report {
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Go(func() {
+			results[i] = run(ctx, timeout, check)
+		})
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]Result, len(checks))}
+	for i, check := range checks {
+		r := results[i]
+		report.Checks[check.Name] = r
+		switch {
+		case r.Status == StatusUnavailable:
+			report.Status = StatusUnavailable
+		case r.Status == StatusDegraded && report.Status == StatusOK:
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+func run(ctx context.Context, timeout time.Duration, check Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	detail, err := check.Run(ctx)
+	r := Result{
+		Status:     StatusOK,
+		Critical:   check.Critical,
+		Detail:     detail,
+		DurationMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		r.Error = err.Error()
+		r.Status = StatusDegraded
+		if check.Critical {
+			r.Status = StatusUnavailable
+		}
+	}
+	return r
+}
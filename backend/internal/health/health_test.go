@@ -0,0 +1,81 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReport_Public(t *testing.T) {
+	report := Run(t.Context(), time.Second, check("db", true, errors.New("dial 10.0.0.5:5432: refused")), check("tts", false, nil))
+	assert.Equal(t, PublicReport{
+		Status: StatusUnavailable,
+		Checks: map[string]Status{"db": StatusUnavailable, "tts": StatusOK},
+	}, report.Public())
+}
+
+func check(name string, critical bool, err error) Check {
+	return Check{Name: name, Critical: critical, Run: func(context.Context) (string, error) {
+		return "detail of " + name, err
+	}}
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name   string
+		checks []Check
+		want   Status
+	}{
+		{"all pass", []Check{check("db", true, nil), check("tts", false, nil)}, StatusOK},
+		{"non-critical failure", []Check{check("db", true, nil), check("tts", false, errors.New("disabled"))}, StatusDegraded},
+		{"critical failure", []Check{check("db", true, errors.New("down")), check("tts", false, errors.New("disabled"))}, StatusUnavailable},
+		{"no checks", nil, StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Run(t.Context(), time.Second, tt.checks...)
+			assert.Equal(t, tt.want, report.Status)
+			assert.Equal(t, tt.want != StatusUnavailable, report.Ready())
+			assert.Len(t, report.Checks, len(tt.checks))
+		})
+	}
+
+	t.Run("reports each check", func(t *testing.T) {
+		report := Run(t.Context(), time.Second, check("db", true, nil), check("tts", false, errors.New("disabled")))
+		assert.Equal(t, Result{Status: StatusOK, Critical: true, Detail: "detail of db"}, withoutDuration(report.Checks["db"]))
+		assert.Equal(t, Result{Status: StatusDegraded, Detail: "detail of tts", Error: "disabled"}, withoutDuration(report.Checks["tts"]))
+	})
+}
+
+func TestRun_Timeout(t *testing.T) {
+	hang := Check{Name: "jwks", Run: func(ctx context.Context) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}}
+
+	start := time.Now()
+	report := Run(t.Context(), 10*time.Millisecond, hang)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, StatusDegraded, report.Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["jwks"].Error)
+}
+
+type checkerFunc func(ctx context.Context) error
+
+func (f checkerFunc) CheckHealth(ctx context.Context) error { return f(ctx) }
+
+func TestFromChecker(t *testing.T) {
+	c := FromChecker("database", true, checkerFunc(func(context.Context) error { return errors.New("refused") }))
+	report := Run(t.Context(), time.Second, c)
+	assert.False(t, report.Ready())
+	assert.Equal(t, "refused", report.Checks["database"].Error)
+}
+
+func withoutDuration(r Result) Result {
+	r.DurationMS = 0
+	return r
+}
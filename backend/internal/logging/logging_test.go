@@ -0,0 +1,142 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestLogger returns a JSON logger writing to a buffer and a function
+// decoding the records written so far
+func newTestLogger(t *testing.T, cfg Config) (*slog.Logger, func() []map[string]any) {
+	t.Helper()
+	var buf bytes.Buffer
+	cfg.Writer = &buf
+	logger := New(cfg)
+
+	return logger, func() []map[string]any {
+		var records []map[string]any
+		for line := range strings.Lines(buf.String()) {
+			var record map[string]any
+			require.NoError(t, json.Unmarshal([]byte(line), &record))
+			records = append(records, record)
+		}
+		return records
+	}
+}
+
+func TestNew_SubsystemLevels(t *testing.T) {
+	logger, records := newTestLogger(t, Config{
+		Level:  slog.LevelInfo,
+		Levels: map[string]slog.Level{"tts": slog.LevelDebug, "dictionary": slog.LevelWarn},
+	})
+
+	logger.Debug("root debug")
+	Subsystem(logger, "tts").Debug("tts debug")
+	Subsystem(logger, "dictionary").Info("dictionary info")
+	Subsystem(logger, "dictionary").Warn("dictionary warn")
+	Subsystem(logger, "digest").Info("digest info")
+
+	var messages []string
+	for _, r := range records() {
+		messages = append(messages, r[slog.MessageKey].(string))
+	}
+	assert.Equal(t, []string{"tts debug", "dictionary warn", "digest info"}, messages)
+}
+
+func TestNew_AddsRequestIDFromContext(t *testing.T) {
+	logger, records := newTestLogger(t, Config{})
+
+	logger.InfoContext(WithRequestID(t.Context(), "req-1"), "with id")
+	logger.InfoContext(t.Context(), "without id")
+
+	got := records()
+	require.Len(t, got, 2)
+	assert.Equal(t, "req-1", got[0]["request_id"])
+	assert.NotContains(t, got[1], "request_id")
+}
+
+func TestRedact(t *testing.T) {
+	logger, records := newTestLogger(t, Config{})
+
+	logger.Info("Invitation sent to ola@example.com",
+		"email", "kari@example.com",
+		"display_name", "Emma",
+		"parent_email", "per@example.com",
+		"id_token", "abc",
+		"user_id", "user-1",
+		"error", errors.New("auth failed for Bearer abc.def.ghi"),
+		"detail", "key dkt_4f3a9b and jwt eyJhbGciOiJSUzI1NiJ9.eyJzdWIiOiIxIn0.c2ln",
+	)
+
+	got := records()
+	require.Len(t, got, 1)
+	r := got[0]
+	assert.Equal(t, "Invitation sent to [EMAIL]", r[slog.MessageKey])
+	assert.Equal(t, Redacted, r["email"])
+	assert.Equal(t, Redacted, r["display_name"])
+	assert.Equal(t, Redacted, r["parent_email"])
+	assert.Equal(t, Redacted, r["id_token"])
+	assert.Equal(t, "user-1", r["user_id"], "IDs are kept for correlation")
+	assert.Equal(t, "auth failed for Bearer [REDACTED]", r["error"])
+	assert.Equal(t, "key dkt_[REDACTED] and jwt [TOKEN]", r["detail"])
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger, records := newTestLogger(t, Config{})
+
+	r := gin.New()
+	r.Use(Middleware(logger, "/health"))
+	r.GET("/api/wordsets/:id", func(c *gin.Context) {
+		logger.InfoContext(c.Request.Context(), "in handler")
+		c.Status(http.StatusNoContent)
+	})
+	r.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	t.Run("generates an ID and logs the request", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/wordsets/abc", nil))
+
+		id := w.Header().Get(RequestIDHeader)
+		require.Len(t, id, 36)
+		got := records()
+		require.Len(t, got, 2)
+		assert.Equal(t, id, got[0]["request_id"], "handler logs carry the request ID")
+		assert.Equal(t, "Request completed", got[1][slog.MessageKey])
+		assert.Equal(t, id, got[1]["request_id"])
+		assert.Equal(t, "/api/wordsets/:id", got[1]["route"])
+		assert.EqualValues(t, http.StatusNoContent, got[1]["status"])
+	})
+
+	t.Run("reuses a valid caller ID", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/wordsets/abc", nil)
+		req.Header.Set(RequestIDHeader, "client-42")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, "client-42", w.Header().Get(RequestIDHeader))
+	})
+
+	t.Run("replaces an invalid caller ID", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/wordsets/abc", nil)
+		req.Header.Set(RequestIDHeader, "bad id\nINFO forged")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Len(t, w.Header().Get(RequestIDHeader), 36)
+	})
+
+	t.Run("skips access logs for skipped paths", func(t *testing.T) {
+		before := len(records())
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+		assert.Len(t, records(), before)
+	})
+}
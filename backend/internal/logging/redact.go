@@ -0,0 +1,93 @@
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces the value of a sensitive attribute
+const Redacted = "[REDACTED]"
+
+// sensitiveKeys are attribute keys whose values are always redacted, compared
+// lowercased with underscores and dashes removed. Names are included because
+// most names in this system belong to children.
+var sensitiveKeys = map[string]bool{
+	"email":         true,
+	"to":            true,
+	"name":          true,
+	"displayname":   true,
+	"childname":     true,
+	"firstname":     true,
+	"lastname":      true,
+	"fullname":      true,
+	"token":         true,
+	"password":      true,
+	"secret":        true,
+	"authorization": true,
+	"cookie":        true,
+	"apikey":        true,
+	"userinfo":      true,
+}
+
+// sensitiveSuffixes catch keys such as parent_email or id_token
+var sensitiveSuffixes = []string{"email", "token", "secret", "password"}
+
+var scrubbers = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`), "[EMAIL]"},
+	{regexp.MustCompile(`(?i)bearer\s+[A-Za-z0-9._~+/=\-]+`), "Bearer " + Redacted},
+	{regexp.MustCompile(`eyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]*`), "[TOKEN]"},
+	{regexp.MustCompile(`\b(dkt|whsec)_[A-Za-z0-9_\-]+`), "${1}_" + Redacted}, // API keys and webhook secrets
+}
+
+// Redact is a slog ReplaceAttr function. It redacts the values of sensitive
+// keys, and masks emails, bearer tokens, JWTs and API keys inside messages,
+// strings and errors.
+func Redact(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey || a.Key == slog.SourceKey) {
+		return a
+	}
+	if a.Key != slog.MessageKey && isSensitiveKey(a.Key) {
+		return slog.String(a.Key, Redacted)
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		if s := a.Value.String(); s != Scrub(s) {
+			return slog.String(a.Key, Scrub(s))
+		}
+	case slog.KindAny:
+		switch v := a.Value.Any().(type) {
+		case error:
+			return slog.String(a.Key, Scrub(v.Error()))
+		case fmt.Stringer:
+			return slog.String(a.Key, Scrub(v.String()))
+		}
+	}
+	return a
+}
+
+// Scrub masks emails, bearer tokens, JWTs and API keys in free text
+func Scrub(s string) string {
+	for _, sc := range scrubbers {
+		s = sc.pattern.ReplaceAllString(s, sc.replacement)
+	}
+	return s
+}
+
+func isSensitiveKey(key string) bool {
+	normalized := strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(key))
+	if sensitiveKeys[normalized] {
+		return true
+	}
+	for _, suffix := range sensitiveSuffixes {
+		if strings.HasSuffix(normalized, suffix) {
+			return true
+		}
+	}
+	return false
+}
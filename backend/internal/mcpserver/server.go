@@ -0,0 +1,122 @@
+// Package mcpserver exposes family-scoped Diktator operations as Model Context Protocol tools.
+//
+// A server instance is bound to a single family through a family API key, so every tool
+// call is implicitly scoped to that family's children and word sets.
+package mcpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services/auth"
+	"github.com/starefossen/diktator/backend/internal/services/db"
+)
+
+// ServerName is the implementation name advertised to MCP clients
+const ServerName = "diktator"
+
+// Common errors returned by tools
+var (
+	ErrUnauthorized   = errors.New("invalid or revoked API key")
+	ErrChildNotFound  = errors.New("child not found in this family")
+	ErrWordSetAccess  = errors.New("word set not found or not accessible to this family")
+	ErrInvalidRequest = errors.New("invalid request")
+)
+
+// Server holds the state shared by all tool handlers for one family
+type Server struct {
+	repo db.Repository
+	key  *models.FamilyAPIKey
+	now  func() time.Time
+}
+
+// Authenticate resolves a plaintext family API key to its stored record
+func Authenticate(ctx context.Context, repo db.Repository, apiKey string) (*models.FamilyAPIKey, error) {
+	if err := auth.ValidateAPIKeyFormat(apiKey); err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	key, err := repo.GetFamilyAPIKeyByHash(ctx, auth.HashAPIKey(apiKey))
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, ErrUnauthorized
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up API key: %w", err)
+	}
+
+	if err := repo.TouchFamilyAPIKey(ctx, key.ID); err != nil {
+		// Usage tracking is best effort and must not block access
+		slog.WarnContext(ctx, "Failed to record API key usage", "key_id", key.ID, "error", err)
+	}
+
+	return key, nil
+}
+
+// revalidate looks the server's key up again, so a key deleted by a parent
+// stops working on the next tool call instead of when the process restarts
+func (s *Server) revalidate(ctx context.Context) error {
+	key, err := s.repo.GetFamilyAPIKeyByHash(ctx, s.key.KeyHash)
+	if errors.Is(err, db.ErrNotFound) {
+		return ErrUnauthorized
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up API key: %w", err)
+	}
+	if key.ID != s.key.ID || key.FamilyID != s.key.FamilyID {
+		return ErrUnauthorized
+	}
+	return nil
+}
+
+// authorized wraps a tool handler so the API key is revalidated on every call
+func authorized[In, Out any](s *Server, handler mcp.ToolHandlerFor[In, Out]) mcp.ToolHandlerFor[In, Out] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, in In) (*mcp.CallToolResult, Out, error) {
+		if err := s.revalidate(ctx); err != nil {
+			var zero Out
+			return nil, zero, err
+		}
+		return handler(ctx, req, in)
+	}
+}
+
+// New creates an MCP server with all Diktator tools registered for the key's family
+func New(repo db.Repository, key *models.FamilyAPIKey, version string) *mcp.Server {
+	s := &Server{
+		repo: repo,
+		key:  key,
+		now:  time.Now,
+	}
+
+	server := mcp.NewServer(&mcp.Implementation{Name: ServerName, Version: version}, &mcp.ServerOptions{
+		Instructions: "Tools for managing a Diktator family: list children, create and assign " +
+			"spelling word sets, and review each child's progress and weak words.",
+	})
+	s.registerTools(server)
+
+	return server
+}
+
+// child loads a child and verifies it belongs to the server's family
+func (s *Server) child(ctx context.Context, childID string) (*models.ChildAccount, error) {
+	if childID == "" {
+		return nil, fmt.Errorf("%w: childId is required", ErrInvalidRequest)
+	}
+
+	child, err := s.repo.GetChild(ctx, childID)
+	if errors.Is(err, db.ErrChildNotFound) || errors.Is(err, db.ErrUserNotFound) {
+		return nil, ErrChildNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get child: %w", err)
+	}
+	if child.FamilyID != s.key.FamilyID {
+		return nil, ErrChildNotFound
+	}
+
+	return child, nil
+}
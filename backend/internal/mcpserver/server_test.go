@@ -0,0 +1,342 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services/auth"
+	"github.com/starefossen/diktator/backend/internal/services/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRepo implements the parts of db.Repository used by the MCP tools.
+// Calling any other method panics via the nil embedded interface.
+type fakeRepo struct {
+	db.Repository
+	keys        map[string]*models.FamilyAPIKey
+	children    []models.ChildAccount
+	results     map[string][]models.TestResult
+	globalSets  map[string]bool
+	wordSets    []*models.WordSet
+	assignments map[string][]string
+	touched     []string
+	failAssign  error // Returned by AssignWordSetToUser when set
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{
+		keys: map[string]*models.FamilyAPIKey{},
+		children: []models.ChildAccount{
+			{ID: "child-1", DisplayName: "Emma", FamilyID: "family-1", Role: "child", TotalXP: 120, Level: 2},
+			{ID: "child-2", DisplayName: "Other", FamilyID: "family-2", Role: "child"},
+		},
+		results:     map[string][]models.TestResult{},
+		globalSets:  map[string]bool{"global-1": true},
+		assignments: map[string][]string{},
+	}
+}
+
+func (f *fakeRepo) GetFamilyAPIKeyByHash(_ context.Context, keyHash string) (*models.FamilyAPIKey, error) {
+	if key, ok := f.keys[keyHash]; ok {
+		return key, nil
+	}
+	return nil, db.ErrNotFound
+}
+
+func (f *fakeRepo) TouchFamilyAPIKey(_ context.Context, keyID string) error {
+	f.touched = append(f.touched, keyID)
+	return nil
+}
+
+func (f *fakeRepo) GetChild(_ context.Context, childID string) (*models.ChildAccount, error) {
+	for i := range f.children {
+		if f.children[i].ID == childID {
+			return &f.children[i], nil
+		}
+	}
+	return nil, db.ErrChildNotFound
+}
+
+func (f *fakeRepo) GetFamilyChildren(_ context.Context, familyID string) ([]models.ChildAccount, error) {
+	var out []models.ChildAccount
+	for _, c := range f.children {
+		if c.FamilyID == familyID {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeRepo) CreateWordSet(_ context.Context, ws *models.WordSet) error {
+	f.wordSets = append(f.wordSets, ws)
+	return nil
+}
+
+func (f *fakeRepo) VerifyWordSetAccess(_ context.Context, familyID, wordSetID string) error {
+	if f.globalSets[wordSetID] {
+		return nil
+	}
+	for _, ws := range f.wordSets {
+		if ws.ID == wordSetID && ws.FamilyID != nil && *ws.FamilyID == familyID {
+			return nil
+		}
+	}
+	return db.ErrNoAccess
+}
+
+// WithTx runs fn against the fake and restores its word sets and assignments
+// when fn fails
+func (f *fakeRepo) WithTx(_ context.Context, fn func(tx db.Repository) error) error {
+	wordSets := slices.Clone(f.wordSets)
+	assignments := maps.Clone(f.assignments)
+	if err := fn(f); err != nil {
+		f.wordSets, f.assignments = wordSets, assignments
+		return err
+	}
+	return nil
+}
+
+func (f *fakeRepo) AssignWordSetToUser(_ context.Context, wordSetID, userID, assignedBy string) error {
+	if f.failAssign != nil {
+		return f.failAssign
+	}
+	f.assignments[wordSetID] = append(f.assignments[wordSetID], userID)
+	return nil
+}
+
+func (f *fakeRepo) GetUserProgress(_ context.Context, userID string) (*models.FamilyProgress, error) {
+	results := f.results[userID]
+	return &models.FamilyProgress{UserID: userID, TotalTests: len(results), TotalXP: 120, Level: 2}, nil
+}
+
+func (f *fakeRepo) GetTestResults(_ context.Context, userID string) ([]models.TestResult, error) {
+	return f.results[userID], nil
+}
+
+// connect starts the server and a client over an in-process stdio-style pipe pair
+func connect(t *testing.T, repo *fakeRepo, key *models.FamilyAPIKey) *mcp.ClientSession {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	serverReader, clientWriter := io.Pipe()
+	clientReader, serverWriter := io.Pipe()
+
+	repo.keys[key.KeyHash] = key
+	server := New(repo, key, "test")
+	go func() {
+		_ = server.Run(ctx, &mcp.IOTransport{Reader: serverReader, Writer: serverWriter})
+	}()
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "test"}, nil)
+	session, err := client.Connect(ctx, &mcp.IOTransport{Reader: clientReader, Writer: clientWriter}, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = session.Close() })
+
+	return session
+}
+
+func callTool[T any](t *testing.T, session *mcp.ClientSession, name string, args map[string]any) (T, *mcp.CallToolResult) {
+	t.Helper()
+	var out T
+
+	res, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: name, Arguments: args})
+	require.NoError(t, err)
+	if res.IsError {
+		return out, res
+	}
+
+	raw, err := json.Marshal(res.StructuredContent)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(raw, &out))
+	return out, res
+}
+
+func testKey() *models.FamilyAPIKey {
+	return &models.FamilyAPIKey{ID: "key-1", FamilyID: "family-1", CreatedBy: "parent-1", KeyPrefix: "dkt_test", KeyHash: "hash-1"}
+}
+
+func TestAuthenticate(t *testing.T) {
+	repo := newFakeRepo()
+	plaintext, _, hash, err := auth.GenerateAPIKey()
+	require.NoError(t, err)
+	repo.keys[hash] = testKey()
+
+	key, err := Authenticate(t.Context(), repo, plaintext)
+	require.NoError(t, err)
+	assert.Equal(t, "family-1", key.FamilyID)
+	assert.Equal(t, []string{"key-1"}, repo.touched)
+
+	_, err = Authenticate(t.Context(), repo, plaintext+"x")
+	assert.ErrorIs(t, err, ErrUnauthorized)
+
+	_, err = Authenticate(t.Context(), repo, "not-a-key")
+	assert.ErrorIs(t, err, ErrUnauthorized)
+}
+
+func TestToolCalls_RejectDeletedKey(t *testing.T) {
+	repo := newFakeRepo()
+	session := connect(t, repo, testKey())
+
+	_, res := callTool[ListChildrenOutput](t, session, "list_children", nil)
+	require.False(t, res.IsError)
+
+	delete(repo.keys, "hash-1")
+	_, res = callTool[ListChildrenOutput](t, session, "list_children", nil)
+	assert.True(t, res.IsError, "a deleted key stops working without a restart")
+}
+
+func TestListTools(t *testing.T) {
+	session := connect(t, newFakeRepo(), testKey())
+
+	res, err := session.ListTools(context.Background(), nil)
+	require.NoError(t, err)
+
+	var names []string
+	for _, tool := range res.Tools {
+		names = append(names, tool.Name)
+	}
+	assert.ElementsMatch(t, []string{
+		"list_children", "create_wordset", "assign_wordset", "get_child_progress", "get_weak_words",
+	}, names)
+}
+
+func TestListChildren_FamilyScoped(t *testing.T) {
+	session := connect(t, newFakeRepo(), testKey())
+
+	out, _ := callTool[ListChildrenOutput](t, session, "list_children", nil)
+	require.Len(t, out.Children, 1)
+	assert.Equal(t, "child-1", out.Children[0].ID)
+	assert.Equal(t, 2, out.Children[0].Level)
+}
+
+func TestCreateWordSet(t *testing.T) {
+	repo := newFakeRepo()
+	session := connect(t, repo, testKey())
+
+	out, res := callTool[CreateWordSetOutput](t, session, "create_wordset", map[string]any{
+		"name":     "Uke 12",
+		"words":    []string{" skje ", "kjole", ""},
+		"assignTo": []string{"child-1"},
+	})
+	require.False(t, res.IsError)
+	assert.Equal(t, "no", out.WordSet.Language)
+	assert.Equal(t, []string{"skje", "kjole"}, out.WordSet.Words)
+
+	require.Len(t, repo.wordSets, 1)
+	created := repo.wordSets[0]
+	assert.Equal(t, "family-1", *created.FamilyID)
+	assert.Equal(t, "parent-1", created.CreatedBy)
+	assert.Equal(t, []string{"child-1"}, repo.assignments[created.ID])
+}
+
+func TestCreateWordSet_RejectsForeignChild(t *testing.T) {
+	repo := newFakeRepo()
+	session := connect(t, repo, testKey())
+
+	_, res := callTool[CreateWordSetOutput](t, session, "create_wordset", map[string]any{
+		"name":     "Uke 12",
+		"words":    []string{"skje"},
+		"assignTo": []string{"child-2"},
+	})
+	assert.True(t, res.IsError)
+	assert.Empty(t, repo.wordSets, "no word set should be created when an assignee is invalid")
+}
+
+func TestCreateWordSet_FailedAssignmentCreatesNothing(t *testing.T) {
+	repo := newFakeRepo()
+	repo.failAssign = errors.New("injected failure")
+	session := connect(t, repo, testKey())
+
+	_, res := callTool[CreateWordSetOutput](t, session, "create_wordset", map[string]any{
+		"name":     "Uke 12",
+		"words":    []string{"skje"},
+		"assignTo": []string{"child-1"},
+	})
+	assert.True(t, res.IsError)
+	assert.Empty(t, repo.wordSets, "the word set is rolled back with the failed assignment")
+}
+
+func TestAssignWordSet(t *testing.T) {
+	repo := newFakeRepo()
+	session := connect(t, repo, testKey())
+
+	out, res := callTool[AssignWordSetOutput](t, session, "assign_wordset", map[string]any{
+		"wordSetId": "global-1",
+		"childId":   "child-1",
+	})
+	require.False(t, res.IsError)
+	assert.True(t, out.Assigned)
+	assert.Equal(t, []string{"child-1"}, repo.assignments["global-1"])
+
+	_, res = callTool[AssignWordSetOutput](t, session, "assign_wordset", map[string]any{
+		"wordSetId": "someone-elses-set",
+		"childId":   "child-1",
+	})
+	assert.True(t, res.IsError)
+
+	_, res = callTool[AssignWordSetOutput](t, session, "assign_wordset", map[string]any{
+		"wordSetId": "global-1",
+		"childId":   "child-2",
+	})
+	assert.True(t, res.IsError)
+}
+
+func TestGetChildProgress(t *testing.T) {
+	repo := newFakeRepo()
+	now := time.Now()
+	for i := 0; i < 8; i++ {
+		repo.results["child-1"] = append(repo.results["child-1"], models.TestResult{
+			WordSetID: "ws-1", Mode: "keyboard", Score: 90, CompletedAt: now.Add(-time.Duration(i) * time.Hour),
+		})
+	}
+	session := connect(t, repo, testKey())
+
+	out, res := callTool[GetChildProgressOutput](t, session, "get_child_progress", map[string]any{
+		"childId": "child-1",
+	})
+	require.False(t, res.IsError)
+	assert.Equal(t, "Emma", out.DisplayName)
+	assert.Equal(t, 8, out.TotalTests)
+	assert.Len(t, out.RecentResults, defaultRecentResults)
+}
+
+func TestGetWeakWords(t *testing.T) {
+	repo := newFakeRepo()
+	now := time.Now()
+	repo.results["child-1"] = []models.TestResult{
+		{CompletedAt: now.Add(-24 * time.Hour), Words: []models.WordTestResult{
+			{Word: "skje", Correct: false, ErrorTypes: []string{"skjSound"}},
+			{Word: "katt", Correct: false, ErrorTypes: []string{"doubleConsonant"}},
+			{Word: "hus", Correct: true},
+		}},
+		{CompletedAt: now.Add(-48 * time.Hour), Words: []models.WordTestResult{
+			{Word: "skje", Correct: false, ErrorTypes: []string{"skjSound", "silentLetter"}},
+			{Word: "katt", Correct: true},
+		}},
+		{CompletedAt: now.AddDate(0, 0, -60), Words: []models.WordTestResult{
+			{Word: "hus", Correct: false},
+		}},
+	}
+	session := connect(t, repo, testKey())
+
+	out, res := callTool[GetWeakWordsOutput](t, session, "get_weak_words", map[string]any{
+		"childId": "child-1",
+	})
+	require.False(t, res.IsError)
+	require.Len(t, out.Words, 2, "words outside the window and never-missed words are excluded")
+	assert.Equal(t, "skje", out.Words[0].Word)
+	assert.Equal(t, 2, out.Words[0].Misses)
+	assert.Equal(t, []string{"skjSound", "silentLetter"}, out.Words[0].ErrorTypes)
+	assert.Equal(t, "katt", out.Words[1].Word)
+	assert.InDelta(t, 50.0, out.Words[1].Accuracy, 0.01)
+}
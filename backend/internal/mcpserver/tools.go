@@ -0,0 +1,440 @@
+package mcpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services/db"
+)
+
+// Default limits for tools that accept optional sizing arguments
+const (
+	defaultRecentResults = 5
+	maxRecentResults     = 50
+	defaultWeakWordDays  = 30
+	defaultWeakWordLimit = 10
+	maxWeakWordLimit     = 100
+	defaultLanguage      = "no"
+)
+
+// ChildSummary is the MCP view of a child account
+type ChildSummary struct {
+	LastActiveAt time.Time `json:"lastActiveAt"`
+	BirthYear    *int      `json:"birthYear,omitempty"`
+	ID           string    `json:"id"`
+	DisplayName  string    `json:"displayName"`
+	TotalXP      int       `json:"totalXp"`
+	Level        int       `json:"level"`
+}
+
+// ListChildrenInput has no arguments
+type ListChildrenInput struct{}
+
+// ListChildrenOutput lists the children in the family
+type ListChildrenOutput struct {
+	Children []ChildSummary `json:"children"`
+}
+
+// CreateWordSetInput describes a new family word set
+type CreateWordSetInput struct {
+	Name     string   `json:"name" jsonschema:"display name of the word set"`
+	Language string   `json:"language,omitempty" jsonschema:"language code of the words, e.g. no or en (default no)"`
+	Words    []string `json:"words" jsonschema:"the words to practise, in order"`
+	AssignTo []string `json:"assignTo,omitempty" jsonschema:"optional child IDs to assign the new word set to"`
+}
+
+// WordSetSummary is the MCP view of a word set
+type WordSetSummary struct {
+	ID              string   `json:"id"`
+	Name            string   `json:"name"`
+	Language        string   `json:"language"`
+	Words           []string `json:"words"`
+	AssignedUserIDs []string `json:"assignedUserIds,omitempty"`
+}
+
+// CreateWordSetOutput returns the created word set
+type CreateWordSetOutput struct {
+	WordSet WordSetSummary `json:"wordSet"`
+}
+
+// AssignWordSetInput identifies a word set and the child to assign it to
+type AssignWordSetInput struct {
+	WordSetID string `json:"wordSetId" jsonschema:"ID of a family or curated word set"`
+	ChildID   string `json:"childId" jsonschema:"ID of a child in the family"`
+}
+
+// AssignWordSetOutput confirms an assignment
+type AssignWordSetOutput struct {
+	WordSetID string `json:"wordSetId"`
+	ChildID   string `json:"childId"`
+	Assigned  bool   `json:"assigned"`
+}
+
+// GetChildProgressInput selects a child and how many recent results to include
+type GetChildProgressInput struct {
+	ChildID     string `json:"childId" jsonschema:"ID of a child in the family"`
+	RecentLimit int    `json:"recentLimit,omitempty" jsonschema:"number of recent test results to include (default 5, max 50)"`
+}
+
+// RecentResult summarizes one completed test
+type RecentResult struct {
+	CompletedAt  time.Time `json:"completedAt"`
+	WordSetID    string    `json:"wordSetId"`
+	Mode         string    `json:"mode"`
+	Score        float64   `json:"score"`
+	TotalWords   int       `json:"totalWords"`
+	CorrectWords int       `json:"correctWords"`
+	TimeSpent    int       `json:"timeSpent"`
+}
+
+// GetChildProgressOutput contains aggregate progress for a child
+type GetChildProgressOutput struct {
+	LastActivity  time.Time      `json:"lastActivity"`
+	ChildID       string         `json:"childId"`
+	DisplayName   string         `json:"displayName"`
+	RecentResults []RecentResult `json:"recentResults"`
+	TotalTests    int            `json:"totalTests"`
+	TotalWords    int            `json:"totalWords"`
+	CorrectWords  int            `json:"correctWords"`
+	AverageScore  float64        `json:"averageScore"`
+	TotalXP       int            `json:"totalXp"`
+	Level         int            `json:"level"`
+}
+
+// GetWeakWordsInput selects a child and the time window to analyse
+type GetWeakWordsInput struct {
+	ChildID string `json:"childId" jsonschema:"ID of a child in the family"`
+	Days    int    `json:"days,omitempty" jsonschema:"how many days back to look (default 30)"`
+	Limit   int    `json:"limit,omitempty" jsonschema:"maximum number of words to return (default 10, max 100)"`
+}
+
+// WeakWord is a word the child has misspelled within the window
+type WeakWord struct {
+	LastMissedAt time.Time `json:"lastMissedAt"`
+	Word         string    `json:"word"`
+	ErrorTypes   []string  `json:"errorTypes,omitempty"`
+	Attempts     int       `json:"attempts"`
+	Misses       int       `json:"misses"`
+	Accuracy     float64   `json:"accuracy"`
+}
+
+// GetWeakWordsOutput lists weak words, most-missed first
+type GetWeakWordsOutput struct {
+	Since time.Time  `json:"since"`
+	Words []WeakWord `json:"words"`
+}
+
+func (s *Server) registerTools(server *mcp.Server) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_children",
+		Description: "List the children in the family with their level and XP.",
+	}, authorized(s, s.listChildren))
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "create_wordset",
+		Description: "Create a new word set for the family and optionally assign it to children.",
+	}, authorized(s, s.createWordSet))
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "assign_wordset",
+		Description: "Assign an existing family or curated word set to a child.",
+	}, authorized(s, s.assignWordSet))
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_child_progress",
+		Description: "Get test statistics, XP and recent results for a child.",
+	}, authorized(s, s.getChildProgress))
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_weak_words",
+		Description: "Get the words a child has misspelled most often recently, with error categories.",
+	}, authorized(s, s.getWeakWords))
+}
+
+func (s *Server) listChildren(ctx context.Context, _ *mcp.CallToolRequest, _ ListChildrenInput) (*mcp.CallToolResult, ListChildrenOutput, error) {
+	children, err := s.repo.GetFamilyChildren(ctx, s.key.FamilyID)
+	if err != nil {
+		return nil, ListChildrenOutput{}, fmt.Errorf("failed to list children: %w", err)
+	}
+
+	out := ListChildrenOutput{Children: make([]ChildSummary, 0, len(children))}
+	for _, child := range children {
+		out.Children = append(out.Children, ChildSummary{
+			ID:           child.ID,
+			DisplayName:  child.DisplayName,
+			BirthYear:    child.BirthYear,
+			TotalXP:      child.TotalXP,
+			Level:        child.Level,
+			LastActiveAt: child.LastActiveAt,
+		})
+	}
+
+	return nil, out, nil
+}
+
+func (s *Server) createWordSet(ctx context.Context, _ *mcp.CallToolRequest, in CreateWordSetInput) (*mcp.CallToolResult, CreateWordSetOutput, error) {
+	name := strings.TrimSpace(in.Name)
+	if name == "" {
+		return nil, CreateWordSetOutput{}, fmt.Errorf("%w: name is required", ErrInvalidRequest)
+	}
+
+	language := strings.TrimSpace(in.Language)
+	if language == "" {
+		language = defaultLanguage
+	}
+
+	words := make([]string, 0, len(in.Words))
+	for _, w := range in.Words {
+		if w = strings.TrimSpace(w); w != "" {
+			words = append(words, w)
+		}
+	}
+	if len(words) == 0 {
+		return nil, CreateWordSetOutput{}, fmt.Errorf("%w: at least one word is required", ErrInvalidRequest)
+	}
+
+	// Validate assignees before creating anything so a bad child ID leaves no partial state
+	for _, childID := range in.AssignTo {
+		if _, err := s.child(ctx, childID); err != nil {
+			return nil, CreateWordSetOutput{}, err
+		}
+	}
+
+	familyID := s.key.FamilyID
+	now := s.now()
+	wordSet := &models.WordSet{
+		ID:        uuid.New().String(),
+		Name:      name,
+		FamilyID:  &familyID,
+		CreatedBy: s.key.CreatedBy,
+		Language:  language,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	for _, w := range words {
+		wordSet.Words = append(wordSet.Words, struct {
+			Word         string               `json:"word"`
+			Audio        models.WordAudio     `json:"audio,omitempty"`
+			Definition   string               `json:"definition,omitempty"`
+			Translations []models.Translation `json:"translations,omitempty"`
+		}{Word: w})
+	}
+
+	// Create and assign together, so a failed assignment leaves no word set behind
+	err := s.repo.WithTx(ctx, func(tx db.Repository) error {
+		if err := tx.CreateWordSet(ctx, wordSet); err != nil {
+			return fmt.Errorf("failed to create word set: %w", err)
+		}
+		for _, childID := range in.AssignTo {
+			if err := tx.AssignWordSetToUser(ctx, wordSet.ID, childID, s.key.CreatedBy); err != nil {
+				return fmt.Errorf("failed to assign word set to %s: %w", childID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, CreateWordSetOutput{}, err
+	}
+
+	return nil, CreateWordSetOutput{
+		WordSet: WordSetSummary{
+			ID:              wordSet.ID,
+			Name:            wordSet.Name,
+			Language:        wordSet.Language,
+			Words:           words,
+			AssignedUserIDs: in.AssignTo,
+		},
+	}, nil
+}
+
+func (s *Server) assignWordSet(ctx context.Context, _ *mcp.CallToolRequest, in AssignWordSetInput) (*mcp.CallToolResult, AssignWordSetOutput, error) {
+	if in.WordSetID == "" {
+		return nil, AssignWordSetOutput{}, fmt.Errorf("%w: wordSetId is required", ErrInvalidRequest)
+	}
+	if _, err := s.child(ctx, in.ChildID); err != nil {
+		return nil, AssignWordSetOutput{}, err
+	}
+
+	if err := s.repo.VerifyWordSetAccess(ctx, s.key.FamilyID, in.WordSetID); err != nil {
+		if errors.Is(err, db.ErrNoAccess) {
+			return nil, AssignWordSetOutput{}, ErrWordSetAccess
+		}
+		return nil, AssignWordSetOutput{}, fmt.Errorf("failed to verify word set access: %w", err)
+	}
+
+	if err := s.repo.AssignWordSetToUser(ctx, in.WordSetID, in.ChildID, s.key.CreatedBy); err != nil {
+		return nil, AssignWordSetOutput{}, fmt.Errorf("failed to assign word set: %w", err)
+	}
+
+	return nil, AssignWordSetOutput{
+		WordSetID: in.WordSetID,
+		ChildID:   in.ChildID,
+		Assigned:  true,
+	}, nil
+}
+
+func (s *Server) getChildProgress(ctx context.Context, _ *mcp.CallToolRequest, in GetChildProgressInput) (*mcp.CallToolResult, GetChildProgressOutput, error) {
+	child, err := s.child(ctx, in.ChildID)
+	if err != nil {
+		return nil, GetChildProgressOutput{}, err
+	}
+
+	progress, err := s.repo.GetUserProgress(ctx, child.ID)
+	if err != nil {
+		return nil, GetChildProgressOutput{}, fmt.Errorf("failed to get progress: %w", err)
+	}
+
+	results, err := s.repo.GetTestResults(ctx, child.ID)
+	if err != nil {
+		return nil, GetChildProgressOutput{}, fmt.Errorf("failed to get test results: %w", err)
+	}
+
+	limit := clamp(in.RecentLimit, defaultRecentResults, maxRecentResults)
+	recent := make([]RecentResult, 0, min(limit, len(results)))
+	for _, r := range results {
+		if len(recent) == limit {
+			break
+		}
+		recent = append(recent, RecentResult{
+			CompletedAt:  r.CompletedAt,
+			WordSetID:    r.WordSetID,
+			Mode:         r.Mode,
+			Score:        r.Score,
+			TotalWords:   r.TotalWords,
+			CorrectWords: r.CorrectWords,
+			TimeSpent:    r.TimeSpent,
+		})
+	}
+
+	return nil, GetChildProgressOutput{
+		LastActivity:  progress.LastActivity,
+		ChildID:       child.ID,
+		DisplayName:   child.DisplayName,
+		RecentResults: recent,
+		TotalTests:    progress.TotalTests,
+		TotalWords:    progress.TotalWords,
+		CorrectWords:  progress.CorrectWords,
+		AverageScore:  progress.AverageScore,
+		TotalXP:       progress.TotalXP,
+		Level:         progress.Level,
+	}, nil
+}
+
+func (s *Server) getWeakWords(ctx context.Context, _ *mcp.CallToolRequest, in GetWeakWordsInput) (*mcp.CallToolResult, GetWeakWordsOutput, error) {
+	child, err := s.child(ctx, in.ChildID)
+	if err != nil {
+		return nil, GetWeakWordsOutput{}, err
+	}
+
+	results, err := s.repo.GetTestResults(ctx, child.ID)
+	if err != nil {
+		return nil, GetWeakWordsOutput{}, fmt.Errorf("failed to get test results: %w", err)
+	}
+
+	days := in.Days
+	if days <= 0 {
+		days = defaultWeakWordDays
+	}
+	since := s.now().AddDate(0, 0, -days)
+
+	return nil, GetWeakWordsOutput{
+		Since: since,
+		Words: weakWords(results, since, clamp(in.Limit, defaultWeakWordLimit, maxWeakWordLimit)),
+	}, nil
+}
+
+// weakWords aggregates per-word outcomes since the given time and returns
+// missed words ordered by miss count, then lowest accuracy, then alphabetically
+func weakWords(results []models.TestResult, since time.Time, limit int) []WeakWord {
+	type tally struct {
+		errorTypes map[string]int
+		word       WeakWord
+	}
+	byWord := make(map[string]*tally)
+
+	for _, r := range results {
+		if r.CompletedAt.Before(since) {
+			continue
+		}
+		for _, w := range r.Words {
+			key := strings.ToLower(w.Word)
+			t, ok := byWord[key]
+			if !ok {
+				t = &tally{word: WeakWord{Word: w.Word}, errorTypes: make(map[string]int)}
+				byWord[key] = t
+			}
+			t.word.Attempts++
+			if w.Correct {
+				continue
+			}
+			t.word.Misses++
+			if r.CompletedAt.After(t.word.LastMissedAt) {
+				t.word.LastMissedAt = r.CompletedAt
+			}
+			for _, et := range w.ErrorTypes {
+				t.errorTypes[et]++
+			}
+		}
+	}
+
+	words := make([]WeakWord, 0, len(byWord))
+	for _, t := range byWord {
+		if t.word.Misses == 0 {
+			continue
+		}
+		t.word.Accuracy = float64(t.word.Attempts-t.word.Misses) / float64(t.word.Attempts) * 100
+		t.word.ErrorTypes = rankErrorTypes(t.errorTypes)
+		words = append(words, t.word)
+	}
+
+	sort.Slice(words, func(i, j int) bool {
+		if words[i].Misses != words[j].Misses {
+			return words[i].Misses > words[j].Misses
+		}
+		if words[i].Accuracy != words[j].Accuracy {
+			return words[i].Accuracy < words[j].Accuracy
+		}
+		return words[i].Word < words[j].Word
+	})
+
+	if len(words) > limit {
+		words = words[:limit]
+	}
+	return words
+}
+
+// rankErrorTypes returns error categories ordered by frequency
+func rankErrorTypes(counts map[string]int) []string {
+	if len(counts) == 0 {
+		return nil
+	}
+	types := make([]string, 0, len(counts))
+	for et := range counts {
+		types = append(types, et)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		if counts[types[i]] != counts[types[j]] {
+			return counts[types[i]] > counts[types[j]]
+		}
+		return types[i] < types[j]
+	})
+	return types
+}
+
+// clamp returns def when v is not positive and caps v at maxValue
+func clamp(v, def, maxValue int) int {
+	if v <= 0 {
+		return def
+	}
+	if v > maxValue {
+		return maxValue
+	}
+	return v
+}
@@ -0,0 +1,132 @@
+package metrics
+
+import (
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// CacheSource is a cache whose size and hit counters are exported
+type CacheSource interface {
+	Stats() (items int, bytes int64, maxBytes int64)
+	Counters() (hits, misses uint64)
+}
+
+// TrackCache exports a cache's hits, misses and size under the given name. A
+// later call with the same name replaces the earlier cache.
+func TrackCache(name string, cache CacheSource) {
+	caches.mu.Lock()
+	defer caches.mu.Unlock()
+	caches.sources[name] = cache
+}
+
+// TrackPool exports the statistics of a database connection pool. stat is read
+// on every scrape; a later call replaces the earlier pool.
+func TrackPool(stat func() *pgxpool.Stat) {
+	pools.mu.Lock()
+	defer pools.mu.Unlock()
+	pools.stat = stat
+}
+
+func desc(subsystem, name, help string, labels ...string) *prometheus.Desc {
+	return prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, name), help, labels, nil)
+}
+
+// cacheCollector reads the tracked caches at scrape time
+type cacheCollector struct {
+	mu       sync.Mutex
+	sources  map[string]CacheSource
+	hits     *prometheus.Desc
+	misses   *prometheus.Desc
+	items    *prometheus.Desc
+	bytes    *prometheus.Desc
+	maxBytes *prometheus.Desc
+}
+
+var caches = &cacheCollector{
+	sources:  map[string]CacheSource{},
+	hits:     desc("cache", "hits_total", "Cache lookups that found an entry.", "cache"),
+	misses:   desc("cache", "misses_total", "Cache lookups that found no entry.", "cache"),
+	items:    desc("cache", "items", "Entries in the cache.", "cache"),
+	bytes:    desc("cache", "bytes", "Bytes held by the cache.", "cache"),
+	maxBytes: desc("cache", "max_bytes", "Byte capacity of the cache.", "cache"),
+}
+
+func (c *cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{c.hits, c.misses, c.items, c.bytes, c.maxBytes} {
+		ch <- d
+	}
+}
+
+func (c *cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, name := range slices.Sorted(maps.Keys(c.sources)) {
+		source := c.sources[name]
+		hits, misses := source.Counters()
+		items, bytes, maxBytes := source.Stats()
+		ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(hits), name)
+		ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(misses), name)
+		ch <- prometheus.MustNewConstMetric(c.items, prometheus.GaugeValue, float64(items), name)
+		ch <- prometheus.MustNewConstMetric(c.bytes, prometheus.GaugeValue, float64(bytes), name)
+		ch <- prometheus.MustNewConstMetric(c.maxBytes, prometheus.GaugeValue, float64(maxBytes), name)
+	}
+}
+
+// poolCollector reads the tracked pgx pool at scrape time
+type poolCollector struct {
+	mu   sync.Mutex
+	stat func() *pgxpool.Stat
+
+	acquired      *prometheus.Desc
+	idle          *prometheus.Desc
+	total         *prometheus.Desc
+	maxConns      *prometheus.Desc
+	acquires      *prometheus.Desc
+	emptyAcquires *prometheus.Desc
+	canceled      *prometheus.Desc
+	acquireWait   *prometheus.Desc
+}
+
+var pools = &poolCollector{
+	acquired:      desc("db_pool", "acquired_connections", "Connections currently in use."),
+	idle:          desc("db_pool", "idle_connections", "Idle connections in the pool."),
+	total:         desc("db_pool", "total_connections", "Open connections, including ones being established."),
+	maxConns:      desc("db_pool", "max_connections", "Maximum size of the pool."),
+	acquires:      desc("db_pool", "acquires_total", "Successful connection acquires."),
+	emptyAcquires: desc("db_pool", "empty_acquires_total", "Acquires that had to wait because no connection was idle."),
+	canceled:      desc("db_pool", "canceled_acquires_total", "Acquires cancelled by their context."),
+	acquireWait:   desc("db_pool", "acquire_wait_seconds_total", "Total time spent waiting to acquire a connection."),
+}
+
+func (p *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{p.acquired, p.idle, p.total, p.maxConns, p.acquires, p.emptyAcquires, p.canceled, p.acquireWait} {
+		ch <- d
+	}
+}
+
+func (p *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	p.mu.Lock()
+	stat := p.stat
+	p.mu.Unlock()
+	if stat == nil {
+		return
+	}
+
+	s := stat()
+	if s == nil {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(p.acquired, prometheus.GaugeValue, float64(s.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(p.idle, prometheus.GaugeValue, float64(s.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(p.total, prometheus.GaugeValue, float64(s.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(p.maxConns, prometheus.GaugeValue, float64(s.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(p.acquires, prometheus.CounterValue, float64(s.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(p.emptyAcquires, prometheus.CounterValue, float64(s.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(p.canceled, prometheus.CounterValue, float64(s.CanceledAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(p.acquireWait, prometheus.CounterValue, s.AcquireDuration().Seconds())
+}
@@ -0,0 +1,159 @@
+// Package metrics defines the Prometheus metrics served on /metrics: HTTP traffic,
+// the database pool, text-to-speech, the dictionary proxy, caches and domain events.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "diktator"
+
+// Registry holds every metric of the API. It is separate from the default
+// registry so tests and tools that import packages twice never collide.
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		pools,
+		caches,
+	)
+}
+
+var (
+	httpDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by route, method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	httpRateLimited = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "rate_limited_total",
+		Help:      "Requests rejected by the rate limiter, by route group.",
+	}, []string{"group"})
+
+	ttsRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "tts",
+		Name:      "synthesis_requests_total",
+		Help:      "Speech synthesis requests sent to the TTS API, by voice and outcome.",
+	}, []string{"voice", "outcome"})
+
+	ttsDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "tts",
+		Name:      "synthesis_duration_seconds",
+		Help:      "Speech synthesis latency by voice.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2, 5, 10},
+	}, []string{"voice"})
+
+	ttsCharacters = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "tts",
+		Name:      "characters_billed_total",
+		Help:      "Characters sent in successful synthesis requests, including SSML markup, by voice.",
+	}, []string{"voice"})
+
+	dictionaryDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "dictionary",
+		Name:      "upstream_duration_seconds",
+		Help:      "Latency of requests to the upstream dictionary API, by endpoint and outcome.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2, 5, 10},
+	}, []string{"endpoint", "outcome"})
+
+	dictionaryWait = factory.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "dictionary",
+		Name:      "rate_limit_wait_seconds",
+		Help:      "Time upstream dictionary requests waited for the rate limiter.",
+		Buckets:   []float64{0, 0.05, 0.1, 0.25, 0.5, 1, 2, 5},
+	})
+
+	testsSaved = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tests_saved_total",
+		Help:      "Test results saved, by test mode.",
+	}, []string{"mode"})
+
+	xpAwarded = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "xp_awarded_total",
+		Help:      "XP awarded for saved test results.",
+	})
+)
+
+// Handler serves the metrics in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// Middleware records the latency and status of each request. Requests are
+// labelled by their route pattern, not their path, so IDs do not create series.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		httpDuration.WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).
+			Observe(time.Since(start).Seconds())
+	}
+}
+
+// RecordRateLimited counts a request rejected by the rate limiter
+func RecordRateLimited(group string) {
+	httpRateLimited.WithLabelValues(group).Inc()
+}
+
+// ObserveSynthesis records one speech synthesis request
+func ObserveSynthesis(voice string, characters int, elapsed time.Duration, err error) {
+	ttsDuration.WithLabelValues(voice).Observe(elapsed.Seconds())
+	if err != nil {
+		ttsRequests.WithLabelValues(voice, "error").Inc()
+		return
+	}
+	ttsRequests.WithLabelValues(voice, "success").Inc()
+	ttsCharacters.WithLabelValues(voice).Add(float64(characters))
+}
+
+// ObserveDictionaryRequest records one request to the upstream dictionary API.
+// The outcome is the HTTP status code, or "error" when no response arrived.
+func ObserveDictionaryRequest(endpoint string, status int, elapsed time.Duration) {
+	outcome := "error"
+	if status != 0 {
+		outcome = strconv.Itoa(status)
+	}
+	dictionaryDuration.WithLabelValues(endpoint, outcome).Observe(elapsed.Seconds())
+}
+
+// ObserveDictionaryWait records how long a dictionary request waited for the rate limiter
+func ObserveDictionaryWait(waited time.Duration) {
+	dictionaryWait.Observe(waited.Seconds())
+}
+
+// RecordTestSaved counts a saved test result and the XP it awarded
+func RecordTestSaved(mode string, xp int) {
+	testsSaved.WithLabelValues(mode).Inc()
+	if xp > 0 {
+		xpAwarded.Add(float64(xp))
+	}
+}
@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/starefossen/diktator/backend/internal/services/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scrape(t *testing.T) string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	return w.Body.String()
+}
+
+func TestMiddleware_LabelsByRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Middleware())
+	r.GET("/api/wordsets/:id", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	for _, path := range []string{"/api/wordsets/a", "/api/wordsets/b", "/nowhere"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	body := scrape(t)
+	assert.Contains(t, body, `diktator_http_request_duration_seconds_count{method="GET",route="/api/wordsets/:id",status="204"} 2`)
+	assert.Contains(t, body, `diktator_http_request_duration_seconds_count{method="GET",route="unmatched",status="404"} 1`)
+	assert.NotContains(t, body, "/api/wordsets/a", "paths with IDs do not become labels")
+}
+
+func TestTrackCache_ExportsHitsAndSize(t *testing.T) {
+	c := cache.NewLRUCache(1024)
+	c.Put("hus", []byte("audio"))
+	c.Get("hus")
+	c.Get("hus")
+	c.Get("katt")
+	TrackCache("test", c)
+
+	body := scrape(t)
+	assert.Contains(t, body, `diktator_cache_hits_total{cache="test"} 2`)
+	assert.Contains(t, body, `diktator_cache_misses_total{cache="test"} 1`)
+	assert.Contains(t, body, `diktator_cache_bytes{cache="test"} 5`)
+	assert.Contains(t, body, `diktator_cache_max_bytes{cache="test"} 1024`)
+}
+
+func TestObserveSynthesis_CountsCharactersOnSuccessOnly(t *testing.T) {
+	before := testutil.ToFloat64(ttsCharacters.WithLabelValues("nb-NO-Test-A"))
+	ObserveSynthesis("nb-NO-Test-A", 4, 10*time.Millisecond, nil)
+	ObserveSynthesis("nb-NO-Test-A", 4, 10*time.Millisecond, assert.AnError)
+
+	assert.Equal(t, before+4, testutil.ToFloat64(ttsCharacters.WithLabelValues("nb-NO-Test-A")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(ttsRequests.WithLabelValues("nb-NO-Test-A", "error")))
+}
+
+func TestRecordTestSaved(t *testing.T) {
+	xpBefore := testutil.ToFloat64(xpAwarded)
+	RecordTestSaved("keyboard", 25)
+	RecordTestSaved("keyboard", 0)
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(testsSaved.WithLabelValues("keyboard")))
+	assert.Equal(t, xpBefore+25, testutil.ToFloat64(xpAwarded))
+}
+
+func TestHandler_ServesRuntimeMetrics(t *testing.T) {
+	body := scrape(t)
+	assert.Contains(t, body, "go_goroutines")
+	assert.NotContains(t, body, "diktator_db_pool_", "no pool is tracked without Postgres")
+}
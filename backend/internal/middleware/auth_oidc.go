@@ -2,9 +2,9 @@
 package middleware
 
 import (
-	"context"
+	"cmp"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"strings"
 
@@ -14,10 +14,12 @@ import (
 	"github.com/starefossen/diktator/backend/internal/services/db"
 )
 
-// OIDCAuthMiddleware validates JWT tokens via OIDC (or mock) and sets user context
-func OIDCAuthMiddleware(validator auth.SessionValidator, repo db.Repository) gin.HandlerFunc {
+// OIDCAuthMiddleware validates JWT tokens via OIDC (or mock) and sets user context.
+// A nil logger uses slog.Default().
+func OIDCAuthMiddleware(validator auth.SessionValidator, repo db.Repository, logger *slog.Logger) gin.HandlerFunc {
+	logger = cmp.Or(logger, slog.Default())
 	return func(c *gin.Context) {
-		identity, err := extractAndValidateToken(c, validator)
+		identity, err := extractAndValidateToken(c, validator, logger)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, models.APIResponse{
 				Error: err.Error(),
@@ -27,7 +29,7 @@ func OIDCAuthMiddleware(validator auth.SessionValidator, repo db.Repository) gin
 		}
 
 		// Get user from database using identity ID (subject from JWT)
-		user, err := repo.GetUserByAuthID(identity.ID)
+		user, err := repo.GetUserByAuthID(c.Request.Context(), identity.ID)
 		if err != nil {
 			if err == db.ErrUserNotFound {
 				c.JSON(http.StatusNotFound, models.APIResponse{
@@ -40,7 +42,8 @@ func OIDCAuthMiddleware(validator auth.SessionValidator, repo db.Repository) gin
 				return
 			}
 
-			log.Printf("[AUTH] Failed to lookup user authID=%s method=%s path=%s: %v", identity.ID, c.Request.Method, c.Request.URL.Path, err)
+			logger.ErrorContext(c.Request.Context(), "Failed to look up user",
+				"auth_id", identity.ID, "method", c.Request.Method, "path", c.Request.URL.Path, "error", err)
 			c.JSON(http.StatusInternalServerError, models.APIResponse{
 				Error: "Failed to lookup user",
 			})
@@ -71,9 +74,10 @@ func OIDCAuthMiddleware(validator auth.SessionValidator, repo db.Repository) gin
 
 // OIDCBasicAuthMiddleware validates tokens but doesn't require user existence in database
 // This is used for user registration endpoints
-func OIDCBasicAuthMiddleware(validator auth.SessionValidator) gin.HandlerFunc {
+func OIDCBasicAuthMiddleware(validator auth.SessionValidator, logger *slog.Logger) gin.HandlerFunc {
+	logger = cmp.Or(logger, slog.Default())
 	return func(c *gin.Context) {
-		identity, err := extractAndValidateToken(c, validator)
+		identity, err := extractAndValidateToken(c, validator, logger)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, models.APIResponse{
 				Error: err.Error(),
@@ -91,8 +95,8 @@ func OIDCBasicAuthMiddleware(validator auth.SessionValidator) gin.HandlerFunc {
 }
 
 // extractAndValidateToken extracts token from request and validates it
-func extractAndValidateToken(c *gin.Context, validator auth.SessionValidator) (*auth.Identity, error) {
-	ctx := context.Background()
+func extractAndValidateToken(c *gin.Context, validator auth.SessionValidator, logger *slog.Logger) (*auth.Identity, error) {
+	ctx := c.Request.Context()
 	var lastErr error
 
 	// Try Authorization header first (Bearer token) - most common for APIs
@@ -134,7 +138,8 @@ func extractAndValidateToken(c *gin.Context, validator auth.SessionValidator) (*
 	}
 
 	if lastErr != nil {
-		log.Printf("[AUTH] Token validation failed for %s %s: %v", c.Request.Method, c.Request.URL.Path, lastErr)
+		logger.InfoContext(ctx, "Token validation failed",
+			"method", c.Request.Method, "path", c.Request.URL.Path, "error", lastErr)
 	}
 	return nil, fmt.Errorf("no valid authentication token found")
 }
@@ -238,7 +243,7 @@ func RequireChildOwnership(repo db.Repository) gin.HandlerFunc {
 		}
 
 		// Verify that the user is the parent of this child
-		if err := repo.VerifyChildOwnership(userIDStr, childID); err != nil {
+		if err := repo.VerifyChildOwnership(c.Request.Context(), userIDStr, childID); err != nil {
 			c.JSON(http.StatusForbidden, models.APIResponse{
 				Error: "Access denied: You can only access your own children",
 			})
@@ -274,7 +279,7 @@ func RequireWordSetAccess(repo db.Repository) gin.HandlerFunc {
 		wordSetID := c.Param("id")
 		if wordSetID != "" {
 			// Verify word set belongs to user's family
-			if err := repo.VerifyWordSetAccess(userFamilyIDStr, wordSetID); err != nil {
+			if err := repo.VerifyWordSetAccess(c.Request.Context(), userFamilyIDStr, wordSetID); err != nil {
 				c.JSON(http.StatusForbidden, models.APIResponse{
 					Error: "Access denied: Word set not found or not accessible",
 				})
@@ -28,109 +28,207 @@ func (stubValidator) Close() error { return nil }
 
 type stubRepo struct{}
 
-func (stubRepo) Close() error                                        { return nil }
-func (stubRepo) GetUser(userID string) (*models.User, error)         { return nil, db.ErrUserNotFound }
-func (stubRepo) GetUserByAuthID(authID string) (*models.User, error) { return nil, db.ErrUserNotFound }
-func (stubRepo) GetUserByEmail(email string) (*models.User, error)   { return nil, db.ErrUserNotFound }
-func (stubRepo) CreateUser(user *models.User) error                  { return nil }
-func (stubRepo) UpdateUser(user *models.User) error                  { return nil }
-func (stubRepo) DeleteUser(userID string) error                      { return nil }
-func (stubRepo) LinkUserToAuthID(userID, authID string) error        { return nil }
-func (stubRepo) GetFamily(familyID string) (*models.Family, error)   { return nil, db.ErrFamilyNotFound }
-func (stubRepo) CreateFamily(family *models.Family) error            { return nil }
-func (stubRepo) UpdateFamily(family *models.Family) error            { return nil }
-func (stubRepo) DeleteFamily(familyID string) error                  { return nil }
-func (stubRepo) AddFamilyMember(familyID, userID, role string) error { return nil }
-func (stubRepo) GetChild(childID string) (*models.ChildAccount, error) {
+func (stubRepo) Close() error { return nil }
+func (r stubRepo) WithTx(_ context.Context, fn func(tx db.Repository) error) error {
+	return fn(r)
+}
+func (stubRepo) GetUser(_ context.Context, userID string) (*models.User, error) {
+	return nil, db.ErrUserNotFound
+}
+func (stubRepo) GetUserByAuthID(_ context.Context, authID string) (*models.User, error) {
+	return nil, db.ErrUserNotFound
+}
+func (stubRepo) GetUserByEmail(_ context.Context, email string) (*models.User, error) {
+	return nil, db.ErrUserNotFound
+}
+func (stubRepo) CreateUser(_ context.Context, user *models.User) error           { return nil }
+func (stubRepo) UpdateUser(_ context.Context, user *models.User) error           { return nil }
+func (stubRepo) DeleteUser(_ context.Context, userID string) error               { return nil }
+func (stubRepo) LinkUserToAuthID(_ context.Context, userID, authID string) error { return nil }
+func (stubRepo) GetFamily(_ context.Context, familyID string) (*models.Family, error) {
+	return nil, db.ErrFamilyNotFound
+}
+func (stubRepo) CreateFamily(_ context.Context, family *models.Family) error            { return nil }
+func (stubRepo) UpdateFamily(_ context.Context, family *models.Family) error            { return nil }
+func (stubRepo) DeleteFamily(_ context.Context, familyID string) error                  { return nil }
+func (stubRepo) AddFamilyMember(_ context.Context, familyID, userID, role string) error { return nil }
+func (stubRepo) GetChild(_ context.Context, childID string) (*models.ChildAccount, error) {
 	return nil, db.ErrChildNotFound
 }
-func (stubRepo) GetFamilyChildren(familyID string) ([]models.ChildAccount, error) {
+func (stubRepo) GetFamilyChildren(_ context.Context, familyID string) ([]models.ChildAccount, error) {
+	return nil, nil
+}
+func (stubRepo) CreateChild(_ context.Context, child *models.ChildAccount) error { return nil }
+func (stubRepo) UpdateChild(_ context.Context, child *models.ChildAccount) error { return nil }
+func (stubRepo) DeleteChild(_ context.Context, childID string) error             { return nil }
+func (stubRepo) GetWordSet(_ context.Context, id string) (*models.WordSet, error) {
+	return nil, db.ErrWordSetNotFound
+}
+func (stubRepo) GetWordSets(_ context.Context, familyID string) ([]models.WordSet, error) {
+	return nil, nil
+}
+func (stubRepo) CreateWordSet(_ context.Context, wordSet *models.WordSet) error { return nil }
+func (stubRepo) UpdateWordSet(_ context.Context, wordSet *models.WordSet) error { return nil }
+func (stubRepo) DeleteWordSet(_ context.Context, id string) error               { return nil }
+func (stubRepo) GetTestResults(_ context.Context, userID string) ([]models.TestResult, error) {
+	return nil, nil
+}
+func (stubRepo) GetFamilyResults(_ context.Context, familyID string) ([]models.TestResult, error) {
+	return nil, nil
+}
+func (stubRepo) SaveTestResult(_ context.Context, result *models.TestResult) error { return nil }
+func (stubRepo) GetTestResultByIdempotencyKey(_ context.Context, userID, key string) (*models.TestResult, error) {
+	return nil, nil
+}
+func (stubRepo) ListTestResults(_ context.Context, filter models.ResultFilter, page models.PageRequest) ([]models.TestResult, string, error) {
+	return nil, "", nil
+}
+func (stubRepo) GetRecentWordSetResults(_ context.Context, userID, wordSetID string, limit int) ([]models.TestResult, error) {
 	return nil, nil
 }
-func (stubRepo) CreateChild(child *models.ChildAccount) error                  { return nil }
-func (stubRepo) UpdateChild(child *models.ChildAccount) error                  { return nil }
-func (stubRepo) DeleteChild(childID string) error                              { return nil }
-func (stubRepo) GetWordSet(id string) (*models.WordSet, error)                 { return nil, db.ErrWordSetNotFound }
-func (stubRepo) GetWordSets(familyID string) ([]models.WordSet, error)         { return nil, nil }
-func (stubRepo) CreateWordSet(wordSet *models.WordSet) error                   { return nil }
-func (stubRepo) UpdateWordSet(wordSet *models.WordSet) error                   { return nil }
-func (stubRepo) DeleteWordSet(id string) error                                 { return nil }
-func (stubRepo) GetTestResults(userID string) ([]models.TestResult, error)     { return nil, nil }
-func (stubRepo) GetFamilyResults(familyID string) ([]models.TestResult, error) { return nil, nil }
-func (stubRepo) SaveTestResult(result *models.TestResult) error                { return nil }
-func (stubRepo) GetAudioFile(word, language, voiceID string) (*models.AudioFile, error) {
+func (stubRepo) GetAudioFile(_ context.Context, word, language, voiceID string) (*models.AudioFile, error) {
 	return nil, nil
 }
-func (stubRepo) SaveAudioFile(audioFile *models.AudioFile) error { return nil }
-func (stubRepo) GetFamilyProgress(familyID string) ([]models.FamilyProgress, error) {
+func (stubRepo) SaveAudioFile(_ context.Context, audioFile *models.AudioFile) error { return nil }
+func (stubRepo) GetFamilyProgress(_ context.Context, familyID string) ([]models.FamilyProgress, error) {
 	return nil, nil
 }
-func (stubRepo) GetFamilyStats(familyID string) (*models.FamilyStats, error) { return nil, nil }
-func (stubRepo) GetUserProgress(userID string) (*models.FamilyProgress, error) {
+func (stubRepo) GetFamilyStats(_ context.Context, familyID string) (*models.FamilyStats, error) {
+	return nil, nil
+}
+func (stubRepo) GetUserProgress(_ context.Context, userID string) (*models.FamilyProgress, error) {
 	return nil, nil
 }
 
 // Family invitation operations
-func (stubRepo) CreateFamilyInvitation(invitation *models.FamilyInvitation) error {
+func (stubRepo) CreateFamilyInvitation(_ context.Context, invitation *models.FamilyInvitation) error {
 	return nil
 }
-func (stubRepo) GetPendingInvitationsByEmail(email string) ([]models.FamilyInvitation, error) {
+func (stubRepo) GetPendingInvitationsByEmail(_ context.Context, email string) ([]models.FamilyInvitation, error) {
 	return nil, nil
 }
-func (stubRepo) GetFamilyInvitations(familyID string) ([]models.FamilyInvitation, error) {
+func (stubRepo) GetFamilyInvitations(_ context.Context, familyID string) ([]models.FamilyInvitation, error) {
 	return nil, nil
 }
-func (stubRepo) AcceptInvitation(invitationID, userID string) error { return nil }
-func (stubRepo) DeleteInvitation(invitationID string) error         { return nil }
+func (stubRepo) AcceptInvitation(_ context.Context, invitationID, userID string) error { return nil }
+func (stubRepo) DeleteInvitation(_ context.Context, invitationID string) error         { return nil }
 
-func (stubRepo) VerifyFamilyMembership(userID, familyID string) error { return nil }
-func (stubRepo) VerifyParentPermission(userID, familyID string) error { return nil }
-func (stubRepo) VerifyChildOwnership(parentID, childID string) error  { return nil }
-func (stubRepo) VerifyWordSetAccess(familyID, wordSetID string) error { return nil }
+func (stubRepo) VerifyFamilyMembership(_ context.Context, userID, familyID string) error { return nil }
+func (stubRepo) VerifyParentPermission(_ context.Context, userID, familyID string) error { return nil }
+func (stubRepo) VerifyChildOwnership(_ context.Context, parentID, childID string) error  { return nil }
+func (stubRepo) VerifyWordSetAccess(_ context.Context, familyID, wordSetID string) error { return nil }
 
 // Word set assignment methods
-func (stubRepo) AssignWordSetToUser(wordSetID, userID, assignedBy string) error {
+func (stubRepo) AssignWordSetToUser(_ context.Context, wordSetID, userID, assignedBy string) error {
 	return nil
 }
-func (stubRepo) UnassignWordSetFromUser(wordSetID, userID string) error { return nil }
-func (stubRepo) GetWordSetAssignments(wordSetID string) ([]string, error) {
+func (stubRepo) UnassignWordSetFromUser(_ context.Context, wordSetID, userID string) error {
+	return nil
+}
+func (stubRepo) GetWordSetAssignments(_ context.Context, wordSetID string) ([]string, error) {
 	return nil, nil
 }
-func (stubRepo) UpdateUserDisplayName(userID, displayName string) error    { return nil }
-func (stubRepo) UpdateChildDisplayName(childID, displayName string) error  { return nil }
-func (stubRepo) UpdateChildBirthYear(childID string, birthYear *int) error { return nil }
-func (stubRepo) GetGlobalWordSets() ([]models.WordSet, error)              { return nil, nil }
-func (stubRepo) IsGlobalWordSet(wordSetID string) (bool, error)            { return false, nil }
+func (stubRepo) UpdateUserDisplayName(_ context.Context, userID, displayName string) error {
+	return nil
+}
+func (stubRepo) UpdateChildDisplayName(_ context.Context, childID, displayName string) error {
+	return nil
+}
+func (stubRepo) UpdateChildBirthYear(_ context.Context, childID string, birthYear *int) error {
+	return nil
+}
+func (stubRepo) ListWordSets(_ context.Context, familyID string, filter models.WordSetFilter, page models.PageRequest) ([]models.WordSet, string, error) {
+	return nil, "", nil
+}
+func (stubRepo) GetGlobalWordSets(_ context.Context) ([]models.WordSet, error)     { return nil, nil }
+func (stubRepo) IsGlobalWordSet(_ context.Context, wordSetID string) (bool, error) { return false, nil }
 
 // Word mastery operations
-func (stubRepo) GetWordMastery(userID, wordSetID, word string) (*models.WordMastery, error) {
+func (stubRepo) GetWordMastery(_ context.Context, userID, wordSetID, word string) (*models.WordMastery, error) {
 	return nil, nil
 }
-func (stubRepo) GetWordSetMastery(userID, wordSetID string) ([]models.WordMastery, error) {
+func (stubRepo) GetWordSetMastery(_ context.Context, userID, wordSetID string) ([]models.WordMastery, error) {
 	return nil, nil
 }
-func (stubRepo) IncrementMastery(userID, wordSetID, word string, mode models.TestMode) (*models.WordMastery, error) {
+func (stubRepo) IncrementMastery(_ context.Context, userID, wordSetID, word string, mode models.TestMode) (*models.WordMastery, error) {
 	return nil, nil
 }
 
 // XP operations
-func (stubRepo) GetUserXP(userID string) (int, int, error) {
+func (stubRepo) GetUserXP(_ context.Context, userID string) (int, int, error) {
 	return 0, 1, nil
 }
-func (stubRepo) UpdateUserXP(userID string, xpAwarded, newTotalXP, newLevel int) error {
+func (stubRepo) UpdateUserXP(_ context.Context, userID string, xpAwarded, newTotalXP, newLevel int) error {
 	return nil
 }
-func (stubRepo) GetRecentCompletions(userID, wordSetID, mode string, since time.Time) (int, error) {
+func (stubRepo) GetRecentCompletions(_ context.Context, userID, wordSetID, mode string, since, until time.Time) (int, error) {
 	return 0, nil
 }
-func (stubRepo) IsFirstCompletion(userID, wordSetID, mode string) (bool, error) {
+func (stubRepo) IsFirstCompletion(_ context.Context, userID, wordSetID, mode string) (bool, error) {
 	return true, nil
 }
 
+// Family API key operations
+func (stubRepo) CreateFamilyAPIKey(_ context.Context, key *models.FamilyAPIKey) error { return nil }
+func (stubRepo) GetFamilyAPIKeyByHash(_ context.Context, keyHash string) (*models.FamilyAPIKey, error) {
+	return nil, db.ErrNotFound
+}
+func (stubRepo) GetFamilyAPIKeys(_ context.Context, familyID string) ([]models.FamilyAPIKey, error) {
+	return nil, nil
+}
+func (stubRepo) DeleteFamilyAPIKey(_ context.Context, familyID, keyID string) error { return nil }
+func (stubRepo) TouchFamilyAPIKey(_ context.Context, keyID string) error            { return nil }
+
+// Webhook operations
+func (stubRepo) CreateWebhook(_ context.Context, webhook *models.Webhook) error { return nil }
+func (stubRepo) GetFamilyWebhooks(_ context.Context, familyID string) ([]models.Webhook, error) {
+	return nil, nil
+}
+func (stubRepo) DeleteWebhook(_ context.Context, familyID, webhookID string) error { return nil }
+func (stubRepo) GetWebhookDeliveries(_ context.Context, familyID, webhookID string, limit int) ([]models.WebhookDelivery, error) {
+	return nil, nil
+}
+func (stubRepo) EnqueueWebhookDeliveries(_ context.Context, familyID, eventID, eventType string, payload []byte) (int, error) {
+	return 0, nil
+}
+func (stubRepo) ClaimWebhookDeliveries(_ context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error) {
+	return nil, nil
+}
+func (stubRepo) UpdateWebhookDelivery(_ context.Context, delivery *models.WebhookDelivery) error {
+	return nil
+}
+func (stubRepo) SetWordSetAssignmentSchedule(_ context.Context, wordSetID, userID string, schedule *models.AssignmentSchedule) error {
+	return nil
+}
+func (stubRepo) GetUserAssignments(_ context.Context, userID string) ([]models.WordSetAssignment, error) {
+	return nil, nil
+}
+func (stubRepo) GetReminderAssignments(_ context.Context) ([]models.WordSetAssignment, error) {
+	return nil, nil
+}
+func (stubRepo) ClaimAssignmentReminder(_ context.Context, wordSetID, userID string, slot, remindedAt time.Time) (bool, error) {
+	return false, nil
+}
+func (stubRepo) GetDigestPreferences(_ context.Context, userID string) (*models.DigestPreferences, error) {
+	return nil, nil
+}
+func (stubRepo) UpsertDigestPreferences(_ context.Context, prefs *models.DigestPreferences) error {
+	return nil
+}
+func (stubRepo) ClaimDueDigestRecipients(_ context.Context, sentBefore, claimedAt time.Time) ([]models.DigestRecipient, error) {
+	return nil, nil
+}
+func (stubRepo) ReleaseDigestClaim(_ context.Context, userID string, claimedAt time.Time, previous *time.Time) error {
+	return nil
+}
+func (stubRepo) GetTestResultsInRange(_ context.Context, userID string, from, to time.Time) ([]models.TestResult, error) {
+	return nil, nil
+}
+
 func TestOIDCAuthMiddlewareRequiresRegistration(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
-	r.Use(OIDCAuthMiddleware(stubValidator{}, stubRepo{}))
+	r.Use(OIDCAuthMiddleware(stubValidator{}, stubRepo{}, nil))
 	r.GET("/protected", func(c *gin.Context) { c.Status(http.StatusOK) })
 
 	req := httptest.NewRequest(http.MethodGet, "/protected", nil)
@@ -154,3 +252,50 @@ func TestOIDCAuthMiddlewareRequiresRegistration(t *testing.T) {
 		t.Fatalf("expected needsRegistration flag in response")
 	}
 }
+func (stubRepo) CreateNotifications(_ context.Context, notifications []models.Notification) error {
+	return nil
+}
+func (stubRepo) GetNotifications(_ context.Context, userID string, unreadOnly bool, limit int) ([]models.Notification, error) {
+	return nil, nil
+}
+func (stubRepo) CountUnreadNotifications(_ context.Context, userID string) (int, error) {
+	return 0, nil
+}
+func (stubRepo) MarkNotificationsRead(_ context.Context, userID string, ids []string, readAt time.Time) (int, error) {
+	return 0, nil
+}
+func (stubRepo) DismissNotifications(_ context.Context, userID string, ids []string) (int, error) {
+	return 0, nil
+}
+func (stubRepo) CreateTestSession(_ context.Context, session *models.TestSession) error { return nil }
+func (stubRepo) GetTestSession(_ context.Context, userID, sessionID string) (*models.TestSession, error) {
+	return nil, nil
+}
+func (stubRepo) GetActiveTestSessions(_ context.Context, userID string, now time.Time) ([]models.TestSession, error) {
+	return nil, nil
+}
+func (stubRepo) UpdateTestSession(_ context.Context, session *models.TestSession) error { return nil }
+func (stubRepo) DeleteExpiredTestSessions(_ context.Context, before time.Time) (int, error) {
+	return 0, nil
+}
+func (stubRepo) GetMissedWords(_ context.Context, userID string, from, to time.Time, limit int) ([]models.MissedWord, error) {
+	return nil, nil
+}
+func (stubRepo) GetErrorTypeCounts(_ context.Context, userID string, from, to time.Time, bucket string, errorTypes []string) ([]models.ErrorTypeCount, error) {
+	return nil, nil
+}
+func (stubRepo) GetWordAttemptStats(_ context.Context, userID string, from, to time.Time, limit int) ([]models.WordAttemptStats, error) {
+	return nil, nil
+}
+func (stubRepo) GetRegressedWords(_ context.Context, userID string, from, to time.Time, minPriorCorrect, limit int) ([]models.RegressedWord, error) {
+	return nil, nil
+}
+func (stubRepo) GetPracticeList(_ context.Context, childID string) (*models.PracticeList, error) {
+	return nil, nil
+}
+func (stubRepo) SavePracticeList(_ context.Context, list *models.PracticeList) error { return nil }
+func (stubRepo) GetChallengeLock(_ context.Context, wordSetID, childID string) (*models.ChallengeLock, error) {
+	return nil, nil
+}
+func (stubRepo) SaveChallengeLock(_ context.Context, lock *models.ChallengeLock) error  { return nil }
+func (stubRepo) DeleteChallengeLock(_ context.Context, wordSetID, childID string) error { return nil }
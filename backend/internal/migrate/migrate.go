@@ -2,9 +2,12 @@
 package migrate
 
 import (
+	"cmp"
 	"embed"
+	"errors"
 	"fmt"
-	"log"
+	"io/fs"
+	"log/slog"
 	"time"
 
 	"github.com/golang-migrate/migrate/v4"
@@ -15,12 +18,13 @@ import (
 //go:embed migrations/*.sql
 var migrationsFS embed.FS
 
-// Run executes database migrations with retry logic
-func Run(databaseURL string) error {
+// Run executes database migrations with retry logic. A nil logger uses slog.Default().
+func Run(databaseURL string, logger *slog.Logger) error {
 	const maxRetries = 30
 	const retryDelay = 2 * time.Second
 
-	log.Println("[MIGRATE] Starting database migrations...")
+	logger = cmp.Or(logger, slog.Default())
+	logger.Info("Starting database migrations")
 
 	// Create migration source from embedded files
 	source, err := iofs.New(migrationsFS, "migrations")
@@ -37,7 +41,8 @@ func Run(databaseURL string) error {
 		}
 
 		if i < maxRetries-1 {
-			log.Printf("[MIGRATE] Database not ready (attempt %d/%d), retrying in %v: %v", i+1, maxRetries, retryDelay, err)
+			logger.Warn("Database not ready, retrying",
+				"attempt", i+1, "max_attempts", maxRetries, "retry_in", retryDelay, "error", err)
 			time.Sleep(retryDelay)
 			continue
 		}
@@ -47,10 +52,10 @@ func Run(databaseURL string) error {
 	defer m.Close()
 
 	// Run migrations
-	log.Println("[MIGRATE] Applying migrations...")
+	logger.Info("Applying migrations")
 	if err := m.Up(); err != nil {
 		if err == migrate.ErrNoChange {
-			log.Println("[MIGRATE] Database schema is up to date")
+			logger.Info("Database schema is up to date")
 			return nil
 		}
 
@@ -62,13 +67,14 @@ func Run(databaseURL string) error {
 			}
 
 			if dirty {
-				log.Printf("[MIGRATE] ⚠️  DIRTY DATABASE DETECTED - Version %d is marked dirty", version)
-				log.Printf("[MIGRATE] This usually means a previous migration failed partway through.")
-				log.Printf("[MIGRATE] To fix manually:")
-				log.Printf("[MIGRATE]   1. Connect to database: kubectl exec -it -n diktator diktator-db-1 -- psql diktator")
-				log.Printf("[MIGRATE]   2. Check state: SELECT * FROM schema_migrations;")
-				log.Printf("[MIGRATE]   3. Fix if needed: UPDATE schema_migrations SET dirty = false WHERE version = %d;", version)
-				log.Printf("[MIGRATE]   4. Or rollback: DELETE FROM schema_migrations WHERE version = %d;", version)
+				// A previous migration failed partway through
+				logger.Error("Dirty database detected, manual intervention required",
+					"version", version,
+					"connect", "kubectl exec -it -n diktator diktator-db-1 -- psql diktator",
+					"check", "SELECT * FROM schema_migrations;",
+					"fix", fmt.Sprintf("UPDATE schema_migrations SET dirty = false WHERE version = %d;", version),
+					"rollback", fmt.Sprintf("DELETE FROM schema_migrations WHERE version = %d;", version),
+				)
 				return fmt.Errorf("database is in dirty state at version %d - manual intervention required", version)
 			}
 		}
@@ -76,10 +82,53 @@ func Run(databaseURL string) error {
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
 
-	log.Println("[MIGRATE] ✅ Database migrations completed successfully")
+	logger.Info("Database migrations completed")
 	return nil
 }
 
+// LatestVersion returns the version of the newest embedded migration
+func LatestVersion() (uint, error) {
+	source, err := iofs.New(migrationsFS, "migrations")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create migration source: %w", err)
+	}
+	defer source.Close()
+
+	version, err := source.First()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read migrations: %w", err)
+	}
+	for {
+		next, err := source.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read migrations: %w", err)
+		}
+		version = next
+	}
+}
+
+// CheckVersion compares the database's schema version with the newest
+// embedded migration. A newer schema is accepted, since during a rollout old
+// replicas keep serving after a new one has migrated.
+func CheckVersion(version uint, dirty bool) (string, error) {
+	latest, err := LatestVersion()
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case dirty:
+		return "", fmt.Errorf("database is in dirty state at version %d", version)
+	case version < latest:
+		return "", fmt.Errorf("schema is at version %d, want %d", version, latest)
+	case version > latest:
+		return fmt.Sprintf("version %d (newer than %d)", version, latest), nil
+	}
+	return fmt.Sprintf("version %d", version), nil
+}
+
 // isDirtyError checks if the error is a dirty database error
 func isDirtyError(err error) bool {
 	if err == nil {
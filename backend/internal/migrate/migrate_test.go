@@ -0,0 +1,28 @@
+package migrate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckVersion(t *testing.T) {
+	latest, err := LatestVersion()
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, latest, uint(28))
+
+	detail, err := CheckVersion(latest, false)
+	require.NoError(t, err)
+	assert.Contains(t, detail, "version")
+
+	_, err = CheckVersion(latest-1, false)
+	assert.ErrorContains(t, err, "want")
+
+	_, err = CheckVersion(latest, true)
+	assert.ErrorContains(t, err, "dirty")
+
+	detail, err = CheckVersion(latest+1, false)
+	require.NoError(t, err, "a newer schema from a rollout is accepted")
+	assert.Contains(t, detail, "newer")
+}
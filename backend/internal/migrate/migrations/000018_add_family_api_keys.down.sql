@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_family_api_keys_family;
+DROP TABLE IF EXISTS family_api_keys;
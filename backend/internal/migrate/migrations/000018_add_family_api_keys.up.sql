@@ -0,0 +1,17 @@
+-- Migration: Add family API keys
+-- Long-lived, family-scoped credentials for non-interactive clients such as
+-- the MCP server (cmd/mcp). Only a SHA-256 hash of the key is stored; the
+-- plaintext key is shown to the parent once when it is created.
+
+CREATE TABLE IF NOT EXISTS family_api_keys (
+    id TEXT PRIMARY KEY,
+    family_id TEXT NOT NULL REFERENCES families(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    key_prefix TEXT NOT NULL, -- First characters of the key, for display only
+    key_hash TEXT NOT NULL UNIQUE,
+    created_by TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE, -- Parent who created the key; used as actor for writes
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_used_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_family_api_keys_family ON family_api_keys(family_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_digest_preferences_due;
+DROP TABLE IF EXISTS digest_preferences;
@@ -0,0 +1,15 @@
+-- Migration: Add weekly email digest preferences
+-- Each parent opts in to a weekly email summarising their children's progress.
+-- last_sent_at drives the digest scheduler so a restart never sends twice in a week.
+
+CREATE TABLE IF NOT EXISTS digest_preferences (
+    user_id TEXT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    enabled BOOLEAN NOT NULL DEFAULT false,
+    language TEXT NOT NULL DEFAULT 'no' CHECK (language IN ('no', 'en')),
+    last_sent_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Scheduler looks up opted-in parents whose last digest is older than a week
+CREATE INDEX IF NOT EXISTS idx_digest_preferences_due ON digest_preferences(last_sent_at) WHERE enabled = true;
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_wordset_assignments_reminders;
+
+ALTER TABLE wordset_assignments DROP COLUMN IF EXISTS last_reminded_at;
+ALTER TABLE wordset_assignments DROP COLUMN IF EXISTS reminder_hour;
+ALTER TABLE wordset_assignments DROP COLUMN IF EXISTS reminder_frequency;
+ALTER TABLE wordset_assignments DROP COLUMN IF EXISTS goal_min_score;
+ALTER TABLE wordset_assignments DROP COLUMN IF EXISTS goal_runs;
+ALTER TABLE wordset_assignments DROP COLUMN IF EXISTS goal_mode;
+ALTER TABLE wordset_assignments DROP COLUMN IF EXISTS due_at;
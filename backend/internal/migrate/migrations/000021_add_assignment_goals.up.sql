@@ -0,0 +1,18 @@
+-- Migration: Add due dates, practice goals and reminders to word set assignments
+-- A goal is met when the child completes goal_runs tests on the word set since it was
+-- assigned, each scoring at least goal_min_score, optionally restricted to goal_mode.
+-- The reminder scheduler nudges the child at reminder_hour (Europe/Oslo) until the goal
+-- is met or the due date passes.
+
+ALTER TABLE wordset_assignments ADD COLUMN IF NOT EXISTS due_at TIMESTAMPTZ;
+ALTER TABLE wordset_assignments ADD COLUMN IF NOT EXISTS goal_mode TEXT; -- NULL counts any mode
+ALTER TABLE wordset_assignments ADD COLUMN IF NOT EXISTS goal_runs INTEGER CHECK (goal_runs IS NULL OR goal_runs > 0);
+ALTER TABLE wordset_assignments ADD COLUMN IF NOT EXISTS goal_min_score DOUBLE PRECISION CHECK (goal_min_score IS NULL OR (goal_min_score >= 0 AND goal_min_score <= 100));
+ALTER TABLE wordset_assignments ADD COLUMN IF NOT EXISTS reminder_frequency TEXT NOT NULL DEFAULT 'none'
+    CHECK (reminder_frequency IN ('none', 'daily', 'weekdays'));
+ALTER TABLE wordset_assignments ADD COLUMN IF NOT EXISTS reminder_hour INTEGER NOT NULL DEFAULT 17
+    CHECK (reminder_hour >= 0 AND reminder_hour <= 23);
+ALTER TABLE wordset_assignments ADD COLUMN IF NOT EXISTS last_reminded_at TIMESTAMPTZ;
+
+-- Scheduler scans assignments that have reminders turned on
+CREATE INDEX IF NOT EXISTS idx_wordset_assignments_reminders ON wordset_assignments(reminder_frequency) WHERE reminder_frequency <> 'none';
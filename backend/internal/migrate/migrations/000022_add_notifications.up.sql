@@ -0,0 +1,20 @@
+-- Migration: Add in-app notifications
+-- One row per recipient. Text is not stored: message_key and params are rendered
+-- by the client in the recipient's language. Dismissed notifications are deleted.
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE, -- Recipient
+    family_id TEXT NOT NULL REFERENCES families(id) ON DELETE CASCADE,
+    subject_user_id TEXT REFERENCES users(id) ON DELETE SET NULL, -- The user the notification is about
+    kind TEXT NOT NULL,
+    message_key TEXT NOT NULL,
+    params JSONB NOT NULL DEFAULT '{}',
+    read_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Inbox listing, newest first
+CREATE INDEX IF NOT EXISTS idx_notifications_user_created ON notifications(user_id, created_at DESC);
+-- Unread badge count
+CREATE INDEX IF NOT EXISTS idx_notifications_unread ON notifications(user_id) WHERE read_at IS NULL;
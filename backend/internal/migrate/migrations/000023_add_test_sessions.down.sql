@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_test_sessions_expires;
+DROP INDEX IF EXISTS idx_test_sessions_user_active;
+DROP TABLE IF EXISTS test_sessions;
@@ -0,0 +1,27 @@
+-- Migration: Add server-side test sessions
+-- A session holds the picked words and every answer so a test survives reloads and
+-- the server can measure time spent. Word state is a JSONB array updated with an
+-- optimistic version check; rows are deleted by the cleanup worker once expired.
+
+CREATE TABLE IF NOT EXISTS test_sessions (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    family_id TEXT NOT NULL REFERENCES families(id) ON DELETE CASCADE,
+    word_set_id TEXT NOT NULL REFERENCES word_sets(id) ON DELETE CASCADE,
+    mode TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'completed')),
+    words JSONB NOT NULL,
+    current_index INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL DEFAULT 3 CHECK (max_attempts > 0),
+    result_id TEXT REFERENCES test_results(id) ON DELETE SET NULL,
+    version INT NOT NULL DEFAULT 0,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_activity_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    completed_at TIMESTAMPTZ
+);
+
+-- Resume lookup of a user's unfinished sessions
+CREATE INDEX IF NOT EXISTS idx_test_sessions_user_active ON test_sessions(user_id, last_activity_at DESC) WHERE status = 'active';
+-- Cleanup worker
+CREATE INDEX IF NOT EXISTS idx_test_sessions_expires ON test_sessions(expires_at);
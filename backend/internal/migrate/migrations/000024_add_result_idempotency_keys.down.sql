@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_test_results_idempotency_key;
+ALTER TABLE test_results DROP COLUMN IF EXISTS idempotency_key;
@@ -0,0 +1,10 @@
+-- Migration: Add idempotency keys to test results
+-- Offline clients queue results with a client-generated key and replay them when back
+-- online. The key is unique per user so a replayed result is recognized instead of
+-- being saved (and awarded XP) twice. Results saved without a key keep NULL.
+
+ALTER TABLE test_results ADD COLUMN IF NOT EXISTS idempotency_key TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_test_results_idempotency_key
+    ON test_results(user_id, idempotency_key)
+    WHERE idempotency_key IS NOT NULL;
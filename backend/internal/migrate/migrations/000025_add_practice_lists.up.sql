@@ -0,0 +1,17 @@
+-- Migration: Add personal practice lists
+-- A practice list ("øveliste") is a family word set built from the words a child
+-- missed recently. This table links the child to that word set and keeps the
+-- settings used to build it, so the list can be rebuilt as words get mastered.
+-- Deleting the word set removes the practice list.
+
+CREATE TABLE IF NOT EXISTS practice_lists (
+    child_id TEXT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    family_id TEXT NOT NULL REFERENCES families(id) ON DELETE CASCADE,
+    word_set_id TEXT NOT NULL UNIQUE REFERENCES word_sets(id) ON DELETE CASCADE,
+    created_by TEXT NOT NULL,
+    window_days INT NOT NULL CHECK (window_days > 0),
+    max_words INT NOT NULL CHECK (max_words > 0),
+    include_curated BOOLEAN NOT NULL DEFAULT false,
+    refreshed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
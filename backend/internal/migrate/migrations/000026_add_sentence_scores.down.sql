@@ -0,0 +1 @@
+ALTER TABLE word_test_results DROP COLUMN IF EXISTS sentence_score;
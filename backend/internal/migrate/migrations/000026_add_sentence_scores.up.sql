@@ -0,0 +1,6 @@
+-- Migration: Add word-level sentence scores to word test results
+-- Sentence dictation answers are aligned word by word against the target sentence.
+-- The per-word statuses, partial credit and focus word error types are stored as
+-- JSON next to the word result. Single-word items keep NULL.
+
+ALTER TABLE word_test_results ADD COLUMN IF NOT EXISTS sentence_score JSONB;
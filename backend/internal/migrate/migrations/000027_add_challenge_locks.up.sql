@@ -0,0 +1,14 @@
+-- Migration: Add challenge locks
+-- Letter tiles, word banks and missing-letter gaps are generated from a seed.
+-- A parent can preview the challenges for a child and lock the seed, so the
+-- child gets exactly those challenges until the lock is removed. Only the seed
+-- is stored; the challenges are regenerated from the current word set.
+
+CREATE TABLE IF NOT EXISTS challenge_locks (
+    word_set_id TEXT NOT NULL REFERENCES word_sets(id) ON DELETE CASCADE,
+    child_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    seed BIGINT NOT NULL CHECK (seed >= 0),
+    locked_by TEXT NOT NULL,
+    locked_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (word_set_id, child_id)
+);
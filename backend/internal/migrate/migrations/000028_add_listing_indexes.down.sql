@@ -0,0 +1,8 @@
+CREATE INDEX IF NOT EXISTS idx_word_sets_family_created ON word_sets(family_id, created_at DESC);
+DROP INDEX IF EXISTS idx_word_sets_family_created_id;
+
+CREATE INDEX IF NOT EXISTS idx_test_results_user_completed ON test_results(user_id, completed_at DESC);
+CREATE INDEX IF NOT EXISTS idx_test_results_user_mode ON test_results(user_id, mode);
+DROP INDEX IF EXISTS idx_test_results_user_mode_completed;
+DROP INDEX IF EXISTS idx_test_results_user_word_set_completed;
+DROP INDEX IF EXISTS idx_test_results_user_completed_id;
@@ -0,0 +1,17 @@
+-- Migration: Add keyset indexes for paginated listings
+-- Result and word set listings page with a (timestamp, id) cursor. These indexes
+-- serve the ordering and the cursor comparison together, including when results
+-- are filtered to one word set or mode. They replace the indexes they extend.
+
+CREATE INDEX IF NOT EXISTS idx_test_results_user_completed_id
+    ON test_results(user_id, completed_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_test_results_user_word_set_completed
+    ON test_results(user_id, word_set_id, completed_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_test_results_user_mode_completed
+    ON test_results(user_id, mode, completed_at DESC, id DESC);
+DROP INDEX IF EXISTS idx_test_results_user_completed;
+DROP INDEX IF EXISTS idx_test_results_user_mode;
+
+CREATE INDEX IF NOT EXISTS idx_word_sets_family_created_id
+    ON word_sets(family_id, created_at DESC, id DESC);
+DROP INDEX IF EXISTS idx_word_sets_family_created;
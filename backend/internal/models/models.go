@@ -1,6 +1,8 @@
 package models
 
 import (
+	"encoding/json"
+	"slices"
 	"time"
 )
 
@@ -129,6 +131,16 @@ const (
 	DifficultyAdvanced     DifficultyLevel = "advanced"
 )
 
+// IsValidGradeLevel checks if a grade level is one of the LK20 grade bands
+func IsValidGradeLevel(grade GradeLevel) bool {
+	return slices.Contains([]GradeLevel{GradeLevel12, GradeLevel34, GradeLevel57}, grade)
+}
+
+// IsValidDifficultyLevel checks if a difficulty is a known difficulty level
+func IsValidDifficultyLevel(difficulty DifficultyLevel) bool {
+	return slices.Contains([]DifficultyLevel{DifficultyBeginner, DifficultyIntermediate, DifficultyAdvanced}, difficulty)
+}
+
 // SpellingFocusCategory represents spelling challenge categories
 // Uses camelCase to match frontend JSON conventions
 type SpellingFocusCategory string
@@ -145,6 +157,29 @@ const (
 	SpellingFocusVowelLength     SpellingFocusCategory = "vowelLength"     // Vokalforlengelse (tak/takk)
 )
 
+// errorTypeFocus maps the frontend's spelling error types to the spelling focus
+// categories that train them
+var errorTypeFocus = map[string][]SpellingFocusCategory{
+	"doubleConsonant": {SpellingFocusDoubleConsonant, SpellingFocusVowelLength},
+	"silentH":         {SpellingFocusSilentLetter},
+	"silentD":         {SpellingFocusSilentD, SpellingFocusSilentLetter},
+	"silentG":         {SpellingFocusSilentLetter},
+	"silentV":         {SpellingFocusSilentLetter},
+	"silentT":         {SpellingFocusSilentLetter},
+	"gjHjJ":           {SpellingFocusSilentLetter},
+	"kjSkjSj":         {SpellingFocusSkjSound},
+	"vowelAeE":        {SpellingFocusSpecialChars},
+	"diphthong":       {SpellingFocusDiphthong},
+	"velarNg":         {SpellingFocusNgNk},
+	"compound":        {SpellingFocusCompoundWord},
+}
+
+// SpellingFocusForErrorType returns the spelling focus categories that train a
+// detected error type, or nil for unknown types
+func SpellingFocusForErrorType(errorType string) []SpellingFocusCategory {
+	return errorTypeFocus[errorType]
+}
+
 // SentenceItem represents a sentence for sentence dictation mode
 type SentenceItem struct {
 	Audio       *WordAudio      `json:"audio,omitempty"`
@@ -184,15 +219,51 @@ type WordSet struct {
 
 // WordTestResult represents detailed information about a word in a test
 type WordTestResult struct {
-	Word           string   `json:"word"`
-	FinalAnswer    string   `json:"finalAnswer"`
-	UserAnswers    []string `json:"userAnswers"`
-	ErrorTypes     []string `json:"errorTypes,omitempty"`
-	Attempts       int      `json:"attempts"`
-	TimeSpent      int      `json:"timeSpent"`
-	HintsUsed      int      `json:"hintsUsed,omitempty"`
-	AudioPlayCount int      `json:"audioPlayCount,omitempty"`
-	Correct        bool     `json:"correct"`
+	Sentence       *SentenceScore `json:"sentence,omitempty"` // Word-level scoring when the item is a sentence
+	Word           string         `json:"word"`
+	FinalAnswer    string         `json:"finalAnswer"`
+	UserAnswers    []string       `json:"userAnswers"`
+	ErrorTypes     []string       `json:"errorTypes,omitempty"`
+	Attempts       int            `json:"attempts"`
+	TimeSpent      int            `json:"timeSpent"`
+	HintsUsed      int            `json:"hintsUsed,omitempty"`
+	AudioPlayCount int            `json:"audioPlayCount,omitempty"`
+	Correct        bool           `json:"correct"`
+}
+
+// Sentence word statuses
+const (
+	SentenceWordCorrect    = "correct"    // Spelled right and in order
+	SentenceWordMisspelled = "misspelled" // Within the close-enough distance of the target word
+	SentenceWordMisplaced  = "misplaced"  // Spelled right but out of order
+	SentenceWordMissing    = "missing"    // Not in the answer
+	SentenceWordExtra      = "extra"      // In the answer but not in the sentence
+)
+
+// SentenceWordResult is one word of a scored sentence
+type SentenceWordResult struct {
+	Word             string   `json:"word"`               // Target word, or the child's word when extra
+	UserWord         string   `json:"userWord,omitempty"` // What the child wrote when misspelled
+	Status           string   `json:"status"`
+	ErrorTypes       []string `json:"errorTypes,omitempty"`
+	ExpectedPosition int      `json:"expectedPosition"` // -1 for extra words
+	UserPosition     int      `json:"userPosition"`     // -1 for missing words
+	Credit           float64  `json:"credit"`           // 1 for correct, 0.5 for misspelled or misplaced
+	Focus            bool     `json:"focus,omitempty"`  // One of the sentence's focus words
+}
+
+// SentenceScore is the word-level grading of a sentence dictation answer
+type SentenceScore struct {
+	FocusErrorTypes []string             `json:"focusErrorTypes,omitempty"` // Error types detected on focus words
+	Words           []SentenceWordResult `json:"words"`                     // Target words in order, then extra words
+	CorrectCount    int                  `json:"correctCount"`
+	CloseCount      int                  `json:"closeCount"` // Misspelled or misplaced words
+	TotalExpected   int                  `json:"totalExpected"`
+	FocusCorrect    int                  `json:"focusCorrect"`
+	FocusTotal      int                  `json:"focusTotal"`
+	Score           float64              `json:"score"`         // Partial credit, 0-100
+	OrderAccuracy   float64              `json:"orderAccuracy"` // Share of words in the right order, 0-1
+	FullyCorrect    bool                 `json:"fullyCorrect"`
 }
 
 // TestResult represents the result of a spelling test
@@ -203,6 +274,7 @@ type TestResult struct {
 	WordSetID      string           `json:"wordSetId"`
 	UserID         string           `json:"userId"`
 	Mode           string           `json:"mode"`
+	IdempotencyKey string           `json:"idempotencyKey,omitempty"` // Client-generated key for replay-safe saves
 	IncorrectWords []string         `json:"incorrectWords,omitempty"`
 	Words          []WordTestResult `json:"words"`
 	Score          float64          `json:"score"`
@@ -232,6 +304,8 @@ type WordInput struct {
 // CreateWordSetRequest represents the request to create a word set
 type CreateWordSetRequest struct {
 	TestConfiguration *map[string]interface{} `json:"testConfiguration,omitempty"`
+	TargetGrade       *GradeLevel             `json:"targetGrade,omitempty" binding:"omitempty,oneof=1-2 3-4 5-7"`
+	Difficulty        *DifficultyLevel        `json:"difficulty,omitempty" binding:"omitempty,oneof=beginner intermediate advanced"`
 	Name              string                  `json:"name" binding:"required"`
 	Language          string                  `json:"language" binding:"required"`
 	Words             []WordInput             `json:"words" binding:"required"`
@@ -240,15 +314,25 @@ type CreateWordSetRequest struct {
 // UpdateWordSetRequest represents the request to update a word set
 type UpdateWordSetRequest struct {
 	TestConfiguration *map[string]interface{} `json:"testConfiguration,omitempty"`
+	TargetGrade       *GradeLevel             `json:"targetGrade,omitempty" binding:"omitempty,oneof=1-2 3-4 5-7"`
+	Difficulty        *DifficultyLevel        `json:"difficulty,omitempty" binding:"omitempty,oneof=beginner intermediate advanced"`
 	Name              string                  `json:"name" binding:"required"`
 	Language          string                  `json:"language" binding:"required"`
 	Words             []WordInput             `json:"words" binding:"required"`
 }
 
+// SentenceScoreRequest is a sentence dictation answer to score against a word set sentence
+type SentenceScoreRequest struct {
+	Sentence string `json:"sentence" binding:"required"` // Target sentence
+	Answer   string `json:"answer"`                      // What the child wrote
+}
+
 // SaveResultRequest represents the request to save a test result
 type SaveResultRequest struct {
+	CompletedAt    *time.Time       `json:"completedAt,omitempty"` // When the test was taken; defaults to now
 	WordSetID      string           `json:"wordSetId" binding:"required"`
 	Mode           string           `json:"mode" binding:"required,oneof=letterTiles wordBank keyboard missingLetters flashcard lookCoverWrite translation listeningTranslation"`
+	IdempotencyKey string           `json:"idempotencyKey,omitempty" binding:"omitempty,max=100"` // Replays with the same key return the saved result
 	IncorrectWords []string         `json:"incorrectWords,omitempty"`
 	Words          []WordTestResult `json:"words"`
 	Score          float64          `json:"score" binding:"required"`
@@ -257,12 +341,38 @@ type SaveResultRequest struct {
 	TimeSpent      int              `json:"timeSpent"`
 }
 
+// ResultFilter narrows a test result listing. Empty fields match everything.
+type ResultFilter struct {
+	UserID    string     // Results of one user
+	FamilyID  string     // Results of every member of a family
+	WordSetID string     // Results for one word set
+	Mode      string     // Results in one test mode
+	From      *time.Time // Completed at or after
+	To        *time.Time // Completed before
+	MinScore  *float64   // Score at least
+}
+
+// WordSetFilter narrows a word set listing. Empty fields match everything.
+type WordSetFilter struct {
+	Search     string          // Case-insensitive substring of the name
+	Language   string          // Word set language
+	Grade      GradeLevel      // Target grade
+	Difficulty DifficultyLevel // Difficulty level
+}
+
+// PageRequest selects one page of a listing, newest first
+type PageRequest struct {
+	Cursor string // NextCursor of the previous page; empty for the first page
+	Limit  int    // Page size; 0 returns everything after the cursor
+}
+
 // APIResponse represents a standard API response
 type APIResponse struct {
-	Data    interface{} `json:"data,omitempty"`
-	Message string      `json:"message,omitempty"`
-	Error   string      `json:"error,omitempty"`
-	Details string      `json:"details,omitempty"` // Technical details for debugging
+	Data       interface{} `json:"data,omitempty"`
+	Message    string      `json:"message,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	Details    string      `json:"details,omitempty"`    // Technical details for debugging
+	NextCursor string      `json:"nextCursor,omitempty"` // Cursor for the next page of a paginated listing
 }
 
 // Family represents a family group
@@ -381,3 +491,502 @@ type SaveResultResponse struct {
 	TestResult *TestResult `json:"testResult"`
 	XP         *XPInfo     `json:"xp"`
 }
+
+// FamilyAPIKey is a family-scoped credential for non-interactive clients (e.g. the MCP server)
+// The plaintext key is never stored; only its hash is persisted
+type FamilyAPIKey struct {
+	CreatedAt  time.Time  `json:"createdAt" db:"created_at"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty" db:"last_used_at"`
+	ID         string     `json:"id" db:"id"`
+	FamilyID   string     `json:"familyId" db:"family_id"`
+	Name       string     `json:"name" db:"name"`
+	KeyPrefix  string     `json:"keyPrefix" db:"key_prefix"`
+	KeyHash    string     `json:"-" db:"key_hash"`
+	CreatedBy  string     `json:"createdBy" db:"created_by"`
+}
+
+// CreateFamilyAPIKeyRequest represents a request to create a family API key
+type CreateFamilyAPIKeyRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=100"`
+}
+
+// CreateFamilyAPIKeyResponse is returned once when a key is created and includes the plaintext key
+type CreateFamilyAPIKeyResponse struct {
+	APIKey *FamilyAPIKey `json:"apiKey"`
+	Key    string        `json:"key"`
+}
+
+// Webhook delivery statuses
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// Webhook is a parent-registered URL that receives signed family event payloads
+type Webhook struct {
+	CreatedAt   time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt   time.Time `json:"updatedAt" db:"updated_at"`
+	Description *string   `json:"description,omitempty" db:"description"`
+	ID          string    `json:"id" db:"id"`
+	FamilyID    string    `json:"familyId" db:"family_id"`
+	URL         string    `json:"url" db:"url"`
+	Secret      string    `json:"-" db:"secret"`
+	CreatedBy   string    `json:"createdBy" db:"created_by"`
+	EventTypes  []string  `json:"eventTypes" db:"event_types"` // Empty means all event types
+	IsActive    bool      `json:"isActive" db:"is_active"`
+}
+
+// WebhookDelivery is one outbox entry / delivery log row for a webhook
+type WebhookDelivery struct {
+	CreatedAt      time.Time       `json:"createdAt" db:"created_at"`
+	NextAttemptAt  time.Time       `json:"nextAttemptAt" db:"next_attempt_at"`
+	LastAttemptAt  *time.Time      `json:"lastAttemptAt,omitempty" db:"last_attempt_at"`
+	DeliveredAt    *time.Time      `json:"deliveredAt,omitempty" db:"delivered_at"`
+	ResponseStatus *int            `json:"responseStatus,omitempty" db:"response_status"`
+	LastError      *string         `json:"lastError,omitempty" db:"last_error"`
+	ID             string          `json:"id" db:"id"`
+	WebhookID      string          `json:"webhookId" db:"webhook_id"`
+	EventID        string          `json:"eventId" db:"event_id"`
+	EventType      string          `json:"eventType" db:"event_type"`
+	Status         string          `json:"status" db:"status"`
+	URL            string          `json:"-" db:"-"` // Populated when claimed for dispatch
+	Secret         string          `json:"-" db:"-"` // Populated when claimed for dispatch
+	Payload        json.RawMessage `json:"payload" db:"payload"`
+	Attempts       int             `json:"attempts" db:"attempts"`
+}
+
+// CreateWebhookRequest represents a request to register a webhook
+type CreateWebhookRequest struct {
+	Description *string  `json:"description,omitempty" binding:"omitempty,max=200"`
+	URL         string   `json:"url" binding:"required,url"`
+	EventTypes  []string `json:"eventTypes,omitempty"` // Empty subscribes to all event types
+}
+
+// CreateWebhookResponse is returned once when a webhook is created and includes the signing secret
+type CreateWebhookResponse struct {
+	Webhook *Webhook `json:"webhook"`
+	Secret  string   `json:"secret"`
+}
+
+// Digest languages
+const (
+	DigestLanguageNorwegian = "no"
+	DigestLanguageEnglish   = "en"
+)
+
+// DigestPreferences holds a parent's weekly email digest settings
+type DigestPreferences struct {
+	UpdatedAt  time.Time  `json:"updatedAt" db:"updated_at"`
+	LastSentAt *time.Time `json:"lastSentAt,omitempty" db:"last_sent_at"`
+	UserID     string     `json:"userId" db:"user_id"`
+	Language   string     `json:"language" db:"language"` // "no" or "en"
+	Enabled    bool       `json:"enabled" db:"enabled"`
+}
+
+// UpdateDigestPreferencesRequest represents a request to change digest settings
+type UpdateDigestPreferencesRequest struct {
+	Enabled  *bool   `json:"enabled,omitempty"`
+	Language *string `json:"language,omitempty" binding:"omitempty,oneof=no en"`
+}
+
+// DigestRecipient is an opted-in parent who is due a weekly digest
+type DigestRecipient struct {
+	LastSentAt  *time.Time `json:"lastSentAt,omitempty"`
+	UserID      string     `json:"userId"`
+	Email       string     `json:"email"`
+	DisplayName string     `json:"displayName"`
+	FamilyID    string     `json:"familyId"`
+	Language    string     `json:"language"`
+}
+
+// DigestPreview is a rendered weekly digest, shown to parents before they opt in
+type DigestPreview struct {
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html"`
+}
+
+// ReminderFrequency controls how often a child is reminded about an assignment
+type ReminderFrequency string
+
+const (
+	ReminderNone     ReminderFrequency = "none"
+	ReminderDaily    ReminderFrequency = "daily"
+	ReminderWeekdays ReminderFrequency = "weekdays" // Monday to Friday
+)
+
+// Assignment completion states
+const (
+	AssignmentNotStarted = "notStarted"
+	AssignmentInProgress = "inProgress"
+	AssignmentCompleted  = "completed"
+	AssignmentOverdue    = "overdue"
+)
+
+// PracticeGoal defines when an assignment counts as done, e.g. 3 keyboard runs with ≥90%
+type PracticeGoal struct {
+	Mode     string  `json:"mode,omitempty"`                   // Empty counts any test mode
+	Runs     int     `json:"runs" binding:"min=1,max=50"`      // Qualifying tests required
+	MinScore float64 `json:"minScore" binding:"min=0,max=100"` // Minimum score for a test to qualify
+}
+
+// AssignmentSchedule holds the optional due date, goal and reminders for an assignment
+type AssignmentSchedule struct {
+	DueAt             *time.Time        `json:"dueAt,omitempty"`
+	Goal              *PracticeGoal     `json:"goal,omitempty"` // Nil means one test of any mode and score
+	ReminderFrequency ReminderFrequency `json:"reminderFrequency,omitempty" binding:"omitempty,oneof=none daily weekdays"`
+	ReminderHour      int               `json:"reminderHour" binding:"min=0,max=23"` // Local hour (Europe/Oslo)
+}
+
+// WordSetAssignment is a word set assigned to a child with its schedule
+type WordSetAssignment struct {
+	AssignedAt     time.Time  `json:"assignedAt" db:"assigned_at"`
+	LastRemindedAt *time.Time `json:"lastRemindedAt,omitempty" db:"last_reminded_at"`
+	WordSetID      string     `json:"wordSetId" db:"wordset_id"`
+	WordSetName    string     `json:"wordSetName" db:"-"`
+	UserID         string     `json:"userId" db:"user_id"`
+	FamilyID       string     `json:"familyId" db:"-"`
+	AssignedBy     string     `json:"assignedBy" db:"assigned_by"`
+	AssignmentSchedule
+}
+
+// AssignmentProgress reports a child's completion state for one assignment
+type AssignmentProgress struct {
+	LastPracticedAt *time.Time         `json:"lastPracticedAt,omitempty"`
+	Assignment      *WordSetAssignment `json:"assignment"`
+	Status          string             `json:"status"` // notStarted, inProgress, completed or overdue
+	Goal            PracticeGoal       `json:"goal"`   // Effective goal (defaults applied)
+	QualifyingRuns  int                `json:"qualifyingRuns"`
+	TotalRuns       int                `json:"totalRuns"`
+	BestScore       float64            `json:"bestScore"`
+	Completed       bool               `json:"completed"`
+}
+
+// Notification is an entry in a user's in-app inbox. Text is rendered by the
+// client from MessageKey and Params in the user's language.
+type Notification struct {
+	CreatedAt     time.Time      `json:"createdAt" db:"created_at"`
+	ReadAt        *time.Time     `json:"readAt,omitempty" db:"read_at"`
+	Params        map[string]any `json:"params" db:"params"`
+	ID            string         `json:"id" db:"id"`
+	UserID        string         `json:"userId" db:"user_id"` // Recipient
+	FamilyID      string         `json:"familyId" db:"family_id"`
+	SubjectUserID string         `json:"subjectUserId,omitempty" db:"subject_user_id"` // The user the notification is about
+	Kind          string         `json:"kind" db:"kind"`                               // Domain event type that produced it
+	MessageKey    string         `json:"messageKey" db:"message_key"`
+}
+
+// NotificationInbox is a page of a user's notifications with the total unread count
+type NotificationInbox struct {
+	Notifications []Notification `json:"notifications"`
+	UnreadCount   int            `json:"unreadCount"`
+}
+
+// NotificationActionRequest selects notifications to mark as read or dismiss
+type NotificationActionRequest struct {
+	IDs []string `json:"ids,omitempty" binding:"omitempty,max=100"`
+	All bool     `json:"all,omitempty"` // Apply to every notification in the inbox
+}
+
+// LiveActivityRequest reports test progress to the family's live dashboards
+type LiveActivityRequest struct {
+	Type       string `json:"type" binding:"required,oneof=test.started test.word_answered"`
+	WordSetID  string `json:"wordSetId" binding:"required"`
+	Mode       string `json:"mode,omitempty"`
+	Word       string `json:"word,omitempty" binding:"max=100"` // The word just answered (test.word_answered)
+	WordIndex  int    `json:"wordIndex" binding:"min=0"`
+	TotalWords int    `json:"totalWords" binding:"min=0"`
+	Attempt    int    `json:"attempt,omitempty" binding:"min=0"`
+	Correct    bool   `json:"correct,omitempty"`
+}
+
+// Test session statuses
+const (
+	TestSessionActive    = "active"
+	TestSessionCompleted = "completed"
+)
+
+// SessionWord is one word in a server-side test session, with the answers given so far
+type SessionWord struct {
+	StartedAt      *time.Time `json:"startedAt,omitempty"`   // When the current timing interval began
+	CompletedAt    *time.Time `json:"completedAt,omitempty"` // Set once answered correctly or out of attempts
+	Word           string     `json:"word"`
+	ExpectedAnswer string     `json:"expectedAnswer,omitempty"` // Differs from Word in translation modes; hidden from clients until the word is complete
+	Direction      string     `json:"direction,omitempty"`      // toTarget or toSource in translation modes
+	Answers        []string   `json:"answers"`
+	ErrorTypes     []string   `json:"errorTypes,omitempty"`
+	TimeSpentMs    int64      `json:"timeSpentMs"` // Active time measured by the server
+	AudioPlayCount int        `json:"audioPlayCount,omitempty"`
+	Correct        bool       `json:"correct"`
+}
+
+// TestSession is a spelling test in progress, stored so it can be resumed
+type TestSession struct {
+	StartedAt      time.Time     `json:"startedAt"`
+	LastActivityAt time.Time     `json:"lastActivityAt"`
+	ExpiresAt      time.Time     `json:"expiresAt"`
+	CompletedAt    *time.Time    `json:"completedAt,omitempty"`
+	ResultID       *string       `json:"resultId,omitempty"`
+	ID             string        `json:"id"`
+	UserID         string        `json:"userId"`
+	FamilyID       string        `json:"familyId"`
+	WordSetID      string        `json:"wordSetId"`
+	Mode           string        `json:"mode"`
+	Status         string        `json:"status"`
+	Words          []SessionWord `json:"words"`
+	CurrentIndex   int           `json:"currentIndex"` // Index of the next word to answer; len(Words) when all are done
+	MaxAttempts    int           `json:"maxAttempts"`
+	Version        int           `json:"-"` // Optimistic concurrency token
+}
+
+// StartTestSessionRequest starts a server-side test session
+type StartTestSessionRequest struct {
+	WordSetID string `json:"wordSetId" binding:"required"`
+	Mode      string `json:"mode,omitempty"` // Defaults to the word set's configured mode, then keyboard
+}
+
+// RecordAnswerRequest records one attempt at the session's current word
+type RecordAnswerRequest struct {
+	Answer         string   `json:"answer" binding:"max=500"`
+	ErrorTypes     []string `json:"errorTypes,omitempty" binding:"max=10"`
+	AudioPlayCount int      `json:"audioPlayCount,omitempty" binding:"min=0"`
+}
+
+// RecordAnswerResponse tells the client how an attempt was judged
+type RecordAnswerResponse struct {
+	Session        *TestSession `json:"session"`
+	ExpectedAnswer string       `json:"expectedAnswer,omitempty"` // Revealed once the word is complete
+	Attempts       int          `json:"attempts"`
+	Correct        bool         `json:"correct"`
+	WordComplete   bool         `json:"wordComplete"`
+	Finished       bool         `json:"finished"` // All words are complete; the session can be finalized
+}
+
+// Result sync statuses
+const (
+	SyncStatusCreated   = "created"
+	SyncStatusDuplicate = "duplicate"
+	SyncStatusFailed    = "failed"
+)
+
+// SyncResultsRequest uploads test results queued while the client was offline
+type SyncResultsRequest struct {
+	Results []SaveResultRequest `json:"results" binding:"required,min=1,max=100,dive"`
+}
+
+// SyncResultItem is the outcome of one queued result
+type SyncResultItem struct {
+	Result         *TestResult `json:"result,omitempty"`
+	XP             *XPInfo     `json:"xp,omitempty"`
+	IdempotencyKey string      `json:"idempotencyKey"`
+	Status         string      `json:"status"` // created, duplicate or failed
+	Error          string      `json:"error,omitempty"`
+}
+
+// SyncResultsResponse lists sync outcomes in chronological order
+type SyncResultsResponse struct {
+	Results   []SyncResultItem `json:"results"`
+	Created   int              `json:"created"`
+	Duplicate int              `json:"duplicate"`
+	Failed    int              `json:"failed"`
+}
+
+// TestPlanRequest asks for an adaptive test plan for a word set
+type TestPlanRequest struct {
+	WordSetID string `json:"wordSetId" binding:"required"`
+	ChildID   string `json:"childId,omitempty"`                          // Parents may plan for a child; defaults to the caller
+	MaxWords  int    `json:"maxWords,omitempty" binding:"min=0,max=100"` // Zero plans every word
+}
+
+// PlannedWord is one word of a test plan with the mode to practice it in
+type PlannedWord struct {
+	Word         string   `json:"word"`
+	Mode         string   `json:"mode"`
+	Reason       string   `json:"reason"` // struggling, new, learning, mastered, ageLimited or configured
+	ErrorTypes   []string `json:"errorTypes,omitempty"`
+	RecentMisses int      `json:"recentMisses"`
+}
+
+// TestPlan is an ordered list of words with a mode for each
+type TestPlan struct {
+	Age         *int          `json:"age,omitempty"`
+	UserID      string        `json:"userId"`
+	WordSetID   string        `json:"wordSetId"`
+	Words       []PlannedWord `json:"words"`
+	MaxAttempts int           `json:"maxAttempts"`
+}
+
+// RecommendationReason explains one factor in a word set recommendation
+type RecommendationReason struct {
+	Params map[string]any `json:"params,omitempty"`
+	Code   string         `json:"code"`   // e.g. gradeMatch, spellingFocus, dueForReview
+	Points int            `json:"points"` // Contribution to the score, may be negative
+}
+
+// WordSetRecommendation is a ranked word set suggestion for a child
+type WordSetRecommendation struct {
+	LastPracticedAt *time.Time              `json:"lastPracticedAt,omitempty"`
+	TargetGrade     *GradeLevel             `json:"targetGrade,omitempty"`
+	Difficulty      *DifficultyLevel        `json:"difficulty,omitempty"`
+	BestScore       *float64                `json:"bestScore,omitempty"`
+	WordSetID       string                  `json:"wordSetId"`
+	Name            string                  `json:"name"`
+	SpellingFocus   []SpellingFocusCategory `json:"spellingFocus,omitempty"`
+	Reasons         []RecommendationReason  `json:"reasons"`
+	Score           int                     `json:"score"`
+	WordCount       int                     `json:"wordCount"`
+	IsGlobal        bool                    `json:"isGlobal"`
+}
+
+// Analytics bucket sizes for error type trends
+const (
+	AnalyticsBucketDay   = "day"
+	AnalyticsBucketWeek  = "week"
+	AnalyticsBucketMonth = "month"
+)
+
+// MissedWord is a word a child got wrong in a time range
+type MissedWord struct {
+	LastMissedAt time.Time `json:"lastMissedAt"`
+	Word         string    `json:"word"`
+	ErrorTypes   []string  `json:"errorTypes"` // Distinct error types detected on misses
+	Misses       int       `json:"misses"`     // Tests where the word was answered wrong
+	Tests        int       `json:"tests"`      // Tests that included the word
+}
+
+// ErrorTypeCount is how often an error type occurred in one time bucket
+type ErrorTypeCount struct {
+	BucketStart time.Time `json:"bucketStart"`
+	ErrorType   string    `json:"errorType"`
+	Count       int       `json:"count"`
+}
+
+// WordAttemptStats summarizes how many attempts a child needs for a word
+type WordAttemptStats struct {
+	Word            string  `json:"word"`
+	AvgAttempts     float64 `json:"avgAttempts"`
+	Tests           int     `json:"tests"`
+	FirstTryCorrect int     `json:"firstTryCorrect"` // Tests answered correctly on the first attempt
+}
+
+// RegressedWord is a word a child had mastered earlier and then missed in a time range.
+// A word counts as mastered once it has been answered right on the first attempt
+// in enough earlier tests.
+type RegressedWord struct {
+	LastMasteredAt time.Time `json:"lastMasteredAt"` // Last first-try correct answer before the misses
+	LastMissedAt   time.Time `json:"lastMissedAt"`
+	Word           string    `json:"word"`
+	ErrorTypes     []string  `json:"errorTypes"`
+	Misses         int       `json:"misses"`
+	PriorCorrect   int       `json:"priorCorrect"` // First-try correct answers before the first miss in range
+}
+
+// Practice list limits
+const (
+	PracticeListDefaultWindowDays = 14
+	PracticeListDefaultMaxWords   = 15
+)
+
+// Practice word sources
+const (
+	PracticeWordSourceMissed  = "missed"  // Missed by the child in the window
+	PracticeWordSourceCurated = "curated" // Padding from a curated set with the same spelling focus
+)
+
+// PracticeList links a child to their auto-generated practice word set
+// ("øveliste") and the settings used to rebuild it
+type PracticeList struct {
+	RefreshedAt    time.Time `json:"refreshedAt"`
+	CreatedAt      time.Time `json:"createdAt"`
+	ChildID        string    `json:"childId"`
+	FamilyID       string    `json:"familyId"`
+	WordSetID      string    `json:"wordSetId"`
+	CreatedBy      string    `json:"createdBy"`
+	WindowDays     int       `json:"windowDays"`
+	MaxWords       int       `json:"maxWords"`
+	IncludeCurated bool      `json:"includeCurated"`
+}
+
+// PracticeListRequest creates or rebuilds a child's practice list
+type PracticeListRequest struct {
+	WindowDays     int  `json:"windowDays,omitempty" binding:"omitempty,min=1,max=90"` // Days of results to collect missed words from (default 14)
+	MaxWords       int  `json:"maxWords,omitempty" binding:"omitempty,min=1,max=30"`   // Default 15
+	IncludeCurated bool `json:"includeCurated"`                                        // Pad with curated words sharing the child's weak spelling focus
+}
+
+// PracticeWord explains why a word is on a practice list
+type PracticeWord struct {
+	LastMissedAt *time.Time            `json:"lastMissedAt,omitempty"`
+	Word         string                `json:"word"`
+	Source       string                `json:"source"`              // missed or curated
+	WordSetID    string                `json:"wordSetId,omitempty"` // Set the word was missed in or padded from
+	Category     SpellingFocusCategory `json:"category,omitempty"`  // Spelling focus that matched a curated word
+	ErrorTypes   []string              `json:"errorTypes,omitempty"`
+	Misses       int                   `json:"misses,omitempty"`
+}
+
+// PracticeListResponse is a practice list with its word set and word explanations
+type PracticeListResponse struct {
+	WordSet      *WordSet       `json:"wordSet"`
+	PracticeList PracticeList   `json:"practiceList"`
+	Words        []PracticeWord `json:"words"`
+}
+
+// Challenge seeds are kept below 2^53 so they survive a round trip through JSON numbers
+const MaxChallengeSeed int64 = 1<<53 - 1
+
+// LetterTile is one tile offered in the letterTiles mode
+type LetterTile struct {
+	Letter       string `json:"letter"`
+	IsDistractor bool   `json:"isDistractor"`
+}
+
+// WordBankItem is one word offered in the wordBank mode
+type WordBankItem struct {
+	Word         string `json:"word"`
+	IsDistractor bool   `json:"isDistractor"`
+}
+
+// MissingLettersGap is a word with the letters to fill in blanked out
+type MissingLettersGap struct {
+	BlankedWord    string `json:"blankedWord"`    // e.g. "ma__" for "mann"
+	MissingLetters string `json:"missingLetters"` // e.g. "nn"
+	ChallengeType  string `json:"challengeType"`  // doubleConsonant, silentH, skjSound, diphthong, vowel, ...
+	Positions      []int  `json:"positions"`      // Rune indexes of the blanks
+}
+
+// WordChallenge is the generated scaffolding for one word or sentence in a word set.
+// Single words get letter tiles and a missing-letters gap, sentences get a word bank.
+type WordChallenge struct {
+	MissingLetters *MissingLettersGap      `json:"missingLetters,omitempty"`
+	Word           string                  `json:"word"`
+	Focus          []SpellingFocusCategory `json:"focus,omitempty"` // Spelling focus that picked the distractors
+	LetterTiles    []LetterTile            `json:"letterTiles,omitempty"`
+	WordBank       []WordBankItem          `json:"wordBank,omitempty"`
+}
+
+// ChallengeSet is the generated scaffolding for a whole word set. The same seed
+// always gives the same tiles, word banks and gaps for an unchanged word set.
+type ChallengeSet struct {
+	LockedAt  *time.Time      `json:"lockedAt,omitempty"`
+	WordSetID string          `json:"wordSetId"`
+	ChildID   string          `json:"childId,omitempty"`
+	Words     []WordChallenge `json:"words"`
+	Seed      int64           `json:"seed"`
+	Locked    bool            `json:"locked"` // Seed was locked by a parent for this child
+}
+
+// ChallengeLock pins the challenge seed a child sees for a word set
+type ChallengeLock struct {
+	LockedAt  time.Time `json:"lockedAt"`
+	WordSetID string    `json:"wordSetId"`
+	ChildID   string    `json:"childId"`
+	LockedBy  string    `json:"lockedBy"`
+	Seed      int64     `json:"seed"`
+}
+
+// ChallengeLockRequest locks a previewed challenge seed for a child
+type ChallengeLockRequest struct {
+	Seed *int64 `json:"seed" binding:"required,min=0,max=9007199254740991"`
+}
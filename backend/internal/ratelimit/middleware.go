@@ -0,0 +1,71 @@
+package ratelimit
+
+import (
+	"cmp"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/starefossen/diktator/backend/internal/metrics"
+	"github.com/starefossen/diktator/backend/internal/models"
+)
+
+// KeyFunc identifies the caller of a request
+type KeyFunc func(c *gin.Context) string
+
+// ByIP keys requests by client IP, for public routes
+func ByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// ByUser keys requests by the authenticated identity, falling back to the
+// client IP. It must run after the auth middleware.
+func ByUser(c *gin.Context) string {
+	if id := c.GetString("authIdentityID"); id != "" {
+		return "user:" + id
+	}
+	return ByIP(c)
+}
+
+// Policy is the limit applied to one route group
+type Policy struct {
+	Group string  // Route group name, used in bucket keys and metrics
+	Key   KeyFunc // Identifies the caller
+	Limit Limit
+}
+
+// Middleware rejects requests over the policy's limit with 429 Too Many
+// Requests and a Retry-After header. When the store fails, requests are let
+// through so an outage of a shared store cannot take the API down.
+// A nil logger uses slog.Default().
+func Middleware(store Store, policy Policy, logger *slog.Logger) gin.HandlerFunc {
+	logger = cmp.Or(logger, slog.Default())
+	if policy.Limit.Unlimited() {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		result, err := store.Allow(ctx, policy.Group+":"+policy.Key(c), policy.Limit)
+		if err != nil {
+			logger.WarnContext(ctx, "Rate limit store failed, allowing request", "group", policy.Group, "error", err)
+			c.Next()
+			return
+		}
+		if result.Allowed {
+			c.Next()
+			return
+		}
+
+		metrics.RecordRateLimited(policy.Group)
+		logger.DebugContext(ctx, "Request rate limited", "group", policy.Group, "retry_after", result.RetryAfter)
+		seconds := max(1, int(math.Ceil(result.RetryAfter.Seconds())))
+		c.Header("Retry-After", strconv.Itoa(seconds))
+		c.JSON(http.StatusTooManyRequests, models.APIResponse{
+			Error: "Too many requests, please try again later",
+		})
+		c.Abort()
+	}
+}
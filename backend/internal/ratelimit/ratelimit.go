@@ -0,0 +1,121 @@
+// Package ratelimit limits how often a caller may hit a group of routes. Each
+// caller gets a token bucket per route group; public routes are keyed by
+// client IP and authenticated routes by user. Buckets live in a Store, which
+// is in memory today and can be backed by a shared store when the API runs
+// with several replicas.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit is a token bucket: Burst requests may be made at once, and tokens
+// refill at Rate per second. A zero Rate means unlimited.
+type Limit struct {
+	Rate  float64 // Tokens added per second
+	Burst int     // Bucket capacity
+}
+
+// PerMinute returns a limit allowing n requests per minute with the given burst
+func PerMinute(n, burst int) Limit {
+	return Limit{Rate: float64(n) / 60, Burst: burst}
+}
+
+// Unlimited reports whether the limit lets every request through
+func (l Limit) Unlimited() bool {
+	return l.Rate <= 0 || l.Burst <= 0
+}
+
+// Result is the outcome of taking a token
+type Result struct {
+	Allowed    bool
+	Remaining  int           // Whole tokens left after this request
+	RetryAfter time.Duration // Time until the next token when not allowed
+}
+
+// Store keeps token buckets by key
+type Store interface {
+	// Allow takes a token from the bucket for key, creating a full bucket
+	// when there is none
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// sweepInterval is how often the memory store drops idle buckets
+const sweepInterval = time.Minute
+
+// MemoryStore keeps buckets in process memory. Buckets that have refilled
+// completely are dropped, since a new bucket would be identical.
+type MemoryStore struct {
+	buckets   map[string]*bucket
+	now       func() time.Time
+	lastSweep time.Time
+	mu        sync.Mutex
+}
+
+type bucket struct {
+	updated time.Time
+	limit   Limit
+	tokens  float64
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]*bucket{}, now: time.Now}
+}
+
+// Allow implements Store
+func (s *MemoryStore) Allow(_ context.Context, key string, limit Limit) (Result, error) {
+	if limit.Unlimited() {
+		return Result{Allowed: true, Remaining: math.MaxInt}, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst)}
+		s.buckets[key] = b
+	} else {
+		b.refill(now, limit)
+	}
+	b.updated, b.limit = now, limit
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second))
+		return Result{RetryAfter: wait}, nil
+	}
+	b.tokens--
+	return Result{Allowed: true, Remaining: int(b.tokens)}, nil
+}
+
+// Len returns the number of buckets held
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.buckets)
+}
+
+func (b *bucket) refill(now time.Time, limit Limit) {
+	b.tokens = min(float64(limit.Burst), b.tokens+now.Sub(b.updated).Seconds()*limit.Rate)
+}
+
+// sweep drops full buckets, at most once per sweepInterval
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		b.refill(now, b.limit)
+		b.updated = now
+		if b.tokens >= float64(b.limit.Burst) {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestStore returns a memory store with a clock advanced by the returned function
+func newTestStore() (*MemoryStore, func(time.Duration)) {
+	now := time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC)
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+	return s, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestMemoryStore_Allow(t *testing.T) {
+	s, advance := newTestStore()
+	limit := PerMinute(60, 3) // One token per second
+
+	for i := range 3 {
+		r, err := s.Allow(t.Context(), "k", limit)
+		require.NoError(t, err)
+		assert.True(t, r.Allowed, "request %d is within the burst", i+1)
+		assert.Equal(t, 2-i, r.Remaining)
+	}
+
+	r, err := s.Allow(t.Context(), "k", limit)
+	require.NoError(t, err)
+	assert.False(t, r.Allowed)
+	assert.Equal(t, time.Second, r.RetryAfter)
+
+	other, err := s.Allow(t.Context(), "other", limit)
+	require.NoError(t, err)
+	assert.True(t, other.Allowed, "buckets are per key")
+
+	advance(500 * time.Millisecond)
+	r, _ = s.Allow(t.Context(), "k", limit)
+	assert.False(t, r.Allowed)
+	assert.Equal(t, 500*time.Millisecond, r.RetryAfter)
+
+	advance(500 * time.Millisecond)
+	r, _ = s.Allow(t.Context(), "k", limit)
+	assert.True(t, r.Allowed, "a token refills after a second")
+
+	advance(time.Hour)
+	r, _ = s.Allow(t.Context(), "k", limit)
+	assert.Equal(t, 2, r.Remaining, "refills stop at the burst")
+}
+
+func TestMemoryStore_SweepsFullBuckets(t *testing.T) {
+	s, advance := newTestStore()
+	limit := PerMinute(60, 10)
+
+	_, _ = s.Allow(t.Context(), "idle", limit)
+	advance(5 * time.Second)
+	for range 10 {
+		_, _ = s.Allow(t.Context(), "busy", limit)
+	}
+	require.Equal(t, 2, s.Len())
+
+	advance(sweepInterval)
+	_, _ = s.Allow(t.Context(), "busy", limit)
+	assert.Equal(t, 1, s.Len(), "the refilled idle bucket is dropped")
+}
+
+type failingStore struct{}
+
+func (failingStore) Allow(context.Context, string, Limit) (Result, error) {
+	return Result{}, errors.New("store unavailable")
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(store Store, policy Policy) *gin.Engine {
+		r := gin.New()
+		r.Use(func(c *gin.Context) {
+			if id := c.GetHeader("X-Test-User"); id != "" {
+				c.Set("authIdentityID", id)
+			}
+		})
+		r.Use(Middleware(store, policy, nil))
+		r.GET("/", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+		return r
+	}
+	get := func(r *gin.Engine, ip, user string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = ip + ":1234"
+		if user != "" {
+			req.Header.Set("X-Test-User", user)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("limits by IP with Retry-After", func(t *testing.T) {
+		store, _ := newTestStore()
+		r := newRouter(store, Policy{Group: "dictionary", Key: ByIP, Limit: PerMinute(6, 2)})
+
+		assert.Equal(t, http.StatusNoContent, get(r, "192.0.2.1", "").Code)
+		assert.Equal(t, http.StatusNoContent, get(r, "192.0.2.1", "").Code)
+		w := get(r, "192.0.2.1", "")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "10", w.Header().Get("Retry-After"))
+		assert.Contains(t, w.Body.String(), "Too many requests")
+
+		assert.Equal(t, http.StatusNoContent, get(r, "192.0.2.2", "").Code, "other IPs have their own bucket")
+	})
+
+	t.Run("limits by user across IPs", func(t *testing.T) {
+		store, _ := newTestStore()
+		r := newRouter(store, Policy{Group: "api", Key: ByUser, Limit: PerMinute(60, 1)})
+
+		assert.Equal(t, http.StatusNoContent, get(r, "192.0.2.1", "user-1").Code)
+		assert.Equal(t, http.StatusTooManyRequests, get(r, "192.0.2.2", "user-1").Code)
+		assert.Equal(t, http.StatusNoContent, get(r, "192.0.2.1", "user-2").Code)
+		assert.Equal(t, "1", get(r, "192.0.2.1", "user-2").Header().Get("Retry-After"), "Retry-After rounds up to whole seconds")
+	})
+
+	t.Run("forged X-Forwarded-For does not get a new bucket", func(t *testing.T) {
+		store, _ := newTestStore()
+		r := newRouter(store, Policy{Group: "dictionary", Key: ByIP, Limit: PerMinute(60, 1)})
+		require.NoError(t, r.SetTrustedProxies(nil)) // As the server does when TRUSTED_PROXIES is empty
+		forwarded := func(remote, xff string) int {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = remote + ":1234"
+			req.Header.Set("X-Forwarded-For", xff)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			return w.Code
+		}
+
+		assert.Equal(t, http.StatusNoContent, forwarded("192.0.2.1", "198.51.100.1"))
+		assert.Equal(t, http.StatusTooManyRequests, forwarded("192.0.2.1", "198.51.100.2"), "the header is ignored without trusted proxies")
+
+		require.NoError(t, r.SetTrustedProxies([]string{"10.0.0.0/8"}))
+		assert.Equal(t, http.StatusNoContent, forwarded("10.0.0.1", "198.51.100.3"), "a trusted proxy's header names the client")
+		assert.Equal(t, http.StatusTooManyRequests, forwarded("10.0.0.2", "198.51.100.3"))
+	})
+
+	t.Run("unlimited policies pass through", func(t *testing.T) {
+		r := newRouter(failingStore{}, Policy{Group: "api", Key: ByIP})
+		assert.Equal(t, http.StatusNoContent, get(r, "192.0.2.1", "").Code)
+	})
+
+	t.Run("store errors fail open", func(t *testing.T) {
+		r := newRouter(failingStore{}, Policy{Group: "api", Key: ByIP, Limit: PerMinute(1, 1)})
+		assert.Equal(t, http.StatusNoContent, get(r, "192.0.2.1", "").Code)
+	})
+}
@@ -0,0 +1,234 @@
+package assignment
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var oslo = DefaultConfig().Location
+
+// Monday 2026-03-16 18:00 Oslo
+var testNow = time.Date(2026, 3, 16, 18, 0, 0, 0, oslo)
+
+func keyboardGoal() *models.PracticeGoal {
+	return &models.PracticeGoal{Mode: string(models.TestModeKeyboard), Runs: 3, MinScore: 90}
+}
+
+func result(wordSetID, mode string, score float64, at time.Time) models.TestResult {
+	return models.TestResult{WordSetID: wordSetID, Mode: mode, Score: score, CompletedAt: at}
+}
+
+func TestEvaluate(t *testing.T) {
+	assignedAt := testNow.AddDate(0, 0, -5)
+	due := testNow.AddDate(0, 0, 2)
+	a := &models.WordSetAssignment{
+		WordSetID:  "ws-1",
+		AssignedAt: assignedAt,
+		AssignmentSchedule: models.AssignmentSchedule{
+			DueAt: &due,
+			Goal:  keyboardGoal(),
+		},
+	}
+
+	kb := string(models.TestModeKeyboard)
+	results := []models.TestResult{
+		result("ws-1", kb, 95, assignedAt.Add(-time.Hour)),                                   // before assignment
+		result("ws-2", kb, 100, assignedAt.Add(time.Hour)),                                   // other word set
+		result("ws-1", kb, 92, assignedAt.Add(time.Hour)),                                    // qualifies
+		result("ws-1", kb, 80, assignedAt.Add(2*time.Hour)),                                  // score too low
+		result("ws-1", string(models.TestModeLetterTiles), 100, assignedAt.Add(3*time.Hour)), // wrong mode
+	}
+
+	p := Evaluate(a, results, testNow)
+	assert.Equal(t, models.AssignmentInProgress, p.Status)
+	assert.Equal(t, 3, p.TotalRuns)
+	assert.Equal(t, 1, p.QualifyingRuns)
+	assert.Equal(t, 100.0, p.BestScore)
+	assert.False(t, p.Completed)
+
+	results = append(results,
+		result("ws-1", kb, 90, assignedAt.Add(4*time.Hour)),
+		result("ws-1", kb, 100, assignedAt.Add(5*time.Hour)),
+	)
+	p = Evaluate(a, results, testNow)
+	assert.Equal(t, models.AssignmentCompleted, p.Status)
+	assert.True(t, p.Completed)
+
+	p = Evaluate(a, nil, due.Add(time.Minute))
+	assert.Equal(t, models.AssignmentOverdue, p.Status)
+
+	p = Evaluate(&models.WordSetAssignment{WordSetID: "ws-1", AssignedAt: assignedAt}, nil, testNow)
+	assert.Equal(t, models.AssignmentNotStarted, p.Status)
+	assert.Equal(t, models.PracticeGoal{Runs: 1}, p.Goal, "default goal is a single test")
+}
+
+func TestReminderDue(t *testing.T) {
+	base := models.WordSetAssignment{
+		AssignedAt: testNow.AddDate(0, 0, -3),
+		AssignmentSchedule: models.AssignmentSchedule{
+			ReminderFrequency: models.ReminderDaily,
+			ReminderHour:      17,
+		},
+	}
+
+	a := base
+	assert.True(t, ReminderDue(&a, testNow, oslo))
+	assert.False(t, ReminderDue(&a, testNow.Add(-2*time.Hour), oslo), "before today's slot")
+
+	reminded := testNow.Add(-30 * time.Minute)
+	a.LastRemindedAt = &reminded
+	assert.False(t, ReminderDue(&a, testNow, oslo), "already reminded today")
+
+	yesterday := testNow.AddDate(0, 0, -1)
+	a.LastRemindedAt = &yesterday
+	assert.True(t, ReminderDue(&a, testNow, oslo))
+
+	a = base
+	a.ReminderFrequency = models.ReminderWeekdays
+	saturday := time.Date(2026, 3, 21, 18, 0, 0, 0, oslo)
+	assert.False(t, ReminderDue(&a, saturday, oslo))
+	assert.True(t, ReminderDue(&a, testNow, oslo))
+
+	a = base
+	due := testNow.Add(-time.Minute)
+	a.DueAt = &due
+	assert.False(t, ReminderDue(&a, testNow, oslo), "past due")
+
+	a = base
+	a.AssignedAt = testNow.Add(-10 * time.Minute)
+	assert.False(t, ReminderDue(&a, testNow, oslo), "assigned after today's slot")
+
+	a = base
+	a.ReminderFrequency = models.ReminderNone
+	assert.False(t, ReminderDue(&a, testNow, oslo))
+}
+
+type fakeRepo struct {
+	reminded    map[string]time.Time
+	results     map[string][]models.TestResult
+	assignments []models.WordSetAssignment
+}
+
+func (f *fakeRepo) GetReminderAssignments(_ context.Context) ([]models.WordSetAssignment, error) {
+	out := make([]models.WordSetAssignment, len(f.assignments))
+	for i, a := range f.assignments {
+		if at, ok := f.reminded[a.WordSetID+"/"+a.UserID]; ok {
+			a.LastRemindedAt = &at
+		}
+		out[i] = a
+	}
+	return out, nil
+}
+
+func (f *fakeRepo) ClaimAssignmentReminder(_ context.Context, wordSetID, userID string, slot, remindedAt time.Time) (bool, error) {
+	key := wordSetID + "/" + userID
+	if at, ok := f.reminded[key]; ok && !at.Before(slot) {
+		return false, nil
+	}
+	f.reminded[key] = remindedAt
+	return true, nil
+}
+
+func (f *fakeRepo) GetUser(_ context.Context, userID string) (*models.User, error) {
+	return &models.User{ID: userID, DisplayName: "Åse"}, nil
+}
+
+func (f *fakeRepo) GetTestResultsInRange(_ context.Context, userID string, from, to time.Time) ([]models.TestResult, error) {
+	var out []models.TestResult
+	for _, r := range f.results[userID] {
+		if !r.CompletedAt.Before(from) && r.CompletedAt.Before(to) {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+type recordingNotifier struct {
+	sent []notify.Notification
+}
+
+func (r *recordingNotifier) Notify(_ context.Context, n notify.Notification) error {
+	r.sent = append(r.sent, n)
+	return nil
+}
+
+func TestScheduler_RunDue(t *testing.T) {
+	assignedAt := testNow.AddDate(0, 0, -2)
+	schedule := models.AssignmentSchedule{ReminderFrequency: models.ReminderDaily, ReminderHour: 17}
+	repo := &fakeRepo{
+		reminded: map[string]time.Time{},
+		assignments: []models.WordSetAssignment{
+			{WordSetID: "ws-open", WordSetName: "Uke 12", UserID: "child-1", FamilyID: "family-1", AssignedAt: assignedAt, AssignmentSchedule: schedule},
+			{WordSetID: "ws-done", WordSetName: "Uke 11", UserID: "child-1", FamilyID: "family-1", AssignedAt: assignedAt, AssignmentSchedule: schedule},
+		},
+		results: map[string][]models.TestResult{
+			"child-1": {result("ws-done", string(models.TestModeKeyboard), 100, assignedAt.Add(time.Hour))},
+		},
+	}
+	notifier := &recordingNotifier{}
+
+	s := NewScheduler(repo, notifier, DefaultConfig())
+	s.now = func() time.Time { return testNow }
+
+	n, err := s.RunDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n, "completed assignments are not reminded")
+	require.Len(t, notifier.sent, 1)
+
+	sent := notifier.sent[0]
+	assert.Equal(t, notify.KindAssignmentReminder, sent.Kind)
+	assert.Equal(t, notify.MessageReminderPractice, sent.MessageKey)
+	assert.Equal(t, "family-1", sent.FamilyID)
+	assert.Equal(t, "child-1", sent.UserID)
+	assert.Equal(t, "Uke 12", sent.Params["wordSetName"])
+
+	// Same slot: nothing more to send
+	n, err = s.RunDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	// Next day's slot
+	s.now = func() time.Time { return testNow.AddDate(0, 0, 1) }
+	n, err = s.RunDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+}
+
+// staleRepo lists assignments as they were before any reminder was recorded,
+// like a replica that read them just before another replica claimed the slot
+type staleRepo struct{ *fakeRepo }
+
+func (r staleRepo) GetReminderAssignments(_ context.Context) ([]models.WordSetAssignment, error) {
+	return r.assignments, nil
+}
+
+func TestScheduler_RunDue_SlotClaimedElsewhere(t *testing.T) {
+	schedule := models.AssignmentSchedule{ReminderFrequency: models.ReminderDaily, ReminderHour: 17}
+	repo := &fakeRepo{
+		reminded: map[string]time.Time{},
+		assignments: []models.WordSetAssignment{
+			{WordSetID: "ws-open", UserID: "child-1", FamilyID: "family-1", AssignedAt: testNow.AddDate(0, 0, -2), AssignmentSchedule: schedule},
+		},
+	}
+
+	first := &recordingNotifier{}
+	s := NewScheduler(staleRepo{repo}, first, DefaultConfig())
+	s.now = func() time.Time { return testNow }
+	n, err := s.RunDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	second := &recordingNotifier{}
+	s = NewScheduler(staleRepo{repo}, second, DefaultConfig())
+	s.now = func() time.Time { return testNow.Add(time.Minute) }
+	n, err = s.RunDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+	assert.Empty(t, second.sent, "a slot claimed by another scheduler is not notified again")
+}
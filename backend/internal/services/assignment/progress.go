@@ -0,0 +1,93 @@
+// Package assignment evaluates practice goals for word set assignments and
+// schedules reminders for children who haven't met them yet.
+package assignment
+
+import (
+	"context"
+	"time"
+
+	"github.com/starefossen/diktator/backend/internal/models"
+)
+
+// ResultsRepository defines the database operations needed to evaluate progress
+type ResultsRepository interface {
+	GetTestResultsInRange(ctx context.Context, userID string, from, to time.Time) ([]models.TestResult, error)
+}
+
+// EffectiveGoal returns the assignment's goal, defaulting to one test of any mode and score
+func EffectiveGoal(a *models.WordSetAssignment) models.PracticeGoal {
+	if a.Goal == nil || a.Goal.Runs < 1 {
+		return models.PracticeGoal{Runs: 1}
+	}
+	return *a.Goal
+}
+
+// Evaluate computes an assignment's completion state from the child's test results.
+// Only results for the assigned word set completed after the assignment count.
+func Evaluate(a *models.WordSetAssignment, results []models.TestResult, now time.Time) models.AssignmentProgress {
+	goal := EffectiveGoal(a)
+	progress := models.AssignmentProgress{
+		Assignment: a,
+		Goal:       goal,
+	}
+
+	for _, r := range results {
+		if r.WordSetID != a.WordSetID || r.CompletedAt.Before(a.AssignedAt) {
+			continue
+		}
+
+		progress.TotalRuns++
+		if r.Score > progress.BestScore {
+			progress.BestScore = r.Score
+		}
+		if progress.LastPracticedAt == nil || r.CompletedAt.After(*progress.LastPracticedAt) {
+			completedAt := r.CompletedAt
+			progress.LastPracticedAt = &completedAt
+		}
+
+		if (goal.Mode == "" || r.Mode == goal.Mode) && r.Score >= goal.MinScore {
+			progress.QualifyingRuns++
+		}
+	}
+
+	progress.Completed = progress.QualifyingRuns >= goal.Runs
+	switch {
+	case progress.Completed:
+		progress.Status = models.AssignmentCompleted
+	case a.DueAt != nil && now.After(*a.DueAt):
+		progress.Status = models.AssignmentOverdue
+	case progress.TotalRuns > 0:
+		progress.Status = models.AssignmentInProgress
+	default:
+		progress.Status = models.AssignmentNotStarted
+	}
+
+	return progress
+}
+
+// ChildProgress evaluates all of one child's assignments, loading their results once
+func ChildProgress(ctx context.Context, repo ResultsRepository, userID string, assignments []models.WordSetAssignment, now time.Time) ([]models.AssignmentProgress, error) {
+	progress := make([]models.AssignmentProgress, 0, len(assignments))
+	if len(assignments) == 0 {
+		return progress, nil
+	}
+
+	since := assignments[0].AssignedAt
+	for _, a := range assignments[1:] {
+		if a.AssignedAt.Before(since) {
+			since = a.AssignedAt
+		}
+	}
+
+	// Include results completed up to now; the range end is exclusive
+	results, err := repo.GetTestResultsInRange(ctx, userID, since, now.Add(time.Second))
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range assignments {
+		progress = append(progress, Evaluate(&assignments[i], results, now))
+	}
+
+	return progress, nil
+}
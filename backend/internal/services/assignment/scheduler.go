@@ -0,0 +1,198 @@
+package assignment
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"time"
+	_ "time/tzdata" // Reminder hours are in Europe/Oslo; don't depend on the container's zoneinfo
+
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services/notify"
+)
+
+// Repository defines the database operations needed by the reminder scheduler
+type Repository interface {
+	ResultsRepository
+	GetReminderAssignments(ctx context.Context) ([]models.WordSetAssignment, error)
+	ClaimAssignmentReminder(ctx context.Context, wordSetID, userID string, slot, remindedAt time.Time) (bool, error)
+	GetUser(ctx context.Context, userID string) (*models.User, error)
+}
+
+// Config holds reminder scheduler configuration
+type Config struct {
+	Location     *time.Location // Time zone reminder hours are interpreted in
+	PollInterval time.Duration  // How often assignments are checked
+	Logger       *slog.Logger   // Defaults to slog.Default()
+}
+
+// DefaultConfig returns sensible default configuration
+func DefaultConfig() Config {
+	loc, err := time.LoadLocation("Europe/Oslo")
+	if err != nil {
+		loc = time.UTC
+	}
+	return Config{
+		Location:     loc,
+		PollInterval: 5 * time.Minute,
+	}
+}
+
+// Scheduler raises reminders for assignments whose goal hasn't been met
+type Scheduler struct {
+	repo     Repository
+	notifier notify.Notifier
+	now      func() time.Time
+	cfg      Config
+	log      *slog.Logger
+}
+
+// NewScheduler creates a new reminder scheduler
+func NewScheduler(repo Repository, notifier notify.Notifier, cfg Config) *Scheduler {
+	return &Scheduler{
+		repo:     repo,
+		notifier: notifier,
+		now:      time.Now,
+		cfg:      cfg,
+		log:      cmp.Or(cfg.Logger, slog.Default()),
+	}
+}
+
+// ReminderDue reports whether today's reminder slot has passed without a reminder being sent.
+// Missed slots on earlier days are not caught up; at most one reminder is sent per day.
+func ReminderDue(a *models.WordSetAssignment, now time.Time, loc *time.Location) bool {
+	slot, ok := reminderSlot(a, now, loc)
+	return ok && (a.LastRemindedAt == nil || a.LastRemindedAt.Before(slot))
+}
+
+// reminderSlot returns today's reminder slot if it has passed and applies to the assignment
+func reminderSlot(a *models.WordSetAssignment, now time.Time, loc *time.Location) (time.Time, bool) {
+	if a.ReminderFrequency == "" || a.ReminderFrequency == models.ReminderNone {
+		return time.Time{}, false
+	}
+	if a.DueAt != nil && !now.Before(*a.DueAt) {
+		return time.Time{}, false
+	}
+
+	local := now.In(loc)
+	slot := time.Date(local.Year(), local.Month(), local.Day(), a.ReminderHour, 0, 0, 0, loc)
+	if local.Before(slot) || slot.Before(a.AssignedAt) {
+		return time.Time{}, false
+	}
+
+	if a.ReminderFrequency == models.ReminderWeekdays {
+		if wd := slot.Weekday(); wd == time.Saturday || wd == time.Sunday {
+			return time.Time{}, false
+		}
+	}
+
+	return slot, true
+}
+
+// RunDue sends all reminders that are due now and returns how many were sent
+func (s *Scheduler) RunDue(ctx context.Context) (int, error) {
+	now := s.now()
+	assignments, err := s.repo.GetReminderAssignments(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// Group due assignments per child so results and names are loaded once
+	due := map[string][]models.WordSetAssignment{}
+	var order []string
+	for _, a := range assignments {
+		if !ReminderDue(&a, now, s.cfg.Location) {
+			continue
+		}
+		if _, seen := due[a.UserID]; !seen {
+			order = append(order, a.UserID)
+		}
+		due[a.UserID] = append(due[a.UserID], a)
+	}
+
+	sent := 0
+	for _, userID := range order {
+		if ctx.Err() != nil {
+			return sent, ctx.Err()
+		}
+
+		n, err := s.remindChild(ctx, userID, due[userID], now)
+		sent += n
+		if err != nil {
+			s.log.ErrorContext(ctx, "Failed to send practice reminders", "user_id", userID, "error", err)
+		}
+	}
+
+	return sent, nil
+}
+
+func (s *Scheduler) remindChild(ctx context.Context, userID string, assignments []models.WordSetAssignment, now time.Time) (int, error) {
+	child, err := s.repo.GetUser(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	progress, err := ChildProgress(ctx, s.repo, userID, assignments, now)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, p := range progress {
+		a := p.Assignment
+		if p.Completed {
+			continue
+		}
+
+		// Claim the slot before notifying so schedulers on other replicas skip it
+		slot, _ := reminderSlot(a, now, s.cfg.Location)
+		claimed, err := s.repo.ClaimAssignmentReminder(ctx, a.WordSetID, userID, slot, now)
+		if err != nil {
+			return sent, err
+		}
+		if !claimed {
+			continue
+		}
+
+		params := map[string]any{
+			"childName":      child.DisplayName,
+			"wordSetId":      a.WordSetID,
+			"wordSetName":    a.WordSetName,
+			"qualifyingRuns": p.QualifyingRuns,
+			"goalRuns":       p.Goal.Runs,
+		}
+		if a.DueAt != nil {
+			params["dueAt"] = a.DueAt.UTC().Format(time.RFC3339)
+		}
+
+		n := notify.New(notify.KindAssignmentReminder, notify.MessageReminderPractice, a.FamilyID, userID, params)
+		if err := s.notifier.Notify(ctx, n); err != nil {
+			// Channels are independent; a partial failure still counts as reminded
+			// so a broken channel cannot cause a reminder storm on the others
+			s.log.WarnContext(ctx, "Some reminder channels failed", "wordset_id", a.WordSetID, "user_id", userID, "error", err)
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+// Run checks for due reminders periodically until the context is cancelled
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := s.RunDue(ctx); err != nil {
+			s.log.ErrorContext(ctx, "Failed to send reminders", "error", err)
+		} else if n > 0 {
+			s.log.InfoContext(ctx, "Sent practice reminders", "count", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// APIKeyPrefix marks family API keys so they are easy to recognise in configs and secret scanners
+const APIKeyPrefix = "dkt_"
+
+// apiKeyDisplayLength is how many characters of the key are kept for display purposes
+const apiKeyDisplayLength = 12
+
+// ErrInvalidAPIKey is returned when an API key is malformed
+var ErrInvalidAPIKey = errors.New("invalid API key")
+
+// GenerateAPIKey creates a new random family API key.
+// It returns the plaintext key (shown to the user once), a short display prefix,
+// and the hash that should be persisted.
+func GenerateAPIKey() (key, displayPrefix, hash string, err error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+
+	key = APIKeyPrefix + base64.RawURLEncoding.EncodeToString(buf)
+	return key, key[:apiKeyDisplayLength], HashAPIKey(key), nil
+}
+
+// HashAPIKey returns the hex-encoded SHA-256 hash of a plaintext API key
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// ValidateAPIKeyFormat performs a cheap syntactic check before hitting the database
+func ValidateAPIKeyFormat(key string) error {
+	if !strings.HasPrefix(key, APIKeyPrefix) || len(key) <= apiKeyDisplayLength {
+		return ErrInvalidAPIKey
+	}
+	return nil
+}
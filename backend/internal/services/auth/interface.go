@@ -4,6 +4,7 @@ package auth
 import (
 	"context"
 	"errors"
+	"log/slog"
 )
 
 // Common errors
@@ -46,6 +47,7 @@ type Config struct {
 	OIDCIssuerURL          string
 	OIDCAudience           string
 	OIDCInsecureSkipVerify bool
+	Logger                 *slog.Logger // Defaults to slog.Default()
 }
 
 // NewSessionValidator creates a new session validator based on the configuration
@@ -59,6 +61,7 @@ func NewSessionValidator(cfg *Config) (SessionValidator, error) {
 			IssuerURL:          cfg.OIDCIssuerURL,
 			Audience:           cfg.OIDCAudience,
 			InsecureSkipVerify: cfg.OIDCInsecureSkipVerify,
+			Logger:             cfg.Logger,
 		})
 	case "mock", "":
 		return NewMockValidator(cfg.MockIdentity), nil
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"cmp"
 	"context"
 	"crypto/rsa"
 	"crypto/tls"
@@ -8,7 +9,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"math/big"
 	"net/http"
 	"strings"
@@ -27,6 +28,7 @@ type OIDCValidator struct {
 	userinfoURL string
 	jwksURL     string
 	jwksMutex   sync.RWMutex
+	log         *slog.Logger
 }
 
 // JWKS represents a JSON Web Key Set
@@ -69,6 +71,9 @@ type OIDCConfig struct {
 
 	// InsecureSkipVerify skips TLS certificate verification (only for development!)
 	InsecureSkipVerify bool
+
+	// Logger receives validation failures (defaults to slog.Default())
+	Logger *slog.Logger
 }
 
 // NewOIDCValidator creates a new OIDC JWT validator
@@ -95,6 +100,7 @@ func NewOIDCValidator(cfg *OIDCConfig) (*OIDCValidator, error) {
 		issuer:     strings.TrimSuffix(cfg.IssuerURL, "/"),
 		audience:   cfg.Audience,
 		httpClient: httpClient,
+		log:        cmp.Or(cfg.Logger, slog.Default()),
 	}
 
 	// Discover JWKS URL from OIDC discovery document
@@ -135,7 +141,7 @@ func (o *OIDCValidator) discoverJWKS(ctx context.Context) error {
 
 	o.jwksURL = doc.JwksURI
 	o.userinfoURL = doc.UserinfoEndpoint
-	log.Printf("[OIDC] Discovered endpoints - JWKS: %s, Userinfo: %s", o.jwksURL, o.userinfoURL)
+	o.log.InfoContext(ctx, "Discovered OIDC endpoints", "jwks_url", o.jwksURL, "userinfo_url", o.userinfoURL)
 	return nil
 }
 
@@ -223,7 +229,7 @@ func (o *OIDCValidator) ValidateSessionFromCookie(ctx context.Context, cookieVal
 // validateToken parses and validates a JWT token
 func (o *OIDCValidator) validateToken(ctx context.Context, tokenString string) (*Identity, error) {
 	if tokenString == "" {
-		log.Printf("[OIDC] Token validation failed: empty token")
+		o.log.DebugContext(ctx, "Token validation failed", "reason", "empty token")
 		return nil, ErrInvalidSession
 	}
 
@@ -231,21 +237,21 @@ func (o *OIDCValidator) validateToken(ctx context.Context, tokenString string) (
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		// Verify signing method
 		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
-			log.Printf("[OIDC] Token validation failed: unexpected signing method: %v", token.Header["alg"])
+			o.log.WarnContext(ctx, "Token validation failed", "reason", "unexpected signing method", "alg", token.Header["alg"])
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
 
 		// Get the key ID
 		kid, ok := token.Header["kid"].(string)
 		if !ok {
-			log.Printf("[OIDC] Token validation failed: missing kid header")
+			o.log.WarnContext(ctx, "Token validation failed", "reason", "missing kid header")
 			return nil, fmt.Errorf("token missing kid header")
 		}
 
 		// Get the signing key
 		jwk, err := o.getKey(ctx, kid)
 		if err != nil {
-			log.Printf("[OIDC] Token validation failed: key lookup error for kid=%s: %v", kid, err)
+			o.log.WarnContext(ctx, "Token validation failed", "reason", "key lookup error", "kid", kid, "error", err)
 			return nil, err
 		}
 
@@ -254,12 +260,12 @@ func (o *OIDCValidator) validateToken(ctx context.Context, tokenString string) (
 	})
 
 	if err != nil {
-		log.Printf("[OIDC] Token parsing failed: %v", err)
+		o.log.InfoContext(ctx, "Token parsing failed", "error", err)
 		return nil, fmt.Errorf("%w: %v", ErrInvalidSession, err)
 	}
 
 	if !token.Valid {
-		log.Printf("[OIDC] Token validation failed: token marked as invalid")
+		o.log.WarnContext(ctx, "Token validation failed", "reason", "token marked as invalid")
 		return nil, ErrInvalidSession
 	}
 
@@ -272,9 +278,9 @@ func (o *OIDCValidator) validateToken(ctx context.Context, tokenString string) (
 	// Validate issuer
 	if iss, ok := claims["iss"].(string); !ok || iss != o.issuer {
 		if !ok {
-			log.Printf("[OIDC] Token validation failed: missing issuer claim")
+			o.log.WarnContext(ctx, "Token validation failed", "reason", "missing issuer claim")
 		} else {
-			log.Printf("[OIDC] Token validation failed: invalid issuer (got=%s, expected=%s)", iss, o.issuer)
+			o.log.WarnContext(ctx, "Token validation failed", "reason", "invalid issuer", "got", iss, "expected", o.issuer)
 		}
 		return nil, fmt.Errorf("%w: invalid issuer", ErrInvalidSession)
 	}
@@ -295,7 +301,7 @@ func (o *OIDCValidator) validateToken(ctx context.Context, tokenString string) (
 		}
 		if !audValid {
 			audValue := claims["aud"]
-			log.Printf("[OIDC] Token validation failed: invalid audience (got=%v, expected=%s)", audValue, o.audience)
+			o.log.WarnContext(ctx, "Token validation failed", "reason", "invalid audience", "got", audValue, "expected", o.audience)
 			return nil, fmt.Errorf("%w: invalid audience", ErrInvalidSession)
 		}
 	}
@@ -304,7 +310,7 @@ func (o *OIDCValidator) validateToken(ctx context.Context, tokenString string) (
 	if exp, ok := claims["exp"].(float64); ok {
 		expTime := time.Unix(int64(exp), 0)
 		if expTime.Before(time.Now()) {
-			log.Printf("[OIDC] Token validation failed: token expired (exp=%s, now=%s)", expTime.Format(time.RFC3339), time.Now().Format(time.RFC3339))
+			o.log.InfoContext(ctx, "Token validation failed", "reason", "token expired", "expired_at", expTime)
 			return nil, fmt.Errorf("%w: token expired", ErrInvalidSession)
 		}
 	}
@@ -334,15 +340,15 @@ func (o *OIDCValidator) validateToken(ctx context.Context, tokenString string) (
 
 	// If email is missing from token, fetch from userinfo endpoint
 	if identity.Email == "" && o.userinfoURL != "" {
-		log.Printf("[OIDC] Email missing from ID token for subject %s, fetching from userinfo endpoint", identity.ID)
+		o.log.DebugContext(ctx, "Email missing from ID token, fetching from userinfo endpoint", "subject", identity.ID)
 		if err := o.enrichIdentityFromUserinfo(ctx, tokenString, identity); err != nil {
-			log.Printf("[OIDC] WARNING: Failed to fetch userinfo: %v. Subject: %s", err, identity.ID)
+			o.log.WarnContext(ctx, "Failed to fetch userinfo", "subject", identity.ID, "error", err)
 		}
 	}
 
 	// Log warning if email is still missing after userinfo fetch
 	if identity.Email == "" {
-		log.Printf("[OIDC] WARNING: Email not found in token or userinfo. Subject: %s", identity.ID)
+		o.log.WarnContext(ctx, "Email not found in token or userinfo", "subject", identity.ID)
 	}
 
 	// Check email_verified claim
@@ -372,6 +378,20 @@ func (o *OIDCValidator) Close() error {
 	return nil
 }
 
+// CheckHealth fetches the provider's signing keys. Keys are cached for five
+// minutes, so frequent probes reach the provider at most that often.
+func (o *OIDCValidator) CheckHealth(ctx context.Context) error {
+	if err := o.fetchJWKS(ctx); err != nil {
+		return err
+	}
+	o.jwksMutex.RLock()
+	defer o.jwksMutex.RUnlock()
+	if len(o.jwks.Keys) == 0 {
+		return fmt.Errorf("JWKS contains no keys")
+	}
+	return nil
+}
+
 // jwkToRSAPublicKey converts a JWK to an RSA public key
 func jwkToRSAPublicKey(jwk *JWK) (interface{}, error) {
 	if jwk.Kty != "RSA" {
@@ -446,13 +466,13 @@ func (o *OIDCValidator) enrichIdentityFromUserinfo(ctx context.Context, accessTo
 		return fmt.Errorf("failed to decode userinfo response: %w", err)
 	}
 
-	log.Printf("[OIDC] Userinfo response for subject %s: %v", identity.ID, userinfo)
+	o.log.DebugContext(ctx, "Received userinfo response", "subject", identity.ID, "userinfo", userinfo)
 
 	// Extract email from userinfo
 	if email, ok := userinfo["email"].(string); ok && email != "" && identity.Email == "" {
 		identity.Email = email
 		identity.Traits["email"] = email
-		log.Printf("[OIDC] Enriched identity with email from userinfo: %s", email)
+		o.log.DebugContext(ctx, "Enriched identity with email from userinfo", "subject", identity.ID)
 	}
 
 	// Extract name if missing
@@ -4,6 +4,7 @@ package cache
 import (
 	"container/list"
 	"sync"
+	"sync/atomic"
 )
 
 // Entry represents a cached item with its key and value
@@ -20,6 +21,8 @@ type LRUCache struct {
 	maxBytes  int64
 	usedBytes int64
 	mu        sync.RWMutex
+	hits      atomic.Uint64
+	misses    atomic.Uint64
 }
 
 // NewLRUCache creates a new LRU cache with the specified capacity
@@ -43,9 +46,11 @@ func (c *LRUCache) Get(key string) ([]byte, bool) {
 		// Move to front (most recently used)
 		c.list.MoveToFront(element)
 		entry := element.Value.(*Entry)
+		c.hits.Add(1)
 		return entry.value, true
 	}
 
+	c.misses.Add(1)
 	return nil, false
 }
 
@@ -116,3 +121,8 @@ func (c *LRUCache) Stats() (items int, bytes int64, maxBytes int64) {
 
 	return c.list.Len(), c.usedBytes, c.maxBytes
 }
+
+// Counters returns the number of lookups that hit and missed since the cache was created
+func (c *LRUCache) Counters() (hits, misses uint64) {
+	return c.hits.Load(), c.misses.Load()
+}
@@ -0,0 +1,171 @@
+// Package challenge generates the scaffolding for the letterTiles, wordBank and
+// missingLetters modes: letter tiles with Norwegian distractors, word banks with
+// confusable words and the letters to blank in a missing-letters gap.
+//
+// The distractors follow the strategy in docs/LEARNING.md. Generation is
+// deterministic for a seed, so a parent can preview a challenge set and lock the
+// seed to give a child exactly the challenges they saw.
+package challenge
+
+import (
+	"hash/fnv"
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services/spelling"
+)
+
+// Number of distractors added to each challenge, as in the frontend's CHALLENGE_CONFIG
+const (
+	LetterDistractors = 4
+	WordDistractors   = 6
+)
+
+// RandomSeed returns a fresh seed for a challenge set that isn't locked
+func RandomSeed() int64 {
+	return rand.Int64N(models.MaxChallengeSeed + 1)
+}
+
+// Generate builds the challenges for every word and sentence in a word set.
+// Each item gets its own random stream derived from the seed and the item, so
+// editing one word leaves the challenges for the other words unchanged.
+func Generate(ws *models.WordSet, seed int64) []models.WordChallenge {
+	challenges := make([]models.WordChallenge, 0, len(ws.Words)+len(ws.Sentences))
+	seen := make(map[string]bool, cap(challenges))
+	add := func(item string) {
+		item = strings.TrimSpace(item)
+		if item == "" || seen[item] {
+			return
+		}
+		seen[item] = true
+		challenges = append(challenges, For(item, ws, seed))
+	}
+
+	for _, w := range ws.Words {
+		add(w.Word)
+	}
+	for _, s := range ws.Sentences {
+		add(s.Sentence)
+	}
+	return challenges
+}
+
+// For builds the challenge for a single word or sentence of a word set
+func For(item string, ws *models.WordSet, seed int64) models.WordChallenge {
+	rng := newRand(seed, item)
+	if spelling.IsSentence(item) {
+		return models.WordChallenge{
+			Word:     item,
+			WordBank: WordBank(item, ws, rng),
+		}
+	}
+
+	focus := Focus(item, ws.SpellingFocus)
+	return models.WordChallenge{
+		Word:           item,
+		Focus:          focus,
+		LetterTiles:    LetterTiles(item, focus, rng),
+		MissingLetters: MissingLetters(item, focus, rng),
+	}
+}
+
+func newRand(seed int64, item string) *rand.Rand {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(item))
+	return rand.New(rand.NewPCG(uint64(seed), h.Sum64()))
+}
+
+// Focus returns the spelling focus categories found in a word. Vowel length
+// can't be seen from the spelling alone, so it (and double consonants for
+// words without one) only applies when the word set has that focus.
+func Focus(word string, setFocus []models.SpellingFocusCategory) []models.SpellingFocusCategory {
+	lower := strings.ToLower(word)
+	var focus []models.SpellingFocusCategory
+	for _, category := range []models.SpellingFocusCategory{
+		models.SpellingFocusDoubleConsonant,
+		models.SpellingFocusSilentLetter,
+		models.SpellingFocusDiphthong,
+		models.SpellingFocusSkjSound,
+		models.SpellingFocusSpecialChars,
+		models.SpellingFocusNgNk,
+		models.SpellingFocusSilentD,
+		models.SpellingFocusVowelLength,
+	} {
+		if detectors[category](lower) || (setOnly(category) && slices.Contains(setFocus, category)) {
+			focus = append(focus, category)
+		}
+	}
+	return focus
+}
+
+func setOnly(category models.SpellingFocusCategory) bool {
+	return category == models.SpellingFocusDoubleConsonant || category == models.SpellingFocusVowelLength
+}
+
+// detectors spot a spelling focus category in a lowercased word
+var detectors = map[models.SpellingFocusCategory]func(string) bool{
+	models.SpellingFocusDoubleConsonant: func(w string) bool { return doubledAt([]rune(w)) >= 0 },
+	models.SpellingFocusSilentLetter: func(w string) bool {
+		return hasAnyPrefix(w, "hj", "hv", "gj")
+	},
+	models.SpellingFocusDiphthong: func(w string) bool { return diphthongAt([]rune(w)) >= 0 },
+	models.SpellingFocusSkjSound: func(w string) bool {
+		return hasAnyPrefix(w, "sj", "kj", "tj") || strings.Contains(w, "skj")
+	},
+	models.SpellingFocusSpecialChars: func(w string) bool { return strings.ContainsAny(w, "æøå") },
+	models.SpellingFocusNgNk:         func(w string) bool { return strings.Contains(w, "ng") || strings.Contains(w, "nk") },
+	models.SpellingFocusSilentD: func(w string) bool {
+		return strings.HasSuffix(w, "nd") || strings.HasSuffix(w, "ld") || strings.HasSuffix(w, "rd")
+	},
+	models.SpellingFocusVowelLength: func(string) bool { return false },
+}
+
+func hasAnyPrefix(s string, prefixes ...string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// doubledAt returns the index of the first doubled consonant, or -1
+func doubledAt(runes []rune) int {
+	for i := 0; i+1 < len(runes); i++ {
+		if runes[i] == runes[i+1] && isConsonant(runes[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+var diphthongs = []string{"ei", "øy", "au", "ai", "oi", "ui"}
+
+// diphthongAt returns the index of the first diphthong, or -1
+func diphthongAt(runes []rune) int {
+	for i := 0; i+1 < len(runes); i++ {
+		if slices.Contains(diphthongs, string(runes[i:i+2])) {
+			return i
+		}
+	}
+	return -1
+}
+
+const vowels = "aeiouyæøå"
+
+func isVowel(r rune) bool {
+	return strings.ContainsRune(vowels, r)
+}
+
+func isConsonant(r rune) bool {
+	return r >= 'a' && r <= 'z' && !isVowel(r)
+}
+
+// shuffled returns a shuffled copy of items
+func shuffled[T any](items []T, rng *rand.Rand) []T {
+	out := slices.Clone(items)
+	rng.Shuffle(len(out), func(i, j int) { out[i], out[j] = out[j], out[i] })
+	return out
+}
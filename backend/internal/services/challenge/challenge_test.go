@@ -0,0 +1,150 @@
+package challenge
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/starefossen/diktator/backend/internal/models"
+)
+
+func wordSet(focus []models.SpellingFocusCategory, words ...string) *models.WordSet {
+	ws := &models.WordSet{SpellingFocus: focus}
+	for _, w := range words {
+		ws.Words = append(ws.Words, struct {
+			Word         string               `json:"word"`
+			Audio        models.WordAudio     `json:"audio,omitempty"`
+			Definition   string               `json:"definition,omitempty"`
+			Translations []models.Translation `json:"translations,omitempty"`
+		}{Word: w})
+	}
+	return ws
+}
+
+func testRand() *rand.Rand {
+	return rand.New(rand.NewPCG(1, 2))
+}
+
+func distractorLetters(tiles []models.LetterTile) []string {
+	var out []string
+	for _, t := range tiles {
+		if t.IsDistractor {
+			out = append(out, t.Letter)
+		}
+	}
+	return out
+}
+
+func TestFocus(t *testing.T) {
+	assert.Equal(t, []models.SpellingFocusCategory{
+		models.SpellingFocusDoubleConsonant, models.SpellingFocusSkjSound, models.SpellingFocusSpecialChars,
+	}, Focus("Skjønn", nil))
+	assert.Equal(t, []models.SpellingFocusCategory{models.SpellingFocusSilentLetter}, Focus("hvit", nil))
+	assert.Equal(t, []models.SpellingFocusCategory{
+		models.SpellingFocusDoubleConsonant, models.SpellingFocusVowelLength,
+	}, Focus("tak", []models.SpellingFocusCategory{models.SpellingFocusDoubleConsonant, models.SpellingFocusVowelLength}))
+	assert.Empty(t, Focus("tak", []models.SpellingFocusCategory{models.SpellingFocusSkjSound}),
+		"set focus only applies to words that have it")
+}
+
+func TestLetterTiles(t *testing.T) {
+	t.Run("FocusDistractors", func(t *testing.T) {
+		tiles := LetterTiles("kjole", Focus("kjole", nil), testRand())
+		require.Len(t, tiles, 5+LetterDistractors)
+		assert.Subset(t, distractorLetters(tiles), []string{"s", "t", "h"}, "skj, tj and hj spellings of the kj-sound")
+
+		var letters []string
+		for _, tile := range tiles {
+			if !tile.IsDistractor {
+				letters = append(letters, tile.Letter)
+			}
+		}
+		assert.ElementsMatch(t, []string{"k", "j", "o", "l", "e"}, letters)
+	})
+
+	t.Run("DoublingTrap", func(t *testing.T) {
+		focus := Focus("tak", []models.SpellingFocusCategory{models.SpellingFocusVowelLength})
+		tiles := LetterTiles("tak", focus, testRand())
+		assert.Contains(t, distractorLetters(tiles), "k", "an extra k tempts takk")
+
+		tiles = LetterTiles("takk", Focus("takk", nil), testRand())
+		assert.NotContains(t, distractorLetters(tiles), "k")
+	})
+
+	t.Run("NoDistractorsFromWord", func(t *testing.T) {
+		tiles := LetterTiles("sol", nil, testRand())
+		for _, d := range distractorLetters(tiles) {
+			assert.NotContains(t, []string{"s", "o", "l"}, d)
+		}
+		assert.Len(t, distractorLetters(tiles), LetterDistractors)
+	})
+}
+
+func TestMissingLetters(t *testing.T) {
+	gap := MissingLetters("mann", nil, testRand())
+	require.NotNil(t, gap)
+	assert.Equal(t, "ma__", gap.BlankedWord)
+	assert.Equal(t, "doubleConsonant", gap.ChallengeType)
+
+	gap = MissingLetters("vei", Focus("vei", nil), testRand())
+	require.NotNil(t, gap)
+	assert.Equal(t, "v__", gap.BlankedWord)
+	assert.Equal(t, GapDiphthong, gap.ChallengeType)
+
+	gap = MissingLetters("hus", nil, testRand())
+	require.NotNil(t, gap)
+	assert.Equal(t, "h_s", gap.BlankedWord)
+	assert.Equal(t, "vowel", gap.ChallengeType)
+
+	assert.Nil(t, MissingLetters("pst", nil, testRand()))
+}
+
+func TestWordBank(t *testing.T) {
+	items := WordBank("Han er her.", nil, testRand())
+	var words, distractors []string
+	for _, item := range items {
+		if item.IsDistractor {
+			distractors = append(distractors, item.Word)
+		} else {
+			words = append(words, item.Word)
+		}
+	}
+	assert.ElementsMatch(t, []string{"han", "er", "her"}, words)
+	assert.ElementsMatch(t, []string{"hun", "den", "var", "blir", "der", "hit"}, distractors)
+
+	items = WordBank("Katten sover.", wordSet(nil, "hund", "Katten sover.", "mus"), testRand())
+	assert.Len(t, items, 2+WordDistractors)
+	var fromSet int
+	for _, item := range items {
+		if item.Word == "hund" || item.Word == "mus" {
+			fromSet++
+		}
+	}
+	assert.Equal(t, 2, fromSet, "single words from the word set come before fillers")
+}
+
+func TestGenerate(t *testing.T) {
+	ws := wordSet(nil, "katt", "sjø", "Jeg ser en båt.")
+	ws.Sentences = []models.SentenceItem{{Sentence: "Jeg ser en båt."}, {Sentence: "Hun løper fort."}}
+
+	challenges := Generate(ws, 42)
+	require.Len(t, challenges, 4)
+	assert.NotEmpty(t, challenges[0].LetterTiles)
+	assert.NotNil(t, challenges[0].MissingLetters)
+	assert.Empty(t, challenges[2].LetterTiles)
+	assert.NotEmpty(t, challenges[2].WordBank)
+	assert.Equal(t, "Hun løper fort.", challenges[3].Word)
+
+	assert.Equal(t, challenges, Generate(ws, 42), "same seed, same challenges")
+
+	differs := false
+	for seed := int64(1); seed < 10 && !differs; seed++ {
+		differs = !assert.ObjectsAreEqual(challenges, Generate(ws, seed))
+	}
+	assert.True(t, differs, "other seeds shuffle differently")
+
+	edited := wordSet(nil, "katt", "hus")
+	assert.Equal(t, challenges[0], Generate(edited, 42)[0], "editing one word keeps the others stable")
+}
@@ -0,0 +1,143 @@
+package challenge
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services/spelling"
+)
+
+// GapDiphthong is the missing-letters challenge type for a blanked diphthong.
+// The other types come from spelling.DetectChallenge.
+const GapDiphthong = "diphthong"
+
+// phoneticPairs are phonetically similar Norwegian letters (PHONETIC_PAIRS in the frontend)
+var phoneticPairs = map[rune][]rune{
+	'o': []rune("øå"),
+	'ø': []rune("oö"),
+	'a': []rune("æå"),
+	'æ': []rune("ae"),
+	'å': []rune("oa"),
+	'e': []rune("æi"),
+	'k': []rune("g"),
+	'g': []rune("k"),
+	'p': []rune("b"),
+	'b': []rune("p"),
+	't': []rune("d"),
+	'd': []rune("t"),
+	's': []rune("z"),
+	'n': []rune("m"),
+	'm': []rune("n"),
+}
+
+// focusLetters are the confusable graphemes tried first for each spelling focus
+var focusLetters = map[models.SpellingFocusCategory][]rune{
+	models.SpellingFocusSilentLetter: []rune("hgv"),    // hj-, gj-, hv- against plain j- and v-
+	models.SpellingFocusDiphthong:    []rune("eiøyau"), // ei/æi, øy/ø, au/ø
+	models.SpellingFocusSkjSound:     []rune("skjth"),  // skj, sj, kj, tj, hj
+	models.SpellingFocusSpecialChars: []rune("æøåeoa"), // æ/e, ø/o, å/o
+	models.SpellingFocusNgNk:         []rune("ngk"),
+	models.SpellingFocusSilentD:      []rune("dt"),
+}
+
+// commonLetters pad the distractors when the word has few confusable letters
+var commonLetters = []rune("erntsailodkgmvfpbhjuyåøæ")
+
+// LetterTiles returns the letters of a word and up to LetterDistractors
+// distractors, shuffled. Distractors are picked from the word's spelling focus
+// first, then phonetically similar letters, then common Norwegian letters.
+// With a double consonant or vowel length focus, a word without a double
+// consonant gets an extra copy of its last consonant (tak/takk).
+func LetterTiles(word string, focus []models.SpellingFocusCategory, rng *rand.Rand) []models.LetterTile {
+	letters := []rune(strings.ToLower(word))
+	tiles := make([]models.LetterTile, 0, len(letters)+LetterDistractors)
+	for _, r := range letters {
+		tiles = append(tiles, models.LetterTile{Letter: string(r)})
+	}
+
+	var distractors []rune
+	add := func(r rune) {
+		if len(distractors) < LetterDistractors && !slices.Contains(letters, r) && !slices.Contains(distractors, r) {
+			distractors = append(distractors, r)
+		}
+	}
+
+	if r, ok := doublingTrap(letters, focus); ok {
+		distractors = append(distractors, r)
+	}
+	for _, category := range focus {
+		for _, r := range shuffled(focusLetters[category], rng) {
+			add(r)
+		}
+	}
+	for _, letter := range letters {
+		for _, r := range shuffled(phoneticPairs[letter], rng) {
+			add(r)
+		}
+	}
+	for _, r := range shuffled(commonLetters, rng) {
+		add(r)
+	}
+
+	for _, r := range distractors {
+		tiles = append(tiles, models.LetterTile{Letter: string(r), IsDistractor: true})
+	}
+	return shuffled(tiles, rng)
+}
+
+// doublingTrap returns the consonant to offer twice for words without a double consonant
+func doublingTrap(letters []rune, focus []models.SpellingFocusCategory) (rune, bool) {
+	if !slices.Contains(focus, models.SpellingFocusDoubleConsonant) && !slices.Contains(focus, models.SpellingFocusVowelLength) {
+		return 0, false
+	}
+	if doubledAt(letters) >= 0 {
+		return 0, false
+	}
+	for i := len(letters) - 1; i > 0; i-- {
+		if isConsonant(letters[i]) && isVowel(letters[i-1]) {
+			return letters[i], true
+		}
+	}
+	return 0, false
+}
+
+// MissingLetters picks the letters to blank in a word. Words with a typical
+// spelling difficulty blank it as spelling.DetectChallenge does; other words
+// blank their diphthong, or a vowel chosen by rng. Returns nil when there is
+// nothing to blank.
+func MissingLetters(word string, focus []models.SpellingFocusCategory, rng *rand.Rand) *models.MissingLettersGap {
+	c, ok := spelling.DetectChallenge(word)
+	if !ok {
+		return nil
+	}
+
+	if c.ChallengeType == spelling.ChallengeVowel {
+		lower := []rune(strings.ToLower(word))
+		if i := diphthongAt(lower); i >= 0 && slices.Contains(focus, models.SpellingFocusDiphthong) {
+			c = spelling.BlankWord(word, []int{i, i + 1})
+			c.ChallengeType = GapDiphthong
+		} else {
+			c = spelling.BlankWord(word, []int{randomVowel(lower, rng)})
+			c.ChallengeType = spelling.ChallengeVowel
+		}
+	}
+
+	return &models.MissingLettersGap{
+		BlankedWord:    c.BlankedWord,
+		MissingLetters: c.MissingLetters,
+		ChallengeType:  c.ChallengeType,
+		Positions:      c.Positions,
+	}
+}
+
+func randomVowel(runes []rune, rng *rand.Rand) int {
+	var positions []int
+	for i, r := range runes {
+		if isVowel(r) {
+			positions = append(positions, i)
+		}
+	}
+	return positions[rng.IntN(len(positions))]
+}
@@ -0,0 +1,105 @@
+package challenge
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services/spelling"
+)
+
+// confusableWords are Norwegian words that are easy to mix up in a sentence
+// (CONFUSABLE_WORDS in the frontend)
+var confusableWords = map[string][]string{
+	"da":     {"når", "så"},
+	"når":    {"da", "hvor"},
+	"der":    {"her", "hvor"},
+	"her":    {"der", "hit"},
+	"han":    {"hun", "den"},
+	"hun":    {"han", "den"},
+	"var":    {"er", "blir"},
+	"er":     {"var", "blir"},
+	"kan":    {"skal", "vil"},
+	"skal":   {"kan", "vil", "må"},
+	"vil":    {"kan", "skal"},
+	"meg":    {"deg", "seg"},
+	"deg":    {"meg", "seg"},
+	"seg":    {"meg", "deg"},
+	"sin":    {"hans", "hennes"},
+	"sitt":   {"hans", "hennes"},
+	"sine":   {"hans", "hennes"},
+	"eller":  {"og", "men"},
+	"og":     {"eller", "men"},
+	"men":    {"og", "eller"},
+	"i":      {"på", "til"},
+	"på":     {"i", "til", "av"},
+	"til":    {"fra", "i", "på"},
+	"fra":    {"til", "av"},
+	"av":     {"fra", "på"},
+	"at":     {"om", "så"},
+	"om":     {"at", "hvis"},
+	"hvis":   {"om", "når"},
+	"fordi":  {"derfor", "siden"},
+	"derfor": {"fordi", "så"},
+	"selv":   {"også", "bare"},
+	"også":   {"selv", "bare"},
+	"bare":   {"selv", "også", "kun"},
+}
+
+// fillerWords pad the word bank when the word set is small (NORWEGIAN_FILLERS in the frontend)
+var fillerWords = []string{
+	"og", "er", "på", "en", "et", "å", "i", "til", "for", "med", "som", "av", "har", "var", "kan",
+	"jeg", "du", "vi", "de", "den", "det", "da", "om", "men", "så", "når", "etter", "før", "mot",
+}
+
+// WordBank returns the words of a sentence, lowercased and without punctuation,
+// and up to WordDistractors distractors, shuffled. Distractors are confusable
+// words for the words in the sentence first, then single words from the word
+// set, then common Norwegian filler words.
+func WordBank(sentence string, ws *models.WordSet, rng *rand.Rand) []models.WordBankItem {
+	words := spelling.Tokenize(sentence, spelling.DefaultSentenceOptions())
+	items := make([]models.WordBankItem, 0, len(words)+WordDistractors)
+	for _, w := range words {
+		items = append(items, models.WordBankItem{Word: w})
+	}
+
+	var distractors []string
+	add := func(w string) {
+		if len(distractors) < WordDistractors && !slices.Contains(words, w) && !slices.Contains(distractors, w) {
+			distractors = append(distractors, w)
+		}
+	}
+
+	for _, w := range words {
+		for _, confusable := range shuffled(confusableWords[w], rng) {
+			add(confusable)
+		}
+	}
+	for _, w := range shuffled(singleWords(ws), rng) {
+		add(w)
+	}
+	for _, w := range shuffled(fillerWords, rng) {
+		add(w)
+	}
+
+	for _, w := range distractors {
+		items = append(items, models.WordBankItem{Word: w, IsDistractor: true})
+	}
+	return shuffled(items, rng)
+}
+
+// singleWords returns the lowercased words of a word set, skipping sentences
+func singleWords(ws *models.WordSet) []string {
+	if ws == nil {
+		return nil
+	}
+	var words []string
+	for _, w := range ws.Words {
+		word := strings.ToLower(strings.TrimSpace(w.Word))
+		if word != "" && !strings.Contains(word, " ") {
+			words = append(words, word)
+		}
+	}
+	return words
+}
@@ -0,0 +1,1066 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testRepository runs the conformance suite every Repository implementation
+// must pass. Subtests create their own families, so one repository can be
+// shared by all of them.
+func testRepository(t *testing.T, repo Repository) {
+	t.Run("Users", func(t *testing.T) { testUsers(t, repo) })
+	t.Run("Families", func(t *testing.T) { testFamilies(t, repo) })
+	t.Run("Children", func(t *testing.T) { testChildren(t, repo) })
+	t.Run("WordSets", func(t *testing.T) { testWordSets(t, repo) })
+	t.Run("TestResults", func(t *testing.T) { testTestResults(t, repo) })
+	t.Run("Listings", func(t *testing.T) { testListings(t, repo) })
+	t.Run("Assignments", func(t *testing.T) { testAssignments(t, repo) })
+	t.Run("MasteryAndXP", func(t *testing.T) { testMasteryAndXP(t, repo) })
+	t.Run("Invitations", func(t *testing.T) { testInvitations(t, repo) })
+	t.Run("Verification", func(t *testing.T) { testVerification(t, repo) })
+	t.Run("APIKeysAndWebhooks", func(t *testing.T) { testAPIKeysAndWebhooks(t, repo) })
+	t.Run("Notifications", func(t *testing.T) { testNotifications(t, repo) })
+	t.Run("TestSessions", func(t *testing.T) { testTestSessions(t, repo) })
+	t.Run("Analytics", func(t *testing.T) { testAnalytics(t, repo) })
+	t.Run("PracticeListsAndLocks", func(t *testing.T) { testPracticeListsAndLocks(t, repo) })
+	t.Run("Cascades", func(t *testing.T) { testCascades(t, repo) })
+	t.Run("ForeignKeys", func(t *testing.T) { testForeignKeys(t, repo) })
+	t.Run("ConcurrentWrites", func(t *testing.T) { testConcurrentWrites(t, repo) })
+	t.Run("Transactions", func(t *testing.T) { testTransactions(t, repo) })
+}
+
+// fixture is a family with a parent, one child and one word set
+type fixture struct {
+	parent  *models.User
+	child   *models.ChildAccount
+	family  *models.Family
+	wordSet *models.WordSet
+}
+
+func newUser(t *testing.T, repo Repository, role string) *models.User {
+	t.Helper()
+	now := time.Now().Truncate(time.Second)
+	user := &models.User{
+		AuthID:       "auth-" + uuid.NewString(),
+		Email:        uuid.NewString() + "@example.com",
+		DisplayName:  "Test " + role,
+		Role:         role,
+		IsActive:     true,
+		CreatedAt:    now,
+		LastActiveAt: now,
+	}
+	require.NoError(t, repo.CreateUser(t.Context(), user))
+	return user
+}
+
+func newFixture(t *testing.T, repo Repository) *fixture {
+	t.Helper()
+	now := time.Now().Truncate(time.Second)
+
+	parent := newUser(t, repo, "parent")
+	family := &models.Family{Name: "Test Family", CreatedBy: parent.ID, Members: []string{parent.ID}, CreatedAt: now, UpdatedAt: now}
+	require.NoError(t, repo.CreateFamily(t.Context(), family))
+	parent.FamilyID = family.ID
+	require.NoError(t, repo.UpdateUser(t.Context(), parent))
+
+	child := &models.ChildAccount{
+		Email:        uuid.NewString() + "@example.com",
+		DisplayName:  "Test child",
+		FamilyID:     family.ID,
+		ParentID:     &parent.ID,
+		IsActive:     true,
+		CreatedAt:    now,
+		LastActiveAt: now,
+	}
+	require.NoError(t, repo.CreateChild(t.Context(), child))
+	require.NoError(t, repo.AddFamilyMember(t.Context(), family.ID, child.ID, "child"))
+
+	wordSet := newWordSet(t, repo, family.ID, parent.ID, "Uke 1", "hus", "katt")
+	return &fixture{parent: parent, child: child, family: family, wordSet: wordSet}
+}
+
+func newWordSet(t *testing.T, repo Repository, familyID, createdBy, name string, words ...string) *models.WordSet {
+	t.Helper()
+	now := time.Now().Truncate(time.Second)
+	ws := &models.WordSet{Name: name, FamilyID: &familyID, CreatedBy: createdBy, Language: "no", CreatedAt: now, UpdatedAt: now}
+	for _, word := range words {
+		ws.Words = append(ws.Words, wordEntry{Word: word})
+	}
+	require.NoError(t, repo.CreateWordSet(t.Context(), ws))
+	return ws
+}
+
+func saveResult(t *testing.T, repo Repository, userID, wordSetID string, completedAt time.Time, words ...models.WordTestResult) *models.TestResult {
+	t.Helper()
+	correct := 0
+	for _, w := range words {
+		if w.Correct {
+			correct++
+		}
+	}
+	result := &models.TestResult{
+		WordSetID:    wordSetID,
+		UserID:       userID,
+		Mode:         "keyboard",
+		Words:        words,
+		Score:        float64(correct) / float64(max(len(words), 1)) * 100,
+		TotalWords:   len(words),
+		CorrectWords: correct,
+		CompletedAt:  completedAt,
+		CreatedAt:    completedAt,
+	}
+	require.NoError(t, repo.SaveTestResult(t.Context(), result))
+	return result
+}
+
+func answer(word string, correct bool, attempts int, errorTypes ...string) models.WordTestResult {
+	return models.WordTestResult{Word: word, Correct: correct, Attempts: attempts, UserAnswers: []string{word}, ErrorTypes: errorTypes}
+}
+
+func testUsers(t *testing.T, repo Repository) {
+	user := newUser(t, repo, "parent")
+
+	got, err := repo.GetUser(t.Context(), user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, user.Email, got.Email)
+	assert.Equal(t, 1, got.Level)
+	assert.Equal(t, 0, got.TotalXP)
+
+	got, err = repo.GetUserByAuthID(t.Context(), user.AuthID)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, got.ID)
+
+	got, err = repo.GetUserByEmail(t.Context(), strings.ToUpper(user.Email))
+	require.NoError(t, err, "email lookups are case-insensitive")
+	assert.Equal(t, user.ID, got.ID)
+
+	require.NoError(t, repo.UpdateUserDisplayName(t.Context(), user.ID, "Renamed"))
+	got, err = repo.GetUser(t.Context(), user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", got.DisplayName)
+
+	newAuthID := "auth-" + uuid.NewString()
+	require.NoError(t, repo.LinkUserToAuthID(t.Context(), user.ID, newAuthID))
+	got, err = repo.GetUserByAuthID(t.Context(), newAuthID)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, got.ID)
+
+	duplicate := &models.User{AuthID: "auth-" + uuid.NewString(), Email: user.Email, DisplayName: "Dup", Role: "parent"}
+	assert.Error(t, repo.CreateUser(t.Context(), duplicate), "email must be unique")
+
+	missing := uuid.NewString()
+	_, err = repo.GetUser(t.Context(), missing)
+	assert.ErrorIs(t, err, ErrUserNotFound)
+	_, err = repo.GetUserByAuthID(t.Context(), missing)
+	assert.ErrorIs(t, err, ErrUserNotFound)
+	assert.ErrorIs(t, repo.UpdateUser(t.Context(), &models.User{ID: missing}), ErrUserNotFound)
+	assert.ErrorIs(t, repo.UpdateUserDisplayName(t.Context(), missing, "x"), ErrUserNotFound)
+	assert.ErrorIs(t, repo.LinkUserToAuthID(t.Context(), missing, "x"), ErrUserNotFound)
+
+	require.NoError(t, repo.DeleteUser(t.Context(), user.ID))
+	_, err = repo.GetUser(t.Context(), user.ID)
+	assert.ErrorIs(t, err, ErrUserNotFound)
+	assert.ErrorIs(t, repo.DeleteUser(t.Context(), user.ID), ErrUserNotFound)
+
+	system, err := repo.GetUser(t.Context(), models.SystemUserID)
+	require.NoError(t, err, "the system user is always present")
+	assert.Equal(t, "system", system.Role)
+}
+
+func testFamilies(t *testing.T, repo Repository) {
+	f := newFixture(t, repo)
+
+	family, err := repo.GetFamily(t.Context(), f.family.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Test Family", family.Name)
+	assert.ElementsMatch(t, []string{f.parent.ID, f.child.ID}, family.Members)
+
+	require.NoError(t, repo.AddFamilyMember(t.Context(), f.family.ID, f.child.ID, "child"), "adding a member twice is a no-op")
+
+	family.Name = "Renamed"
+	require.NoError(t, repo.UpdateFamily(t.Context(), family))
+	family, err = repo.GetFamily(t.Context(), f.family.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", family.Name)
+
+	parent, err := repo.GetUser(t.Context(), f.parent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{f.child.ID}, parent.Children)
+
+	missing := uuid.NewString()
+	_, err = repo.GetFamily(t.Context(), missing)
+	assert.ErrorIs(t, err, ErrFamilyNotFound)
+	assert.ErrorIs(t, repo.UpdateFamily(t.Context(), &models.Family{ID: missing}), ErrFamilyNotFound)
+	assert.ErrorIs(t, repo.DeleteFamily(t.Context(), missing), ErrFamilyNotFound)
+}
+
+func testChildren(t *testing.T, repo Repository) {
+	f := newFixture(t, repo)
+
+	child, err := repo.GetChild(t.Context(), f.child.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "child", child.Role)
+	assert.Equal(t, f.parent.ID, *child.ParentID)
+
+	user, err := repo.GetUserByAuthID(t.Context(), f.child.ID)
+	require.NoError(t, err, "a new child's auth ID is its ID")
+	assert.Equal(t, f.child.ID, user.ID)
+
+	children, err := repo.GetFamilyChildren(t.Context(), f.family.ID)
+	require.NoError(t, err)
+	require.Len(t, children, 1)
+	assert.Equal(t, f.child.ID, children[0].ID)
+
+	birthYear := 2017
+	require.NoError(t, repo.UpdateChildBirthYear(t.Context(), f.child.ID, &birthYear))
+	require.NoError(t, repo.UpdateChildDisplayName(t.Context(), f.child.ID, "Åse"))
+	child, err = repo.GetChild(t.Context(), f.child.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2017, *child.BirthYear)
+	assert.Equal(t, "Åse", child.DisplayName)
+
+	_, err = repo.GetChild(t.Context(), f.parent.ID)
+	assert.ErrorIs(t, err, ErrChildNotFound, "parents are not children")
+	assert.ErrorIs(t, repo.DeleteChild(t.Context(), f.parent.ID), ErrChildNotFound)
+	assert.ErrorIs(t, repo.UpdateChildDisplayName(t.Context(), uuid.NewString(), "x"), ErrChildNotFound)
+
+	empty, err := repo.GetFamilyChildren(t.Context(), uuid.NewString())
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+
+	require.NoError(t, repo.DeleteChild(t.Context(), f.child.ID))
+	_, err = repo.GetChild(t.Context(), f.child.ID)
+	assert.ErrorIs(t, err, ErrChildNotFound)
+}
+
+func testWordSets(t *testing.T, repo Repository) {
+	f := newFixture(t, repo)
+	config := map[string]interface{}{"defaultMode": "keyboard"}
+
+	later := time.Now().Add(time.Minute).Truncate(time.Second)
+	ws := &models.WordSet{Name: "Uke 2", FamilyID: &f.family.ID, CreatedBy: f.parent.ID, Language: "no", TestConfiguration: &config, CreatedAt: later, UpdatedAt: later}
+	ws.Words = []wordEntry{
+		{Word: "sol", Definition: "stjerne", Audio: models.WordAudio{AudioURL: "https://example.com/sol.mp3"}},
+		{Word: "måne", Translations: []models.Translation{{Language: "en", Text: "moon"}}},
+	}
+	require.NoError(t, repo.CreateWordSet(t.Context(), ws))
+	config["defaultMode"] = "changed" // The repository keeps its own copy
+
+	got, err := repo.GetWordSet(t.Context(), ws.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "keyboard", (*got.TestConfiguration)["defaultMode"])
+	require.Len(t, got.Words, 2)
+	assert.Equal(t, "sol", got.Words[0].Audio.Word)
+	assert.Equal(t, "stjerne", got.Words[0].Definition)
+	assert.Equal(t, "moon", got.Words[1].Translations[0].Text)
+
+	require.NoError(t, repo.AssignWordSetToUser(t.Context(), ws.ID, f.child.ID, f.parent.ID))
+	sets, err := repo.GetWordSets(t.Context(), f.family.ID)
+	require.NoError(t, err)
+	require.Len(t, sets, 2)
+	assert.Equal(t, ws.ID, sets[0].ID, "newest word set first")
+	assert.Equal(t, []string{f.child.ID}, sets[0].AssignedUserIDs)
+
+	got.Name = "Uke 2b"
+	got.Words = got.Words[:1]
+	require.NoError(t, repo.UpdateWordSet(t.Context(), got))
+	got, err = repo.GetWordSet(t.Context(), ws.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Uke 2b", got.Name)
+	assert.Len(t, got.Words, 1)
+
+	isGlobal, err := repo.IsGlobalWordSet(t.Context(), ws.ID)
+	require.NoError(t, err)
+	assert.False(t, isGlobal)
+
+	missing := uuid.NewString()
+	_, err = repo.GetWordSet(t.Context(), missing)
+	assert.ErrorIs(t, err, ErrWordSetNotFound)
+	_, err = repo.IsGlobalWordSet(t.Context(), missing)
+	assert.ErrorIs(t, err, ErrWordSetNotFound)
+	assert.ErrorIs(t, repo.UpdateWordSet(t.Context(), &models.WordSet{ID: missing}), ErrWordSetNotFound)
+
+	require.NoError(t, repo.DeleteWordSet(t.Context(), ws.ID))
+	assert.ErrorIs(t, repo.DeleteWordSet(t.Context(), ws.ID), ErrWordSetNotFound)
+}
+
+func testTestResults(t *testing.T, repo Repository) {
+	f := newFixture(t, repo)
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	first := saveResult(t, repo, f.child.ID, f.wordSet.ID, base, answer("hus", true, 1), answer("katt", false, 3, "doubleConsonant"))
+	second := saveResult(t, repo, f.child.ID, f.wordSet.ID, base.Add(time.Minute), answer("hus", true, 1))
+
+	results, err := repo.GetTestResults(t.Context(), f.child.ID)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, second.ID, results[0].ID, "newest result first")
+	assert.Equal(t, first.ID, results[1].ID)
+	require.Len(t, results[1].Words, 2)
+	assert.Equal(t, []string{"doubleConsonant"}, results[1].Words[1].ErrorTypes)
+	assert.Equal(t, []string{}, results[1].Words[0].ErrorTypes)
+
+	family, err := repo.GetFamilyResults(t.Context(), f.family.ID)
+	require.NoError(t, err)
+	assert.Len(t, family, 2)
+
+	recent, err := repo.GetRecentWordSetResults(t.Context(), f.child.ID, f.wordSet.ID, 1)
+	require.NoError(t, err)
+	require.Len(t, recent, 1)
+	assert.Equal(t, second.ID, recent[0].ID)
+
+	keyed := &models.TestResult{WordSetID: f.wordSet.ID, UserID: f.child.ID, Mode: "keyboard", IdempotencyKey: "key-1", XPAwarded: 10, CompletedAt: base}
+	require.NoError(t, repo.SaveTestResult(t.Context(), keyed))
+	replay := &models.TestResult{WordSetID: f.wordSet.ID, UserID: f.child.ID, Mode: "keyboard", IdempotencyKey: "key-1", CompletedAt: base}
+	assert.ErrorIs(t, repo.SaveTestResult(t.Context(), replay), ErrDuplicate)
+
+	got, err := repo.GetTestResultByIdempotencyKey(t.Context(), f.child.ID, "key-1")
+	require.NoError(t, err)
+	assert.Equal(t, keyed.ID, got.ID)
+	assert.Equal(t, 10, got.XPAwarded)
+	_, err = repo.GetTestResultByIdempotencyKey(t.Context(), f.parent.ID, "key-1")
+	assert.ErrorIs(t, err, ErrNotFound, "idempotency keys are scoped per user")
+
+	completions, err := repo.GetRecentCompletions(t.Context(), f.child.ID, f.wordSet.ID, "keyboard", base, base.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 2, completions, "the window excludes its end")
+
+	firstTime, err := repo.IsFirstCompletion(t.Context(), f.child.ID, f.wordSet.ID, "wordBank")
+	require.NoError(t, err)
+	assert.True(t, firstTime)
+
+	inRange, err := repo.GetTestResultsInRange(t.Context(), f.child.ID, base, base.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, inRange, 3)
+	assert.Equal(t, second.ID, inRange[2].ID, "oldest result first")
+
+	none, err := repo.GetTestResults(t.Context(), f.parent.ID)
+	require.NoError(t, err)
+	assert.Empty(t, none)
+}
+
+func testListings(t *testing.T, repo Repository) {
+	f := newFixture(t, repo)
+	other := newFixture(t, repo)
+	ctx := t.Context()
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	// Five results, the last two completed at the same time so the ID breaks the tie
+	var saved []*models.TestResult
+	for i := range 5 {
+		at := base.Add(time.Duration(min(i, 3)) * time.Minute)
+		saved = append(saved, saveResult(t, repo, f.child.ID, f.wordSet.ID, at, answer("hus", i%2 == 0, 1)))
+	}
+	saveResult(t, repo, other.child.ID, other.wordSet.ID, base, answer("hus", true, 1))
+
+	var pages [][]models.TestResult
+	cursor := ""
+	for {
+		page, next, err := repo.ListTestResults(ctx, models.ResultFilter{FamilyID: f.family.ID}, models.PageRequest{Cursor: cursor, Limit: 2})
+		require.NoError(t, err)
+		pages = append(pages, page)
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	require.Len(t, pages, 3)
+	var listed []string
+	for _, page := range pages {
+		for _, r := range page {
+			listed = append(listed, r.ID)
+			assert.Len(t, r.Words, 1, "word results are loaded with each page")
+		}
+	}
+	require.Len(t, listed, 5, "pages neither skip nor repeat results")
+	assert.ElementsMatch(t, []string{saved[3].ID, saved[4].ID}, listed[:2], "the newest results come first")
+	assert.Equal(t, saved[0].ID, listed[4])
+
+	all, next, err := repo.ListTestResults(ctx, models.ResultFilter{UserID: f.child.ID}, models.PageRequest{})
+	require.NoError(t, err)
+	assert.Len(t, all, 5, "without a limit the whole listing is returned")
+	assert.Empty(t, next)
+
+	minScore := 100.0
+	passed, _, err := repo.ListTestResults(ctx, models.ResultFilter{UserID: f.child.ID, MinScore: &minScore}, models.PageRequest{})
+	require.NoError(t, err)
+	assert.Len(t, passed, 3)
+
+	from, to := base.Add(time.Minute), base.Add(3*time.Minute)
+	window, _, err := repo.ListTestResults(ctx, models.ResultFilter{UserID: f.child.ID, From: &from, To: &to}, models.PageRequest{})
+	require.NoError(t, err)
+	require.Len(t, window, 2, "the range excludes its end")
+	assert.Equal(t, saved[2].ID, window[0].ID)
+
+	none, _, err := repo.ListTestResults(ctx, models.ResultFilter{FamilyID: f.family.ID, UserID: other.child.ID}, models.PageRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, none, "a child filter cannot leave the family")
+	none, _, err = repo.ListTestResults(ctx, models.ResultFilter{UserID: f.child.ID, Mode: "wordBank"}, models.PageRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, none)
+
+	_, _, err = repo.ListTestResults(ctx, models.ResultFilter{UserID: f.child.ID}, models.PageRequest{Cursor: "not a cursor", Limit: 2})
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+
+	grade := models.GradeLevel34
+	graded := newWordSet(t, repo, f.family.ID, f.parent.ID, "Uke 2: dobbel_konsonant", "katt")
+	graded.TargetGrade = &grade
+	require.NoError(t, repo.UpdateWordSet(ctx, graded))
+	newWordSet(t, repo, f.family.ID, f.parent.ID, "Uke 3", "sol")
+
+	sets, next, err := repo.ListWordSets(ctx, f.family.ID, models.WordSetFilter{}, models.PageRequest{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, sets, 2)
+	require.NotEmpty(t, next)
+	rest, next, err := repo.ListWordSets(ctx, f.family.ID, models.WordSetFilter{}, models.PageRequest{Cursor: next, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, rest, 1)
+	assert.Empty(t, next)
+	assert.NotContains(t, []string{sets[0].ID, sets[1].ID}, rest[0].ID)
+
+	found, _, err := repo.ListWordSets(ctx, f.family.ID, models.WordSetFilter{Search: "UKE 2"}, models.PageRequest{})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, graded.ID, found[0].ID)
+	found, _, err = repo.ListWordSets(ctx, f.family.ID, models.WordSetFilter{Search: "_"}, models.PageRequest{})
+	require.NoError(t, err)
+	assert.Len(t, found, 1, "search terms are matched literally")
+	found, _, err = repo.ListWordSets(ctx, f.family.ID, models.WordSetFilter{Grade: grade, Language: "no"}, models.PageRequest{})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, graded.ID, found[0].ID)
+	found, _, err = repo.ListWordSets(ctx, f.family.ID, models.WordSetFilter{Difficulty: models.DifficultyAdvanced}, models.PageRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, found)
+}
+
+func testAssignments(t *testing.T, repo Repository) {
+	f := newFixture(t, repo)
+
+	assert.Error(t, repo.AssignWordSetToUser(t.Context(), f.wordSet.ID, f.parent.ID, f.parent.ID), "only children can be assigned")
+	require.NoError(t, repo.AssignWordSetToUser(t.Context(), f.wordSet.ID, f.child.ID, f.parent.ID))
+	require.NoError(t, repo.AssignWordSetToUser(t.Context(), f.wordSet.ID, f.child.ID, f.parent.ID), "assigning twice is a no-op")
+
+	assigned, err := repo.GetWordSetAssignments(t.Context(), f.wordSet.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{f.child.ID}, assigned)
+
+	due := time.Now().Add(48 * time.Hour).Truncate(time.Second)
+	schedule := &models.AssignmentSchedule{DueAt: &due, ReminderFrequency: models.ReminderDaily, ReminderHour: 18}
+	require.NoError(t, repo.SetWordSetAssignmentSchedule(t.Context(), f.wordSet.ID, f.child.ID, schedule))
+	assert.ErrorIs(t, repo.SetWordSetAssignmentSchedule(t.Context(), f.wordSet.ID, f.parent.ID, schedule), ErrNotFound)
+
+	assignments, err := repo.GetUserAssignments(t.Context(), f.child.ID)
+	require.NoError(t, err)
+	require.Len(t, assignments, 1)
+	assert.Equal(t, "Uke 1", assignments[0].WordSetName)
+	assert.Equal(t, f.family.ID, assignments[0].FamilyID)
+	assert.True(t, due.Equal(*assignments[0].DueAt))
+	assert.Equal(t, 18, assignments[0].ReminderHour)
+
+	reminders, err := repo.GetReminderAssignments(t.Context())
+	require.NoError(t, err)
+	var found bool
+	for _, a := range reminders {
+		found = found || a.UserID == f.child.ID && a.WordSetID == f.wordSet.ID
+	}
+	assert.True(t, found, "a daily reminder before the due date is listed")
+
+	slot := time.Now().Add(-time.Hour)
+	claimed, err := repo.ClaimAssignmentReminder(t.Context(), f.wordSet.ID, f.child.ID, slot, time.Now())
+	require.NoError(t, err)
+	assert.True(t, claimed)
+	claimed, err = repo.ClaimAssignmentReminder(t.Context(), f.wordSet.ID, f.child.ID, slot, time.Now())
+	require.NoError(t, err)
+	assert.False(t, claimed, "a reminder slot is claimed once")
+
+	require.NoError(t, repo.UnassignWordSetFromUser(t.Context(), f.wordSet.ID, f.child.ID))
+	assert.Error(t, repo.UnassignWordSetFromUser(t.Context(), f.wordSet.ID, f.child.ID))
+
+	empty, err := repo.GetUserAssignments(t.Context(), f.child.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, empty)
+	assert.Empty(t, empty)
+}
+
+func testMasteryAndXP(t *testing.T, repo Repository) {
+	f := newFixture(t, repo)
+
+	mastery, err := repo.GetWordMastery(t.Context(), f.child.ID, f.wordSet.ID, "hus")
+	require.NoError(t, err)
+	assert.Nil(t, mastery, "no mastery before the first correct answer")
+
+	_, err = repo.IncrementMastery(t.Context(), f.child.ID, f.wordSet.ID, "hus", models.TestModeKeyboard)
+	require.NoError(t, err)
+	mastery, err = repo.IncrementMastery(t.Context(), f.child.ID, f.wordSet.ID, "hus", models.TestModeKeyboard)
+	require.NoError(t, err)
+	assert.Equal(t, 2, mastery.KeyboardCorrect)
+	_, err = repo.IncrementMastery(t.Context(), f.child.ID, f.wordSet.ID, "hus", models.TestModeFlashcard)
+	assert.Error(t, err, "flashcards are self-reported")
+
+	all, err := repo.GetWordSetMastery(t.Context(), f.child.ID, f.wordSet.ID)
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+
+	progress, err := repo.GetFamilyProgress(t.Context(), f.family.ID)
+	require.NoError(t, err)
+	require.Len(t, progress, 2)
+	for _, p := range progress {
+		if p.UserID == f.child.ID {
+			assert.Equal(t, 1, p.KeyboardMasteredWords)
+			assert.Equal(t, 1, p.TotalWordsWithMastery)
+		}
+	}
+
+	require.NoError(t, repo.UpdateUserXP(t.Context(), f.child.ID, 50, 150, 2))
+	xp, level, err := repo.GetUserXP(t.Context(), f.child.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 150, xp)
+	assert.Equal(t, 2, level)
+	_, _, err = repo.GetUserXP(t.Context(), uuid.NewString())
+	assert.ErrorIs(t, err, ErrUserNotFound)
+
+	stats, err := repo.GetFamilyStats(t.Context(), f.family.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.TotalChildren)
+	assert.Equal(t, 2, stats.TotalMembers)
+	assert.Equal(t, 1, stats.TotalWordSets)
+	require.NotNil(t, stats.MostActiveChild)
+	assert.Equal(t, f.child.ID, *stats.MostActiveChild)
+}
+
+func testInvitations(t *testing.T, repo Repository) {
+	f := newFixture(t, repo)
+	invitee := newUser(t, repo, "parent")
+
+	invitation := &models.FamilyInvitation{
+		ID:        uuid.NewString(),
+		FamilyID:  f.family.ID,
+		Email:     invitee.Email,
+		Role:      "parent",
+		InvitedBy: f.parent.ID,
+		Status:    "pending",
+		CreatedAt: time.Now().Truncate(time.Second),
+	}
+	require.NoError(t, repo.CreateFamilyInvitation(t.Context(), invitation))
+
+	again := *invitation
+	again.ID = uuid.NewString()
+	assert.Error(t, repo.CreateFamilyInvitation(t.Context(), &again), "one pending invitation per email and family")
+
+	pending, err := repo.GetPendingInvitationsByEmail(t.Context(), invitee.Email)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, "Test Family", pending[0].FamilyName)
+
+	require.NoError(t, repo.AcceptInvitation(t.Context(), invitation.ID, invitee.ID))
+	assert.Error(t, repo.AcceptInvitation(t.Context(), invitation.ID, invitee.ID), "an invitation is accepted once")
+	require.NoError(t, repo.VerifyParentPermission(t.Context(), invitee.ID, f.family.ID))
+
+	user, err := repo.GetUser(t.Context(), invitee.ID)
+	require.NoError(t, err)
+	assert.Equal(t, f.family.ID, user.FamilyID)
+
+	invitations, err := repo.GetFamilyInvitations(t.Context(), f.family.ID)
+	require.NoError(t, err)
+	assert.Empty(t, invitations)
+	assert.Error(t, repo.DeleteInvitation(t.Context(), invitation.ID))
+}
+
+func testVerification(t *testing.T, repo Repository) {
+	f := newFixture(t, repo)
+	other := newFixture(t, repo)
+
+	assert.NoError(t, repo.VerifyFamilyMembership(t.Context(), f.child.ID, f.family.ID))
+	assert.ErrorIs(t, repo.VerifyFamilyMembership(t.Context(), other.child.ID, f.family.ID), ErrNotFamilyMember)
+
+	assert.NoError(t, repo.VerifyParentPermission(t.Context(), f.parent.ID, f.family.ID))
+	assert.ErrorIs(t, repo.VerifyParentPermission(t.Context(), f.child.ID, f.family.ID), ErrNotParent)
+	assert.ErrorIs(t, repo.VerifyParentPermission(t.Context(), other.parent.ID, f.family.ID), ErrNotParent)
+
+	assert.NoError(t, repo.VerifyChildOwnership(t.Context(), f.parent.ID, f.child.ID))
+	assert.ErrorIs(t, repo.VerifyChildOwnership(t.Context(), other.parent.ID, f.child.ID), ErrNotChildOwner)
+
+	assert.NoError(t, repo.VerifyWordSetAccess(t.Context(), f.family.ID, f.wordSet.ID))
+	assert.ErrorIs(t, repo.VerifyWordSetAccess(t.Context(), other.family.ID, f.wordSet.ID), ErrNoAccess)
+	assert.ErrorIs(t, repo.VerifyWordSetAccess(t.Context(), f.family.ID, uuid.NewString()), ErrNoAccess)
+}
+
+func testAPIKeysAndWebhooks(t *testing.T, repo Repository) {
+	f := newFixture(t, repo)
+	other := newFixture(t, repo)
+
+	key := &models.FamilyAPIKey{FamilyID: f.family.ID, Name: "MCP", KeyPrefix: "dk_", KeyHash: uuid.NewString(), CreatedBy: f.parent.ID}
+	require.NoError(t, repo.CreateFamilyAPIKey(t.Context(), key))
+	got, err := repo.GetFamilyAPIKeyByHash(t.Context(), key.KeyHash)
+	require.NoError(t, err)
+	assert.Equal(t, key.ID, got.ID)
+	require.NoError(t, repo.TouchFamilyAPIKey(t.Context(), key.ID))
+
+	keys, err := repo.GetFamilyAPIKeys(t.Context(), f.family.ID)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Empty(t, keys[0].KeyHash, "listings never include the hash")
+	assert.NotNil(t, keys[0].LastUsedAt)
+	assert.ErrorIs(t, repo.DeleteFamilyAPIKey(t.Context(), other.family.ID, key.ID), ErrNotFound)
+	require.NoError(t, repo.DeleteFamilyAPIKey(t.Context(), f.family.ID, key.ID))
+
+	all := &models.Webhook{FamilyID: f.family.ID, URL: "https://example.com/all", Secret: "s", CreatedBy: f.parent.ID, IsActive: true}
+	results := &models.Webhook{FamilyID: f.family.ID, URL: "https://example.com/results", Secret: "s", CreatedBy: f.parent.ID, IsActive: true, EventTypes: []string{"test.completed"}}
+	require.NoError(t, repo.CreateWebhook(t.Context(), all))
+	require.NoError(t, repo.CreateWebhook(t.Context(), results))
+
+	eventID := uuid.NewString()
+	n, err := repo.EnqueueWebhookDeliveries(t.Context(), f.family.ID, eventID, "wordset.created", []byte(`{}`))
+	require.NoError(t, err)
+	assert.Equal(t, 1, n, "only subscribed webhooks get a delivery")
+	n, err = repo.EnqueueWebhookDeliveries(t.Context(), f.family.ID, eventID, "wordset.created", []byte(`{}`))
+	require.NoError(t, err)
+	assert.Equal(t, 0, n, "an event is enqueued once per webhook")
+
+	claimed, err := repo.ClaimWebhookDeliveries(t.Context(), 100, time.Minute)
+	require.NoError(t, err)
+	var delivery *models.WebhookDelivery
+	for i := range claimed {
+		if claimed[i].EventID == eventID {
+			delivery = &claimed[i]
+		}
+	}
+	require.NotNil(t, delivery)
+	assert.Equal(t, all.URL, delivery.URL)
+
+	again, err := repo.ClaimWebhookDeliveries(t.Context(), 100, time.Minute)
+	require.NoError(t, err)
+	for _, d := range again {
+		assert.NotEqual(t, delivery.ID, d.ID, "claimed deliveries are leased")
+	}
+
+	now := time.Now()
+	delivery.Status = models.WebhookDeliveryDelivered
+	delivery.Attempts = 1
+	delivery.DeliveredAt = &now
+	require.NoError(t, repo.UpdateWebhookDelivery(t.Context(), delivery))
+
+	deliveries, err := repo.GetWebhookDeliveries(t.Context(), f.family.ID, all.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, models.WebhookDeliveryDelivered, deliveries[0].Status)
+	_, err = repo.GetWebhookDeliveries(t.Context(), other.family.ID, all.ID, 10)
+	assert.ErrorIs(t, err, ErrNotFound, "deliveries are scoped to the family")
+
+	webhooks, err := repo.GetFamilyWebhooks(t.Context(), f.family.ID)
+	require.NoError(t, err)
+	require.Len(t, webhooks, 2)
+	assert.Empty(t, webhooks[0].Secret)
+	assert.ErrorIs(t, repo.DeleteWebhook(t.Context(), other.family.ID, all.ID), ErrNotFound)
+	require.NoError(t, repo.DeleteWebhook(t.Context(), f.family.ID, all.ID))
+}
+
+func testNotifications(t *testing.T, repo Repository) {
+	f := newFixture(t, repo)
+	base := time.Now().Add(-time.Hour).Truncate(time.Second).UTC()
+
+	notifications := []models.Notification{
+		{UserID: f.parent.ID, FamilyID: f.family.ID, SubjectUserID: f.child.ID, Kind: "test.completed", MessageKey: "a", CreatedAt: base},
+		{UserID: f.parent.ID, FamilyID: f.family.ID, Kind: "test.completed", MessageKey: "b", CreatedAt: base.Add(time.Minute), Params: map[string]any{"score": 90}},
+	}
+	require.NoError(t, repo.CreateNotifications(t.Context(), notifications))
+	assert.NotEmpty(t, notifications[0].ID, "IDs are assigned in place")
+
+	inbox, err := repo.GetNotifications(t.Context(), f.parent.ID, false, 10)
+	require.NoError(t, err)
+	require.Len(t, inbox, 2)
+	assert.Equal(t, "b", inbox[0].MessageKey, "newest first")
+	assert.Equal(t, float64(90), inbox[0].Params["score"])
+	assert.Equal(t, map[string]any{}, inbox[1].Params)
+
+	n, err := repo.MarkNotificationsRead(t.Context(), f.parent.ID, []string{notifications[0].ID}, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	unread, err := repo.CountUnreadNotifications(t.Context(), f.parent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, unread)
+
+	n, err = repo.MarkNotificationsRead(t.Context(), f.parent.ID, nil, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n, "already read notifications are not counted")
+
+	n, err = repo.DismissNotifications(t.Context(), f.child.ID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n, "dismissing is scoped to the recipient")
+	n, err = repo.DismissNotifications(t.Context(), f.parent.ID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+}
+
+func testTestSessions(t *testing.T, repo Repository) {
+	f := newFixture(t, repo)
+	now := time.Now().Truncate(time.Second)
+
+	session := &models.TestSession{
+		ID:             uuid.NewString(),
+		UserID:         f.child.ID,
+		FamilyID:       f.family.ID,
+		WordSetID:      f.wordSet.ID,
+		Mode:           "keyboard",
+		Status:         models.TestSessionActive,
+		Words:          []models.SessionWord{{Word: "hus", ExpectedAnswer: "hus", Answers: []string{}}},
+		MaxAttempts:    3,
+		StartedAt:      now,
+		LastActivityAt: now,
+		ExpiresAt:      now.Add(time.Hour),
+	}
+	require.NoError(t, repo.CreateTestSession(t.Context(), session))
+
+	_, err := repo.GetTestSession(t.Context(), f.parent.ID, session.ID)
+	assert.ErrorIs(t, err, ErrNotFound, "sessions are scoped to their user")
+
+	stale, err := repo.GetTestSession(t.Context(), f.child.ID, session.ID)
+	require.NoError(t, err)
+
+	session.Words[0].Answers = []string{"hus"}
+	session.CurrentIndex = 1
+	require.NoError(t, repo.UpdateTestSession(t.Context(), session))
+	assert.Equal(t, 1, session.Version)
+
+	stale.CurrentIndex = 1
+	assert.ErrorIs(t, repo.UpdateTestSession(t.Context(), stale), ErrConflict, "a stale version is rejected")
+	assert.ErrorIs(t, repo.UpdateTestSession(t.Context(), &models.TestSession{ID: uuid.NewString()}), ErrNotFound)
+
+	got, err := repo.GetTestSession(t.Context(), f.child.ID, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"hus"}, got.Words[0].Answers)
+
+	active, err := repo.GetActiveTestSessions(t.Context(), f.child.ID, now)
+	require.NoError(t, err)
+	assert.Len(t, active, 1)
+	active, err = repo.GetActiveTestSessions(t.Context(), f.child.ID, now.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, active)
+
+	deleted, err := repo.DeleteExpiredTestSessions(t.Context(), now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, deleted, 1)
+	_, err = repo.GetTestSession(t.Context(), f.child.ID, session.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func testAnalytics(t *testing.T, repo Repository) {
+	f := newFixture(t, repo)
+	// Monday 2 March 2026, so the results fall in two ISO weeks
+	monday := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+
+	saveResult(t, repo, f.child.ID, f.wordSet.ID, monday.AddDate(0, 0, -7), answer("katt", true, 1), answer("hus", true, 1))
+	saveResult(t, repo, f.child.ID, f.wordSet.ID, monday.AddDate(0, 0, -6), answer("katt", true, 1))
+	saveResult(t, repo, f.child.ID, f.wordSet.ID, monday, answer("katt", false, 3, "doubleConsonant"), answer("hus", false, 2, "silentLetter"))
+	saveResult(t, repo, f.child.ID, f.wordSet.ID, monday.AddDate(0, 0, 1), answer("katt", false, 3, "doubleConsonant", "vowel"))
+
+	from, to := monday.AddDate(0, 0, -14), monday.AddDate(0, 0, 7)
+	missed, err := repo.GetMissedWords(t.Context(), f.child.ID, from, to, 10)
+	require.NoError(t, err)
+	require.Len(t, missed, 2)
+	assert.Equal(t, "katt", missed[0].Word)
+	assert.Equal(t, 2, missed[0].Misses)
+	assert.Equal(t, 4, missed[0].Tests)
+	assert.Equal(t, []string{"doubleConsonant", "vowel"}, missed[0].ErrorTypes)
+	assert.True(t, monday.AddDate(0, 0, 1).Equal(missed[0].LastMissedAt))
+
+	counts, err := repo.GetErrorTypeCounts(t.Context(), f.child.ID, from, to, models.AnalyticsBucketWeek, []string{"doubleConsonant", "vowel"})
+	require.NoError(t, err)
+	require.Len(t, counts, 2)
+	assert.True(t, monday.Truncate(24*time.Hour).Equal(counts[0].BucketStart))
+	assert.Equal(t, "doubleConsonant", counts[0].ErrorType)
+	assert.Equal(t, 2, counts[0].Count)
+
+	attempts, err := repo.GetWordAttemptStats(t.Context(), f.child.ID, from, to, 1)
+	require.NoError(t, err)
+	require.Len(t, attempts, 1)
+	assert.Equal(t, "katt", attempts[0].Word)
+	assert.InDelta(t, 2.0, attempts[0].AvgAttempts, 0.001)
+	assert.Equal(t, 2, attempts[0].FirstTryCorrect)
+
+	regressed, err := repo.GetRegressedWords(t.Context(), f.child.ID, monday, to, 2, 10)
+	require.NoError(t, err)
+	require.Len(t, regressed, 1, "hus was only mastered once")
+	assert.Equal(t, "katt", regressed[0].Word)
+	assert.Equal(t, 2, regressed[0].Misses)
+	assert.Equal(t, 2, regressed[0].PriorCorrect)
+	assert.True(t, monday.AddDate(0, 0, -6).Equal(regressed[0].LastMasteredAt))
+
+	none, err := repo.GetMissedWords(t.Context(), f.parent.ID, from, to, 10)
+	require.NoError(t, err)
+	assert.NotNil(t, none)
+	assert.Empty(t, none)
+}
+
+func testPracticeListsAndLocks(t *testing.T, repo Repository) {
+	f := newFixture(t, repo)
+
+	_, err := repo.GetPracticeList(t.Context(), f.child.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	list := &models.PracticeList{ChildID: f.child.ID, FamilyID: f.family.ID, WordSetID: f.wordSet.ID, CreatedBy: f.parent.ID, WindowDays: 14, MaxWords: 15}
+	require.NoError(t, repo.SavePracticeList(t.Context(), list))
+	assert.False(t, list.CreatedAt.IsZero())
+
+	list.MaxWords = 10
+	require.NoError(t, repo.SavePracticeList(t.Context(), list))
+	got, err := repo.GetPracticeList(t.Context(), f.child.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 10, got.MaxWords)
+
+	_, err = repo.GetChallengeLock(t.Context(), f.wordSet.ID, f.child.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+	lock := &models.ChallengeLock{WordSetID: f.wordSet.ID, ChildID: f.child.ID, LockedBy: f.parent.ID, Seed: 42}
+	require.NoError(t, repo.SaveChallengeLock(t.Context(), lock))
+	lock.Seed = 7
+	require.NoError(t, repo.SaveChallengeLock(t.Context(), lock))
+	gotLock, err := repo.GetChallengeLock(t.Context(), f.wordSet.ID, f.child.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), gotLock.Seed)
+	require.NoError(t, repo.DeleteChallengeLock(t.Context(), f.wordSet.ID, f.child.ID))
+	assert.ErrorIs(t, repo.DeleteChallengeLock(t.Context(), f.wordSet.ID, f.child.ID), ErrNotFound)
+}
+
+func testCascades(t *testing.T, repo Repository) {
+	f := newFixture(t, repo)
+	saveResult(t, repo, f.child.ID, f.wordSet.ID, time.Now().Truncate(time.Second), answer("hus", true, 1))
+	require.NoError(t, repo.AssignWordSetToUser(t.Context(), f.wordSet.ID, f.child.ID, f.parent.ID))
+	_, err := repo.IncrementMastery(t.Context(), f.child.ID, f.wordSet.ID, "hus", models.TestModeKeyboard)
+	require.NoError(t, err)
+	require.NoError(t, repo.SaveChallengeLock(t.Context(), &models.ChallengeLock{WordSetID: f.wordSet.ID, ChildID: f.child.ID, LockedBy: f.parent.ID, Seed: 1}))
+	require.NoError(t, repo.CreateNotifications(t.Context(), []models.Notification{
+		{UserID: f.parent.ID, FamilyID: f.family.ID, SubjectUserID: f.child.ID, Kind: "test.completed", MessageKey: "a"},
+	}))
+
+	// Deleting a word set removes its results, assignments, mastery and locks
+	require.NoError(t, repo.DeleteWordSet(t.Context(), f.wordSet.ID))
+	results, err := repo.GetTestResults(t.Context(), f.child.ID)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+	assignments, err := repo.GetUserAssignments(t.Context(), f.child.ID)
+	require.NoError(t, err)
+	assert.Empty(t, assignments)
+	mastery, err := repo.GetWordSetMastery(t.Context(), f.child.ID, f.wordSet.ID)
+	require.NoError(t, err)
+	assert.Empty(t, mastery)
+	_, err = repo.GetChallengeLock(t.Context(), f.wordSet.ID, f.child.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// Deleting a child keeps notifications about them for the parent
+	require.NoError(t, repo.DeleteChild(t.Context(), f.child.ID))
+	inbox, err := repo.GetNotifications(t.Context(), f.parent.ID, false, 10)
+	require.NoError(t, err)
+	require.Len(t, inbox, 1)
+	assert.Empty(t, inbox[0].SubjectUserID)
+	assert.ErrorIs(t, repo.VerifyFamilyMembership(t.Context(), f.child.ID, f.family.ID), ErrNotFamilyMember)
+
+	// Deleting a family detaches its users and removes everything it owns
+	wordSet := newWordSet(t, repo, f.family.ID, f.parent.ID, "Uke 3", "sol")
+	require.NoError(t, repo.DeleteFamily(t.Context(), f.family.ID))
+	_, err = repo.GetWordSet(t.Context(), wordSet.ID)
+	assert.ErrorIs(t, err, ErrWordSetNotFound)
+	parent, err := repo.GetUser(t.Context(), f.parent.ID)
+	require.NoError(t, err)
+	assert.Empty(t, parent.FamilyID)
+	inbox, err = repo.GetNotifications(t.Context(), f.parent.ID, false, 10)
+	require.NoError(t, err)
+	assert.Empty(t, inbox)
+}
+
+func testForeignKeys(t *testing.T, repo Repository) {
+	f := newFixture(t, repo)
+	missing := uuid.NewString()
+	now := time.Now().Truncate(time.Second)
+
+	// Inserts referencing a missing row are rejected and store nothing
+	err := repo.CreateFamily(t.Context(), &models.Family{Name: "Orphan", CreatedBy: missing, CreatedAt: now, UpdatedAt: now})
+	assert.ErrorIs(t, err, ErrMissingReference)
+
+	assert.ErrorIs(t, repo.AddFamilyMember(t.Context(), missing, f.parent.ID, "parent"), ErrMissingReference)
+	assert.ErrorIs(t, repo.AddFamilyMember(t.Context(), f.family.ID, missing, "parent"), ErrMissingReference)
+
+	child := &models.ChildAccount{Email: uuid.NewString() + "@example.com", DisplayName: "Orphan", FamilyID: missing, ParentID: &f.parent.ID, IsActive: true, CreatedAt: now, LastActiveAt: now}
+	assert.ErrorIs(t, repo.CreateChild(t.Context(), child), ErrMissingReference)
+	_, err = repo.GetUser(t.Context(), child.ID)
+	assert.ErrorIs(t, err, ErrUserNotFound)
+
+	ws := &models.WordSet{Name: "Orphan", FamilyID: &missing, CreatedBy: f.parent.ID, Language: "no", CreatedAt: now, UpdatedAt: now}
+	assert.ErrorIs(t, repo.CreateWordSet(t.Context(), ws), ErrMissingReference)
+	_, err = repo.GetWordSet(t.Context(), ws.ID)
+	assert.ErrorIs(t, err, ErrWordSetNotFound)
+
+	result := &models.TestResult{WordSetID: missing, UserID: f.child.ID, Mode: "keyboard", CompletedAt: now}
+	assert.ErrorIs(t, repo.SaveTestResult(t.Context(), result), ErrMissingReference)
+	results, err := repo.GetTestResults(t.Context(), f.child.ID)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	assert.ErrorIs(t, repo.AssignWordSetToUser(t.Context(), missing, f.child.ID, f.parent.ID), ErrMissingReference)
+	assignments, err := repo.GetUserAssignments(t.Context(), f.child.ID)
+	require.NoError(t, err)
+	assert.Empty(t, assignments)
+
+	_, err = repo.IncrementMastery(t.Context(), f.child.ID, missing, "hus", models.TestModeKeyboard)
+	assert.ErrorIs(t, err, ErrMissingReference)
+
+	lock := &models.ChallengeLock{WordSetID: f.wordSet.ID, ChildID: missing, LockedBy: f.parent.ID, Seed: 1}
+	assert.ErrorIs(t, repo.SaveChallengeLock(t.Context(), lock), ErrMissingReference)
+
+	notifications := []models.Notification{
+		{UserID: f.parent.ID, FamilyID: f.family.ID, Kind: "test.completed", MessageKey: "a"},
+		{UserID: missing, FamilyID: f.family.ID, Kind: "test.completed", MessageKey: "a"},
+	}
+	assert.ErrorIs(t, repo.CreateNotifications(t.Context(), notifications), ErrMissingReference)
+	inbox, err := repo.GetNotifications(t.Context(), f.parent.ID, false, 10)
+	require.NoError(t, err)
+	assert.Empty(t, inbox, "a batch with an orphan is stored or rejected as a whole")
+
+	session := &models.TestSession{
+		ID: uuid.NewString(), UserID: f.child.ID, FamilyID: f.family.ID, WordSetID: missing,
+		Mode: "keyboard", Status: models.TestSessionActive, Words: []models.SessionWord{},
+		MaxAttempts: 3, StartedAt: now, LastActivityAt: now, ExpiresAt: now.Add(time.Hour),
+	}
+	assert.ErrorIs(t, repo.CreateTestSession(t.Context(), session), ErrMissingReference)
+
+	prefs := &models.DigestPreferences{UserID: missing, Enabled: true}
+	assert.ErrorIs(t, repo.UpsertDigestPreferences(t.Context(), prefs), ErrMissingReference)
+}
+
+func testConcurrentWrites(t *testing.T, repo Repository) {
+	f := newFixture(t, repo)
+
+	const writers = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := range writers {
+		wg.Go(func() {
+			if _, err := repo.IncrementMastery(t.Context(), f.child.ID, f.wordSet.ID, "hus", models.TestModeKeyboard); err != nil {
+				errs <- err
+			}
+			result := &models.TestResult{WordSetID: f.wordSet.ID, UserID: f.child.ID, Mode: "keyboard", IdempotencyKey: fmt.Sprintf("key-%d", i%10), CompletedAt: time.Now()}
+			if err := repo.SaveTestResult(t.Context(), result); err != nil && !errors.Is(err, ErrDuplicate) {
+				errs <- err
+			}
+		})
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	mastery, err := repo.GetWordMastery(t.Context(), f.child.ID, f.wordSet.ID, "hus")
+	require.NoError(t, err)
+	assert.Equal(t, writers, mastery.KeyboardCorrect, "no increment is lost")
+
+	results, err := repo.GetTestResults(t.Context(), f.child.ID)
+	require.NoError(t, err)
+	assert.Len(t, results, 10, "each idempotency key is saved once")
+
+	// Concurrent digest schedulers claim each recipient once
+	require.NoError(t, repo.UpsertDigestPreferences(t.Context(), &models.DigestPreferences{UserID: f.parent.ID, Enabled: true, Language: "no", UpdatedAt: time.Now()}))
+	claimedAt := time.Now().Truncate(time.Second)
+	var claims atomic.Int32
+	for range writers {
+		wg.Go(func() {
+			recipients, err := repo.ClaimDueDigestRecipients(t.Context(), claimedAt, claimedAt)
+			assert.NoError(t, err)
+			for _, r := range recipients {
+				if r.UserID == f.parent.ID {
+					claims.Add(1)
+				}
+			}
+		})
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), claims.Load(), "a due parent is claimed by one scheduler")
+
+	// Releasing restores the previous send time so the digest is due again
+	require.NoError(t, repo.ReleaseDigestClaim(t.Context(), f.parent.ID, claimedAt, nil))
+	due, err := repo.ClaimDueDigestRecipients(t.Context(), claimedAt, claimedAt.Add(time.Second))
+	require.NoError(t, err)
+	i := slices.IndexFunc(due, func(r models.DigestRecipient) bool { return r.UserID == f.parent.ID })
+	require.GreaterOrEqual(t, i, 0)
+	assert.Nil(t, due[i].LastSentAt, "the claim returns the restored send time")
+}
+
+func testTransactions(t *testing.T, repo Repository) {
+	f := newFixture(t, repo)
+	errInjected := errors.New("injected failure")
+	result := func() *models.TestResult {
+		return &models.TestResult{WordSetID: f.wordSet.ID, UserID: f.child.ID, Mode: "keyboard", CompletedAt: time.Now().Truncate(time.Second)}
+	}
+	assertState := func(xp, results int) {
+		t.Helper()
+		total, _, err := repo.GetUserXP(t.Context(), f.child.ID)
+		require.NoError(t, err)
+		assert.Equal(t, xp, total)
+		saved, err := repo.GetTestResults(t.Context(), f.child.ID)
+		require.NoError(t, err)
+		assert.Len(t, saved, results)
+	}
+
+	// A failed callback rolls back every write, including ones it could read back
+	err := repo.WithTx(t.Context(), func(tx Repository) error {
+		require.NoError(t, tx.UpdateUserXP(t.Context(), f.child.ID, 10, 10, 1))
+		require.NoError(t, tx.SaveTestResult(t.Context(), result()))
+		total, _, err := tx.GetUserXP(t.Context(), f.child.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 10, total)
+		return errInjected
+	})
+	assert.ErrorIs(t, err, errInjected)
+	assertState(0, 0)
+
+	// A failing operation inside the transaction undoes the ones before it
+	first := result()
+	first.IdempotencyKey = "tx-key"
+	require.NoError(t, repo.SaveTestResult(t.Context(), first))
+	err = repo.WithTx(t.Context(), func(tx Repository) error {
+		if err := tx.UpdateUserXP(t.Context(), f.child.ID, 10, 10, 1); err != nil {
+			return err
+		}
+		duplicate := result()
+		duplicate.IdempotencyKey = "tx-key"
+		return tx.SaveTestResult(t.Context(), duplicate)
+	})
+	assert.ErrorIs(t, err, ErrDuplicate)
+	assertState(0, 1)
+
+	// A failed nested transaction only rolls back its own writes
+	err = repo.WithTx(t.Context(), func(tx Repository) error {
+		if err := tx.SaveTestResult(t.Context(), result()); err != nil {
+			return err
+		}
+		nested := tx.WithTx(t.Context(), func(inner Repository) error {
+			require.NoError(t, inner.UpdateUserXP(t.Context(), f.child.ID, 10, 10, 1))
+			return errInjected
+		})
+		assert.ErrorIs(t, nested, errInjected)
+		return nil
+	})
+	require.NoError(t, err)
+	assertState(0, 2)
+
+	// A successful callback commits
+	require.NoError(t, repo.WithTx(t.Context(), func(tx Repository) error {
+		if err := tx.UpdateUserXP(t.Context(), f.child.ID, 25, 25, 1); err != nil {
+			return err
+		}
+		return tx.SaveTestResult(t.Context(), result())
+	}))
+	assertState(25, 3)
+}
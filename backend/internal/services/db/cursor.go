@@ -0,0 +1,62 @@
+package db
+
+import (
+	"encoding/base64"
+	"strings"
+	"time"
+)
+
+// pageCursor is the position of the last row of a page. Listings are ordered
+// newest first with the ID breaking ties, so the next page starts strictly
+// below (At, ID).
+type pageCursor struct {
+	At time.Time
+	ID string
+}
+
+func encodeCursor(at time.Time, id string) string {
+	raw := at.UTC().Format(time.RFC3339Nano) + "|" + id
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeCursor parses a cursor from a previous page; an empty cursor is the first page
+func decodeCursor(cursor string) (*pageCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	at, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return nil, ErrInvalidCursor
+	}
+	t, err := time.Parse(time.RFC3339Nano, at)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &pageCursor{At: t, ID: id}, nil
+}
+
+// admits reports whether a row at (at, id) sorts after the cursor, so it belongs
+// on a later page. A nil cursor admits every row.
+func (c *pageCursor) admits(at time.Time, id string) bool {
+	if c == nil {
+		return true
+	}
+	if !at.Equal(c.At) {
+		return at.Before(c.At)
+	}
+	return id < c.ID
+}
+
+// trimPage cuts items, fetched with one row past the limit, down to a page and
+// returns the cursor for the next page, or "" when this is the last one
+func trimPage[T any](items []T, limit int, position func(T) (time.Time, string)) ([]T, string) {
+	if limit <= 0 || len(items) <= limit {
+		return items, ""
+	}
+	items = items[:limit]
+	return items, encodeCursor(position(items[limit-1]))
+}
@@ -1,7 +1,11 @@
 // Package db provides database access layer and repository implementations.
 package db
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
 
 // Common database errors
 var (
@@ -19,4 +23,19 @@ var (
 	ErrNoAccess          = errors.New("no access to this resource")
 	ErrConnectionFailed  = errors.New("database connection failed")
 	ErrTransactionFailed = errors.New("transaction failed")
+	ErrConflict          = errors.New("record was modified concurrently")
+	ErrInvalidCursor     = errors.New("invalid page cursor")
+	ErrMissingReference  = errors.New("referenced record does not exist")
 )
+
+// isUniqueViolation reports whether err is a Postgres unique constraint violation
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+// isForeignKeyViolation reports whether err is a Postgres foreign key constraint violation
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23503"
+}
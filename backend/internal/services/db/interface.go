@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/starefossen/diktator/backend/internal/models"
@@ -13,92 +14,166 @@ type Repository interface {
 	// Close closes the database connection
 	Close() error
 
+	// WithTx runs fn in a transaction. The operations fn performs through tx are
+	// committed together if it returns nil and rolled back otherwise.
+	WithTx(ctx context.Context, fn func(tx Repository) error) error
+
 	// User operations
-	GetUser(userID string) (*models.User, error)
-	GetUserByAuthID(authID string) (*models.User, error)
-	GetUserByEmail(email string) (*models.User, error)
-	CreateUser(user *models.User) error
-	UpdateUser(user *models.User) error
-	UpdateUserDisplayName(userID, displayName string) error
-	DeleteUser(userID string) error
-	LinkUserToAuthID(userID, authID string) error
+	GetUser(ctx context.Context, userID string) (*models.User, error)
+	GetUserByAuthID(ctx context.Context, authID string) (*models.User, error)
+	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
+	CreateUser(ctx context.Context, user *models.User) error
+	UpdateUser(ctx context.Context, user *models.User) error
+	UpdateUserDisplayName(ctx context.Context, userID, displayName string) error
+	DeleteUser(ctx context.Context, userID string) error
+	LinkUserToAuthID(ctx context.Context, userID, authID string) error
 
 	// Family operations
-	GetFamily(familyID string) (*models.Family, error)
-	CreateFamily(family *models.Family) error
-	UpdateFamily(family *models.Family) error
-	DeleteFamily(familyID string) error
-	AddFamilyMember(familyID, userID, role string) error
+	GetFamily(ctx context.Context, familyID string) (*models.Family, error)
+	CreateFamily(ctx context.Context, family *models.Family) error
+	UpdateFamily(ctx context.Context, family *models.Family) error
+	DeleteFamily(ctx context.Context, familyID string) error
+	AddFamilyMember(ctx context.Context, familyID, userID, role string) error
 
 	// Child operations
-	GetChild(childID string) (*models.ChildAccount, error)
-	GetFamilyChildren(familyID string) ([]models.ChildAccount, error)
-	CreateChild(child *models.ChildAccount) error
-	UpdateChild(child *models.ChildAccount) error
-	UpdateChildDisplayName(childID, displayName string) error
-	UpdateChildBirthYear(childID string, birthYear *int) error
-	DeleteChild(childID string) error
+	GetChild(ctx context.Context, childID string) (*models.ChildAccount, error)
+	GetFamilyChildren(ctx context.Context, familyID string) ([]models.ChildAccount, error)
+	CreateChild(ctx context.Context, child *models.ChildAccount) error
+	UpdateChild(ctx context.Context, child *models.ChildAccount) error
+	UpdateChildDisplayName(ctx context.Context, childID, displayName string) error
+	UpdateChildBirthYear(ctx context.Context, childID string, birthYear *int) error
+	DeleteChild(ctx context.Context, childID string) error
 
 	// Word set operations
-	GetWordSet(id string) (*models.WordSet, error)
-	GetWordSets(familyID string) ([]models.WordSet, error)
-	GetGlobalWordSets() ([]models.WordSet, error) // Get curated word sets available to all users
-	CreateWordSet(wordSet *models.WordSet) error
-	UpdateWordSet(wordSet *models.WordSet) error
-	DeleteWordSet(id string) error
-	IsGlobalWordSet(wordSetID string) (bool, error) // Check if a word set is global/curated
+	GetWordSet(ctx context.Context, id string) (*models.WordSet, error)
+	GetWordSets(ctx context.Context, familyID string) ([]models.WordSet, error)
+	// ListWordSets filters a family's word sets, newest first, and returns the next page cursor
+	ListWordSets(ctx context.Context, familyID string, filter models.WordSetFilter, page models.PageRequest) ([]models.WordSet, string, error)
+	GetGlobalWordSets(ctx context.Context) ([]models.WordSet, error) // Get curated word sets available to all users
+	CreateWordSet(ctx context.Context, wordSet *models.WordSet) error
+	UpdateWordSet(ctx context.Context, wordSet *models.WordSet) error
+	DeleteWordSet(ctx context.Context, id string) error
+	IsGlobalWordSet(ctx context.Context, wordSetID string) (bool, error) // Check if a word set is global/curated
 
 	// Word set assignment operations
-	AssignWordSetToUser(wordSetID, userID, assignedBy string) error
-	UnassignWordSetFromUser(wordSetID, userID string) error
-	GetWordSetAssignments(wordSetID string) ([]string, error)
+	AssignWordSetToUser(ctx context.Context, wordSetID, userID, assignedBy string) error
+	UnassignWordSetFromUser(ctx context.Context, wordSetID, userID string) error
+	GetWordSetAssignments(ctx context.Context, wordSetID string) ([]string, error)
+	SetWordSetAssignmentSchedule(ctx context.Context, wordSetID, userID string, schedule *models.AssignmentSchedule) error
+	GetUserAssignments(ctx context.Context, userID string) ([]models.WordSetAssignment, error)
+	GetReminderAssignments(ctx context.Context) ([]models.WordSetAssignment, error)
+	ClaimAssignmentReminder(ctx context.Context, wordSetID, userID string, slot, remindedAt time.Time) (bool, error)
 
 	// Test result operations
-	GetTestResults(userID string) ([]models.TestResult, error)
-	GetFamilyResults(familyID string) ([]models.TestResult, error)
-	SaveTestResult(result *models.TestResult) error
+	GetTestResults(ctx context.Context, userID string) ([]models.TestResult, error)
+	GetFamilyResults(ctx context.Context, familyID string) ([]models.TestResult, error)
+	// ListTestResults filters test results with their word results, newest first, and returns the next page cursor
+	ListTestResults(ctx context.Context, filter models.ResultFilter, page models.PageRequest) ([]models.TestResult, string, error)
+	SaveTestResult(ctx context.Context, result *models.TestResult) error
+	GetTestResultByIdempotencyKey(ctx context.Context, userID, key string) (*models.TestResult, error)
+	GetRecentWordSetResults(ctx context.Context, userID, wordSetID string, limit int) ([]models.TestResult, error)
 
 	// Audio file operations
-	GetAudioFile(word, language, voiceID string) (*models.AudioFile, error)
-	SaveAudioFile(audioFile *models.AudioFile) error
+	GetAudioFile(ctx context.Context, word, language, voiceID string) (*models.AudioFile, error)
+	SaveAudioFile(ctx context.Context, audioFile *models.AudioFile) error
 
 	// Progress and stats operations
-	GetFamilyProgress(familyID string) ([]models.FamilyProgress, error)
-	GetFamilyStats(familyID string) (*models.FamilyStats, error)
-	GetUserProgress(userID string) (*models.FamilyProgress, error)
+	GetFamilyProgress(ctx context.Context, familyID string) ([]models.FamilyProgress, error)
+	GetFamilyStats(ctx context.Context, familyID string) (*models.FamilyStats, error)
+	GetUserProgress(ctx context.Context, userID string) (*models.FamilyProgress, error)
 
 	// Family invitation operations
-	CreateFamilyInvitation(invitation *models.FamilyInvitation) error
-	GetPendingInvitationsByEmail(email string) ([]models.FamilyInvitation, error)
-	GetFamilyInvitations(familyID string) ([]models.FamilyInvitation, error)
-	AcceptInvitation(invitationID, userID string) error
-	DeleteInvitation(invitationID string) error
+	CreateFamilyInvitation(ctx context.Context, invitation *models.FamilyInvitation) error
+	GetPendingInvitationsByEmail(ctx context.Context, email string) ([]models.FamilyInvitation, error)
+	GetFamilyInvitations(ctx context.Context, familyID string) ([]models.FamilyInvitation, error)
+	AcceptInvitation(ctx context.Context, invitationID, userID string) error
+	DeleteInvitation(ctx context.Context, invitationID string) error
 
 	// Verification operations
-	VerifyFamilyMembership(userID, familyID string) error
-	VerifyParentPermission(userID, familyID string) error
-	VerifyChildOwnership(parentID, childID string) error
-	VerifyWordSetAccess(familyID, wordSetID string) error
+	VerifyFamilyMembership(ctx context.Context, userID, familyID string) error
+	VerifyParentPermission(ctx context.Context, userID, familyID string) error
+	VerifyChildOwnership(ctx context.Context, parentID, childID string) error
+	VerifyWordSetAccess(ctx context.Context, familyID, wordSetID string) error
 
 	// Word mastery operations
-	GetWordMastery(userID, wordSetID, word string) (*models.WordMastery, error)
-	GetWordSetMastery(userID, wordSetID string) ([]models.WordMastery, error)
-	IncrementMastery(userID, wordSetID, word string, mode models.TestMode) (*models.WordMastery, error)
+	GetWordMastery(ctx context.Context, userID, wordSetID, word string) (*models.WordMastery, error)
+	GetWordSetMastery(ctx context.Context, userID, wordSetID string) ([]models.WordMastery, error)
+	IncrementMastery(ctx context.Context, userID, wordSetID, word string, mode models.TestMode) (*models.WordMastery, error)
 
 	// XP operations
-	GetUserXP(userID string) (totalXP int, level int, err error)
-	UpdateUserXP(userID string, xpAwarded, newTotalXP, newLevel int) error
-	GetRecentCompletions(userID, wordSetID, mode string, since time.Time) (int, error)
-	IsFirstCompletion(userID, wordSetID, mode string) (bool, error)
+	GetUserXP(ctx context.Context, userID string) (totalXP int, level int, err error)
+	UpdateUserXP(ctx context.Context, userID string, xpAwarded, newTotalXP, newLevel int) error
+	GetRecentCompletions(ctx context.Context, userID, wordSetID, mode string, since, until time.Time) (int, error)
+	IsFirstCompletion(ctx context.Context, userID, wordSetID, mode string) (bool, error)
+
+	// Family API key operations
+	CreateFamilyAPIKey(ctx context.Context, key *models.FamilyAPIKey) error
+	GetFamilyAPIKeyByHash(ctx context.Context, keyHash string) (*models.FamilyAPIKey, error)
+	GetFamilyAPIKeys(ctx context.Context, familyID string) ([]models.FamilyAPIKey, error)
+	DeleteFamilyAPIKey(ctx context.Context, familyID, keyID string) error
+	TouchFamilyAPIKey(ctx context.Context, keyID string) error
+
+	// Webhook operations
+	CreateWebhook(ctx context.Context, webhook *models.Webhook) error
+	GetFamilyWebhooks(ctx context.Context, familyID string) ([]models.Webhook, error)
+	DeleteWebhook(ctx context.Context, familyID, webhookID string) error
+	GetWebhookDeliveries(ctx context.Context, familyID, webhookID string, limit int) ([]models.WebhookDelivery, error)
+	EnqueueWebhookDeliveries(ctx context.Context, familyID, eventID, eventType string, payload []byte) (int, error)
+	ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error)
+	UpdateWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+
+	// Email digest operations
+	GetDigestPreferences(ctx context.Context, userID string) (*models.DigestPreferences, error)
+	UpsertDigestPreferences(ctx context.Context, prefs *models.DigestPreferences) error
+	ClaimDueDigestRecipients(ctx context.Context, sentBefore, claimedAt time.Time) ([]models.DigestRecipient, error)
+	ReleaseDigestClaim(ctx context.Context, userID string, claimedAt time.Time, previous *time.Time) error
+	GetTestResultsInRange(ctx context.Context, userID string, from, to time.Time) ([]models.TestResult, error)
+
+	// Notification inbox operations
+	CreateNotifications(ctx context.Context, notifications []models.Notification) error
+	GetNotifications(ctx context.Context, userID string, unreadOnly bool, limit int) ([]models.Notification, error)
+	CountUnreadNotifications(ctx context.Context, userID string) (int, error)
+	MarkNotificationsRead(ctx context.Context, userID string, ids []string, readAt time.Time) (int, error)
+	DismissNotifications(ctx context.Context, userID string, ids []string) (int, error)
+
+	// Test session operations
+	CreateTestSession(ctx context.Context, session *models.TestSession) error
+	GetTestSession(ctx context.Context, userID, sessionID string) (*models.TestSession, error)
+	GetActiveTestSessions(ctx context.Context, userID string, now time.Time) ([]models.TestSession, error)
+	UpdateTestSession(ctx context.Context, session *models.TestSession) error
+	DeleteExpiredTestSessions(ctx context.Context, before time.Time) (int, error)
+
+	// Analytics operations
+	GetMissedWords(ctx context.Context, userID string, from, to time.Time, limit int) ([]models.MissedWord, error)
+	GetErrorTypeCounts(ctx context.Context, userID string, from, to time.Time, bucket string, errorTypes []string) ([]models.ErrorTypeCount, error)
+	GetWordAttemptStats(ctx context.Context, userID string, from, to time.Time, limit int) ([]models.WordAttemptStats, error)
+	GetRegressedWords(ctx context.Context, userID string, from, to time.Time, minPriorCorrect, limit int) ([]models.RegressedWord, error)
+
+	// Practice list operations
+	GetPracticeList(ctx context.Context, childID string) (*models.PracticeList, error)
+	SavePracticeList(ctx context.Context, list *models.PracticeList) error
+
+	// Challenge lock operations
+	GetChallengeLock(ctx context.Context, wordSetID, childID string) (*models.ChallengeLock, error)
+	SaveChallengeLock(ctx context.Context, lock *models.ChallengeLock) error
+	DeleteChallengeLock(ctx context.Context, wordSetID, childID string) error
 }
 
+// Database drivers supported by NewRepository
+const (
+	DriverPostgres = "postgres"
+	DriverMemory   = "memory"
+)
+
 // Config holds database configuration
 type Config struct {
+	Driver          string        // Database driver, DriverPostgres (default) or DriverMemory
 	DSN             string        // Database connection string
 	MaxOpenConns    int           // Maximum number of open connections
 	MaxIdleConns    int           // Maximum number of idle connections
 	ConnMaxLifetime time.Duration // Maximum connection lifetime
 	ConnMaxIdleTime time.Duration // Maximum idle time
+	QueryTimeout    time.Duration // Deadline for a single repository operation (0 = none)
 }
 
 // DefaultConfig returns sensible default configuration
@@ -108,11 +183,19 @@ func DefaultConfig() *Config {
 		MaxIdleConns:    5,
 		ConnMaxLifetime: 5 * time.Minute,
 		ConnMaxIdleTime: 1 * time.Minute,
+		QueryTimeout:    10 * time.Second,
 	}
 }
 
 // NewRepository creates a new repository based on the configuration.
 // This factory function allows switching between different implementations.
 func NewRepository(ctx context.Context, cfg *Config) (Repository, error) {
-	return NewPostgres(ctx, cfg)
+	switch cfg.Driver {
+	case "", DriverPostgres:
+		return NewPostgres(ctx, cfg)
+	case DriverMemory:
+		return NewMemory(), nil
+	default:
+		return nil, fmt.Errorf("unknown database driver %q", cfg.Driver)
+	}
 }
@@ -0,0 +1,2898 @@
+package db
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/starefossen/diktator/backend/internal/models"
+)
+
+// Memory implements the Repository interface in process memory. It mirrors the
+// Postgres implementation: the same error values, orderings, stored columns and
+// cascading deletes, so it can stand in for Postgres in unit tests and demos.
+// Writes check foreign keys like the schema's constraints and fail with
+// ErrMissingReference. Operations never wait on I/O, so
+// contexts are accepted but not consulted. Data is lost when the process exits.
+type Memory struct {
+	memoryState
+	shared table // Inside WithTx, the tables not yet copied from the committed state
+	mu     sync.RWMutex
+}
+
+// memoryState holds the tables. Slices and pointers inside a stored row are
+// replaced rather than modified in place, so copying each row is enough for a
+// transaction's private copy of a table.
+type memoryState struct {
+	users          map[string]*models.User
+	families       map[string]*models.Family
+	wordSets       map[string]*models.WordSet
+	audioFiles     map[audioKey]*models.AudioFile
+	mastery        map[masteryKey]*models.WordMastery
+	apiKeys        map[string]*models.FamilyAPIKey
+	webhooks       map[string]*models.Webhook
+	digestPrefs    map[string]*models.DigestPreferences
+	sessions       map[string]*models.TestSession
+	practiceLists  map[string]*models.PracticeList
+	challengeLocks map[lockKey]*models.ChallengeLock
+	members        []familyMember // Tables without a single-column key keep insertion order
+	assignments    []*models.WordSetAssignment
+	results        []*models.TestResult
+	invitations    []*models.FamilyInvitation
+	deliveries     []*models.WebhookDelivery
+	notifications  []*models.Notification
+}
+
+// table is a set of memoryState tables
+type table uint32
+
+const (
+	tableUsers table = 1 << iota
+	tableFamilies
+	tableWordSets
+	tableAudioFiles
+	tableMastery
+	tableAPIKeys
+	tableWebhooks
+	tableDigestPrefs
+	tableSessions
+	tablePracticeLists
+	tableChallengeLocks
+	tableMembers
+	tableAssignments
+	tableResults
+	tableInvitations
+	tableDeliveries
+	tableNotifications
+
+	allTables = tableNotifications<<1 - 1
+)
+
+type audioKey struct {
+	word, language, voiceID string
+}
+
+type masteryKey struct {
+	userID, wordSetID, word string
+}
+
+type lockKey struct {
+	wordSetID, childID string
+}
+
+type familyMember struct {
+	familyID, userID, role string
+}
+
+// wordEntry is the element type of models.WordSet.Words
+type wordEntry = struct {
+	Word         string               `json:"word"`
+	Audio        models.WordAudio     `json:"audio,omitempty"`
+	Definition   string               `json:"definition,omitempty"`
+	Translations []models.Translation `json:"translations,omitempty"`
+}
+
+// NewMemory creates an empty in-memory repository with the system user that
+// owns curated content, as seeded by the migrations
+func NewMemory() *Memory {
+	now := time.Now()
+	return &Memory{memoryState: memoryState{
+		users: map[string]*models.User{
+			models.SystemUserID: {
+				ID:           models.SystemUserID,
+				AuthID:       models.SystemUserID,
+				Email:        "system@diktator.app",
+				DisplayName:  "System",
+				Role:         "system",
+				IsActive:     true,
+				Level:        1,
+				CreatedAt:    now,
+				LastActiveAt: now,
+			},
+		},
+		families:       map[string]*models.Family{},
+		wordSets:       map[string]*models.WordSet{},
+		audioFiles:     map[audioKey]*models.AudioFile{},
+		mastery:        map[masteryKey]*models.WordMastery{},
+		apiKeys:        map[string]*models.FamilyAPIKey{},
+		webhooks:       map[string]*models.Webhook{},
+		digestPrefs:    map[string]*models.DigestPreferences{},
+		sessions:       map[string]*models.TestSession{},
+		practiceLists:  map[string]*models.PracticeList{},
+		challengeLocks: map[lockKey]*models.ChallengeLock{},
+	}}
+}
+
+// Close is a no-op; the data is kept until the repository is garbage collected
+func (m *Memory) Close() error {
+	return nil
+}
+
+// CheckHealth always succeeds; the data is in process memory
+func (m *Memory) CheckHealth(context.Context) error {
+	return nil
+}
+
+// WithTx runs fn against the data and keeps its changes only if fn returns nil.
+// The transaction shares the committed tables and copies each one the first
+// time it writes to it. The write lock is held throughout, so transactions are
+// serializable and fn must not call m directly.
+func (m *Memory) WithTx(_ context.Context, fn func(tx Repository) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tx := &Memory{memoryState: m.memoryState, shared: allTables}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	m.memoryState = tx.memoryState
+	return nil
+}
+
+// own copies the tables a write is about to change if they are still shared
+// with the committed state. Every write calls it with m.mu held.
+func (m *Memory) own(tables table) {
+	tables &= m.shared
+	if tables == 0 {
+		return
+	}
+	m.shared &^= tables
+
+	s := &m.memoryState
+	if tables&tableUsers != 0 {
+		s.users = cloneRows(s.users)
+	}
+	if tables&tableFamilies != 0 {
+		s.families = cloneRows(s.families)
+	}
+	if tables&tableWordSets != 0 {
+		s.wordSets = cloneRows(s.wordSets)
+	}
+	if tables&tableAudioFiles != 0 {
+		s.audioFiles = cloneRows(s.audioFiles)
+	}
+	if tables&tableMastery != 0 {
+		s.mastery = cloneRows(s.mastery)
+	}
+	if tables&tableAPIKeys != 0 {
+		s.apiKeys = cloneRows(s.apiKeys)
+	}
+	if tables&tableWebhooks != 0 {
+		s.webhooks = cloneRows(s.webhooks)
+	}
+	if tables&tableDigestPrefs != 0 {
+		s.digestPrefs = cloneRows(s.digestPrefs)
+	}
+	if tables&tableSessions != 0 {
+		s.sessions = cloneRows(s.sessions)
+	}
+	if tables&tablePracticeLists != 0 {
+		s.practiceLists = cloneRows(s.practiceLists)
+	}
+	if tables&tableChallengeLocks != 0 {
+		s.challengeLocks = cloneRows(s.challengeLocks)
+	}
+	if tables&tableMembers != 0 {
+		s.members = slices.Clone(s.members)
+	}
+	if tables&tableAssignments != 0 {
+		s.assignments = cloneList(s.assignments)
+	}
+	if tables&tableResults != 0 {
+		s.results = cloneList(s.results)
+	}
+	if tables&tableInvitations != 0 {
+		s.invitations = cloneList(s.invitations)
+	}
+	if tables&tableDeliveries != 0 {
+		s.deliveries = cloneList(s.deliveries)
+	}
+	if tables&tableNotifications != 0 {
+		s.notifications = cloneList(s.notifications)
+	}
+}
+
+func cloneRows[K comparable, V any](rows map[K]*V) map[K]*V {
+	out := make(map[K]*V, len(rows))
+	for k, v := range rows {
+		out[k] = clonePtr(v)
+	}
+	return out
+}
+
+func cloneList[V any](rows []*V) []*V {
+	out := make([]*V, len(rows))
+	for i, v := range rows {
+		out[i] = clonePtr(v)
+	}
+	return out
+}
+
+func clonePtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+// deref returns the value p points to, or the zero value for nil
+func deref[T any](p *T) T {
+	if p == nil {
+		var zero T
+		return zero
+	}
+	return *p
+}
+
+// requireRows returns ErrMissingReference unless table has a row for every ID,
+// like a foreign key constraint. Empty IDs are NULL and reference nothing.
+func requireRows[V any](table map[string]*V, ids ...string) error {
+	for _, id := range ids {
+		if _, ok := table[id]; id != "" && !ok {
+			return ErrMissingReference
+		}
+	}
+	return nil
+}
+
+// jsonCopy deep-copies src into dst through JSON, like a JSONB column round-trip
+func jsonCopy(src, dst any) error {
+	data, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst)
+}
+
+// limited returns the first limit items, like SQL LIMIT
+func limited[T any](items []T, limit int) []T {
+	if limit >= 0 && limit < len(items) {
+		return items[:limit]
+	}
+	return items
+}
+
+// sortedValues returns the values of a map ordered by cmpFn, with ties broken by key
+func sortedValues[K cmp.Ordered, V any](m map[K]V, cmpFn func(a, b V) int) []V {
+	keys := slices.Sorted(maps.Keys(m))
+	values := make([]V, 0, len(keys))
+	for _, k := range keys {
+		values = append(values, m[k])
+	}
+	slices.SortStableFunc(values, cmpFn)
+	return values
+}
+
+// sortedSet returns the members of a set in order, or an empty slice
+func sortedSet(set map[string]struct{}) []string {
+	return slices.AppendSeq([]string{}, slices.Values(slices.Sorted(maps.Keys(set))))
+}
+
+// ============================================================================
+// User Operations
+// ============================================================================
+
+func cloneUser(u *models.User) *models.User {
+	user := *u
+	user.ParentID = clonePtr(u.ParentID)
+	user.BirthYear = clonePtr(u.BirthYear)
+	user.Children = nil
+	return &user
+}
+
+// usersByCreation returns all users, oldest first
+func (m *Memory) usersByCreation() []*models.User {
+	return sortedValues(m.users, func(a, b *models.User) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+}
+
+// userView copies a stored user and lists the children of a parent
+func (m *Memory) userView(u *models.User) *models.User {
+	user := cloneUser(u)
+	if user.Role != "parent" {
+		return user
+	}
+	for _, c := range m.usersByCreation() {
+		if c.Role == "child" && c.ParentID != nil && *c.ParentID == u.ID {
+			user.Children = append(user.Children, c.ID)
+		}
+	}
+	return user
+}
+
+func (m *Memory) findUser(match func(u *models.User) bool) *models.User {
+	for _, u := range m.users {
+		if match(u) {
+			return u
+		}
+	}
+	return nil
+}
+
+// checkUniqueUser returns ErrDuplicate if another user has the auth ID or email
+func (m *Memory) checkUniqueUser(userID, authID, email string) error {
+	taken := m.findUser(func(u *models.User) bool {
+		return u.ID != userID && (u.AuthID == authID || u.Email == email)
+	})
+	if taken != nil {
+		return ErrDuplicate
+	}
+	return nil
+}
+
+func (m *Memory) GetUser(_ context.Context, userID string) (*models.User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	u, ok := m.users[userID]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+	return m.userView(u), nil
+}
+
+func (m *Memory) GetUserByAuthID(_ context.Context, authID string) (*models.User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	u := m.findUser(func(u *models.User) bool { return u.AuthID == authID })
+	if u == nil {
+		return nil, ErrUserNotFound
+	}
+	return m.userView(u), nil
+}
+
+func (m *Memory) GetUserByEmail(_ context.Context, email string) (*models.User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	u := m.findUser(func(u *models.User) bool { return strings.EqualFold(u.Email, email) })
+	if u == nil {
+		return nil, ErrUserNotFound
+	}
+	return cloneUser(u), nil
+}
+
+func (m *Memory) LinkUserToAuthID(_ context.Context, userID, authID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableUsers)
+
+	u, ok := m.users[userID]
+	if !ok {
+		return ErrUserNotFound
+	}
+	if err := m.checkUniqueUser(userID, authID, u.Email); err != nil {
+		return fmt.Errorf("failed to link user to auth ID: %w", err)
+	}
+
+	u.AuthID = authID
+	u.IsActive = true
+	u.LastActiveAt = time.Now()
+	return nil
+}
+
+func (m *Memory) CreateUser(_ context.Context, user *models.User) error {
+	if user.ID == "" {
+		user.ID = uuid.New().String()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableUsers)
+
+	if err := m.insertUser(cloneUser(user)); err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+	return nil
+}
+
+// insertUser stores a new user with the column defaults for XP and level
+func (m *Memory) insertUser(user *models.User) error {
+	if _, exists := m.users[user.ID]; exists {
+		return ErrDuplicate
+	}
+	if err := m.checkUniqueUser(user.ID, user.AuthID, user.Email); err != nil {
+		return err
+	}
+	if err := cmp.Or(requireRows(m.families, user.FamilyID), requireRows(m.users, deref(user.ParentID))); err != nil {
+		return err
+	}
+
+	user.TotalXP = 0
+	user.Level = 1
+	m.users[user.ID] = user
+	return nil
+}
+
+func (m *Memory) UpdateUserDisplayName(_ context.Context, userID, displayName string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableUsers)
+
+	u, ok := m.users[userID]
+	if !ok {
+		return ErrUserNotFound
+	}
+	u.DisplayName = displayName
+	return nil
+}
+
+func (m *Memory) UpdateUser(_ context.Context, user *models.User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableUsers)
+
+	u, ok := m.users[user.ID]
+	if !ok {
+		return ErrUserNotFound
+	}
+	if err := m.checkUniqueUser(u.ID, u.AuthID, user.Email); err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	if err := cmp.Or(requireRows(m.families, user.FamilyID), requireRows(m.users, deref(user.ParentID))); err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	u.Email = user.Email
+	u.DisplayName = user.DisplayName
+	u.FamilyID = user.FamilyID
+	u.Role = user.Role
+	u.ParentID = clonePtr(user.ParentID)
+	u.IsActive = user.IsActive
+	u.LastActiveAt = user.LastActiveAt
+	return nil
+}
+
+func (m *Memory) DeleteUser(_ context.Context, userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableUsers | tableMembers | tableResults | tableAssignments | tableMastery | tableAPIKeys | tableWebhooks | tableDeliveries | tableDigestPrefs | tableNotifications | tableSessions | tablePracticeLists | tableChallengeLocks)
+
+	if _, ok := m.users[userID]; !ok {
+		return ErrUserNotFound
+	}
+	m.deleteUserLocked(userID)
+	return nil
+}
+
+// deleteUserLocked removes a user and cascades like the users foreign keys
+func (m *Memory) deleteUserLocked(userID string) {
+	delete(m.users, userID)
+	for _, u := range m.users {
+		if u.ParentID != nil && *u.ParentID == userID {
+			u.ParentID = nil
+		}
+	}
+	for _, n := range m.notifications {
+		if n.SubjectUserID == userID {
+			n.SubjectUserID = ""
+		}
+	}
+
+	m.members = slices.DeleteFunc(m.members, func(fm familyMember) bool { return fm.userID == userID })
+	m.deleteResults(func(r *models.TestResult) bool { return r.UserID == userID })
+	m.assignments = slices.DeleteFunc(m.assignments, func(a *models.WordSetAssignment) bool { return a.UserID == userID })
+	m.notifications = slices.DeleteFunc(m.notifications, func(n *models.Notification) bool { return n.UserID == userID })
+	maps.DeleteFunc(m.mastery, func(k masteryKey, _ *models.WordMastery) bool { return k.userID == userID })
+	maps.DeleteFunc(m.sessions, func(_ string, s *models.TestSession) bool { return s.UserID == userID })
+	maps.DeleteFunc(m.challengeLocks, func(k lockKey, _ *models.ChallengeLock) bool { return k.childID == userID })
+	maps.DeleteFunc(m.apiKeys, func(_ string, k *models.FamilyAPIKey) bool { return k.CreatedBy == userID })
+	m.deleteWebhooks(func(w *models.Webhook) bool { return w.CreatedBy == userID })
+	delete(m.digestPrefs, userID)
+	delete(m.practiceLists, userID)
+}
+
+// ============================================================================
+// Family Operations
+// ============================================================================
+
+func (m *Memory) GetFamily(_ context.Context, familyID string) (*models.Family, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	f, ok := m.families[familyID]
+	if !ok {
+		return nil, ErrFamilyNotFound
+	}
+
+	family := *f
+	family.Members = nil
+	for _, fm := range m.members {
+		if fm.familyID == familyID {
+			family.Members = append(family.Members, fm.userID)
+		}
+	}
+	return &family, nil
+}
+
+func (m *Memory) CreateFamily(_ context.Context, family *models.Family) error {
+	if family.ID == "" {
+		family.ID = uuid.New().String()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableFamilies | tableMembers)
+
+	if _, exists := m.families[family.ID]; exists {
+		return fmt.Errorf("failed to create family: %w", ErrDuplicate)
+	}
+	if err := requireRows(m.users, family.CreatedBy); err != nil {
+		return fmt.Errorf("failed to create family: %w", err)
+	}
+	for i, memberID := range family.Members {
+		if slices.Contains(family.Members[:i], memberID) {
+			return fmt.Errorf("failed to add family member: %w", ErrDuplicate)
+		}
+		if err := requireRows(m.users, memberID); err != nil {
+			return fmt.Errorf("failed to add family member: %w", err)
+		}
+	}
+
+	stored := *family
+	stored.Members = nil
+	m.families[family.ID] = &stored
+	for _, memberID := range family.Members {
+		m.members = append(m.members, familyMember{familyID: family.ID, userID: memberID, role: "parent"})
+	}
+	return nil
+}
+
+func (m *Memory) AddFamilyMember(_ context.Context, familyID, userID, role string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableMembers)
+
+	if err := cmp.Or(requireRows(m.families, familyID), requireRows(m.users, userID)); err != nil {
+		return fmt.Errorf("failed to add family member: %w", err)
+	}
+	m.addMemberLocked(familyID, userID, role)
+	return nil
+}
+
+// addMemberLocked adds a family member unless they are one already
+func (m *Memory) addMemberLocked(familyID, userID, role string) {
+	for _, fm := range m.members {
+		if fm.familyID == familyID && fm.userID == userID {
+			return
+		}
+	}
+	m.members = append(m.members, familyMember{familyID: familyID, userID: userID, role: role})
+}
+
+func (m *Memory) UpdateFamily(_ context.Context, family *models.Family) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableFamilies)
+
+	f, ok := m.families[family.ID]
+	if !ok {
+		return ErrFamilyNotFound
+	}
+	f.Name = family.Name
+	f.UpdatedAt = family.UpdatedAt
+	return nil
+}
+
+func (m *Memory) DeleteFamily(_ context.Context, familyID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(allTables &^ tableAudioFiles &^ tableDigestPrefs)
+
+	if _, ok := m.families[familyID]; !ok {
+		return ErrFamilyNotFound
+	}
+	m.deleteFamilyLocked(familyID)
+	return nil
+}
+
+// deleteFamilyLocked removes a family and cascades like the families foreign keys
+func (m *Memory) deleteFamilyLocked(familyID string) {
+	delete(m.families, familyID)
+	for _, u := range m.users {
+		if u.FamilyID == familyID {
+			u.FamilyID = ""
+		}
+	}
+	for id, ws := range m.wordSets {
+		if ws.FamilyID != nil && *ws.FamilyID == familyID {
+			m.deleteWordSetLocked(id)
+		}
+	}
+
+	m.members = slices.DeleteFunc(m.members, func(fm familyMember) bool { return fm.familyID == familyID })
+	m.invitations = slices.DeleteFunc(m.invitations, func(i *models.FamilyInvitation) bool { return i.FamilyID == familyID })
+	m.notifications = slices.DeleteFunc(m.notifications, func(n *models.Notification) bool { return n.FamilyID == familyID })
+	maps.DeleteFunc(m.apiKeys, func(_ string, k *models.FamilyAPIKey) bool { return k.FamilyID == familyID })
+	maps.DeleteFunc(m.sessions, func(_ string, s *models.TestSession) bool { return s.FamilyID == familyID })
+	maps.DeleteFunc(m.practiceLists, func(_ string, l *models.PracticeList) bool { return l.FamilyID == familyID })
+	m.deleteWebhooks(func(w *models.Webhook) bool { return w.FamilyID == familyID })
+}
+
+// ============================================================================
+// Child Operations
+// ============================================================================
+
+func childAccount(u *models.User) models.ChildAccount {
+	return models.ChildAccount{
+		ID:           u.ID,
+		Email:        u.Email,
+		DisplayName:  u.DisplayName,
+		FamilyID:     u.FamilyID,
+		ParentID:     clonePtr(u.ParentID),
+		Role:         u.Role,
+		IsActive:     u.IsActive,
+		BirthYear:    clonePtr(u.BirthYear),
+		TotalXP:      u.TotalXP,
+		Level:        u.Level,
+		CreatedAt:    u.CreatedAt,
+		LastActiveAt: u.LastActiveAt,
+	}
+}
+
+// child returns a stored user with the child role
+func (m *Memory) child(childID string) (*models.User, bool) {
+	u, ok := m.users[childID]
+	if !ok || u.Role != "child" {
+		return nil, false
+	}
+	return u, true
+}
+
+func (m *Memory) GetChild(_ context.Context, childID string) (*models.ChildAccount, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	u, ok := m.child(childID)
+	if !ok {
+		return nil, ErrChildNotFound
+	}
+	child := childAccount(u)
+	return &child, nil
+}
+
+func (m *Memory) GetFamilyChildren(_ context.Context, familyID string) ([]models.ChildAccount, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var children []models.ChildAccount
+	for _, u := range m.usersByCreation() {
+		if u.FamilyID == familyID && u.Role == "child" {
+			children = append(children, childAccount(u))
+		}
+	}
+	return children, nil
+}
+
+func (m *Memory) CreateChild(_ context.Context, child *models.ChildAccount) error {
+	if child.ID == "" {
+		child.ID = uuid.New().String()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableUsers)
+
+	// The child ID doubles as auth ID until the child logs in
+	user := &models.User{
+		ID:           child.ID,
+		AuthID:       child.ID,
+		Email:        child.Email,
+		DisplayName:  child.DisplayName,
+		FamilyID:     child.FamilyID,
+		ParentID:     clonePtr(child.ParentID),
+		Role:         "child",
+		IsActive:     child.IsActive,
+		CreatedAt:    child.CreatedAt,
+		LastActiveAt: child.LastActiveAt,
+	}
+	if err := m.insertUser(user); err != nil {
+		return fmt.Errorf("failed to create child: %w", err)
+	}
+	return nil
+}
+
+func (m *Memory) UpdateChildDisplayName(_ context.Context, childID, displayName string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableUsers)
+
+	u, ok := m.child(childID)
+	if !ok {
+		return ErrChildNotFound
+	}
+	u.DisplayName = displayName
+	return nil
+}
+
+func (m *Memory) UpdateChildBirthYear(_ context.Context, childID string, birthYear *int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableUsers)
+
+	u, ok := m.child(childID)
+	if !ok {
+		return ErrChildNotFound
+	}
+	u.BirthYear = clonePtr(birthYear)
+	return nil
+}
+
+func (m *Memory) UpdateChild(_ context.Context, child *models.ChildAccount) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableUsers)
+
+	u, ok := m.child(child.ID)
+	if !ok {
+		return ErrChildNotFound
+	}
+	if err := m.checkUniqueUser(u.ID, u.AuthID, child.Email); err != nil {
+		return fmt.Errorf("failed to update child: %w", err)
+	}
+
+	u.Email = child.Email
+	u.DisplayName = child.DisplayName
+	u.IsActive = child.IsActive
+	u.LastActiveAt = child.LastActiveAt
+	return nil
+}
+
+func (m *Memory) DeleteChild(_ context.Context, childID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableUsers | tableMembers | tableResults | tableAssignments | tableMastery | tableAPIKeys | tableWebhooks | tableDeliveries | tableDigestPrefs | tableNotifications | tableSessions | tablePracticeLists | tableChallengeLocks)
+
+	if _, ok := m.child(childID); !ok {
+		return ErrChildNotFound
+	}
+	m.deleteUserLocked(childID)
+	return nil
+}
+
+// ============================================================================
+// WordSet Operations
+// ============================================================================
+
+// copyWords copies word entries the way the words table stores them: audio is
+// kept only with a URL, and empty translations are dropped
+func copyWords(words []wordEntry) []wordEntry {
+	var out []wordEntry
+	for _, w := range words {
+		entry := wordEntry{Word: w.Word, Definition: w.Definition}
+		for _, t := range w.Translations {
+			entry.Translations = append(entry.Translations, models.Translation{
+				AudioURL: clonePtr(t.AudioURL),
+				AudioID:  clonePtr(t.AudioID),
+				VoiceID:  clonePtr(t.VoiceID),
+				Language: t.Language,
+				Text:     t.Text,
+			})
+		}
+		if w.Audio.AudioURL != "" {
+			entry.Audio = w.Audio
+			entry.Audio.Word = w.Word
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+// copyWordSet copies the word set columns that CreateWordSet and the curated
+// content migrations write
+func copyWordSet(ws *models.WordSet) (*models.WordSet, error) {
+	out := &models.WordSet{
+		ID:            ws.ID,
+		Name:          ws.Name,
+		FamilyID:      clonePtr(ws.FamilyID),
+		IsGlobal:      ws.IsGlobal,
+		CreatedBy:     ws.CreatedBy,
+		Language:      ws.Language,
+		TargetGrade:   clonePtr(ws.TargetGrade),
+		Difficulty:    clonePtr(ws.Difficulty),
+		Description:   clonePtr(ws.Description),
+		SpellingFocus: slices.Clone(ws.SpellingFocus),
+		CreatedAt:     ws.CreatedAt,
+		UpdatedAt:     ws.UpdatedAt,
+		Words:         copyWords(ws.Words),
+	}
+	if err := copyTestConfig(out, ws.TestConfiguration); err != nil {
+		return nil, err
+	}
+	if ws.Sentences != nil {
+		if err := jsonCopy(ws.Sentences, &out.Sentences); err != nil {
+			return nil, fmt.Errorf("failed to marshal sentences: %w", err)
+		}
+	}
+	return out, nil
+}
+
+func copyTestConfig(ws *models.WordSet, config *map[string]interface{}) error {
+	ws.TestConfiguration = nil
+	if config == nil {
+		return nil
+	}
+	var copied map[string]interface{}
+	if err := jsonCopy(config, &copied); err != nil {
+		return fmt.Errorf("failed to marshal test config: %w", err)
+	}
+	ws.TestConfiguration = &copied
+	return nil
+}
+
+// wordSetAssignees returns the users assigned to a word set, earliest first
+func (m *Memory) wordSetAssignees(wordSetID string) []string {
+	var userIDs []string
+	for _, a := range m.assignmentsByAssignedAt() {
+		if a.WordSetID == wordSetID {
+			userIDs = append(userIDs, a.UserID)
+		}
+	}
+	return userIDs
+}
+
+func (m *Memory) GetWordSet(_ context.Context, id string) (*models.WordSet, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ws, ok := m.wordSets[id]
+	if !ok {
+		return nil, ErrWordSetNotFound
+	}
+	return copyWordSet(ws)
+}
+
+func (m *Memory) GetWordSets(_ context.Context, familyID string) ([]models.WordSet, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	sets := sortedValues(m.wordSets, func(a, b *models.WordSet) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+
+	var wordSets []models.WordSet
+	for _, ws := range sets {
+		if ws.FamilyID == nil || *ws.FamilyID != familyID {
+			continue
+		}
+		out, err := copyWordSet(ws)
+		if err != nil {
+			return nil, err
+		}
+		out.AssignedUserIDs = m.wordSetAssignees(ws.ID)
+		wordSets = append(wordSets, *out)
+	}
+	return wordSets, nil
+}
+
+func (m *Memory) ListWordSets(_ context.Context, familyID string, filter models.WordSetFilter, page models.PageRequest) ([]models.WordSet, string, error) {
+	after, err := decodeCursor(page.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var matching []*models.WordSet
+	for _, ws := range m.wordSets {
+		if ws.FamilyID != nil && *ws.FamilyID == familyID && wordSetMatches(ws, filter) && after.admits(ws.CreatedAt, ws.ID) {
+			matching = append(matching, ws)
+		}
+	}
+	slices.SortFunc(matching, func(a, b *models.WordSet) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+	})
+	if page.Limit > 0 {
+		matching = limited(matching, page.Limit+1)
+	}
+
+	wordSets := []models.WordSet{}
+	for _, ws := range matching {
+		out, err := copyWordSet(ws)
+		if err != nil {
+			return nil, "", err
+		}
+		out.AssignedUserIDs = m.wordSetAssignees(ws.ID)
+		wordSets = append(wordSets, *out)
+	}
+	wordSets, next := trimPage(wordSets, page.Limit, func(ws models.WordSet) (time.Time, string) { return ws.CreatedAt, ws.ID })
+	return wordSets, next, nil
+}
+
+func wordSetMatches(ws *models.WordSet, filter models.WordSetFilter) bool {
+	switch {
+	case filter.Search != "" && !strings.Contains(strings.ToLower(ws.Name), strings.ToLower(filter.Search)),
+		filter.Language != "" && ws.Language != filter.Language,
+		filter.Grade != "" && (ws.TargetGrade == nil || *ws.TargetGrade != filter.Grade),
+		filter.Difficulty != "" && (ws.Difficulty == nil || *ws.Difficulty != filter.Difficulty):
+		return false
+	}
+	return true
+}
+
+// GetGlobalWordSets returns all global (curated) word sets available to all users
+func (m *Memory) GetGlobalWordSets(_ context.Context) ([]models.WordSet, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	sets := sortedValues(m.wordSets, func(a, b *models.WordSet) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	var wordSets []models.WordSet
+	for _, ws := range sets {
+		if !ws.IsGlobal {
+			continue
+		}
+		out, err := copyWordSet(ws)
+		if err != nil {
+			return nil, err
+		}
+		wordSets = append(wordSets, *out)
+	}
+	return wordSets, nil
+}
+
+// IsGlobalWordSet checks if a word set is global (curated)
+func (m *Memory) IsGlobalWordSet(_ context.Context, wordSetID string) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ws, ok := m.wordSets[wordSetID]
+	if !ok {
+		return false, ErrWordSetNotFound
+	}
+	return ws.IsGlobal, nil
+}
+
+// CreateWordSet stores the columns Postgres writes; curated-only fields such as
+// sentences and spelling focus are dropped, as they are only seeded by migrations
+func (m *Memory) CreateWordSet(_ context.Context, ws *models.WordSet) error {
+	if ws.ID == "" {
+		ws.ID = uuid.New().String()
+	}
+
+	stored, err := copyWordSet(&models.WordSet{
+		ID:                ws.ID,
+		Name:              ws.Name,
+		FamilyID:          ws.FamilyID,
+		IsGlobal:          ws.IsGlobal,
+		CreatedBy:         ws.CreatedBy,
+		Language:          ws.Language,
+		TestConfiguration: ws.TestConfiguration,
+		TargetGrade:       ws.TargetGrade,
+		Difficulty:        ws.Difficulty,
+		CreatedAt:         ws.CreatedAt,
+		UpdatedAt:         ws.UpdatedAt,
+		Words:             ws.Words,
+	})
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableWordSets)
+
+	if _, exists := m.wordSets[ws.ID]; exists {
+		return fmt.Errorf("failed to create word set: %w", ErrDuplicate)
+	}
+	if err := cmp.Or(requireRows(m.families, deref(ws.FamilyID)), requireRows(m.users, ws.CreatedBy)); err != nil {
+		return fmt.Errorf("failed to create word set: %w", err)
+	}
+	m.wordSets[ws.ID] = stored
+	return nil
+}
+
+func (m *Memory) UpdateWordSet(_ context.Context, ws *models.WordSet) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableWordSets)
+
+	stored, ok := m.wordSets[ws.ID]
+	if !ok {
+		return ErrWordSetNotFound
+	}
+	if err := copyTestConfig(stored, ws.TestConfiguration); err != nil {
+		return err
+	}
+
+	stored.Name = ws.Name
+	stored.Language = ws.Language
+	stored.TargetGrade = clonePtr(ws.TargetGrade)
+	stored.Difficulty = clonePtr(ws.Difficulty)
+	stored.UpdatedAt = ws.UpdatedAt
+	stored.Words = copyWords(ws.Words)
+	return nil
+}
+
+func (m *Memory) DeleteWordSet(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableWordSets | tableResults | tableAssignments | tableMastery | tableSessions | tablePracticeLists | tableChallengeLocks)
+
+	if _, ok := m.wordSets[id]; !ok {
+		return ErrWordSetNotFound
+	}
+	m.deleteWordSetLocked(id)
+	return nil
+}
+
+// deleteWordSetLocked removes a word set and cascades like the word_sets foreign keys
+func (m *Memory) deleteWordSetLocked(wordSetID string) {
+	delete(m.wordSets, wordSetID)
+	m.deleteResults(func(r *models.TestResult) bool { return r.WordSetID == wordSetID })
+	m.assignments = slices.DeleteFunc(m.assignments, func(a *models.WordSetAssignment) bool { return a.WordSetID == wordSetID })
+	maps.DeleteFunc(m.mastery, func(k masteryKey, _ *models.WordMastery) bool { return k.wordSetID == wordSetID })
+	maps.DeleteFunc(m.sessions, func(_ string, s *models.TestSession) bool { return s.WordSetID == wordSetID })
+	maps.DeleteFunc(m.practiceLists, func(_ string, l *models.PracticeList) bool { return l.WordSetID == wordSetID })
+	maps.DeleteFunc(m.challengeLocks, func(k lockKey, _ *models.ChallengeLock) bool { return k.wordSetID == wordSetID })
+}
+
+// ============================================================================
+// Test Result Operations
+// ============================================================================
+
+// resultColumns selects the optional test result columns a query returns
+type resultColumns struct {
+	xp, key, words bool
+}
+
+var (
+	resultWithWords   = resultColumns{words: true}
+	resultWithXP      = resultColumns{xp: true, words: true}
+	resultWithXPAndID = resultColumns{xp: true, key: true, words: true}
+)
+
+func copyWordResults(words []models.WordTestResult) ([]models.WordTestResult, error) {
+	var out []models.WordTestResult
+	for _, w := range words {
+		wtr := w
+		wtr.UserAnswers = slices.Clone(w.UserAnswers)
+		wtr.ErrorTypes = slices.Clone(w.ErrorTypes)
+		if wtr.ErrorTypes == nil {
+			wtr.ErrorTypes = []string{}
+		}
+		if w.Sentence != nil {
+			wtr.Sentence = &models.SentenceScore{}
+			if err := jsonCopy(w.Sentence, wtr.Sentence); err != nil {
+				return nil, fmt.Errorf("failed to marshal sentence score: %w", err)
+			}
+		}
+		out = append(out, wtr)
+	}
+	return out, nil
+}
+
+// resultView copies a stored result with the columns a query selects
+func resultView(r *models.TestResult, cols resultColumns) models.TestResult {
+	result := models.TestResult{
+		ID:           r.ID,
+		WordSetID:    r.WordSetID,
+		UserID:       r.UserID,
+		Score:        r.Score,
+		TotalWords:   r.TotalWords,
+		CorrectWords: r.CorrectWords,
+		TimeSpent:    r.TimeSpent,
+		Mode:         r.Mode,
+		CompletedAt:  r.CompletedAt,
+		CreatedAt:    r.CreatedAt,
+	}
+	if cols.xp {
+		result.XPAwarded = r.XPAwarded
+	}
+	if cols.key {
+		result.IdempotencyKey = r.IdempotencyKey
+	}
+	if cols.words {
+		// Stored word results were copied on save, so copying again cannot fail
+		result.Words, _ = copyWordResults(r.Words)
+	}
+	return result
+}
+
+// resultsByCompletion returns the results matching a filter, oldest first;
+// results completed at the same time keep the order they were saved in
+func (m *Memory) resultsByCompletion(match func(r *models.TestResult) bool) []*models.TestResult {
+	var results []*models.TestResult
+	for _, r := range m.results {
+		if match(r) {
+			results = append(results, r)
+		}
+	}
+	slices.SortStableFunc(results, func(a, b *models.TestResult) int {
+		return a.CompletedAt.Compare(b.CompletedAt)
+	})
+	return results
+}
+
+// newestResults returns the matching results, newest first
+func (m *Memory) newestResults(match func(r *models.TestResult) bool) []*models.TestResult {
+	results := m.resultsByCompletion(match)
+	slices.Reverse(results)
+	return results
+}
+
+func (m *Memory) GetTestResults(_ context.Context, userID string) ([]models.TestResult, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var results []models.TestResult
+	for _, r := range m.newestResults(func(r *models.TestResult) bool { return r.UserID == userID }) {
+		results = append(results, resultView(r, resultWithWords))
+	}
+	return results, nil
+}
+
+func (m *Memory) GetFamilyResults(_ context.Context, familyID string) ([]models.TestResult, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	inFamily := func(r *models.TestResult) bool {
+		u, ok := m.users[r.UserID]
+		return ok && u.FamilyID == familyID
+	}
+
+	var results []models.TestResult
+	for _, r := range m.newestResults(inFamily) {
+		results = append(results, resultView(r, resultWithWords))
+	}
+	return results, nil
+}
+
+func (m *Memory) ListTestResults(_ context.Context, filter models.ResultFilter, page models.PageRequest) ([]models.TestResult, string, error) {
+	after, err := decodeCursor(page.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var matching []*models.TestResult
+	for _, r := range m.results {
+		if m.resultMatches(r, filter) && after.admits(r.CompletedAt, r.ID) {
+			matching = append(matching, r)
+		}
+	}
+	slices.SortFunc(matching, func(a, b *models.TestResult) int {
+		return cmp.Or(b.CompletedAt.Compare(a.CompletedAt), cmp.Compare(b.ID, a.ID))
+	})
+	if page.Limit > 0 {
+		matching = limited(matching, page.Limit+1)
+	}
+
+	results := []models.TestResult{}
+	for _, r := range matching {
+		results = append(results, resultView(r, resultWithWords))
+	}
+	results, next := trimPage(results, page.Limit, func(r models.TestResult) (time.Time, string) { return r.CompletedAt, r.ID })
+	return results, next, nil
+}
+
+func (m *Memory) resultMatches(r *models.TestResult, filter models.ResultFilter) bool {
+	if filter.FamilyID != "" {
+		u, ok := m.users[r.UserID]
+		if !ok || u.FamilyID != filter.FamilyID {
+			return false
+		}
+	}
+	switch {
+	case filter.UserID != "" && r.UserID != filter.UserID,
+		filter.WordSetID != "" && r.WordSetID != filter.WordSetID,
+		filter.Mode != "" && r.Mode != filter.Mode,
+		filter.From != nil && r.CompletedAt.Before(*filter.From),
+		filter.To != nil && !r.CompletedAt.Before(*filter.To),
+		filter.MinScore != nil && r.Score < *filter.MinScore:
+		return false
+	}
+	return true
+}
+
+// GetRecentWordSetResults retrieves a user's latest results for a word set, newest first
+func (m *Memory) GetRecentWordSetResults(_ context.Context, userID, wordSetID string, limit int) ([]models.TestResult, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	matching := m.newestResults(func(r *models.TestResult) bool {
+		return r.UserID == userID && r.WordSetID == wordSetID
+	})
+
+	results := []models.TestResult{}
+	for _, r := range limited(matching, limit) {
+		results = append(results, resultView(r, resultWithXP))
+	}
+	return results, nil
+}
+
+// GetTestResultByIdempotencyKey retrieves a user's result saved with the given key
+func (m *Memory) GetTestResultByIdempotencyKey(_ context.Context, userID, key string) (*models.TestResult, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, r := range m.results {
+		if r.UserID == userID && r.IdempotencyKey != "" && r.IdempotencyKey == key {
+			result := resultView(r, resultWithXPAndID)
+			return &result, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (m *Memory) SaveTestResult(_ context.Context, result *models.TestResult) error {
+	if result.ID == "" {
+		result.ID = uuid.New().String()
+	}
+
+	words, err := copyWordResults(result.Words)
+	if err != nil {
+		return err
+	}
+	stored := resultView(result, resultWithXPAndID)
+	stored.Words = words
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableResults)
+
+	for _, r := range m.results {
+		if r.ID == result.ID {
+			return fmt.Errorf("failed to save test result: %w", ErrDuplicate)
+		}
+		if result.IdempotencyKey != "" && r.UserID == result.UserID && r.IdempotencyKey == result.IdempotencyKey {
+			return ErrDuplicate
+		}
+	}
+	if err := cmp.Or(requireRows(m.wordSets, result.WordSetID), requireRows(m.users, result.UserID)); err != nil {
+		return fmt.Errorf("failed to save test result: %w", err)
+	}
+	m.results = append(m.results, &stored)
+	return nil
+}
+
+// deleteResults removes the matching results and clears test sessions that
+// point at them, like the test_sessions.result_id foreign key
+func (m *Memory) deleteResults(match func(r *models.TestResult) bool) {
+	m.results = slices.DeleteFunc(m.results, func(r *models.TestResult) bool {
+		if !match(r) {
+			return false
+		}
+		for _, s := range m.sessions {
+			if s.ResultID != nil && *s.ResultID == r.ID {
+				s.ResultID = nil
+			}
+		}
+		return true
+	})
+}
+
+// ============================================================================
+// Audio File Operations
+// ============================================================================
+
+func (m *Memory) GetAudioFile(_ context.Context, word, language, voiceID string) (*models.AudioFile, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	af, ok := m.audioFiles[audioKey{word, language, voiceID}]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	audioFile := *af
+	return &audioFile, nil
+}
+
+func (m *Memory) SaveAudioFile(_ context.Context, af *models.AudioFile) error {
+	if af.ID == "" {
+		af.ID = uuid.New().String()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableAudioFiles)
+
+	key := audioKey{af.Word, af.Language, af.VoiceID}
+	if existing, ok := m.audioFiles[key]; ok {
+		existing.URL = af.URL
+		return nil
+	}
+	stored := *af
+	m.audioFiles[key] = &stored
+	return nil
+}
+
+// ============================================================================
+// Progress and Stats Operations
+// ============================================================================
+
+// userStats fills the test counts, averages and last activity of a user
+func (m *Memory) userStats(fp *models.FamilyProgress) {
+	fp.LastActivity = time.Now()
+	var scoreSum float64
+	for _, r := range m.resultsByCompletion(func(r *models.TestResult) bool { return r.UserID == fp.UserID }) {
+		fp.TotalTests++
+		scoreSum += r.Score
+		fp.TotalWords += r.TotalWords
+		fp.CorrectWords += r.CorrectWords
+		fp.LastActivity = r.CompletedAt
+	}
+	if fp.TotalTests > 0 {
+		fp.AverageScore = scoreSum / float64(fp.TotalTests)
+	}
+}
+
+// masteryStats counts the distinct words a user has mastered per mode
+// (mastery threshold is 2 correct answers)
+func (m *Memory) masteryStats(fp *models.FamilyProgress) {
+	words := map[string]struct{}{}
+	mastered := make([]map[string]struct{}, 5)
+	for i := range mastered {
+		mastered[i] = map[string]struct{}{}
+	}
+
+	for k, wm := range m.mastery {
+		if k.userID != fp.UserID {
+			continue
+		}
+		words[k.word] = struct{}{}
+		counts := []int{wm.LetterTilesCorrect, wm.WordBankCorrect, wm.KeyboardCorrect, wm.MissingLettersCorrect, wm.TranslationCorrect}
+		for i, correct := range counts {
+			if correct >= 2 {
+				mastered[i][k.word] = struct{}{}
+			}
+		}
+	}
+
+	fp.TotalWordsWithMastery = len(words)
+	fp.LetterTilesMasteredWords = len(mastered[0])
+	fp.WordBankMasteredWords = len(mastered[1])
+	fp.KeyboardMasteredWords = len(mastered[2])
+	fp.MissingLettersMasteredWords = len(mastered[3])
+	fp.TranslationMasteredWords = len(mastered[4])
+}
+
+// familyUsers returns the users who are members of a family
+func (m *Memory) familyUsers(familyID string) []*models.User {
+	var users []*models.User
+	for _, fm := range m.members {
+		if u, ok := m.users[fm.userID]; ok && fm.familyID == familyID {
+			users = append(users, u)
+		}
+	}
+	return users
+}
+
+func (m *Memory) GetFamilyProgress(_ context.Context, familyID string) ([]models.FamilyProgress, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var progress []models.FamilyProgress
+	for _, u := range m.familyUsers(familyID) {
+		fp := models.FamilyProgress{
+			UserID:    u.ID,
+			UserName:  u.DisplayName,
+			Role:      u.Role,
+			BirthYear: clonePtr(u.BirthYear),
+			TotalXP:   u.TotalXP,
+			Level:     u.Level,
+		}
+		m.userStats(&fp)
+
+		recent := m.newestResults(func(r *models.TestResult) bool { return r.UserID == u.ID })
+		for _, r := range limited(recent, 5) {
+			fp.RecentResults = append(fp.RecentResults, models.TestResult{
+				ID:           r.ID,
+				WordSetID:    r.WordSetID,
+				UserID:       r.UserID,
+				Score:        r.Score,
+				TotalWords:   r.TotalWords,
+				CorrectWords: r.CorrectWords,
+				TimeSpent:    r.TimeSpent,
+				CompletedAt:  r.CompletedAt,
+				CreatedAt:    r.CreatedAt,
+			})
+		}
+
+		m.masteryStats(&fp)
+		progress = append(progress, fp)
+	}
+	return progress, nil
+}
+
+func (m *Memory) GetFamilyStats(_ context.Context, familyID string) (*models.FamilyStats, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	stats := &models.FamilyStats{LastActivity: time.Now()}
+	var scoreSum float64
+	mostTests := -1
+	for _, u := range m.familyUsers(familyID) {
+		stats.TotalMembers++
+		results := m.resultsByCompletion(func(r *models.TestResult) bool { return r.UserID == u.ID })
+		for _, r := range results {
+			scoreSum += r.Score
+			if stats.TotalTestsCompleted == 0 || r.CompletedAt.After(stats.LastActivity) {
+				stats.LastActivity = r.CompletedAt
+			}
+			stats.TotalTestsCompleted++
+		}
+		if u.Role == "child" {
+			stats.TotalChildren++
+			if len(results) > mostTests {
+				mostTests = len(results)
+				stats.MostActiveChild = &u.ID
+			}
+		}
+	}
+	if stats.TotalTestsCompleted > 0 {
+		stats.AverageFamilyScore = scoreSum / float64(stats.TotalTestsCompleted)
+	}
+
+	for _, ws := range m.wordSets {
+		if ws.FamilyID != nil && *ws.FamilyID == familyID {
+			stats.TotalWordSets++
+		}
+	}
+	stats.MostActiveChild = clonePtr(stats.MostActiveChild)
+	return stats, nil
+}
+
+func (m *Memory) GetUserProgress(_ context.Context, userID string) (*models.FamilyProgress, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	u, ok := m.users[userID]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+
+	fp := models.FamilyProgress{
+		UserID:   u.ID,
+		UserName: u.DisplayName,
+		Role:     u.Role,
+		TotalXP:  u.TotalXP,
+		Level:    u.Level,
+	}
+	m.userStats(&fp)
+	return &fp, nil
+}
+
+// ============================================================================
+// Family Invitation Operations
+// ============================================================================
+
+// invitationsByCreation returns the matching invitations, newest first
+func (m *Memory) invitationsByCreation(match func(i *models.FamilyInvitation) bool) []models.FamilyInvitation {
+	var invitations []models.FamilyInvitation
+	for _, inv := range m.invitations {
+		if match(inv) {
+			invitation := *inv
+			invitation.ExpiresAt = clonePtr(inv.ExpiresAt)
+			invitations = append(invitations, invitation)
+		}
+	}
+	slices.SortStableFunc(invitations, func(a, b models.FamilyInvitation) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return invitations
+}
+
+func (m *Memory) CreateFamilyInvitation(_ context.Context, invitation *models.FamilyInvitation) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableInvitations)
+
+	for _, inv := range m.invitations {
+		if inv.ID == invitation.ID {
+			return fmt.Errorf("failed to create family invitation: %w", ErrDuplicate)
+		}
+		// Only one pending invitation per email and family
+		if invitation.Status == "pending" && inv.Status == "pending" &&
+			inv.FamilyID == invitation.FamilyID && strings.EqualFold(inv.Email, invitation.Email) {
+			return fmt.Errorf("invitation already exists for this email and family")
+		}
+	}
+
+	if err := cmp.Or(requireRows(m.families, invitation.FamilyID), requireRows(m.users, invitation.InvitedBy)); err != nil {
+		return fmt.Errorf("failed to create family invitation: %w", err)
+	}
+	stored := *invitation
+	stored.FamilyName = ""
+	stored.ExpiresAt = clonePtr(invitation.ExpiresAt)
+	m.invitations = append(m.invitations, &stored)
+	return nil
+}
+
+func (m *Memory) GetPendingInvitationsByEmail(_ context.Context, email string) ([]models.FamilyInvitation, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	invitations := m.invitationsByCreation(func(inv *models.FamilyInvitation) bool {
+		_, hasFamily := m.families[inv.FamilyID]
+		return hasFamily && inv.Status == "pending" && strings.EqualFold(inv.Email, email)
+	})
+	for i := range invitations {
+		invitations[i].FamilyName = m.families[invitations[i].FamilyID].Name
+	}
+	return invitations, nil
+}
+
+func (m *Memory) GetFamilyInvitations(_ context.Context, familyID string) ([]models.FamilyInvitation, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.invitationsByCreation(func(inv *models.FamilyInvitation) bool {
+		return inv.FamilyID == familyID
+	}), nil
+}
+
+func (m *Memory) AcceptInvitation(_ context.Context, invitationID, userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableInvitations | tableMembers | tableUsers)
+
+	i := slices.IndexFunc(m.invitations, func(inv *models.FamilyInvitation) bool {
+		return inv.ID == invitationID && inv.Status == "pending"
+	})
+	if i < 0 {
+		return fmt.Errorf("invitation not found or already processed")
+	}
+	inv := m.invitations[i]
+
+	u, ok := m.users[userID]
+	if !ok {
+		return fmt.Errorf("failed to add family member: %w", ErrUserNotFound)
+	}
+
+	u.FamilyID = inv.FamilyID
+	m.addMemberLocked(inv.FamilyID, userID, inv.Role)
+	m.invitations = slices.Delete(m.invitations, i, i+1)
+	return nil
+}
+
+func (m *Memory) DeleteInvitation(_ context.Context, invitationID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableInvitations)
+
+	i := slices.IndexFunc(m.invitations, func(inv *models.FamilyInvitation) bool { return inv.ID == invitationID })
+	if i < 0 {
+		return fmt.Errorf("invitation not found")
+	}
+	m.invitations = slices.Delete(m.invitations, i, i+1)
+	return nil
+}
+
+// ============================================================================
+// Verification Operations
+// ============================================================================
+
+func (m *Memory) memberRole(userID, familyID string) (string, bool) {
+	for _, fm := range m.members {
+		if fm.userID == userID && fm.familyID == familyID {
+			return fm.role, true
+		}
+	}
+	return "", false
+}
+
+func (m *Memory) VerifyFamilyMembership(_ context.Context, userID, familyID string) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if _, ok := m.memberRole(userID, familyID); !ok {
+		return ErrNotFamilyMember
+	}
+	return nil
+}
+
+func (m *Memory) VerifyParentPermission(_ context.Context, userID, familyID string) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if role, ok := m.memberRole(userID, familyID); !ok || role != "parent" {
+		return ErrNotParent
+	}
+	return nil
+}
+
+func (m *Memory) VerifyChildOwnership(_ context.Context, parentID, childID string) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	u, ok := m.child(childID)
+	if !ok || u.ParentID == nil || *u.ParentID != parentID {
+		return ErrNotChildOwner
+	}
+	return nil
+}
+
+func (m *Memory) VerifyWordSetAccess(_ context.Context, familyID, wordSetID string) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Allow access if word set belongs to family OR if it's a global (curated) word set
+	ws, ok := m.wordSets[wordSetID]
+	if !ok || !ws.IsGlobal && (ws.FamilyID == nil || *ws.FamilyID != familyID) {
+		return ErrNoAccess
+	}
+	return nil
+}
+
+// ============================================================================
+// Word Set Assignment Operations
+// ============================================================================
+
+// assignmentsByAssignedAt returns all assignments, earliest first
+func (m *Memory) assignmentsByAssignedAt() []*models.WordSetAssignment {
+	assignments := slices.Clone(m.assignments)
+	slices.SortStableFunc(assignments, func(a, b *models.WordSetAssignment) int {
+		return a.AssignedAt.Compare(b.AssignedAt)
+	})
+	return assignments
+}
+
+func (m *Memory) assignment(wordSetID, userID string) *models.WordSetAssignment {
+	for _, a := range m.assignments {
+		if a.WordSetID == wordSetID && a.UserID == userID {
+			return a
+		}
+	}
+	return nil
+}
+
+// assignmentView copies an assignment with its word set name and the child's family
+func (m *Memory) assignmentView(a *models.WordSetAssignment) (models.WordSetAssignment, bool) {
+	ws, hasWordSet := m.wordSets[a.WordSetID]
+	u, hasUser := m.users[a.UserID]
+	if !hasWordSet || !hasUser {
+		return models.WordSetAssignment{}, false
+	}
+
+	out := *a
+	out.WordSetName = ws.Name
+	out.FamilyID = u.FamilyID
+	out.DueAt = clonePtr(a.DueAt)
+	out.Goal = clonePtr(a.Goal)
+	out.LastRemindedAt = clonePtr(a.LastRemindedAt)
+	return out, true
+}
+
+func (m *Memory) AssignWordSetToUser(_ context.Context, wordSetID, userID, assignedBy string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableAssignments)
+
+	// Verify the user is a child
+	u, ok := m.users[userID]
+	if !ok {
+		return fmt.Errorf("user not found")
+	}
+	if u.Role != "child" {
+		return fmt.Errorf("only children can be assigned to wordsets")
+	}
+
+	if m.assignment(wordSetID, userID) != nil {
+		return nil
+	}
+	if err := requireRows(m.wordSets, wordSetID); err != nil {
+		return fmt.Errorf("failed to assign wordset to user: %w", err)
+	}
+	m.assignments = append(m.assignments, &models.WordSetAssignment{
+		WordSetID:  wordSetID,
+		UserID:     userID,
+		AssignedBy: assignedBy,
+		AssignedAt: time.Now(),
+		AssignmentSchedule: models.AssignmentSchedule{
+			ReminderFrequency: models.ReminderNone,
+			ReminderHour:      17,
+		},
+	})
+	return nil
+}
+
+func (m *Memory) UnassignWordSetFromUser(_ context.Context, wordSetID, userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableAssignments)
+
+	n := len(m.assignments)
+	m.assignments = slices.DeleteFunc(m.assignments, func(a *models.WordSetAssignment) bool {
+		return a.WordSetID == wordSetID && a.UserID == userID
+	})
+	if len(m.assignments) == n {
+		return fmt.Errorf("assignment not found")
+	}
+	return nil
+}
+
+func (m *Memory) GetWordSetAssignments(_ context.Context, wordSetID string) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.wordSetAssignees(wordSetID), nil
+}
+
+func (m *Memory) SetWordSetAssignmentSchedule(_ context.Context, wordSetID, userID string, schedule *models.AssignmentSchedule) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableAssignments)
+
+	a := m.assignment(wordSetID, userID)
+	if a == nil {
+		return ErrNotFound
+	}
+
+	a.DueAt = clonePtr(schedule.DueAt)
+	a.Goal = clonePtr(schedule.Goal)
+	a.ReminderFrequency = schedule.ReminderFrequency
+	if a.ReminderFrequency == "" {
+		a.ReminderFrequency = models.ReminderNone
+	}
+	a.ReminderHour = schedule.ReminderHour
+	return nil
+}
+
+func (m *Memory) GetUserAssignments(_ context.Context, userID string) ([]models.WordSetAssignment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	assignments := []models.WordSetAssignment{}
+	for _, a := range m.assignments {
+		if view, ok := m.assignmentView(a); ok && a.UserID == userID {
+			assignments = append(assignments, view)
+		}
+	}
+
+	// Due dates first, soonest first; then the most recently assigned
+	slices.SortStableFunc(assignments, func(a, b models.WordSetAssignment) int {
+		switch {
+		case a.DueAt != nil && b.DueAt != nil && !a.DueAt.Equal(*b.DueAt):
+			return a.DueAt.Compare(*b.DueAt)
+		case a.DueAt != nil && b.DueAt == nil:
+			return -1
+		case a.DueAt == nil && b.DueAt != nil:
+			return 1
+		}
+		return b.AssignedAt.Compare(a.AssignedAt)
+	})
+	return assignments, nil
+}
+
+func (m *Memory) GetReminderAssignments(_ context.Context) ([]models.WordSetAssignment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	now := time.Now()
+	assignments := []models.WordSetAssignment{}
+	for _, a := range m.assignmentsByAssignedAt() {
+		view, ok := m.assignmentView(a)
+		if !ok || a.ReminderFrequency == models.ReminderNone || !m.users[a.UserID].IsActive {
+			continue
+		}
+		if a.DueAt == nil || a.DueAt.After(now) {
+			assignments = append(assignments, view)
+		}
+	}
+
+	slices.SortStableFunc(assignments, func(a, b models.WordSetAssignment) int {
+		return strings.Compare(a.UserID, b.UserID)
+	})
+	return assignments, nil
+}
+
+func (m *Memory) ClaimAssignmentReminder(_ context.Context, wordSetID, userID string, slot, remindedAt time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableAssignments)
+
+	a := m.assignment(wordSetID, userID)
+	if a == nil || (a.LastRemindedAt != nil && !a.LastRemindedAt.Before(slot)) {
+		return false, nil
+	}
+	a.LastRemindedAt = &remindedAt
+	return true, nil
+}
+
+// ============================================================================
+// Word Mastery Operations
+// ============================================================================
+
+// GetWordMastery retrieves mastery for a specific word
+func (m *Memory) GetWordMastery(_ context.Context, userID, wordSetID, word string) (*models.WordMastery, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	wm, ok := m.mastery[masteryKey{userID, wordSetID, word}]
+	if !ok {
+		return nil, nil // No mastery record yet, not an error
+	}
+	mastery := *wm
+	return &mastery, nil
+}
+
+// GetWordSetMastery retrieves all mastery records for a user's word set
+func (m *Memory) GetWordSetMastery(_ context.Context, userID, wordSetID string) ([]models.WordMastery, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var mastery []models.WordMastery
+	for k, wm := range m.mastery {
+		if k.userID == userID && k.wordSetID == wordSetID {
+			mastery = append(mastery, *wm)
+		}
+	}
+	slices.SortFunc(mastery, func(a, b models.WordMastery) int { return strings.Compare(a.Word, b.Word) })
+	return mastery, nil
+}
+
+// IncrementMastery increments the mastery counter for a specific test mode
+// letterTiles, wordBank, keyboard, missingLetters, and translation modes have mastery tracking
+// flashcard and lookCoverWrite are self-reported and don't track mastery
+func (m *Memory) IncrementMastery(_ context.Context, userID, wordSetID, word string, mode models.TestMode) (*models.WordMastery, error) {
+	counter := func(wm *models.WordMastery) *int {
+		switch mode {
+		case models.TestModeLetterTiles:
+			return &wm.LetterTilesCorrect
+		case models.TestModeWordBank:
+			return &wm.WordBankCorrect
+		case models.TestModeKeyboard:
+			return &wm.KeyboardCorrect
+		case models.TestModeMissingLetters:
+			return &wm.MissingLettersCorrect
+		case models.TestModeTranslation:
+			return &wm.TranslationCorrect
+		}
+		return nil
+	}
+	if counter(&models.WordMastery{}) == nil {
+		return nil, fmt.Errorf("mode %s does not have mastery tracking", mode)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableMastery)
+
+	now := time.Now()
+	key := masteryKey{userID, wordSetID, word}
+	wm, ok := m.mastery[key]
+	if !ok {
+		if err := cmp.Or(requireRows(m.users, userID), requireRows(m.wordSets, wordSetID)); err != nil {
+			return nil, fmt.Errorf("failed to increment mastery: %w", err)
+		}
+		wm = &models.WordMastery{
+			ID:        uuid.New().String(),
+			UserID:    userID,
+			WordSetID: wordSetID,
+			Word:      word,
+			CreatedAt: now,
+		}
+		m.mastery[key] = wm
+	}
+	*counter(wm)++
+	wm.UpdatedAt = now
+
+	mastery := *wm
+	return &mastery, nil
+}
+
+// ============================================================================
+// XP Operations
+// ============================================================================
+
+// GetUserXP returns the user's current XP and level
+func (m *Memory) GetUserXP(_ context.Context, userID string) (totalXP int, level int, err error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	u, ok := m.users[userID]
+	if !ok {
+		return 0, 1, ErrUserNotFound
+	}
+	return u.TotalXP, u.Level, nil
+}
+
+// UpdateUserXP updates the user's total XP and level
+func (m *Memory) UpdateUserXP(_ context.Context, userID string, xpAwarded, newTotalXP, newLevel int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableUsers)
+
+	u, ok := m.users[userID]
+	if !ok {
+		return ErrUserNotFound
+	}
+	u.TotalXP = newTotalXP
+	u.Level = newLevel
+	return nil
+}
+
+// GetRecentCompletions returns how many times a user has completed a word set + mode
+// combination in the window [since, until)
+func (m *Memory) GetRecentCompletions(_ context.Context, userID, wordSetID, mode string, since, until time.Time) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	count := 0
+	for _, r := range m.results {
+		if r.UserID == userID && r.WordSetID == wordSetID && r.Mode == mode &&
+			!r.CompletedAt.Before(since) && r.CompletedAt.Before(until) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// IsFirstCompletion checks if this is the user's first completion of this word set + mode
+func (m *Memory) IsFirstCompletion(_ context.Context, userID, wordSetID, mode string) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	exists := slices.ContainsFunc(m.results, func(r *models.TestResult) bool {
+		return r.UserID == userID && r.WordSetID == wordSetID && r.Mode == mode
+	})
+	return !exists, nil
+}
+
+// ============================================================================
+// Family API Key Operations
+// ============================================================================
+
+func (m *Memory) CreateFamilyAPIKey(_ context.Context, key *models.FamilyAPIKey) error {
+	if key.ID == "" {
+		key.ID = uuid.New().String()
+	}
+	if key.CreatedAt.IsZero() {
+		key.CreatedAt = time.Now()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableAPIKeys)
+
+	_, exists := m.apiKeys[key.ID]
+	for _, k := range m.apiKeys {
+		exists = exists || k.KeyHash == key.KeyHash
+	}
+	if exists {
+		return fmt.Errorf("failed to create family API key: %w", ErrDuplicate)
+	}
+
+	if err := requireRows(m.families, key.FamilyID); err != nil {
+		return fmt.Errorf("failed to create family API key: %w", err)
+	}
+	stored := *key
+	stored.LastUsedAt = nil
+	m.apiKeys[key.ID] = &stored
+	return nil
+}
+
+func (m *Memory) GetFamilyAPIKeyByHash(_ context.Context, keyHash string) (*models.FamilyAPIKey, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, k := range m.apiKeys {
+		if k.KeyHash == keyHash {
+			key := *k
+			key.LastUsedAt = clonePtr(k.LastUsedAt)
+			return &key, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (m *Memory) GetFamilyAPIKeys(_ context.Context, familyID string) ([]models.FamilyAPIKey, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	sorted := sortedValues(m.apiKeys, func(a, b *models.FamilyAPIKey) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+
+	keys := []models.FamilyAPIKey{}
+	for _, k := range sorted {
+		if k.FamilyID == familyID {
+			key := *k
+			key.KeyHash = ""
+			key.LastUsedAt = clonePtr(k.LastUsedAt)
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (m *Memory) DeleteFamilyAPIKey(_ context.Context, familyID, keyID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableAPIKeys)
+
+	k, ok := m.apiKeys[keyID]
+	if !ok || k.FamilyID != familyID {
+		return ErrNotFound
+	}
+	delete(m.apiKeys, keyID)
+	return nil
+}
+
+func (m *Memory) TouchFamilyAPIKey(_ context.Context, keyID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableAPIKeys)
+
+	if k, ok := m.apiKeys[keyID]; ok {
+		now := time.Now()
+		k.LastUsedAt = &now
+	}
+	return nil
+}
+
+// ============================================================================
+// Webhook Operations
+// ============================================================================
+
+// webhooksByCreation returns all webhooks, newest first
+func (m *Memory) webhooksByCreation() []*models.Webhook {
+	return sortedValues(m.webhooks, func(a, b *models.Webhook) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+}
+
+// deleteWebhooks removes the matching webhooks and their deliveries
+func (m *Memory) deleteWebhooks(match func(w *models.Webhook) bool) {
+	maps.DeleteFunc(m.webhooks, func(_ string, w *models.Webhook) bool { return match(w) })
+	m.deliveries = slices.DeleteFunc(m.deliveries, func(d *models.WebhookDelivery) bool {
+		_, ok := m.webhooks[d.WebhookID]
+		return !ok
+	})
+}
+
+func copyDelivery(d *models.WebhookDelivery) models.WebhookDelivery {
+	delivery := *d
+	delivery.LastAttemptAt = clonePtr(d.LastAttemptAt)
+	delivery.DeliveredAt = clonePtr(d.DeliveredAt)
+	delivery.ResponseStatus = clonePtr(d.ResponseStatus)
+	delivery.LastError = clonePtr(d.LastError)
+	delivery.Payload = slices.Clone(d.Payload)
+	delivery.URL = ""
+	delivery.Secret = ""
+	return delivery
+}
+
+func (m *Memory) CreateWebhook(_ context.Context, webhook *models.Webhook) error {
+	if webhook.ID == "" {
+		webhook.ID = uuid.New().String()
+	}
+	now := time.Now()
+	if webhook.CreatedAt.IsZero() {
+		webhook.CreatedAt = now
+	}
+	webhook.UpdatedAt = now
+	if webhook.EventTypes == nil {
+		webhook.EventTypes = []string{}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableWebhooks)
+
+	if _, exists := m.webhooks[webhook.ID]; exists {
+		return fmt.Errorf("failed to create webhook: %w", ErrDuplicate)
+	}
+	if err := cmp.Or(requireRows(m.families, webhook.FamilyID), requireRows(m.users, webhook.CreatedBy)); err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+	stored := *webhook
+	stored.Description = clonePtr(webhook.Description)
+	stored.EventTypes = slices.Clone(webhook.EventTypes)
+	m.webhooks[webhook.ID] = &stored
+	return nil
+}
+
+func (m *Memory) GetFamilyWebhooks(_ context.Context, familyID string) ([]models.Webhook, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	webhooks := []models.Webhook{}
+	for _, w := range m.webhooksByCreation() {
+		if w.FamilyID == familyID {
+			webhook := *w
+			webhook.Secret = ""
+			webhook.Description = clonePtr(w.Description)
+			webhook.EventTypes = slices.Clone(w.EventTypes)
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	return webhooks, nil
+}
+
+func (m *Memory) DeleteWebhook(_ context.Context, familyID, webhookID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableWebhooks | tableDeliveries)
+
+	w, ok := m.webhooks[webhookID]
+	if !ok || w.FamilyID != familyID {
+		return ErrNotFound
+	}
+	m.deleteWebhooks(func(w *models.Webhook) bool { return w.ID == webhookID })
+	return nil
+}
+
+func (m *Memory) GetWebhookDeliveries(_ context.Context, familyID, webhookID string, limit int) ([]models.WebhookDelivery, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Scope to the family first so a foreign webhook ID is indistinguishable from a missing one
+	w, ok := m.webhooks[webhookID]
+	if !ok || w.FamilyID != familyID {
+		return nil, ErrNotFound
+	}
+
+	deliveries := []models.WebhookDelivery{}
+	for _, d := range m.deliveries {
+		if d.WebhookID == webhookID {
+			deliveries = append(deliveries, copyDelivery(d))
+		}
+	}
+	slices.Reverse(deliveries)
+	slices.SortStableFunc(deliveries, func(a, b models.WebhookDelivery) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return limited(deliveries, limit), nil
+}
+
+func (m *Memory) EnqueueWebhookDeliveries(_ context.Context, familyID, eventID, eventType string, payload []byte) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableDeliveries)
+
+	// One outbox row per active webhook subscribed to this event type (empty list = all types)
+	now := time.Now()
+	count := 0
+	for _, w := range m.webhooksByCreation() {
+		subscribed := len(w.EventTypes) == 0 || slices.Contains(w.EventTypes, eventType)
+		if w.FamilyID != familyID || !w.IsActive || !subscribed || m.hasDelivery(w.ID, eventID) {
+			continue
+		}
+		m.deliveries = append(m.deliveries, &models.WebhookDelivery{
+			ID:            uuid.New().String(),
+			WebhookID:     w.ID,
+			EventID:       eventID,
+			EventType:     eventType,
+			Payload:       slices.Clone(payload),
+			Status:        models.WebhookDeliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		})
+		count++
+	}
+	return count, nil
+}
+
+func (m *Memory) hasDelivery(webhookID, eventID string) bool {
+	return slices.ContainsFunc(m.deliveries, func(d *models.WebhookDelivery) bool {
+		return d.WebhookID == webhookID && d.EventID == eventID
+	})
+}
+
+func (m *Memory) ClaimWebhookDeliveries(_ context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableDeliveries)
+
+	now := time.Now()
+	var due []*models.WebhookDelivery
+	for _, d := range m.deliveries {
+		if d.Status == models.WebhookDeliveryPending && !d.NextAttemptAt.After(now) {
+			due = append(due, d)
+		}
+	}
+	slices.SortStableFunc(due, func(a, b *models.WebhookDelivery) int {
+		return a.NextAttemptAt.Compare(b.NextAttemptAt)
+	})
+
+	// Push next_attempt_at forward by the lease so the next claim skips these
+	// deliveries; if this process dies mid-delivery they become due again.
+	var deliveries []models.WebhookDelivery
+	for _, d := range limited(due, limit) {
+		d.NextAttemptAt = now.Add(lease)
+		w := m.webhooks[d.WebhookID]
+		deliveries = append(deliveries, models.WebhookDelivery{
+			ID:            d.ID,
+			WebhookID:     d.WebhookID,
+			EventID:       d.EventID,
+			EventType:     d.EventType,
+			Payload:       slices.Clone(d.Payload),
+			Status:        d.Status,
+			Attempts:      d.Attempts,
+			NextAttemptAt: d.NextAttemptAt,
+			CreatedAt:     d.CreatedAt,
+			URL:           w.URL,
+			Secret:        w.Secret,
+		})
+	}
+	return deliveries, nil
+}
+
+func (m *Memory) UpdateWebhookDelivery(_ context.Context, delivery *models.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableDeliveries)
+
+	i := slices.IndexFunc(m.deliveries, func(d *models.WebhookDelivery) bool { return d.ID == delivery.ID })
+	if i < 0 {
+		return ErrNotFound
+	}
+
+	d := m.deliveries[i]
+	d.Status = delivery.Status
+	d.Attempts = delivery.Attempts
+	d.NextAttemptAt = delivery.NextAttemptAt
+	d.LastAttemptAt = clonePtr(delivery.LastAttemptAt)
+	d.ResponseStatus = clonePtr(delivery.ResponseStatus)
+	d.LastError = clonePtr(delivery.LastError)
+	d.DeliveredAt = clonePtr(delivery.DeliveredAt)
+	return nil
+}
+
+// ============================================================================
+// Email Digest Operations
+// ============================================================================
+
+func (m *Memory) GetDigestPreferences(_ context.Context, userID string) (*models.DigestPreferences, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	p, ok := m.digestPrefs[userID]
+	if !ok {
+		// Parents are opted out until they enable the digest
+		return &models.DigestPreferences{
+			UserID:   userID,
+			Language: models.DigestLanguageNorwegian,
+		}, nil
+	}
+	prefs := *p
+	prefs.LastSentAt = clonePtr(p.LastSentAt)
+	return &prefs, nil
+}
+
+func (m *Memory) UpsertDigestPreferences(_ context.Context, prefs *models.DigestPreferences) error {
+	if prefs.Language == "" {
+		prefs.Language = models.DigestLanguageNorwegian
+	}
+	prefs.UpdatedAt = time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableDigestPrefs)
+
+	stored, ok := m.digestPrefs[prefs.UserID]
+	if !ok {
+		if err := requireRows(m.users, prefs.UserID); err != nil {
+			return fmt.Errorf("failed to save digest preferences: %w", err)
+		}
+		stored = &models.DigestPreferences{UserID: prefs.UserID}
+		m.digestPrefs[prefs.UserID] = stored
+	}
+	stored.Enabled = prefs.Enabled
+	stored.Language = prefs.Language
+	stored.UpdatedAt = prefs.UpdatedAt
+	prefs.LastSentAt = clonePtr(stored.LastSentAt)
+	return nil
+}
+
+// dueDigestRecipients must be called with m.mu held
+func (m *Memory) dueDigestRecipients(sentBefore time.Time) []models.DigestRecipient {
+	recipients := []models.DigestRecipient{}
+	for userID, p := range m.digestPrefs {
+		u, ok := m.users[userID]
+		if !ok || !p.Enabled || u.Role != "parent" || !u.IsActive || u.Email == "" || u.FamilyID == "" {
+			continue
+		}
+		if p.LastSentAt == nil || p.LastSentAt.Before(sentBefore) {
+			recipients = append(recipients, models.DigestRecipient{
+				UserID:      u.ID,
+				Email:       u.Email,
+				DisplayName: u.DisplayName,
+				FamilyID:    u.FamilyID,
+				Language:    p.Language,
+				LastSentAt:  clonePtr(p.LastSentAt),
+			})
+		}
+	}
+
+	slices.SortFunc(recipients, func(a, b models.DigestRecipient) int {
+		return cmp.Or(strings.Compare(a.FamilyID, b.FamilyID), strings.Compare(a.UserID, b.UserID))
+	})
+	return recipients
+}
+
+func (m *Memory) ClaimDueDigestRecipients(_ context.Context, sentBefore, claimedAt time.Time) ([]models.DigestRecipient, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableDigestPrefs)
+
+	recipients := m.dueDigestRecipients(sentBefore)
+	for _, r := range recipients {
+		m.digestPrefs[r.UserID].LastSentAt = &claimedAt
+	}
+	return recipients, nil
+}
+
+func (m *Memory) ReleaseDigestClaim(_ context.Context, userID string, claimedAt time.Time, previous *time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableDigestPrefs)
+
+	if p, ok := m.digestPrefs[userID]; ok && p.LastSentAt != nil && p.LastSentAt.Equal(claimedAt) {
+		p.LastSentAt = clonePtr(previous)
+	}
+	return nil
+}
+
+func (m *Memory) GetTestResultsInRange(_ context.Context, userID string, from, to time.Time) ([]models.TestResult, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	matching := m.resultsByCompletion(func(r *models.TestResult) bool {
+		return r.UserID == userID && !r.CompletedAt.Before(from) && r.CompletedAt.Before(to)
+	})
+
+	results := []models.TestResult{}
+	for _, r := range matching {
+		results = append(results, resultView(r, resultWithXP))
+	}
+	return results, nil
+}
+
+// ============================================================================
+// Notification Inbox Operations
+// ============================================================================
+
+func (m *Memory) CreateNotifications(_ context.Context, notifications []models.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	// All notifications are stored or none, like the Postgres transaction
+	stored := make([]*models.Notification, 0, len(notifications))
+	for i := range notifications {
+		n := &notifications[i]
+		if n.ID == "" {
+			n.ID = uuid.New().String()
+		}
+		if n.CreatedAt.IsZero() {
+			n.CreatedAt = time.Now().UTC()
+		}
+		if n.Params == nil {
+			n.Params = map[string]any{}
+		}
+
+		s := *n
+		s.ReadAt = nil
+		if err := jsonCopy(n.Params, &s.Params); err != nil {
+			return fmt.Errorf("failed to encode notification params: %w", err)
+		}
+		stored = append(stored, &s)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableNotifications)
+
+	for _, s := range stored {
+		if slices.ContainsFunc(m.notifications, func(n *models.Notification) bool { return n.ID == s.ID }) {
+			return fmt.Errorf("failed to create notification: %w", ErrDuplicate)
+		}
+		if err := cmp.Or(requireRows(m.users, s.UserID, s.SubjectUserID), requireRows(m.families, s.FamilyID)); err != nil {
+			return fmt.Errorf("failed to create notification: %w", err)
+		}
+	}
+	m.notifications = append(m.notifications, stored...)
+	return nil
+}
+
+// selectNotifications reports whether ids selects a notification; nil selects all
+func selectNotifications(ids []string, id string) bool {
+	return ids == nil || slices.Contains(ids, id)
+}
+
+func (m *Memory) GetNotifications(_ context.Context, userID string, unreadOnly bool, limit int) ([]models.Notification, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	notifications := []models.Notification{}
+	for _, n := range m.notifications {
+		if n.UserID != userID || unreadOnly && n.ReadAt != nil {
+			continue
+		}
+		notification := *n
+		notification.ReadAt = clonePtr(n.ReadAt)
+		// Stored params came from JSON, so copying them again cannot fail
+		_ = jsonCopy(n.Params, &notification.Params)
+		notifications = append(notifications, notification)
+	}
+
+	slices.Reverse(notifications)
+	slices.SortStableFunc(notifications, func(a, b models.Notification) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return limited(notifications, limit), nil
+}
+
+func (m *Memory) CountUnreadNotifications(_ context.Context, userID string) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	count := 0
+	for _, n := range m.notifications {
+		if n.UserID == userID && n.ReadAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *Memory) MarkNotificationsRead(_ context.Context, userID string, ids []string, readAt time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableNotifications)
+
+	count := 0
+	for _, n := range m.notifications {
+		if n.UserID == userID && n.ReadAt == nil && selectNotifications(ids, n.ID) {
+			n.ReadAt = &readAt
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *Memory) DismissNotifications(_ context.Context, userID string, ids []string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableNotifications)
+
+	n := len(m.notifications)
+	m.notifications = slices.DeleteFunc(m.notifications, func(n *models.Notification) bool {
+		return n.UserID == userID && selectNotifications(ids, n.ID)
+	})
+	return n - len(m.notifications), nil
+}
+
+// ============================================================================
+// Test Session Operations
+// ============================================================================
+
+func copySession(s *models.TestSession) (*models.TestSession, error) {
+	session := *s
+	session.CompletedAt = clonePtr(s.CompletedAt)
+	session.ResultID = clonePtr(s.ResultID)
+	session.Words = nil
+	if err := jsonCopy(s.Words, &session.Words); err != nil {
+		return nil, fmt.Errorf("failed to encode session words: %w", err)
+	}
+	return &session, nil
+}
+
+func (m *Memory) CreateTestSession(_ context.Context, session *models.TestSession) error {
+	stored, err := copySession(session)
+	if err != nil {
+		return err
+	}
+	stored.ResultID = nil
+	stored.CompletedAt = nil
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableSessions)
+
+	if _, exists := m.sessions[session.ID]; exists {
+		return fmt.Errorf("failed to create test session: %w", ErrDuplicate)
+	}
+	if err := cmp.Or(requireRows(m.users, session.UserID), requireRows(m.families, session.FamilyID), requireRows(m.wordSets, session.WordSetID)); err != nil {
+		return fmt.Errorf("failed to create test session: %w", err)
+	}
+	m.sessions[session.ID] = stored
+	return nil
+}
+
+func (m *Memory) GetTestSession(_ context.Context, userID, sessionID string) (*models.TestSession, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	s, ok := m.sessions[sessionID]
+	if !ok || s.UserID != userID {
+		return nil, ErrNotFound
+	}
+	return copySession(s)
+}
+
+func (m *Memory) GetActiveTestSessions(_ context.Context, userID string, now time.Time) ([]models.TestSession, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	sorted := sortedValues(m.sessions, func(a, b *models.TestSession) int {
+		return b.LastActivityAt.Compare(a.LastActivityAt)
+	})
+
+	sessions := []models.TestSession{}
+	for _, s := range sorted {
+		if s.UserID != userID || s.Status != models.TestSessionActive || !s.ExpiresAt.After(now) {
+			continue
+		}
+		session, err := copySession(s)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, *session)
+	}
+	return sessions, nil
+}
+
+func (m *Memory) UpdateTestSession(_ context.Context, session *models.TestSession) error {
+	updated, err := copySession(session)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableSessions)
+
+	stored, ok := m.sessions[session.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	if stored.Version != session.Version {
+		return ErrConflict
+	}
+
+	stored.Status = updated.Status
+	stored.Words = updated.Words
+	stored.CurrentIndex = updated.CurrentIndex
+	stored.ResultID = updated.ResultID
+	stored.LastActivityAt = updated.LastActivityAt
+	stored.ExpiresAt = updated.ExpiresAt
+	stored.CompletedAt = updated.CompletedAt
+	stored.Version++
+	session.Version++
+	return nil
+}
+
+func (m *Memory) DeleteExpiredTestSessions(_ context.Context, before time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableSessions)
+
+	n := len(m.sessions)
+	maps.DeleteFunc(m.sessions, func(_ string, s *models.TestSession) bool {
+		return !s.ExpiresAt.After(before)
+	})
+	return n - len(m.sessions), nil
+}
+
+// ============================================================================
+// Analytics Operations
+// ============================================================================
+
+// answeredWord is a word result with the time its test was completed
+type answeredWord struct {
+	completedAt time.Time
+	*models.WordTestResult
+}
+
+// answeredWords returns a user's word results from tests completed in
+// [from, to), oldest first
+func (m *Memory) answeredWords(userID string, from, to time.Time) []answeredWord {
+	var words []answeredWord
+	results := m.resultsByCompletion(func(r *models.TestResult) bool {
+		return r.UserID == userID && !r.CompletedAt.Before(from) && r.CompletedAt.Before(to)
+	})
+	for _, r := range results {
+		for i := range r.Words {
+			words = append(words, answeredWord{completedAt: r.CompletedAt, WordTestResult: &r.Words[i]})
+		}
+	}
+	return words
+}
+
+func addErrorTypes(set map[string]struct{}, errorTypes []string) {
+	for _, et := range errorTypes {
+		set[et] = struct{}{}
+	}
+}
+
+func (m *Memory) GetMissedWords(_ context.Context, userID string, from, to time.Time, limit int) ([]models.MissedWord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	byWord := map[string]*models.MissedWord{}
+	errorTypes := map[string]map[string]struct{}{}
+	var order []string
+	for _, w := range m.answeredWords(userID, from, to) {
+		mw, ok := byWord[w.Word]
+		if !ok {
+			mw = &models.MissedWord{Word: w.Word}
+			byWord[w.Word] = mw
+			errorTypes[w.Word] = map[string]struct{}{}
+			order = append(order, w.Word)
+		}
+		mw.Tests++
+		if !w.Correct {
+			// Error types are collected for misses only
+			mw.Misses++
+			mw.LastMissedAt = w.completedAt
+			addErrorTypes(errorTypes[w.Word], w.ErrorTypes)
+		}
+	}
+
+	words := []models.MissedWord{}
+	for _, word := range order {
+		if mw := byWord[word]; mw.Misses > 0 {
+			mw.ErrorTypes = sortedSet(errorTypes[word])
+			words = append(words, *mw)
+		}
+	}
+	slices.SortFunc(words, func(a, b models.MissedWord) int {
+		return cmp.Or(cmp.Compare(b.Misses, a.Misses), b.LastMissedAt.Compare(a.LastMissedAt), strings.Compare(a.Word, b.Word))
+	})
+	return limited(words, limit), nil
+}
+
+// truncateBucket returns the start of the UTC day, ISO week or month of t, like date_trunc
+func truncateBucket(t time.Time, bucket string) (time.Time, error) {
+	y, mo, d := t.UTC().Date()
+	day := time.Date(y, mo, d, 0, 0, 0, 0, time.UTC)
+	switch bucket {
+	case models.AnalyticsBucketDay:
+		return day, nil
+	case models.AnalyticsBucketWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7)), nil
+	case models.AnalyticsBucketMonth:
+		return time.Date(y, mo, 1, 0, 0, 0, 0, time.UTC), nil
+	}
+	return time.Time{}, fmt.Errorf("unsupported bucket %q", bucket)
+}
+
+func (m *Memory) GetErrorTypeCounts(_ context.Context, userID string, from, to time.Time, bucket string, errorTypes []string) ([]models.ErrorTypeCount, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	type bucketKey struct {
+		start     time.Time
+		errorType string
+	}
+	counts := map[bucketKey]int{}
+	for _, w := range m.answeredWords(userID, from, to) {
+		start, err := truncateBucket(w.completedAt, bucket)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get error type counts: %w", err)
+		}
+		for _, et := range w.ErrorTypes {
+			if len(errorTypes) == 0 || slices.Contains(errorTypes, et) {
+				counts[bucketKey{start, et}]++
+			}
+		}
+	}
+
+	result := []models.ErrorTypeCount{}
+	for k, count := range counts {
+		result = append(result, models.ErrorTypeCount{BucketStart: k.start, ErrorType: k.errorType, Count: count})
+	}
+	slices.SortFunc(result, func(a, b models.ErrorTypeCount) int {
+		return cmp.Or(a.BucketStart.Compare(b.BucketStart), cmp.Compare(b.Count, a.Count), strings.Compare(a.ErrorType, b.ErrorType))
+	})
+	return result, nil
+}
+
+func (m *Memory) GetWordAttemptStats(_ context.Context, userID string, from, to time.Time, limit int) ([]models.WordAttemptStats, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	byWord := map[string]*models.WordAttemptStats{}
+	attempts := map[string]int{}
+	for _, w := range m.answeredWords(userID, from, to) {
+		s, ok := byWord[w.Word]
+		if !ok {
+			s = &models.WordAttemptStats{Word: w.Word}
+			byWord[w.Word] = s
+		}
+		s.Tests++
+		attempts[w.Word] += w.Attempts
+		if w.Correct && w.Attempts == 1 {
+			s.FirstTryCorrect++
+		}
+	}
+
+	stats := []models.WordAttemptStats{}
+	for word, s := range byWord {
+		s.AvgAttempts = float64(attempts[word]) / float64(s.Tests)
+		stats = append(stats, *s)
+	}
+	slices.SortFunc(stats, func(a, b models.WordAttemptStats) int {
+		return cmp.Or(cmp.Compare(b.AvgAttempts, a.AvgAttempts), cmp.Compare(b.Tests, a.Tests), strings.Compare(a.Word, b.Word))
+	})
+	return limited(stats, limit), nil
+}
+
+// regressionHistory tracks first-try correct answers per word while walking a
+// user's answers in order
+type regressionHistory struct {
+	lastMastered map[string]time.Time
+	priorCorrect map[string]int
+}
+
+func (m *Memory) GetRegressedWords(_ context.Context, userID string, from, to time.Time, minPriorCorrect, limit int) ([]models.RegressedWord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	history := regressionHistory{lastMastered: map[string]time.Time{}, priorCorrect: map[string]int{}}
+	byWord := map[string]*models.RegressedWord{}
+	errorTypes := map[string]map[string]struct{}{}
+
+	// Each miss in range is a regression when enough first-try correct answers
+	// came strictly before it
+	for _, w := range m.answeredWords(userID, time.Time{}, to) {
+		prior := history.priorCorrect[w.Word]
+		if !w.Correct && !w.completedAt.Before(from) && prior >= minPriorCorrect {
+			rw, ok := byWord[w.Word]
+			if !ok {
+				rw = &models.RegressedWord{Word: w.Word, PriorCorrect: prior}
+				byWord[w.Word] = rw
+				errorTypes[w.Word] = map[string]struct{}{}
+			}
+			rw.Misses++
+			rw.LastMissedAt = w.completedAt
+			rw.LastMasteredAt = history.lastMastered[w.Word]
+			rw.PriorCorrect = min(rw.PriorCorrect, prior)
+			addErrorTypes(errorTypes[w.Word], w.ErrorTypes)
+		}
+		if w.Correct && w.Attempts == 1 {
+			history.priorCorrect[w.Word]++
+			history.lastMastered[w.Word] = w.completedAt
+		}
+	}
+
+	words := []models.RegressedWord{}
+	for word, rw := range byWord {
+		rw.ErrorTypes = sortedSet(errorTypes[word])
+		words = append(words, *rw)
+	}
+	slices.SortFunc(words, func(a, b models.RegressedWord) int {
+		return cmp.Or(b.LastMissedAt.Compare(a.LastMissedAt), strings.Compare(a.Word, b.Word))
+	})
+	return limited(words, limit), nil
+}
+
+// ============================================================================
+// Practice List Operations
+// ============================================================================
+
+func (m *Memory) GetPracticeList(_ context.Context, childID string) (*models.PracticeList, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	l, ok := m.practiceLists[childID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	list := *l
+	return &list, nil
+}
+
+func (m *Memory) SavePracticeList(_ context.Context, list *models.PracticeList) error {
+	if list.RefreshedAt.IsZero() {
+		list.RefreshedAt = time.Now()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tablePracticeLists)
+
+	// A word set backs at most one practice list
+	for childID, l := range m.practiceLists {
+		if childID != list.ChildID && l.WordSetID == list.WordSetID {
+			return fmt.Errorf("failed to save practice list: %w", ErrDuplicate)
+		}
+	}
+
+	if err := cmp.Or(requireRows(m.users, list.ChildID), requireRows(m.families, list.FamilyID), requireRows(m.wordSets, list.WordSetID)); err != nil {
+		return fmt.Errorf("failed to save practice list: %w", err)
+	}
+
+	stored, ok := m.practiceLists[list.ChildID]
+	if !ok {
+		stored = &models.PracticeList{
+			ChildID:   list.ChildID,
+			FamilyID:  list.FamilyID,
+			CreatedBy: list.CreatedBy,
+			CreatedAt: time.Now(),
+		}
+		m.practiceLists[list.ChildID] = stored
+	}
+	stored.WordSetID = list.WordSetID
+	stored.WindowDays = list.WindowDays
+	stored.MaxWords = list.MaxWords
+	stored.IncludeCurated = list.IncludeCurated
+	stored.RefreshedAt = list.RefreshedAt
+	list.CreatedAt = stored.CreatedAt
+	return nil
+}
+
+// ============================================================================
+// Challenge Lock Operations
+// ============================================================================
+
+func (m *Memory) GetChallengeLock(_ context.Context, wordSetID, childID string) (*models.ChallengeLock, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	l, ok := m.challengeLocks[lockKey{wordSetID, childID}]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	lock := *l
+	return &lock, nil
+}
+
+func (m *Memory) SaveChallengeLock(_ context.Context, lock *models.ChallengeLock) error {
+	if lock.LockedAt.IsZero() {
+		lock.LockedAt = time.Now()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableChallengeLocks)
+
+	if err := cmp.Or(requireRows(m.wordSets, lock.WordSetID), requireRows(m.users, lock.ChildID)); err != nil {
+		return fmt.Errorf("failed to save challenge lock: %w", err)
+	}
+	stored := *lock
+	m.challengeLocks[lockKey{lock.WordSetID, lock.ChildID}] = &stored
+	return nil
+}
+
+func (m *Memory) DeleteChallengeLock(_ context.Context, wordSetID, childID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.own(tableChallengeLocks)
+
+	key := lockKey{wordSetID, childID}
+	if _, ok := m.challengeLocks[key]; !ok {
+		return ErrNotFound
+	}
+	delete(m.challengeLocks, key)
+	return nil
+}
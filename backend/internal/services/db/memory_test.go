@@ -0,0 +1,205 @@
+package db
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemory_Conformance(t *testing.T) {
+	testRepository(t, NewMemory())
+}
+
+func TestNewRepository_Driver(t *testing.T) {
+	repo, err := NewRepository(context.Background(), &Config{Driver: DriverMemory})
+	require.NoError(t, err)
+	assert.IsType(t, &Memory{}, repo)
+
+	_, err = NewRepository(context.Background(), &Config{Driver: "sqlite"})
+	assert.ErrorContains(t, err, `unknown database driver "sqlite"`)
+}
+
+// deepCopy copies every table and row, for comparing states
+func (s *memoryState) deepCopy() memoryState {
+	return memoryState{
+		users:          cloneRows(s.users),
+		families:       cloneRows(s.families),
+		wordSets:       cloneRows(s.wordSets),
+		audioFiles:     cloneRows(s.audioFiles),
+		mastery:        cloneRows(s.mastery),
+		apiKeys:        cloneRows(s.apiKeys),
+		webhooks:       cloneRows(s.webhooks),
+		digestPrefs:    cloneRows(s.digestPrefs),
+		sessions:       cloneRows(s.sessions),
+		practiceLists:  cloneRows(s.practiceLists),
+		challengeLocks: cloneRows(s.challengeLocks),
+		members:        slices.Clone(s.members),
+		assignments:    cloneList(s.assignments),
+		results:        cloneList(s.results),
+		invitations:    cloneList(s.invitations),
+		deliveries:     cloneList(s.deliveries),
+		notifications:  cloneList(s.notifications),
+	}
+}
+
+// TestMemory_WithTx_RollbackLeavesTablesUntouched runs each write in its own
+// failed transaction, so a write that changes a table without copying it
+// first shows up as a change to the committed state
+func TestMemory_WithTx_RollbackLeavesTablesUntouched(t *testing.T) {
+	m := NewMemory()
+	f := newFixture(t, m)
+	ctx := t.Context()
+	now := time.Now().Truncate(time.Second)
+
+	other := newWordSet(t, m, f.family.ID, f.parent.ID, "Uke 2", "sol")
+	saveResult(t, m, f.child.ID, f.wordSet.ID, now, answer("hus", true, 1))
+	require.NoError(t, m.AssignWordSetToUser(ctx, f.wordSet.ID, f.child.ID, f.parent.ID))
+	_, err := m.IncrementMastery(ctx, f.child.ID, f.wordSet.ID, "hus", models.TestModeKeyboard)
+	require.NoError(t, err)
+	require.NoError(t, m.SaveAudioFile(ctx, &models.AudioFile{ID: "audio-1", Word: "hus", Language: "no", VoiceID: "v", URL: "u", CreatedAt: now}))
+	key := &models.FamilyAPIKey{FamilyID: f.family.ID, Name: "key", KeyHash: "hash", CreatedBy: f.parent.ID}
+	require.NoError(t, m.CreateFamilyAPIKey(ctx, key))
+	webhook := &models.Webhook{FamilyID: f.family.ID, URL: "https://example.com", CreatedBy: f.parent.ID, IsActive: true}
+	require.NoError(t, m.CreateWebhook(ctx, webhook))
+	_, err = m.EnqueueWebhookDeliveries(ctx, f.family.ID, "event-1", "test.completed", []byte(`{}`))
+	require.NoError(t, err)
+	require.NoError(t, m.UpsertDigestPreferences(ctx, &models.DigestPreferences{UserID: f.parent.ID, Enabled: true}))
+	invitation := &models.FamilyInvitation{ID: "invite-1", FamilyID: f.family.ID, Email: "new@example.com", Role: "parent", InvitedBy: f.parent.ID, Status: "pending", CreatedAt: now}
+	require.NoError(t, m.CreateFamilyInvitation(ctx, invitation))
+	notifications := []models.Notification{{UserID: f.parent.ID, FamilyID: f.family.ID, SubjectUserID: f.child.ID, Kind: "test.completed", MessageKey: "a"}}
+	require.NoError(t, m.CreateNotifications(ctx, notifications))
+	session := &models.TestSession{
+		ID: "session-1", UserID: f.child.ID, FamilyID: f.family.ID, WordSetID: f.wordSet.ID, Mode: "keyboard",
+		Status: models.TestSessionActive, Words: []models.SessionWord{{Word: "hus", ExpectedAnswer: "hus", Answers: []string{}}},
+		MaxAttempts: 3, StartedAt: now, LastActivityAt: now, ExpiresAt: now.Add(time.Hour),
+	}
+	require.NoError(t, m.CreateTestSession(ctx, session))
+	require.NoError(t, m.SavePracticeList(ctx, &models.PracticeList{ChildID: f.child.ID, FamilyID: f.family.ID, WordSetID: other.ID, CreatedBy: f.parent.ID}))
+	require.NoError(t, m.SaveChallengeLock(ctx, &models.ChallengeLock{WordSetID: f.wordSet.ID, ChildID: f.child.ID, LockedBy: f.parent.ID, Seed: 1}))
+
+	writes := []func(tx Repository) error{
+		func(tx Repository) error { return tx.LinkUserToAuthID(ctx, f.parent.ID, "auth-relinked") },
+		func(tx Repository) error { return tx.UpdateUserDisplayName(ctx, f.parent.ID, "Renamed") },
+		func(tx Repository) error {
+			u := *f.parent
+			u.DisplayName = "Updated"
+			return tx.UpdateUser(ctx, &u)
+		},
+		func(tx Repository) error { return tx.UpdateUserXP(ctx, f.child.ID, 10, 10, 2) },
+		func(tx Repository) error {
+			return tx.UpdateFamily(ctx, &models.Family{ID: f.family.ID, Name: "Renamed", UpdatedAt: now})
+		},
+		func(tx Repository) error { return tx.AddFamilyMember(ctx, f.family.ID, f.child.ID, "parent") },
+		func(tx Repository) error { return tx.UpdateChildDisplayName(ctx, f.child.ID, "Renamed") },
+		func(tx Repository) error { return tx.UpdateChildBirthYear(ctx, f.child.ID, new(2015)) },
+		func(tx Repository) error {
+			c := *f.child
+			c.IsActive = false
+			return tx.UpdateChild(ctx, &c)
+		},
+		func(tx Repository) error {
+			ws := *f.wordSet
+			ws.Name = "Renamed"
+			return tx.UpdateWordSet(ctx, &ws)
+		},
+		func(tx Repository) error {
+			return tx.SaveTestResult(ctx, &models.TestResult{WordSetID: f.wordSet.ID, UserID: f.child.ID, Mode: "keyboard", CompletedAt: now})
+		},
+		func(tx Repository) error {
+			return tx.SaveAudioFile(ctx, &models.AudioFile{ID: "audio-2", Word: "sol", Language: "no", VoiceID: "v", URL: "u", CreatedAt: now})
+		},
+		func(tx Repository) error {
+			schedule := &models.AssignmentSchedule{ReminderFrequency: models.ReminderDaily, ReminderHour: 18}
+			return tx.SetWordSetAssignmentSchedule(ctx, f.wordSet.ID, f.child.ID, schedule)
+		},
+		func(tx Repository) error {
+			_, err := tx.ClaimAssignmentReminder(ctx, f.wordSet.ID, f.child.ID, now, now)
+			return err
+		},
+		func(tx Repository) error {
+			_, err := tx.IncrementMastery(ctx, f.child.ID, f.wordSet.ID, "hus", models.TestModeKeyboard)
+			return err
+		},
+		func(tx Repository) error { return tx.TouchFamilyAPIKey(ctx, key.ID) },
+		func(tx Repository) error {
+			deliveries, err := tx.ClaimWebhookDeliveries(ctx, 10, time.Minute)
+			if err != nil || len(deliveries) == 0 {
+				return cmp.Or(err, errors.New("no delivery claimed"))
+			}
+			d := deliveries[0]
+			d.Status, d.Attempts = "delivered", 1
+			return tx.UpdateWebhookDelivery(ctx, &d)
+		},
+		func(tx Repository) error {
+			_, err := tx.ClaimDueDigestRecipients(ctx, now.Add(time.Hour), now)
+			return err
+		},
+		func(tx Repository) error {
+			_, err := tx.MarkNotificationsRead(ctx, f.parent.ID, []string{notifications[0].ID}, now)
+			return err
+		},
+		func(tx Repository) error {
+			s := *session
+			s.CurrentIndex = 1
+			return tx.UpdateTestSession(ctx, &s)
+		},
+		func(tx Repository) error {
+			return tx.SavePracticeList(ctx, &models.PracticeList{ChildID: f.child.ID, FamilyID: f.family.ID, WordSetID: other.ID, CreatedBy: f.parent.ID, MaxWords: 5})
+		},
+		func(tx Repository) error {
+			return tx.SaveChallengeLock(ctx, &models.ChallengeLock{WordSetID: f.wordSet.ID, ChildID: f.child.ID, LockedBy: f.parent.ID, Seed: 2})
+		},
+		func(tx Repository) error { return tx.AcceptInvitation(ctx, invitation.ID, f.child.ID) },
+		func(tx Repository) error {
+			_, err := tx.DismissNotifications(ctx, f.parent.ID, []string{notifications[0].ID})
+			return err
+		},
+		func(tx Repository) error {
+			_, err := tx.DeleteExpiredTestSessions(ctx, now.Add(2*time.Hour))
+			return err
+		},
+		func(tx Repository) error { return tx.DeleteChallengeLock(ctx, f.wordSet.ID, f.child.ID) },
+		func(tx Repository) error { return tx.UnassignWordSetFromUser(ctx, f.wordSet.ID, f.child.ID) },
+		func(tx Repository) error { return tx.DeleteFamilyAPIKey(ctx, f.family.ID, key.ID) },
+		func(tx Repository) error { return tx.DeleteWebhook(ctx, f.family.ID, webhook.ID) },
+		func(tx Repository) error { return tx.DeleteWordSet(ctx, other.ID) },
+		func(tx Repository) error { return tx.DeleteChild(ctx, f.child.ID) },
+		func(tx Repository) error { return tx.DeleteFamily(ctx, f.family.ID) },
+		func(tx Repository) error { return tx.DeleteUser(ctx, f.parent.ID) },
+	}
+
+	errInjected := errors.New("injected failure")
+	for i, write := range writes {
+		before := m.deepCopy()
+		err := m.WithTx(ctx, func(tx Repository) error {
+			require.NoError(t, write(tx), "write %d", i)
+			return errInjected
+		})
+		require.ErrorIs(t, err, errInjected)
+		require.Equal(t, before, m.deepCopy(), "write %d changed a table shared with the committed state", i)
+	}
+}
+
+func TestMemory_WithTx_CopiesOnlyWrittenTables(t *testing.T) {
+	m := NewMemory()
+	f := newFixture(t, m)
+	users, wordSets := fmt.Sprintf("%p", m.users), fmt.Sprintf("%p", m.wordSets)
+
+	require.NoError(t, m.WithTx(t.Context(), func(tx Repository) error {
+		return tx.UpdateUserDisplayName(t.Context(), f.parent.ID, "Renamed")
+	}))
+
+	assert.NotEqual(t, users, fmt.Sprintf("%p", m.users), "the written table is a committed copy")
+	assert.Equal(t, wordSets, fmt.Sprintf("%p", m.wordSets), "tables the transaction only read are not copied")
+	got, err := m.GetUser(t.Context(), f.parent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", got.DisplayName)
+}
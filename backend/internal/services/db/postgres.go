@@ -4,17 +4,31 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/starefossen/diktator/backend/internal/models"
 )
 
 // Postgres implements the Repository interface for PostgreSQL
 type Postgres struct {
-	pool *pgxpool.Pool
+	pool         *pgxpool.Pool
+	conn         querier // The pool, or the transaction inside WithTx
+	queryTimeout time.Duration
+}
+
+// querier is the part of pgx shared by the pool and a transaction. Begin on a
+// transaction creates a savepoint, so operations that use their own transaction
+// nest inside WithTx.
+type querier interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
 }
 
 // NewPostgres creates a new PostgreSQL repository
@@ -28,6 +42,7 @@ func NewPostgres(ctx context.Context, cfg *Config) (*Postgres, error) {
 	poolConfig.MinConns = int32(cfg.MaxIdleConns)
 	poolConfig.MaxConnLifetime = cfg.ConnMaxLifetime
 	poolConfig.MaxConnIdleTime = cfg.ConnMaxIdleTime
+	poolConfig.ConnConfig.Tracer = queryTracer{}
 
 	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
@@ -40,21 +55,83 @@ func NewPostgres(ctx context.Context, cfg *Config) (*Postgres, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	return &Postgres{pool: pool}, nil
+	return &Postgres{pool: pool, conn: pool, queryTimeout: cfg.QueryTimeout}, nil
+}
+
+// withTimeout bounds a single operation by the configured query timeout. The
+// caller's deadline and cancellation still apply when they come first.
+func (db *Postgres) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if db.queryTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, db.queryTimeout)
 }
 
-// Close closes the database connection pool
+// Close closes the database connection pool. It is a no-op on the repository
+// passed to a WithTx callback.
 func (db *Postgres) Close() error {
-	db.pool.Close()
+	if db.pool != nil {
+		db.pool.Close()
+	}
 	return nil
 }
 
+// CheckHealth pings the database. It is a no-op on the repository passed to a
+// WithTx callback.
+func (db *Postgres) CheckHealth(ctx context.Context) error {
+	if db.pool == nil {
+		return nil
+	}
+	return db.pool.Ping(ctx)
+}
+
+// MigrationVersion returns the schema version recorded by golang-migrate and
+// whether a migration failed partway through
+func (db *Postgres) MigrationVersion(ctx context.Context) (version uint, dirty bool, err error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
+	err = db.conn.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if err == pgx.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read migration version: %w", err)
+	}
+	return version, dirty, nil
+}
+
+// PoolStat returns the connection pool statistics, or nil on the repository
+// passed to a WithTx callback
+func (db *Postgres) PoolStat() *pgxpool.Stat {
+	if db.pool == nil {
+		return nil
+	}
+	return db.pool.Stat()
+}
+
+// WithTx runs fn in a transaction. Each operation inside is still bounded by the
+// query timeout, but the transaction as a whole only by ctx.
+func (db *Postgres) WithTx(ctx context.Context, fn func(tx Repository) error) error {
+	tx, err := db.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(&Postgres{conn: tx, queryTimeout: db.queryTimeout}); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
 // ============================================================================
 // User Operations
 // ============================================================================
 
-func (db *Postgres) GetUser(userID string) (*models.User, error) {
-	ctx := context.Background()
+func (db *Postgres) GetUser(ctx context.Context, userID string) (*models.User, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 	query := `
 		SELECT id, auth_id, email, display_name, family_id, role,
 		       parent_id, is_active, created_at, last_active_at
@@ -62,7 +139,7 @@ func (db *Postgres) GetUser(userID string) (*models.User, error) {
 
 	var user models.User
 
-	err := db.pool.QueryRow(ctx, query, userID).Scan(
+	err := db.conn.QueryRow(ctx, query, userID).Scan(
 		&user.ID, &user.AuthID, &user.Email, &user.DisplayName, &user.FamilyID,
 		&user.Role, &user.ParentID, &user.IsActive,
 		&user.CreatedAt, &user.LastActiveAt,
@@ -77,7 +154,7 @@ func (db *Postgres) GetUser(userID string) (*models.User, error) {
 	// Get children IDs if this is a parent
 	if user.Role == "parent" {
 		childrenQuery := `SELECT id FROM users WHERE parent_id = $1 AND role = 'child'`
-		rows, err := db.pool.Query(ctx, childrenQuery, user.ID)
+		rows, err := db.conn.Query(ctx, childrenQuery, user.ID)
 		if err == nil {
 			defer rows.Close()
 			for rows.Next() {
@@ -92,8 +169,9 @@ func (db *Postgres) GetUser(userID string) (*models.User, error) {
 	return &user, nil
 }
 
-func (db *Postgres) GetUserByAuthID(authID string) (*models.User, error) {
-	ctx := context.Background()
+func (db *Postgres) GetUserByAuthID(ctx context.Context, authID string) (*models.User, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 	query := `
 		SELECT id, auth_id, email, display_name, family_id, role,
 		       parent_id, is_active, birth_year, total_xp, level, created_at, last_active_at
@@ -101,7 +179,7 @@ func (db *Postgres) GetUserByAuthID(authID string) (*models.User, error) {
 
 	var user models.User
 
-	err := db.pool.QueryRow(ctx, query, authID).Scan(
+	err := db.conn.QueryRow(ctx, query, authID).Scan(
 		&user.ID, &user.AuthID, &user.Email, &user.DisplayName, &user.FamilyID,
 		&user.Role, &user.ParentID, &user.IsActive, &user.BirthYear, &user.TotalXP, &user.Level,
 		&user.CreatedAt, &user.LastActiveAt,
@@ -116,7 +194,7 @@ func (db *Postgres) GetUserByAuthID(authID string) (*models.User, error) {
 	// Get children IDs if this is a parent
 	if user.Role == "parent" {
 		childrenQuery := `SELECT id FROM users WHERE parent_id = $1 AND role = 'child'`
-		rows, err := db.pool.Query(ctx, childrenQuery, user.ID)
+		rows, err := db.conn.Query(ctx, childrenQuery, user.ID)
 		if err == nil {
 			defer rows.Close()
 			for rows.Next() {
@@ -131,8 +209,9 @@ func (db *Postgres) GetUserByAuthID(authID string) (*models.User, error) {
 	return &user, nil
 }
 
-func (db *Postgres) GetUserByEmail(email string) (*models.User, error) {
-	ctx := context.Background()
+func (db *Postgres) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 	var user models.User
 
 	query := `
@@ -141,7 +220,7 @@ func (db *Postgres) GetUserByEmail(email string) (*models.User, error) {
 		FROM users
 		WHERE LOWER(email) = LOWER($1)`
 
-	err := db.pool.QueryRow(ctx, query, email).Scan(
+	err := db.conn.QueryRow(ctx, query, email).Scan(
 		&user.ID, &user.AuthID, &user.Email, &user.DisplayName, &user.FamilyID,
 		&user.Role, &user.ParentID, &user.IsActive,
 		&user.CreatedAt, &user.LastActiveAt,
@@ -157,15 +236,16 @@ func (db *Postgres) GetUserByEmail(email string) (*models.User, error) {
 	return &user, nil
 }
 
-func (db *Postgres) LinkUserToAuthID(userID, authID string) error {
-	ctx := context.Background()
+func (db *Postgres) LinkUserToAuthID(ctx context.Context, userID, authID string) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 
 	query := `
 		UPDATE users
 		SET auth_id = $2, is_active = true, last_active_at = NOW()
 		WHERE id = $1`
 
-	result, err := db.pool.Exec(ctx, query, userID, authID)
+	result, err := db.conn.Exec(ctx, query, userID, authID)
 	if err != nil {
 		return fmt.Errorf("failed to link user to auth ID: %w", err)
 	}
@@ -177,8 +257,9 @@ func (db *Postgres) LinkUserToAuthID(userID, authID string) error {
 	return nil
 }
 
-func (db *Postgres) CreateUser(user *models.User) error {
-	ctx := context.Background()
+func (db *Postgres) CreateUser(ctx context.Context, user *models.User) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 
 	if user.ID == "" {
 		user.ID = uuid.New().String()
@@ -195,7 +276,7 @@ func (db *Postgres) CreateUser(user *models.User) error {
 		                   parent_id, birth_year, is_active, created_at, last_active_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
 
-	_, err := db.pool.Exec(ctx, query,
+	_, err := db.conn.Exec(ctx, query,
 		user.ID, user.AuthID, user.Email, user.DisplayName, familyID,
 		user.Role, user.ParentID, user.BirthYear, user.IsActive,
 		user.CreatedAt, user.LastActiveAt,
@@ -207,15 +288,16 @@ func (db *Postgres) CreateUser(user *models.User) error {
 	return nil
 }
 
-func (db *Postgres) UpdateUserDisplayName(userID, displayName string) error {
-	ctx := context.Background()
+func (db *Postgres) UpdateUserDisplayName(ctx context.Context, userID, displayName string) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 
 	query := `
 		UPDATE users SET
 			display_name = $2
 		WHERE id = $1`
 
-	result, err := db.pool.Exec(ctx, query, userID, displayName)
+	result, err := db.conn.Exec(ctx, query, userID, displayName)
 	if err != nil {
 		return fmt.Errorf("failed to update user display name: %w", err)
 	}
@@ -227,8 +309,9 @@ func (db *Postgres) UpdateUserDisplayName(userID, displayName string) error {
 	return nil
 }
 
-func (db *Postgres) UpdateUser(user *models.User) error {
-	ctx := context.Background()
+func (db *Postgres) UpdateUser(ctx context.Context, user *models.User) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 
 	// Convert empty strings to nil for nullable FK columns
 	var familyID *string
@@ -242,7 +325,7 @@ func (db *Postgres) UpdateUser(user *models.User) error {
 			parent_id = $6, is_active = $7, last_active_at = $8
 		WHERE id = $1`
 
-	result, err := db.pool.Exec(ctx, query,
+	result, err := db.conn.Exec(ctx, query,
 		user.ID, user.Email, user.DisplayName, familyID, user.Role,
 		user.ParentID, user.IsActive, user.LastActiveAt,
 	)
@@ -257,11 +340,12 @@ func (db *Postgres) UpdateUser(user *models.User) error {
 	return nil
 }
 
-func (db *Postgres) DeleteUser(userID string) error {
-	ctx := context.Background()
+func (db *Postgres) DeleteUser(ctx context.Context, userID string) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 	query := `DELETE FROM users WHERE id = $1`
 
-	result, err := db.pool.Exec(ctx, query, userID)
+	result, err := db.conn.Exec(ctx, query, userID)
 	if err != nil {
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
@@ -277,14 +361,15 @@ func (db *Postgres) DeleteUser(userID string) error {
 // Family Operations
 // ============================================================================
 
-func (db *Postgres) GetFamily(familyID string) (*models.Family, error) {
-	ctx := context.Background()
+func (db *Postgres) GetFamily(ctx context.Context, familyID string) (*models.Family, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 
 	// Get family basic info
 	query := `SELECT id, name, created_by, created_at, updated_at FROM families WHERE id = $1`
 
 	var family models.Family
-	err := db.pool.QueryRow(ctx, query, familyID).Scan(
+	err := db.conn.QueryRow(ctx, query, familyID).Scan(
 		&family.ID, &family.Name, &family.CreatedBy, &family.CreatedAt, &family.UpdatedAt,
 	)
 	if err == pgx.ErrNoRows {
@@ -296,7 +381,7 @@ func (db *Postgres) GetFamily(familyID string) (*models.Family, error) {
 
 	// Get members
 	membersQuery := `SELECT user_id FROM family_members WHERE family_id = $1`
-	rows, err := db.pool.Query(ctx, membersQuery, familyID)
+	rows, err := db.conn.Query(ctx, membersQuery, familyID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get family members: %w", err)
 	}
@@ -313,14 +398,15 @@ func (db *Postgres) GetFamily(familyID string) (*models.Family, error) {
 	return &family, nil
 }
 
-func (db *Postgres) CreateFamily(family *models.Family) error {
-	ctx := context.Background()
+func (db *Postgres) CreateFamily(ctx context.Context, family *models.Family) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 
 	if family.ID == "" {
 		family.ID = uuid.New().String()
 	}
 
-	tx, err := db.pool.Begin(ctx)
+	tx, err := db.conn.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -332,6 +418,9 @@ func (db *Postgres) CreateFamily(family *models.Family) error {
 	_, err = tx.Exec(ctx, query, family.ID, family.Name, family.CreatedBy,
 		family.CreatedAt, family.UpdatedAt)
 	if err != nil {
+		if isForeignKeyViolation(err) {
+			return fmt.Errorf("failed to create family: %w", ErrMissingReference)
+		}
 		return fmt.Errorf("failed to create family: %w", err)
 	}
 
@@ -341,6 +430,9 @@ func (db *Postgres) CreateFamily(family *models.Family) error {
 		                VALUES ($1, $2, 'parent', $3)`
 		_, err = tx.Exec(ctx, memberQuery, family.ID, memberID, time.Now())
 		if err != nil {
+			if isForeignKeyViolation(err) {
+				return fmt.Errorf("failed to add family member: %w", ErrMissingReference)
+			}
 			return fmt.Errorf("failed to add family member: %w", err)
 		}
 	}
@@ -348,26 +440,31 @@ func (db *Postgres) CreateFamily(family *models.Family) error {
 	return tx.Commit(ctx)
 }
 
-func (db *Postgres) AddFamilyMember(familyID, userID, role string) error {
-	ctx := context.Background()
+func (db *Postgres) AddFamilyMember(ctx context.Context, familyID, userID, role string) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 
 	query := `INSERT INTO family_members (family_id, user_id, role, joined_at)
 	          VALUES ($1, $2, $3, $4)
 	          ON CONFLICT (family_id, user_id) DO NOTHING`
 
-	_, err := db.pool.Exec(ctx, query, familyID, userID, role, time.Now())
+	_, err := db.conn.Exec(ctx, query, familyID, userID, role, time.Now())
 	if err != nil {
+		if isForeignKeyViolation(err) {
+			return fmt.Errorf("failed to add family member: %w", ErrMissingReference)
+		}
 		return fmt.Errorf("failed to add family member: %w", err)
 	}
 
 	return nil
 }
 
-func (db *Postgres) UpdateFamily(family *models.Family) error {
-	ctx := context.Background()
+func (db *Postgres) UpdateFamily(ctx context.Context, family *models.Family) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 
 	query := `UPDATE families SET name = $2, updated_at = $3 WHERE id = $1`
-	result, err := db.pool.Exec(ctx, query, family.ID, family.Name, family.UpdatedAt)
+	result, err := db.conn.Exec(ctx, query, family.ID, family.Name, family.UpdatedAt)
 	if err != nil {
 		return fmt.Errorf("failed to update family: %w", err)
 	}
@@ -379,10 +476,11 @@ func (db *Postgres) UpdateFamily(family *models.Family) error {
 	return nil
 }
 
-func (db *Postgres) DeleteFamily(familyID string) error {
-	ctx := context.Background()
+func (db *Postgres) DeleteFamily(ctx context.Context, familyID string) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 
-	tx, err := db.pool.Begin(ctx)
+	tx, err := db.conn.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -411,15 +509,16 @@ func (db *Postgres) DeleteFamily(familyID string) error {
 // Child Operations
 // ============================================================================
 
-func (db *Postgres) GetChild(childID string) (*models.ChildAccount, error) {
-	ctx := context.Background()
+func (db *Postgres) GetChild(ctx context.Context, childID string) (*models.ChildAccount, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 	query := `
 		SELECT id, email, display_name, family_id, parent_id, role,
 		       is_active, birth_year, created_at, last_active_at
 		FROM users WHERE id = $1 AND role = 'child'`
 
 	var child models.ChildAccount
-	err := db.pool.QueryRow(ctx, query, childID).Scan(
+	err := db.conn.QueryRow(ctx, query, childID).Scan(
 		&child.ID, &child.Email, &child.DisplayName, &child.FamilyID,
 		&child.ParentID, &child.Role, &child.IsActive, &child.BirthYear,
 		&child.CreatedAt, &child.LastActiveAt,
@@ -434,8 +533,9 @@ func (db *Postgres) GetChild(childID string) (*models.ChildAccount, error) {
 	return &child, nil
 }
 
-func (db *Postgres) GetFamilyChildren(familyID string) ([]models.ChildAccount, error) {
-	ctx := context.Background()
+func (db *Postgres) GetFamilyChildren(ctx context.Context, familyID string) ([]models.ChildAccount, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 	query := `
 		SELECT id, email, display_name, family_id, parent_id, role,
 		       is_active, birth_year, total_xp, level, created_at, last_active_at
@@ -443,7 +543,7 @@ func (db *Postgres) GetFamilyChildren(familyID string) ([]models.ChildAccount, e
 		WHERE family_id = $1 AND role = 'child'
 		ORDER BY created_at ASC`
 
-	rows, err := db.pool.Query(ctx, query, familyID)
+	rows, err := db.conn.Query(ctx, query, familyID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get family children: %w", err)
 	}
@@ -467,8 +567,9 @@ func (db *Postgres) GetFamilyChildren(familyID string) ([]models.ChildAccount, e
 	return children, nil
 }
 
-func (db *Postgres) CreateChild(child *models.ChildAccount) error {
-	ctx := context.Background()
+func (db *Postgres) CreateChild(ctx context.Context, child *models.ChildAccount) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 
 	if child.ID == "" {
 		child.ID = uuid.New().String()
@@ -481,27 +582,31 @@ func (db *Postgres) CreateChild(child *models.ChildAccount) error {
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
 
 	// Use the child ID as auth_id for now (can be updated when child logs in)
-	_, err := db.pool.Exec(ctx, query,
+	_, err := db.conn.Exec(ctx, query,
 		child.ID, child.ID, child.Email, child.DisplayName, child.FamilyID,
 		child.ParentID, "child", child.IsActive,
 		child.CreatedAt, child.LastActiveAt,
 	)
 	if err != nil {
+		if isForeignKeyViolation(err) {
+			return fmt.Errorf("failed to create child: %w", ErrMissingReference)
+		}
 		return fmt.Errorf("failed to create child: %w", err)
 	}
 
 	return nil
 }
 
-func (db *Postgres) UpdateChildDisplayName(childID, displayName string) error {
-	ctx := context.Background()
+func (db *Postgres) UpdateChildDisplayName(ctx context.Context, childID, displayName string) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 
 	query := `
 		UPDATE users SET
 			display_name = $2
 		WHERE id = $1 AND role = 'child'`
 
-	result, err := db.pool.Exec(ctx, query, childID, displayName)
+	result, err := db.conn.Exec(ctx, query, childID, displayName)
 	if err != nil {
 		return fmt.Errorf("failed to update child display name: %w", err)
 	}
@@ -513,15 +618,16 @@ func (db *Postgres) UpdateChildDisplayName(childID, displayName string) error {
 	return nil
 }
 
-func (db *Postgres) UpdateChildBirthYear(childID string, birthYear *int) error {
-	ctx := context.Background()
+func (db *Postgres) UpdateChildBirthYear(ctx context.Context, childID string, birthYear *int) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 
 	query := `
 		UPDATE users SET
 			birth_year = $2
 		WHERE id = $1 AND role = 'child'`
 
-	result, err := db.pool.Exec(ctx, query, childID, birthYear)
+	result, err := db.conn.Exec(ctx, query, childID, birthYear)
 	if err != nil {
 		return fmt.Errorf("failed to update child birth year: %w", err)
 	}
@@ -533,15 +639,16 @@ func (db *Postgres) UpdateChildBirthYear(childID string, birthYear *int) error {
 	return nil
 }
 
-func (db *Postgres) UpdateChild(child *models.ChildAccount) error {
-	ctx := context.Background()
+func (db *Postgres) UpdateChild(ctx context.Context, child *models.ChildAccount) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 
 	query := `
 		UPDATE users SET
 			email = $2, display_name = $3, is_active = $4, last_active_at = $5
 		WHERE id = $1 AND role = 'child'`
 
-	result, err := db.pool.Exec(ctx, query,
+	result, err := db.conn.Exec(ctx, query,
 		child.ID, child.Email, child.DisplayName, child.IsActive, child.LastActiveAt,
 	)
 	if err != nil {
@@ -555,11 +662,12 @@ func (db *Postgres) UpdateChild(child *models.ChildAccount) error {
 	return nil
 }
 
-func (db *Postgres) DeleteChild(childID string) error {
-	ctx := context.Background()
+func (db *Postgres) DeleteChild(ctx context.Context, childID string) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 	query := `DELETE FROM users WHERE id = $1 AND role = 'child'`
 
-	result, err := db.pool.Exec(ctx, query, childID)
+	result, err := db.conn.Exec(ctx, query, childID)
 	if err != nil {
 		return fmt.Errorf("failed to delete child: %w", err)
 	}
@@ -582,34 +690,29 @@ type WordForDB struct {
 	Definition string           `json:"definition,omitempty"`
 }
 
-func (db *Postgres) GetWordSet(id string) (*models.WordSet, error) {
-	ctx := context.Background()
-
-	// Get word set basic info
-	query := `
-		SELECT id, name, family_id, is_global, created_by, language, test_configuration,
-		       target_grade, spelling_focus, difficulty, sentences, description, created_at, updated_at
-		FROM word_sets WHERE id = $1`
+// wordSetColumns are the word_sets columns read by scanWordSet
+const wordSetColumns = `id, name, family_id, is_global, created_by, language, test_configuration,
+		       target_grade, spelling_focus, difficulty, sentences, description, created_at, updated_at`
 
+// scanWordSet scans a row selected with wordSetColumns. Scan errors are returned
+// unwrapped so callers can match pgx.ErrNoRows.
+func scanWordSet(row pgx.Row) (models.WordSet, error) {
 	var ws models.WordSet
 	var testConfigJSON, spellingFocusJSON, sentencesJSON []byte
 	var targetGrade, difficulty *string
-	err := db.pool.QueryRow(ctx, query, id).Scan(
+	err := row.Scan(
 		&ws.ID, &ws.Name, &ws.FamilyID, &ws.IsGlobal, &ws.CreatedBy, &ws.Language,
 		&testConfigJSON, &targetGrade, &spellingFocusJSON, &difficulty, &sentencesJSON,
 		&ws.Description, &ws.CreatedAt, &ws.UpdatedAt,
 	)
-	if err == pgx.ErrNoRows {
-		return nil, ErrWordSetNotFound
-	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get word set: %w", err)
+		return ws, err
 	}
 
 	if testConfigJSON != nil {
 		var testConfig map[string]interface{}
 		if err := json.Unmarshal(testConfigJSON, &testConfig); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal test config: %w", err)
+			return ws, fmt.Errorf("failed to unmarshal test config: %w", err)
 		}
 		ws.TestConfiguration = &testConfig
 	}
@@ -626,36 +729,165 @@ func (db *Postgres) GetWordSet(id string) (*models.WordSet, error) {
 	if len(spellingFocusJSON) > 0 {
 		var focus []models.SpellingFocusCategory
 		if err := json.Unmarshal(spellingFocusJSON, &focus); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal spelling focus: %w", err)
+			return ws, fmt.Errorf("failed to unmarshal spelling focus: %w", err)
 		}
 		ws.SpellingFocus = focus
 	}
 	if len(sentencesJSON) > 0 {
 		var sentences []models.SentenceItem
 		if err := json.Unmarshal(sentencesJSON, &sentences); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal sentences: %w", err)
+			return ws, fmt.Errorf("failed to unmarshal sentences: %w", err)
 		}
 		ws.Sentences = sentences
 	}
 
-	// Get words
-	wordsQuery := `
-		SELECT word, audio_url, audio_id, voice_id, audio_created_at, definition, translations
-		FROM words WHERE word_set_id = $1 ORDER BY position`
-	rows, err := db.pool.Query(ctx, wordsQuery, id)
+	return ws, nil
+}
+
+func (db *Postgres) GetWordSet(ctx context.Context, id string) (*models.WordSet, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT ` + wordSetColumns + ` FROM word_sets WHERE id = $1`
+	ws, err := scanWordSet(db.conn.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, ErrWordSetNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get word set: %w", err)
+	}
+
+	wordSets := []models.WordSet{ws}
+	if err := db.loadWords(ctx, wordSets); err != nil {
+		return nil, err
+	}
+	return &wordSets[0], nil
+}
+
+func (db *Postgres) GetWordSets(ctx context.Context, familyID string) ([]models.WordSet, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+	return db.queryWordSets(ctx, `family_id = $1 ORDER BY created_at DESC`, familyID)
+}
+
+func (db *Postgres) ListWordSets(ctx context.Context, familyID string, filter models.WordSetFilter, page models.PageRequest) ([]models.WordSet, string, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
+	after, err := decodeCursor(page.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// The family and cursor conditions are served by idx_word_sets_family_created_id;
+	// the other filters only narrow the rows of one family
+	var q listQuery
+	q.where("family_id = %s", familyID)
+	if filter.Search != "" {
+		q.where(`name ILIKE %s`, "%"+escapeLike(filter.Search)+"%")
+	}
+	if filter.Language != "" {
+		q.where("language = %s", filter.Language)
+	}
+	if filter.Grade != "" {
+		q.where("target_grade = %s", string(filter.Grade))
+	}
+	if filter.Difficulty != "" {
+		q.where("difficulty = %s", string(filter.Difficulty))
+	}
+	if after != nil {
+		q.where("(created_at, id) < (%s, %s)", after.At, after.ID)
+	}
+
+	wordSets, err := db.queryWordSets(ctx, q.conditions()+` ORDER BY created_at DESC, id DESC`+q.limit(page.Limit), q.args...)
+	if err != nil {
+		return nil, "", err
+	}
+	wordSets, next := trimPage(wordSets, page.Limit, func(ws models.WordSet) (time.Time, string) { return ws.CreatedAt, ws.ID })
+	return wordSets, next, nil
+}
+
+// queryWordSets loads the word sets matching a WHERE clause, with their words and assignees
+func (db *Postgres) queryWordSets(ctx context.Context, where string, args ...any) ([]models.WordSet, error) {
+	wordSets, err := db.collectWordSets(ctx, where, args...)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.loadAssignedUserIDs(ctx, wordSets); err != nil {
+		return nil, err
+	}
+	if err := db.loadWords(ctx, wordSets); err != nil {
+		return nil, err
+	}
+	return wordSets, nil
+}
+
+// collectWordSets reads the word sets matching a WHERE clause. The rows are
+// collected before returning, so the caller can run further queries on a
+// transaction's single connection.
+func (db *Postgres) collectWordSets(ctx context.Context, where string, args ...any) ([]models.WordSet, error) {
+	rows, err := db.conn.Query(ctx, `SELECT `+wordSetColumns+` FROM word_sets WHERE `+where, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get word sets: %w", err)
+	}
+	wordSets, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.WordSet, error) {
+		return scanWordSet(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan word set: %w", err)
+	}
+	return wordSets, nil
+}
+
+// loadAssignedUserIDs fills in the assignees of the word sets with one query
+func (db *Postgres) loadAssignedUserIDs(ctx context.Context, wordSets []models.WordSet) error {
+	if len(wordSets) == 0 {
+		return nil
+	}
+	ids, index := wordSetIndex(wordSets)
+
+	query := `SELECT wordset_id, user_id FROM wordset_assignments WHERE wordset_id = ANY($1) ORDER BY assigned_at`
+	rows, err := db.conn.Query(ctx, query, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get wordset assignments: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var wordSetID, userID string
+		if err := rows.Scan(&wordSetID, &userID); err != nil {
+			return fmt.Errorf("failed to scan assignment: %w", err)
+		}
+		ws := &wordSets[index[wordSetID]]
+		ws.AssignedUserIDs = append(ws.AssignedUserIDs, userID)
+	}
+	return rows.Err()
+}
+
+// loadWords fills in the words of the word sets with one query
+func (db *Postgres) loadWords(ctx context.Context, wordSets []models.WordSet) error {
+	if len(wordSets) == 0 {
+		return nil
+	}
+	ids, index := wordSetIndex(wordSets)
+
+	query := `
+		SELECT word_set_id, word, audio_url, audio_id, voice_id, audio_created_at, definition, translations
+		FROM words WHERE word_set_id = ANY($1) ORDER BY word_set_id, position`
+	rows, err := db.conn.Query(ctx, query, ids)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get words: %w", err)
+		return fmt.Errorf("failed to get words: %w", err)
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var wordStr, definition string
+		var wordSetID, wordStr, definition string
 		var audioURL, audioID, voiceID *string
 		var audioCreatedAt *time.Time
 		var translationsJSON []byte
-		err := rows.Scan(&wordStr, &audioURL, &audioID, &voiceID, &audioCreatedAt, &definition, &translationsJSON)
+		err := rows.Scan(&wordSetID, &wordStr, &audioURL, &audioID, &voiceID, &audioCreatedAt, &definition, &translationsJSON)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan word: %w", err)
+			return fmt.Errorf("failed to scan word: %w", err)
 		}
 
 		wordEntry := struct {
@@ -672,7 +904,7 @@ func (db *Postgres) GetWordSet(id string) (*models.WordSet, error) {
 		if len(translationsJSON) > 0 {
 			var translations []models.Translation
 			if err := json.Unmarshal(translationsJSON, &translations); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal translations: %w", err)
+				return fmt.Errorf("failed to unmarshal translations: %w", err)
 			}
 			wordEntry.Translations = translations
 		}
@@ -692,286 +924,46 @@ func (db *Postgres) GetWordSet(id string) (*models.WordSet, error) {
 				wordEntry.Audio.CreatedAt = *audioCreatedAt
 			}
 		}
+		ws := &wordSets[index[wordSetID]]
 		ws.Words = append(ws.Words, wordEntry)
 	}
-
-	return &ws, nil
+	return rows.Err()
 }
 
-func (db *Postgres) GetWordSets(familyID string) ([]models.WordSet, error) {
-	ctx := context.Background()
-	query := `
-		SELECT id, name, family_id, is_global, created_by, language, test_configuration,
-		       target_grade, spelling_focus, difficulty, sentences, description, created_at, updated_at
-		FROM word_sets WHERE family_id = $1 ORDER BY created_at DESC`
-
-	rows, err := db.pool.Query(ctx, query, familyID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get word sets: %w", err)
-	}
-	defer rows.Close()
-
-	var wordSets []models.WordSet
-	for rows.Next() {
-		var ws models.WordSet
-		var testConfigJSON, spellingFocusJSON, sentencesJSON []byte
-		var targetGrade, difficulty *string
-		err := rows.Scan(
-			&ws.ID, &ws.Name, &ws.FamilyID, &ws.IsGlobal, &ws.CreatedBy, &ws.Language,
-			&testConfigJSON, &targetGrade, &spellingFocusJSON, &difficulty, &sentencesJSON,
-			&ws.Description, &ws.CreatedAt, &ws.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan word set: %w", err)
-		}
-
-		if testConfigJSON != nil {
-			var testConfig map[string]interface{}
-			if err := json.Unmarshal(testConfigJSON, &testConfig); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal test config: %w", err)
-			}
-			ws.TestConfiguration = &testConfig
-		}
-
-		// Handle curated content fields
-		if targetGrade != nil {
-			g := models.GradeLevel(*targetGrade)
-			ws.TargetGrade = &g
-		}
-		if difficulty != nil {
-			d := models.DifficultyLevel(*difficulty)
-			ws.Difficulty = &d
-		}
-		if len(spellingFocusJSON) > 0 {
-			var focus []models.SpellingFocusCategory
-			if err := json.Unmarshal(spellingFocusJSON, &focus); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal spelling focus: %w", err)
-			}
-			ws.SpellingFocus = focus
-		}
-		if len(sentencesJSON) > 0 {
-			var sentences []models.SentenceItem
-			if err := json.Unmarshal(sentencesJSON, &sentences); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal sentences: %w", err)
-			}
-			ws.Sentences = sentences
-		}
-
-		// Get assigned user IDs for this word set
-		assignmentsQuery := `SELECT user_id FROM wordset_assignments WHERE wordset_id = $1 ORDER BY assigned_at`
-		assignmentRows, err := db.pool.Query(ctx, assignmentsQuery, ws.ID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get wordset assignments: %w", err)
-		}
-
-		var assignedUserIDs []string
-		for assignmentRows.Next() {
-			var userID string
-			if err := assignmentRows.Scan(&userID); err != nil {
-				assignmentRows.Close()
-				return nil, fmt.Errorf("failed to scan assignment: %w", err)
-			}
-			assignedUserIDs = append(assignedUserIDs, userID)
-		}
-		assignmentRows.Close()
-		ws.AssignedUserIDs = assignedUserIDs
-
-		// Get words for this word set
-		wordsQuery := `
-			SELECT word, audio_url, audio_id, voice_id, audio_created_at, definition, translations
-			FROM words WHERE word_set_id = $1 ORDER BY position`
-		wordRows, err := db.pool.Query(ctx, wordsQuery, ws.ID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get words: %w", err)
-		}
-
-		for wordRows.Next() {
-			var wordStr, definition string
-			var audioURL, audioID, voiceID *string
-			var audioCreatedAt *time.Time
-			var translationsJSON []byte
-			err := wordRows.Scan(&wordStr, &audioURL, &audioID, &voiceID, &audioCreatedAt, &definition, &translationsJSON)
-			if err != nil {
-				wordRows.Close()
-				return nil, fmt.Errorf("failed to scan word: %w", err)
-			}
-
-			wordEntry := struct {
-				Word         string               `json:"word"`
-				Audio        models.WordAudio     `json:"audio,omitempty"`
-				Definition   string               `json:"definition,omitempty"`
-				Translations []models.Translation `json:"translations,omitempty"`
-			}{
-				Word:       wordStr,
-				Definition: definition,
-			}
-
-			// Unmarshal translations if present
-			if len(translationsJSON) > 0 {
-				var translations []models.Translation
-				if err := json.Unmarshal(translationsJSON, &translations); err != nil {
-					wordRows.Close()
-					return nil, fmt.Errorf("failed to unmarshal translations: %w", err)
-				}
-				wordEntry.Translations = translations
-			}
-
-			if audioURL != nil {
-				wordEntry.Audio = models.WordAudio{
-					Word:     wordStr,
-					AudioURL: *audioURL,
-				}
-				if audioID != nil {
-					wordEntry.Audio.AudioID = *audioID
-				}
-				if voiceID != nil {
-					wordEntry.Audio.VoiceID = *voiceID
-				}
-				if audioCreatedAt != nil {
-					wordEntry.Audio.CreatedAt = *audioCreatedAt
-				}
-			}
-			ws.Words = append(ws.Words, wordEntry)
-		}
-		wordRows.Close()
-
-		wordSets = append(wordSets, ws)
+// wordSetIndex returns the IDs of the word sets and each ID's position
+func wordSetIndex(wordSets []models.WordSet) ([]string, map[string]int) {
+	ids := make([]string, len(wordSets))
+	index := make(map[string]int, len(wordSets))
+	for i, ws := range wordSets {
+		ids[i] = ws.ID
+		index[ws.ID] = i
 	}
-
-	return wordSets, nil
+	return ids, index
 }
 
 // GetGlobalWordSets returns all global (curated) word sets available to all users
-func (db *Postgres) GetGlobalWordSets() ([]models.WordSet, error) {
-	ctx := context.Background()
-	query := `
-		SELECT id, name, family_id, is_global, created_by, language, test_configuration,
-		       target_grade, spelling_focus, difficulty, sentences, description, created_at, updated_at
-		FROM word_sets WHERE is_global = true ORDER BY name ASC`
+func (db *Postgres) GetGlobalWordSets(ctx context.Context) ([]models.WordSet, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 
-	rows, err := db.pool.Query(ctx, query)
+	wordSets, err := db.collectWordSets(ctx, `is_global = true ORDER BY name ASC`)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get global word sets: %w", err)
+		return nil, err
 	}
-	defer rows.Close()
-
-	var wordSets []models.WordSet
-	for rows.Next() {
-		var ws models.WordSet
-		var testConfigJSON, spellingFocusJSON, sentencesJSON []byte
-		var targetGrade, difficultyStr *string
-		err := rows.Scan(
-			&ws.ID, &ws.Name, &ws.FamilyID, &ws.IsGlobal, &ws.CreatedBy, &ws.Language,
-			&testConfigJSON, &targetGrade, &spellingFocusJSON, &difficultyStr, &sentencesJSON,
-			&ws.Description, &ws.CreatedAt, &ws.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan word set: %w", err)
-		}
-
-		if testConfigJSON != nil {
-			var testConfig map[string]interface{}
-			if err := json.Unmarshal(testConfigJSON, &testConfig); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal test config: %w", err)
-			}
-			ws.TestConfiguration = &testConfig
-		}
-
-		// Handle curated content fields
-		if targetGrade != nil {
-			g := models.GradeLevel(*targetGrade)
-			ws.TargetGrade = &g
-		}
-		if difficultyStr != nil {
-			d := models.DifficultyLevel(*difficultyStr)
-			ws.Difficulty = &d
-		}
-		if len(spellingFocusJSON) > 0 {
-			var focus []models.SpellingFocusCategory
-			if err := json.Unmarshal(spellingFocusJSON, &focus); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal spelling focus: %w", err)
-			}
-			ws.SpellingFocus = focus
-		}
-		if len(sentencesJSON) > 0 {
-			var sentences []models.SentenceItem
-			if err := json.Unmarshal(sentencesJSON, &sentences); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal sentences: %w", err)
-			}
-			ws.Sentences = sentences
-		}
-
-		// Get words for this word set
-		wordsQuery := `
-			SELECT word, audio_url, audio_id, voice_id, audio_created_at, definition, translations
-			FROM words WHERE word_set_id = $1 ORDER BY position`
-		wordRows, err := db.pool.Query(ctx, wordsQuery, ws.ID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get words: %w", err)
-		}
-
-		for wordRows.Next() {
-			var wordStr, definition string
-			var audioURL, audioID, voiceID *string
-			var audioCreatedAt *time.Time
-			var translationsJSON []byte
-			err := wordRows.Scan(&wordStr, &audioURL, &audioID, &voiceID, &audioCreatedAt, &definition, &translationsJSON)
-			if err != nil {
-				wordRows.Close()
-				return nil, fmt.Errorf("failed to scan word: %w", err)
-			}
-
-			wordEntry := struct {
-				Word         string               `json:"word"`
-				Audio        models.WordAudio     `json:"audio,omitempty"`
-				Definition   string               `json:"definition,omitempty"`
-				Translations []models.Translation `json:"translations,omitempty"`
-			}{
-				Word:       wordStr,
-				Definition: definition,
-			}
-
-			if len(translationsJSON) > 0 {
-				var translations []models.Translation
-				if err := json.Unmarshal(translationsJSON, &translations); err != nil {
-					wordRows.Close()
-					return nil, fmt.Errorf("failed to unmarshal translations: %w", err)
-				}
-				wordEntry.Translations = translations
-			}
-
-			if audioURL != nil {
-				wordEntry.Audio = models.WordAudio{
-					Word:     wordStr,
-					AudioURL: *audioURL,
-				}
-				if audioID != nil {
-					wordEntry.Audio.AudioID = *audioID
-				}
-				if voiceID != nil {
-					wordEntry.Audio.VoiceID = *voiceID
-				}
-				if audioCreatedAt != nil {
-					wordEntry.Audio.CreatedAt = *audioCreatedAt
-				}
-			}
-			ws.Words = append(ws.Words, wordEntry)
-		}
-		wordRows.Close()
-
-		wordSets = append(wordSets, ws)
+	if err := db.loadWords(ctx, wordSets); err != nil {
+		return nil, err
 	}
-
 	return wordSets, nil
 }
 
 // IsGlobalWordSet checks if a word set is global (curated)
-func (db *Postgres) IsGlobalWordSet(wordSetID string) (bool, error) {
-	ctx := context.Background()
+func (db *Postgres) IsGlobalWordSet(ctx context.Context, wordSetID string) (bool, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 	query := `SELECT is_global FROM word_sets WHERE id = $1`
 
 	var isGlobal bool
-	err := db.pool.QueryRow(ctx, query, wordSetID).Scan(&isGlobal)
+	err := db.conn.QueryRow(ctx, query, wordSetID).Scan(&isGlobal)
 	if err == pgx.ErrNoRows {
 		return false, ErrWordSetNotFound
 	}
@@ -982,14 +974,15 @@ func (db *Postgres) IsGlobalWordSet(wordSetID string) (bool, error) {
 	return isGlobal, nil
 }
 
-func (db *Postgres) CreateWordSet(ws *models.WordSet) error {
-	ctx := context.Background()
+func (db *Postgres) CreateWordSet(ctx context.Context, ws *models.WordSet) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 
 	if ws.ID == "" {
 		ws.ID = uuid.New().String()
 	}
 
-	tx, err := db.pool.Begin(ctx)
+	tx, err := db.conn.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -1005,14 +998,17 @@ func (db *Postgres) CreateWordSet(ws *models.WordSet) error {
 
 	query := `
 		INSERT INTO word_sets (id, name, family_id, is_global, created_by, language, test_configuration,
-		                       created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+		                       target_grade, difficulty, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
 
 	_, err = tx.Exec(ctx, query,
 		ws.ID, ws.Name, ws.FamilyID, ws.IsGlobal, ws.CreatedBy, ws.Language,
-		testConfigJSON, ws.CreatedAt, ws.UpdatedAt,
+		testConfigJSON, ws.TargetGrade, ws.Difficulty, ws.CreatedAt, ws.UpdatedAt,
 	)
 	if err != nil {
+		if isForeignKeyViolation(err) {
+			return fmt.Errorf("failed to create word set: %w", ErrMissingReference)
+		}
 		return fmt.Errorf("failed to create word set: %w", err)
 	}
 
@@ -1052,10 +1048,11 @@ func (db *Postgres) CreateWordSet(ws *models.WordSet) error {
 	return tx.Commit(ctx)
 }
 
-func (db *Postgres) UpdateWordSet(ws *models.WordSet) error {
-	ctx := context.Background()
+func (db *Postgres) UpdateWordSet(ctx context.Context, ws *models.WordSet) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 
-	tx, err := db.pool.Begin(ctx)
+	tx, err := db.conn.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -1071,11 +1068,12 @@ func (db *Postgres) UpdateWordSet(ws *models.WordSet) error {
 
 	query := `
 		UPDATE word_sets SET
-			name = $2, language = $3, test_configuration = $4, updated_at = $5
+			name = $2, language = $3, test_configuration = $4,
+			target_grade = $5, difficulty = $6, updated_at = $7
 		WHERE id = $1`
 
 	result, err := tx.Exec(ctx, query,
-		ws.ID, ws.Name, ws.Language, testConfigJSON, ws.UpdatedAt,
+		ws.ID, ws.Name, ws.Language, testConfigJSON, ws.TargetGrade, ws.Difficulty, ws.UpdatedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update word set: %w", err)
@@ -1126,10 +1124,11 @@ func (db *Postgres) UpdateWordSet(ws *models.WordSet) error {
 	return tx.Commit(ctx)
 }
 
-func (db *Postgres) DeleteWordSet(id string) error {
-	ctx := context.Background()
+func (db *Postgres) DeleteWordSet(ctx context.Context, id string) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 
-	tx, err := db.pool.Begin(ctx)
+	tx, err := db.conn.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -1158,46 +1157,24 @@ func (db *Postgres) DeleteWordSet(id string) error {
 // Test Result Operations
 // ============================================================================
 
-func (db *Postgres) GetTestResults(userID string) ([]models.TestResult, error) {
-	ctx := context.Background()
+func (db *Postgres) GetTestResults(ctx context.Context, userID string) ([]models.TestResult, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 	query := `
 		SELECT id, word_set_id, user_id, score, total_words, correct_words,
 		       time_spent, mode, completed_at, created_at
 		FROM test_results WHERE user_id = $1 ORDER BY completed_at DESC`
 
-	rows, err := db.pool.Query(ctx, query, userID)
+	rows, err := db.conn.Query(ctx, query, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get test results: %w", err)
 	}
-	defer rows.Close()
-
-	var results []models.TestResult
-	for rows.Next() {
-		var result models.TestResult
-		err := rows.Scan(
-			&result.ID, &result.WordSetID, &result.UserID, &result.Score,
-			&result.TotalWords, &result.CorrectWords, &result.TimeSpent, &result.Mode,
-			&result.CompletedAt, &result.CreatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan result: %w", err)
-		}
-
-		// Get word test results
-		wordResults, err := db.getWordTestResults(ctx, result.ID)
-		if err != nil {
-			return nil, err
-		}
-		result.Words = wordResults
-
-		results = append(results, result)
-	}
-
-	return results, nil
+	return db.collectTestResults(ctx, rows)
 }
 
-func (db *Postgres) GetFamilyResults(familyID string) ([]models.TestResult, error) {
-	ctx := context.Background()
+func (db *Postgres) GetFamilyResults(ctx context.Context, familyID string) ([]models.TestResult, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 	query := `
 		SELECT tr.id, tr.word_set_id, tr.user_id, tr.score, tr.total_words,
 		       tr.correct_words, tr.time_spent, tr.mode, tr.completed_at, tr.created_at
@@ -1206,96 +1183,367 @@ func (db *Postgres) GetFamilyResults(familyID string) ([]models.TestResult, erro
 		WHERE u.family_id = $1
 		ORDER BY tr.completed_at DESC`
 
-	rows, err := db.pool.Query(ctx, query, familyID)
+	rows, err := db.conn.Query(ctx, query, familyID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get family results: %w", err)
 	}
-	defer rows.Close()
-
-	var results []models.TestResult
-	for rows.Next() {
-		var result models.TestResult
-		err := rows.Scan(
-			&result.ID, &result.WordSetID, &result.UserID, &result.Score,
-			&result.TotalWords, &result.CorrectWords, &result.TimeSpent, &result.Mode,
-			&result.CompletedAt, &result.CreatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan result: %w", err)
-		}
-
-		wordResults, err := db.getWordTestResults(ctx, result.ID)
-		if err != nil {
-			return nil, err
-		}
-		result.Words = wordResults
-
-		results = append(results, result)
-	}
-
-	return results, nil
+	return db.collectTestResults(ctx, rows)
 }
 
-func (db *Postgres) getWordTestResults(ctx context.Context, testResultID string) ([]models.WordTestResult, error) {
-	query := `
-		SELECT word, user_answers, attempts, correct, time_spent,
-		       final_answer, hints_used, audio_play_count
-		FROM word_test_results WHERE test_result_id = $1`
-
-	rows, err := db.pool.Query(ctx, query, testResultID)
+// collectTestResults reads result summaries selected with the columns of
+// scanTestResult, then loads their word results once the rows are closed
+func (db *Postgres) collectTestResults(ctx context.Context, rows pgx.Rows) ([]models.TestResult, error) {
+	results, err := pgx.CollectRows(rows, scanTestResult)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get word test results: %w", err)
+		return nil, fmt.Errorf("failed to scan result: %w", err)
 	}
-	defer rows.Close()
-
-	var results []models.WordTestResult
-	for rows.Next() {
-		var wtr models.WordTestResult
-		var answersJSON []byte
-		err := rows.Scan(
-			&wtr.Word, &answersJSON, &wtr.Attempts, &wtr.Correct,
-			&wtr.TimeSpent, &wtr.FinalAnswer, &wtr.HintsUsed, &wtr.AudioPlayCount,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan word test result: %w", err)
-		}
-
-		if answersJSON != nil {
-			if err := json.Unmarshal(answersJSON, &wtr.UserAnswers); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal user answers: %w", err)
-			}
-		}
-
-		results = append(results, wtr)
+	if err := db.loadWordTestResults(ctx, results); err != nil {
+		return nil, err
 	}
-
 	return results, nil
 }
 
-func (db *Postgres) SaveTestResult(result *models.TestResult) error {
-	ctx := context.Background()
+// scanTestResult scans the id, word_set_id, user_id, score, total_words,
+// correct_words, time_spent, mode, completed_at and created_at columns
+func scanTestResult(row pgx.CollectableRow) (models.TestResult, error) {
+	var r models.TestResult
+	err := row.Scan(
+		&r.ID, &r.WordSetID, &r.UserID, &r.Score,
+		&r.TotalWords, &r.CorrectWords, &r.TimeSpent, &r.Mode,
+		&r.CompletedAt, &r.CreatedAt,
+	)
+	return r, err
+}
 
-	if result.ID == "" {
-		result.ID = uuid.New().String()
-	}
+func (db *Postgres) ListTestResults(ctx context.Context, filter models.ResultFilter, page models.PageRequest) ([]models.TestResult, string, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 
-	tx, err := db.pool.Begin(ctx)
+	after, err := decodeCursor(page.Cursor)
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return nil, "", err
 	}
-	defer tx.Rollback(ctx)
 
+	q := resultConditions(filter, after)
+	limit := q.limit(page.Limit)
+	const columns = `tr.id, tr.word_set_id, tr.user_id, tr.score, tr.total_words,
+		       tr.correct_words, tr.time_spent, tr.mode, tr.completed_at, tr.created_at`
+	const order = `
+		ORDER BY tr.completed_at DESC, tr.id DESC`
 	query := `
-		INSERT INTO test_results (id, word_set_id, user_id, score, total_words,
-		                          correct_words, time_spent, mode, xp_awarded, completed_at, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
-
+		SELECT ` + columns + `
+		FROM test_results tr
+		WHERE ` + q.conditions() + order + limit
+	if filter.FamilyID != "" {
+		// A family page merges each member's newest rows. The per-user indexes
+		// serve every member's subquery in order, so at most a page per member
+		// is read instead of the family's whole history.
+		query = `
+		SELECT ` + columns + `
+		FROM users u
+		CROSS JOIN LATERAL (
+			SELECT ` + columns + `
+			FROM test_results tr
+			WHERE tr.user_id = u.id AND ` + q.conditions() + order + limit + `
+		) tr
+		WHERE u.family_id = ` + q.param(filter.FamilyID) + order + limit
+	}
+
+	rows, err := db.conn.Query(ctx, query, q.args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list test results: %w", err)
+	}
+	results, err := pgx.CollectRows(rows, scanTestResult)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to scan result: %w", err)
+	}
+
+	results, next := trimPage(results, page.Limit, func(r models.TestResult) (time.Time, string) { return r.CompletedAt, r.ID })
+	if err := db.loadWordTestResults(ctx, results); err != nil {
+		return nil, "", err
+	}
+	return results, next, nil
+}
+
+// resultConditions builds the WHERE clause of a result listing, except for the
+// family, which ListTestResults applies per member. Each user's rows are read
+// through the (user_id, ..., completed_at, id) indexes.
+func resultConditions(filter models.ResultFilter, after *pageCursor) *listQuery {
+	q := &listQuery{}
+	if filter.UserID != "" {
+		q.where("tr.user_id = %s", filter.UserID)
+	}
+	if filter.WordSetID != "" {
+		q.where("tr.word_set_id = %s", filter.WordSetID)
+	}
+	if filter.Mode != "" {
+		q.where("tr.mode = %s", filter.Mode)
+	}
+	if filter.From != nil {
+		q.where("tr.completed_at >= %s", *filter.From)
+	}
+	if filter.To != nil {
+		q.where("tr.completed_at < %s", *filter.To)
+	}
+	if filter.MinScore != nil {
+		q.where("tr.score >= %s", *filter.MinScore)
+	}
+	if after != nil {
+		q.where("(tr.completed_at, tr.id) < (%s, %s)", after.At, after.ID)
+	}
+	return q
+}
+
+// listQuery collects the conditions and arguments of a filtered listing
+type listQuery struct {
+	parts []string
+	args  []any
+}
+
+// where adds a condition, replacing each %s in cond with the placeholder of the
+// matching argument
+func (q *listQuery) where(cond string, args ...any) {
+	placeholders := make([]any, len(args))
+	for i, arg := range args {
+		q.args = append(q.args, arg)
+		placeholders[i] = fmt.Sprintf("$%d", len(q.args))
+	}
+	q.parts = append(q.parts, fmt.Sprintf(cond, placeholders...))
+}
+
+// param adds an argument and returns its placeholder
+func (q *listQuery) param(arg any) string {
+	q.args = append(q.args, arg)
+	return fmt.Sprintf("$%d", len(q.args))
+}
+
+func (q *listQuery) conditions() string {
+	if len(q.parts) == 0 {
+		return "TRUE"
+	}
+	return strings.Join(q.parts, " AND ")
+}
+
+// limit returns a LIMIT clause one row past the page size, so the caller can tell
+// whether another page follows, or "" for an unlimited listing
+func (q *listQuery) limit(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	q.args = append(q.args, n+1)
+	return fmt.Sprintf(" LIMIT $%d", len(q.args))
+}
+
+// escapeLike escapes the LIKE wildcards in a search term
+func escapeLike(term string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(term)
+}
+
+// GetRecentWordSetResults retrieves a user's latest results for a word set, newest first
+func (db *Postgres) GetRecentWordSetResults(ctx context.Context, userID, wordSetID string, limit int) ([]models.TestResult, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+	query := `
+		SELECT id, word_set_id, user_id, score, total_words, correct_words,
+		       time_spent, mode, xp_awarded, completed_at, created_at
+		FROM test_results
+		WHERE user_id = $1 AND word_set_id = $2
+		ORDER BY completed_at DESC
+		LIMIT $3`
+
+	rows, err := db.conn.Query(ctx, query, userID, wordSetID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get word set results: %w", err)
+	}
+
+	results := []models.TestResult{}
+	for rows.Next() {
+		var result models.TestResult
+		err := rows.Scan(
+			&result.ID, &result.WordSetID, &result.UserID, &result.Score,
+			&result.TotalWords, &result.CorrectWords, &result.TimeSpent, &result.Mode,
+			&result.XPAwarded, &result.CompletedAt, &result.CreatedAt,
+		)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan result: %w", err)
+		}
+		results = append(results, result)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate word set results: %w", err)
+	}
+
+	for i := range results {
+		results[i].Words, err = db.getWordTestResults(ctx, results[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
+
+// GetTestResultByIdempotencyKey retrieves a user's result saved with the given key
+func (db *Postgres) GetTestResultByIdempotencyKey(ctx context.Context, userID, key string) (*models.TestResult, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+	query := `
+		SELECT id, word_set_id, user_id, score, total_words, correct_words,
+		       time_spent, mode, xp_awarded, completed_at, created_at, idempotency_key
+		FROM test_results WHERE user_id = $1 AND idempotency_key = $2`
+
+	var result models.TestResult
+	err := db.conn.QueryRow(ctx, query, userID, key).Scan(
+		&result.ID, &result.WordSetID, &result.UserID, &result.Score,
+		&result.TotalWords, &result.CorrectWords, &result.TimeSpent, &result.Mode,
+		&result.XPAwarded, &result.CompletedAt, &result.CreatedAt, &result.IdempotencyKey,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get test result: %w", err)
+	}
+
+	result.Words, err = db.getWordTestResults(ctx, result.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (db *Postgres) getWordTestResults(ctx context.Context, testResultID string) ([]models.WordTestResult, error) {
+	query := `
+		SELECT word, user_answers, attempts, correct, time_spent,
+		       final_answer, hints_used, audio_play_count,
+		       COALESCE(error_types, '{}'), sentence_score
+		FROM word_test_results WHERE test_result_id = $1`
+
+	rows, err := db.conn.Query(ctx, query, testResultID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get word test results: %w", err)
+	}
+	defer rows.Close()
+
+	var results []models.WordTestResult
+	for rows.Next() {
+		wtr, err := scanWordTestResult(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, wtr)
+	}
+
+	return results, nil
+}
+
+// loadWordTestResults fills in the word results of a page of test results with one query
+func (db *Postgres) loadWordTestResults(ctx context.Context, results []models.TestResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(results))
+	index := make(map[string]int, len(results))
+	for i, r := range results {
+		ids[i] = r.ID
+		index[r.ID] = i
+	}
+
+	query := `
+		SELECT test_result_id, word, user_answers, attempts, correct, time_spent,
+		       final_answer, hints_used, audio_play_count,
+		       COALESCE(error_types, '{}'), sentence_score
+		FROM word_test_results WHERE test_result_id = ANY($1)`
+
+	rows, err := db.conn.Query(ctx, query, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get word test results: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var resultID string
+		wtr, err := scanWordTestResult(rows, &resultID)
+		if err != nil {
+			return err
+		}
+		r := &results[index[resultID]]
+		r.Words = append(r.Words, wtr)
+	}
+
+	return rows.Err()
+}
+
+// scanWordTestResult scans the word result columns, after any leading columns
+func scanWordTestResult(rows pgx.Rows, lead ...any) (models.WordTestResult, error) {
+	var wtr models.WordTestResult
+	var answersJSON, sentenceJSON []byte
+	dest := append(lead,
+		&wtr.Word, &answersJSON, &wtr.Attempts, &wtr.Correct,
+		&wtr.TimeSpent, &wtr.FinalAnswer, &wtr.HintsUsed, &wtr.AudioPlayCount,
+		&wtr.ErrorTypes, &sentenceJSON,
+	)
+	if err := rows.Scan(dest...); err != nil {
+		return wtr, fmt.Errorf("failed to scan word test result: %w", err)
+	}
+
+	if answersJSON != nil {
+		if err := json.Unmarshal(answersJSON, &wtr.UserAnswers); err != nil {
+			return wtr, fmt.Errorf("failed to unmarshal user answers: %w", err)
+		}
+	}
+
+	if sentenceJSON != nil {
+		wtr.Sentence = &models.SentenceScore{}
+		if err := json.Unmarshal(sentenceJSON, wtr.Sentence); err != nil {
+			return wtr, fmt.Errorf("failed to unmarshal sentence score: %w", err)
+		}
+	}
+
+	return wtr, nil
+}
+
+func (db *Postgres) SaveTestResult(ctx context.Context, result *models.TestResult) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
+	if result.ID == "" {
+		result.ID = uuid.New().String()
+	}
+
+	tx, err := db.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var idempotencyKey *string
+	if result.IdempotencyKey != "" {
+		idempotencyKey = &result.IdempotencyKey
+	}
+
+	query := `
+		INSERT INTO test_results (id, word_set_id, user_id, score, total_words,
+		                          correct_words, time_spent, mode, xp_awarded, completed_at, created_at,
+		                          idempotency_key)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+
 	_, err = tx.Exec(ctx, query,
 		result.ID, result.WordSetID, result.UserID, result.Score,
 		result.TotalWords, result.CorrectWords, result.TimeSpent, result.Mode,
-		result.XPAwarded, result.CompletedAt, result.CreatedAt,
+		result.XPAwarded, result.CompletedAt, result.CreatedAt, idempotencyKey,
 	)
 	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrDuplicate
+		}
+		if isForeignKeyViolation(err) {
+			return fmt.Errorf("failed to save test result: %w", ErrMissingReference)
+		}
 		return fmt.Errorf("failed to save test result: %w", err)
 	}
 
@@ -1306,16 +1554,24 @@ func (db *Postgres) SaveTestResult(result *models.TestResult) error {
 			return fmt.Errorf("failed to marshal user answers: %w", err)
 		}
 
+		var sentenceJSON []byte
+		if wtr.Sentence != nil {
+			if sentenceJSON, err = json.Marshal(wtr.Sentence); err != nil {
+				return fmt.Errorf("failed to marshal sentence score: %w", err)
+			}
+		}
+
 		wtrQuery := `
 			INSERT INTO word_test_results (id, test_result_id, word, user_answers,
 			                               attempts, correct, time_spent, final_answer,
-			                               hints_used, audio_play_count, error_types)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+			                               hints_used, audio_play_count, error_types,
+			                               sentence_score)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
 
 		_, err = tx.Exec(ctx, wtrQuery,
 			uuid.New().String(), result.ID, wtr.Word, answersJSON,
 			wtr.Attempts, wtr.Correct, wtr.TimeSpent, wtr.FinalAnswer,
-			wtr.HintsUsed, wtr.AudioPlayCount, wtr.ErrorTypes,
+			wtr.HintsUsed, wtr.AudioPlayCount, wtr.ErrorTypes, sentenceJSON,
 		)
 		if err != nil {
 			return fmt.Errorf("failed to save word test result: %w", err)
@@ -1329,14 +1585,15 @@ func (db *Postgres) SaveTestResult(result *models.TestResult) error {
 // Audio File Operations
 // ============================================================================
 
-func (db *Postgres) GetAudioFile(word, language, voiceID string) (*models.AudioFile, error) {
-	ctx := context.Background()
+func (db *Postgres) GetAudioFile(ctx context.Context, word, language, voiceID string) (*models.AudioFile, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 	query := `
 		SELECT id, word, language, voice_id, url, created_at
 		FROM audio_files WHERE word = $1 AND language = $2 AND voice_id = $3`
 
 	var af models.AudioFile
-	err := db.pool.QueryRow(ctx, query, word, language, voiceID).Scan(
+	err := db.conn.QueryRow(ctx, query, word, language, voiceID).Scan(
 		&af.ID, &af.Word, &af.Language, &af.VoiceID,
 		&af.URL, &af.CreatedAt,
 	)
@@ -1350,8 +1607,9 @@ func (db *Postgres) GetAudioFile(word, language, voiceID string) (*models.AudioF
 	return &af, nil
 }
 
-func (db *Postgres) SaveAudioFile(af *models.AudioFile) error {
-	ctx := context.Background()
+func (db *Postgres) SaveAudioFile(ctx context.Context, af *models.AudioFile) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 
 	if af.ID == "" {
 		af.ID = uuid.New().String()
@@ -1363,7 +1621,7 @@ func (db *Postgres) SaveAudioFile(af *models.AudioFile) error {
 		ON CONFLICT (word, language, voice_id) DO UPDATE SET
 			url = EXCLUDED.url`
 
-	_, err := db.pool.Exec(ctx, query,
+	_, err := db.conn.Exec(ctx, query,
 		af.ID, af.Word, af.Language, af.VoiceID,
 		af.URL, af.CreatedAt,
 	)
@@ -1378,8 +1636,9 @@ func (db *Postgres) SaveAudioFile(af *models.AudioFile) error {
 // Progress and Stats Operations
 // ============================================================================
 
-func (db *Postgres) GetFamilyProgress(familyID string) ([]models.FamilyProgress, error) {
-	ctx := context.Background()
+func (db *Postgres) GetFamilyProgress(ctx context.Context, familyID string) ([]models.FamilyProgress, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 
 	// Get all family members including XP data
 	membersQuery := `
@@ -1388,19 +1647,23 @@ func (db *Postgres) GetFamilyProgress(familyID string) ([]models.FamilyProgress,
 		JOIN family_members fm ON u.id = fm.user_id
 		WHERE fm.family_id = $1`
 
-	rows, err := db.pool.Query(ctx, membersQuery, familyID)
+	rows, err := db.conn.Query(ctx, membersQuery, familyID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get family members: %w", err)
 	}
-	defer rows.Close()
+	// Members are collected first so the per-member queries below also work on
+	// a transaction's single connection
+	members, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.FamilyProgress, error) {
+		var fp models.FamilyProgress
+		err := row.Scan(&fp.UserID, &fp.UserName, &fp.Role, &fp.BirthYear, &fp.TotalXP, &fp.Level)
+		return fp, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan member: %w", err)
+	}
 
 	var progress []models.FamilyProgress
-	for rows.Next() {
-		var fp models.FamilyProgress
-		err := rows.Scan(&fp.UserID, &fp.UserName, &fp.Role, &fp.BirthYear, &fp.TotalXP, &fp.Level)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan member: %w", err)
-		}
+	for _, fp := range members {
 
 		// Get stats for this user
 		statsQuery := `
@@ -1408,7 +1671,7 @@ func (db *Postgres) GetFamilyProgress(familyID string) ([]models.FamilyProgress,
 			       COALESCE(SUM(correct_words), 0), COALESCE(MAX(completed_at), NOW())
 			FROM test_results WHERE user_id = $1`
 
-		err = db.pool.QueryRow(ctx, statsQuery, fp.UserID).Scan(
+		err = db.conn.QueryRow(ctx, statsQuery, fp.UserID).Scan(
 			&fp.TotalTests, &fp.AverageScore, &fp.TotalWords,
 			&fp.CorrectWords, &fp.LastActivity,
 		)
@@ -1422,7 +1685,7 @@ func (db *Postgres) GetFamilyProgress(familyID string) ([]models.FamilyProgress,
 			       time_spent, completed_at, created_at
 			FROM test_results WHERE user_id = $1 ORDER BY completed_at DESC LIMIT 5`
 
-		recentRows, err := db.pool.Query(ctx, recentQuery, fp.UserID)
+		recentRows, err := db.conn.Query(ctx, recentQuery, fp.UserID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get recent results: %w", err)
 		}
@@ -1454,7 +1717,7 @@ func (db *Postgres) GetFamilyProgress(familyID string) ([]models.FamilyProgress,
 			FROM word_mastery
 			WHERE user_id = $1`
 
-		err = db.pool.QueryRow(ctx, masteryQuery, fp.UserID).Scan(
+		err = db.conn.QueryRow(ctx, masteryQuery, fp.UserID).Scan(
 			&fp.TotalWordsWithMastery,
 			&fp.LetterTilesMasteredWords,
 			&fp.WordBankMasteredWords,
@@ -1472,8 +1735,9 @@ func (db *Postgres) GetFamilyProgress(familyID string) ([]models.FamilyProgress,
 	return progress, nil
 }
 
-func (db *Postgres) GetFamilyStats(familyID string) (*models.FamilyStats, error) {
-	ctx := context.Background()
+func (db *Postgres) GetFamilyStats(ctx context.Context, familyID string) (*models.FamilyStats, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 
 	stats := &models.FamilyStats{}
 
@@ -1484,7 +1748,7 @@ func (db *Postgres) GetFamilyStats(familyID string) (*models.FamilyStats, error)
 		JOIN family_members fm ON u.id = fm.user_id
 		WHERE fm.family_id = $1`
 
-	err := db.pool.QueryRow(ctx, memberQuery, familyID).Scan(
+	err := db.conn.QueryRow(ctx, memberQuery, familyID).Scan(
 		&stats.TotalChildren, &stats.TotalMembers,
 	)
 	if err != nil {
@@ -1493,7 +1757,7 @@ func (db *Postgres) GetFamilyStats(familyID string) (*models.FamilyStats, error)
 
 	// Get word set count
 	wsQuery := `SELECT COUNT(*) FROM word_sets WHERE family_id = $1`
-	err = db.pool.QueryRow(ctx, wsQuery, familyID).Scan(&stats.TotalWordSets)
+	err = db.conn.QueryRow(ctx, wsQuery, familyID).Scan(&stats.TotalWordSets)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get word set count: %w", err)
 	}
@@ -1506,7 +1770,7 @@ func (db *Postgres) GetFamilyStats(familyID string) (*models.FamilyStats, error)
 		JOIN family_members fm ON u.id = fm.user_id
 		WHERE fm.family_id = $1`
 
-	err = db.pool.QueryRow(ctx, testQuery, familyID).Scan(
+	err = db.conn.QueryRow(ctx, testQuery, familyID).Scan(
 		&stats.TotalTestsCompleted, &stats.AverageFamilyScore, &stats.LastActivity,
 	)
 	if err != nil {
@@ -1525,7 +1789,7 @@ func (db *Postgres) GetFamilyStats(familyID string) (*models.FamilyStats, error)
 		LIMIT 1`
 
 	var mostActive string
-	err = db.pool.QueryRow(ctx, activeQuery, familyID).Scan(&mostActive)
+	err = db.conn.QueryRow(ctx, activeQuery, familyID).Scan(&mostActive)
 	if err != nil && err != pgx.ErrNoRows {
 		return nil, fmt.Errorf("failed to get most active child: %w", err)
 	}
@@ -1536,13 +1800,14 @@ func (db *Postgres) GetFamilyStats(familyID string) (*models.FamilyStats, error)
 	return stats, nil
 }
 
-func (db *Postgres) GetUserProgress(userID string) (*models.FamilyProgress, error) {
-	ctx := context.Background()
+func (db *Postgres) GetUserProgress(ctx context.Context, userID string) (*models.FamilyProgress, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 
 	// Get user info including XP data
 	userQuery := `SELECT id, display_name, role, total_xp, level FROM users WHERE id = $1`
 	var fp models.FamilyProgress
-	err := db.pool.QueryRow(ctx, userQuery, userID).Scan(&fp.UserID, &fp.UserName, &fp.Role, &fp.TotalXP, &fp.Level)
+	err := db.conn.QueryRow(ctx, userQuery, userID).Scan(&fp.UserID, &fp.UserName, &fp.Role, &fp.TotalXP, &fp.Level)
 	if err == pgx.ErrNoRows {
 		return nil, ErrUserNotFound
 	}
@@ -1556,7 +1821,7 @@ func (db *Postgres) GetUserProgress(userID string) (*models.FamilyProgress, erro
 		       COALESCE(SUM(correct_words), 0), COALESCE(MAX(completed_at), NOW())
 		FROM test_results WHERE user_id = $1`
 
-	err = db.pool.QueryRow(ctx, statsQuery, userID).Scan(
+	err = db.conn.QueryRow(ctx, statsQuery, userID).Scan(
 		&fp.TotalTests, &fp.AverageScore, &fp.TotalWords,
 		&fp.CorrectWords, &fp.LastActivity,
 	)
@@ -1571,15 +1836,16 @@ func (db *Postgres) GetUserProgress(userID string) (*models.FamilyProgress, erro
 // Family Invitation Operations
 // ============================================================================
 
-func (db *Postgres) CreateFamilyInvitation(invitation *models.FamilyInvitation) error {
-	ctx := context.Background()
+func (db *Postgres) CreateFamilyInvitation(ctx context.Context, invitation *models.FamilyInvitation) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 	query := `
 		INSERT INTO family_invitations (id, family_id, email, role, invited_by, status, created_at, expires_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		ON CONFLICT (family_id, LOWER(email)) WHERE status = 'pending'
 		DO NOTHING`
 
-	result, err := db.pool.Exec(ctx, query,
+	result, err := db.conn.Exec(ctx, query,
 		invitation.ID,
 		invitation.FamilyID,
 		invitation.Email,
@@ -1601,8 +1867,9 @@ func (db *Postgres) CreateFamilyInvitation(invitation *models.FamilyInvitation)
 	return nil
 }
 
-func (db *Postgres) GetPendingInvitationsByEmail(email string) ([]models.FamilyInvitation, error) {
-	ctx := context.Background()
+func (db *Postgres) GetPendingInvitationsByEmail(ctx context.Context, email string) ([]models.FamilyInvitation, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 	query := `
 		SELECT i.id, i.family_id, f.name as family_name, i.email, i.role, i.invited_by, i.status, i.created_at, i.expires_at
 		FROM family_invitations i
@@ -1610,7 +1877,7 @@ func (db *Postgres) GetPendingInvitationsByEmail(email string) ([]models.FamilyI
 		WHERE LOWER(i.email) = LOWER($1) AND i.status = 'pending'
 		ORDER BY i.created_at DESC`
 
-	rows, err := db.pool.Query(ctx, query, email)
+	rows, err := db.conn.Query(ctx, query, email)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get pending invitations: %w", err)
 	}
@@ -1643,15 +1910,16 @@ func (db *Postgres) GetPendingInvitationsByEmail(email string) ([]models.FamilyI
 	return invitations, nil
 }
 
-func (db *Postgres) GetFamilyInvitations(familyID string) ([]models.FamilyInvitation, error) {
-	ctx := context.Background()
+func (db *Postgres) GetFamilyInvitations(ctx context.Context, familyID string) ([]models.FamilyInvitation, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 	query := `
 		SELECT id, family_id, email, role, invited_by, status, created_at, expires_at
 		FROM family_invitations
 		WHERE family_id = $1
 		ORDER BY created_at DESC`
 
-	rows, err := db.pool.Query(ctx, query, familyID)
+	rows, err := db.conn.Query(ctx, query, familyID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get family invitations: %w", err)
 	}
@@ -1683,9 +1951,10 @@ func (db *Postgres) GetFamilyInvitations(familyID string) ([]models.FamilyInvita
 	return invitations, nil
 }
 
-func (db *Postgres) AcceptInvitation(invitationID, userID string) error {
-	ctx := context.Background()
-	tx, err := db.pool.Begin(ctx)
+func (db *Postgres) AcceptInvitation(ctx context.Context, invitationID, userID string) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+	tx, err := db.conn.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -1746,11 +2015,12 @@ func (db *Postgres) AcceptInvitation(invitationID, userID string) error {
 	return nil
 }
 
-func (db *Postgres) DeleteInvitation(invitationID string) error {
-	ctx := context.Background()
+func (db *Postgres) DeleteInvitation(ctx context.Context, invitationID string) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 	query := `DELETE FROM family_invitations WHERE id = $1`
 
-	result, err := db.pool.Exec(ctx, query, invitationID)
+	result, err := db.conn.Exec(ctx, query, invitationID)
 	if err != nil {
 		return fmt.Errorf("failed to delete invitation: %w", err)
 	}
@@ -1766,12 +2036,13 @@ func (db *Postgres) DeleteInvitation(invitationID string) error {
 // Verification Operations
 // ============================================================================
 
-func (db *Postgres) VerifyFamilyMembership(userID, familyID string) error {
-	ctx := context.Background()
+func (db *Postgres) VerifyFamilyMembership(ctx context.Context, userID, familyID string) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 	query := `SELECT 1 FROM family_members WHERE user_id = $1 AND family_id = $2`
 
 	var exists int
-	err := db.pool.QueryRow(ctx, query, userID, familyID).Scan(&exists)
+	err := db.conn.QueryRow(ctx, query, userID, familyID).Scan(&exists)
 	if err == pgx.ErrNoRows {
 		return ErrNotFamilyMember
 	}
@@ -1782,12 +2053,13 @@ func (db *Postgres) VerifyFamilyMembership(userID, familyID string) error {
 	return nil
 }
 
-func (db *Postgres) VerifyParentPermission(userID, familyID string) error {
-	ctx := context.Background()
+func (db *Postgres) VerifyParentPermission(ctx context.Context, userID, familyID string) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 	query := `SELECT 1 FROM family_members WHERE user_id = $1 AND family_id = $2 AND role = 'parent'`
 
 	var exists int
-	err := db.pool.QueryRow(ctx, query, userID, familyID).Scan(&exists)
+	err := db.conn.QueryRow(ctx, query, userID, familyID).Scan(&exists)
 	if err == pgx.ErrNoRows {
 		return ErrNotParent
 	}
@@ -1798,12 +2070,13 @@ func (db *Postgres) VerifyParentPermission(userID, familyID string) error {
 	return nil
 }
 
-func (db *Postgres) VerifyChildOwnership(parentID, childID string) error {
-	ctx := context.Background()
+func (db *Postgres) VerifyChildOwnership(ctx context.Context, parentID, childID string) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 	query := `SELECT 1 FROM users WHERE id = $1 AND parent_id = $2 AND role = 'child'`
 
 	var exists int
-	err := db.pool.QueryRow(ctx, query, childID, parentID).Scan(&exists)
+	err := db.conn.QueryRow(ctx, query, childID, parentID).Scan(&exists)
 	if err == pgx.ErrNoRows {
 		return ErrNotChildOwner
 	}
@@ -1814,13 +2087,14 @@ func (db *Postgres) VerifyChildOwnership(parentID, childID string) error {
 	return nil
 }
 
-func (db *Postgres) VerifyWordSetAccess(familyID, wordSetID string) error {
-	ctx := context.Background()
+func (db *Postgres) VerifyWordSetAccess(ctx context.Context, familyID, wordSetID string) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 	// Allow access if word set belongs to family OR if it's a global (curated) word set
 	query := `SELECT 1 FROM word_sets WHERE id = $1 AND (family_id = $2 OR is_global = true)`
 
 	var exists int
-	err := db.pool.QueryRow(ctx, query, wordSetID, familyID).Scan(&exists)
+	err := db.conn.QueryRow(ctx, query, wordSetID, familyID).Scan(&exists)
 	if err == pgx.ErrNoRows {
 		return ErrNoAccess
 	}
@@ -1835,13 +2109,14 @@ func (db *Postgres) VerifyWordSetAccess(familyID, wordSetID string) error {
 // Word Set Assignment Operations
 // ============================================================================
 
-func (db *Postgres) AssignWordSetToUser(wordSetID, userID, assignedBy string) error {
-	ctx := context.Background()
+func (db *Postgres) AssignWordSetToUser(ctx context.Context, wordSetID, userID, assignedBy string) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 
 	// Verify the user is a child
 	var role string
 	roleQuery := `SELECT role FROM users WHERE id = $1`
-	err := db.pool.QueryRow(ctx, roleQuery, userID).Scan(&role)
+	err := db.conn.QueryRow(ctx, roleQuery, userID).Scan(&role)
 	if err == pgx.ErrNoRows {
 		return fmt.Errorf("user not found")
 	}
@@ -1858,20 +2133,24 @@ func (db *Postgres) AssignWordSetToUser(wordSetID, userID, assignedBy string) er
 		VALUES ($1, $2, $3, now())
 		ON CONFLICT (wordset_id, user_id) DO NOTHING`
 
-	_, err = db.pool.Exec(ctx, query, wordSetID, userID, assignedBy)
+	_, err = db.conn.Exec(ctx, query, wordSetID, userID, assignedBy)
 	if err != nil {
+		if isForeignKeyViolation(err) {
+			return fmt.Errorf("failed to assign wordset to user: %w", ErrMissingReference)
+		}
 		return fmt.Errorf("failed to assign wordset to user: %w", err)
 	}
 
 	return nil
 }
 
-func (db *Postgres) UnassignWordSetFromUser(wordSetID, userID string) error {
-	ctx := context.Background()
+func (db *Postgres) UnassignWordSetFromUser(ctx context.Context, wordSetID, userID string) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 
 	query := `DELETE FROM wordset_assignments WHERE wordset_id = $1 AND user_id = $2`
 
-	result, err := db.pool.Exec(ctx, query, wordSetID, userID)
+	result, err := db.conn.Exec(ctx, query, wordSetID, userID)
 	if err != nil {
 		return fmt.Errorf("failed to unassign wordset from user: %w", err)
 	}
@@ -1883,12 +2162,13 @@ func (db *Postgres) UnassignWordSetFromUser(wordSetID, userID string) error {
 	return nil
 }
 
-func (db *Postgres) GetWordSetAssignments(wordSetID string) ([]string, error) {
-	ctx := context.Background()
+func (db *Postgres) GetWordSetAssignments(ctx context.Context, wordSetID string) ([]string, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 
 	query := `SELECT user_id FROM wordset_assignments WHERE wordset_id = $1 ORDER BY assigned_at`
 
-	rows, err := db.pool.Query(ctx, query, wordSetID)
+	rows, err := db.conn.Query(ctx, query, wordSetID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get wordset assignments: %w", err)
 	}
@@ -1906,13 +2186,160 @@ func (db *Postgres) GetWordSetAssignments(wordSetID string) ([]string, error) {
 	return userIDs, nil
 }
 
+func (db *Postgres) SetWordSetAssignmentSchedule(ctx context.Context, wordSetID, userID string, schedule *models.AssignmentSchedule) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
+	frequency := schedule.ReminderFrequency
+	if frequency == "" {
+		frequency = models.ReminderNone
+	}
+
+	var goalMode *string
+	var goalRuns *int
+	var goalMinScore *float64
+	if schedule.Goal != nil {
+		if schedule.Goal.Mode != "" {
+			goalMode = &schedule.Goal.Mode
+		}
+		goalRuns = &schedule.Goal.Runs
+		goalMinScore = &schedule.Goal.MinScore
+	}
+
+	query := `
+		UPDATE wordset_assignments
+		SET due_at = $3, goal_mode = $4, goal_runs = $5, goal_min_score = $6,
+		    reminder_frequency = $7, reminder_hour = $8
+		WHERE wordset_id = $1 AND user_id = $2`
+
+	result, err := db.conn.Exec(ctx, query, wordSetID, userID,
+		schedule.DueAt, goalMode, goalRuns, goalMinScore, string(frequency), schedule.ReminderHour)
+	if err != nil {
+		return fmt.Errorf("failed to update assignment schedule: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// assignmentColumns selects a WordSetAssignment; used with scanAssignment
+const assignmentColumns = `
+		wa.wordset_id, ws.name, wa.user_id, COALESCE(u.family_id, ''), wa.assigned_by, wa.assigned_at,
+		wa.due_at, wa.goal_mode, wa.goal_runs, wa.goal_min_score,
+		wa.reminder_frequency, wa.reminder_hour, wa.last_reminded_at`
+
+func scanAssignment(rows pgx.Rows) (models.WordSetAssignment, error) {
+	var a models.WordSetAssignment
+	var goalMode *string
+	var goalRuns *int
+	var goalMinScore *float64
+	var frequency string
+
+	err := rows.Scan(
+		&a.WordSetID, &a.WordSetName, &a.UserID, &a.FamilyID, &a.AssignedBy, &a.AssignedAt,
+		&a.DueAt, &goalMode, &goalRuns, &goalMinScore,
+		&frequency, &a.ReminderHour, &a.LastRemindedAt,
+	)
+	if err != nil {
+		return a, err
+	}
+
+	a.ReminderFrequency = models.ReminderFrequency(frequency)
+	if goalRuns != nil {
+		a.Goal = &models.PracticeGoal{Runs: *goalRuns}
+		if goalMode != nil {
+			a.Goal.Mode = *goalMode
+		}
+		if goalMinScore != nil {
+			a.Goal.MinScore = *goalMinScore
+		}
+	}
+
+	return a, nil
+}
+
+func (db *Postgres) queryAssignments(ctx context.Context, query string, args ...any) ([]models.WordSetAssignment, error) {
+	rows, err := db.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assignments: %w", err)
+	}
+	defer rows.Close()
+
+	assignments := []models.WordSetAssignment{}
+	for rows.Next() {
+		a, err := scanAssignment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan assignment: %w", err)
+		}
+		assignments = append(assignments, a)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating assignments: %w", err)
+	}
+
+	return assignments, nil
+}
+
+func (db *Postgres) GetUserAssignments(ctx context.Context, userID string) ([]models.WordSetAssignment, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+	query := `
+		SELECT ` + assignmentColumns + `
+		FROM wordset_assignments wa
+		JOIN word_sets ws ON ws.id = wa.wordset_id
+		JOIN users u ON u.id = wa.user_id
+		WHERE wa.user_id = $1
+		ORDER BY wa.due_at ASC NULLS LAST, wa.assigned_at DESC`
+
+	return db.queryAssignments(ctx, query, userID)
+}
+
+func (db *Postgres) GetReminderAssignments(ctx context.Context) ([]models.WordSetAssignment, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+	query := `
+		SELECT ` + assignmentColumns + `
+		FROM wordset_assignments wa
+		JOIN word_sets ws ON ws.id = wa.wordset_id
+		JOIN users u ON u.id = wa.user_id
+		WHERE wa.reminder_frequency <> 'none'
+		  AND u.is_active = true
+		  AND (wa.due_at IS NULL OR wa.due_at > now())
+		ORDER BY wa.user_id, wa.assigned_at`
+
+	return db.queryAssignments(ctx, query)
+}
+
+func (db *Postgres) ClaimAssignmentReminder(ctx context.Context, wordSetID, userID string, slot, remindedAt time.Time) (bool, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
+	// Only one scheduler (replica) can move last_reminded_at past the slot
+	query := `
+		UPDATE wordset_assignments SET last_reminded_at = $4
+		WHERE wordset_id = $1 AND user_id = $2
+		  AND (last_reminded_at IS NULL OR last_reminded_at < $3)`
+
+	result, err := db.conn.Exec(ctx, query, wordSetID, userID, slot, remindedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim assignment reminder: %w", err)
+	}
+
+	return result.RowsAffected() == 1, nil
+}
+
 // ============================================================================
 // Word Mastery Operations
 // ============================================================================
 
 // GetWordMastery retrieves mastery for a specific word
-func (db *Postgres) GetWordMastery(userID, wordSetID, word string) (*models.WordMastery, error) {
-	ctx := context.Background()
+func (db *Postgres) GetWordMastery(ctx context.Context, userID, wordSetID, word string) (*models.WordMastery, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 	query := `
 		SELECT id, user_id, word_set_id, word, letter_tiles_correct, word_bank_correct,
 		       keyboard_correct, missing_letters_correct, translation_correct, created_at, updated_at
@@ -1920,7 +2347,7 @@ func (db *Postgres) GetWordMastery(userID, wordSetID, word string) (*models.Word
 		WHERE user_id = $1 AND word_set_id = $2 AND word = $3`
 
 	var m models.WordMastery
-	err := db.pool.QueryRow(ctx, query, userID, wordSetID, word).Scan(
+	err := db.conn.QueryRow(ctx, query, userID, wordSetID, word).Scan(
 		&m.ID, &m.UserID, &m.WordSetID, &m.Word,
 		&m.LetterTilesCorrect, &m.WordBankCorrect, &m.KeyboardCorrect,
 		&m.MissingLettersCorrect, &m.TranslationCorrect,
@@ -1937,8 +2364,9 @@ func (db *Postgres) GetWordMastery(userID, wordSetID, word string) (*models.Word
 }
 
 // GetWordSetMastery retrieves all mastery records for a user's word set
-func (db *Postgres) GetWordSetMastery(userID, wordSetID string) ([]models.WordMastery, error) {
-	ctx := context.Background()
+func (db *Postgres) GetWordSetMastery(ctx context.Context, userID, wordSetID string) ([]models.WordMastery, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 	query := `
 		SELECT id, user_id, word_set_id, word, letter_tiles_correct, word_bank_correct,
 		       keyboard_correct, missing_letters_correct, translation_correct, created_at, updated_at
@@ -1946,7 +2374,7 @@ func (db *Postgres) GetWordSetMastery(userID, wordSetID string) ([]models.WordMa
 		WHERE user_id = $1 AND word_set_id = $2
 		ORDER BY word`
 
-	rows, err := db.pool.Query(ctx, query, userID, wordSetID)
+	rows, err := db.conn.Query(ctx, query, userID, wordSetID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get word set mastery: %w", err)
 	}
@@ -1972,8 +2400,9 @@ func (db *Postgres) GetWordSetMastery(userID, wordSetID string) ([]models.WordMa
 // IncrementMastery increments the mastery counter for a specific test mode
 // letterTiles, wordBank, keyboard, missingLetters, and translation modes have mastery tracking
 // flashcard and lookCoverWrite are self-reported and don't track mastery
-func (db *Postgres) IncrementMastery(userID, wordSetID, word string, mode models.TestMode) (*models.WordMastery, error) {
-	ctx := context.Background()
+func (db *Postgres) IncrementMastery(ctx context.Context, userID, wordSetID, word string, mode models.TestMode) (*models.WordMastery, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 
 	// Determine which column to increment based on mode
 	var column string
@@ -2004,13 +2433,16 @@ func (db *Postgres) IncrementMastery(userID, wordSetID, word string, mode models
 		column, column, column)
 
 	var m models.WordMastery
-	err := db.pool.QueryRow(ctx, query, uuid.New().String(), userID, wordSetID, word).Scan(
+	err := db.conn.QueryRow(ctx, query, uuid.New().String(), userID, wordSetID, word).Scan(
 		&m.ID, &m.UserID, &m.WordSetID, &m.Word,
 		&m.LetterTilesCorrect, &m.WordBankCorrect, &m.KeyboardCorrect,
 		&m.MissingLettersCorrect, &m.TranslationCorrect,
 		&m.CreatedAt, &m.UpdatedAt,
 	)
 	if err != nil {
+		if isForeignKeyViolation(err) {
+			return nil, fmt.Errorf("failed to increment mastery: %w", ErrMissingReference)
+		}
 		return nil, fmt.Errorf("failed to increment mastery: %w", err)
 	}
 
@@ -2022,11 +2454,12 @@ func (db *Postgres) IncrementMastery(userID, wordSetID, word string, mode models
 // ============================================================================
 
 // GetUserXP returns the user's current XP and level
-func (db *Postgres) GetUserXP(userID string) (totalXP int, level int, err error) {
-	ctx := context.Background()
+func (db *Postgres) GetUserXP(ctx context.Context, userID string) (totalXP int, level int, err error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 	query := `SELECT total_xp, level FROM users WHERE id = $1`
 
-	err = db.pool.QueryRow(ctx, query, userID).Scan(&totalXP, &level)
+	err = db.conn.QueryRow(ctx, query, userID).Scan(&totalXP, &level)
 	if err == pgx.ErrNoRows {
 		return 0, 1, ErrUserNotFound
 	}
@@ -2037,11 +2470,12 @@ func (db *Postgres) GetUserXP(userID string) (totalXP int, level int, err error)
 }
 
 // UpdateUserXP updates the user's total XP and level
-func (db *Postgres) UpdateUserXP(userID string, xpAwarded, newTotalXP, newLevel int) error {
-	ctx := context.Background()
+func (db *Postgres) UpdateUserXP(ctx context.Context, userID string, xpAwarded, newTotalXP, newLevel int) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 	query := `UPDATE users SET total_xp = $2, level = $3 WHERE id = $1`
 
-	result, err := db.pool.Exec(ctx, query, userID, newTotalXP, newLevel)
+	result, err := db.conn.Exec(ctx, query, userID, newTotalXP, newLevel)
 	if err != nil {
 		return fmt.Errorf("failed to update user XP: %w", err)
 	}
@@ -2052,15 +2486,17 @@ func (db *Postgres) UpdateUserXP(userID string, xpAwarded, newTotalXP, newLevel
 }
 
 // GetRecentCompletions returns how many times a user has completed a word set + mode
-// combination since the specified time
-func (db *Postgres) GetRecentCompletions(userID, wordSetID, mode string, since time.Time) (int, error) {
-	ctx := context.Background()
+// combination in the window [since, until)
+func (db *Postgres) GetRecentCompletions(ctx context.Context, userID, wordSetID, mode string, since, until time.Time) (int, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 	query := `
 		SELECT COUNT(*) FROM test_results
-		WHERE user_id = $1 AND word_set_id = $2 AND mode = $3 AND completed_at >= $4`
+		WHERE user_id = $1 AND word_set_id = $2 AND mode = $3
+		  AND completed_at >= $4 AND completed_at < $5`
 
 	var count int
-	err := db.pool.QueryRow(ctx, query, userID, wordSetID, mode, since).Scan(&count)
+	err := db.conn.QueryRow(ctx, query, userID, wordSetID, mode, since, until).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get recent completions: %w", err)
 	}
@@ -2068,8 +2504,9 @@ func (db *Postgres) GetRecentCompletions(userID, wordSetID, mode string, since t
 }
 
 // IsFirstCompletion checks if this is the user's first completion of this word set + mode
-func (db *Postgres) IsFirstCompletion(userID, wordSetID, mode string) (bool, error) {
-	ctx := context.Background()
+func (db *Postgres) IsFirstCompletion(ctx context.Context, userID, wordSetID, mode string) (bool, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
 	query := `
 		SELECT EXISTS(
 			SELECT 1 FROM test_results
@@ -2077,9 +2514,1135 @@ func (db *Postgres) IsFirstCompletion(userID, wordSetID, mode string) (bool, err
 		)`
 
 	var exists bool
-	err := db.pool.QueryRow(ctx, query, userID, wordSetID, mode).Scan(&exists)
+	err := db.conn.QueryRow(ctx, query, userID, wordSetID, mode).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("failed to check first completion: %w", err)
 	}
 	return !exists, nil // First completion if NOT exists
 }
+
+// ============================================================================
+// Family API Key Operations
+// ============================================================================
+
+func (db *Postgres) CreateFamilyAPIKey(ctx context.Context, key *models.FamilyAPIKey) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+	if key.ID == "" {
+		key.ID = uuid.New().String()
+	}
+	if key.CreatedAt.IsZero() {
+		key.CreatedAt = time.Now()
+	}
+
+	query := `
+		INSERT INTO family_api_keys (id, family_id, name, key_prefix, key_hash, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err := db.conn.Exec(ctx, query,
+		key.ID,
+		key.FamilyID,
+		key.Name,
+		key.KeyPrefix,
+		key.KeyHash,
+		key.CreatedBy,
+		key.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create family API key: %w", err)
+	}
+
+	return nil
+}
+
+func (db *Postgres) GetFamilyAPIKeyByHash(ctx context.Context, keyHash string) (*models.FamilyAPIKey, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+	query := `
+		SELECT id, family_id, name, key_prefix, key_hash, created_by, created_at, last_used_at
+		FROM family_api_keys
+		WHERE key_hash = $1`
+
+	var key models.FamilyAPIKey
+	err := db.conn.QueryRow(ctx, query, keyHash).Scan(
+		&key.ID,
+		&key.FamilyID,
+		&key.Name,
+		&key.KeyPrefix,
+		&key.KeyHash,
+		&key.CreatedBy,
+		&key.CreatedAt,
+		&key.LastUsedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get family API key: %w", err)
+	}
+
+	return &key, nil
+}
+
+func (db *Postgres) GetFamilyAPIKeys(ctx context.Context, familyID string) ([]models.FamilyAPIKey, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+	query := `
+		SELECT id, family_id, name, key_prefix, created_by, created_at, last_used_at
+		FROM family_api_keys
+		WHERE family_id = $1
+		ORDER BY created_at DESC`
+
+	rows, err := db.conn.Query(ctx, query, familyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get family API keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []models.FamilyAPIKey{}
+	for rows.Next() {
+		var key models.FamilyAPIKey
+		err := rows.Scan(
+			&key.ID,
+			&key.FamilyID,
+			&key.Name,
+			&key.KeyPrefix,
+			&key.CreatedBy,
+			&key.CreatedAt,
+			&key.LastUsedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan family API key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating family API keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+func (db *Postgres) DeleteFamilyAPIKey(ctx context.Context, familyID, keyID string) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+	query := `DELETE FROM family_api_keys WHERE id = $1 AND family_id = $2`
+
+	result, err := db.conn.Exec(ctx, query, keyID, familyID)
+	if err != nil {
+		return fmt.Errorf("failed to delete family API key: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (db *Postgres) TouchFamilyAPIKey(ctx context.Context, keyID string) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+	query := `UPDATE family_api_keys SET last_used_at = now() WHERE id = $1`
+
+	if _, err := db.conn.Exec(ctx, query, keyID); err != nil {
+		return fmt.Errorf("failed to update family API key usage: %w", err)
+	}
+
+	return nil
+}
+
+// ============================================================================
+// Webhook Operations
+// ============================================================================
+
+func (db *Postgres) CreateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+	if webhook.ID == "" {
+		webhook.ID = uuid.New().String()
+	}
+	now := time.Now()
+	if webhook.CreatedAt.IsZero() {
+		webhook.CreatedAt = now
+	}
+	webhook.UpdatedAt = now
+	if webhook.EventTypes == nil {
+		webhook.EventTypes = []string{}
+	}
+
+	query := `
+		INSERT INTO webhooks (id, family_id, url, secret, description, event_types,
+		                      is_active, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	_, err := db.conn.Exec(ctx, query,
+		webhook.ID, webhook.FamilyID, webhook.URL, webhook.Secret, webhook.Description,
+		webhook.EventTypes, webhook.IsActive, webhook.CreatedBy, webhook.CreatedAt, webhook.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	return nil
+}
+
+func (db *Postgres) GetFamilyWebhooks(ctx context.Context, familyID string) ([]models.Webhook, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+	query := `
+		SELECT id, family_id, url, description, event_types, is_active,
+		       created_by, created_at, updated_at
+		FROM webhooks
+		WHERE family_id = $1
+		ORDER BY created_at DESC`
+
+	rows, err := db.conn.Query(ctx, query, familyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := []models.Webhook{}
+	for rows.Next() {
+		var w models.Webhook
+		err := rows.Scan(
+			&w.ID, &w.FamilyID, &w.URL, &w.Description, &w.EventTypes, &w.IsActive,
+			&w.CreatedBy, &w.CreatedAt, &w.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook: %w", err)
+		}
+		webhooks = append(webhooks, w)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+func (db *Postgres) DeleteWebhook(ctx context.Context, familyID, webhookID string) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+	query := `DELETE FROM webhooks WHERE id = $1 AND family_id = $2`
+
+	result, err := db.conn.Exec(ctx, query, webhookID, familyID)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (db *Postgres) GetWebhookDeliveries(ctx context.Context, familyID, webhookID string, limit int) ([]models.WebhookDelivery, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
+	// Scope to the family first so a foreign webhook ID is indistinguishable from a missing one
+	var exists int
+	err := db.conn.QueryRow(ctx, `SELECT 1 FROM webhooks WHERE id = $1 AND family_id = $2`, webhookID, familyID).Scan(&exists)
+	if err == pgx.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify webhook: %w", err)
+	}
+
+	query := `
+		SELECT id, webhook_id, event_id, event_type, payload, status, attempts,
+		       next_attempt_at, last_attempt_at, response_status, last_error,
+		       created_at, delivered_at
+		FROM webhook_deliveries
+		WHERE webhook_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2`
+
+	rows, err := db.conn.Query(ctx, query, webhookID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		var d models.WebhookDelivery
+		err := rows.Scan(
+			&d.ID, &d.WebhookID, &d.EventID, &d.EventType, &d.Payload, &d.Status, &d.Attempts,
+			&d.NextAttemptAt, &d.LastAttemptAt, &d.ResponseStatus, &d.LastError,
+			&d.CreatedAt, &d.DeliveredAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func (db *Postgres) EnqueueWebhookDeliveries(ctx context.Context, familyID, eventID, eventType string, payload []byte) (int, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
+	// One outbox row per active webhook subscribed to this event type (empty list = all types)
+	query := `
+		INSERT INTO webhook_deliveries (id, webhook_id, event_id, event_type, payload)
+		SELECT gen_random_uuid()::text, id, $2, $3, $4
+		FROM webhooks
+		WHERE family_id = $1
+		  AND is_active = true
+		  AND (cardinality(event_types) = 0 OR $3 = ANY(event_types))
+		ON CONFLICT (webhook_id, event_id) DO NOTHING`
+
+	result, err := db.conn.Exec(ctx, query, familyID, eventID, eventType, payload)
+	if err != nil {
+		return 0, fmt.Errorf("failed to enqueue webhook deliveries: %w", err)
+	}
+
+	return int(result.RowsAffected()), nil
+}
+
+func (db *Postgres) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
+	// Push next_attempt_at forward by the lease so concurrent dispatchers (other replicas)
+	// skip these rows; if this process dies mid-delivery the row becomes due again.
+	query := `
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = now() + make_interval(secs => $2)
+		FROM webhooks w
+		WHERE d.webhook_id = w.id
+		  AND d.id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= now()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		  )
+		RETURNING d.id, d.webhook_id, d.event_id, d.event_type, d.payload, d.status,
+		          d.attempts, d.next_attempt_at, d.created_at, w.url, w.secret`
+
+	rows, err := db.conn.Query(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []models.WebhookDelivery
+	for rows.Next() {
+		var d models.WebhookDelivery
+		err := rows.Scan(
+			&d.ID, &d.WebhookID, &d.EventID, &d.EventType, &d.Payload, &d.Status,
+			&d.Attempts, &d.NextAttemptAt, &d.CreatedAt, &d.URL, &d.Secret,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan claimed delivery: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating claimed deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func (db *Postgres) UpdateWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, next_attempt_at = $4, last_attempt_at = $5,
+		    response_status = $6, last_error = $7, delivered_at = $8
+		WHERE id = $1`
+
+	result, err := db.conn.Exec(ctx, query,
+		delivery.ID, delivery.Status, delivery.Attempts, delivery.NextAttemptAt,
+		delivery.LastAttemptAt, delivery.ResponseStatus, delivery.LastError, delivery.DeliveredAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// ============================================================================
+// Email Digest Operations
+// ============================================================================
+
+func (db *Postgres) GetDigestPreferences(ctx context.Context, userID string) (*models.DigestPreferences, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+	query := `
+		SELECT user_id, enabled, language, last_sent_at, updated_at
+		FROM digest_preferences
+		WHERE user_id = $1`
+
+	var prefs models.DigestPreferences
+	err := db.conn.QueryRow(ctx, query, userID).Scan(
+		&prefs.UserID, &prefs.Enabled, &prefs.Language, &prefs.LastSentAt, &prefs.UpdatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		// Parents are opted out until they enable the digest
+		return &models.DigestPreferences{
+			UserID:   userID,
+			Language: models.DigestLanguageNorwegian,
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get digest preferences: %w", err)
+	}
+
+	return &prefs, nil
+}
+
+func (db *Postgres) UpsertDigestPreferences(ctx context.Context, prefs *models.DigestPreferences) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+	if prefs.Language == "" {
+		prefs.Language = models.DigestLanguageNorwegian
+	}
+	prefs.UpdatedAt = time.Now()
+
+	query := `
+		INSERT INTO digest_preferences (user_id, enabled, language, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) DO UPDATE
+		SET enabled = EXCLUDED.enabled,
+		    language = EXCLUDED.language,
+		    updated_at = EXCLUDED.updated_at
+		RETURNING last_sent_at`
+
+	err := db.conn.QueryRow(ctx, query, prefs.UserID, prefs.Enabled, prefs.Language, prefs.UpdatedAt).Scan(&prefs.LastSentAt)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return fmt.Errorf("failed to save digest preferences: %w", ErrMissingReference)
+		}
+		return fmt.Errorf("failed to save digest preferences: %w", err)
+	}
+
+	return nil
+}
+
+func (db *Postgres) ClaimDueDigestRecipients(ctx context.Context, sentBefore, claimedAt time.Time) ([]models.DigestRecipient, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
+	// Set last_sent_at before the digest is sent so concurrent schedulers (other replicas)
+	// skip these rows; the previous value is returned so a failed send can be released.
+	query := `
+		WITH due AS (
+			SELECT dp.user_id, dp.last_sent_at
+			FROM digest_preferences dp
+			JOIN users u ON u.id = dp.user_id
+			WHERE dp.enabled = true
+			  AND u.role = 'parent'
+			  AND u.is_active = true
+			  AND u.email <> ''
+			  AND u.family_id IS NOT NULL
+			  AND (dp.last_sent_at IS NULL OR dp.last_sent_at < $1)
+			FOR UPDATE OF dp SKIP LOCKED
+		), claimed AS (
+			UPDATE digest_preferences dp
+			SET last_sent_at = $2
+			FROM due
+			WHERE dp.user_id = due.user_id
+			RETURNING dp.user_id, dp.language, due.last_sent_at
+		)
+		SELECT u.id, u.email, u.display_name, u.family_id, c.language, c.last_sent_at
+		FROM claimed c
+		JOIN users u ON u.id = c.user_id
+		ORDER BY u.family_id, u.id`
+
+	rows, err := db.conn.Query(ctx, query, sentBefore, claimedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim digest recipients: %w", err)
+	}
+	defer rows.Close()
+
+	recipients := []models.DigestRecipient{}
+	for rows.Next() {
+		var r models.DigestRecipient
+		if err := rows.Scan(&r.UserID, &r.Email, &r.DisplayName, &r.FamilyID, &r.Language, &r.LastSentAt); err != nil {
+			return nil, fmt.Errorf("failed to scan claimed digest recipient: %w", err)
+		}
+		recipients = append(recipients, r)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating claimed digest recipients: %w", err)
+	}
+
+	return recipients, nil
+}
+
+func (db *Postgres) ReleaseDigestClaim(ctx context.Context, userID string, claimedAt time.Time, previous *time.Time) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+	query := `UPDATE digest_preferences SET last_sent_at = $3 WHERE user_id = $1 AND last_sent_at = $2`
+
+	if _, err := db.conn.Exec(ctx, query, userID, claimedAt, previous); err != nil {
+		return fmt.Errorf("failed to release digest claim: %w", err)
+	}
+
+	return nil
+}
+
+func (db *Postgres) GetTestResultsInRange(ctx context.Context, userID string, from, to time.Time) ([]models.TestResult, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+	query := `
+		SELECT id, word_set_id, user_id, score, total_words, correct_words,
+		       time_spent, mode, xp_awarded, completed_at, created_at
+		FROM test_results
+		WHERE user_id = $1 AND completed_at >= $2 AND completed_at < $3
+		ORDER BY completed_at ASC`
+
+	rows, err := db.conn.Query(ctx, query, userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get test results: %w", err)
+	}
+
+	results := []models.TestResult{}
+	for rows.Next() {
+		var result models.TestResult
+		err := rows.Scan(
+			&result.ID, &result.WordSetID, &result.UserID, &result.Score,
+			&result.TotalWords, &result.CorrectWords, &result.TimeSpent, &result.Mode,
+			&result.XPAwarded, &result.CompletedAt, &result.CreatedAt,
+		)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan result: %w", err)
+		}
+		results = append(results, result)
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating test results: %w", err)
+	}
+
+	// Word results are loaded after the outer rows are released to avoid holding two connections
+	for i := range results {
+		wordResults, err := db.getWordTestResults(ctx, results[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		results[i].Words = wordResults
+	}
+
+	return results, nil
+}
+
+// ============================================================================
+// Notification Inbox Operations
+// ============================================================================
+
+func (db *Postgres) CreateNotifications(ctx context.Context, notifications []models.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := db.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO notifications (id, user_id, family_id, subject_user_id, kind, message_key, params, created_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7, $8)`
+
+	for i := range notifications {
+		n := &notifications[i]
+		if n.ID == "" {
+			n.ID = uuid.New().String()
+		}
+		if n.CreatedAt.IsZero() {
+			n.CreatedAt = time.Now().UTC()
+		}
+		if n.Params == nil {
+			n.Params = map[string]any{}
+		}
+
+		params, err := json.Marshal(n.Params)
+		if err != nil {
+			return fmt.Errorf("failed to encode notification params: %w", err)
+		}
+
+		_, err = tx.Exec(ctx, query, n.ID, n.UserID, n.FamilyID, n.SubjectUserID, n.Kind, n.MessageKey, params, n.CreatedAt)
+		if err != nil {
+			if isForeignKeyViolation(err) {
+				return fmt.Errorf("failed to create notification: %w", ErrMissingReference)
+			}
+			return fmt.Errorf("failed to create notification: %w", err)
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (db *Postgres) GetNotifications(ctx context.Context, userID string, unreadOnly bool, limit int) ([]models.Notification, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+	query := `
+		SELECT id, user_id, family_id, COALESCE(subject_user_id, ''), kind, message_key,
+		       params, read_at, created_at
+		FROM notifications
+		WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)
+		ORDER BY created_at DESC
+		LIMIT $3`
+
+	rows, err := db.conn.Query(ctx, query, userID, unreadOnly, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notifications: %w", err)
+	}
+	defer rows.Close()
+
+	notifications := []models.Notification{}
+	for rows.Next() {
+		var n models.Notification
+		var params []byte
+		err := rows.Scan(
+			&n.ID, &n.UserID, &n.FamilyID, &n.SubjectUserID, &n.Kind, &n.MessageKey,
+			&params, &n.ReadAt, &n.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		if err := json.Unmarshal(params, &n.Params); err != nil {
+			return nil, fmt.Errorf("failed to decode notification params: %w", err)
+		}
+		notifications = append(notifications, n)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating notifications: %w", err)
+	}
+
+	return notifications, nil
+}
+
+func (db *Postgres) CountUnreadNotifications(ctx context.Context, userID string) (int, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+	query := `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL`
+
+	var count int
+	if err := db.conn.QueryRow(ctx, query, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+
+	return count, nil
+}
+
+func (db *Postgres) MarkNotificationsRead(ctx context.Context, userID string, ids []string, readAt time.Time) (int, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+	query := `
+		UPDATE notifications SET read_at = $3
+		WHERE user_id = $1 AND read_at IS NULL AND ($2::text[] IS NULL OR id = ANY($2))`
+
+	result, err := db.conn.Exec(ctx, query, userID, ids, readAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications read: %w", err)
+	}
+
+	return int(result.RowsAffected()), nil
+}
+
+func (db *Postgres) DismissNotifications(ctx context.Context, userID string, ids []string) (int, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+	query := `DELETE FROM notifications WHERE user_id = $1 AND ($2::text[] IS NULL OR id = ANY($2))`
+
+	result, err := db.conn.Exec(ctx, query, userID, ids)
+	if err != nil {
+		return 0, fmt.Errorf("failed to dismiss notifications: %w", err)
+	}
+
+	return int(result.RowsAffected()), nil
+}
+
+// ============================================================================
+// Test Session Operations
+// ============================================================================
+
+const testSessionColumns = `
+	id, user_id, family_id, word_set_id, mode, status, words, current_index,
+	max_attempts, result_id, version, started_at, last_activity_at, expires_at, completed_at`
+
+func scanTestSession(row pgx.Row) (*models.TestSession, error) {
+	var s models.TestSession
+	var words []byte
+	err := row.Scan(
+		&s.ID, &s.UserID, &s.FamilyID, &s.WordSetID, &s.Mode, &s.Status, &words, &s.CurrentIndex,
+		&s.MaxAttempts, &s.ResultID, &s.Version, &s.StartedAt, &s.LastActivityAt, &s.ExpiresAt, &s.CompletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(words, &s.Words); err != nil {
+		return nil, fmt.Errorf("failed to decode session words: %w", err)
+	}
+	return &s, nil
+}
+
+func (db *Postgres) CreateTestSession(ctx context.Context, session *models.TestSession) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
+	words, err := json.Marshal(session.Words)
+	if err != nil {
+		return fmt.Errorf("failed to encode session words: %w", err)
+	}
+
+	query := `
+		INSERT INTO test_sessions (
+			id, user_id, family_id, word_set_id, mode, status, words, current_index,
+			max_attempts, version, started_at, last_activity_at, expires_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
+
+	_, err = db.conn.Exec(ctx, query,
+		session.ID, session.UserID, session.FamilyID, session.WordSetID, session.Mode, session.Status,
+		words, session.CurrentIndex, session.MaxAttempts, session.Version,
+		session.StartedAt, session.LastActivityAt, session.ExpiresAt,
+	)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return fmt.Errorf("failed to create test session: %w", ErrMissingReference)
+		}
+		return fmt.Errorf("failed to create test session: %w", err)
+	}
+
+	return nil
+}
+
+func (db *Postgres) GetTestSession(ctx context.Context, userID, sessionID string) (*models.TestSession, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+	query := `SELECT ` + testSessionColumns + ` FROM test_sessions WHERE id = $1 AND user_id = $2`
+
+	session, err := scanTestSession(db.conn.QueryRow(ctx, query, sessionID, userID))
+	if err == pgx.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get test session: %w", err)
+	}
+
+	return session, nil
+}
+
+func (db *Postgres) GetActiveTestSessions(ctx context.Context, userID string, now time.Time) ([]models.TestSession, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+	query := `
+		SELECT ` + testSessionColumns + `
+		FROM test_sessions
+		WHERE user_id = $1 AND status = 'active' AND expires_at > $2
+		ORDER BY last_activity_at DESC`
+
+	rows, err := db.conn.Query(ctx, query, userID, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get test sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := []models.TestSession{}
+	for rows.Next() {
+		session, err := scanTestSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan test session: %w", err)
+		}
+		sessions = append(sessions, *session)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating test sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+func (db *Postgres) UpdateTestSession(ctx context.Context, session *models.TestSession) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
+	words, err := json.Marshal(session.Words)
+	if err != nil {
+		return fmt.Errorf("failed to encode session words: %w", err)
+	}
+
+	query := `
+		UPDATE test_sessions
+		SET status = $3, words = $4, current_index = $5, result_id = $6,
+		    last_activity_at = $7, expires_at = $8, completed_at = $9, version = version + 1
+		WHERE id = $1 AND version = $2`
+
+	result, err := db.conn.Exec(ctx, query,
+		session.ID, session.Version, session.Status, words, session.CurrentIndex, session.ResultID,
+		session.LastActivityAt, session.ExpiresAt, session.CompletedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update test session: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		var exists int
+		err := db.conn.QueryRow(ctx, `SELECT 1 FROM test_sessions WHERE id = $1`, session.ID).Scan(&exists)
+		if err == pgx.ErrNoRows {
+			return ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to check test session: %w", err)
+		}
+		return ErrConflict
+	}
+
+	session.Version++
+	return nil
+}
+
+func (db *Postgres) DeleteExpiredTestSessions(ctx context.Context, before time.Time) (int, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
+	result, err := db.conn.Exec(ctx, `DELETE FROM test_sessions WHERE expires_at <= $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired test sessions: %w", err)
+	}
+
+	return int(result.RowsAffected()), nil
+}
+
+// ============================================================================
+// Analytics Operations
+// ============================================================================
+
+func (db *Postgres) GetMissedWords(ctx context.Context, userID string, from, to time.Time, limit int) ([]models.MissedWord, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+	// Error types are unnested for misses only, so rows are counted by word result id
+	query := `
+		SELECT w.word,
+		       MAX(tr.completed_at) FILTER (WHERE NOT w.correct) AS last_missed_at,
+		       COALESCE(array_agg(DISTINCT et) FILTER (WHERE et IS NOT NULL), '{}') AS error_types,
+		       COUNT(DISTINCT w.id) FILTER (WHERE NOT w.correct) AS misses,
+		       COUNT(DISTINCT w.id) AS tests
+		FROM test_results tr
+		JOIN word_test_results w ON w.test_result_id = tr.id
+		LEFT JOIN LATERAL unnest(
+			CASE WHEN w.correct THEN '{}'::text[] ELSE COALESCE(w.error_types, '{}') END
+		) AS et ON true
+		WHERE tr.user_id = $1 AND tr.completed_at >= $2 AND tr.completed_at < $3
+		GROUP BY w.word
+		HAVING COUNT(*) FILTER (WHERE NOT w.correct) > 0
+		ORDER BY misses DESC, last_missed_at DESC, w.word
+		LIMIT $4`
+
+	rows, err := db.conn.Query(ctx, query, userID, from, to, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get missed words: %w", err)
+	}
+	defer rows.Close()
+
+	words := []models.MissedWord{}
+	for rows.Next() {
+		var w models.MissedWord
+		if err := rows.Scan(&w.Word, &w.LastMissedAt, &w.ErrorTypes, &w.Misses, &w.Tests); err != nil {
+			return nil, fmt.Errorf("failed to scan missed word: %w", err)
+		}
+		words = append(words, w)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating missed words: %w", err)
+	}
+
+	return words, nil
+}
+
+func (db *Postgres) GetErrorTypeCounts(ctx context.Context, userID string, from, to time.Time, bucket string, errorTypes []string) ([]models.ErrorTypeCount, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+	args := []any{userID, from, to, bucket}
+
+	// The array overlap filter is served by the GIN index on error_types
+	filter := `cardinality(w.error_types) > 0`
+	if len(errorTypes) > 0 {
+		filter = `w.error_types && $5 AND et = ANY($5)`
+		args = append(args, errorTypes)
+	}
+
+	query := `
+		SELECT date_trunc($4, tr.completed_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' AS bucket_start,
+		       et, COUNT(*)
+		FROM test_results tr
+		JOIN word_test_results w ON w.test_result_id = tr.id
+		CROSS JOIN LATERAL unnest(w.error_types) AS et
+		WHERE tr.user_id = $1 AND tr.completed_at >= $2 AND tr.completed_at < $3
+		  AND ` + filter + `
+		GROUP BY bucket_start, et
+		ORDER BY bucket_start, COUNT(*) DESC, et`
+
+	rows, err := db.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get error type counts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := []models.ErrorTypeCount{}
+	for rows.Next() {
+		var c models.ErrorTypeCount
+		if err := rows.Scan(&c.BucketStart, &c.ErrorType, &c.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan error type count: %w", err)
+		}
+		counts = append(counts, c)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating error type counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+func (db *Postgres) GetWordAttemptStats(ctx context.Context, userID string, from, to time.Time, limit int) ([]models.WordAttemptStats, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+	query := `
+		SELECT w.word,
+		       AVG(w.attempts)::float8 AS avg_attempts,
+		       COUNT(*) AS tests,
+		       COUNT(*) FILTER (WHERE w.correct AND w.attempts = 1) AS first_try_correct
+		FROM test_results tr
+		JOIN word_test_results w ON w.test_result_id = tr.id
+		WHERE tr.user_id = $1 AND tr.completed_at >= $2 AND tr.completed_at < $3
+		GROUP BY w.word
+		ORDER BY avg_attempts DESC, tests DESC, w.word
+		LIMIT $4`
+
+	rows, err := db.conn.Query(ctx, query, userID, from, to, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get word attempt stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := []models.WordAttemptStats{}
+	for rows.Next() {
+		var s models.WordAttemptStats
+		if err := rows.Scan(&s.Word, &s.AvgAttempts, &s.Tests, &s.FirstTryCorrect); err != nil {
+			return nil, fmt.Errorf("failed to scan word attempt stats: %w", err)
+		}
+		stats = append(stats, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating word attempt stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+func (db *Postgres) GetRegressedWords(ctx context.Context, userID string, from, to time.Time, minPriorCorrect, limit int) ([]models.RegressedWord, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+	// The window counts first-try correct answers strictly before each answer, so a
+	// miss in range is a regression when enough of them came before it
+	query := `
+		WITH history AS (
+			SELECT w.id, w.word, w.correct, tr.completed_at,
+			       COALESCE(w.error_types, '{}') AS error_types,
+			       COUNT(*) FILTER (WHERE w.correct AND w.attempts = 1) OVER prior AS prior_correct,
+			       MAX(tr.completed_at) FILTER (WHERE w.correct AND w.attempts = 1) OVER prior AS last_mastered_at
+			FROM test_results tr
+			JOIN word_test_results w ON w.test_result_id = tr.id
+			WHERE tr.user_id = $1 AND tr.completed_at < $3
+			WINDOW prior AS (
+				PARTITION BY w.word ORDER BY tr.completed_at, w.id
+				ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING
+			)
+		)
+		SELECT h.word,
+		       MAX(h.last_mastered_at),
+		       MAX(h.completed_at) AS last_missed_at,
+		       COALESCE(array_agg(DISTINCT et) FILTER (WHERE et IS NOT NULL), '{}'),
+		       COUNT(DISTINCT h.id),
+		       MIN(h.prior_correct)
+		FROM history h
+		LEFT JOIN LATERAL unnest(h.error_types) AS et ON true
+		WHERE NOT h.correct AND h.completed_at >= $2 AND h.prior_correct >= $4
+		GROUP BY h.word
+		ORDER BY last_missed_at DESC, h.word
+		LIMIT $5`
+
+	rows, err := db.conn.Query(ctx, query, userID, from, to, minPriorCorrect, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get regressed words: %w", err)
+	}
+	defer rows.Close()
+
+	words := []models.RegressedWord{}
+	for rows.Next() {
+		var w models.RegressedWord
+		err := rows.Scan(&w.Word, &w.LastMasteredAt, &w.LastMissedAt, &w.ErrorTypes, &w.Misses, &w.PriorCorrect)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan regressed word: %w", err)
+		}
+		words = append(words, w)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating regressed words: %w", err)
+	}
+
+	return words, nil
+}
+
+// ============================================================================
+// Practice List Operations
+// ============================================================================
+
+func (db *Postgres) GetPracticeList(ctx context.Context, childID string) (*models.PracticeList, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+	query := `
+		SELECT child_id, family_id, word_set_id, created_by, window_days, max_words,
+		       include_curated, refreshed_at, created_at
+		FROM practice_lists
+		WHERE child_id = $1`
+
+	var list models.PracticeList
+	err := db.conn.QueryRow(ctx, query, childID).Scan(
+		&list.ChildID, &list.FamilyID, &list.WordSetID, &list.CreatedBy, &list.WindowDays, &list.MaxWords,
+		&list.IncludeCurated, &list.RefreshedAt, &list.CreatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get practice list: %w", err)
+	}
+
+	return &list, nil
+}
+
+func (db *Postgres) SavePracticeList(ctx context.Context, list *models.PracticeList) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+	if list.RefreshedAt.IsZero() {
+		list.RefreshedAt = time.Now()
+	}
+
+	query := `
+		INSERT INTO practice_lists (child_id, family_id, word_set_id, created_by, window_days,
+		                            max_words, include_curated, refreshed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (child_id) DO UPDATE
+		SET word_set_id = EXCLUDED.word_set_id,
+		    window_days = EXCLUDED.window_days,
+		    max_words = EXCLUDED.max_words,
+		    include_curated = EXCLUDED.include_curated,
+		    refreshed_at = EXCLUDED.refreshed_at
+		RETURNING created_at`
+
+	err := db.conn.QueryRow(ctx, query,
+		list.ChildID, list.FamilyID, list.WordSetID, list.CreatedBy, list.WindowDays,
+		list.MaxWords, list.IncludeCurated, list.RefreshedAt,
+	).Scan(&list.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save practice list: %w", err)
+	}
+
+	return nil
+}
+
+// ============================================================================
+// Challenge Lock Operations
+// ============================================================================
+
+func (db *Postgres) GetChallengeLock(ctx context.Context, wordSetID, childID string) (*models.ChallengeLock, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+	query := `
+		SELECT word_set_id, child_id, seed, locked_by, locked_at
+		FROM challenge_locks
+		WHERE word_set_id = $1 AND child_id = $2`
+
+	var lock models.ChallengeLock
+	err := db.conn.QueryRow(ctx, query, wordSetID, childID).Scan(
+		&lock.WordSetID, &lock.ChildID, &lock.Seed, &lock.LockedBy, &lock.LockedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get challenge lock: %w", err)
+	}
+
+	return &lock, nil
+}
+
+func (db *Postgres) SaveChallengeLock(ctx context.Context, lock *models.ChallengeLock) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+	if lock.LockedAt.IsZero() {
+		lock.LockedAt = time.Now()
+	}
+
+	query := `
+		INSERT INTO challenge_locks (word_set_id, child_id, seed, locked_by, locked_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (word_set_id, child_id) DO UPDATE
+		SET seed = EXCLUDED.seed,
+		    locked_by = EXCLUDED.locked_by,
+		    locked_at = EXCLUDED.locked_at`
+
+	_, err := db.conn.Exec(ctx, query, lock.WordSetID, lock.ChildID, lock.Seed, lock.LockedBy, lock.LockedAt)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return fmt.Errorf("failed to save challenge lock: %w", ErrMissingReference)
+		}
+		return fmt.Errorf("failed to save challenge lock: %w", err)
+	}
+
+	return nil
+}
+
+func (db *Postgres) DeleteChallengeLock(ctx context.Context, wordSetID, childID string) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+	result, err := db.conn.Exec(ctx, `DELETE FROM challenge_locks WHERE word_set_id = $1 AND child_id = $2`,
+		wordSetID, childID)
+	if err != nil {
+		return fmt.Errorf("failed to delete challenge lock: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -29,6 +29,7 @@ package db
 
 // GetWordSet retrieves a word set with all words and translations.
 // GetWordSets retrieves all word sets for a family.
+// ListWordSets retrieves one page of a family's word sets matching a filter, newest first.
 // GetGlobalWordSets retrieves all global (curated) word sets.
 // CreateWordSet creates a new word set.
 // UpdateWordSet updates an existing word set.
@@ -36,7 +37,10 @@ package db
 
 // GetTestResults retrieves all test results for a user.
 // GetFamilyResults retrieves all test results for a family.
-// SaveTestResult saves a test result.
+// ListTestResults retrieves one page of a user's or family's test results matching a filter, newest first.
+// SaveTestResult saves a test result, returning ErrDuplicate if its idempotency key was already used.
+// GetTestResultByIdempotencyKey retrieves the result a user saved with an idempotency key.
+// GetRecentWordSetResults retrieves a user's latest results for a word set with word details, newest first.
 
 // GetAudioFile retrieves cached audio file metadata.
 // SaveAudioFile saves audio file metadata to cache.
@@ -59,3 +63,51 @@ package db
 // AssignWordSetToUser assigns a word set to a user.
 // UnassignWordSetFromUser removes a word set assignment from a user.
 // GetWordSetAssignments retrieves all users assigned to a word set.
+// SetWordSetAssignmentSchedule sets the due date, practice goal and reminders of an assignment.
+// GetUserAssignments retrieves a child's assignments with their schedules.
+// GetReminderAssignments retrieves assignments with reminders enabled that are not past due.
+// ClaimAssignmentReminder records a reminder unless one was already sent at or after the slot, reporting whether it did.
+
+// CreateFamilyAPIKey stores a new family API key (hash only).
+// GetFamilyAPIKeyByHash looks up a family API key by the hash of its plaintext value.
+// GetFamilyAPIKeys retrieves all API keys for a family.
+// DeleteFamilyAPIKey revokes a family API key.
+// TouchFamilyAPIKey records that a family API key was just used.
+
+// CreateWebhook registers a new family webhook.
+// GetFamilyWebhooks retrieves all webhooks for a family.
+// DeleteWebhook removes a family webhook and its delivery log.
+// GetWebhookDeliveries retrieves the most recent deliveries for a family webhook.
+// EnqueueWebhookDeliveries adds an outbox entry for every active webhook subscribed to the event.
+// ClaimWebhookDeliveries leases due pending deliveries for dispatch.
+// UpdateWebhookDelivery records the outcome of a delivery attempt.
+
+// GetDigestPreferences retrieves a parent's digest settings, defaulting to opted out.
+// UpsertDigestPreferences creates or updates a parent's digest settings.
+// ClaimDueDigestRecipients marks due recipients as sent at claimedAt and returns them with their previous send time.
+// ReleaseDigestClaim restores the previous send time after a failed send, unless the row was claimed again.
+// GetTestResultsInRange retrieves a user's test results completed within [from, to).
+
+// CreateNotifications stores inbox notifications in a single batch.
+// GetNotifications retrieves a user's most recent notifications, optionally unread only.
+// CountUnreadNotifications counts a user's unread notifications.
+// MarkNotificationsRead marks the given notifications (or all when ids is nil) as read.
+// DismissNotifications deletes the given notifications (or all when ids is nil) from the inbox.
+
+// CreateTestSession stores a new server-side test session.
+// GetTestSession retrieves one of a user's test sessions.
+// GetActiveTestSessions retrieves a user's unfinished, unexpired sessions, most recent first.
+// UpdateTestSession saves session progress, failing with ErrConflict if it changed since it was read.
+// DeleteExpiredTestSessions deletes sessions that expired before the cutoff.
+
+// GetMissedWords retrieves the words a user missed most within [from, to).
+// GetErrorTypeCounts counts a user's spelling error types per day, week or month within [from, to).
+// GetWordAttemptStats retrieves per-word attempt averages within [from, to), most attempts first.
+// GetRegressedWords retrieves words missed within [from, to) after earlier first-try correct answers.
+
+// GetPracticeList retrieves a child's practice list settings, or ErrNotFound.
+// SavePracticeList creates or updates a child's practice list settings.
+
+// GetChallengeLock retrieves the challenge seed locked for a child and word set, or ErrNotFound.
+// SaveChallengeLock creates or replaces the challenge seed locked for a child and word set.
+// DeleteChallengeLock removes a challenge lock, or returns ErrNotFound if there is none.
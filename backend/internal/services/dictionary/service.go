@@ -6,17 +6,25 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/starefossen/diktator/backend/internal/metrics"
 	"github.com/starefossen/diktator/backend/internal/models"
 	"github.com/starefossen/diktator/backend/internal/services/cache"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("github.com/starefossen/diktator/backend/internal/services/dictionary")
+
 // Service provides access to the Norwegian dictionary API (ord.uib.no)
 type Service struct {
 	client       *http.Client
@@ -24,6 +32,14 @@ type Service struct {
 	rateLimiter  *rateLimiter
 	baseURL      string
 	cacheEnabled bool
+	log          *slog.Logger
+	lastFailure  atomic.Pointer[upstreamFailure] // Most recent failed upstream request, cleared by a success
+}
+
+// upstreamFailure records a failed upstream request for health checks
+type upstreamFailure struct {
+	at  time.Time
+	err string
 }
 
 // rateLimiter implements a simple token bucket rate limiter
@@ -39,25 +55,39 @@ func newRateLimiter(requestsPerSecond float64) *rateLimiter {
 	}
 }
 
-func (r *rateLimiter) wait() {
+// wait blocks until the next request is allowed, or returns the context's
+// error if it is cancelled first
+func (r *rateLimiter) wait(ctx context.Context) error {
+	_, span := tracer.Start(ctx, "dictionary.rateLimitWait")
+	defer span.End()
+
+	start := time.Now()
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	defer func() { metrics.ObserveDictionaryWait(time.Since(start)) }()
 
-	now := time.Now()
-	elapsed := now.Sub(r.lastRequest)
+	elapsed := time.Since(r.lastRequest)
 	if elapsed < r.minInterval {
-		time.Sleep(r.minInterval - elapsed)
+		timer := time.NewTimer(r.minInterval - elapsed)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
 	}
 	r.lastRequest = time.Now()
+	return nil
 }
 
 // Config holds configuration for the dictionary service
 type Config struct {
-	BaseURL           string  // Base URL for ord.uib.no API
-	RequestsPerSecond float64 // Rate limit for upstream requests
-	CacheSizeBytes    int64   // LRU cache size in bytes
-	CacheEnabled      bool    // Whether to enable caching
-	TimeoutSeconds    int     // HTTP client timeout
+	BaseURL           string       // Base URL for ord.uib.no API
+	RequestsPerSecond float64      // Rate limit for upstream requests
+	CacheSizeBytes    int64        // LRU cache size in bytes
+	CacheEnabled      bool         // Whether to enable caching
+	TimeoutSeconds    int          // HTTP client timeout
+	Logger            *slog.Logger // Defaults to slog.Default()
 }
 
 // DefaultConfig returns default configuration for the dictionary service
@@ -76,6 +106,10 @@ func NewService(config *Config) *Service {
 	if config == nil {
 		config = DefaultConfig()
 	}
+	logger := config.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
 
 	return &Service{
 		client: &http.Client{
@@ -85,6 +119,7 @@ func NewService(config *Config) *Service {
 		baseURL:      config.BaseURL,
 		rateLimiter:  newRateLimiter(config.RequestsPerSecond),
 		cacheEnabled: config.CacheEnabled,
+		log:          logger,
 	}
 }
 
@@ -113,7 +148,9 @@ func (s *Service) ValidateWord(ctx context.Context, word, dictionary string) (*m
 	}
 
 	// Rate limit upstream requests
-	s.rateLimiter.wait()
+	if err := s.rateLimiter.wait(ctx); err != nil {
+		return nil, err
+	}
 
 	// Step 1: Get article IDs for the word using /api/articles?w=word
 	lookupURL := fmt.Sprintf("%s/api/articles?w=%s&dict=%s",
@@ -127,15 +164,15 @@ func (s *Service) ValidateWord(ctx context.Context, word, dictionary string) (*m
 		return nil, fmt.Errorf("failed to create lookup request: %w", err)
 	}
 
-	resp, err := s.client.Do(req)
+	resp, err := s.do(req, "lookup")
 	if err != nil {
-		log.Printf("[Dictionary] Error looking up word '%s': %v", word, err)
+		s.log.ErrorContext(ctx, "Dictionary lookup failed", "word", word, "error", err)
 		return nil, fmt.Errorf("dictionary service unavailable")
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		log.Printf("[Dictionary] Lookup returned status %d for word '%s'", resp.StatusCode, word)
+		s.log.InfoContext(ctx, "Dictionary lookup returned non-OK status", "word", word, "status", resp.StatusCode)
 		return nil, nil // Word not found
 	}
 
@@ -147,14 +184,14 @@ func (s *Service) ValidateWord(ctx context.Context, word, dictionary string) (*m
 	// Parse the lookup response to get article IDs
 	var lookupResp articlesLookupResponse
 	if err := json.Unmarshal(body, &lookupResp); err != nil {
-		log.Printf("[Dictionary] Error parsing lookup response: %v", err)
+		s.log.ErrorContext(ctx, "Failed to parse dictionary lookup response", "error", err)
 		return nil, fmt.Errorf("failed to parse dictionary response")
 	}
 
 	// Get article IDs for the dictionary
 	articleIDs, ok := lookupResp.Articles[dictionary]
 	if !ok || len(articleIDs) == 0 {
-		log.Printf("[Dictionary] No articles found for word '%s' in dict '%s'", word, dictionary)
+		s.log.DebugContext(ctx, "No dictionary articles found", "word", word, "dictionary", dictionary)
 		return nil, nil // Word not found
 	}
 
@@ -166,7 +203,9 @@ func (s *Service) ValidateWord(ctx context.Context, word, dictionary string) (*m
 // Uses the /{dict}/article/{id}.json endpoint
 func (s *Service) fetchArticle(ctx context.Context, articleID int, dictionary, cacheKey string) (*models.DictionaryWord, error) {
 	// Rate limit upstream requests
-	s.rateLimiter.wait()
+	if err := s.rateLimiter.wait(ctx); err != nil {
+		return nil, err
+	}
 
 	// Use the dictionary-specific article endpoint
 	articleURL := fmt.Sprintf("%s/%s/article/%d.json",
@@ -180,15 +219,15 @@ func (s *Service) fetchArticle(ctx context.Context, articleID int, dictionary, c
 		return nil, fmt.Errorf("failed to create article request: %w", err)
 	}
 
-	resp, err := s.client.Do(req)
+	resp, err := s.do(req, "article")
 	if err != nil {
-		log.Printf("[Dictionary] Error fetching article %d: %v", articleID, err)
+		s.log.ErrorContext(ctx, "Dictionary article fetch failed", "article_id", articleID, "error", err)
 		return nil, fmt.Errorf("dictionary service unavailable")
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		log.Printf("[Dictionary] Article fetch returned status %d for ID %d", resp.StatusCode, articleID)
+		s.log.InfoContext(ctx, "Dictionary article fetch returned non-OK status", "article_id", articleID, "status", resp.StatusCode)
 		return nil, nil
 	}
 
@@ -200,7 +239,7 @@ func (s *Service) fetchArticle(ctx context.Context, articleID int, dictionary, c
 	// Parse the article response
 	var articleResp article
 	if err := json.Unmarshal(body, &articleResp); err != nil {
-		log.Printf("[Dictionary] Error parsing article response: %v", err)
+		s.log.ErrorContext(ctx, "Failed to parse dictionary article", "article_id", articleID, "error", err)
 		return nil, fmt.Errorf("failed to parse dictionary article")
 	}
 
@@ -246,7 +285,9 @@ func (s *Service) Suggest(ctx context.Context, query, dictionary string, limit i
 	}
 
 	// Rate limit upstream requests
-	s.rateLimiter.wait()
+	if err := s.rateLimiter.wait(ctx); err != nil {
+		return nil, err
+	}
 
 	suggestURL := fmt.Sprintf("%s/api/suggest?q=%s&dict=%s&n=%d",
 		s.baseURL,
@@ -260,15 +301,15 @@ func (s *Service) Suggest(ctx context.Context, query, dictionary string, limit i
 		return nil, fmt.Errorf("failed to create suggest request: %w", err)
 	}
 
-	resp, err := s.client.Do(req)
+	resp, err := s.do(req, "suggest")
 	if err != nil {
-		log.Printf("[Dictionary] Error getting suggestions for '%s': %v", query, err)
+		s.log.ErrorContext(ctx, "Dictionary suggest failed", "query", query, "error", err)
 		return nil, fmt.Errorf("dictionary service unavailable")
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		log.Printf("[Dictionary] Suggest returned status %d for query '%s'", resp.StatusCode, query)
+		s.log.InfoContext(ctx, "Dictionary suggest returned non-OK status", "query", query, "status", resp.StatusCode)
 		return []models.DictionarySuggestion{}, nil
 	}
 
@@ -279,7 +320,7 @@ func (s *Service) Suggest(ctx context.Context, query, dictionary string, limit i
 
 	var suggestResp suggestResponse
 	if err := json.Unmarshal(body, &suggestResp); err != nil {
-		log.Printf("[Dictionary] Error parsing suggest response: %v", err)
+		s.log.ErrorContext(ctx, "Failed to parse dictionary suggest response", "error", err)
 		return nil, fmt.Errorf("failed to parse dictionary response")
 	}
 
@@ -341,6 +382,50 @@ func (s *Service) Suggest(ctx context.Context, query, dictionary string, limit i
 	return suggestions, nil
 }
 
+// do sends a request to the upstream API and records its latency
+func (s *Service) do(req *http.Request, endpoint string) (*http.Response, error) {
+	ctx, span := tracer.Start(req.Context(), "dictionary."+endpoint,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(req.Method),
+			semconv.ServerAddress(req.URL.Host),
+			semconv.URLPath(req.URL.Path),
+		),
+	)
+	defer span.End()
+
+	start := time.Now()
+	resp, err := s.client.Do(req.WithContext(ctx))
+	status := 0
+	if err == nil {
+		status = resp.StatusCode
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+	} else {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	metrics.ObserveDictionaryRequest(endpoint, status, time.Since(start))
+
+	switch {
+	case err != nil && ctx.Err() == nil: // Cancelled callers say nothing about the upstream
+		s.lastFailure.Store(&upstreamFailure{at: time.Now(), err: err.Error()})
+	case err == nil && status >= http.StatusInternalServerError:
+		s.lastFailure.Store(&upstreamFailure{at: time.Now(), err: fmt.Sprintf("status %d", status)})
+	case err == nil:
+		s.lastFailure.Store(nil)
+	}
+	return resp, err
+}
+
+// CheckHealth reports the outcome of the most recent upstream request. It does
+// not call the upstream API, so probes never use up its rate limit.
+func (s *Service) CheckHealth(context.Context) error {
+	if f := s.lastFailure.Load(); f != nil {
+		return fmt.Errorf("last upstream request failed %s ago: %s", time.Since(f.at).Round(time.Second), f.err)
+	}
+	return nil
+}
+
 // parseArticle extracts a simplified DictionaryWord from the complex article structure
 func (s *Service) parseArticle(art article) *models.DictionaryWord {
 	result := &models.DictionaryWord{
@@ -447,6 +532,11 @@ func (s *Service) Close() error {
 	return nil
 }
 
+// Cache returns the cache of validated words and suggestions
+func (s *Service) Cache() *cache.LRUCache {
+	return s.cache
+}
+
 // CacheStats returns cache statistics
 func (s *Service) CacheStats() (items int, bytes int64, maxBytes int64) {
 	return s.cache.Stats()
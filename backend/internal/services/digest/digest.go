@@ -0,0 +1,304 @@
+// Package digest composes and sends the weekly parent email digest.
+//
+// For each opted-in parent the digest summarises every child in the family over
+// the last period: tests completed, time spent, accuracy compared with the
+// previous period, XP gained and the words the child struggled with.
+package digest
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services/mail"
+)
+
+// Repository defines the database operations needed by the digest service
+type Repository interface {
+	ClaimDueDigestRecipients(ctx context.Context, sentBefore, claimedAt time.Time) ([]models.DigestRecipient, error)
+	ReleaseDigestClaim(ctx context.Context, userID string, claimedAt time.Time, previous *time.Time) error
+	GetFamilyChildren(ctx context.Context, familyID string) ([]models.ChildAccount, error)
+	GetTestResultsInRange(ctx context.Context, userID string, from, to time.Time) ([]models.TestResult, error)
+}
+
+// Config holds digest configuration
+type Config struct {
+	AppURL            string        // Link included in the email footer
+	Period            time.Duration // Length of the summarised period and minimum time between digests
+	PollInterval      time.Duration // How often the scheduler looks for due recipients
+	StruggleWordLimit int           // Maximum struggle words listed per child
+	TrendThreshold    float64       // Accuracy change (percentage points) reported as up/down
+	Logger            *slog.Logger  // Defaults to slog.Default()
+}
+
+// DefaultConfig returns sensible default configuration
+func DefaultConfig() Config {
+	return Config{
+		AppURL:            "https://www.diktator.fn.flaatten.org",
+		Period:            7 * 24 * time.Hour,
+		PollInterval:      time.Hour,
+		StruggleWordLimit: 5,
+		TrendThreshold:    5,
+	}
+}
+
+// Trend describes how a child's accuracy changed since the previous period
+type Trend string
+
+const (
+	TrendUp   Trend = "up"
+	TrendDown Trend = "down"
+	TrendFlat Trend = "flat"
+	TrendNew  Trend = "new" // No tests in the previous period to compare with
+)
+
+// StruggleWord is a word the child missed on the first attempt during the period
+type StruggleWord struct {
+	Word   string
+	Misses int
+}
+
+// ChildSummary is one child's activity during the digest period
+type ChildSummary struct {
+	Name             string
+	Trend            Trend
+	StruggleWords    []StruggleWord
+	TestsCompleted   int
+	TimeSpent        int // Seconds
+	WordsPracticed   int
+	XPGained         int
+	Level            int
+	Accuracy         float64 // Average score this period (0-100)
+	PreviousAccuracy float64 // Average score the previous period (0 when Trend is new)
+}
+
+// Digest is the content of one parent's weekly email
+type Digest struct {
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+	ParentName  string
+	AppURL      string
+	Children    []ChildSummary
+}
+
+// HasActivity reports whether any child completed a test during the period
+func (d *Digest) HasActivity() bool {
+	for _, c := range d.Children {
+		if c.TestsCompleted > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Service composes and sends weekly digests
+type Service struct {
+	repo   Repository
+	mailer mail.Mailer
+	now    func() time.Time
+	cfg    Config
+	log    *slog.Logger
+}
+
+// NewService creates a new digest service
+func NewService(repo Repository, mailer mail.Mailer, cfg Config) *Service {
+	return &Service{
+		repo:   repo,
+		mailer: mailer,
+		now:    time.Now,
+		cfg:    cfg,
+		log:    cmp.Or(cfg.Logger, slog.Default()),
+	}
+}
+
+// Compose builds the digest for a recipient covering the period ending at end
+func (s *Service) Compose(ctx context.Context, recipient models.DigestRecipient, end time.Time) (*Digest, error) {
+	start := end.Add(-s.cfg.Period)
+	previousStart := start.Add(-s.cfg.Period)
+
+	children, err := s.repo.GetFamilyChildren(ctx, recipient.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := &Digest{
+		PeriodStart: start,
+		PeriodEnd:   end,
+		ParentName:  recipient.DisplayName,
+		AppURL:      s.cfg.AppURL,
+	}
+
+	for _, child := range children {
+		if !child.IsActive {
+			continue
+		}
+
+		current, err := s.repo.GetTestResultsInRange(ctx, child.ID, start, end)
+		if err != nil {
+			return nil, err
+		}
+		previous, err := s.repo.GetTestResultsInRange(ctx, child.ID, previousStart, start)
+		if err != nil {
+			return nil, err
+		}
+
+		digest.Children = append(digest.Children, summarize(child, current, previous, s.cfg))
+	}
+
+	return digest, nil
+}
+
+// summarize aggregates a child's results for the current and previous periods
+func summarize(child models.ChildAccount, current, previous []models.TestResult, cfg Config) ChildSummary {
+	summary := ChildSummary{
+		Name:           child.DisplayName,
+		Level:          child.Level,
+		TestsCompleted: len(current),
+		Accuracy:       averageScore(current),
+	}
+
+	misses := map[string]int{}
+	practiced := map[string]bool{}
+	for _, r := range current {
+		summary.TimeSpent += r.TimeSpent
+		summary.XPGained += r.XPAwarded
+		for _, w := range r.Words {
+			key := strings.ToLower(w.Word)
+			practiced[key] = true
+			if !w.Correct || w.Attempts > 1 {
+				misses[key]++
+			}
+		}
+	}
+	summary.WordsPracticed = len(practiced)
+
+	for word, n := range misses {
+		summary.StruggleWords = append(summary.StruggleWords, StruggleWord{Word: word, Misses: n})
+	}
+	sort.Slice(summary.StruggleWords, func(i, j int) bool {
+		a, b := summary.StruggleWords[i], summary.StruggleWords[j]
+		if a.Misses != b.Misses {
+			return a.Misses > b.Misses
+		}
+		return a.Word < b.Word
+	})
+	if len(summary.StruggleWords) > cfg.StruggleWordLimit {
+		summary.StruggleWords = summary.StruggleWords[:cfg.StruggleWordLimit]
+	}
+
+	switch {
+	case len(current) == 0 || len(previous) == 0:
+		summary.Trend = TrendNew
+	default:
+		summary.PreviousAccuracy = averageScore(previous)
+		delta := summary.Accuracy - summary.PreviousAccuracy
+		switch {
+		case delta >= cfg.TrendThreshold:
+			summary.Trend = TrendUp
+		case delta <= -cfg.TrendThreshold:
+			summary.Trend = TrendDown
+		default:
+			summary.Trend = TrendFlat
+		}
+	}
+
+	return summary
+}
+
+func averageScore(results []models.TestResult) float64 {
+	if len(results) == 0 {
+		return 0
+	}
+	var total float64
+	for _, r := range results {
+		total += r.Score
+	}
+	return total / float64(len(results))
+}
+
+// SendDue composes and sends a digest to every recipient whose last digest is older than one period.
+// It returns the number of digests sent. Failures for one recipient do not stop the others.
+//
+// Recipients are claimed by marking them sent before anything is mailed, so schedulers on
+// other replicas never send the same digest twice. A failed send releases the claim so the
+// digest is retried on the next poll.
+func (s *Service) SendDue(ctx context.Context) (int, error) {
+	now := s.now()
+	recipients, err := s.repo.ClaimDueDigestRecipients(ctx, now.Add(-s.cfg.Period), now)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for i, recipient := range recipients {
+		if ctx.Err() != nil {
+			s.release(context.WithoutCancel(ctx), recipients[i:], now)
+			return sent, ctx.Err()
+		}
+		if err := s.send(ctx, recipient, now); err != nil {
+			s.log.ErrorContext(ctx, "Failed to send digest", "user_id", recipient.UserID, "error", err)
+			s.release(ctx, recipients[i:i+1], now)
+			continue
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+func (s *Service) send(ctx context.Context, recipient models.DigestRecipient, now time.Time) error {
+	digest, err := s.Compose(ctx, recipient, now)
+	if err != nil {
+		return fmt.Errorf("failed to compose digest: %w", err)
+	}
+
+	// Parents without children stay claimed too so they are not re-checked every poll
+	if len(digest.Children) == 0 {
+		return nil
+	}
+
+	msg, err := Render(digest, recipient.Language)
+	if err != nil {
+		return fmt.Errorf("failed to render digest: %w", err)
+	}
+	msg.To = []string{recipient.Email}
+
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		return fmt.Errorf("failed to send digest: %w", err)
+	}
+	return nil
+}
+
+// release returns claimed but unsent recipients to their previous send time
+func (s *Service) release(ctx context.Context, recipients []models.DigestRecipient, claimedAt time.Time) {
+	for _, r := range recipients {
+		if err := s.repo.ReleaseDigestClaim(ctx, r.UserID, claimedAt, r.LastSentAt); err != nil {
+			s.log.ErrorContext(ctx, "Failed to release digest claim", "user_id", r.UserID, "error", err)
+		}
+	}
+}
+
+// Run sends due digests periodically until the context is cancelled
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := s.SendDue(ctx); err != nil {
+			s.log.ErrorContext(ctx, "Failed to send digests", "error", err)
+		} else if n > 0 {
+			s.log.InfoContext(ctx, "Sent weekly digests", "count", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
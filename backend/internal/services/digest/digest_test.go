@@ -0,0 +1,226 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services/mail"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct {
+	sent       map[string]time.Time
+	children   map[string][]models.ChildAccount
+	results    map[string][]models.TestResult
+	recipients []models.DigestRecipient
+}
+
+func (f *fakeRepo) ClaimDueDigestRecipients(_ context.Context, sentBefore, claimedAt time.Time) ([]models.DigestRecipient, error) {
+	var due []models.DigestRecipient
+	for _, r := range f.recipients {
+		if last, ok := f.sent[r.UserID]; !ok || last.Before(sentBefore) {
+			if ok {
+				r.LastSentAt = &last
+			}
+			due = append(due, r)
+			f.sent[r.UserID] = claimedAt
+		}
+	}
+	return due, nil
+}
+
+func (f *fakeRepo) ReleaseDigestClaim(_ context.Context, userID string, claimedAt time.Time, previous *time.Time) error {
+	if f.sent[userID].Equal(claimedAt) {
+		if previous == nil {
+			delete(f.sent, userID)
+		} else {
+			f.sent[userID] = *previous
+		}
+	}
+	return nil
+}
+
+func (f *fakeRepo) GetFamilyChildren(_ context.Context, familyID string) ([]models.ChildAccount, error) {
+	return f.children[familyID], nil
+}
+
+func (f *fakeRepo) GetTestResultsInRange(_ context.Context, userID string, from, to time.Time) ([]models.TestResult, error) {
+	var out []models.TestResult
+	for _, r := range f.results[userID] {
+		if !r.CompletedAt.Before(from) && r.CompletedAt.Before(to) {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+type recordingMailer struct {
+	err  error
+	sent []*mail.Message
+}
+
+func (m *recordingMailer) Send(_ context.Context, msg *mail.Message) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+var digestNow = time.Date(2026, 3, 16, 7, 0, 0, 0, time.UTC)
+
+func word(w string, correct bool, attempts int) models.WordTestResult {
+	return models.WordTestResult{Word: w, Correct: correct, Attempts: attempts}
+}
+
+func newFixture() *fakeRepo {
+	return &fakeRepo{
+		sent: map[string]time.Time{},
+		recipients: []models.DigestRecipient{
+			{UserID: "parent-1", Email: "parent@example.com", DisplayName: "Kari", FamilyID: "family-1", Language: "no"},
+		},
+		children: map[string][]models.ChildAccount{
+			"family-1": {
+				{ID: "child-1", DisplayName: "Åse", IsActive: true, Level: 3},
+				{ID: "child-2", DisplayName: "Per", IsActive: true, Level: 1},
+				{ID: "child-3", DisplayName: "Inactive", IsActive: false},
+			},
+		},
+		results: map[string][]models.TestResult{
+			"child-1": {
+				// Previous week
+				{CompletedAt: digestNow.AddDate(0, 0, -10), Score: 60},
+				// This week
+				{
+					CompletedAt: digestNow.AddDate(0, 0, -3), Score: 80, TimeSpent: 100, XPAwarded: 20,
+					Words: []models.WordTestResult{word("skjære", false, 3), word("hjem", true, 2), word("bil", true, 1)},
+				},
+				{
+					CompletedAt: digestNow.AddDate(0, 0, -1), Score: 90, TimeSpent: 130, XPAwarded: 25,
+					Words: []models.WordTestResult{word("Skjære", true, 2), word("bil", true, 1)},
+				},
+			},
+		},
+	}
+}
+
+func TestCompose(t *testing.T) {
+	svc := NewService(newFixture(), &recordingMailer{}, DefaultConfig())
+
+	d, err := svc.Compose(t.Context(), newFixture().recipients[0], digestNow)
+	require.NoError(t, err)
+	require.Len(t, d.Children, 2, "inactive children are skipped")
+	assert.True(t, d.HasActivity())
+
+	ase := d.Children[0]
+	assert.Equal(t, 2, ase.TestsCompleted)
+	assert.Equal(t, 230, ase.TimeSpent)
+	assert.Equal(t, 45, ase.XPGained)
+	assert.Equal(t, 3, ase.WordsPracticed)
+	assert.InDelta(t, 85, ase.Accuracy, 0.001)
+	assert.Equal(t, TrendUp, ase.Trend)
+	assert.InDelta(t, 60, ase.PreviousAccuracy, 0.001)
+	assert.Equal(t, []StruggleWord{{Word: "skjære", Misses: 2}, {Word: "hjem", Misses: 1}}, ase.StruggleWords)
+
+	per := d.Children[1]
+	assert.Equal(t, 0, per.TestsCompleted)
+	assert.Equal(t, TrendNew, per.Trend)
+}
+
+func TestSummarize_TrendAndLimit(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.StruggleWordLimit = 1
+
+	current := []models.TestResult{{Score: 70, Words: []models.WordTestResult{word("a", false, 1), word("b", false, 1)}}}
+	previous := []models.TestResult{{Score: 80}}
+
+	s := summarize(models.ChildAccount{DisplayName: "X"}, current, previous, cfg)
+	assert.Equal(t, TrendDown, s.Trend)
+	assert.Len(t, s.StruggleWords, 1)
+
+	s = summarize(models.ChildAccount{}, []models.TestResult{{Score: 82}}, previous, cfg)
+	assert.Equal(t, TrendFlat, s.Trend)
+}
+
+func TestRender(t *testing.T) {
+	svc := NewService(newFixture(), &recordingMailer{}, DefaultConfig())
+	d, err := svc.Compose(t.Context(), newFixture().recipients[0], digestNow)
+	require.NoError(t, err)
+
+	no, err := Render(d, "no")
+	require.NoError(t, err)
+	assert.Contains(t, no.Subject, "ukesoppsummering")
+	assert.Contains(t, no.Text, "Hei Kari!")
+	assert.Contains(t, no.Text, "Treffsikkerhet: 85 % (opp fra 60 %)")
+	assert.Contains(t, no.Text, "Tid brukt: 4 min")
+	assert.Contains(t, no.Text, "skjære (2x)")
+	assert.Contains(t, no.Text, "Ingen prøver denne uken")
+	assert.Contains(t, no.HTML, "<h2 style=\"font-size:17px;margin:24px 0 8px;\">Åse</h2>")
+	assert.Contains(t, no.HTML, "XP opptjent")
+
+	en, err := Render(d, "en")
+	require.NoError(t, err)
+	assert.Contains(t, en.Subject, "weekly summary")
+	assert.Contains(t, en.Text, "Accuracy: 85% (up from 60%)")
+	assert.Contains(t, en.Text, "XP earned: 45 (level 3)")
+	assert.Contains(t, en.HTML, "Words to practice more")
+
+	fallback, err := Render(d, "de")
+	require.NoError(t, err)
+	assert.Equal(t, no.Subject, fallback.Subject)
+}
+
+func TestRender_EscapesHTML(t *testing.T) {
+	d := &Digest{ParentName: "<script>x</script>", Children: []ChildSummary{{Name: "<b>kid</b>"}}}
+
+	msg, err := Render(d, "en")
+	require.NoError(t, err)
+	assert.NotContains(t, msg.HTML, "<script>")
+	assert.NotContains(t, msg.HTML, "<b>kid</b>")
+}
+
+func TestSendDue(t *testing.T) {
+	repo := newFixture()
+	mailer := &recordingMailer{}
+	svc := NewService(repo, mailer, DefaultConfig())
+	svc.now = func() time.Time { return digestNow }
+
+	n, err := svc.SendDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	require.Len(t, mailer.sent, 1)
+	assert.Equal(t, []string{"parent@example.com"}, mailer.sent[0].To)
+	assert.Equal(t, digestNow, repo.sent["parent-1"])
+
+	// Not due again within the same week
+	svc.now = func() time.Time { return digestNow.Add(24 * time.Hour) }
+	n, err = svc.SendDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	svc.now = func() time.Time { return digestNow.AddDate(0, 0, 7).Add(time.Minute) }
+	n, err = svc.SendDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+}
+
+func TestSendDue_MailerFailureIsRetried(t *testing.T) {
+	repo := newFixture()
+	mailer := &recordingMailer{err: errors.New("smtp down")}
+	svc := NewService(repo, mailer, DefaultConfig())
+	svc.now = func() time.Time { return digestNow }
+
+	n, err := svc.SendDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+	assert.NotContains(t, repo.sent, "parent-1", "failed digests are not marked sent")
+
+	mailer.err = nil
+	n, err = svc.SendDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+}
@@ -0,0 +1,75 @@
+package digest
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"math"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services/mail"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+// Languages lists the supported digest languages
+var Languages = []string{models.DigestLanguageNorwegian, models.DigestLanguageEnglish}
+
+var templateFuncs = map[string]any{
+	// minutes rounds seconds up to whole minutes so short sessions don't show as 0
+	"minutes": func(seconds int) int {
+		return int(math.Ceil(float64(seconds) / 60))
+	},
+	"pct": func(score float64) string {
+		return fmt.Sprintf("%.0f", score)
+	},
+	"date": func(t time.Time, layout string) string {
+		return t.Format(layout)
+	},
+}
+
+var (
+	textTemplates = map[string]*texttemplate.Template{}
+	htmlTemplates = map[string]*htmltemplate.Template{}
+)
+
+func init() {
+	for _, lang := range Languages {
+		textTemplates[lang] = texttemplate.Must(texttemplate.New("digest."+lang+".txt.tmpl").
+			Funcs(templateFuncs).ParseFS(templateFS, "templates/digest."+lang+".txt.tmpl"))
+		htmlTemplates[lang] = htmltemplate.Must(htmltemplate.New("digest."+lang+".html.tmpl").
+			Funcs(templateFuncs).ParseFS(templateFS, "templates/digest."+lang+".html.tmpl"))
+	}
+}
+
+// Render renders the digest as an email message in the given language ("no" or "en").
+// Unknown languages fall back to Norwegian. The returned message has no recipients set.
+func Render(d *Digest, lang string) (*mail.Message, error) {
+	textTmpl, ok := textTemplates[lang]
+	if !ok {
+		lang = models.DigestLanguageNorwegian
+		textTmpl = textTemplates[lang]
+	}
+	htmlTmpl := htmlTemplates[lang]
+
+	var subject, text, html bytes.Buffer
+	if err := textTmpl.ExecuteTemplate(&subject, "subject", d); err != nil {
+		return nil, fmt.Errorf("failed to render subject: %w", err)
+	}
+	if err := textTmpl.Execute(&text, d); err != nil {
+		return nil, fmt.Errorf("failed to render text body: %w", err)
+	}
+	if err := htmlTmpl.Execute(&html, d); err != nil {
+		return nil, fmt.Errorf("failed to render HTML body: %w", err)
+	}
+
+	return &mail.Message{
+		Subject: subject.String(),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
@@ -0,0 +1,132 @@
+// Package events defines family-scoped domain events and a simple in-process bus
+// that fans them out to subscribers such as webhooks and notifications.
+package events
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Type identifies the kind of domain event
+type Type string
+
+// Domain event types. Values are part of the public webhook contract and must not change.
+const (
+	TypeTestCompleted      Type = "test.completed"
+	TypeLevelUp            Type = "xp.level_up"
+	TypeBadgeEarned        Type = "badge.earned" // Reserved for the upcoming badge system
+	TypeWordSetAssigned    Type = "wordset.assigned"
+	TypeInvitationAccepted Type = "invitation.accepted"
+	TypeAssignmentReminder Type = "assignment.reminder"
+)
+
+// AllTypes returns every event type that can be subscribed to
+func AllTypes() []Type {
+	return []Type{
+		TypeTestCompleted,
+		TypeLevelUp,
+		TypeBadgeEarned,
+		TypeWordSetAssigned,
+		TypeInvitationAccepted,
+		TypeAssignmentReminder,
+	}
+}
+
+// IsValidType checks if a string is a known event type
+func IsValidType(t string) bool {
+	for _, known := range AllTypes() {
+		if string(known) == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is a family-scoped domain event
+type Event struct {
+	OccurredAt time.Time      `json:"occurredAt"`
+	Data       map[string]any `json:"data"`
+	ID         string         `json:"id"`
+	Type       Type           `json:"type"`
+	FamilyID   string         `json:"familyId"`
+	UserID     string         `json:"userId,omitempty"` // The user the event is about (e.g. the child who finished a test)
+}
+
+// New creates an event with a fresh ID and timestamp
+func New(eventType Type, familyID, userID string, data map[string]any) Event {
+	if data == nil {
+		data = map[string]any{}
+	}
+	return Event{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		FamilyID:   familyID,
+		UserID:     userID,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	}
+}
+
+// Handler receives published events
+type Handler interface {
+	HandleEvent(ctx context.Context, event Event) error
+}
+
+// HandlerFunc adapts a function to the Handler interface
+type HandlerFunc func(ctx context.Context, event Event) error
+
+// HandleEvent calls f(ctx, event)
+func (f HandlerFunc) HandleEvent(ctx context.Context, event Event) error {
+	return f(ctx, event)
+}
+
+// Publisher publishes domain events
+type Publisher interface {
+	Publish(ctx context.Context, event Event)
+}
+
+// Bus delivers events synchronously to all subscribed handlers.
+// Handler errors are logged and never propagated to the publisher, so a failing
+// subscriber cannot break the request that produced the event.
+type Bus struct {
+	handlers []namedHandler
+	mu       sync.RWMutex
+	log      *slog.Logger
+}
+
+type namedHandler struct {
+	handler Handler
+	name    string
+}
+
+// NewBus creates an empty event bus. A nil logger uses slog.Default().
+func NewBus(logger *slog.Logger) *Bus {
+	return &Bus{log: cmp.Or(logger, slog.Default())}
+}
+
+// Subscribe registers a handler under a name used in log messages
+func (b *Bus) Subscribe(name string, h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, namedHandler{name: name, handler: h})
+}
+
+// Publish delivers the event to every subscriber
+func (b *Bus) Publish(ctx context.Context, event Event) {
+	b.mu.RLock()
+	handlers := make([]namedHandler, len(b.handlers))
+	copy(handlers, b.handlers)
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		if err := h.handler.HandleEvent(ctx, event); err != nil {
+			b.log.ErrorContext(ctx, "Event handler failed",
+				"handler", h.name, "event_type", event.Type, "event_id", event.ID, "error", err)
+		}
+	}
+}
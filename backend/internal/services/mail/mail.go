@@ -0,0 +1,143 @@
+// Package mail provides a pluggable mailer for outgoing email.
+//
+// Messages carry both a plain-text and an HTML body and are sent as
+// multipart/alternative so mail clients can pick the richest part they support.
+package mail
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// ErrInvalidMessage is returned when a message is missing recipients or a body
+var ErrInvalidMessage = errors.New("invalid mail message")
+
+// Message is an email with plain-text and HTML alternatives
+type Message struct {
+	Subject string
+	Text    string
+	HTML    string
+	To      []string
+}
+
+// Mailer sends email messages
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// LogMailer writes messages to the log instead of sending them. Useful for local development.
+// Recipients are redacted by the logger; the body is logged at debug level.
+type LogMailer struct {
+	Logger *slog.Logger // Defaults to slog.Default()
+}
+
+// Send implements Mailer
+func (m LogMailer) Send(ctx context.Context, msg *Message) error {
+	if err := msg.validate(); err != nil {
+		return err
+	}
+	logger := cmp.Or(m.Logger, slog.Default())
+	logger.InfoContext(ctx, "Email not sent (log-only mailer)", "to", strings.Join(msg.To, ", "), "subject", msg.Subject)
+	logger.DebugContext(ctx, "Email body", "subject", msg.Subject, "text", msg.Text)
+	return nil
+}
+
+func (m *Message) validate() error {
+	if m == nil || len(m.To) == 0 {
+		return fmt.Errorf("%w: no recipients", ErrInvalidMessage)
+	}
+	if m.Text == "" && m.HTML == "" {
+		return fmt.Errorf("%w: empty body", ErrInvalidMessage)
+	}
+	for _, addr := range m.To {
+		if _, err := mail.ParseAddress(addr); err != nil {
+			return fmt.Errorf("%w: bad recipient %q", ErrInvalidMessage, addr)
+		}
+	}
+	return nil
+}
+
+// build renders the message as an RFC 5322 document with a multipart/alternative body
+func (m *Message) build(from string, now time.Time) ([]byte, error) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+
+	parts := []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=utf-8", m.Text},
+		{"text/html; charset=utf-8", m.HTML},
+	}
+	for _, p := range parts {
+		if p.content == "" {
+			continue
+		}
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {p.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeQuotedPrintable(w, p.content); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	headers := []struct{ key, value string }{
+		{"From", from},
+		{"To", strings.Join(m.To, ", ")},
+		{"Subject", mime.QEncoding.Encode("utf-8", m.Subject)},
+		{"Date", now.Format(time.RFC1123Z)},
+		{"Message-ID", messageID(from)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "multipart/alternative; boundary=" + mw.Boundary()},
+	}
+	for _, h := range headers {
+		fmt.Fprintf(&buf, "%s: %s\r\n", h.key, h.value)
+	}
+	buf.WriteString("\r\n")
+	buf.Write(body.Bytes())
+
+	return buf.Bytes(), nil
+}
+
+func writeQuotedPrintable(w io.Writer, content string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(content)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+// messageID generates a unique Message-ID using the sender's domain
+func messageID(from string) string {
+	domain := "diktator.local"
+	if addr, err := mail.ParseAddress(from); err == nil {
+		if at := strings.LastIndex(addr.Address, "@"); at >= 0 {
+			domain = addr.Address[at+1:]
+		}
+	}
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(b), domain)
+}
@@ -0,0 +1,137 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// SMTPConfig holds SMTP mailer configuration
+type SMTPConfig struct {
+	Host        string        // SMTP server host
+	Username    string        // Optional AUTH PLAIN username
+	Password    string        // Optional AUTH PLAIN password
+	From        string        // Sender address, e.g. "Diktator <noreply@example.com>"
+	Timeout     time.Duration // Dial and session timeout
+	Port        int           // SMTP server port
+	RequireTLS  bool          // Fail instead of sending in clear text when STARTTLS is unavailable
+	InsecureTLS bool          // Skip certificate verification (development only)
+}
+
+// DefaultSMTPConfig returns sensible default configuration
+func DefaultSMTPConfig() SMTPConfig {
+	return SMTPConfig{
+		Port:    587,
+		From:    "Diktator <noreply@diktator.local>",
+		Timeout: 30 * time.Second,
+	}
+}
+
+// SMTPMailer sends email through an SMTP server
+type SMTPMailer struct {
+	now  func() time.Time
+	from *mail.Address
+	cfg  SMTPConfig
+}
+
+// NewSMTPMailer creates a new SMTP mailer
+func NewSMTPMailer(cfg SMTPConfig) (*SMTPMailer, error) {
+	if cfg.Host == "" {
+		return nil, fmt.Errorf("SMTP host is required")
+	}
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address %q: %w", cfg.From, err)
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = DefaultSMTPConfig().Timeout
+	}
+
+	return &SMTPMailer{now: time.Now, from: from, cfg: cfg}, nil
+}
+
+// Send implements Mailer
+func (m *SMTPMailer) Send(ctx context.Context, msg *Message) error {
+	if err := msg.validate(); err != nil {
+		return err
+	}
+
+	data, err := msg.build(m.from.String(), m.now())
+	if err != nil {
+		return fmt.Errorf("failed to build message: %w", err)
+	}
+
+	addr := net.JoinHostPort(m.cfg.Host, strconv.Itoa(m.cfg.Port))
+	dialer := &net.Dialer{Timeout: m.cfg.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+
+	deadline := time.Now().Add(m.cfg.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, m.cfg.Host)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("failed to start SMTP session: %w", err)
+	}
+	defer client.Close()
+
+	if err := m.session(client, msg, data); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+func (m *SMTPMailer) session(client *smtp.Client, msg *Message, data []byte) error {
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		// #nosec G402 -- InsecureTLS is an explicit opt-in for development servers
+		tlsConfig := &tls.Config{ServerName: m.cfg.Host, InsecureSkipVerify: m.cfg.InsecureTLS, MinVersion: tls.VersionTLS12}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("failed to start TLS: %w", err)
+		}
+	} else if m.cfg.RequireTLS {
+		return fmt.Errorf("SMTP server does not support STARTTLS")
+	}
+
+	if m.cfg.Username != "" {
+		auth := smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(m.from.Address); err != nil {
+		return fmt.Errorf("SMTP MAIL FROM failed: %w", err)
+	}
+	for _, to := range msg.To {
+		addr, _ := mail.ParseAddress(to) // validated in Send
+		if err := client.Rcpt(addr.Address); err != nil {
+			return fmt.Errorf("SMTP RCPT TO %s failed: %w", addr.Address, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("SMTP DATA failed: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		_ = w.Close()
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("SMTP server rejected message: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,209 @@
+package mail
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMTPServer is a minimal SMTP server that records the envelope and data of each message
+type fakeSMTPServer struct {
+	listener   net.Listener
+	rejectRcpt string
+	from       string
+	rcpts      []string
+	data       string
+	mu         sync.Mutex
+	wg         sync.WaitGroup
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := &fakeSMTPServer{listener: l}
+	s.wg.Add(1)
+	go s.serve()
+	t.Cleanup(func() {
+		_ = l.Close()
+		s.wg.Wait()
+	})
+	return s
+}
+
+func (s *fakeSMTPServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSMTPServer) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.handle(conn)
+	}
+}
+
+func (s *fakeSMTPServer) handle(conn net.Conn) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+	reply := func(line string) { _ = tp.PrintfLine("%s", line) }
+
+	reply("220 fake.smtp ESMTP ready")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250-fake.smtp")
+			reply("250 8BITMIME")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			s.mu.Lock()
+			s.from = envelopeAddress(line[len("MAIL FROM:"):])
+			s.mu.Unlock()
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			rcpt := envelopeAddress(line[len("RCPT TO:"):])
+			if rcpt == s.rejectRcpt {
+				reply("550 no such user")
+				continue
+			}
+			s.mu.Lock()
+			s.rcpts = append(s.rcpts, rcpt)
+			s.mu.Unlock()
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 end data with <CR><LF>.<CR><LF>")
+			data, err := io.ReadAll(tp.DotReader())
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.data = string(data)
+			s.mu.Unlock()
+			reply("250 queued")
+		case cmd == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+// envelopeAddress extracts the address from "<addr> [PARAMS]"
+func envelopeAddress(arg string) string {
+	arg = strings.TrimSpace(arg)
+	if end := strings.Index(arg, ">"); strings.HasPrefix(arg, "<") && end > 0 {
+		return arg[1:end]
+	}
+	return arg
+}
+
+func newTestMailer(t *testing.T, srv *fakeSMTPServer) *SMTPMailer {
+	t.Helper()
+	cfg := DefaultSMTPConfig()
+	cfg.Host = "127.0.0.1"
+	cfg.Port = srv.port()
+	cfg.From = "Diktator <noreply@diktator.test>"
+	cfg.Timeout = 5 * time.Second
+
+	m, err := NewSMTPMailer(cfg)
+	require.NoError(t, err)
+	return m
+}
+
+func TestSMTPMailer_SendsMultipartMessage(t *testing.T) {
+	srv := newFakeSMTPServer(t)
+	m := newTestMailer(t, srv)
+
+	err := m.Send(context.Background(), &Message{
+		To:      []string{"Forelder <parent@example.com>"},
+		Subject: "Ukentlig oppsummering for Åse",
+		Text:    "Hei! Åse øvde på 12 ord.",
+		HTML:    "<p>Hei! Åse øvde på <strong>12</strong> ord.</p>",
+	})
+	require.NoError(t, err)
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	assert.Equal(t, "noreply@diktator.test", srv.from)
+	assert.Equal(t, []string{"parent@example.com"}, srv.rcpts)
+
+	parsed, err := mail.ReadMessage(strings.NewReader(srv.data))
+	require.NoError(t, err)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Ukentlig oppsummering for Åse", subject)
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	bodies := map[string]string{}
+	mr := multipart.NewReader(parsed.Body, params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(part) // multipart.Reader decodes quoted-printable
+		require.NoError(t, err)
+		ct, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		bodies[ct] = string(content)
+	}
+
+	assert.Equal(t, "Hei! Åse øvde på 12 ord.", bodies["text/plain"])
+	assert.Equal(t, "<p>Hei! Åse øvde på <strong>12</strong> ord.</p>", bodies["text/html"])
+}
+
+func TestSMTPMailer_ReturnsRecipientRejection(t *testing.T) {
+	srv := newFakeSMTPServer(t)
+	srv.rejectRcpt = "nobody@example.com"
+	m := newTestMailer(t, srv)
+
+	err := m.Send(context.Background(), &Message{
+		To:      []string{"nobody@example.com"},
+		Subject: "Hello",
+		Text:    "Hi",
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "550")
+}
+
+func TestSMTPMailer_RequireTLS(t *testing.T) {
+	srv := newFakeSMTPServer(t)
+	m := newTestMailer(t, srv)
+	m.cfg.RequireTLS = true
+
+	err := m.Send(context.Background(), &Message{To: []string{"parent@example.com"}, Subject: "Hi", Text: "Hi"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "STARTTLS")
+}
+
+func TestMessageValidation(t *testing.T) {
+	m := LogMailer{}
+	assert.ErrorIs(t, m.Send(context.Background(), &Message{Text: "x"}), ErrInvalidMessage)
+	assert.ErrorIs(t, m.Send(context.Background(), &Message{To: []string{"a@b.c"}}), ErrInvalidMessage)
+	assert.ErrorIs(t, m.Send(context.Background(), &Message{To: []string{"not an address"}, Text: "x"}), ErrInvalidMessage)
+	assert.NoError(t, m.Send(context.Background(), &Message{To: []string{"a@b.c"}, Text: "x"}))
+}
@@ -0,0 +1,125 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services/events"
+	"github.com/starefossen/diktator/backend/internal/services/mail"
+)
+
+// EventChannel publishes notifications as domain events, which makes them
+// available to webhook subscribers and the in-app inbox. Kind must be a known events.Type.
+type EventChannel struct {
+	publisher events.Publisher
+}
+
+// NewEventChannel creates a channel that publishes to the event bus
+func NewEventChannel(publisher events.Publisher) *EventChannel {
+	return &EventChannel{publisher: publisher}
+}
+
+// Name implements Channel
+func (c *EventChannel) Name() string { return "events" }
+
+// Notify implements Channel
+func (c *EventChannel) Notify(ctx context.Context, n Notification) error {
+	if !events.IsValidType(n.Kind) {
+		return fmt.Errorf("notification kind %q is not an event type", n.Kind)
+	}
+
+	data := copyParams(n.Params)
+	data["notificationId"] = n.ID
+	data["messageKey"] = n.MessageKey
+
+	c.publisher.Publish(ctx, events.New(events.Type(n.Kind), n.FamilyID, n.UserID, data))
+	return nil
+}
+
+// EmailRepository defines the database operations needed by the email channel
+type EmailRepository interface {
+	memberRepository
+	GetDigestPreferences(ctx context.Context, userID string) (*models.DigestPreferences, error)
+}
+
+// EmailChannel emails notifications to the family's parents. Only parents who
+// opted in to email from Diktator (the weekly digest setting) are contacted, in
+// their chosen language.
+type EmailChannel struct {
+	repo   EmailRepository
+	mailer mail.Mailer
+}
+
+// NewEmailChannel creates a channel that emails parents
+func NewEmailChannel(repo EmailRepository, mailer mail.Mailer) *EmailChannel {
+	return &EmailChannel{repo: repo, mailer: mailer}
+}
+
+// Name implements Channel
+func (c *EmailChannel) Name() string { return "email" }
+
+// Notify implements Channel
+func (c *EmailChannel) Notify(ctx context.Context, n Notification) error {
+	parents, err := familyParents(ctx, c.repo, n.FamilyID, "")
+	if err != nil {
+		return err
+	}
+
+	for _, member := range parents {
+		if member.Email == "" {
+			continue
+		}
+
+		prefs, err := c.repo.GetDigestPreferences(ctx, member.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get email preferences: %w", err)
+		}
+		if !prefs.Enabled {
+			continue
+		}
+
+		subject, body, err := Render(n, prefs.Language)
+		if err != nil {
+			return err
+		}
+
+		if err := c.mailer.Send(ctx, &mail.Message{
+			To:      []string{member.Email},
+			Subject: subject,
+			Text:    body,
+		}); err != nil {
+			return fmt.Errorf("failed to email %s: %w", member.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// memberRepository looks up family members
+type memberRepository interface {
+	GetFamily(ctx context.Context, familyID string) (*models.Family, error)
+	GetUser(ctx context.Context, userID string) (*models.User, error)
+}
+
+// familyParents returns the parents in a family, leaving out excludeUserID
+func familyParents(ctx context.Context, repo memberRepository, familyID, excludeUserID string) ([]*models.User, error) {
+	family, err := repo.GetFamily(ctx, familyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get family: %w", err)
+	}
+
+	var parents []*models.User
+	for _, memberID := range family.Members {
+		if memberID == excludeUserID {
+			continue
+		}
+		member, err := repo.GetUser(ctx, memberID)
+		if err != nil || member.Role != "parent" {
+			continue
+		}
+		parents = append(parents, member)
+	}
+
+	return parents, nil
+}
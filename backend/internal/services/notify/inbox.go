@@ -0,0 +1,186 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services/events"
+)
+
+// InboxRepository defines the database operations needed by the in-app inbox
+type InboxRepository interface {
+	memberRepository
+	GetWordSet(ctx context.Context, id string) (*models.WordSet, error)
+	CreateNotifications(ctx context.Context, notifications []models.Notification) error
+}
+
+// Inbox turns domain events into in-app notifications. It subscribes to the
+// event bus, so anything published there (including reminders sent through
+// EventChannel) reaches the inbox of the right family members:
+//
+//   - test.completed: the family's parents
+//   - xp.level_up: the user who levelled up and the family's parents
+//   - wordset.assigned and assignment.reminder: the assigned child
+//   - invitation.accepted: the family's other parents
+type Inbox struct {
+	repo InboxRepository
+}
+
+// NewInbox creates an inbox subscriber
+func NewInbox(repo InboxRepository) *Inbox {
+	return &Inbox{repo: repo}
+}
+
+// HandleEvent implements events.Handler
+func (i *Inbox) HandleEvent(ctx context.Context, e events.Event) error {
+	var notifications []models.Notification
+	var err error
+
+	switch e.Type {
+	case events.TypeTestCompleted:
+		notifications, err = i.testCompleted(ctx, e)
+	case events.TypeLevelUp:
+		notifications, err = i.levelUp(ctx, e)
+	case events.TypeWordSetAssigned:
+		notifications, err = i.wordSetAssigned(ctx, e)
+	case events.TypeInvitationAccepted:
+		notifications, err = i.memberJoined(ctx, e)
+	case events.TypeAssignmentReminder:
+		notifications = i.reminder(e)
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return i.repo.CreateNotifications(ctx, notifications)
+}
+
+func (i *Inbox) testCompleted(ctx context.Context, e events.Event) ([]models.Notification, error) {
+	params := map[string]any{
+		"resultId":  e.Data["resultId"],
+		"wordSetId": e.Data["wordSetId"],
+		"score":     e.Data["score"],
+		"mode":      e.Data["mode"],
+	}
+	if err := i.addUserName(ctx, params, "childName", e.UserID); err != nil {
+		return nil, err
+	}
+	if err := i.addWordSetName(ctx, params, e.Data["wordSetId"]); err != nil {
+		return nil, err
+	}
+
+	return i.toParents(ctx, e, MessageTestCompleted, params)
+}
+
+func (i *Inbox) levelUp(ctx context.Context, e events.Event) ([]models.Notification, error) {
+	params := map[string]any{
+		"level":       e.Data["level"],
+		"levelName":   e.Data["levelName"],
+		"levelNameNo": e.Data["levelNameNo"],
+	}
+	notifications := []models.Notification{inboxEntry(e, e.UserID, MessageLevelUp, params)}
+
+	parentParams := copyParams(params)
+	if err := i.addUserName(ctx, parentParams, "childName", e.UserID); err != nil {
+		return nil, err
+	}
+	toParents, err := i.toParents(ctx, e, MessageChildLevelUp, parentParams)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(notifications, toParents...), nil
+}
+
+func (i *Inbox) wordSetAssigned(ctx context.Context, e events.Event) ([]models.Notification, error) {
+	params := map[string]any{"wordSetId": e.Data["wordSetId"]}
+	if dueAt, ok := e.Data["dueAt"]; ok {
+		params["dueAt"] = dueAt
+	}
+	if err := i.addWordSetName(ctx, params, e.Data["wordSetId"]); err != nil {
+		return nil, err
+	}
+
+	return []models.Notification{inboxEntry(e, e.UserID, MessageWordSetAssigned, params)}, nil
+}
+
+func (i *Inbox) memberJoined(ctx context.Context, e events.Event) ([]models.Notification, error) {
+	params := map[string]any{"role": e.Data["role"]}
+	if err := i.addUserName(ctx, params, "memberName", e.UserID); err != nil {
+		return nil, err
+	}
+
+	return i.toParents(ctx, e, MessageMemberJoined, params)
+}
+
+func (i *Inbox) reminder(e events.Event) []models.Notification {
+	messageKey, _ := e.Data["messageKey"].(string)
+	if messageKey == "" {
+		messageKey = MessageReminderPractice
+	}
+
+	params := copyParams(e.Data)
+	delete(params, "messageKey")
+	delete(params, "notificationId")
+
+	return []models.Notification{inboxEntry(e, e.UserID, messageKey, params)}
+}
+
+// toParents addresses a notification to every parent in the family except the subject user
+func (i *Inbox) toParents(ctx context.Context, e events.Event, messageKey string, params map[string]any) ([]models.Notification, error) {
+	parents, err := familyParents(ctx, i.repo, e.FamilyID, e.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	notifications := make([]models.Notification, 0, len(parents))
+	for _, parent := range parents {
+		notifications = append(notifications, inboxEntry(e, parent.ID, messageKey, params))
+	}
+	return notifications, nil
+}
+
+func (i *Inbox) addUserName(ctx context.Context, params map[string]any, key, userID string) error {
+	user, err := i.repo.GetUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	params[key] = user.DisplayName
+	return nil
+}
+
+func (i *Inbox) addWordSetName(ctx context.Context, params map[string]any, wordSetID any) error {
+	id, _ := wordSetID.(string)
+	if id == "" {
+		return nil
+	}
+	wordSet, err := i.repo.GetWordSet(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get word set: %w", err)
+	}
+	params["wordSetName"] = wordSet.Name
+	return nil
+}
+
+func inboxEntry(e events.Event, recipientID, messageKey string, params map[string]any) models.Notification {
+	return models.Notification{
+		UserID:        recipientID,
+		FamilyID:      e.FamilyID,
+		SubjectUserID: e.UserID,
+		Kind:          string(e.Type),
+		MessageKey:    messageKey,
+		Params:        params,
+		CreatedAt:     e.OccurredAt,
+	}
+}
+
+func copyParams(params map[string]any) map[string]any {
+	out := make(map[string]any, len(params))
+	for k, v := range params {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,98 @@
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+	"time"
+)
+
+// Languages supported by server-rendered notifications
+const (
+	LanguageNorwegian = "no"
+	LanguageEnglish   = "en"
+)
+
+type message struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+var messageFuncs = template.FuncMap{
+	// date formats a time.Time or RFC 3339 string as a short local date
+	"date": func(v any, lang string) string {
+		var t time.Time
+		switch x := v.(type) {
+		case time.Time:
+			t = x
+		case *time.Time:
+			if x == nil {
+				return ""
+			}
+			t = *x
+		case string:
+			parsed, err := time.Parse(time.RFC3339, x)
+			if err != nil {
+				return x
+			}
+			t = parsed
+		default:
+			return fmt.Sprint(v)
+		}
+		if lang == LanguageEnglish {
+			return t.In(osloLocation()).Format("Jan 2")
+		}
+		return t.In(osloLocation()).Format("2.1.")
+	},
+}
+
+// catalog holds the server-side translations for message keys sent by email
+var catalog = map[string]map[string]message{
+	MessageReminderPractice: {
+		LanguageNorwegian: newMessage(
+			"Tid for diktat!",
+			`Tid for diktat! {{.childName}} har ikke nådd målet for «{{.wordSetName}}» ennå.{{with .dueAt}} Fristen er {{date . "no"}}{{end}}`,
+		),
+		LanguageEnglish: newMessage(
+			"Time for dictation!",
+			`Time for dictation! {{.childName}} hasn't reached the goal for "{{.wordSetName}}" yet.{{with .dueAt}} It is due {{date . "en"}}.{{end}}`,
+		),
+	},
+}
+
+func newMessage(subject, body string) message {
+	return message{
+		subject: template.Must(template.New("subject").Funcs(messageFuncs).Parse(subject)),
+		body:    template.Must(template.New("body").Funcs(messageFuncs).Parse(body)),
+	}
+}
+
+// Render renders a notification's message key in the given language, falling back to Norwegian
+func Render(n Notification, lang string) (subject, body string, err error) {
+	translations, ok := catalog[n.MessageKey]
+	if !ok {
+		return "", "", fmt.Errorf("no translation for message key %q", n.MessageKey)
+	}
+	msg, ok := translations[lang]
+	if !ok {
+		msg = translations[LanguageNorwegian]
+	}
+
+	var s, b bytes.Buffer
+	if err := msg.subject.Execute(&s, n.Params); err != nil {
+		return "", "", fmt.Errorf("failed to render subject: %w", err)
+	}
+	if err := msg.body.Execute(&b, n.Params); err != nil {
+		return "", "", fmt.Errorf("failed to render body: %w", err)
+	}
+
+	return s.String(), b.String(), nil
+}
+
+func osloLocation() *time.Location {
+	loc, err := time.LoadLocation("Europe/Oslo")
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
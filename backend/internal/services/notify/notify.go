@@ -0,0 +1,98 @@
+// Package notify delivers user-facing notifications through pluggable channels.
+//
+// A Notification carries a localized message key and parameters rather than
+// rendered text, so each channel (in-app inbox, email, webhooks) can render it
+// in the recipient's language or pass it on as structured data.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/starefossen/diktator/backend/internal/services/events"
+)
+
+// Notification kinds. Kinds that are also event types are forwarded to the event bus by
+// EventChannel, which feeds webhooks and the in-app inbox.
+const (
+	KindAssignmentReminder = string(events.TypeAssignmentReminder)
+)
+
+// Message keys. The frontend owns the full translations; the keys used by
+// server-rendered channels (email) are also in the catalog in messages.go.
+const (
+	MessageReminderPractice = "notifications.reminder.practice" // "Tid for diktat!"
+	MessageTestCompleted    = "notifications.test.completed"
+	MessageWordSetAssigned  = "notifications.wordset.assigned"
+	MessageLevelUp          = "notifications.xp.levelUp"
+	MessageChildLevelUp     = "notifications.xp.childLevelUp"
+	MessageMemberJoined     = "notifications.invitation.accepted"
+)
+
+// Notification is a user-facing message about something that happened in a family
+type Notification struct {
+	CreatedAt  time.Time      `json:"createdAt"`
+	Params     map[string]any `json:"params,omitempty"`
+	ID         string         `json:"id"`
+	Kind       string         `json:"kind"`
+	MessageKey string         `json:"messageKey"`
+	FamilyID   string         `json:"familyId"`
+	UserID     string         `json:"userId"` // The user the notification is for
+}
+
+// New creates a notification with a fresh ID and timestamp
+func New(kind, messageKey, familyID, userID string, params map[string]any) Notification {
+	if params == nil {
+		params = map[string]any{}
+	}
+	return Notification{
+		ID:         uuid.New().String(),
+		Kind:       kind,
+		MessageKey: messageKey,
+		FamilyID:   familyID,
+		UserID:     userID,
+		CreatedAt:  time.Now().UTC(),
+		Params:     params,
+	}
+}
+
+// Channel delivers notifications to one medium
+type Channel interface {
+	Name() string
+	Notify(ctx context.Context, n Notification) error
+}
+
+// Notifier sends notifications
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// Fanout sends each notification to every channel.
+// A failing channel does not stop the others; all failures are returned joined.
+type Fanout struct {
+	channels []Channel
+}
+
+// NewFanout creates a notifier that delivers to all given channels
+func NewFanout(channels ...Channel) *Fanout {
+	return &Fanout{channels: channels}
+}
+
+// Add registers another channel
+func (f *Fanout) Add(ch Channel) {
+	f.channels = append(f.channels, ch)
+}
+
+// Notify implements Notifier
+func (f *Fanout) Notify(ctx context.Context, n Notification) error {
+	var errs []error
+	for _, ch := range f.channels {
+		if err := ch.Notify(ctx, n); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", ch.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,245 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services/events"
+	"github.com/starefossen/diktator/backend/internal/services/mail"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type funcChannel struct {
+	fn   func(Notification) error
+	name string
+}
+
+func (c funcChannel) Name() string                                   { return c.name }
+func (c funcChannel) Notify(_ context.Context, n Notification) error { return c.fn(n) }
+
+func reminder() Notification {
+	return New(KindAssignmentReminder, MessageReminderPractice, "family-1", "child-1", map[string]any{
+		"childName":   "Åse",
+		"wordSetName": "Uke 12",
+		"dueAt":       "2026-03-20T16:00:00Z",
+	})
+}
+
+func TestFanout_ContinuesAfterFailure(t *testing.T) {
+	var delivered []string
+	f := NewFanout(
+		funcChannel{name: "broken", fn: func(Notification) error { return errors.New("down") }},
+		funcChannel{name: "ok", fn: func(n Notification) error { delivered = append(delivered, n.ID); return nil }},
+	)
+
+	n := reminder()
+	err := f.Notify(context.Background(), n)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "broken: down")
+	assert.Equal(t, []string{n.ID}, delivered)
+}
+
+func TestRender(t *testing.T) {
+	subject, body, err := Render(reminder(), LanguageNorwegian)
+	require.NoError(t, err)
+	assert.Equal(t, "Tid for diktat!", subject)
+	assert.Equal(t, "Tid for diktat! Åse har ikke nådd målet for «Uke 12» ennå. Fristen er 20.3.", body)
+
+	subject, body, err = Render(reminder(), LanguageEnglish)
+	require.NoError(t, err)
+	assert.Equal(t, "Time for dictation!", subject)
+	assert.Contains(t, body, `"Uke 12"`)
+	assert.Contains(t, body, "It is due Mar 20.")
+
+	_, _, err = Render(Notification{MessageKey: "unknown"}, LanguageNorwegian)
+	assert.Error(t, err)
+}
+
+type recordingPublisher struct {
+	events []events.Event
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, e events.Event) {
+	p.events = append(p.events, e)
+}
+
+func TestEventChannel(t *testing.T) {
+	pub := &recordingPublisher{}
+	ch := NewEventChannel(pub)
+
+	n := reminder()
+	require.NoError(t, ch.Notify(context.Background(), n))
+	require.Len(t, pub.events, 1)
+	assert.Equal(t, events.TypeAssignmentReminder, pub.events[0].Type)
+	assert.Equal(t, "family-1", pub.events[0].FamilyID)
+	assert.Equal(t, n.ID, pub.events[0].Data["notificationId"])
+	assert.Equal(t, "Uke 12", pub.events[0].Data["wordSetName"])
+
+	assert.Error(t, ch.Notify(context.Background(), Notification{Kind: "not.an.event"}))
+}
+
+type emailRepo struct {
+	users map[string]*models.User
+	prefs map[string]*models.DigestPreferences
+}
+
+func (r *emailRepo) GetFamily(_ context.Context, familyID string) (*models.Family, error) {
+	members := make([]string, 0, len(r.users))
+	for id := range r.users {
+		members = append(members, id)
+	}
+	return &models.Family{ID: familyID, Members: members}, nil
+}
+
+func (r *emailRepo) GetUser(_ context.Context, userID string) (*models.User, error) {
+	return r.users[userID], nil
+}
+
+func (r *emailRepo) GetDigestPreferences(_ context.Context, userID string) (*models.DigestPreferences, error) {
+	if p, ok := r.prefs[userID]; ok {
+		return p, nil
+	}
+	return &models.DigestPreferences{UserID: userID, Language: LanguageNorwegian}, nil
+}
+
+type recordingMailer struct {
+	sent []*mail.Message
+}
+
+func (m *recordingMailer) Send(_ context.Context, msg *mail.Message) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func TestEmailChannel_OnlyOptedInParents(t *testing.T) {
+	repo := &emailRepo{
+		users: map[string]*models.User{
+			"p1": {ID: "p1", Role: "parent", Email: "optin@example.com"},
+			"p2": {ID: "p2", Role: "parent", Email: "optout@example.com"},
+			"c1": {ID: "c1", Role: "child", Email: "child@example.com"},
+		},
+		prefs: map[string]*models.DigestPreferences{
+			"p1": {UserID: "p1", Enabled: true, Language: LanguageEnglish},
+			"c1": {UserID: "c1", Enabled: true, Language: LanguageNorwegian},
+		},
+	}
+	mailer := &recordingMailer{}
+
+	require.NoError(t, NewEmailChannel(repo, mailer).Notify(context.Background(), reminder()))
+	require.Len(t, mailer.sent, 1)
+	assert.Equal(t, []string{"optin@example.com"}, mailer.sent[0].To)
+	assert.Equal(t, "Time for dictation!", mailer.sent[0].Subject)
+}
+
+type inboxRepo struct {
+	emailRepo
+	wordSets map[string]*models.WordSet
+	created  []models.Notification
+}
+
+func (r *inboxRepo) GetWordSet(_ context.Context, id string) (*models.WordSet, error) {
+	return r.wordSets[id], nil
+}
+
+func (r *inboxRepo) CreateNotifications(_ context.Context, notifications []models.Notification) error {
+	r.created = append(r.created, notifications...)
+	return nil
+}
+
+func newInboxRepo() *inboxRepo {
+	return &inboxRepo{
+		emailRepo: emailRepo{users: map[string]*models.User{
+			"p1": {ID: "p1", Role: "parent", DisplayName: "Mor"},
+			"p2": {ID: "p2", Role: "parent", DisplayName: "Far"},
+			"c1": {ID: "c1", Role: "child", DisplayName: "Åse"},
+		}},
+		wordSets: map[string]*models.WordSet{"ws-1": {ID: "ws-1", Name: "Uke 12"}},
+	}
+}
+
+func recipients(notifications []models.Notification) map[string]string {
+	out := map[string]string{}
+	for _, n := range notifications {
+		out[n.UserID] = n.MessageKey
+	}
+	return out
+}
+
+func TestInbox_Recipients(t *testing.T) {
+	tests := []struct {
+		want  map[string]string
+		event events.Event
+		name  string
+	}{
+		{
+			name:  "test completed goes to parents",
+			event: events.New(events.TypeTestCompleted, "family-1", "c1", map[string]any{"wordSetId": "ws-1", "score": 90.0}),
+			want:  map[string]string{"p1": MessageTestCompleted, "p2": MessageTestCompleted},
+		},
+		{
+			name:  "level up goes to the child and parents",
+			event: events.New(events.TypeLevelUp, "family-1", "c1", map[string]any{"level": 3}),
+			want:  map[string]string{"c1": MessageLevelUp, "p1": MessageChildLevelUp, "p2": MessageChildLevelUp},
+		},
+		{
+			name:  "assignment goes to the child",
+			event: events.New(events.TypeWordSetAssigned, "family-1", "c1", map[string]any{"wordSetId": "ws-1"}),
+			want:  map[string]string{"c1": MessageWordSetAssigned},
+		},
+		{
+			name:  "new parent is not told about joining",
+			event: events.New(events.TypeInvitationAccepted, "family-1", "p2", map[string]any{"role": "parent"}),
+			want:  map[string]string{"p1": MessageMemberJoined},
+		},
+		{
+			name:  "reminder goes to the child",
+			event: events.New(events.TypeAssignmentReminder, "family-1", "c1", map[string]any{"messageKey": MessageReminderPractice, "notificationId": "n-1"}),
+			want:  map[string]string{"c1": MessageReminderPractice},
+		},
+		{
+			name:  "badges are not in the inbox yet",
+			event: events.New(events.TypeBadgeEarned, "family-1", "c1", nil),
+			want:  map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newInboxRepo()
+			require.NoError(t, NewInbox(repo).HandleEvent(context.Background(), tt.event))
+			assert.Equal(t, tt.want, recipients(repo.created))
+			for _, n := range repo.created {
+				assert.Equal(t, "family-1", n.FamilyID)
+				assert.Equal(t, tt.event.UserID, n.SubjectUserID)
+				assert.Equal(t, string(tt.event.Type), n.Kind)
+			}
+		})
+	}
+}
+
+func TestInbox_Params(t *testing.T) {
+	repo := newInboxRepo()
+	inbox := NewInbox(repo)
+
+	require.NoError(t, inbox.HandleEvent(context.Background(),
+		events.New(events.TypeTestCompleted, "family-1", "c1", map[string]any{"wordSetId": "ws-1", "score": 90.0})))
+	require.NotEmpty(t, repo.created)
+	assert.Equal(t, "Åse", repo.created[0].Params["childName"])
+	assert.Equal(t, "Uke 12", repo.created[0].Params["wordSetName"])
+	assert.Equal(t, 90.0, repo.created[0].Params["score"])
+
+	// Reminders reach the inbox through the event bus
+	repo.created = nil
+	bus := events.NewBus(nil)
+	bus.Subscribe("inbox", inbox)
+	require.NoError(t, NewEventChannel(bus).Notify(context.Background(), reminder()))
+	require.Len(t, repo.created, 1)
+	assert.Equal(t, "child-1", repo.created[0].UserID)
+	assert.Equal(t, MessageReminderPractice, repo.created[0].MessageKey)
+	assert.Equal(t, "Uke 12", repo.created[0].Params["wordSetName"])
+	assert.NotContains(t, repo.created[0].Params, "notificationId")
+	assert.NotContains(t, repo.created[0].Params, "messageKey")
+}
@@ -0,0 +1,230 @@
+// Package planner builds adaptive test plans: which words a child should practice
+// from a word set, in which order, and with which mode for each word. It brings
+// together word mastery, the child's age, recent spelling errors and the word
+// set's test configuration so every client runs the same session.
+package planner
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/starefossen/diktator/backend/internal/models"
+)
+
+// Mastery thresholds, matching the frontend's MASTERY_CONFIG
+const (
+	LetterTilesRequired = 2 // Correct letter tile answers before word bank
+	WordBankRequired    = 2 // Correct word bank answers before keyboard
+)
+
+// RecentResultsLimit is how many recent results of a word set are considered
+const RecentResultsLimit = 5
+
+// Reasons explain why a word got its mode and position
+const (
+	ReasonStruggling = "struggling" // Missed recently; scheduled first with extra support
+	ReasonNew        = "new"        // No mastery yet
+	ReasonLearning   = "learning"   // Working through the mode progression
+	ReasonMastered   = "mastered"   // Keyboard unlocked and not missed recently
+	ReasonAgeLimited = "ageLimited" // Mastery allows a harder mode than the child's age
+	ReasonConfigured = "configured" // The word set's test configuration fixes the mode
+)
+
+// Input is everything a plan is built from
+type Input struct {
+	WordSet   *models.WordSet
+	BirthYear *int
+	Mastery   []models.WordMastery
+	Recent    []models.TestResult // Recent results for the word set, newest first
+	UserID    string
+	MaxWords  int // Zero plans every word
+}
+
+type wordHistory struct {
+	errorTypes []string
+	misses     int
+	lastMissed bool
+	seen       bool
+}
+
+type candidate struct {
+	word models.PlannedWord
+	tier int
+}
+
+// Build creates the plan. Words the child struggled with recently come first,
+// then new and in-progress words, then mastered ones; ties keep the word set's
+// order. MaxWords trims from the end, so mastered words are dropped first.
+func Build(in Input, now time.Time) *models.TestPlan {
+	config := map[string]any{}
+	if in.WordSet.TestConfiguration != nil {
+		config = *in.WordSet.TestConfiguration
+	}
+	configuredMode, _ := config["defaultMode"].(string)
+
+	mastery := make(map[string]*models.WordMastery, len(in.Mastery))
+	for i := range in.Mastery {
+		mastery[in.Mastery[i].Word] = &in.Mastery[i]
+	}
+	history := recentHistory(in.Recent)
+	age := Age(in.BirthYear, now)
+
+	candidates := make([]candidate, 0, len(in.WordSet.Words))
+	for _, w := range in.WordSet.Words {
+		h := history[w.Word]
+		planned := models.PlannedWord{
+			Word:         w.Word,
+			ErrorTypes:   h.errorTypes,
+			RecentMisses: h.misses,
+		}
+
+		if isFixedMode(configuredMode) && (!isTranslationMode(configuredMode) || len(w.Translations) > 0) {
+			planned.Mode = configuredMode
+			planned.Reason = ReasonConfigured
+		} else {
+			planned.Mode, planned.Reason = adaptiveMode(mastery[w.Word], h, age)
+			if strings.Contains(strings.TrimSpace(w.Word), " ") && planned.Mode == string(models.TestModeLetterTiles) {
+				// Letter tiles don't work for sentences; word bank is their first step
+				planned.Mode = string(models.TestModeWordBank)
+			}
+		}
+
+		tier := 1
+		if h.misses > 0 {
+			tier = 0
+		} else if planned.Reason == ReasonMastered {
+			tier = 2
+		}
+		candidates = append(candidates, candidate{word: planned, tier: tier})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.tier != b.tier {
+			return a.tier < b.tier
+		}
+		return a.word.RecentMisses > b.word.RecentMisses
+	})
+
+	if in.MaxWords > 0 && len(candidates) > in.MaxWords {
+		candidates = candidates[:in.MaxWords]
+	}
+
+	plan := &models.TestPlan{
+		Age:         age,
+		UserID:      in.UserID,
+		WordSetID:   in.WordSet.ID,
+		Words:       make([]models.PlannedWord, len(candidates)),
+		MaxAttempts: maxAttempts(config),
+	}
+	for i, c := range candidates {
+		plan.Words[i] = c.word
+	}
+	return plan
+}
+
+// Age returns a child's age the way the frontend computes it: the age they
+// turn this year. It is nil when the birth year is unknown.
+func Age(birthYear *int, now time.Time) *int {
+	if birthYear == nil {
+		return nil
+	}
+	age := now.Year() - *birthYear
+	return &age
+}
+
+// adaptiveMode walks the letter tiles → word bank → keyboard progression.
+// A word missed on its last attempt at keyboard level gets missing letters as
+// a scaffold, and modes are capped by the unlock ages. Without a known age only
+// mastery decides.
+func adaptiveMode(m *models.WordMastery, h wordHistory, age *int) (string, string) {
+	reason := ReasonLearning
+	mode := models.TestModeLetterTiles
+	if m != nil {
+		mode = m.GetCurrentChallengeMode(LetterTilesRequired, WordBankRequired)
+	} else {
+		reason = ReasonNew
+	}
+	if mode == models.TestModeKeyboard {
+		reason = ReasonMastered
+		if h.lastMissed {
+			mode = models.TestModeMissingLetters
+		}
+	}
+	if h.misses > 0 {
+		reason = ReasonStruggling
+	}
+
+	if age != nil {
+		if *age < models.KeyboardUnlockAge && (mode == models.TestModeKeyboard || mode == models.TestModeMissingLetters) {
+			mode = models.TestModeWordBank
+			reason = ReasonAgeLimited
+		}
+		if *age < models.WordBankUnlockAge && mode == models.TestModeWordBank {
+			mode = models.TestModeLetterTiles
+			reason = ReasonAgeLimited
+		}
+	}
+
+	return string(mode), reason
+}
+
+// recentHistory summarizes misses and error types per word from recent results
+func recentHistory(results []models.TestResult) map[string]wordHistory {
+	if len(results) > RecentResultsLimit {
+		results = results[:RecentResultsLimit]
+	}
+
+	history := map[string]wordHistory{}
+	for _, r := range results {
+		for _, w := range r.Words {
+			h := history[w.Word]
+			if !w.Correct {
+				h.misses++
+				if !h.seen {
+					h.lastMissed = true
+				}
+			}
+			for _, et := range w.ErrorTypes {
+				if !contains(h.errorTypes, et) {
+					h.errorTypes = append(h.errorTypes, et)
+				}
+			}
+			h.seen = true
+			history[w.Word] = h
+		}
+	}
+	return history
+}
+
+// isFixedMode reports whether a configured default mode replaces the mastery
+// progression. Letter tiles, word bank and keyboard are the progression itself.
+func isFixedMode(mode string) bool {
+	switch models.TestMode(mode) {
+	case "", models.TestModeLetterTiles, models.TestModeWordBank, models.TestModeKeyboard:
+		return false
+	default:
+		return models.IsValidTestMode(mode)
+	}
+}
+
+func isTranslationMode(mode string) bool {
+	return mode == string(models.TestModeTranslation) || mode == string(models.TestModeListeningTranslation)
+}
+
+func maxAttempts(config map[string]any) int {
+	if n, ok := config["maxAttempts"].(float64); ok && n >= 1 {
+		return min(int(n), 5)
+	}
+	return 3 // Frontend default
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package planner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2026, 3, 16, 17, 0, 0, 0, time.UTC)
+
+func wordSet(config map[string]any, words ...string) *models.WordSet {
+	ws := &models.WordSet{ID: "ws-1"}
+	if config != nil {
+		ws.TestConfiguration = &config
+	}
+	for _, w := range words {
+		ws.Words = append(ws.Words, struct {
+			Word         string               `json:"word"`
+			Audio        models.WordAudio     `json:"audio,omitempty"`
+			Definition   string               `json:"definition,omitempty"`
+			Translations []models.Translation `json:"translations,omitempty"`
+		}{Word: w})
+	}
+	return ws
+}
+
+func mastery(word string, tiles, bank int) models.WordMastery {
+	return models.WordMastery{Word: word, LetterTilesCorrect: tiles, WordBankCorrect: bank}
+}
+
+func birthYear(age int) *int {
+	year := now.Year() - age
+	return &year
+}
+
+func modes(plan *models.TestPlan) map[string]string {
+	m := map[string]string{}
+	for _, w := range plan.Words {
+		m[w.Word] = w.Mode
+	}
+	return m
+}
+
+func TestBuild_MasteryProgression(t *testing.T) {
+	plan := Build(Input{
+		WordSet:   wordSet(nil, "hus", "bil", "båt", "katten sover"),
+		BirthYear: birthYear(9),
+		Mastery:   []models.WordMastery{mastery("bil", 2, 0), mastery("båt", 2, 2)},
+	}, now)
+
+	assert.Equal(t, map[string]string{
+		"hus":          "letterTiles",
+		"bil":          "wordBank",
+		"båt":          "keyboard",
+		"katten sover": "wordBank",
+	}, modes(plan))
+	assert.Equal(t, 9, *plan.Age)
+	assert.Equal(t, 3, plan.MaxAttempts)
+
+	// Mastered words go last
+	assert.Equal(t, "båt", plan.Words[3].Word)
+	assert.Equal(t, ReasonMastered, plan.Words[3].Reason)
+	assert.Equal(t, ReasonNew, plan.Words[0].Reason)
+}
+
+func TestBuild_AgeGates(t *testing.T) {
+	in := Input{
+		WordSet: wordSet(nil, "hus", "bil"),
+		Mastery: []models.WordMastery{mastery("hus", 2, 2), mastery("bil", 2, 0)},
+	}
+
+	in.BirthYear = birthYear(5)
+	plan := Build(in, now)
+	assert.Equal(t, map[string]string{"hus": "letterTiles", "bil": "letterTiles"}, modes(plan))
+	assert.Equal(t, ReasonAgeLimited, plan.Words[0].Reason)
+
+	in.BirthYear = birthYear(6)
+	assert.Equal(t, map[string]string{"hus": "wordBank", "bil": "wordBank"}, modes(Build(in, now)))
+
+	in.BirthYear = nil
+	assert.Equal(t, map[string]string{"hus": "keyboard", "bil": "wordBank"}, modes(Build(in, now)), "unknown age uses mastery only")
+}
+
+func TestBuild_RecentErrors(t *testing.T) {
+	recent := []models.TestResult{
+		{Words: []models.WordTestResult{
+			{Word: "skjære", Correct: false, ErrorTypes: []string{"kjSkjSj"}},
+			{Word: "hus", Correct: true},
+		}},
+		{Words: []models.WordTestResult{
+			{Word: "skjære", Correct: false, ErrorTypes: []string{"kjSkjSj", "doubleConsonant"}},
+			{Word: "hus", Correct: false},
+		}},
+	}
+
+	plan := Build(Input{
+		WordSet:   wordSet(nil, "hus", "bil", "skjære"),
+		BirthYear: birthYear(8),
+		Mastery:   []models.WordMastery{mastery("hus", 2, 2), mastery("bil", 2, 2), mastery("skjære", 2, 2)},
+		Recent:    recent,
+		MaxWords:  2,
+	}, now)
+
+	require.Len(t, plan.Words, 2)
+	first, second := plan.Words[0], plan.Words[1]
+	assert.Equal(t, "skjære", first.Word)
+	assert.Equal(t, 2, first.RecentMisses)
+	assert.Equal(t, []string{"kjSkjSj", "doubleConsonant"}, first.ErrorTypes)
+	assert.Equal(t, string(models.TestModeMissingLetters), first.Mode, "missed last time at keyboard level")
+	assert.Equal(t, ReasonStruggling, first.Reason)
+
+	assert.Equal(t, "hus", second.Word)
+	assert.Equal(t, string(models.TestModeKeyboard), second.Mode, "right on the latest attempt")
+}
+
+func TestBuild_ConfiguredMode(t *testing.T) {
+	ws := wordSet(map[string]any{"defaultMode": "translation", "maxAttempts": 9.0}, "hus", "bil")
+	ws.Words[0].Translations = []models.Translation{{Language: "en", Text: "house"}}
+
+	plan := Build(Input{WordSet: ws}, now)
+	assert.Equal(t, map[string]string{"hus": "translation", "bil": "letterTiles"}, modes(plan))
+	assert.Equal(t, 5, plan.MaxAttempts)
+
+	plan = Build(Input{WordSet: wordSet(map[string]any{"defaultMode": "keyboard"}, "hus")}, now)
+	assert.Equal(t, "letterTiles", plan.Words[0].Mode, "progression modes stay adaptive")
+}
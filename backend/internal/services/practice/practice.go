@@ -0,0 +1,471 @@
+// Package practice builds personal practice lists ("øvelister"): family word
+// sets made from the words a child missed recently, optionally padded with
+// curated words that train the same spelling patterns. A list is assigned to the
+// child when a parent creates it and is rebuilt in the background after each
+// test, so words drop off once the child has mastered them.
+package practice
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services/db"
+	"github.com/starefossen/diktator/backend/internal/services/events"
+)
+
+// Limits for practice list settings
+const (
+	MaxWindowDays = 90
+	MaxWords      = 30
+)
+
+// MasteredStreak is how many first-try correct answers in a row take a word off
+// a practice list
+const MasteredStreak = 2
+
+const listLanguage = "no"
+
+// ErrNothingToPractice is returned when a child has no missed words to build a list from
+var ErrNothingToPractice = errors.New("no missed words to practice")
+
+// Repository defines the database operations needed for practice lists
+type Repository interface {
+	GetChild(ctx context.Context, childID string) (*models.ChildAccount, error)
+	GetTestResultsInRange(ctx context.Context, userID string, from, to time.Time) ([]models.TestResult, error)
+	GetWordSet(ctx context.Context, id string) (*models.WordSet, error)
+	GetGlobalWordSets(ctx context.Context) ([]models.WordSet, error)
+	CreateWordSet(ctx context.Context, wordSet *models.WordSet) error
+	UpdateWordSet(ctx context.Context, wordSet *models.WordSet) error
+	AssignWordSetToUser(ctx context.Context, wordSetID, userID, assignedBy string) error
+	GetPracticeList(ctx context.Context, childID string) (*models.PracticeList, error)
+	SavePracticeList(ctx context.Context, list *models.PracticeList) error
+}
+
+// Input is everything a practice list selection is made from
+type Input struct {
+	Results        []models.TestResult // The child's results, in any order
+	Curated        []models.WordSet    // Curated word sets to pad from
+	WindowDays     int
+	MaxWords       int
+	IncludeCurated bool
+}
+
+type wordState struct {
+	lastMissed time.Time
+	wordSetID  string
+	errorTypes []string
+	misses     int
+	streak     int // First-try correct answers since the last miss or retry
+}
+
+// wordEntry is the element type of models.WordSet.Words
+type wordEntry = struct {
+	Word         string               `json:"word"`
+	Audio        models.WordAudio     `json:"audio,omitempty"`
+	Definition   string               `json:"definition,omitempty"`
+	Translations []models.Translation `json:"translations,omitempty"`
+}
+
+// Select picks the words for a practice list. Words missed in the window come
+// first, most misses first, skipping words answered right on the first try
+// MasteredStreak times since. Curated padding fills the remaining room with words
+// from sets whose spelling focus matches the missed words' error types.
+func Select(in Input, now time.Time) []models.PracticeWord {
+	states := history(in.Results, now.AddDate(0, 0, -in.WindowDays))
+
+	words := []models.PracticeWord{}
+	for word, st := range states {
+		if st.misses == 0 || st.streak >= MasteredStreak {
+			continue
+		}
+		lastMissed := st.lastMissed
+		words = append(words, models.PracticeWord{
+			LastMissedAt: &lastMissed,
+			Word:         word,
+			Source:       models.PracticeWordSourceMissed,
+			WordSetID:    st.wordSetID,
+			ErrorTypes:   st.errorTypes,
+			Misses:       st.misses,
+		})
+	}
+	sort.Slice(words, func(i, j int) bool {
+		a, b := words[i], words[j]
+		if a.Misses != b.Misses {
+			return a.Misses > b.Misses
+		}
+		if !a.LastMissedAt.Equal(*b.LastMissedAt) {
+			return a.LastMissedAt.After(*b.LastMissedAt)
+		}
+		return a.Word < b.Word
+	})
+	if len(words) > in.MaxWords {
+		words = words[:in.MaxWords]
+	}
+
+	if in.IncludeCurated && len(words) > 0 {
+		words = pad(words, in, states)
+	}
+	return words
+}
+
+// history replays results oldest first to track each word's misses since the
+// window start and its current first-try streak
+func history(results []models.TestResult, since time.Time) map[string]*wordState {
+	sorted := make([]models.TestResult, len(results))
+	copy(sorted, results)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CompletedAt.Before(sorted[j].CompletedAt)
+	})
+
+	states := map[string]*wordState{}
+	for _, r := range sorted {
+		for _, w := range r.Words {
+			st := states[w.Word]
+			if st == nil {
+				st = &wordState{}
+				states[w.Word] = st
+			}
+			if w.Correct && w.Attempts <= 1 {
+				st.streak++
+				continue
+			}
+			st.streak = 0
+			if w.Correct || r.CompletedAt.Before(since) {
+				continue
+			}
+			st.misses++
+			st.lastMissed = r.CompletedAt
+			st.wordSetID = r.WordSetID
+			for _, et := range w.ErrorTypes {
+				if !contains(st.errorTypes, et) {
+					st.errorTypes = append(st.errorTypes, et)
+				}
+			}
+		}
+	}
+	return states
+}
+
+// pad adds curated words for the missed words' spelling focus categories, the
+// most frequent category first
+func pad(words []models.PracticeWord, in Input, states map[string]*wordState) []models.PracticeWord {
+	curated := make([]models.WordSet, len(in.Curated))
+	copy(curated, in.Curated)
+	sort.SliceStable(curated, func(i, j int) bool { return curated[i].Name < curated[j].Name })
+
+	listed := map[string]bool{}
+	for _, w := range words {
+		listed[w.Word] = true
+	}
+	for _, category := range focusCategories(words) {
+		for _, ws := range curated {
+			if !containsFocus(ws.SpellingFocus, category) {
+				continue
+			}
+			for _, entry := range ws.Words {
+				if len(words) >= in.MaxWords {
+					return words
+				}
+				if st := states[entry.Word]; listed[entry.Word] || (st != nil && st.streak >= MasteredStreak) {
+					continue
+				}
+				listed[entry.Word] = true
+				words = append(words, models.PracticeWord{
+					Word:      entry.Word,
+					Source:    models.PracticeWordSourceCurated,
+					WordSetID: ws.ID,
+					Category:  category,
+				})
+			}
+		}
+	}
+	return words
+}
+
+// focusCategories maps the words' error types to spelling focus categories,
+// most frequent first
+func focusCategories(words []models.PracticeWord) []models.SpellingFocusCategory {
+	counts := map[models.SpellingFocusCategory]int{}
+	for _, w := range words {
+		for _, et := range w.ErrorTypes {
+			for _, category := range models.SpellingFocusForErrorType(et) {
+				counts[category]++
+			}
+		}
+	}
+	categories := make([]models.SpellingFocusCategory, 0, len(counts))
+	for category := range counts {
+		categories = append(categories, category)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		if counts[categories[i]] != counts[categories[j]] {
+			return counts[categories[i]] > counts[categories[j]]
+		}
+		return categories[i] < categories[j]
+	})
+	return categories
+}
+
+// Service creates practice lists and keeps them up to date
+type Service struct {
+	repo    Repository
+	now     func() time.Time
+	log     *slog.Logger
+	pending map[string]bool // Children whose list Run should refresh
+	wake    chan struct{}
+	mu      sync.Mutex
+}
+
+// NewService creates a practice list service. A nil logger uses slog.Default().
+func NewService(repo Repository, logger *slog.Logger) *Service {
+	return &Service{
+		repo:    repo,
+		now:     time.Now,
+		log:     cmp.Or(logger, slog.Default()),
+		pending: map[string]bool{},
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// Generate creates a child's practice list, or rebuilds it with new settings,
+// and assigns it to the child. It returns ErrNothingToPractice and leaves any
+// existing list unchanged when there are no missed words.
+func (s *Service) Generate(ctx context.Context, childID, createdBy string, req models.PracticeListRequest) (*models.PracticeListResponse, error) {
+	child, err := s.repo.GetChild(ctx, childID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get child: %w", err)
+	}
+
+	list, err := s.repo.GetPracticeList(ctx, childID)
+	if errors.Is(err, db.ErrNotFound) {
+		list = &models.PracticeList{ChildID: child.ID, FamilyID: child.FamilyID, CreatedBy: createdBy}
+	} else if err != nil {
+		return nil, err
+	}
+	list.WindowDays = models.PracticeListDefaultWindowDays
+	if req.WindowDays > 0 {
+		list.WindowDays = min(req.WindowDays, MaxWindowDays)
+	}
+	list.MaxWords = models.PracticeListDefaultMaxWords
+	if req.MaxWords > 0 {
+		list.MaxWords = min(req.MaxWords, MaxWords)
+	}
+	list.IncludeCurated = req.IncludeCurated
+
+	resp, err := s.build(ctx, child, list, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.AssignWordSetToUser(ctx, list.WordSetID, child.ID, createdBy); err != nil {
+		return nil, fmt.Errorf("failed to assign practice list: %w", err)
+	}
+	return resp, nil
+}
+
+// Refresh rebuilds a child's practice list with its saved settings. A list can
+// end up empty once every word is mastered. It returns nil for children without
+// a list.
+func (s *Service) Refresh(ctx context.Context, childID string) (*models.PracticeListResponse, error) {
+	list, err := s.repo.GetPracticeList(ctx, childID)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	child, err := s.repo.GetChild(ctx, childID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get child: %w", err)
+	}
+	resp, err := s.build(ctx, child, list, false)
+	if errors.Is(err, db.ErrWordSetNotFound) {
+		// The word set was deleted since the list was read, which removes the list
+		return nil, nil
+	}
+	return resp, err
+}
+
+// HandleEvent implements events.Handler: a completed test queues a refresh of
+// the child's practice list for Run, so finishing a test never waits for it
+func (s *Service) HandleEvent(_ context.Context, e events.Event) error {
+	if e.Type != events.TypeTestCompleted || e.UserID == "" {
+		return nil
+	}
+	s.mu.Lock()
+	s.pending[e.UserID] = true
+	s.mu.Unlock()
+	select {
+	case s.wake <- struct{}{}:
+	default: // A wake-up is already queued
+	}
+	return nil
+}
+
+// Run refreshes queued practice lists until ctx is cancelled. Several tests
+// finished before a refresh starts lead to one refresh per child, and a failed
+// refresh is logged and tried again after the child's next test.
+func (s *Service) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wake:
+		}
+
+		s.mu.Lock()
+		pending := s.pending
+		s.pending = map[string]bool{}
+		s.mu.Unlock()
+
+		for childID := range pending {
+			if ctx.Err() != nil {
+				return
+			}
+			if _, err := s.Refresh(ctx, childID); err != nil {
+				s.log.ErrorContext(ctx, "Failed to refresh practice list", "child_id", childID, "error", err)
+			}
+		}
+	}
+}
+
+// build selects the words and writes the word set and list settings. Word
+// entries keep their audio, definition and translations from the current list
+// or from the set they came from.
+func (s *Service) build(ctx context.Context, child *models.ChildAccount, list *models.PracticeList, requireWords bool) (*models.PracticeListResponse, error) {
+	// Only results in the window can add misses. The end leaves room for
+	// completion times from client clocks that run ahead.
+	now := s.now()
+	results, err := s.repo.GetTestResultsInRange(ctx, child.ID, now.AddDate(0, 0, -list.WindowDays), now.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get test results: %w", err)
+	}
+	var curated []models.WordSet
+	if list.IncludeCurated {
+		if curated, err = s.repo.GetGlobalWordSets(ctx); err != nil {
+			return nil, fmt.Errorf("failed to get curated word sets: %w", err)
+		}
+	}
+
+	words := Select(Input{
+		Results:        results,
+		Curated:        curated,
+		WindowDays:     list.WindowDays,
+		MaxWords:       list.MaxWords,
+		IncludeCurated: list.IncludeCurated,
+	}, now)
+	if requireWords && len(words) == 0 {
+		return nil, ErrNothingToPractice
+	}
+
+	sources := map[string]*models.WordSet{}
+	for i := range curated {
+		sources[curated[i].ID] = &curated[i]
+	}
+
+	var wordSet *models.WordSet
+	if list.WordSetID != "" {
+		if wordSet, err = s.repo.GetWordSet(ctx, list.WordSetID); err != nil {
+			return nil, fmt.Errorf("failed to get practice word set: %w", err)
+		}
+		sources[wordSet.ID] = wordSet
+	}
+
+	entries := make([]wordEntry, 0, len(words))
+	for _, w := range words {
+		entries = append(entries, s.entry(ctx, w, wordSet, sources))
+	}
+
+	if wordSet == nil {
+		familyID := child.FamilyID
+		wordSet = &models.WordSet{
+			ID:        uuid.New().String(),
+			Name:      "Øveliste for " + child.DisplayName,
+			FamilyID:  &familyID,
+			CreatedBy: list.CreatedBy,
+			Language:  listLanguage,
+			CreatedAt: now,
+			UpdatedAt: now,
+			Words:     entries,
+		}
+		if err := s.repo.CreateWordSet(ctx, wordSet); err != nil {
+			return nil, fmt.Errorf("failed to create practice word set: %w", err)
+		}
+		list.WordSetID = wordSet.ID
+	} else {
+		wordSet.Words = entries
+		wordSet.UpdatedAt = now
+		if err := s.repo.UpdateWordSet(ctx, wordSet); err != nil {
+			return nil, fmt.Errorf("failed to update practice word set: %w", err)
+		}
+	}
+
+	list.RefreshedAt = now
+	if err := s.repo.SavePracticeList(ctx, list); err != nil {
+		return nil, err
+	}
+
+	return &models.PracticeListResponse{
+		WordSet:      wordSet,
+		PracticeList: *list,
+		Words:        words,
+	}, nil
+}
+
+// entry finds the stored entry for a word in the current list or its source set,
+// falling back to the bare word
+func (s *Service) entry(ctx context.Context, w models.PracticeWord, current *models.WordSet, sources map[string]*models.WordSet) wordEntry {
+	if current != nil {
+		if e, ok := findEntry(current, w.Word); ok {
+			return e
+		}
+	}
+
+	source, ok := sources[w.WordSetID]
+	if !ok && w.WordSetID != "" {
+		// A set that can't be loaded (e.g. deleted since) just means a bare entry
+		source, _ = s.repo.GetWordSet(ctx, w.WordSetID)
+		sources[w.WordSetID] = source
+	}
+	if source != nil {
+		if e, ok := findEntry(source, w.Word); ok {
+			return e
+		}
+	}
+	return wordEntry{Word: w.Word}
+}
+
+func findEntry(ws *models.WordSet, word string) (wordEntry, bool) {
+	for _, e := range ws.Words {
+		if e.Word == word {
+			return e, true
+		}
+	}
+	return wordEntry{}, false
+}
+
+func containsFocus(focus []models.SpellingFocusCategory, category models.SpellingFocusCategory) bool {
+	for _, f := range focus {
+		if f == category {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
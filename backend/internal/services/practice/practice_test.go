@@ -0,0 +1,259 @@
+package practice
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services/db"
+	"github.com/starefossen/diktator/backend/internal/services/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2026, 3, 16, 17, 0, 0, 0, time.UTC)
+
+func result(daysAgo int, words ...models.WordTestResult) models.TestResult {
+	return models.TestResult{
+		WordSetID:   "ws-family",
+		CompletedAt: now.AddDate(0, 0, -daysAgo),
+		Words:       words,
+	}
+}
+
+func missed(word string, errorTypes ...string) models.WordTestResult {
+	return models.WordTestResult{Word: word, Attempts: 3, ErrorTypes: errorTypes}
+}
+
+func firstTry(word string) models.WordTestResult {
+	return models.WordTestResult{Word: word, Attempts: 1, Correct: true}
+}
+
+func wordSet(id, name string, focus []models.SpellingFocusCategory, words ...string) models.WordSet {
+	ws := models.WordSet{ID: id, Name: name, SpellingFocus: focus}
+	for _, w := range words {
+		ws.Words = append(ws.Words, wordEntry{Word: w})
+	}
+	return ws
+}
+
+func listed(words []models.PracticeWord) []string {
+	out := make([]string, len(words))
+	for i, w := range words {
+		out[i] = w.Word
+	}
+	return out
+}
+
+func TestSelect_MissedWords(t *testing.T) {
+	results := []models.TestResult{
+		result(30, missed("gammel")), // Outside the window
+		result(5, missed("hvit", "silentH"), missed("skjære", "kjSkjSj"), firstTry("hus")),
+		result(3, missed("skjære", "kjSkjSj", "vowelAeE"), missed("båt"), firstTry("hvit")),
+		result(1, firstTry("båt"), firstTry("hvit"), models.WordTestResult{Word: "hus", Attempts: 2, Correct: true}),
+	}
+
+	words := Select(Input{Results: results, WindowDays: 14, MaxWords: 10}, now)
+
+	assert.Equal(t, []string{"skjære", "båt"}, listed(words), "hvit is mastered again after two first-try answers")
+	assert.Equal(t, 2, words[0].Misses)
+	assert.Equal(t, []string{"kjSkjSj", "vowelAeE"}, words[0].ErrorTypes)
+	assert.Equal(t, "ws-family", words[0].WordSetID)
+	assert.Equal(t, models.PracticeWordSourceMissed, words[0].Source)
+	assert.Equal(t, now.AddDate(0, 0, -3), *words[0].LastMissedAt)
+
+	words = Select(Input{Results: results, WindowDays: 14, MaxWords: 1}, now)
+	assert.Equal(t, []string{"skjære"}, listed(words))
+
+	words = Select(Input{Results: results, WindowDays: 60, MaxWords: 10}, now)
+	assert.Contains(t, listed(words), "gammel")
+}
+
+func TestSelect_CuratedPadding(t *testing.T) {
+	results := []models.TestResult{
+		result(2, missed("takk", "doubleConsonant"), missed("hvem", "silentH"), missed("mamma", "doubleConsonant")),
+		result(1, firstTry("ball"), firstTry("ball")),
+	}
+	curated := []models.WordSet{
+		wordSet("ws-silent", "Stumme bokstaver", []models.SpellingFocusCategory{models.SpellingFocusSilentLetter}, "hva", "hvem"),
+		wordSet("ws-double", "Dobbel konsonant", []models.SpellingFocusCategory{models.SpellingFocusDoubleConsonant}, "ball", "katt", "takk", "hopp"),
+	}
+
+	words := Select(Input{Results: results, Curated: curated, WindowDays: 14, MaxWords: 6, IncludeCurated: true}, now)
+
+	assert.Equal(t, []string{"hvem", "mamma", "takk", "katt", "hopp", "hva"}, listed(words),
+		"double consonants are the most common weakness; mastered and listed words are skipped")
+	assert.Equal(t, models.PracticeWordSourceCurated, words[3].Source)
+	assert.Equal(t, models.SpellingFocusDoubleConsonant, words[3].Category)
+	assert.Equal(t, "ws-double", words[3].WordSetID)
+
+	words = Select(Input{Results: nil, Curated: curated, WindowDays: 14, MaxWords: 6, IncludeCurated: true}, now)
+	assert.Empty(t, words, "padding needs missed words to pick a focus")
+}
+
+type fakeRepo struct {
+	wordSets    map[string]*models.WordSet
+	list        *models.PracticeList
+	results     []models.TestResult
+	assignments []string
+	mu          sync.Mutex // Run reads the repo from its own goroutine
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{wordSets: map[string]*models.WordSet{}}
+}
+
+// wordSet returns a stored word set under the lock
+func (f *fakeRepo) wordSet(id string) *models.WordSet {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.wordSets[id]
+}
+
+func (f *fakeRepo) GetChild(_ context.Context, childID string) (*models.ChildAccount, error) {
+	return &models.ChildAccount{ID: childID, FamilyID: "family-1", DisplayName: "Ola"}, nil
+}
+func (f *fakeRepo) GetTestResultsInRange(_ context.Context, _ string, from, to time.Time) ([]models.TestResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var results []models.TestResult
+	for _, r := range f.results {
+		if !r.CompletedAt.Before(from) && r.CompletedAt.Before(to) {
+			results = append(results, r)
+		}
+	}
+	return results, nil
+}
+func (f *fakeRepo) GetWordSet(_ context.Context, id string) (*models.WordSet, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if ws, ok := f.wordSets[id]; ok {
+		copied := *ws
+		return &copied, nil
+	}
+	return nil, db.ErrWordSetNotFound
+}
+func (f *fakeRepo) GetGlobalWordSets(_ context.Context) ([]models.WordSet, error) { return nil, nil }
+func (f *fakeRepo) CreateWordSet(_ context.Context, ws *models.WordSet) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.wordSets[ws.ID] = ws
+	return nil
+}
+func (f *fakeRepo) UpdateWordSet(_ context.Context, ws *models.WordSet) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.wordSets[ws.ID] = ws
+	return nil
+}
+func (f *fakeRepo) AssignWordSetToUser(_ context.Context, wordSetID, userID, _ string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.assignments = append(f.assignments, wordSetID+":"+userID)
+	return nil
+}
+func (f *fakeRepo) GetPracticeList(_ context.Context, childID string) (*models.PracticeList, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.list == nil || f.list.ChildID != childID {
+		return nil, db.ErrNotFound
+	}
+	copied := *f.list
+	return &copied, nil
+}
+func (f *fakeRepo) SavePracticeList(_ context.Context, list *models.PracticeList) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	copied := *list
+	f.list = &copied
+	return nil
+}
+
+func TestService_GenerateAndRefresh(t *testing.T) {
+	repo := newFakeRepo()
+	source := wordSet("ws-family", "Uke 12", nil, "skjære", "båt")
+	source.Words[0].Definition = "å kutte"
+	repo.wordSets[source.ID] = &source
+
+	svc := NewService(repo, nil)
+	svc.now = func() time.Time { return now }
+
+	_, err := svc.Generate(t.Context(), "child-1", "parent-1", models.PracticeListRequest{})
+	assert.ErrorIs(t, err, ErrNothingToPractice)
+	assert.Nil(t, repo.list)
+
+	repo.results = []models.TestResult{result(2, missed("skjære"), missed("båt"))}
+	resp, err := svc.Generate(t.Context(), "child-1", "parent-1", models.PracticeListRequest{WindowDays: 500})
+	require.NoError(t, err)
+
+	assert.Equal(t, MaxWindowDays, resp.PracticeList.WindowDays)
+	assert.Equal(t, models.PracticeListDefaultMaxWords, resp.PracticeList.MaxWords)
+	assert.Equal(t, "Øveliste for Ola", resp.WordSet.Name)
+	require.Len(t, resp.WordSet.Words, 2)
+	assert.Equal(t, "å kutte", resp.WordSet.Words[1].Definition, "entries are copied from the source set")
+	assert.Equal(t, []string{resp.WordSet.ID + ":child-1"}, repo.assignments)
+	listID := resp.WordSet.ID
+
+	// A completed test with two first-try answers masters "båt"
+	repo.results = append(repo.results,
+		result(1, firstTry("båt")),
+		result(0, firstTry("båt")),
+	)
+	_, err = svc.Refresh(t.Context(), "child-1")
+	require.NoError(t, err)
+
+	refreshed := repo.wordSets[listID]
+	require.Len(t, refreshed.Words, 1)
+	assert.Equal(t, "skjære", refreshed.Words[0].Word)
+	assert.Equal(t, "å kutte", refreshed.Words[0].Definition)
+	assert.Len(t, repo.assignments, 1, "refreshing doesn't reassign")
+
+	// Children without a list are ignored
+	resp, err = svc.Refresh(t.Context(), "child-2")
+	require.NoError(t, err)
+	assert.Nil(t, resp)
+}
+
+func TestService_RefreshesInBackground(t *testing.T) {
+	repo := newFakeRepo()
+	svc := NewService(repo, nil)
+	svc.now = func() time.Time { return now }
+	repo.results = []models.TestResult{result(2, missed("skjære"), missed("båt"))}
+	resp, err := svc.Generate(t.Context(), "child-1", "parent-1", models.PracticeListRequest{})
+	require.NoError(t, err)
+	listID := resp.WordSet.ID
+
+	repo.mu.Lock()
+	repo.results = append(repo.results, result(1, firstTry("båt")), result(0, firstTry("båt")))
+	repo.mu.Unlock()
+	require.NoError(t, svc.HandleEvent(t.Context(), events.New(events.TypeTestCompleted, "family-1", "child-1", nil)))
+	require.NoError(t, svc.HandleEvent(t.Context(), events.New(events.TypeLevelUp, "family-1", "child-2", nil)))
+	assert.Len(t, repo.wordSet(listID).Words, 2, "handling the event does not refresh on the caller's goroutine")
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan struct{})
+	go func() {
+		svc.Run(ctx)
+		close(done)
+	}()
+	assert.Eventually(t, func() bool { return len(repo.wordSet(listID).Words) == 1 }, time.Second, 5*time.Millisecond)
+	cancel()
+	<-done
+}
+
+func TestService_RefreshAfterWordSetDeleted(t *testing.T) {
+	repo := newFakeRepo()
+	svc := NewService(repo, nil)
+	svc.now = func() time.Time { return now }
+	repo.results = []models.TestResult{result(2, missed("skjære"))}
+	resp, err := svc.Generate(t.Context(), "child-1", "parent-1", models.PracticeListRequest{})
+	require.NoError(t, err)
+
+	// The list row normally goes with the word set; here it is read just before
+	delete(repo.wordSets, resp.WordSet.ID)
+	resp, err = svc.Refresh(t.Context(), "child-1")
+	require.NoError(t, err)
+	assert.Nil(t, resp)
+}
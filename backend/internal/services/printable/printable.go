@@ -0,0 +1,182 @@
+// Package printable renders word set worksheets and child progress reports as PDF.
+//
+// Documents are generated in pure Go with fpdf (github.com/go-pdf/fpdf). The Go fonts are embedded in the
+// binary so Norwegian letters (æ, ø, å) print without any system fonts, which the
+// distroless runtime image doesn't have.
+package printable
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+
+	"github.com/starefossen/diktator/backend/internal/models"
+)
+
+const (
+	fontFamily = "go"
+	pageMargin = 20.0 // mm
+	lineHeight = 8.0  // mm
+)
+
+// labels holds the fixed texts printed on the documents
+type labels struct {
+	modes          map[string]string
+	dateLayout     string
+	name           string
+	date           string
+	page           string
+	dictation      string
+	missingLetters string
+	answerKey      string
+	sentences      string
+	report         string
+	generated      string
+	summary        string
+	testsTaken     string
+	averageScore   string
+	correctWords   string
+	level          string
+	lastActivity   string
+	recentTests    string
+	mode           string
+	score          string
+	wordSet        string
+	missedWords    string
+	word           string
+	misses         string
+	noTests        string
+}
+
+var languageLabels = map[string]labels{
+	models.DigestLanguageNorwegian: {
+		modes: map[string]string{
+			string(models.TestModeLetterTiles):          "Bygg Ordet",
+			string(models.TestModeWordBank):             "Velg Ord",
+			string(models.TestModeKeyboard):             "Skriv Selv",
+			string(models.TestModeMissingLetters):       "Fyll Inn",
+			string(models.TestModeFlashcard):            "Hurtigblikk",
+			string(models.TestModeLookCoverWrite):       "Huskestaving",
+			string(models.TestModeTranslation):          "Oversett",
+			string(models.TestModeListeningTranslation): "Hør og Oversett",
+		},
+		dateLayout:     "02.01.2006",
+		name:           "Navn",
+		date:           "Dato",
+		page:           "Side %d av {nb}",
+		dictation:      "Diktat",
+		missingLetters: "Fyll inn bokstavene som mangler",
+		answerKey:      "Fasit",
+		sentences:      "Setninger",
+		report:         "Fremdriftsrapport",
+		generated:      "Laget",
+		summary:        "Oppsummering",
+		testsTaken:     "Tester fullført",
+		averageScore:   "Gjennomsnittlig poengsum",
+		correctWords:   "Riktige ord",
+		level:          "Nivå",
+		lastActivity:   "Sist aktiv",
+		recentTests:    "Siste tester",
+		mode:           "Modus",
+		score:          "Poeng",
+		wordSet:        "Ordliste",
+		missedWords:    "Ord å øve mer på",
+		word:           "Ord",
+		misses:         "Feil",
+		noTests:        "Ingen tester fullført ennå.",
+	},
+	models.DigestLanguageEnglish: {
+		modes: map[string]string{
+			string(models.TestModeLetterTiles):          "Build It",
+			string(models.TestModeWordBank):             "Pick Words",
+			string(models.TestModeKeyboard):             "Type It",
+			string(models.TestModeMissingLetters):       "Fill the Gap",
+			string(models.TestModeFlashcard):            "Quick Look",
+			string(models.TestModeLookCoverWrite):       "Memory Spell",
+			string(models.TestModeTranslation):          "Switch Languages",
+			string(models.TestModeListeningTranslation): "Hear & Translate",
+		},
+		dateLayout:     "2006-01-02",
+		name:           "Name",
+		date:           "Date",
+		page:           "Page %d of {nb}",
+		dictation:      "Dictation",
+		missingLetters: "Fill in the missing letters",
+		answerKey:      "Answer key",
+		sentences:      "Sentences",
+		report:         "Progress report",
+		generated:      "Generated",
+		summary:        "Summary",
+		testsTaken:     "Tests completed",
+		averageScore:   "Average score",
+		correctWords:   "Correct words",
+		level:          "Level",
+		lastActivity:   "Last active",
+		recentTests:    "Recent tests",
+		mode:           "Mode",
+		score:          "Score",
+		wordSet:        "Word set",
+		missedWords:    "Words to practise",
+		word:           "Word",
+		misses:         "Misses",
+		noTests:        "No tests completed yet.",
+	},
+}
+
+// labelsFor returns the labels for lang, falling back to Norwegian
+func labelsFor(lang string) labels {
+	if l, ok := languageLabels[lang]; ok {
+		return l
+	}
+	return languageLabels[models.DigestLanguageNorwegian]
+}
+
+// newDocument creates an A4 document with the embedded fonts and a page number footer
+func newDocument(title string, l labels, now time.Time) *fpdf.Fpdf {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	// The alias must be set before the fonts are added so the digits are kept in the font subset
+	pdf.AliasNbPages("")
+	pdf.AddUTF8FontFromBytes(fontFamily, "", goregular.TTF)
+	pdf.AddUTF8FontFromBytes(fontFamily, "B", gobold.TTF)
+	pdf.SetMargins(pageMargin, pageMargin, pageMargin)
+	pdf.SetAutoPageBreak(true, pageMargin)
+	pdf.SetTitle(title, true)
+	pdf.SetCreator("Diktator", true)
+	pdf.SetCreationDate(now)
+	pdf.SetCatalogSort(true)
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-pageMargin + 5)
+		pdf.SetFont(fontFamily, "", 9)
+		pdf.SetTextColor(128, 128, 128)
+		pdf.CellFormat(0, 5, fmt.Sprintf(l.page, pdf.PageNo()), "", 0, "C", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+	})
+	pdf.AddPage()
+	return pdf
+}
+
+// heading prints a bold title with an optional smaller subtitle underneath
+func heading(pdf *fpdf.Fpdf, title, subtitle string) {
+	pdf.SetFont(fontFamily, "B", 18)
+	pdf.MultiCell(0, 9, title, "", "L", false)
+	if subtitle != "" {
+		pdf.SetFont(fontFamily, "", 12)
+		pdf.SetTextColor(80, 80, 80)
+		pdf.MultiCell(0, 7, subtitle, "", "L", false)
+		pdf.SetTextColor(0, 0, 0)
+	}
+	pdf.Ln(4)
+}
+
+// output writes the finished document to a byte slice
+func output(pdf *fpdf.Fpdf) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to render PDF: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,86 @@
+package printable
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/starefossen/diktator/backend/internal/models"
+)
+
+var now = time.Date(2026, 3, 16, 17, 0, 0, 0, time.UTC)
+
+func wordSet(words ...string) *models.WordSet {
+	ws := &models.WordSet{ID: "ws-1", Name: "Uke 12: æ, ø og å"}
+	for _, w := range words {
+		ws.Words = append(ws.Words, struct {
+			Word         string               `json:"word"`
+			Audio        models.WordAudio     `json:"audio,omitempty"`
+			Definition   string               `json:"definition,omitempty"`
+			Translations []models.Translation `json:"translations,omitempty"`
+		}{Word: w})
+	}
+	return ws
+}
+
+func TestWorksheet(t *testing.T) {
+	ws := wordSet("blåbær", "mann", "hjelpe", "skjorte", "pst")
+	ws.Sentences = []models.SentenceItem{{Sentence: "Jeg ser en båt på sjøen."}}
+
+	for _, mode := range []WorksheetMode{WorksheetDictation, WorksheetMissingLetters, WorksheetAnswerKey, "unknown"} {
+		t.Run(string(mode), func(t *testing.T) {
+			pdf, err := Worksheet(ws, mode, models.DigestLanguageNorwegian, now)
+			require.NoError(t, err)
+			assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-")))
+		})
+	}
+
+	// Output is deterministic for the same input
+	a, err := Worksheet(ws, WorksheetDictation, models.DigestLanguageEnglish, now)
+	require.NoError(t, err)
+	b, err := Worksheet(ws, WorksheetDictation, models.DigestLanguageEnglish, now)
+	require.NoError(t, err)
+	assert.Equal(t, a, b)
+}
+
+func TestWorksheet_ManyWordsSpanPages(t *testing.T) {
+	words := make([]string, 60)
+	for i := range words {
+		words[i] = "søsken"
+	}
+	pdf, err := Worksheet(wordSet(words...), WorksheetDictation, "", now)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, bytes.Count(pdf, []byte("/Type /Page\n")), 2)
+}
+
+func TestReport(t *testing.T) {
+	progress := &models.FamilyProgress{
+		UserName:     "Åse",
+		TotalTests:   2,
+		AverageScore: 75,
+		CorrectWords: 15,
+		TotalWords:   20,
+		Level:        3,
+		TotalXP:      420,
+		LastActivity: now,
+		RecentResults: []models.TestResult{
+			{WordSetID: "ws-1", Mode: string(models.TestModeKeyboard), Score: 80, CorrectWords: 8, TotalWords: 10, CompletedAt: now},
+			{WordSetID: "ws-gone", Mode: "somethingNew", Score: 70, CorrectWords: 7, TotalWords: 10, CompletedAt: now.AddDate(0, 0, -1)},
+		},
+	}
+
+	pdf, err := Report(ReportData{
+		Progress:    progress,
+		WordSets:    map[string]string{"ws-1": "En veldig lang ordliste med mange ord som ikke får plass i kolonnen"},
+		MissedWords: []models.MissedWord{{Word: "skjære", Misses: 2, Tests: 3}},
+	}, models.DigestLanguageNorwegian, now)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-")))
+
+	pdf, err = Report(ReportData{Progress: &models.FamilyProgress{UserName: "Ola"}}, models.DigestLanguageEnglish, now)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-")))
+}
@@ -0,0 +1,131 @@
+package printable
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+
+	"github.com/starefossen/diktator/backend/internal/models"
+)
+
+// ReportRecentTests is the number of recent tests listed on a report
+const ReportRecentTests = 10
+
+// ReportData is the content of a child's progress report
+type ReportData struct {
+	Progress    *models.FamilyProgress // RecentResults should be newest first
+	WordSets    map[string]string      // Word set names by ID, for the recent tests table
+	MissedWords []models.MissedWord
+}
+
+// Report renders a child's progress as a one or two page summary that can be
+// shared with teachers.
+func Report(data ReportData, lang string, now time.Time) ([]byte, error) {
+	l := labelsFor(lang)
+	p := data.Progress
+
+	pdf := newDocument(l.report+" – "+p.UserName, l, now)
+	heading(pdf, l.report+": "+p.UserName, l.generated+" "+now.Format(l.dateLayout))
+
+	section(pdf, l.summary)
+	summary := [][2]string{
+		{l.testsTaken, fmt.Sprintf("%d", p.TotalTests)},
+		{l.averageScore, fmt.Sprintf("%.0f %%", p.AverageScore)},
+		{l.correctWords, fmt.Sprintf("%d / %d", p.CorrectWords, p.TotalWords)},
+		{l.level, fmt.Sprintf("%d (%d XP)", p.Level, p.TotalXP)},
+	}
+	if p.TotalTests > 0 {
+		summary = append(summary, [2]string{l.lastActivity, p.LastActivity.Format(l.dateLayout)})
+	}
+	pdf.SetFont(fontFamily, "", 12)
+	for _, row := range summary {
+		pdf.CellFormat(70, lineHeight, row[0], "", 0, "L", false, 0, "")
+		pdf.CellFormat(0, lineHeight, row[1], "", 1, "L", false, 0, "")
+	}
+
+	section(pdf, l.recentTests)
+	if len(p.RecentResults) == 0 {
+		pdf.SetFont(fontFamily, "", 12)
+		pdf.CellFormat(0, lineHeight, l.noTests, "", 1, "L", false, 0, "")
+		return output(pdf)
+	}
+	recentTests(pdf, data, l)
+
+	if len(data.MissedWords) > 0 {
+		section(pdf, l.missedWords)
+		missedWords(pdf, data.MissedWords, l)
+	}
+
+	return output(pdf)
+}
+
+func section(pdf *fpdf.Fpdf, title string) {
+	pdf.Ln(4)
+	pdf.SetFont(fontFamily, "B", 14)
+	pdf.CellFormat(0, lineHeight+2, title, "B", 1, "L", false, 0, "")
+	pdf.Ln(2)
+}
+
+func recentTests(pdf *fpdf.Fpdf, data ReportData, l labels) {
+	widths := []float64{24, 56, 36, 20, 34}
+	tableRow(pdf, widths, true, l.date, l.wordSet, l.mode, l.score, l.correctWords)
+
+	results := data.Progress.RecentResults
+	if len(results) > ReportRecentTests {
+		results = results[:ReportRecentTests]
+	}
+	for _, r := range results {
+		mode := l.modes[r.Mode]
+		if mode == "" {
+			mode = r.Mode
+		}
+		name := data.WordSets[r.WordSetID]
+		tableRow(pdf, widths, false,
+			r.CompletedAt.Format(l.dateLayout),
+			name,
+			mode,
+			fmt.Sprintf("%.0f %%", r.Score),
+			fmt.Sprintf("%d/%d", r.CorrectWords, r.TotalWords),
+		)
+	}
+}
+
+func missedWords(pdf *fpdf.Fpdf, words []models.MissedWord, l labels) {
+	widths := []float64{50, 30}
+	tableRow(pdf, widths, true, l.word, l.misses)
+	for _, w := range words {
+		tableRow(pdf, widths, false, w.Word, fmt.Sprintf("%d / %d", w.Misses, w.Tests))
+	}
+}
+
+// tableRow prints one row of cells, truncating text that doesn't fit its column
+func tableRow(pdf *fpdf.Fpdf, widths []float64, header bool, cells ...string) {
+	style, border := "", "B"
+	if header {
+		style = "B"
+	}
+	pdf.SetFont(fontFamily, style, 11)
+	pdf.SetDrawColor(200, 200, 200)
+	for i, text := range cells {
+		ln := 0
+		if i == len(cells)-1 {
+			ln = 1
+		}
+		pdf.CellFormat(widths[i], lineHeight, fit(pdf, text, widths[i]-2), border, ln, "L", false, 0, "")
+	}
+	pdf.SetDrawColor(0, 0, 0)
+}
+
+// fit shortens text with an ellipsis until it is at most width mm wide
+func fit(pdf *fpdf.Fpdf, text string, width float64) string {
+	if pdf.GetStringWidth(text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && pdf.GetStringWidth(string(runes)+"…") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return strings.TrimSpace(string(runes)) + "…"
+}
@@ -0,0 +1,152 @@
+package printable
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services/spelling"
+)
+
+// WorksheetMode selects what a worksheet asks the child to do
+type WorksheetMode string
+
+const (
+	WorksheetDictation      WorksheetMode = "dictation"      // Numbered blank lines for words read aloud
+	WorksheetMissingLetters WorksheetMode = "missingLetters" // Words with their tricky letters blanked out
+	WorksheetAnswerKey      WorksheetMode = "answerKey"      // Every word spelled out, with the missing letters
+)
+
+// IsValid reports whether m is a known worksheet mode
+func (m WorksheetMode) IsValid() bool {
+	switch m {
+	case WorksheetDictation, WorksheetMissingLetters, WorksheetAnswerKey:
+		return true
+	}
+	return false
+}
+
+const (
+	numberWidth     = 12.0 // mm reserved for "12."
+	wordRowHeight   = 11.0 // mm between dictation lines
+	blankLineLength = 80.0 // mm
+)
+
+// Worksheet renders a word set as a printable worksheet. Sentences in the word set
+// follow the words in their own section.
+func Worksheet(ws *models.WordSet, mode WorksheetMode, lang string, now time.Time) ([]byte, error) {
+	l := labelsFor(lang)
+	if !mode.IsValid() {
+		mode = WorksheetDictation
+	}
+
+	subtitle := map[WorksheetMode]string{
+		WorksheetDictation:      l.dictation,
+		WorksheetMissingLetters: l.missingLetters,
+		WorksheetAnswerKey:      l.answerKey,
+	}[mode]
+
+	pdf := newDocument(ws.Name+" – "+subtitle, l, now)
+	heading(pdf, ws.Name, subtitle)
+	if mode != WorksheetAnswerKey {
+		nameAndDate(pdf, l)
+	}
+
+	pdf.SetFont(fontFamily, "", 14)
+	for i, w := range ws.Words {
+		number(pdf, i+1)
+		switch mode {
+		case WorksheetMissingLetters:
+			missingLettersRow(pdf, w.Word)
+		case WorksheetAnswerKey:
+			answerRow(pdf, w.Word)
+		default:
+			blankLine(pdf, blankLineLength)
+		}
+	}
+
+	if len(ws.Sentences) > 0 {
+		pdf.Ln(lineHeight)
+		pdf.SetFont(fontFamily, "B", 14)
+		pdf.CellFormat(0, lineHeight, l.sentences, "", 1, "L", false, 0, "")
+		pdf.SetFont(fontFamily, "", 14)
+		for i, s := range ws.Sentences {
+			number(pdf, i+1)
+			if mode == WorksheetAnswerKey {
+				pdf.MultiCell(0, lineHeight, s.Sentence, "", "L", false)
+				pdf.Ln(2)
+				continue
+			}
+			blankLine(pdf, 0)
+		}
+	}
+
+	return output(pdf)
+}
+
+// nameAndDate prints the "Name: ____  Date: ____" line at the top of a worksheet
+func nameAndDate(pdf *fpdf.Fpdf, l labels) {
+	pdf.SetFont(fontFamily, "", 12)
+	for _, field := range []struct {
+		label string
+		width float64
+	}{{l.name, 80}, {l.date, 40}} {
+		pdf.CellFormat(pdf.GetStringWidth(field.label+": ")+1, lineHeight, field.label+":", "", 0, "L", false, 0, "")
+		x, y := pdf.GetXY()
+		pdf.Line(x, y+lineHeight-1.5, x+field.width, y+lineHeight-1.5)
+		pdf.SetX(x + field.width + 10)
+	}
+	pdf.Ln(lineHeight * 2)
+}
+
+func number(pdf *fpdf.Fpdf, n int) {
+	pdf.CellFormat(numberWidth, wordRowHeight, fmt.Sprintf("%d.", n), "", 0, "R", false, 0, "")
+	pdf.SetX(pdf.GetX() + 3)
+}
+
+// blankLine draws a writing line from the cursor; a zero length spans to the right margin
+func blankLine(pdf *fpdf.Fpdf, length float64) {
+	x, y := pdf.GetXY()
+	if length == 0 {
+		w, _ := pdf.GetPageSize()
+		length = w - pageMargin - x
+	}
+	pdf.SetDrawColor(150, 150, 150)
+	pdf.Line(x, y+wordRowHeight-2, x+length, y+wordRowHeight-2)
+	pdf.SetDrawColor(0, 0, 0)
+	pdf.Ln(wordRowHeight)
+}
+
+// missingLettersRow prints the word with each blank as a wide gap to write in.
+// Words without a detectable challenge get a dictation line instead.
+func missingLettersRow(pdf *fpdf.Fpdf, word string) {
+	c, ok := spelling.DetectChallenge(word)
+	if !ok {
+		blankLine(pdf, blankLineLength)
+		return
+	}
+	pdf.CellFormat(0, wordRowHeight, spacedBlanks(c.BlankedWord), "", 1, "L", false, 0, "")
+}
+
+// answerRow prints the word, followed by the letters a missing-letters sheet leaves out
+func answerRow(pdf *fpdf.Fpdf, word string) {
+	pdf.SetFont(fontFamily, "B", 14)
+	pdf.CellFormat(blankLineLength, wordRowHeight, word, "", 0, "L", false, 0, "")
+	pdf.SetFont(fontFamily, "", 11)
+	pdf.SetTextColor(100, 100, 100)
+	hint := ""
+	if c, ok := spelling.DetectChallenge(word); ok {
+		hint = c.BlankedWord + " → " + c.MissingLetters
+	}
+	pdf.CellFormat(0, wordRowHeight, hint, "", 1, "L", false, 0, "")
+	pdf.SetTextColor(0, 0, 0)
+	pdf.SetFont(fontFamily, "", 14)
+}
+
+// spacedBlanks widens each blank so there is room to write a letter by hand
+func spacedBlanks(blanked string) string {
+	return strings.ReplaceAll(blanked, spelling.Blank, " ___ ")
+}
@@ -0,0 +1,129 @@
+// Package realtime streams family-scoped events to connected clients.
+//
+// Durable domain events reach the hub through the event bus (see BusHandler);
+// high-frequency live events such as answered words are published to the hub
+// directly so they never reach webhooks or the notification inbox.
+package realtime
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/starefossen/diktator/backend/internal/services/events"
+)
+
+// Live-only event types. They are streamed to connected clients but are not
+// part of the webhook contract and are never published on the event bus.
+const (
+	TypeTestStarted  events.Type = "test.started"
+	TypeWordAnswered events.Type = "test.word_answered"
+	TypeXPAwarded    events.Type = "xp.awarded"
+)
+
+// DefaultBufferSize is how many events a subscriber may lag behind before events are dropped
+const DefaultBufferSize = 64
+
+// Hub is a family-scoped publish/subscribe hub.
+//
+// MemoryHub only reaches subscribers in the same process. Running several
+// replicas needs an implementation that relays through a shared channel (for
+// example Postgres LISTEN/NOTIFY) into a local MemoryHub.
+type Hub interface {
+	Publish(ctx context.Context, event events.Event) error
+	Subscribe(familyID string) *Subscription
+}
+
+// Subscription receives a family's events until it is closed
+type Subscription struct {
+	C       <-chan events.Event
+	closeFn func()
+	once    sync.Once
+}
+
+// Close unsubscribes and releases the subscription
+func (s *Subscription) Close() {
+	s.once.Do(s.closeFn)
+}
+
+// BusHandler forwards events published on the event bus to the hub
+func BusHandler(hub Hub) events.Handler {
+	return events.HandlerFunc(func(ctx context.Context, event events.Event) error {
+		return hub.Publish(ctx, event)
+	})
+}
+
+// MemoryHub delivers events to subscribers in this process. Publishing never
+// blocks: a subscriber whose buffer is full misses the event.
+type MemoryHub struct {
+	families   map[string]map[chan events.Event]struct{}
+	mu         sync.RWMutex
+	bufferSize int
+	log        *slog.Logger
+}
+
+// NewMemoryHub creates an in-process hub. A nil logger uses slog.Default().
+func NewMemoryHub(bufferSize int, logger *slog.Logger) *MemoryHub {
+	if bufferSize < 1 {
+		bufferSize = DefaultBufferSize
+	}
+	return &MemoryHub{
+		families:   map[string]map[chan events.Event]struct{}{},
+		bufferSize: bufferSize,
+		log:        cmp.Or(logger, slog.Default()),
+	}
+}
+
+// Publish implements Hub
+func (h *MemoryHub) Publish(ctx context.Context, event events.Event) error {
+	if event.FamilyID == "" {
+		return nil
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for ch := range h.families[event.FamilyID] {
+		select {
+		case ch <- event:
+		default:
+			h.log.WarnContext(ctx, "Dropped event for a slow subscriber",
+				"event_type", event.Type, "event_id", event.ID, "family_id", event.FamilyID)
+		}
+	}
+
+	return nil
+}
+
+// Subscribe implements Hub
+func (h *MemoryHub) Subscribe(familyID string) *Subscription {
+	ch := make(chan events.Event, h.bufferSize)
+
+	h.mu.Lock()
+	if h.families[familyID] == nil {
+		h.families[familyID] = map[chan events.Event]struct{}{}
+	}
+	h.families[familyID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	return &Subscription{
+		C: ch,
+		closeFn: func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			delete(h.families[familyID], ch)
+			if len(h.families[familyID]) == 0 {
+				delete(h.families, familyID)
+			}
+			close(ch)
+		},
+	}
+}
+
+// Subscribers returns the number of open subscriptions for a family
+func (h *MemoryHub) Subscribers(familyID string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.families[familyID])
+}
@@ -0,0 +1,72 @@
+package realtime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/starefossen/diktator/backend/internal/services/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryHub_FamilyScoped(t *testing.T) {
+	hub := NewMemoryHub(4, nil)
+	a := hub.Subscribe("family-a")
+	defer a.Close()
+	b := hub.Subscribe("family-b")
+	defer b.Close()
+
+	event := events.New(TypeWordAnswered, "family-a", "child-1", map[string]any{"correct": true})
+	require.NoError(t, hub.Publish(context.Background(), event))
+
+	select {
+	case got := <-a.C:
+		assert.Equal(t, event.ID, got.ID)
+	default:
+		t.Fatal("family-a subscriber did not receive the event")
+	}
+
+	select {
+	case got := <-b.C:
+		t.Fatalf("family-b subscriber received %s", got.Type)
+	default:
+	}
+}
+
+func TestMemoryHub_SlowSubscriberDoesNotBlock(t *testing.T) {
+	hub := NewMemoryHub(1, nil)
+	sub := hub.Subscribe("family-a")
+	defer sub.Close()
+
+	for range 3 {
+		require.NoError(t, hub.Publish(context.Background(), events.New(TypeTestStarted, "family-a", "child-1", nil)))
+	}
+	assert.Len(t, sub.C, 1)
+}
+
+func TestMemoryHub_Close(t *testing.T) {
+	hub := NewMemoryHub(1, nil)
+	sub := hub.Subscribe("family-a")
+	assert.Equal(t, 1, hub.Subscribers("family-a"))
+
+	sub.Close()
+	sub.Close() // idempotent
+	assert.Equal(t, 0, hub.Subscribers("family-a"))
+
+	_, open := <-sub.C
+	assert.False(t, open)
+	require.NoError(t, hub.Publish(context.Background(), events.New(TypeTestStarted, "family-a", "child-1", nil)))
+}
+
+func TestBusHandler(t *testing.T) {
+	hub := NewMemoryHub(1, nil)
+	sub := hub.Subscribe("family-a")
+	defer sub.Close()
+
+	bus := events.NewBus(nil)
+	bus.Subscribe("realtime", BusHandler(hub))
+	bus.Publish(context.Background(), events.New(events.TypeTestCompleted, "family-a", "child-1", nil))
+
+	got := <-sub.C
+	assert.Equal(t, events.TypeTestCompleted, got.Type)
+}
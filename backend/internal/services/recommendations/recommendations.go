@@ -0,0 +1,309 @@
+// Package recommendations ranks family and curated word sets for a child.
+//
+// It is the server-side port of the frontend's rankWordSets, extended with the
+// child's spelling weaknesses and practice recency, so the REST API, the MCP
+// server and emails can all suggest the same word sets. Every recommendation
+// carries the reasons that produced its score.
+package recommendations
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/starefossen/diktator/backend/internal/models"
+)
+
+// Limits for the number of recommendations returned
+const (
+	DefaultLimit = 10
+	MaxLimit     = 50
+)
+
+// Reason codes
+const (
+	ReasonGradeMatch        = "gradeMatch"        // Target grade matches the child's estimated grade
+	ReasonGradeClose        = "gradeClose"        // Target grade is within two grades
+	ReasonGradeMismatch     = "gradeMismatch"     // Target grade is too easy or too hard
+	ReasonNotPlayed         = "notPlayed"         // Never practiced
+	ReasonRetry             = "retry"             // Practiced but not mastered
+	ReasonDifficultyMatch   = "difficultyMatch"   // Difficulty suits the child's level
+	ReasonDifficultyTooHard = "difficultyTooHard" // Advanced set for a beginner
+	ReasonDifficultyTooEasy = "difficultyTooEasy" // Beginner set for an advanced child
+	ReasonSpellingFocus     = "spellingFocus"     // Trains spelling patterns the child gets wrong
+	ReasonAssigned          = "assigned"          // Assigned to the child by a parent
+	ReasonDueForReview      = "dueForReview"      // Not practiced for a while
+	ReasonPracticedRecently = "practicedRecently" // Practiced in the last day
+)
+
+// Scoring weights, matching the frontend's rankWordSets where it has them
+const (
+	baseScore            = 100
+	masteredScore        = 90 // Best score at which a set counts as mastered and is not suggested
+	gradeMatchPoints     = 50
+	gradeClosePoints     = 20
+	gradeMismatchPoints  = -30
+	retryPoints          = 30
+	notPlayedPoints      = 10
+	difficultyPoints     = 20
+	tooEasyPoints        = -10
+	focusPointsPerMatch  = 20
+	maxFocusPoints       = 40
+	assignedPoints       = 40
+	reviewPoints         = 15
+	recentPoints         = -15
+	weaknessWindow       = 30 * 24 * time.Hour
+	reviewAfter          = 7 * 24 * time.Hour
+	recentlyPracticedFor = 24 * time.Hour
+)
+
+// Child is the part of a child account that recommendations depend on
+type Child struct {
+	BirthYear *int
+	ID        string
+	FamilyID  string
+	Level     int
+}
+
+// Repository defines the database operations needed for recommendations
+type Repository interface {
+	GetWordSets(ctx context.Context, familyID string) ([]models.WordSet, error)
+	GetGlobalWordSets(ctx context.Context) ([]models.WordSet, error)
+	GetTestResults(ctx context.Context, userID string) ([]models.TestResult, error)
+}
+
+// Service loads a child's word sets and history and ranks them
+type Service struct {
+	repo Repository
+	now  func() time.Time
+}
+
+// NewService creates a recommendation service
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo, now: time.Now}
+}
+
+// ForChild returns up to limit recommendations for a child, best first
+func (s *Service) ForChild(ctx context.Context, child Child, limit int) ([]models.WordSetRecommendation, error) {
+	familySets, err := s.repo.GetWordSets(ctx, child.FamilyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get family word sets: %w", err)
+	}
+	globalSets, err := s.repo.GetGlobalWordSets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get curated word sets: %w", err)
+	}
+	results, err := s.repo.GetTestResults(ctx, child.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get test results: %w", err)
+	}
+
+	ranked := Rank(child, append(familySets, globalSets...), results, s.now())
+	if limit < 1 || limit > MaxLimit {
+		limit = DefaultLimit
+	}
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	return ranked, nil
+}
+
+// Rank scores every word set the child has not mastered, best first. Ties are
+// broken by name so the order is stable.
+func Rank(child Child, wordSets []models.WordSet, results []models.TestResult, now time.Time) []models.WordSetRecommendation {
+	history := map[string]*setHistory{}
+	for _, r := range results {
+		h := history[r.WordSetID]
+		if h == nil {
+			h = &setHistory{best: r.Score, last: r.CompletedAt}
+			history[r.WordSetID] = h
+		}
+		h.best = max(h.best, r.Score)
+		if r.CompletedAt.After(h.last) {
+			h.last = r.CompletedAt
+		}
+	}
+	weaknesses := Weaknesses(results, now)
+
+	seen := map[string]bool{}
+	ranked := []models.WordSetRecommendation{}
+	for i := range wordSets {
+		ws := &wordSets[i]
+		if seen[ws.ID] {
+			continue
+		}
+		seen[ws.ID] = true
+
+		h := history[ws.ID]
+		if h != nil && h.best >= masteredScore {
+			continue
+		}
+
+		rec := models.WordSetRecommendation{
+			WordSetID:     ws.ID,
+			Name:          ws.Name,
+			TargetGrade:   ws.TargetGrade,
+			Difficulty:    ws.Difficulty,
+			SpellingFocus: ws.SpellingFocus,
+			WordCount:     len(ws.Words),
+			IsGlobal:      ws.IsGlobal,
+			Score:         baseScore,
+			Reasons:       []models.RecommendationReason{},
+		}
+		add := func(code string, points int, params map[string]any) {
+			rec.Score += points
+			rec.Reasons = append(rec.Reasons, models.RecommendationReason{Code: code, Points: points, Params: params})
+		}
+
+		scoreGrade(child, ws, now, add)
+		scoreHistory(h, now, &rec, add)
+		scoreDifficulty(child, ws, add)
+
+		if matched := focusMatches(ws.SpellingFocus, weaknesses); len(matched) > 0 {
+			add(ReasonSpellingFocus, min(len(matched)*focusPointsPerMatch, maxFocusPoints), map[string]any{"categories": matched})
+		}
+		for _, id := range ws.AssignedUserIDs {
+			if id == child.ID {
+				add(ReasonAssigned, assignedPoints, nil)
+				break
+			}
+		}
+
+		ranked = append(ranked, rec)
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].Name < ranked[j].Name
+	})
+	return ranked
+}
+
+// Weaknesses counts the child's spelling errors in the last 30 days by spelling
+// focus category
+func Weaknesses(results []models.TestResult, now time.Time) map[models.SpellingFocusCategory]int {
+	since := now.Add(-weaknessWindow)
+	counts := map[models.SpellingFocusCategory]int{}
+	for _, r := range results {
+		if r.CompletedAt.Before(since) {
+			continue
+		}
+		for _, w := range r.Words {
+			for _, et := range w.ErrorTypes {
+				for _, category := range models.SpellingFocusForErrorType(et) {
+					counts[category]++
+				}
+			}
+		}
+	}
+	return counts
+}
+
+// EstimateGrade returns the Norwegian school grade for a birth year: children
+// start first grade the year they turn six
+func EstimateGrade(birthYear int, now time.Time) int {
+	return max(0, now.Year()-birthYear-5)
+}
+
+type setHistory struct {
+	last time.Time
+	best float64
+}
+
+type addReason func(code string, points int, params map[string]any)
+
+func scoreGrade(child Child, ws *models.WordSet, now time.Time, add addReason) {
+	if child.BirthYear == nil || ws.TargetGrade == nil {
+		return
+	}
+
+	grade := EstimateGrade(*child.BirthYear, now)
+	if gradeCategory(grade) == *ws.TargetGrade {
+		add(ReasonGradeMatch, gradeMatchPoints, map[string]any{"grade": grade})
+		return
+	}
+
+	diff := float64(grade) - gradeMidpoint(*ws.TargetGrade)
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff <= 2 {
+		add(ReasonGradeClose, gradeClosePoints, map[string]any{"grade": grade})
+	} else {
+		add(ReasonGradeMismatch, gradeMismatchPoints, map[string]any{"grade": grade})
+	}
+}
+
+func scoreHistory(h *setHistory, now time.Time, rec *models.WordSetRecommendation, add addReason) {
+	if h == nil {
+		add(ReasonNotPlayed, notPlayedPoints, nil)
+		return
+	}
+
+	best, last := h.best, h.last
+	rec.BestScore = &best
+	rec.LastPracticedAt = &last
+	add(ReasonRetry, retryPoints, map[string]any{"bestScore": best})
+
+	switch since := now.Sub(last); {
+	case since < recentlyPracticedFor:
+		add(ReasonPracticedRecently, recentPoints, nil)
+	case since >= reviewAfter:
+		add(ReasonDueForReview, reviewPoints, map[string]any{"days": int(since.Hours() / 24)})
+	}
+}
+
+func scoreDifficulty(child Child, ws *models.WordSet, add addReason) {
+	if ws.Difficulty == nil {
+		return
+	}
+
+	level := max(child.Level, 1)
+	switch {
+	case level <= 3 && *ws.Difficulty == models.DifficultyBeginner,
+		level >= 7 && *ws.Difficulty == models.DifficultyAdvanced:
+		add(ReasonDifficultyMatch, difficultyPoints, map[string]any{"level": level})
+	case level <= 3 && *ws.Difficulty == models.DifficultyAdvanced:
+		add(ReasonDifficultyTooHard, -difficultyPoints, map[string]any{"level": level})
+	case level >= 7 && *ws.Difficulty == models.DifficultyBeginner:
+		add(ReasonDifficultyTooEasy, tooEasyPoints, map[string]any{"level": level})
+	}
+}
+
+// focusMatches returns the set's focus categories the child has made errors in
+func focusMatches(focus []models.SpellingFocusCategory, weaknesses map[models.SpellingFocusCategory]int) []models.SpellingFocusCategory {
+	var matched []models.SpellingFocusCategory
+	for _, category := range focus {
+		if weaknesses[category] > 0 {
+			matched = append(matched, category)
+		}
+	}
+	return matched
+}
+
+func gradeCategory(grade int) models.GradeLevel {
+	switch {
+	case grade <= 2:
+		return models.GradeLevel12
+	case grade <= 4:
+		return models.GradeLevel34
+	case grade <= 7:
+		return models.GradeLevel57
+	default:
+		return ""
+	}
+}
+
+func gradeMidpoint(level models.GradeLevel) float64 {
+	switch level {
+	case models.GradeLevel12:
+		return 1.5
+	case models.GradeLevel34:
+		return 3.5
+	default:
+		return 6
+	}
+}
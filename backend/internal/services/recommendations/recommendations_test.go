@@ -0,0 +1,177 @@
+package recommendations
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2026, 3, 16, 17, 0, 0, 0, time.UTC)
+
+func wordSet(id, name string, grade models.GradeLevel, difficulty models.DifficultyLevel, focus ...models.SpellingFocusCategory) models.WordSet {
+	ws := models.WordSet{ID: id, Name: name, SpellingFocus: focus}
+	if grade != "" {
+		ws.TargetGrade = &grade
+	}
+	if difficulty != "" {
+		ws.Difficulty = &difficulty
+	}
+	return ws
+}
+
+func result(wordSetID string, score float64, ago time.Duration, errorTypes ...string) models.TestResult {
+	return models.TestResult{
+		WordSetID:   wordSetID,
+		Score:       score,
+		CompletedAt: now.Add(-ago),
+		Words:       []models.WordTestResult{{Word: "ord", ErrorTypes: errorTypes}},
+	}
+}
+
+func reasons(rec models.WordSetRecommendation) map[string]int {
+	m := map[string]int{}
+	for _, r := range rec.Reasons {
+		m[r.Code] = r.Points
+	}
+	return m
+}
+
+func byID(ranked []models.WordSetRecommendation) map[string]models.WordSetRecommendation {
+	m := map[string]models.WordSetRecommendation{}
+	for _, rec := range ranked {
+		m[rec.WordSetID] = rec
+	}
+	return m
+}
+
+func TestRank_GradeAndDifficulty(t *testing.T) {
+	birthYear := now.Year() - 9 // Grade 4
+	child := Child{ID: "child-1", BirthYear: &birthYear, Level: 2}
+
+	ranked := Rank(child, []models.WordSet{
+		wordSet("match", "Match", models.GradeLevel34, models.DifficultyBeginner),
+		wordSet("close", "Close", models.GradeLevel57, ""),
+		wordSet("far", "Far", models.GradeLevel12, models.DifficultyAdvanced),
+		wordSet("plain", "Plain", "", ""),
+	}, nil, now)
+
+	require.Len(t, ranked, 4)
+	assert.Equal(t, []string{"match", "close", "plain", "far"}, []string{ranked[0].WordSetID, ranked[1].WordSetID, ranked[2].WordSetID, ranked[3].WordSetID})
+
+	sets := byID(ranked)
+	assert.Equal(t, map[string]int{ReasonGradeMatch: 50, ReasonNotPlayed: 10, ReasonDifficultyMatch: 20}, reasons(sets["match"]))
+	assert.Equal(t, 180, sets["match"].Score)
+	assert.Equal(t, map[string]int{ReasonGradeClose: 20, ReasonNotPlayed: 10}, reasons(sets["close"]))
+	assert.Equal(t, map[string]int{ReasonGradeMismatch: -30, ReasonNotPlayed: 10, ReasonDifficultyTooHard: -20}, reasons(sets["far"]))
+	assert.Equal(t, 110, sets["plain"].Score, "sets without metadata only get the history bonus")
+}
+
+func TestRank_HistoryAndRecency(t *testing.T) {
+	child := Child{ID: "child-1", Level: 5}
+	sets := []models.WordSet{
+		wordSet("mastered", "Mastered", "", ""),
+		wordSet("yesterday", "Yesterday", "", ""),
+		wordSet("stale", "Stale", "", ""),
+		wordSet("today", "Today", "", ""),
+	}
+	results := []models.TestResult{
+		result("mastered", 60, 20*24*time.Hour),
+		result("mastered", 95, 2*24*time.Hour),
+		result("yesterday", 70, 36*time.Hour),
+		result("stale", 50, 10*24*time.Hour),
+		result("today", 40, 2*time.Hour),
+	}
+
+	ranked := byID(Rank(child, sets, results, now))
+
+	assert.NotContains(t, ranked, "mastered", "a best score of 90 or more is mastered")
+	assert.Equal(t, map[string]int{ReasonRetry: 30}, reasons(ranked["yesterday"]))
+	assert.Equal(t, map[string]int{ReasonRetry: 30, ReasonDueForReview: 15}, reasons(ranked["stale"]))
+	assert.Equal(t, map[string]int{ReasonRetry: 30, ReasonPracticedRecently: -15}, reasons(ranked["today"]))
+
+	require.NotNil(t, ranked["stale"].BestScore)
+	assert.Equal(t, 50.0, *ranked["stale"].BestScore)
+	require.NotNil(t, ranked["stale"].LastPracticedAt)
+	assert.Equal(t, now.Add(-10*24*time.Hour), *ranked["stale"].LastPracticedAt)
+}
+
+func TestRank_SpellingWeaknessesAndAssignments(t *testing.T) {
+	child := Child{ID: "child-1", Level: 5}
+	double := wordSet("double", "Dobbel", "", "", models.SpellingFocusDoubleConsonant, models.SpellingFocusVowelLength, models.SpellingFocusSilentLetter)
+	skj := wordSet("skj", "Skj", "", "", models.SpellingFocusSkjSound)
+	assigned := wordSet("assigned", "Assigned", "", "")
+	assigned.AssignedUserIDs = []string{"child-2", "child-1"}
+
+	results := []models.TestResult{
+		result("other", 50, 3*24*time.Hour, "doubleConsonant", "silentH"),
+		result("other", 50, 60*24*time.Hour, "kjSkjSj"), // Outside the weakness window
+	}
+
+	ranked := byID(Rank(child, []models.WordSet{double, skj, assigned, double}, results, now))
+	require.Len(t, ranked, 3, "duplicate word sets are ranked once")
+
+	assert.Equal(t, 40, reasons(ranked["double"])[ReasonSpellingFocus], "focus points are capped")
+	assert.Equal(t, []models.SpellingFocusCategory{
+		models.SpellingFocusDoubleConsonant, models.SpellingFocusVowelLength, models.SpellingFocusSilentLetter,
+	}, ranked["double"].Reasons[len(ranked["double"].Reasons)-1].Params["categories"])
+	assert.NotContains(t, reasons(ranked["skj"]), ReasonSpellingFocus)
+	assert.Equal(t, 40, reasons(ranked["assigned"])[ReasonAssigned])
+}
+
+func TestRank_TiesSortByName(t *testing.T) {
+	ranked := Rank(Child{ID: "child-1"}, []models.WordSet{
+		wordSet("b", "Båt", "", ""),
+		wordSet("a", "Alle", "", ""),
+	}, nil, now)
+	assert.Equal(t, "a", ranked[0].WordSetID)
+}
+
+type fakeRepo struct {
+	family  []models.WordSet
+	global  []models.WordSet
+	results []models.TestResult
+	err     error
+}
+
+func (f *fakeRepo) GetWordSets(context.Context, string) ([]models.WordSet, error) {
+	return f.family, f.err
+}
+func (f *fakeRepo) GetGlobalWordSets(_ context.Context) ([]models.WordSet, error) {
+	return f.global, nil
+}
+func (f *fakeRepo) GetTestResults(context.Context, string) ([]models.TestResult, error) {
+	return f.results, nil
+}
+
+func TestService_ForChild(t *testing.T) {
+	repo := &fakeRepo{}
+	for i := range 15 {
+		repo.family = append(repo.family, wordSet(string(rune('a'+i)), string(rune('a'+i)), "", ""))
+	}
+	global := wordSet("global", "Global", "", "")
+	global.IsGlobal = true
+	global.AssignedUserIDs = []string{"child-1"}
+	repo.global = []models.WordSet{global}
+
+	svc := NewService(repo)
+	svc.now = func() time.Time { return now }
+
+	ranked, err := svc.ForChild(t.Context(), Child{ID: "child-1"}, 0)
+	require.NoError(t, err)
+	assert.Len(t, ranked, DefaultLimit)
+	assert.Equal(t, "global", ranked[0].WordSetID)
+	assert.True(t, ranked[0].IsGlobal)
+
+	ranked, err = svc.ForChild(t.Context(), Child{ID: "child-1"}, 3)
+	require.NoError(t, err)
+	assert.Len(t, ranked, 3)
+
+	repo.err = errors.New("boom")
+	_, err = svc.ForChild(t.Context(), Child{ID: "child-1"}, 3)
+	assert.ErrorIs(t, err, repo.err)
+}
@@ -0,0 +1,51 @@
+package session
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"time"
+)
+
+// CleanupRepository defines the database operations needed by the cleanup worker
+type CleanupRepository interface {
+	DeleteExpiredTestSessions(ctx context.Context, before time.Time) (int, error)
+}
+
+// Cleaner periodically deletes expired sessions
+type Cleaner struct {
+	repo CleanupRepository
+	now  func() time.Time
+	cfg  Config
+	log  *slog.Logger
+}
+
+// NewCleaner creates a cleanup worker
+func NewCleaner(repo CleanupRepository, cfg Config) *Cleaner {
+	return &Cleaner{repo: repo, now: time.Now, cfg: cfg, log: cmp.Or(cfg.Logger, slog.Default())}
+}
+
+// RunOnce deletes all sessions that have expired and returns how many were removed
+func (c *Cleaner) RunOnce(ctx context.Context) (int, error) {
+	return c.repo.DeleteExpiredTestSessions(ctx, c.now())
+}
+
+// Run deletes expired sessions periodically until the context is cancelled
+func (c *Cleaner) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.cfg.CleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := c.RunOnce(ctx); err != nil {
+			c.log.ErrorContext(ctx, "Failed to delete expired sessions", "error", err)
+		} else if n > 0 {
+			c.log.InfoContext(ctx, "Deleted expired test sessions", "count", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
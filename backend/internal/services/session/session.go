@@ -0,0 +1,366 @@
+// Package session runs spelling tests on the server. A session picks the words
+// and mode up front, judges each answer as it arrives, measures time per word
+// and is finalized into a models.TestResult. Sessions survive reloads and can
+// be resumed until they expire.
+package session
+
+import (
+	"errors"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services/spelling"
+)
+
+// Session errors
+var (
+	ErrNoWords          = errors.New("word set has no words")
+	ErrInvalidMode      = errors.New("invalid test mode")
+	ErrNoTranslations   = errors.New("word set has no translations")
+	ErrSessionFinished  = errors.New("session has no words left to answer")
+	ErrSessionCompleted = errors.New("session is already completed")
+	ErrSessionExpired   = errors.New("session has expired")
+	ErrIncomplete       = errors.New("session has unanswered words")
+)
+
+// Translation directions
+const (
+	DirectionToTarget = "toTarget"
+	DirectionToSource = "toSource"
+)
+
+// DefaultMaxAttempts matches the frontend's default test configuration
+const DefaultMaxAttempts = 3
+
+// Config holds session timing configuration
+type Config struct {
+	TTL             time.Duration // Inactivity before a session expires
+	MaxIdle         time.Duration // Longest gap between answers counted as time spent
+	CleanupInterval time.Duration // How often expired sessions are deleted
+	Logger          *slog.Logger  // Cleanup worker logger; defaults to slog.Default()
+}
+
+// DefaultConfig returns sensible default configuration
+func DefaultConfig() Config {
+	return Config{
+		TTL:             24 * time.Hour,
+		MaxIdle:         5 * time.Minute,
+		CleanupInterval: time.Hour,
+	}
+}
+
+// Engine applies session state transitions. It does not persist anything.
+type Engine struct {
+	rng *rand.Rand
+	cfg Config
+}
+
+// NewEngine creates a session engine. A nil rng uses a randomly seeded source.
+func NewEngine(cfg Config, rng *rand.Rand) *Engine {
+	if rng == nil {
+		rng = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	}
+	return &Engine{cfg: cfg, rng: rng}
+}
+
+// Start creates a new session for a word set. An empty mode uses the word set's
+// configured default mode, then keyboard.
+func (e *Engine) Start(wordSet *models.WordSet, userID, familyID, mode string, now time.Time) (*models.TestSession, error) {
+	if len(wordSet.Words) == 0 {
+		return nil, ErrNoWords
+	}
+
+	config := testConfig(wordSet)
+	if mode == "" {
+		mode, _ = config["defaultMode"].(string)
+	}
+	if mode == "" {
+		mode = string(models.TestModeKeyboard)
+	}
+	if !models.IsValidTestMode(mode) {
+		return nil, ErrInvalidMode
+	}
+
+	translating := isTranslationMode(mode)
+	if translating && !hasTranslations(wordSet) {
+		return nil, ErrNoTranslations
+	}
+
+	order := make([]int, len(wordSet.Words))
+	for i := range order {
+		order[i] = i
+	}
+	if shuffle, _ := config["shuffleWords"].(bool); shuffle {
+		e.rng.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+	}
+
+	direction, _ := config["translationDirection"].(string)
+	mixRatio := 0.5
+	if ratio, ok := config["translationMixRatio"].(float64); ok {
+		mixRatio = ratio
+	}
+
+	words := make([]models.SessionWord, len(order))
+	for i, idx := range order {
+		w := wordSet.Words[idx]
+		sw := models.SessionWord{Word: w.Word, ExpectedAnswer: w.Word, Answers: []string{}}
+		if translating {
+			sw.Direction = DirectionToTarget
+			switch direction {
+			case DirectionToSource:
+				sw.Direction = DirectionToSource
+			case "mixed":
+				if e.rng.Float64() >= mixRatio {
+					sw.Direction = DirectionToSource
+				}
+			}
+			if sw.Direction == DirectionToTarget && len(w.Translations) > 0 {
+				sw.ExpectedAnswer = w.Translations[0].Text
+			}
+		}
+		words[i] = sw
+	}
+	started := now
+	words[0].StartedAt = &started
+
+	maxAttempts := DefaultMaxAttempts
+	if n, ok := config["maxAttempts"].(float64); ok && n >= 1 {
+		maxAttempts = min(int(n), 5)
+	}
+
+	return &models.TestSession{
+		ID:             uuid.New().String(),
+		UserID:         userID,
+		FamilyID:       familyID,
+		WordSetID:      wordSet.ID,
+		Mode:           mode,
+		Status:         models.TestSessionActive,
+		Words:          words,
+		MaxAttempts:    maxAttempts,
+		StartedAt:      now,
+		LastActivityAt: now,
+		ExpiresAt:      now.Add(e.cfg.TTL),
+	}, nil
+}
+
+// Answer judges an attempt at the current word and advances the session when
+// the word is answered correctly or runs out of attempts
+func (e *Engine) Answer(s *models.TestSession, req models.RecordAnswerRequest, now time.Time) (*models.RecordAnswerResponse, error) {
+	if err := e.checkActive(s, now); err != nil {
+		return nil, err
+	}
+	if s.CurrentIndex >= len(s.Words) {
+		return nil, ErrSessionFinished
+	}
+
+	w := &s.Words[s.CurrentIndex]
+	e.accumulate(w, now)
+
+	w.Answers = append(w.Answers, req.Answer)
+	w.AudioPlayCount += req.AudioPlayCount
+	w.ErrorTypes = appendUnique(w.ErrorTypes, req.ErrorTypes...)
+	w.Correct = Normalize(req.Answer) == Normalize(w.ExpectedAnswer)
+
+	resp := &models.RecordAnswerResponse{
+		Session:  s,
+		Correct:  w.Correct,
+		Attempts: len(w.Answers),
+	}
+
+	if w.Correct || len(w.Answers) >= s.MaxAttempts {
+		completed := now
+		w.CompletedAt = &completed
+		w.StartedAt = nil
+		s.CurrentIndex++
+		if s.CurrentIndex < len(s.Words) {
+			next := now
+			s.Words[s.CurrentIndex].StartedAt = &next
+		}
+		resp.WordComplete = true
+		resp.ExpectedAnswer = w.ExpectedAnswer
+	}
+	resp.Finished = s.CurrentIndex >= len(s.Words)
+
+	e.touch(s, now)
+	return resp, nil
+}
+
+// Resume restarts timing of the current word so the time a session sat idle
+// (a reload or a sleeping tablet) is not counted
+func (e *Engine) Resume(s *models.TestSession, now time.Time) error {
+	if err := e.checkActive(s, now); err != nil {
+		return err
+	}
+	if s.CurrentIndex < len(s.Words) {
+		resumed := now
+		s.Words[s.CurrentIndex].StartedAt = &resumed
+	}
+	e.touch(s, now)
+	return nil
+}
+
+// Result builds the test result for a session whose words are all complete.
+// Scoring matches the frontend: 100% for a first-try word, 70% for the
+// second, 40% for the third and nothing after that or for a failed word.
+func (e *Engine) Result(s *models.TestSession, now time.Time) (*models.TestResult, error) {
+	if err := e.checkActive(s, now); err != nil {
+		return nil, err
+	}
+	if s.CurrentIndex < len(s.Words) {
+		return nil, ErrIncomplete
+	}
+
+	result := &models.TestResult{
+		ID:             uuid.New().String(),
+		WordSetID:      s.WordSetID,
+		UserID:         s.UserID,
+		Mode:           s.Mode,
+		TotalWords:     len(s.Words),
+		IncorrectWords: []string{},
+		Words:          make([]models.WordTestResult, len(s.Words)),
+		CompletedAt:    now,
+		CreatedAt:      now,
+	}
+
+	var weighted float64
+	var totalMs int64
+	for i, w := range s.Words {
+		attempts := len(w.Answers)
+		if w.Correct {
+			result.CorrectWords++
+			weighted += attemptWeight(attempts)
+		} else {
+			result.IncorrectWords = append(result.IncorrectWords, w.Word)
+		}
+		totalMs += w.TimeSpentMs
+
+		final := ""
+		if attempts > 0 {
+			final = strings.TrimSpace(w.Answers[attempts-1])
+		}
+		result.Words[i] = models.WordTestResult{
+			Word:           w.Word,
+			UserAnswers:    w.Answers,
+			FinalAnswer:    final,
+			ErrorTypes:     w.ErrorTypes,
+			Attempts:       attempts,
+			TimeSpent:      msToSeconds(w.TimeSpentMs),
+			AudioPlayCount: w.AudioPlayCount,
+			Correct:        w.Correct,
+		}
+	}
+
+	result.Score = math.Round(weighted / float64(len(s.Words)) * 100)
+	result.TimeSpent = msToSeconds(totalMs)
+	return result, nil
+}
+
+// Complete marks a session as finalized into the given result
+func (e *Engine) Complete(s *models.TestSession, resultID string, now time.Time) {
+	s.Status = models.TestSessionCompleted
+	s.ResultID = &resultID
+	completed := now
+	s.CompletedAt = &completed
+	e.touch(s, now)
+}
+
+// Expired reports whether a session can no longer be used
+func Expired(s *models.TestSession, now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
+
+func (e *Engine) checkActive(s *models.TestSession, now time.Time) error {
+	if s.Status == models.TestSessionCompleted {
+		return ErrSessionCompleted
+	}
+	if Expired(s, now) {
+		return ErrSessionExpired
+	}
+	return nil
+}
+
+// accumulate adds the time since the word's interval started, capped at MaxIdle
+func (e *Engine) accumulate(w *models.SessionWord, now time.Time) {
+	if w.StartedAt != nil {
+		elapsed := now.Sub(*w.StartedAt)
+		if elapsed > e.cfg.MaxIdle {
+			elapsed = e.cfg.MaxIdle
+		}
+		if elapsed > 0 {
+			w.TimeSpentMs += elapsed.Milliseconds()
+		}
+	}
+	started := now
+	w.StartedAt = &started
+}
+
+func (e *Engine) touch(s *models.TestSession, now time.Time) {
+	s.LastActivityAt = now
+	s.ExpiresAt = now.Add(e.cfg.TTL)
+}
+
+func attemptWeight(attempts int) float64 {
+	switch attempts {
+	case 1:
+		return 1.0
+	case 2:
+		return 0.7
+	case 3:
+		return 0.4
+	default:
+		return 0
+	}
+}
+
+// Normalize prepares an answer for comparison the same way the frontend does:
+// case, punctuation and extra whitespace are ignored
+func Normalize(text string) string {
+	text = strings.ToLower(strings.TrimSpace(text))
+	text = spelling.StripPunctuation(text)
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func testConfig(wordSet *models.WordSet) map[string]any {
+	if wordSet.TestConfiguration == nil {
+		return map[string]any{}
+	}
+	return *wordSet.TestConfiguration
+}
+
+func isTranslationMode(mode string) bool {
+	return mode == string(models.TestModeTranslation) || mode == string(models.TestModeListeningTranslation)
+}
+
+func hasTranslations(wordSet *models.WordSet) bool {
+	for _, w := range wordSet.Words {
+		if len(w.Translations) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func appendUnique(list []string, values ...string) []string {
+	for _, v := range values {
+		found := false
+		for _, existing := range list {
+			if existing == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+func msToSeconds(ms int64) int {
+	return int(math.Round(float64(ms) / 1000))
+}
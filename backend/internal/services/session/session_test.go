@@ -0,0 +1,177 @@
+package session
+
+import (
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var t0 = time.Date(2026, 3, 16, 17, 0, 0, 0, time.UTC)
+
+func newWordSet(config map[string]any, words ...string) *models.WordSet {
+	ws := &models.WordSet{ID: "ws-1", Name: "Uke 12"}
+	if config != nil {
+		ws.TestConfiguration = &config
+	}
+	for _, w := range words {
+		ws.Words = append(ws.Words, struct {
+			Word         string               `json:"word"`
+			Audio        models.WordAudio     `json:"audio,omitempty"`
+			Definition   string               `json:"definition,omitempty"`
+			Translations []models.Translation `json:"translations,omitempty"`
+		}{
+			Word:         w,
+			Translations: []models.Translation{{Language: "en", Text: "en-" + w}},
+		})
+	}
+	return ws
+}
+
+func newEngine() *Engine {
+	return NewEngine(DefaultConfig(), rand.New(rand.NewPCG(1, 2)))
+}
+
+func answer(t *testing.T, e *Engine, s *models.TestSession, text string, at time.Time) *models.RecordAnswerResponse {
+	t.Helper()
+	resp, err := e.Answer(s, models.RecordAnswerRequest{Answer: text}, at)
+	require.NoError(t, err)
+	return resp
+}
+
+func TestStart(t *testing.T) {
+	e := newEngine()
+
+	s, err := e.Start(newWordSet(nil, "hus", "bil"), "child-1", "family-1", "", t0)
+	require.NoError(t, err)
+	assert.Equal(t, string(models.TestModeKeyboard), s.Mode)
+	assert.Equal(t, DefaultMaxAttempts, s.MaxAttempts)
+	assert.Equal(t, []string{"hus", "bil"}, []string{s.Words[0].Word, s.Words[1].Word})
+	assert.Equal(t, t0.Add(DefaultConfig().TTL), s.ExpiresAt)
+	require.NotNil(t, s.Words[0].StartedAt)
+	assert.Nil(t, s.Words[1].StartedAt)
+
+	s, err = e.Start(newWordSet(map[string]any{"defaultMode": "letterTiles", "maxAttempts": 2.0}, "hus"), "child-1", "family-1", "", t0)
+	require.NoError(t, err)
+	assert.Equal(t, string(models.TestModeLetterTiles), s.Mode)
+	assert.Equal(t, 2, s.MaxAttempts)
+
+	s, err = e.Start(newWordSet(nil, "hus"), "child-1", "family-1", string(models.TestModeTranslation), t0)
+	require.NoError(t, err)
+	assert.Equal(t, DirectionToTarget, s.Words[0].Direction)
+	assert.Equal(t, "en-hus", s.Words[0].ExpectedAnswer)
+
+	_, err = e.Start(newWordSet(nil), "child-1", "family-1", "", t0)
+	assert.ErrorIs(t, err, ErrNoWords)
+	_, err = e.Start(newWordSet(nil, "hus"), "child-1", "family-1", "telepathy", t0)
+	assert.ErrorIs(t, err, ErrInvalidMode)
+
+	noTranslations := newWordSet(nil, "hus")
+	noTranslations.Words[0].Translations = nil
+	_, err = e.Start(noTranslations, "child-1", "family-1", string(models.TestModeTranslation), t0)
+	assert.ErrorIs(t, err, ErrNoTranslations)
+}
+
+func TestStart_Shuffle(t *testing.T) {
+	words := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+	s, err := newEngine().Start(newWordSet(map[string]any{"shuffleWords": true}, words...), "child-1", "family-1", "", t0)
+	require.NoError(t, err)
+
+	var got []string
+	for _, w := range s.Words {
+		got = append(got, w.Word)
+	}
+	assert.ElementsMatch(t, words, got)
+	assert.NotEqual(t, words, got, "seeded shuffle should reorder")
+}
+
+func TestAnswerAndResult(t *testing.T) {
+	e := newEngine()
+	s, err := e.Start(newWordSet(nil, "hus", "skjære", "bil"), "child-1", "family-1", "", t0)
+	require.NoError(t, err)
+
+	// First word right on the first try, with a little punctuation and case noise
+	resp := answer(t, e, s, " Hus. ", t0.Add(4*time.Second))
+	assert.True(t, resp.Correct)
+	assert.True(t, resp.WordComplete)
+	assert.Equal(t, "hus", resp.ExpectedAnswer)
+	assert.Equal(t, 1, s.CurrentIndex)
+
+	// Second word right on the second try
+	resp = answer(t, e, s, "sjære", t0.Add(10*time.Second))
+	assert.False(t, resp.Correct)
+	assert.False(t, resp.WordComplete)
+	assert.Empty(t, resp.ExpectedAnswer, "answer is not revealed before the word is complete")
+	resp = answer(t, e, s, "skjære", t0.Add(15*time.Second))
+	assert.True(t, resp.WordComplete)
+
+	_, err = e.Result(s, t0.Add(16*time.Second))
+	assert.ErrorIs(t, err, ErrIncomplete)
+
+	// Third word failed after all attempts
+	answer(t, e, s, "bill", t0.Add(20*time.Second))
+	answer(t, e, s, "bilen", t0.Add(22*time.Second))
+	resp = answer(t, e, s, "bli", t0.Add(25*time.Second))
+	assert.True(t, resp.WordComplete)
+	assert.True(t, resp.Finished)
+
+	_, err = e.Answer(s, models.RecordAnswerRequest{Answer: "x"}, t0.Add(26*time.Second))
+	assert.ErrorIs(t, err, ErrSessionFinished)
+
+	result, err := e.Result(s, t0.Add(30*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, 57.0, result.Score, "(1.0 + 0.7 + 0) / 3")
+	assert.Equal(t, 3, result.TotalWords)
+	assert.Equal(t, 2, result.CorrectWords)
+	assert.Equal(t, []string{"bil"}, result.IncorrectWords)
+	assert.Equal(t, 25, result.TimeSpent)
+	assert.Equal(t, 4, result.Words[0].TimeSpent)
+	assert.Equal(t, 11, result.Words[1].TimeSpent)
+	assert.Equal(t, 2, result.Words[1].Attempts)
+	assert.Equal(t, []string{"sjære", "skjære"}, result.Words[1].UserAnswers)
+	assert.Equal(t, "bli", result.Words[2].FinalAnswer)
+
+	e.Complete(s, result.ID, t0.Add(30*time.Second))
+	assert.Equal(t, models.TestSessionCompleted, s.Status)
+	_, err = e.Result(s, t0.Add(31*time.Second))
+	assert.ErrorIs(t, err, ErrSessionCompleted)
+}
+
+func TestTiming_IdleAndResume(t *testing.T) {
+	e := newEngine()
+	s, err := e.Start(newWordSet(nil, "hus", "bil"), "child-1", "family-1", "", t0)
+	require.NoError(t, err)
+
+	// An hour-long gap without a resume counts as MaxIdle
+	answer(t, e, s, "hus", t0.Add(time.Hour))
+	assert.Equal(t, DefaultConfig().MaxIdle.Milliseconds(), s.Words[0].TimeSpentMs)
+
+	// After a resume only time since the resume counts
+	answer(t, e, s, "bli", t0.Add(time.Hour+3*time.Second))
+	require.NoError(t, e.Resume(s, t0.Add(3*time.Hour)))
+	answer(t, e, s, "bil", t0.Add(3*time.Hour+2*time.Second))
+	assert.Equal(t, int64(5000), s.Words[1].TimeSpentMs)
+}
+
+func TestExpiry(t *testing.T) {
+	e := newEngine()
+	s, err := e.Start(newWordSet(nil, "hus"), "child-1", "family-1", "", t0)
+	require.NoError(t, err)
+
+	// Activity pushes expiry forward
+	answer(t, e, s, "hs", t0.Add(23*time.Hour))
+	assert.Equal(t, t0.Add(47*time.Hour), s.ExpiresAt)
+
+	_, err = e.Answer(s, models.RecordAnswerRequest{Answer: "hus"}, t0.Add(48*time.Hour))
+	assert.ErrorIs(t, err, ErrSessionExpired)
+	assert.ErrorIs(t, e.Resume(s, t0.Add(48*time.Hour)), ErrSessionExpired)
+}
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "katten sover", Normalize("  Katten   sover. "))
+	assert.Equal(t, "blåbærsyltetøy", Normalize("«Blåbærsyltetøy»"))
+	assert.Equal(t, Normalize("sjø-mann"), Normalize("sjømann"))
+}
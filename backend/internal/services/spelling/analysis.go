@@ -0,0 +1,277 @@
+package spelling
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Error types detected by ErrorTypes. They match the frontend's
+// spellingAnalysis so results graded on either side aggregate together.
+const (
+	ErrorCompound        = "compound"
+	ErrorDoubleConsonant = "doubleConsonant"
+	ErrorSilentH         = "silentH"
+	ErrorSilentD         = "silentD"
+	ErrorSilentG         = "silentG"
+	ErrorSilentV         = "silentV"
+	ErrorSilentT         = "silentT"
+	ErrorKjSkjSj         = "kjSkjSj"
+	ErrorGjHjJ           = "gjHjJ"
+	ErrorVowelAeE        = "vowelAeE"
+	ErrorDiphthong       = "diphthong"
+	ErrorRetroflex       = "retroflex"
+	ErrorVelarNg         = "velarNg"
+	ErrorTransposition   = "transposition"
+	ErrorKeyboardTypo    = "keyboardTypo"
+	ErrorMissingLetter   = "missingLetter"
+	ErrorExtraLetter     = "extraLetter"
+	ErrorAlmostCorrect   = "almostCorrect"
+)
+
+// AlmostCorrectThreshold is the edit distance still counted as almost correct
+const AlmostCorrectThreshold = 2
+
+var doubleConsonants = []string{"bb", "dd", "ff", "gg", "kk", "ll", "mm", "nn", "pp", "rr", "ss", "tt"}
+
+// silentPatterns are checked in order; the first one the answer misses wins
+var silentPatterns = []struct {
+	errorType string
+	matches   func(string) bool
+}{
+	{ErrorSilentH, func(s string) bool { return strings.HasPrefix(s, "hv") || strings.HasPrefix(s, "hj") }},
+	{ErrorSilentD, func(s string) bool { return hasAnySuffix(s, "ld", "nd", "rd", "gd") }},
+	{ErrorSilentG, func(s string) bool { return strings.HasSuffix(s, "ig") }},
+	{ErrorSilentV, func(s string) bool { return strings.HasSuffix(s, "lv") }},
+	{ErrorSilentT, func(s string) bool { return strings.HasSuffix(s, "et") }},
+}
+
+// qwertyNeighbors lists the adjacent keys on a Norwegian keyboard
+var qwertyNeighbors = map[rune]string{
+	'q': "was", 'w': "qeasd", 'e': "wrsdf", 'r': "etdfg", 't': "ryfgh", 'y': "tughj",
+	'u': "yihjk", 'i': "uojkl", 'o': "ipklø", 'p': "oåløæ", 'å': "pøæ",
+	'a': "qwszx", 's': "qweadzxc", 'd': "wersfxcv", 'f': "ertdgcvb", 'g': "rtyfhvbn",
+	'h': "tyugjbnm", 'j': "yuihknm", 'k': "uiojlm", 'l': "iopkø", 'ø': "opålæ", 'æ': "påø",
+	'z': "asx", 'x': "zsdc", 'c': "xdfv", 'v': "cfgb", 'b': "vghn", 'n': "bhjm", 'm': "njk",
+}
+
+var (
+	nonLetters  = regexp.MustCompile(`[^a-zæøå]`)
+	retroflexS  = regexp.MustCompile(`([^r])s`)
+	whitespaces = regexp.MustCompile(`\s+`)
+)
+
+// Distance is the case-insensitive Levenshtein distance between a and b, in runes
+func Distance(a, b string) int {
+	return levenshtein([]rune(strings.ToLower(a)), []rune(strings.ToLower(b)))
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			if a[i-1] == b[j-1] {
+				curr[j] = prev[j-1]
+			} else {
+				curr[j] = 1 + min(prev[j-1], prev[j], curr[j-1])
+			}
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// ErrorTypes classifies how answer differs from expected, most relevant first.
+// It is a port of analyzeSpelling in the frontend.
+func ErrorTypes(answer, expected string) []string {
+	user := strings.ToLower(answer)
+	exp := strings.ToLower(expected)
+
+	var types []string
+	add := func(ok bool, errorType string) {
+		if ok {
+			types = append(types, errorType)
+		}
+	}
+
+	add(isCompoundError(user, exp), ErrorCompound)
+	add(isDoubleConsonantError(user, exp), ErrorDoubleConsonant)
+	if silent := silentLetterError(user, exp); silent != "" {
+		types = append(types, silent)
+	}
+	add(isVariantConfusion(user, exp, "kj", "skj", "sj"), ErrorKjSkjSj)
+	add(isGjHjJError(user, exp), ErrorGjHjJ)
+	add(isVowelAeEError(user, exp), ErrorVowelAeE)
+	add(isDiphthongError(user, exp), ErrorDiphthong)
+	add(isRetroflexError(user, exp), ErrorRetroflex)
+	add(isVelarNgError(user, exp), ErrorVelarNg)
+	add(isTransposition(user, exp), ErrorTransposition)
+	add(isKeyboardTypo(user, exp), ErrorKeyboardTypo)
+
+	userLen, expLen := len([]rune(answer)), len([]rune(expected))
+	if len(types) == 0 && userLen < expLen {
+		types = append(types, ErrorMissingLetter)
+	} else if len(types) == 0 && userLen > expLen {
+		types = append(types, ErrorExtraLetter)
+	}
+
+	if len(types) > 0 && Distance(answer, expected) <= AlmostCorrectThreshold {
+		types = append(types, ErrorAlmostCorrect)
+	}
+	return types
+}
+
+// isCompoundError detects a compound word written apart ("fotball" as "fot ball")
+func isCompoundError(user, exp string) bool {
+	user = strings.TrimSpace(user)
+	if strings.Contains(user, " ") && !strings.Contains(exp, " ") {
+		return whitespaces.ReplaceAllString(user, "") == exp
+	}
+	return false
+}
+
+func isDoubleConsonantError(user, exp string) bool {
+	for _, dc := range doubleConsonants {
+		inExp, inUser := strings.Contains(exp, dc), strings.Contains(user, dc)
+		if inExp && !inUser {
+			single := strings.Replace(exp, dc, dc[:1], 1)
+			if single == user || Distance(single, user) < Distance(exp, user) {
+				return true
+			}
+		}
+		// Over-correction: a double where a single was expected
+		if !inExp && inUser {
+			return true
+		}
+	}
+	return false
+}
+
+func silentLetterError(user, exp string) string {
+	for _, p := range silentPatterns {
+		if p.matches(exp) && !p.matches(user) {
+			cleanUser := nonLetters.ReplaceAllString(user, "")
+			cleanExp := nonLetters.ReplaceAllString(exp, "")
+			if abs(len([]rune(cleanUser))-len([]rune(cleanExp))) <= 2 {
+				return p.errorType
+			}
+		}
+	}
+	return ""
+}
+
+// isVariantConfusion detects one spelling of a sound written as another variant
+func isVariantConfusion(user, exp string, variants ...string) bool {
+	for _, v1 := range variants {
+		for _, v2 := range variants {
+			if v1 != v2 && strings.Contains(exp, v1) && strings.Contains(user, v2) && !strings.Contains(user, v1) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isGjHjJError(user, exp string) bool {
+	variants := []string{"gj", "hj", "j"}
+	if isVariantConfusion(user, exp, variants...) {
+		return true
+	}
+	for _, v1 := range variants {
+		for _, v2 := range variants {
+			if v1 != v2 && strings.HasPrefix(exp, v1) && strings.HasPrefix(user, v2) {
+				return true
+			}
+		}
+	}
+	// A gj/hj added where only j was expected
+	if strings.HasPrefix(exp, "j") {
+		return strings.HasPrefix(user, "gj") || strings.HasPrefix(user, "hj")
+	}
+	return false
+}
+
+func isVowelAeEError(user, exp string) bool {
+	swapped := strings.ReplaceAll(strings.ReplaceAll(user, "æ", "e"), "e", "æ")
+	if Distance(swapped, exp) < Distance(user, exp)-1 {
+		return true
+	}
+	u, e := []rune(user), []rune(exp)
+	for i := 0; i < min(len(u), len(e)); i++ {
+		if (u[i] == 'æ' && e[i] == 'e') || (u[i] == 'e' && e[i] == 'æ') {
+			return true
+		}
+	}
+	return false
+}
+
+func isDiphthongError(user, exp string) bool {
+	return (strings.Contains(exp, "ei") && strings.Contains(user, "ai") && !strings.Contains(user, "ei")) ||
+		(strings.Contains(exp, "ai") && strings.Contains(user, "ei") && !strings.Contains(user, "ai"))
+}
+
+// isRetroflexError detects rs (pronounced like sj) written as s or sj
+func isRetroflexError(user, exp string) bool {
+	if !strings.Contains(exp, "rs") || !strings.Contains(user, "s") {
+		return false
+	}
+	withRs := retroflexS.ReplaceAllString(strings.Replace(user, "sj", "rs", 1), "${1}rs")
+	return Distance(withRs, exp) < Distance(user, exp)
+}
+
+func isVelarNgError(user, exp string) bool {
+	if strings.Contains(user, "ngg") && strings.Contains(exp, "ng") && !strings.Contains(exp, "ngg") {
+		return true
+	}
+	return !strings.Contains(exp, "ng") && strings.Contains(user, "ng")
+}
+
+// isTransposition detects one or two pairs of adjacent letters swapped
+func isTransposition(user, exp string) bool {
+	u, e := []rune(user), []rune(exp)
+	if len(u) != len(e) || user == exp {
+		return false
+	}
+	swaps := 0
+	for i := 0; i < len(u)-1; i++ {
+		if u[i] == e[i+1] && u[i+1] == e[i] && u[i] != e[i] {
+			swaps++
+		}
+	}
+	return swaps > 0 && swaps <= 2
+}
+
+// isKeyboardTypo detects one or two letters replaced by a neighbouring key
+func isKeyboardTypo(user, exp string) bool {
+	u, e := []rune(user), []rune(exp)
+	if abs(len(u)-len(e)) > 1 {
+		return false
+	}
+	adjacent := 0
+	for i := 0; i < min(len(u), len(e)); i++ {
+		if u[i] != e[i] && strings.ContainsRune(qwertyNeighbors[u[i]], e[i]) {
+			adjacent++
+		}
+	}
+	return adjacent > 0 && adjacent <= 2
+}
+
+func hasAnySuffix(s string, suffixes ...string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,40 @@
+package spelling
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistance(t *testing.T) {
+	assert.Equal(t, 0, Distance("Båt", "båt"))
+	assert.Equal(t, 1, Distance("bat", "båt"), "distance counts runes, not bytes")
+	assert.Equal(t, 3, Distance("", "sjø"))
+	assert.Equal(t, 2, Distance("skjære", "sjere"))
+}
+
+func TestErrorTypes(t *testing.T) {
+	tests := []struct {
+		answer, expected string
+		want             []string
+	}{
+		{"fot ball", "fotball", []string{ErrorCompound, ErrorAlmostCorrect}},
+		{"mane", "manne", []string{ErrorDoubleConsonant, ErrorAlmostCorrect}},
+		{"vit", "hvit", []string{ErrorSilentH, ErrorAlmostCorrect}},
+		{"lan", "land", []string{ErrorSilentD, ErrorAlmostCorrect}},
+		{"sjære", "skjære", []string{ErrorKjSkjSj, ErrorKeyboardTypo, ErrorAlmostCorrect}},
+		{"jerne", "gjerne", []string{ErrorGjHjJ, ErrorKeyboardTypo, ErrorAlmostCorrect}},
+		{"sel", "sæl", []string{ErrorVowelAeE, ErrorAlmostCorrect}},
+		{"vai", "vei", []string{ErrorDiphthong, ErrorAlmostCorrect}},
+		{"tnek", "tenk", []string{ErrorTransposition, ErrorAlmostCorrect}},
+		{"sangg", "sang", []string{ErrorDoubleConsonant, ErrorVelarNg, ErrorAlmostCorrect}},
+		{"bil", "bik", []string{ErrorKeyboardTypo, ErrorAlmostCorrect}},
+		{"bok", "bokstav", []string{ErrorMissingLetter}},
+		{"hus", "hus", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.want, ErrorTypes(tt.answer, tt.expected))
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package spelling
+
+import (
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/starefossen/diktator/backend/internal/models"
+)
+
+// punctuation is removed before comparing words when IgnorePunctuation is set
+const punctuation = `.,!?;:'"()[]{}«»–—-`
+
+// misspelledCredit is the partial credit for a word that is close to the target
+const misspelledCredit = 0.5
+
+// SentenceOptions controls how a sentence answer is compared with the target
+type SentenceOptions struct {
+	CloseEnough       int  // Edit distance at which a wrong word counts as misspelled instead of missing
+	IgnoreCase        bool // Compare words case-insensitively
+	IgnorePunctuation bool // Strip punctuation before comparing
+}
+
+// DefaultSentenceOptions returns the options the frontend grades sentences with
+func DefaultSentenceOptions() SentenceOptions {
+	return SentenceOptions{
+		CloseEnough:       1,
+		IgnoreCase:        true,
+		IgnorePunctuation: true,
+	}
+}
+
+// SentenceOptionsFor reads the ignoreCase and ignorePunctuation settings from a
+// word set's test configuration, keeping the defaults for missing keys
+func SentenceOptionsFor(ws *models.WordSet) SentenceOptions {
+	opts := DefaultSentenceOptions()
+	if ws == nil || ws.TestConfiguration == nil {
+		return opts
+	}
+	config := *ws.TestConfiguration
+	if v, ok := config["ignoreCase"].(bool); ok {
+		opts.IgnoreCase = v
+	}
+	if v, ok := config["ignorePunctuation"].(bool); ok {
+		opts.IgnorePunctuation = v
+	}
+	return opts
+}
+
+// FocusWordsFor returns the focus words of a sentence in the word set, if any
+func FocusWordsFor(ws *models.WordSet, sentence string) []string {
+	if ws == nil {
+		return nil
+	}
+	sentence = strings.TrimSpace(sentence)
+	for _, s := range ws.Sentences {
+		if strings.TrimSpace(s.Sentence) == sentence {
+			return s.FocusWords
+		}
+	}
+	return nil
+}
+
+// IsSentence reports whether text has more than one word
+func IsSentence(text string) bool {
+	return len(strings.Fields(text)) > 1
+}
+
+// Tokenize splits text into the words compared when scoring a sentence
+func Tokenize(text string, opts SentenceOptions) []string {
+	if opts.IgnoreCase {
+		text = strings.ToLower(text)
+	}
+	if opts.IgnorePunctuation {
+		text = StripPunctuation(text)
+	}
+	return strings.Fields(text)
+}
+
+// StripPunctuation removes the punctuation ignored when comparing answers
+func StripPunctuation(text string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(punctuation, r) {
+			return -1
+		}
+		return r
+	}, text)
+}
+
+// ScoreSentence aligns the child's answer with the expected sentence word by word.
+// Words in the same order are matched exactly first; the remaining expected words
+// are then paired with the closest unmatched answer word within opts.CloseEnough.
+// Exact words in order earn full credit; misspelled and out-of-order words earn half. Error types are detected
+// for every misspelled word and collected separately for the focus words.
+func ScoreSentence(expected, answer string, focusWords []string, opts SentenceOptions) models.SentenceScore {
+	exp := Tokenize(expected, opts)
+	act := Tokenize(answer, opts)
+
+	if len(exp) == 0 {
+		correct := len(act) == 0
+		score := models.SentenceScore{TotalExpected: 1, FullyCorrect: correct, Words: []models.SentenceWordResult{}}
+		if correct {
+			score.CorrectCount, score.Score, score.OrderAccuracy = 1, 100, 1
+		}
+		return score
+	}
+
+	focus := focusSet(focusWords, opts)
+	lcs := longestCommonSubsequence(exp, act)
+	pairs := align(exp, act, lcs, opts.CloseEnough)
+
+	score := models.SentenceScore{
+		TotalExpected: len(exp),
+		OrderAccuracy: float64(len(lcs)) / float64(len(exp)),
+		Words:         make([]models.SentenceWordResult, 0, len(exp)),
+	}
+	inOrder := make(map[int]bool, len(lcs))
+	for _, m := range lcs {
+		inOrder[m[0]] = true
+	}
+	usedAnswer := make([]bool, len(act))
+	points := 0.0
+	for i, word := range exp {
+		w := models.SentenceWordResult{Word: word, ExpectedPosition: i, UserPosition: -1, Focus: focus[word]}
+		j, matched := pairs[i]
+		switch {
+		case !matched:
+			w.Status = models.SentenceWordMissing
+		case inOrder[i]:
+			w.Status, w.UserPosition, w.Credit = models.SentenceWordCorrect, j, 1
+			score.CorrectCount++
+		case act[j] == word:
+			w.Status, w.UserPosition, w.Credit = models.SentenceWordMisplaced, j, misspelledCredit
+			score.CloseCount++
+		default:
+			w.Status, w.UserPosition, w.Credit = models.SentenceWordMisspelled, j, misspelledCredit
+			w.UserWord = act[j]
+			w.ErrorTypes = ErrorTypes(act[j], word)
+			score.CloseCount++
+		}
+		if matched {
+			usedAnswer[j] = true
+		}
+		points += w.Credit
+		addFocus(&score, w)
+		score.Words = append(score.Words, w)
+	}
+
+	extra := 0
+	for j, word := range act {
+		if !usedAnswer[j] {
+			extra++
+			score.Words = append(score.Words, models.SentenceWordResult{
+				Word:             word,
+				Status:           models.SentenceWordExtra,
+				ExpectedPosition: -1,
+				UserPosition:     j,
+			})
+		}
+	}
+
+	score.Score = math.Round(points / float64(len(exp)) * 100)
+	score.FullyCorrect = score.CorrectCount == len(exp) && extra == 0
+	return score
+}
+
+// focusSet returns the tokens of the focus words
+func focusSet(focusWords []string, opts SentenceOptions) map[string]bool {
+	focus := make(map[string]bool)
+	for _, w := range focusWords {
+		for _, token := range Tokenize(w, opts) {
+			focus[token] = true
+		}
+	}
+	return focus
+}
+
+// addFocus counts a focus word and collects its error types
+func addFocus(score *models.SentenceScore, w models.SentenceWordResult) {
+	if !w.Focus {
+		return
+	}
+	score.FocusTotal++
+	if w.Status == models.SentenceWordCorrect {
+		score.FocusCorrect++
+	}
+	for _, et := range w.ErrorTypes {
+		if !slices.Contains(score.FocusErrorTypes, et) {
+			score.FocusErrorTypes = append(score.FocusErrorTypes, et)
+		}
+	}
+}
+
+// align pairs expected word indexes with answer word indexes: the exact in-order
+// matches first, then the closest remaining answer word within threshold
+func align(exp, act []string, lcs [][2]int, threshold int) map[int]int {
+	pairs := make(map[int]int, len(exp))
+	used := make([]bool, len(act))
+	for _, m := range lcs {
+		pairs[m[0]] = m[1]
+		used[m[1]] = true
+	}
+
+	for i, word := range exp {
+		if _, ok := pairs[i]; ok {
+			continue
+		}
+		best, bestDistance := -1, math.MaxInt
+		for j, candidate := range act {
+			if used[j] {
+				continue
+			}
+			if d := levenshtein([]rune(word), []rune(candidate)); d < bestDistance {
+				best, bestDistance = j, d
+			}
+		}
+		if best >= 0 && bestDistance <= threshold {
+			pairs[i] = best
+			used[best] = true
+		}
+	}
+	return pairs
+}
+
+// longestCommonSubsequence returns the [expected, actual] index pairs of the
+// words that appear in both slices in the same order
+func longestCommonSubsequence(exp, act []string) [][2]int {
+	m, n := len(exp), len(act)
+	dp := make([][]int, m+1)
+	for i := range dp {
+		dp[i] = make([]int, n+1)
+	}
+	for i := 1; i <= m; i++ {
+		for j := 1; j <= n; j++ {
+			if exp[i-1] == act[j-1] {
+				dp[i][j] = dp[i-1][j-1] + 1
+			} else {
+				dp[i][j] = max(dp[i-1][j], dp[i][j-1])
+			}
+		}
+	}
+
+	var pairs [][2]int
+	for i, j := m, n; i > 0 && j > 0; {
+		switch {
+		case exp[i-1] == act[j-1]:
+			pairs = append(pairs, [2]int{i - 1, j - 1})
+			i--
+			j--
+		case dp[i-1][j] > dp[i][j-1]:
+			i--
+		default:
+			j--
+		}
+	}
+	slices.Reverse(pairs)
+	return pairs
+}
+
+// GradeSentences scores every sentence in a test's word results against its word
+// set. The score is stored on the word result and the focus word error types are
+// added to its ErrorTypes, so sentence mistakes show up in the error analytics.
+// Single words are left as they are. The word set may be nil, in which case no
+// words are in focus and the default options apply.
+func GradeSentences(ws *models.WordSet, words []models.WordTestResult) {
+	opts := SentenceOptionsFor(ws)
+	for i := range words {
+		w := &words[i]
+		if !IsSentence(w.Word) {
+			continue
+		}
+		answer := w.FinalAnswer
+		if answer == "" && len(w.UserAnswers) > 0 {
+			answer = w.UserAnswers[len(w.UserAnswers)-1]
+		}
+		score := ScoreSentence(w.Word, answer, FocusWordsFor(ws, w.Word), opts)
+		w.Sentence = &score
+		for _, et := range score.FocusErrorTypes {
+			if !slices.Contains(w.ErrorTypes, et) {
+				w.ErrorTypes = append(w.ErrorTypes, et)
+			}
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package spelling
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/starefossen/diktator/backend/internal/models"
+)
+
+func statuses(score models.SentenceScore) []string {
+	out := make([]string, len(score.Words))
+	for i, w := range score.Words {
+		out[i] = w.Word + ":" + w.Status
+	}
+	return out
+}
+
+func TestTokenize(t *testing.T) {
+	assert.Equal(t, []string{"hei", "sa", "åse"}, Tokenize("  Hei, sa «Åse»! ", DefaultSentenceOptions()))
+	assert.Equal(t, []string{"Hei,", "sa", "«Åse»!"}, Tokenize("Hei, sa «Åse»!", SentenceOptions{}))
+}
+
+func TestStripPunctuation(t *testing.T) {
+	assert.Equal(t, "Hei sa Åse  ja", StripPunctuation(`Hei, sa «Åse» – "ja"!`))
+	assert.Equal(t, "ordbok", StripPunctuation("ord-bok"))
+}
+
+func TestScoreSentence(t *testing.T) {
+	opts := DefaultSentenceOptions()
+
+	t.Run("FullyCorrect", func(t *testing.T) {
+		score := ScoreSentence("Katten sover på stolen.", "katten sover på stolen", nil, opts)
+		assert.True(t, score.FullyCorrect)
+		assert.Equal(t, 100.0, score.Score)
+		assert.Equal(t, 4, score.CorrectCount)
+		assert.Equal(t, 1.0, score.OrderAccuracy)
+	})
+
+	t.Run("PartialCredit", func(t *testing.T) {
+		score := ScoreSentence("Hunden løper etter katten i parken.", "hunen løper etter i parken fort",
+			[]string{"Hunden", "katten"}, opts)
+
+		assert.Equal(t, []string{
+			"hunden:misspelled", "løper:correct", "etter:correct", "katten:missing",
+			"i:correct", "parken:correct", "fort:extra",
+		}, statuses(score))
+		assert.Equal(t, 4, score.CorrectCount)
+		assert.Equal(t, 1, score.CloseCount)
+		assert.Equal(t, 6, score.TotalExpected)
+		assert.Equal(t, 75.0, score.Score, "(4 + 0.5) / 6")
+		assert.False(t, score.FullyCorrect)
+
+		hunden := score.Words[0]
+		assert.Equal(t, "hunen", hunden.UserWord)
+		assert.Equal(t, 0, hunden.UserPosition)
+		assert.Equal(t, 0.5, hunden.Credit)
+		assert.True(t, hunden.Focus)
+		assert.Equal(t, 2, score.FocusTotal)
+		assert.Equal(t, 0, score.FocusCorrect)
+		assert.Equal(t, []string{ErrorKeyboardTypo, ErrorAlmostCorrect}, score.FocusErrorTypes)
+
+		extra := score.Words[6]
+		assert.Equal(t, -1, extra.ExpectedPosition)
+		assert.Equal(t, 5, extra.UserPosition)
+	})
+
+	t.Run("WordOrder", func(t *testing.T) {
+		score := ScoreSentence("Jeg liker is", "liker jeg is", nil, opts)
+		assert.Equal(t, []string{"jeg:misplaced", "liker:correct", "is:correct"}, statuses(score))
+		assert.Equal(t, 83.0, score.Score)
+		assert.InDelta(t, 2.0/3, score.OrderAccuracy, 0.001)
+	})
+
+	t.Run("CaseAndPunctuation", func(t *testing.T) {
+		strict := SentenceOptions{CloseEnough: 1}
+		score := ScoreSentence("Mor lager middag.", "mor lager middag", []string{"Mor"}, strict)
+		assert.Equal(t, []string{"Mor:misspelled", "lager:correct", "middag.:misspelled"}, statuses(score))
+		assert.Equal(t, 1, score.FocusTotal)
+	})
+
+	t.Run("EmptyAnswer", func(t *testing.T) {
+		score := ScoreSentence("Ballen er rød.", "", nil, opts)
+		assert.Equal(t, 0.0, score.Score)
+		assert.Equal(t, []string{"ballen:missing", "er:missing", "rød:missing"}, statuses(score))
+	})
+}
+
+func TestGradeSentences(t *testing.T) {
+	config := map[string]interface{}{"ignoreCase": false}
+	ws := &models.WordSet{
+		TestConfiguration: &config,
+		Sentences: []models.SentenceItem{
+			{Sentence: "Jeg ser en hvit båt.", FocusWords: []string{"hvit"}},
+		},
+	}
+	words := []models.WordTestResult{
+		{Word: "båt", FinalAnswer: "bot"},
+		{Word: "Jeg ser en hvit båt.", UserAnswers: []string{"jeg ser en vit båt"}, ErrorTypes: []string{"almostCorrect"}},
+	}
+
+	GradeSentences(ws, words)
+
+	assert.Nil(t, words[0].Sentence, "single words are not graded as sentences")
+	require.NotNil(t, words[1].Sentence)
+	s := words[1].Sentence
+	assert.Equal(t, models.SentenceWordMisspelled, s.Words[0].Status, "case matters for this word set")
+	assert.Equal(t, models.SentenceWordMisspelled, s.Words[3].Status)
+	assert.Equal(t, []string{ErrorSilentH, ErrorAlmostCorrect}, s.FocusErrorTypes)
+	assert.Equal(t, []string{"almostCorrect", ErrorSilentH}, words[1].ErrorTypes)
+}
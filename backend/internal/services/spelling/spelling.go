@@ -0,0 +1,130 @@
+// Package spelling holds Norwegian spelling helpers shared by the test modes.
+//
+// DetectChallenge mirrors detectSpellingChallenge in the frontend's
+// MissingLettersInput so printed worksheets blank the same letters the child
+// sees on screen.
+package spelling
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Blank is the placeholder used for a missing letter
+const Blank = "_"
+
+// Challenge types returned by DetectChallenge
+const (
+	ChallengeDoubleConsonant = "doubleConsonant"
+	ChallengeSilentH         = "silentH"
+	ChallengeSilentD         = "silentD"
+	ChallengeNorwegianChar   = "norwegianChar"
+	ChallengeSkjSound        = "skjSound"
+	ChallengeVowel           = "vowel"
+)
+
+const vowels = "aeiouyæøå"
+
+// Challenge is a word with some of its letters blanked out
+type Challenge struct {
+	BlankedWord    string `json:"blankedWord"`    // e.g. "ma__" for "mann"
+	MissingLetters string `json:"missingLetters"` // e.g. "nn"
+	ChallengeType  string `json:"challengeType"`
+	Positions      []int  `json:"positions"` // Rune indexes of the blanks
+}
+
+// BlankWord replaces the runes at the given positions with Blank.
+// Positions outside the word are ignored.
+func BlankWord(word string, positions []int) Challenge {
+	runes := []rune(word)
+	var blanked, missing strings.Builder
+	blank := make(map[int]bool, len(positions))
+	kept := make([]int, 0, len(positions))
+	for _, pos := range positions {
+		if pos >= 0 && pos < len(runes) && !blank[pos] {
+			blank[pos] = true
+			kept = append(kept, pos)
+			missing.WriteRune(runes[pos])
+		}
+	}
+	for i, r := range runes {
+		if blank[i] {
+			blanked.WriteString(Blank)
+		} else {
+			blanked.WriteRune(r)
+		}
+	}
+	return Challenge{
+		BlankedWord:    blanked.String(),
+		MissingLetters: missing.String(),
+		Positions:      kept,
+	}
+}
+
+// DetectChallenge picks the letters to blank for a missing-letters exercise,
+// looking for the most typical Norwegian spelling difficulty in the word.
+// It returns false for words without any vowel.
+func DetectChallenge(word string) (Challenge, bool) {
+	lower := []rune(strings.ToLower(word))
+	n := len(lower)
+
+	// Double consonants (nn, ll, mm, tt, ss, etc.)
+	for i := 0; i+1 < n; i++ {
+		if lower[i] == lower[i+1] {
+			return challenge(word, ChallengeDoubleConsonant, i, i+1), true
+		}
+	}
+
+	// Silent h at start (hj-, hv-)
+	if hasPrefix(lower, "hj") || hasPrefix(lower, "hv") {
+		return challenge(word, ChallengeSilentH, 0), true
+	}
+
+	// Silent d at end (-nd, -ld)
+	if n >= 2 && lower[n-1] == 'd' && (lower[n-2] == 'n' || lower[n-2] == 'l') {
+		return challenge(word, ChallengeSilentD, n-1), true
+	}
+
+	// Norwegian special characters (æ, ø, å)
+	if i := indexAny(lower, "æøå"); i >= 0 {
+		return challenge(word, ChallengeNorwegianChar, i), true
+	}
+
+	// Skj-sound variants
+	for _, prefix := range []string{"skj", "sj", "sk"} {
+		if hasPrefix(lower, prefix) {
+			positions := make([]int, utf8.RuneCountInString(prefix))
+			for i := range positions {
+				positions[i] = i
+			}
+			return challenge(word, ChallengeSkjSound, positions...), true
+		}
+	}
+
+	// Default: blank a vowel
+	if i := indexAny(lower, vowels); i >= 0 {
+		return challenge(word, ChallengeVowel, i), true
+	}
+
+	return Challenge{}, false
+}
+
+func challenge(word, challengeType string, positions ...int) Challenge {
+	c := BlankWord(word, positions)
+	c.ChallengeType = challengeType
+	return c
+}
+
+func hasPrefix(runes []rune, prefix string) bool {
+	return strings.HasPrefix(string(runes), prefix)
+}
+
+// indexAny returns the rune index of the first rune in chars, or -1
+func indexAny(runes []rune, chars string) int {
+	for i, r := range runes {
+		if strings.ContainsRune(chars, r) {
+			return i
+		}
+	}
+	return -1
+}
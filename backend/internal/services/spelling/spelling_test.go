@@ -0,0 +1,47 @@
+package spelling
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectChallenge(t *testing.T) {
+	tests := []struct {
+		word, blanked, missing, challengeType string
+	}{
+		{"mann", "ma__", "nn", ChallengeDoubleConsonant},
+		{"Hjelpe", "_jelpe", "H", ChallengeSilentH},
+		{"hvit", "_vit", "h", ChallengeSilentH},
+		{"land", "lan_", "d", ChallengeSilentD},
+		{"kald", "kal_", "d", ChallengeSilentD},
+		{"båt", "b_t", "å", ChallengeNorwegianChar},
+		{"skjære", "skj_re", "æ", ChallengeNorwegianChar},
+		{"skjorte", "___orte", "skj", ChallengeSkjSound},
+		{"sjø", "sj_", "ø", ChallengeNorwegianChar},
+		{"ski", "__i", "sk", ChallengeSkjSound},
+		{"bil", "b_l", "i", ChallengeVowel},
+		{"Tak", "T_k", "a", ChallengeVowel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			c, ok := DetectChallenge(tt.word)
+			assert.True(t, ok)
+			assert.Equal(t, tt.blanked, c.BlankedWord)
+			assert.Equal(t, tt.missing, c.MissingLetters)
+			assert.Equal(t, tt.challengeType, c.ChallengeType)
+		})
+	}
+
+	_, ok := DetectChallenge("brr")
+	assert.True(t, ok, "rr is a double consonant")
+	_, ok = DetectChallenge("pst")
+	assert.False(t, ok)
+}
+
+func TestBlankWord(t *testing.T) {
+	c := BlankWord("blåbær", []int{2, 4, 4, 10, -1})
+	assert.Equal(t, "bl_b_r", c.BlankedWord)
+	assert.Equal(t, "åæ", c.MissingLetters)
+	assert.Equal(t, []int{2, 4}, c.Positions)
+}
@@ -3,6 +3,7 @@ package tts
 
 import (
 	texttospeechpb "cloud.google.com/go/texttospeech/apiv1/texttospeechpb"
+	"context"
 	"github.com/starefossen/diktator/backend/internal/models"
 )
 
@@ -12,29 +13,29 @@ import (
 type Provider interface {
 	// GenerateAudio generates audio for a single word using text-to-speech
 	// Returns the audio data, audio file metadata, and any error
-	GenerateAudio(word, language string) ([]byte, *models.AudioFile, error)
+	GenerateAudio(ctx context.Context, word, language string) ([]byte, *models.AudioFile, error)
 
 	// GenerateSentenceAudio generates audio for a sentence using SSML with appropriate prosody
 	// Uses a slightly faster speaking rate (0.9x) than single words (0.8x) for natural flow
-	GenerateSentenceAudio(sentence, language string) ([]byte, *models.AudioFile, error)
+	GenerateSentenceAudio(ctx context.Context, sentence, language string) ([]byte, *models.AudioFile, error)
 
 	// GenerateTextAudio generates audio for any text, automatically detecting if it's a sentence
 	// and using the appropriate generation method (single word vs sentence)
-	GenerateTextAudio(text, language string) ([]byte, *models.AudioFile, error)
+	GenerateTextAudio(ctx context.Context, text, language string) ([]byte, *models.AudioFile, error)
 
 	// GenerateTextAudioWithFormat generates audio for any text with format control.
 	// If useMP3 is true, generates MP3 format for iOS Safari compatibility.
 	// Returns audio data, metadata, content type, and any error.
-	GenerateTextAudioWithFormat(text, language string, useMP3 bool) ([]byte, *models.AudioFile, string, error)
+	GenerateTextAudioWithFormat(ctx context.Context, text, language string, useMP3 bool) ([]byte, *models.AudioFile, string, error)
 
 	// GenerateAudioWithSSML generates audio using SSML markup for custom pronunciation
-	GenerateAudioWithSSML(ssml, language string) ([]byte, error)
+	GenerateAudioWithSSML(ctx context.Context, ssml, language string) ([]byte, error)
 
 	// GetOptimalVoiceForWord returns the best voice configuration for a specific word and language
 	GetOptimalVoiceForWord(word, language string) VoiceConfig
 
 	// GetChildFriendlyVoices returns available child-friendly voices for a language
-	GetChildFriendlyVoices(languageCode string) ([]*texttospeechpb.Voice, error)
+	GetChildFriendlyVoices(ctx context.Context, languageCode string) ([]*texttospeechpb.Voice, error)
 
 	// Close releases any resources held by the TTS service
 	Close() error
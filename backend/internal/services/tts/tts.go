@@ -4,23 +4,31 @@ import (
 	"context"
 	"crypto/md5" // #nosec G501 -- MD5 used for cache keys only, not cryptographic purposes
 	"fmt"
-	"log"
-	"os"
-	"strconv"
+	"log/slog"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	texttospeech "cloud.google.com/go/texttospeech/apiv1"
 	"cloud.google.com/go/texttospeech/apiv1/texttospeechpb"
+	"github.com/starefossen/diktator/backend/internal/config"
+	"github.com/starefossen/diktator/backend/internal/metrics"
 	"github.com/starefossen/diktator/backend/internal/models"
 	"github.com/starefossen/diktator/backend/internal/services/cache"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/api/option"
 )
 
+var tracer = otel.Tracer("github.com/starefossen/diktator/backend/internal/services/tts")
+
 // Service implements text-to-speech functionality using Google Cloud TTS API.
 type Service struct {
 	client *texttospeech.Client
-	ctx    context.Context
 	cache  *cache.LRUCache
+	log    *slog.Logger
 }
 
 // VoiceConfig defines the voice configuration for different languages
@@ -113,59 +121,68 @@ var DefaultVoices = map[string]VoiceConfig{
 	},
 }
 
-// NewService creates a new Text-to-Speech service
-func NewService() (*Service, error) {
+// NewService creates a new Text-to-Speech service. A nil logger uses slog.Default().
+func NewService(cfg config.TTS, logger *slog.Logger) (*Service, error) {
 	ctx := context.Background()
+	if logger == nil {
+		logger = slog.Default()
+	}
 
 	// Allow TTS to be optional for environments without Google Cloud credentials
-	if os.Getenv("DISABLE_TTS") == "true" {
-		log.Println("⚠️  TTS service disabled (DISABLE_TTS=true)")
+	if cfg.Disabled {
+		logger.Warn("TTS service disabled", "reason", "tts.disabled is set")
 		return &Service{
 			client: nil,
-			ctx:    ctx,
 			cache:  cache.NewLRUCache(1024 * 1024), // 1MB minimal cache
+			log:    logger,
 		}, nil
 	}
 
-	// Get quota project from environment
+	// Bill TTS usage to a separate quota project when configured
 	var clientOptions []option.ClientOption
-	if quotaProject := os.Getenv("TTS_QUOTA_PROJECT"); quotaProject != "" {
-		clientOptions = append(clientOptions, option.WithQuotaProject(quotaProject))
-		log.Printf("Using TTS quota project: %s", quotaProject)
+	if cfg.QuotaProject != "" {
+		clientOptions = append(clientOptions, option.WithQuotaProject(cfg.QuotaProject))
+		logger.Info("Using TTS quota project", "project", cfg.QuotaProject)
 	}
 
 	client, err := texttospeech.NewClient(ctx, clientOptions...)
 	if err != nil {
 		// If credentials are missing, log warning and continue without TTS
 		if strings.Contains(err.Error(), "credentials") || strings.Contains(err.Error(), "ADC") {
-			log.Println("⚠️  TTS service disabled (no TTS credentials found)")
+			logger.Warn("TTS service disabled", "reason", "no TTS credentials found")
 			return &Service{
 				client: nil,
-				ctx:    ctx,
 				cache:  cache.NewLRUCache(1024 * 1024),
+				log:    logger,
 			}, nil
 		}
 		return nil, fmt.Errorf("failed to create TTS client: %v", err)
 	}
 
-	// Get cache size from environment (default 15MB)
-	cacheSize := int64(15 * 1024 * 1024) // 15MB default
-	if cacheSizeStr := os.Getenv("TTS_CACHE_SIZE_MB"); cacheSizeStr != "" {
-		if size, err := strconv.ParseInt(cacheSizeStr, 10, 64); err == nil && size > 0 {
-			cacheSize = size * 1024 * 1024
-			log.Printf("TTS cache size set to %d MB", size)
-		}
-	} else {
-		log.Printf("TTS cache size set to default 15 MB")
-	}
+	cacheSize := int64(cfg.CacheSizeMB) * 1024 * 1024
+	logger.Info("TTS cache size set", "megabytes", cfg.CacheSizeMB)
 
 	return &Service{
 		client: client,
-		ctx:    ctx,
 		cache:  cache.NewLRUCache(cacheSize),
+		log:    logger,
 	}, nil
 }
 
+// Cache returns the cache of synthesized audio
+func (s *Service) Cache() *cache.LRUCache {
+	return s.cache
+}
+
+// CheckHealth reports whether speech synthesis is available. It does not call
+// the API, so probes cost nothing.
+func (s *Service) CheckHealth(context.Context) error {
+	if s.client == nil {
+		return fmt.Errorf("TTS service is disabled")
+	}
+	return nil
+}
+
 // Close closes the TTS client
 func (s *Service) Close() error {
 	if s.client != nil {
@@ -175,13 +192,13 @@ func (s *Service) Close() error {
 }
 
 // GenerateAudio generates audio for a word using Text-to-Speech with optimal voice selection
-func (s *Service) GenerateAudio(word, language string) ([]byte, *models.AudioFile, error) {
-	return s.GenerateAudioWithFormat(word, language, false)
+func (s *Service) GenerateAudio(ctx context.Context, word, language string) ([]byte, *models.AudioFile, error) {
+	return s.GenerateAudioWithFormat(ctx, word, language, false)
 }
 
 // GenerateAudioWithFormat generates TTS audio for a single word with format control.
 // If useMP3 is true, generates MP3 format for iOS Safari compatibility. Otherwise uses OGG Opus.
-func (s *Service) GenerateAudioWithFormat(word, language string, useMP3 bool) ([]byte, *models.AudioFile, error) {
+func (s *Service) GenerateAudioWithFormat(ctx context.Context, word, language string, useMP3 bool) ([]byte, *models.AudioFile, error) {
 	if s.client == nil {
 		return nil, nil, fmt.Errorf("TTS service is disabled")
 	}
@@ -203,7 +220,7 @@ func (s *Service) GenerateAudioWithFormat(word, language string, useMP3 bool) ([
 
 	// Check cache first
 	if cachedAudio, found := s.cache.Get(cacheKey); found {
-		log.Printf("Cache HIT for word '%s' in language '%s'", word, language)
+		s.log.DebugContext(ctx, "Cache hit for word audio", "word", word, "language", language)
 
 		// Generate filename for cached audio
 		filename := s.generateFilename(word, language, voiceConfig.VoiceName)
@@ -218,8 +235,8 @@ func (s *Service) GenerateAudioWithFormat(word, language string, useMP3 bool) ([
 		return cachedAudio, audioFile, nil
 	}
 
-	log.Printf("Cache MISS - Generating audio for word '%s' in language '%s' using voice '%s'",
-		word, language, voiceConfig.VoiceName)
+	s.log.DebugContext(ctx, "Cache miss, generating word audio",
+		"word", word, "language", language, "voice", voiceConfig.VoiceName)
 
 	// Create the synthesis input with word normalization
 	normalizedWord := s.normalizeTextForTTS(word)
@@ -254,17 +271,18 @@ func (s *Service) GenerateAudioWithFormat(word, language string, useMP3 bool) ([
 		AudioConfig: audioConfig,
 	}
 
-	resp, err := s.client.SynthesizeSpeech(s.ctx, req)
+	resp, err := s.synthesize(ctx, req)
 	if err != nil {
 		// Try fallback voice if the primary voice fails
 		fallbackConfig := s.getFallbackVoiceConfig(language)
 		if fallbackConfig.VoiceName != voiceConfig.VoiceName {
-			log.Printf("Primary voice failed, trying fallback voice: %s", fallbackConfig.VoiceName)
+			s.log.WarnContext(ctx, "Primary voice failed, trying fallback voice",
+				"voice", voiceConfig.VoiceName, "fallback", fallbackConfig.VoiceName, "error", err)
 			voice.Name = fallbackConfig.VoiceName
 			voice.LanguageCode = fallbackConfig.LanguageCode
 			req.Voice = voice
 
-			resp, err = s.client.SynthesizeSpeech(s.ctx, req)
+			resp, err = s.synthesize(ctx, req)
 			if err != nil {
 				return nil, nil, fmt.Errorf("failed to synthesize speech with both primary and fallback voices: %v", err)
 			}
@@ -291,14 +309,37 @@ func (s *Service) GenerateAudioWithFormat(word, language string, useMP3 bool) ([
 
 	// Log cache stats periodically
 	items, bytes, maxBytes := s.cache.Stats()
-	log.Printf("Generated audio for word '%s' in language '%s' | Cache: %d items, %.2f MB / %.2f MB",
-		word, language, items, float64(bytes)/(1024*1024), float64(maxBytes)/(1024*1024))
+	s.log.DebugContext(ctx, "Generated word audio", "word", word, "language", language,
+		"cache_items", items, "cache_bytes", bytes, "cache_max_bytes", maxBytes)
 
 	return resp.AudioContent, audioFile, nil
 }
 
+// synthesize sends a synthesis request and records its latency and billed characters
+func (s *Service) synthesize(ctx context.Context, req *texttospeechpb.SynthesizeSpeechRequest) (*texttospeechpb.SynthesizeSpeechResponse, error) {
+	voice := req.GetVoice().GetName()
+	characters := utf8.RuneCountInString(req.GetInput().GetText() + req.GetInput().GetSsml())
+	ctx, span := tracer.Start(ctx, "tts.SynthesizeSpeech",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("tts.voice", voice),
+			attribute.Int("tts.characters", characters),
+		),
+	)
+	defer span.End()
+
+	start := time.Now()
+	resp, err := s.client.SynthesizeSpeech(ctx, req)
+	metrics.ObserveSynthesis(voice, characters, time.Since(start), err)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return resp, err
+}
+
 // GenerateAudioWithSSML generates audio using SSML for custom pronunciation
-func (s *Service) GenerateAudioWithSSML(ssml, language string) ([]byte, error) {
+func (s *Service) GenerateAudioWithSSML(ctx context.Context, ssml, language string) ([]byte, error) {
 	voiceConfig := s.getOptimalVoiceConfig(language)
 
 	input := &texttospeechpb.SynthesisInput{
@@ -327,7 +368,7 @@ func (s *Service) GenerateAudioWithSSML(ssml, language string) ([]byte, error) {
 		AudioConfig: audioConfig,
 	}
 
-	resp, err := s.client.SynthesizeSpeech(s.ctx, req)
+	resp, err := s.synthesize(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to synthesize SSML speech: %v", err)
 	}
@@ -358,13 +399,13 @@ func GetWordCount(text string) int {
 
 // GenerateSentenceAudio generates audio for a sentence using SSML with appropriate prosody
 // Uses a slightly faster speaking rate (0.9x) than single words (0.8x) for natural flow
-func (s *Service) GenerateSentenceAudio(sentence, language string) ([]byte, *models.AudioFile, error) {
-	return s.GenerateSentenceAudioWithFormat(sentence, language, false)
+func (s *Service) GenerateSentenceAudio(ctx context.Context, sentence, language string) ([]byte, *models.AudioFile, error) {
+	return s.GenerateSentenceAudioWithFormat(ctx, sentence, language, false)
 }
 
 // GenerateSentenceAudioWithFormat generates TTS audio for a sentence with format control.
 // If useMP3 is true, generates MP3 format for iOS Safari compatibility. Otherwise uses OGG Opus.
-func (s *Service) GenerateSentenceAudioWithFormat(sentence, language string, useMP3 bool) ([]byte, *models.AudioFile, error) {
+func (s *Service) GenerateSentenceAudioWithFormat(ctx context.Context, sentence, language string, useMP3 bool) ([]byte, *models.AudioFile, error) {
 	if s.client == nil {
 		return nil, nil, fmt.Errorf("TTS service is disabled")
 	}
@@ -394,7 +435,7 @@ func (s *Service) GenerateSentenceAudioWithFormat(sentence, language string, use
 
 	// Check cache first
 	if cachedAudio, found := s.cache.Get(cacheKey); found {
-		log.Printf("Cache HIT for sentence in language '%s' (%d words)", language, wordCount)
+		s.log.DebugContext(ctx, "Cache hit for sentence audio", "language", language, "words", wordCount)
 
 		filename := s.generateSentenceFilename(sentence, language, voiceConfig.VoiceName)
 		audioFile := &models.AudioFile{
@@ -408,7 +449,7 @@ func (s *Service) GenerateSentenceAudioWithFormat(sentence, language string, use
 		return cachedAudio, audioFile, nil
 	}
 
-	log.Printf("Cache MISS - Generating audio for sentence in language '%s' (%d words)", language, wordCount)
+	s.log.DebugContext(ctx, "Cache miss, generating sentence audio", "language", language, "words", wordCount)
 
 	// Normalize the sentence for TTS
 	normalizedSentence := s.normalizeTextForTTS(sentence)
@@ -443,17 +484,18 @@ func (s *Service) GenerateSentenceAudioWithFormat(sentence, language string, use
 		AudioConfig: audioConfig,
 	}
 
-	resp, err := s.client.SynthesizeSpeech(s.ctx, req)
+	resp, err := s.synthesize(ctx, req)
 	if err != nil {
 		// Try fallback voice
 		fallbackConfig := s.getFallbackVoiceConfig(language)
 		if fallbackConfig.VoiceName != voiceConfig.VoiceName {
-			log.Printf("Primary voice failed for sentence, trying fallback: %s", fallbackConfig.VoiceName)
+			s.log.WarnContext(ctx, "Primary voice failed for sentence, trying fallback voice",
+				"voice", voiceConfig.VoiceName, "fallback", fallbackConfig.VoiceName, "error", err)
 			voice.Name = fallbackConfig.VoiceName
 			voice.LanguageCode = fallbackConfig.LanguageCode
 			req.Voice = voice
 
-			resp, err = s.client.SynthesizeSpeech(s.ctx, req)
+			resp, err = s.synthesize(ctx, req)
 			if err != nil {
 				return nil, nil, fmt.Errorf("failed to synthesize sentence with both primary and fallback voices: %v", err)
 			}
@@ -476,32 +518,32 @@ func (s *Service) GenerateSentenceAudioWithFormat(sentence, language string, use
 	s.cache.Put(cacheKey, resp.AudioContent)
 
 	items, bytes, maxBytes := s.cache.Stats()
-	log.Printf("Generated audio for sentence (%d words) in language '%s' | Cache: %d items, %.2f MB / %.2f MB",
-		wordCount, language, items, float64(bytes)/(1024*1024), float64(maxBytes)/(1024*1024))
+	s.log.DebugContext(ctx, "Generated sentence audio", "language", language, "words", wordCount,
+		"cache_items", items, "cache_bytes", bytes, "cache_max_bytes", maxBytes)
 
 	return resp.AudioContent, audioFile, nil
 }
 
 // GenerateTextAudio generates audio for any text, automatically detecting if it's a sentence
 // and using the appropriate generation method
-func (s *Service) GenerateTextAudio(text, language string) ([]byte, *models.AudioFile, error) {
+func (s *Service) GenerateTextAudio(ctx context.Context, text, language string) ([]byte, *models.AudioFile, error) {
 	if IsSentence(text) {
-		return s.GenerateSentenceAudio(text, language)
+		return s.GenerateSentenceAudio(ctx, text, language)
 	}
-	return s.GenerateAudio(text, language)
+	return s.GenerateAudio(ctx, text, language)
 }
 
 // GenerateTextAudioWithFormat generates audio for text (word or sentence) with format detection
 // and returns the appropriate content type. iOS Safari requires MP3, other browsers use OGG Opus.
-func (s *Service) GenerateTextAudioWithFormat(text, language string, useMP3 bool) ([]byte, *models.AudioFile, string, error) {
+func (s *Service) GenerateTextAudioWithFormat(ctx context.Context, text, language string, useMP3 bool) ([]byte, *models.AudioFile, string, error) {
 	var audioData []byte
 	var audioFile *models.AudioFile
 	var err error
 
 	if IsSentence(text) {
-		audioData, audioFile, err = s.GenerateSentenceAudioWithFormat(text, language, useMP3)
+		audioData, audioFile, err = s.GenerateSentenceAudioWithFormat(ctx, text, language, useMP3)
 	} else {
-		audioData, audioFile, err = s.GenerateAudioWithFormat(text, language, useMP3)
+		audioData, audioFile, err = s.GenerateAudioWithFormat(ctx, text, language, useMP3)
 	}
 
 	if err != nil {
@@ -551,12 +593,12 @@ func (s *Service) generateFilename(word, language, voiceID string) string {
 }
 
 // ListAvailableVoices lists available voices for a language
-func (s *Service) ListAvailableVoices(languageCode string) ([]*texttospeechpb.Voice, error) {
+func (s *Service) ListAvailableVoices(ctx context.Context, languageCode string) ([]*texttospeechpb.Voice, error) {
 	req := &texttospeechpb.ListVoicesRequest{
 		LanguageCode: languageCode,
 	}
 
-	resp, err := s.client.ListVoices(s.ctx, req)
+	resp, err := s.client.ListVoices(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list voices: %v", err)
 	}
@@ -565,8 +607,8 @@ func (s *Service) ListAvailableVoices(languageCode string) ([]*texttospeechpb.Vo
 }
 
 // GetChildFriendlyVoices returns available child-friendly voices for a language
-func (s *Service) GetChildFriendlyVoices(languageCode string) ([]*texttospeechpb.Voice, error) {
-	voices, err := s.ListAvailableVoices(languageCode)
+func (s *Service) GetChildFriendlyVoices(ctx context.Context, languageCode string) ([]*texttospeechpb.Voice, error) {
+	voices, err := s.ListAvailableVoices(ctx, languageCode)
 	if err != nil {
 		return nil, err
 	}
@@ -603,8 +645,8 @@ func (s *Service) GetChildFriendlyVoices(languageCode string) ([]*texttospeechpb
 }
 
 // ValidateVoice checks if a specific voice is available for a language
-func (s *Service) ValidateVoice(languageCode, voiceName string) (bool, error) {
-	voices, err := s.ListAvailableVoices(languageCode)
+func (s *Service) ValidateVoice(ctx context.Context, languageCode, voiceName string) (bool, error) {
+	voices, err := s.ListAvailableVoices(ctx, languageCode)
 	if err != nil {
 		return false, err
 	}
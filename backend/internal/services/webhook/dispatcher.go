@@ -0,0 +1,211 @@
+package webhook
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services/events"
+)
+
+// Repository defines the database operations needed by the dispatcher
+type Repository interface {
+	EnqueueWebhookDeliveries(ctx context.Context, familyID, eventID, eventType string, payload []byte) (int, error)
+	ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error)
+	UpdateWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+}
+
+// Config holds dispatcher configuration
+type Config struct {
+	PollInterval   time.Duration // How often the outbox is polled for due deliveries
+	RequestTimeout time.Duration // Per-request HTTP timeout
+	BaseBackoff    time.Duration // Delay before the first retry
+	MaxBackoff     time.Duration // Upper bound for retry delay
+	Lease          time.Duration // How long a claimed delivery is hidden from other dispatchers
+	MaxAttempts    int           // Attempts before a delivery is marked failed
+	BatchSize      int           // Deliveries claimed per poll
+	Logger         *slog.Logger  // Defaults to slog.Default()
+
+	// AllowPrivateNetworks permits loopback and private targets. Only for tests
+	// and local development; parents must never reach internal services.
+	AllowPrivateNetworks bool
+}
+
+// DefaultConfig returns sensible default configuration.
+// With these values a delivery is retried for roughly a day before giving up.
+func DefaultConfig() Config {
+	return Config{
+		PollInterval:   10 * time.Second,
+		RequestTimeout: 10 * time.Second,
+		BaseBackoff:    30 * time.Second,
+		MaxBackoff:     6 * time.Hour,
+		Lease:          time.Minute,
+		MaxAttempts:    10,
+		BatchSize:      20,
+	}
+}
+
+// maxErrorLength caps the stored error in the delivery log
+const maxErrorLength = 500
+
+// Dispatcher enqueues events into the outbox and delivers them over HTTP
+type Dispatcher struct {
+	repo   Repository
+	client *http.Client
+	now    func() time.Time
+	cfg    Config
+	log    *slog.Logger
+}
+
+// NewDispatcher creates a new webhook dispatcher
+func NewDispatcher(repo Repository, cfg Config) *Dispatcher {
+	return &Dispatcher{
+		repo:   repo,
+		client: newClient(cfg.RequestTimeout, cfg.AllowPrivateNetworks),
+		now:    time.Now,
+		cfg:    cfg,
+		log:    cmp.Or(cfg.Logger, slog.Default()),
+	}
+}
+
+// ValidateURL checks a webhook URL before it is stored. A nil dispatcher
+// applies the default rules.
+func (d *Dispatcher) ValidateURL(raw string) error {
+	return ValidateURL(raw, d != nil && d.cfg.AllowPrivateNetworks)
+}
+
+// HandleEvent implements events.Handler by writing the event to the outbox
+func (d *Dispatcher) HandleEvent(ctx context.Context, event events.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	if _, err := d.repo.EnqueueWebhookDeliveries(ctx, event.FamilyID, event.ID, string(event.Type), payload); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Run polls the outbox until the context is cancelled
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.ProcessDue(ctx); err != nil {
+			d.log.ErrorContext(ctx, "Failed to process webhook outbox", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessDue claims and attempts all currently due deliveries, returning how many were attempted
+func (d *Dispatcher) ProcessDue(ctx context.Context) (int, error) {
+	deliveries, err := d.repo.ClaimWebhookDeliveries(ctx, d.cfg.BatchSize, d.cfg.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range deliveries {
+		if ctx.Err() != nil {
+			// Unattempted deliveries become due again when their lease expires
+			return i, ctx.Err()
+		}
+		d.attempt(ctx, &deliveries[i])
+	}
+
+	return len(deliveries), nil
+}
+
+// attempt sends one delivery and records the outcome
+func (d *Dispatcher) attempt(ctx context.Context, delivery *models.WebhookDelivery) {
+	now := d.now()
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+
+	status, err := d.send(ctx, delivery, now)
+	if status != 0 {
+		delivery.ResponseStatus = &status
+	}
+
+	switch {
+	case err == nil:
+		delivery.Status = models.WebhookDeliveryDelivered
+		delivery.DeliveredAt = &now
+		delivery.LastError = nil
+	case delivery.Attempts >= d.cfg.MaxAttempts:
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.LastError = truncateError(err)
+	default:
+		delivery.Status = models.WebhookDeliveryPending
+		delivery.NextAttemptAt = now.Add(Backoff(d.cfg, delivery.Attempts))
+		delivery.LastError = truncateError(err)
+	}
+
+	if err := d.repo.UpdateWebhookDelivery(ctx, delivery); err != nil {
+		d.log.ErrorContext(ctx, "Failed to record webhook delivery outcome", "delivery_id", delivery.ID, "error", err)
+	}
+}
+
+// send performs the HTTP request, returning the response status (0 if none) and an error for non-2xx
+func (d *Dispatcher) send(ctx context.Context, delivery *models.WebhookDelivery, now time.Time) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("invalid webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Diktator-Webhooks/1.0")
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, delivery.ID)
+	req.Header.Set(HeaderSignature, Sign(delivery.Secret, now, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// The body is never stored: parents can read the delivery log, and the
+	// response of an internal service must not be echoed back to them
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp.StatusCode, nil
+	}
+	return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+}
+
+// Backoff returns the retry delay after the given number of failed attempts
+func Backoff(cfg Config, attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	delay := cfg.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= cfg.MaxBackoff {
+			return cfg.MaxBackoff
+		}
+	}
+	return delay
+}
+
+func truncateError(err error) *string {
+	msg := err.Error()
+	if len(msg) > maxErrorLength {
+		msg = msg[:maxErrorLength]
+	}
+	return &msg
+}
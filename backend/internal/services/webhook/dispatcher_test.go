@@ -0,0 +1,261 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/starefossen/diktator/backend/internal/services/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryOutbox is an in-memory Repository for dispatcher tests
+type memoryOutbox struct {
+	url        string
+	secret     string
+	deliveries []*models.WebhookDelivery
+	mu         sync.Mutex
+}
+
+func (m *memoryOutbox) EnqueueWebhookDeliveries(_ context.Context, familyID, eventID, eventType string, payload []byte) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deliveries = append(m.deliveries, &models.WebhookDelivery{
+		ID:            eventID + "-delivery",
+		WebhookID:     "hook-1",
+		EventID:       eventID,
+		EventType:     eventType,
+		Payload:       payload,
+		Status:        models.WebhookDeliveryPending,
+		NextAttemptAt: time.Time{},
+	})
+	return 1, nil
+}
+
+func (m *memoryOutbox) ClaimWebhookDeliveries(_ context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var claimed []models.WebhookDelivery
+	for _, d := range m.deliveries {
+		if d.Status == models.WebhookDeliveryPending && !d.NextAttemptAt.After(time.Now()) && len(claimed) < limit {
+			c := *d
+			c.URL = m.url
+			c.Secret = m.secret
+			claimed = append(claimed, c)
+		}
+	}
+	return claimed, nil
+}
+
+func (m *memoryOutbox) UpdateWebhookDelivery(_ context.Context, delivery *models.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, d := range m.deliveries {
+		if d.ID == delivery.ID {
+			updated := *delivery
+			updated.URL, updated.Secret = "", ""
+			m.deliveries[i] = &updated
+		}
+	}
+	return nil
+}
+
+func testConfig() Config {
+	cfg := DefaultConfig()
+	cfg.BaseBackoff = 50 * time.Millisecond
+	cfg.MaxBackoff = 100 * time.Millisecond
+	cfg.MaxAttempts = 3
+	cfg.AllowPrivateNetworks = true // httptest servers listen on loopback
+	return cfg
+}
+
+func TestSignVerify(t *testing.T) {
+	body := []byte(`{"type":"test.completed"}`)
+	now := time.Unix(1_700_000_000, 0)
+	header := Sign("whsec_abc", now, body)
+
+	assert.NoError(t, Verify("whsec_abc", header, body, 5*time.Minute, now.Add(time.Minute)))
+	assert.ErrorIs(t, Verify("whsec_other", header, body, 0, now), ErrInvalidSignature)
+	assert.ErrorIs(t, Verify("whsec_abc", header, []byte(`{}`), 0, now), ErrInvalidSignature)
+	assert.ErrorIs(t, Verify("whsec_abc", header, body, 5*time.Minute, now.Add(time.Hour)), ErrSignatureExpired)
+	assert.ErrorIs(t, Verify("whsec_abc", "garbage", body, 0, now), ErrInvalidSignature)
+}
+
+func TestBackoff(t *testing.T) {
+	cfg := Config{BaseBackoff: 30 * time.Second, MaxBackoff: 5 * time.Minute}
+
+	assert.Equal(t, 30*time.Second, Backoff(cfg, 1))
+	assert.Equal(t, time.Minute, Backoff(cfg, 2))
+	assert.Equal(t, 2*time.Minute, Backoff(cfg, 3))
+	assert.Equal(t, 5*time.Minute, Backoff(cfg, 5), "capped at MaxBackoff")
+	assert.Equal(t, 5*time.Minute, Backoff(cfg, 50))
+}
+
+func TestDispatcher_DeliversSignedPayload(t *testing.T) {
+	var gotHeaders http.Header
+	var gotBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeaders = r.Header.Clone()
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	outbox := &memoryOutbox{url: srv.URL, secret: "whsec_test"}
+	d := NewDispatcher(outbox, testConfig())
+
+	event := events.New(events.TypeTestCompleted, "family-1", "child-1", map[string]any{"score": 95.0})
+	require.NoError(t, d.HandleEvent(context.Background(), event))
+
+	n, err := d.ProcessDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	assert.Equal(t, "test.completed", gotHeaders.Get(HeaderEvent))
+	assert.NoError(t, Verify("whsec_test", gotHeaders.Get(HeaderSignature), gotBody, time.Minute, time.Now()))
+
+	var payload events.Event
+	require.NoError(t, json.Unmarshal(gotBody, &payload))
+	assert.Equal(t, event.ID, payload.ID)
+	assert.Equal(t, "family-1", payload.FamilyID)
+
+	delivered := outbox.deliveries[0]
+	assert.Equal(t, models.WebhookDeliveryDelivered, delivered.Status)
+	assert.Equal(t, 1, delivered.Attempts)
+	require.NotNil(t, delivered.ResponseStatus)
+	assert.Equal(t, http.StatusNoContent, *delivered.ResponseStatus)
+	assert.NotNil(t, delivered.DeliveredAt)
+}
+
+func TestDispatcher_RetriesWithBackoffThenFails(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.Error(w, "internal secret", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	outbox := &memoryOutbox{url: srv.URL, secret: "whsec_test"}
+	cfg := testConfig()
+	d := NewDispatcher(outbox, cfg)
+	require.NoError(t, d.HandleEvent(context.Background(), events.New(events.TypeLevelUp, "family-1", "child-1", nil)))
+
+	_, err := d.ProcessDue(context.Background())
+	require.NoError(t, err)
+
+	first := outbox.deliveries[0]
+	assert.Equal(t, models.WebhookDeliveryPending, first.Status)
+	assert.Equal(t, 1, first.Attempts)
+	require.NotNil(t, first.LastError)
+	assert.Equal(t, "unexpected status 500", *first.LastError, "the response body is never stored")
+	assert.WithinDuration(t, first.LastAttemptAt.Add(cfg.BaseBackoff), first.NextAttemptAt, time.Millisecond)
+
+	// Not due yet: nothing is claimed
+	n, err := d.ProcessDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	for outbox.deliveries[0].Status == models.WebhookDeliveryPending {
+		time.Sleep(cfg.MaxBackoff)
+		_, err := d.ProcessDue(context.Background())
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, models.WebhookDeliveryFailed, outbox.deliveries[0].Status)
+	assert.Equal(t, cfg.MaxAttempts, outbox.deliveries[0].Attempts)
+	assert.Equal(t, int32(cfg.MaxAttempts), calls.Load())
+}
+
+func TestIsPublicAddr(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"93.184.215.14":    true,
+		"2606:4700::1111":  true,
+		"127.0.0.1":        false,
+		"10.1.2.3":         false,
+		"172.16.0.1":       false,
+		"192.168.1.1":      false,
+		"169.254.169.254":  false,
+		"100.64.0.1":       false,
+		"0.0.0.0":          false,
+		"::1":              false,
+		"fd00::1":          false,
+		"fe80::1":          false,
+		"::ffff:127.0.0.1": false,
+		"64:ff9b::a00:1":   false,
+		"255.255.255.255":  false,
+		"224.0.0.1":        false,
+	} {
+		assert.Equal(t, want, IsPublicAddr(netip.MustParseAddr(addr)), addr)
+	}
+}
+
+func TestValidateURL(t *testing.T) {
+	for raw, ok := range map[string]bool{
+		"https://hooks.example.com/diktator": true,
+		"http://93.184.215.14/hook":          true,
+		"ftp://example.com/hook":             false,
+		"/relative":                          false,
+		"http://localhost:8080/hook":         false,
+		"http://api.localhost/hook":          false,
+		"http://127.0.0.1/hook":              false,
+		"http://169.254.169.254/latest":      false,
+		"http://[::1]/hook":                  false,
+	} {
+		assert.Equal(t, ok, ValidateURL(raw, false) == nil, raw)
+	}
+	assert.NoError(t, ValidateURL("http://127.0.0.1/hook", true))
+}
+
+func TestDispatcher_BlocksPrivateAddresses(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer srv.Close()
+
+	outbox := &memoryOutbox{url: srv.URL, secret: "whsec_test"}
+	cfg := testConfig()
+	cfg.AllowPrivateNetworks = false
+	d := NewDispatcher(outbox, cfg)
+	require.NoError(t, d.HandleEvent(context.Background(), events.New(events.TypeLevelUp, "family-1", "child-1", nil)))
+
+	_, err := d.ProcessDue(context.Background())
+	require.NoError(t, err)
+
+	assert.Zero(t, calls.Load(), "the loopback receiver is never contacted")
+	require.NotNil(t, outbox.deliveries[0].LastError)
+	assert.Contains(t, *outbox.deliveries[0].LastError, ErrBlockedAddress.Error())
+}
+
+func TestDispatcher_DoesNotFollowRedirects(t *testing.T) {
+	var redirected atomic.Bool
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirected.Store(true)
+	}))
+	defer target.Close()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusTemporaryRedirect)
+	}))
+	defer srv.Close()
+
+	outbox := &memoryOutbox{url: srv.URL, secret: "whsec_test"}
+	d := NewDispatcher(outbox, testConfig())
+	require.NoError(t, d.HandleEvent(context.Background(), events.New(events.TypeLevelUp, "family-1", "child-1", nil)))
+
+	_, err := d.ProcessDue(context.Background())
+	require.NoError(t, err)
+
+	assert.False(t, redirected.Load())
+	assert.Equal(t, models.WebhookDeliveryPending, outbox.deliveries[0].Status)
+	require.NotNil(t, outbox.deliveries[0].ResponseStatus)
+	assert.Equal(t, http.StatusTemporaryRedirect, *outbox.deliveries[0].ResponseStatus)
+}
@@ -0,0 +1,97 @@
+// Package webhook delivers family domain events to parent-registered URLs.
+//
+// Events are written to a persistent outbox (webhook_deliveries) when published and
+// sent by a Dispatcher that retries failed deliveries with exponential backoff.
+// Every request is signed with the webhook's shared secret so receivers can verify it.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HTTP headers sent with every delivery
+const (
+	HeaderEvent     = "X-Diktator-Event"
+	HeaderDelivery  = "X-Diktator-Delivery"
+	HeaderSignature = "X-Diktator-Signature"
+)
+
+// SecretPrefix marks webhook signing secrets
+const SecretPrefix = "whsec_"
+
+// Signature verification errors
+var (
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrSignatureExpired = errors.New("webhook signature timestamp outside tolerance")
+)
+
+// GenerateSecret creates a new random signing secret
+func GenerateSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return SecretPrefix + hex.EncodeToString(buf), nil
+}
+
+// Sign returns the signature header value for a payload.
+// Format: "t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>">"
+// Including the timestamp in the MAC lets receivers reject replayed requests.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + ts + ",v1=" + computeMAC(secret, ts, body)
+}
+
+// Verify checks a signature header against the payload.
+// A zero tolerance disables the timestamp check.
+func Verify(secret, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	var ts, sig string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			ts = value
+		case "v1":
+			sig = value
+		}
+	}
+	if ts == "" || sig == "" {
+		return ErrInvalidSignature
+	}
+
+	if !hmac.Equal([]byte(sig), []byte(computeMAC(secret, ts, body))) {
+		return ErrInvalidSignature
+	}
+
+	if tolerance > 0 {
+		unix, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil {
+			return ErrInvalidSignature
+		}
+		if math.Abs(now.Sub(time.Unix(unix, 0)).Seconds()) > tolerance.Seconds() {
+			return ErrSignatureExpired
+		}
+	}
+
+	return nil
+}
+
+func computeMAC(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -1,6 +1,7 @@
 package xp
 
 import (
+	"context"
 	"math"
 	"time"
 
@@ -197,17 +198,17 @@ func GetLevelInfo(level int) Level {
 // Repository defines database operations needed for XP calculations
 type Repository interface {
 	// GetRecentCompletions returns how many times a user has completed a word set + mode
-	// combination in the specified time window
-	GetRecentCompletions(userID, wordSetID, mode string, since time.Time) (int, error)
+	// combination in the time window [since, until)
+	GetRecentCompletions(ctx context.Context, userID, wordSetID, mode string, since, until time.Time) (int, error)
 
 	// IsFirstCompletion checks if this is the user's first completion of this word set + mode
-	IsFirstCompletion(userID, wordSetID, mode string) (bool, error)
+	IsFirstCompletion(ctx context.Context, userID, wordSetID, mode string) (bool, error)
 
 	// UpdateUserXP updates the user's total XP and level
-	UpdateUserXP(userID string, xpAwarded, newTotalXP, newLevel int) error
+	UpdateUserXP(ctx context.Context, userID string, xpAwarded, newTotalXP, newLevel int) error
 
 	// GetUserXP returns the user's current XP and level
-	GetUserXP(userID string) (totalXP int, level int, err error)
+	GetUserXP(ctx context.Context, userID string) (totalXP int, level int, err error)
 }
 
 // Service handles XP calculations and updates
@@ -220,17 +221,29 @@ func NewService(repo Repository) *Service {
 	return &Service{repo: repo}
 }
 
+// WithRepository returns a service that reads and writes through repo, such as
+// the transaction passed to a db.Repository.WithTx callback
+func (s *Service) WithRepository(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
 // AwardXP calculates and awards XP for a completed test
-func (s *Service) AwardXP(userID string, result *models.TestResult) (*XPResult, error) {
+func (s *Service) AwardXP(ctx context.Context, userID string, result *models.TestResult) (*XPResult, error) {
 	// Check if this is a first-time completion for this word set + mode
-	isFirstTime, err := s.repo.IsFirstCompletion(userID, result.WordSetID, result.Mode)
+	isFirstTime, err := s.repo.IsFirstCompletion(ctx, userID, result.WordSetID, result.Mode)
 	if err != nil {
 		return nil, err
 	}
 
-	// Get recent completions for decay calculation (within 7 days)
-	sevenDaysAgo := time.Now().AddDate(0, 0, -7)
-	completionCount, err := s.repo.GetRecentCompletions(userID, result.WordSetID, result.Mode, sevenDaysAgo)
+	// Get completions in the 7 days before this test for decay calculation. The window
+	// ends at the test's own completion time so results synced late from an offline
+	// client decay as they would have when taken.
+	completedAt := result.CompletedAt
+	if completedAt.IsZero() {
+		completedAt = time.Now()
+	}
+	sevenDaysBefore := completedAt.AddDate(0, 0, -7)
+	completionCount, err := s.repo.GetRecentCompletions(ctx, userID, result.WordSetID, result.Mode, sevenDaysBefore, completedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -239,7 +252,7 @@ func (s *Service) AwardXP(userID string, result *models.TestResult) (*XPResult,
 	awarded, xpResult := CalculateXP(result.Mode, result.Score, isFirstTime, completionCount)
 
 	// Get current user XP
-	currentXP, currentLevel, err := s.repo.GetUserXP(userID)
+	currentXP, currentLevel, err := s.repo.GetUserXP(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -249,7 +262,7 @@ func (s *Service) AwardXP(userID string, result *models.TestResult) (*XPResult,
 	newLevel := GetLevelNumber(newTotalXP)
 
 	// Update user XP in database
-	err = s.repo.UpdateUserXP(userID, awarded, newTotalXP, newLevel)
+	err = s.repo.UpdateUserXP(ctx, userID, awarded, newTotalXP, newLevel)
 	if err != nil {
 		return nil, err
 	}
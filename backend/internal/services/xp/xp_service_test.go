@@ -0,0 +1,66 @@
+package xp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/starefossen/diktator/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct {
+	completions []time.Time
+	totalXP     int
+}
+
+func (r *fakeRepo) GetRecentCompletions(_ context.Context, _, _, _ string, since, until time.Time) (int, error) {
+	count := 0
+	for _, at := range r.completions {
+		if !at.Before(since) && at.Before(until) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *fakeRepo) IsFirstCompletion(_ context.Context, _, _, _ string) (bool, error) {
+	return len(r.completions) == 0, nil
+}
+
+func (r *fakeRepo) UpdateUserXP(_ context.Context, _ string, _, newTotalXP, _ int) error {
+	r.totalXP = newTotalXP
+	return nil
+}
+
+func (r *fakeRepo) GetUserXP(_ context.Context, _ string) (int, int, error) {
+	return r.totalXP, GetLevelNumber(r.totalXP), nil
+}
+
+func TestAwardXP_DecayWindowEndsAtCompletion(t *testing.T) {
+	now := time.Now()
+	repo := &fakeRepo{}
+	svc := NewService(repo)
+
+	award := func(completedAt time.Time) *XPResult {
+		t.Helper()
+		result := &models.TestResult{WordSetID: "ws-1", Mode: "keyboard", Score: 80, CompletedAt: completedAt}
+		xpResult, err := svc.AwardXP(t.Context(), "child-1", result)
+		require.NoError(t, err)
+		repo.completions = append(repo.completions, completedAt)
+		return xpResult
+	}
+
+	// Tests taken online today
+	award(now.Add(-2 * time.Hour))
+	award(now.Add(-time.Hour))
+
+	// A test taken offline ten days ago is not decayed by today's practice
+	late := award(now.AddDate(0, 0, -10))
+	assert.Equal(t, 1.0, late.RepetitionDecay)
+
+	// The next test today sees both of today's earlier runs
+	next := award(now)
+	assert.Equal(t, RepetitionDecay(2), next.RepetitionDecay)
+}
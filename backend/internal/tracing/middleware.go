@@ -0,0 +1,63 @@
+package tracing
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceIDHeader is the response header carrying the request's trace ID
+const TraceIDHeader = "X-Trace-Id"
+
+// TraceIDKey is the gin context key holding the request's trace ID
+const TraceIDKey = "traceID"
+
+// Middleware starts a server span for each request, continuing a trace from the
+// caller's traceparent header. The span is named after the route pattern, and
+// the trace ID is returned in the X-Trace-Id header and stored under TraceIDKey.
+// Call it after Setup so it uses the configured provider.
+func Middleware() gin.HandlerFunc {
+	tracer := otel.Tracer("github.com/starefossen/diktator/backend/internal/tracing")
+	return func(c *gin.Context) {
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+
+		route := c.FullPath()
+		name := c.Request.Method
+		if route != "" {
+			name += " " + route
+		}
+		ctx, span := tracer.Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(c.Request.Method),
+				semconv.URLPath(c.Request.URL.Path),
+			),
+		)
+		defer span.End()
+
+		if route != "" {
+			span.SetAttributes(semconv.HTTPRoute(route))
+		}
+		if traceID := TraceID(ctx); traceID != "" {
+			c.Header(TraceIDHeader, traceID)
+			c.Set(TraceIDKey, traceID)
+		}
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+		if len(c.Errors) > 0 {
+			span.RecordError(c.Errors.Last())
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exportedSpan is the subset of the stdout exporter's JSON the tests inspect
+type exportedSpan struct {
+	Name        string
+	SpanContext struct {
+		TraceID string
+	}
+	Parent struct {
+		TraceID string
+	}
+	Attributes []struct {
+		Key   string
+		Value struct {
+			Value any
+		}
+	}
+}
+
+// setupStdout installs a provider exporting to a buffer and returns a function
+// decoding the spans written so far
+func setupStdout(t *testing.T) func() []exportedSpan {
+	t.Helper()
+	var buf bytes.Buffer
+	shutdown, err := Setup(t.Context(), Config{Exporter: ExporterStdout, ServiceName: "diktator-test", Writer: &buf})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = shutdown(t.Context()) })
+
+	return func() []exportedSpan {
+		var spans []exportedSpan
+		dec := json.NewDecoder(bytes.NewReader(buf.Bytes()))
+		for dec.More() {
+			var span exportedSpan
+			require.NoError(t, dec.Decode(&span))
+			spans = append(spans, span)
+		}
+		return spans
+	}
+}
+
+func (s exportedSpan) attribute(key string) any {
+	for _, a := range s.Attributes {
+		if a.Key == key {
+			return a.Value.Value
+		}
+	}
+	return nil
+}
+
+func newRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Middleware())
+	r.GET("/api/wordsets/:id", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	return r
+}
+
+func TestMiddleware_ExportsServerSpan(t *testing.T) {
+	spans := setupStdout(t)
+
+	w := httptest.NewRecorder()
+	newRouter().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/wordsets/abc", nil))
+
+	exported := spans()
+	require.Len(t, exported, 1)
+	span := exported[0]
+	assert.Equal(t, "GET /api/wordsets/:id", span.Name)
+	assert.Equal(t, "/api/wordsets/:id", span.attribute("http.route"))
+	assert.EqualValues(t, http.StatusNoContent, span.attribute("http.response.status_code"))
+	assert.Equal(t, span.SpanContext.TraceID, w.Header().Get(TraceIDHeader))
+}
+
+func TestMiddleware_ContinuesIncomingTrace(t *testing.T) {
+	spans := setupStdout(t)
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+	req := httptest.NewRequest(http.MethodGet, "/api/wordsets/abc", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	w := httptest.NewRecorder()
+	newRouter().ServeHTTP(w, req)
+
+	assert.Equal(t, traceID, w.Header().Get(TraceIDHeader))
+	exported := spans()
+	require.Len(t, exported, 1)
+	assert.Equal(t, traceID, exported[0].SpanContext.TraceID)
+	assert.Equal(t, traceID, exported[0].Parent.TraceID)
+}
+
+func TestSetup_NoExporterStillAssignsTraceIDs(t *testing.T) {
+	shutdown, err := Setup(t.Context(), Config{Exporter: ExporterNone})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = shutdown(t.Context()) })
+
+	w := httptest.NewRecorder()
+	newRouter().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/wordsets/abc", nil))
+	assert.Len(t, w.Header().Get(TraceIDHeader), 32)
+}
+
+func TestSetup_RejectsUnknownExporter(t *testing.T) {
+	_, err := Setup(t.Context(), Config{Exporter: "zipkin"})
+	assert.Error(t, err)
+}
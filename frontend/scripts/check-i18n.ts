@@ -45,6 +45,7 @@ const KNOWN_DYNAMIC_PREFIXES = [
   "stavle.companion.", // Used with selectRandomVariant() for .1, .2, .3 suffixes
   "aria.stavle.", // ARIA labels for Stavle poses
   "mastery.difficulty.", // Used dynamically in CuratedWordSetCard for difficulty badges
+  "notifications.", // Message keys come from the backend notification inbox
 ];
 
 // Keys that are intentionally defined but may not be directly used in code
@@ -0,0 +1,12 @@
+export const notifications = {
+  // In-app notifications. Keys and {params} are sent by the backend
+  "notifications.test.completed":
+    '{childName} finished "{wordSetName}" with {score}%',
+  "notifications.wordset.assigned": 'New word set to practice: "{wordSetName}"',
+  "notifications.xp.levelUp": "You reached level {level}: {levelName}!",
+  "notifications.xp.childLevelUp":
+    "{childName} reached level {level}: {levelName}",
+  "notifications.invitation.accepted": "{memberName} joined the family",
+  "notifications.reminder.practice":
+    'Time for dictation! "{wordSetName}" is waiting for you',
+};
@@ -8,6 +8,7 @@ import { test } from "./test";
 import { aria } from "./aria";
 import { mastery } from "./mastery";
 import { xp } from "./xp";
+import { notifications } from "./notifications";
 
 export const no = {
   ...auth,
@@ -20,6 +21,7 @@ export const no = {
   ...aria,
   ...mastery,
   ...xp,
+  ...notifications,
 
   // Home page
   "home.welcome": "Velkommen til Diktator!",
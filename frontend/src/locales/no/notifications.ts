@@ -0,0 +1,12 @@
+export const notifications = {
+  // In-app notifications. Keys and {params} are sent by the backend
+  "notifications.test.completed":
+    "{childName} fullførte «{wordSetName}» med {score} %",
+  "notifications.wordset.assigned": "Nytt ordsett å øve på: «{wordSetName}»",
+  "notifications.xp.levelUp": "Du nådde nivå {level}: {levelNameNo}!",
+  "notifications.xp.childLevelUp":
+    "{childName} nådde nivå {level}: {levelNameNo}",
+  "notifications.invitation.accepted": "{memberName} ble med i familien",
+  "notifications.reminder.practice":
+    "Tid for diktat! «{wordSetName}» venter på deg",
+};